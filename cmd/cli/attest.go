@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleKeygen(args []string) {
+	keygenCmd := flag.NewFlagSet("keygen", flag.ExitOnError)
+	outPath := keygenCmd.String("out", "", "Output path prefix for the key pair (writes <out>.key and <out>.pub)")
+	holder := keygenCmd.Bool("holder", false, "Generate a holder secret for proof nullifiers instead (writes <out>.secret)")
+	prover := keygenCmd.Bool("prover", false, "Generate an X25519 prover daemon key pair for delegated proving instead (writes <out>.key and <out>.pub)")
+	srsSize := keygenCmd.Int("srs", 0, "Generate a development KZG SRS with this many points for -backend plonk instead (writes <out>.srs)")
+	curveName := keygenCmd.String("curve", "bn254", "Curve of the -srs SRS (bn254, bls12-381, bw6-761)")
+	apiKey := keygenCmd.Bool("api-key", false, "Generate an API key for a serve -tenants tenant instead (writes <out>.apikey and prints the hash to list in the tenants file)")
+
+	keygenCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s keygen [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Generate an Ed25519 signing key pair, a prover key pair, a holder secret, a server API key or a development KZG SRS\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		keygenCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s keygen -out keys/lab\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s keygen -holder -out keys/me\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s keygen -prover -out keys/prover\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s keygen -srs 1048576 -out keys/bn254\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s keygen -api-key -out keys/acme\n", os.Args[0])
+	}
+
+	keygenCmd.Parse(args)
+
+	if *outPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -out is required\n\n")
+		keygenCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	if *holder {
+		if err := proofs.GenerateHolderSecret(*outPath); err != nil {
+			fmt.Printf("Error generating holder secret: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Holder secret saved to: %s%s (keep it private)\n", *outPath, proofs.HolderSecretSuffix)
+		return
+	}
+
+	if *apiKey {
+		hash, err := proofs.GenerateAPIKey(*outPath)
+		if err != nil {
+			fmt.Printf("Error generating API key: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("API key saved to: %s%s (give it to the tenant)\n", *outPath, proofs.APIKeySuffix)
+		fmt.Printf("API key hash for the tenants file: %s\n", hash)
+		return
+	}
+
+	if *prover {
+		if err := proofs.GenerateProverKey(*outPath); err != nil {
+			fmt.Printf("Error generating prover key: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Prover keys saved to: %s.key (keep it on the prover) and %s.pub\n", *outPath, *outPath)
+		return
+	}
+
+	if *srsSize > 0 {
+		curve, err := proofs.ParseCurve(*curveName)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		if err := proofs.GenerateSRS(*outPath+".srs", curve, uint64(*srsSize)); err != nil {
+			fmt.Printf("Error generating SRS: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("SRS saved to: %s.srs (single-party setup; use a ceremony SRS in production)\n", *outPath)
+		return
+	}
+
+	if err := proofs.GenerateSigningKey(*outPath); err != nil {
+		fmt.Printf("Error generating key: %v\n", err)
+		exit(1)
+	}
+
+	fmt.Printf("Keys saved to: %s.key and %s.pub\n", *outPath, *outPath)
+}
+
+func handleAttest(args []string) {
+	attestCmd := flag.NewFlagSet("attest", flag.ExitOnError)
+	vcfPath := attestCmd.String("vcf", "", "Path to the VCF being attested")
+	keyPath := attestCmd.String("key", "", "Lab Ed25519 private key (PEM)")
+	labID := attestCmd.String("lab-id", "", "Identifier of the sequencing lab")
+	platform := attestCmd.String("platform", "", "Sequencing platform (e.g. NovaSeq 6000)")
+	sequenced := attestCmd.String("sequenced", "", "Sequencing date (YYYY-MM-DD)")
+	pipeline := attestCmd.String("pipeline", "", "Analysis pipeline version")
+	outPath := attestCmd.String("out", "", "Output path for the attestation (default: <vcf>.attestation.json)")
+
+	attestCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s attest [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Sign a sequencing provenance statement over a VCF\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		attestCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s attest -vcf data/genome.vcf -key keys/lab.key -lab-id LAB-042 -platform NovaSeq -sequenced 2025-03-01 -pipeline dragen-4.2\n", os.Args[0])
+	}
+
+	attestCmd.Parse(args)
+
+	if *vcfPath == "" || *keyPath == "" || *labID == "" || *sequenced == "" {
+		fmt.Fprintf(os.Stderr, "Error: -vcf, -key, -lab-id and -sequenced are required\n\n")
+		attestCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	sequencedAt, err := time.Parse(time.DateOnly, *sequenced)
+	if err != nil {
+		fmt.Printf("Error: invalid -sequenced date: %v\n", err)
+		exit(1)
+	}
+
+	key, err := proofs.ReadSigningKey(*keyPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	digest, err := proofs.GenomeDigest(*vcfPath)
+	if err != nil {
+		fmt.Printf("Error hashing VCF: %v\n", err)
+		exit(1)
+	}
+
+	attestation, err := proofs.SignProvenance(proofs.ProvenanceStatement{
+		LabID:           *labID,
+		Platform:        *platform,
+		SequencedAt:     sequencedAt,
+		PipelineVersion: *pipeline,
+		GenomeDigest:    digest,
+	}, key)
+	if err != nil {
+		fmt.Printf("Error signing attestation: %v\n", err)
+		exit(1)
+	}
+
+	if *outPath == "" {
+		*outPath = *vcfPath + ".attestation.json"
+	}
+	if err := proofs.WriteProvenance(*outPath, attestation); err != nil {
+		fmt.Printf("Error writing attestation: %v\n", err)
+		exit(1)
+	}
+
+	fmt.Printf("Attestation for genome %s saved to: %s\n", digest, *outPath)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// verifyBatch verifies every proof in dir, optionally only those of
+// proofType, against one verifying key and prints a per-file report. Only
+// the proofs themselves and their expiry are checked; per-type options such
+// as -commitment or -nonce need a single-proof verify.
+func verifyBatch(dir string, proofType string, verifyingKeyPath string, ignoreExpiry bool) {
+	files, err := proofs.ProofFilesInDir(dir, proofType)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	if len(files) == 0 {
+		fmt.Printf("Error: no proofs found in %s\n", dir)
+		exit(1)
+	}
+
+	fmt.Printf("Verifying %d proofs in %s...\n", len(files), dir)
+	fmt.Printf("Verifying key: %s\n", verifyingKeyPath)
+
+	errs, err := proofs.VerifyBatch(verifyingKeyPath, files)
+	if err != nil {
+		fmt.Printf("Error verifying proofs: %v\n", err)
+		exit(1)
+	}
+	if !ignoreExpiry {
+		now := time.Now()
+		for i, file := range files {
+			if errs[i] == nil {
+				errs[i] = proofs.CheckExpiry(file.Path, now)
+			}
+		}
+	}
+
+	failed := 0
+	for i, file := range files {
+		reportVerification(proofType, file.Path, verifyingKeyPath, errs[i] == nil, errs[i])
+		if errs[i] != nil {
+			failed++
+			fmt.Printf("FAIL %s: %v\n", file.Path, errs[i])
+			continue
+		}
+		fmt.Printf("OK   %s\n", file.Path)
+	}
+
+	if failed > 0 {
+		fmt.Printf("✗ %d of %d proofs failed verification\n", failed, len(files))
+		exit(batchExitCode(errs))
+	}
+	fmt.Printf("✅ All %d proofs successfully verified!\n", len(files))
+}
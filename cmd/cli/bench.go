@@ -0,0 +1,330 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"text/tabwriter"
+	"time"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// benchReport is what bench measured of one proof type.
+type benchReport struct {
+	Type              string `json:"type"`
+	Backend           string `json:"backend,omitempty"`
+	Curve             string `json:"curve,omitempty"`
+	Constraints       int    `json:"constraints,omitempty"`
+	ProvingKeyBytes   int64  `json:"proving_key_bytes,omitempty"`
+	VerifyingKeyBytes int64  `json:"verifying_key_bytes,omitempty"`
+	ProofBytes        int64  `json:"proof_bytes,omitempty"`
+	CompileMS         int64  `json:"compile_ms,omitempty"`
+	SetupMS           int64  `json:"setup_ms,omitempty"`
+	ProveMS           int64  `json:"prove_ms,omitempty"`
+	VerifyMS          int64  `json:"verify_ms,omitempty"`
+	PeakHeapBytes     uint64 `json:"peak_heap_bytes,omitempty"`
+	Error             string `json:"error,omitempty"`
+}
+
+func handleBench(args []string) {
+	benchCmd := flag.NewFlagSet("bench", flag.ExitOnError)
+	proofType := benchCmd.String("type", "", "Comma-separated proof types to benchmark (default: every type with a circuit of its own)")
+	vcfPath := benchCmd.String("vcf", "", "VCF to prove and verify, to also time proving and verification (optional)")
+	outDir := benchCmd.String("out", "", "Directory to keep the keys and proofs in (default: a temporary directory, removed afterwards)")
+	asJSON := benchCmd.Bool("json", false, "Print the results as JSON")
+	gene := benchCmd.String("gene", "CYP2C19", "Gene for diplotype and compoundhet proofs, and for the -panel entries of dosage, threshold and panel proofs")
+	snps := benchCmd.String("snps", "", "Comma-separated chrom:pos:ref:alt SNPs for dosage, threshold and panel proofs (default: the -panel entries)")
+	panelPath := benchCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel to take dosage, threshold and panel proof SNPs from")
+	position := benchCmd.String("position", "", "Locus as chrom:pos for zygosity and wildtype proofs proved over -vcf")
+	variant := benchCmd.String("variant", "", "Variant as chrom:pos:ref:alt for proofs proved over -vcf that take one")
+	secondVariant := benchCmd.String("second-variant", "", "Second variant for haplotype proofs proved over -vcf")
+	region := benchCmd.String("region", "", "Region as chrom:start-end for region and sv proofs proved over -vcf")
+	target := benchCmd.Int("target", proofs.DefaultTargetChromosome, "Chromosome a chromosome proof over -vcf shows is present")
+	maxVariants := benchCmd.Int("max-variants", proofs.DefaultMaxVariants, "Number of VCF records the chromosome circuit holds")
+	treeDepth := benchCmd.Int("tree-depth", proofs.DefaultTreeDepth, "Depth of the variant Merkle tree for membership, absence, region, haplotype and panel proofs")
+	hashName := benchCmd.String("hash", "mimc", "Variant tree hash for membership, absence, region and panel proofs (mimc, poseidon2)")
+	backendName := benchCmd.String("backend", envDefault(envBackend, "groth16"), "Proof system (groth16, plonk)")
+	curveName := benchCmd.String("curve", "bn254", "Curve to benchmark over (bn254, bls12-381, bls12-377, bw6-761)")
+	srsPath := benchCmd.String("srs", "", "KZG SRS file for -backend plonk (default: sample a development SRS)")
+	acceleratorName := benchCmd.String("accelerator", "cpu", "Hardware to compute groth16 proofs over bn254 on (cpu, gpu)")
+
+	benchCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s bench [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Compile and set up each proof type's circuit on this machine and report its constraint\n")
+		fmt.Fprintf(os.Stderr, "count, key sizes, compile and setup time and peak heap. With -vcf each proof is also\n")
+		fmt.Fprintf(os.Stderr, "generated and verified to time proving and verification; proofs the VCF does not\n")
+		fmt.Fprintf(os.Stderr, "support report why instead.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		benchCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s bench\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s bench -type zygosity,eyecolor -vcf data/genome.vcf -position 15:28365618\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s bench -type membership,region -backend plonk -curve bls12-381 -json\n", os.Args[0])
+	}
+
+	benchCmd.Parse(args)
+
+	settings := proofs.Settings{}
+	var err error
+	if settings.Hash, err = proofs.ParseHashAlgorithm(*hashName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+	if backend, err := proofs.ParseBackend(*backendName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	} else if backend != proofs.BackendGroth16 {
+		settings.Backend = backend
+		settings.SRS = *srsPath
+	} else if *srsPath != "" {
+		fmt.Printf("Error: -srs requires -backend plonk\n")
+		exit(exitBadInput)
+	}
+	if curve, err := proofs.ParseCurve(*curveName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	} else if curve != proofs.CurveBN254 {
+		settings.Curve = curve
+	}
+	if settings.Accelerator, err = proofs.ParseAccelerator(*acceleratorName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+
+	var types []string
+	if *proofType != "" {
+		types = splitList(*proofType)
+	} else {
+		for _, t := range proofs.ProofTypes() {
+			types = append(types, t.Name)
+		}
+	}
+
+	cfg := proofs.ProofConfig{
+		Settings:      settings,
+		MaxVariants:   *maxVariants,
+		Target:        *target,
+		TreeDepth:     *treeDepth,
+		Region:        *region,
+		Locus:         "HTT",
+		K:             1,
+		Gene:          *gene,
+		Variant:       *variant,
+		Position:      *position,
+		SecondVariant: *secondVariant,
+	}
+	var panelErr error
+	if hasType(types, "dosage", "threshold", "panel") {
+		cfg.Panel, panelErr = snpPanel(*snps, *panelPath, *gene)
+	}
+
+	dir := *outDir
+	if dir == "" {
+		if dir, err = os.MkdirTemp("", "bench-"); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		defer os.RemoveAll(dir)
+	} else if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Printf("Error creating output directory: %v\n", err)
+		exit(1)
+	}
+
+	var reports []benchReport
+	for _, t := range types {
+		proof, err := proofs.NewProof(t, cfg)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		if panelErr != nil && hasType([]string{t}, "dosage", "threshold", "panel") {
+			reports = append(reports, benchReport{Type: t, Error: panelErr.Error()})
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Benchmarking %s...\n", t)
+		result, err := proofs.Benchmark(cmdContext, proof, *vcfPath, dir)
+		if err != nil {
+			// Types aggregating other proofs have no circuit of their own
+			// to benchmark unless asked for by name.
+			if *proofType == "" && errors.Is(err, proofs.ErrNoCircuit) {
+				continue
+			}
+			reports = append(reports, benchReport{Type: t, Error: err.Error()})
+			continue
+		}
+		reports = append(reports, benchReport{
+			Type:              result.Type,
+			Backend:           string(result.Backend),
+			Curve:             string(result.Curve),
+			Constraints:       result.Constraints,
+			ProvingKeyBytes:   result.ProvingKeySize,
+			VerifyingKeyBytes: result.VerifyingKeySize,
+			ProofBytes:        result.ProofSize,
+			CompileMS:         result.CompileTime.Milliseconds(),
+			SetupMS:           result.SetupTime.Milliseconds(),
+			ProveMS:           result.ProveTime.Milliseconds(),
+			VerifyMS:          result.VerifyTime.Milliseconds(),
+			PeakHeapBytes:     result.PeakHeap,
+			Error:             result.ProveError,
+		})
+	}
+
+	switch {
+	case jsonMode:
+		jsonReport.Benchmarks = reports
+	case *asJSON:
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Println(string(data))
+	default:
+		printBenchTable(reports, *vcfPath != "")
+	}
+}
+
+// printBenchTable prints reports as a table; the proving columns only when
+// a VCF was proved.
+func printBenchTable(reports []benchReport, proved bool) {
+	fmt.Printf("%s/%s, %d CPUs\n\n", runtime.GOOS, runtime.GOARCH, runtime.NumCPU())
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "TYPE\tCONSTRAINTS\tPK\tVK\tCOMPILE\tSETUP\t"
+	if proved {
+		header += "PROOF\tPROVE\tVERIFY\t"
+	}
+	fmt.Fprintln(w, header+"PEAK HEAP")
+
+	var errs []string
+	for _, r := range reports {
+		if r.Constraints == 0 {
+			errs = append(errs, fmt.Sprintf("%s: %s", r.Type, r.Error))
+			continue
+		}
+		row := fmt.Sprintf("%s\t%d\t%s\t%s\t%s\t%s\t", r.Type, r.Constraints, byteSize(r.ProvingKeyBytes), byteSize(r.VerifyingKeyBytes), millis(r.CompileMS), millis(r.SetupMS))
+		if proved {
+			if r.Error != "" {
+				row += "-\t-\t-\t"
+				errs = append(errs, fmt.Sprintf("%s: not proved: %s", r.Type, r.Error))
+			} else {
+				row += fmt.Sprintf("%s\t%s\t%s\t", byteSize(r.ProofBytes), millis(r.ProveMS), millis(r.VerifyMS))
+			}
+		}
+		fmt.Fprintln(w, row+byteSize(int64(r.PeakHeapBytes)))
+	}
+	w.Flush()
+
+	if len(errs) > 0 {
+		fmt.Println()
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+	}
+}
+
+func millis(ms int64) string {
+	if ms == 0 {
+		return "<1ms"
+	}
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// byteSize formats n bytes with a binary unit.
+func byteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// estimateReport is what generate -dry-run expects a proof to take.
+type estimateReport struct {
+	Type            string `json:"type"`
+	Backend         string `json:"backend,omitempty"`
+	Curve           string `json:"curve,omitempty"`
+	Constraints     int    `json:"constraints,omitempty"`
+	PublicInputs    int    `json:"public_inputs,omitempty"`
+	SecretInputs    int    `json:"secret_inputs,omitempty"`
+	ProvingKeyBytes int64  `json:"proving_key_bytes,omitempty"`
+	MemoryBytes     uint64 `json:"memory_bytes,omitempty"`
+	SetupMS         int64  `json:"setup_ms,omitempty"`
+	ProveMS         int64  `json:"prove_ms,omitempty"`
+	CPUs            int    `json:"cpus,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// estimateProofs prints what generating each of types with cfg is expected
+// to take, for generate -dry-run.
+func estimateProofs(types []string, cfg proofs.ProofConfig, provingKeyPath string) {
+	var errs []error
+	for i, t := range types {
+		if i > 0 {
+			fmt.Println()
+		}
+		proof, err := proofs.NewProof(t, cfg)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		e, err := proofs.EstimateResources(proof)
+		if err != nil {
+			fmt.Printf("Error estimating %s proof: %v\n", t, err)
+			jsonReport.Estimates = append(jsonReport.Estimates, estimateReport{Type: t, Error: err.Error()})
+			errs = append(errs, err)
+			continue
+		}
+		jsonReport.Estimates = append(jsonReport.Estimates, estimateReport{
+			Type:            e.Type,
+			Backend:         string(e.Backend),
+			Curve:           string(e.Curve),
+			Constraints:     e.Constraints,
+			PublicInputs:    e.PublicInputs,
+			SecretInputs:    e.SecretInputs,
+			ProvingKeyBytes: e.ProvingKeySize,
+			MemoryBytes:     e.Memory,
+			SetupMS:         e.SetupTime.Milliseconds(),
+			ProveMS:         e.ProveTime.Milliseconds(),
+			CPUs:            e.CPUs,
+		})
+
+		cpus := fmt.Sprintf("%d CPUs", e.CPUs)
+		if e.CPUs == 1 {
+			cpus = "1 CPU"
+		}
+		fmt.Printf("Dry run of %s proof (%s over %s); nothing was set up or proved\n", e.Type, e.Backend, e.Curve)
+		fmt.Printf("  Constraints:   %d (%d public, %d secret inputs)\n", e.Constraints, e.PublicInputs, e.SecretInputs)
+		fmt.Printf("  Proving key:   about %s\n", byteSize(e.ProvingKeySize))
+		fmt.Printf("  Peak memory:   about %s\n", byteSize(int64(e.Memory)))
+		if provingKeyPath != "" {
+			fmt.Printf("  Setup time:    none, -proving-key is given\n")
+		} else {
+			fmt.Printf("  Setup time:    about %s on %s\n", estimateDuration(e.SetupTime), cpus)
+		}
+		fmt.Printf("  Proving time:  about %s on %s\n", estimateDuration(e.ProveTime), cpus)
+	}
+	if len(errs) > 0 {
+		exit(batchExitCode(errs))
+	}
+}
+
+// estimateDuration rounds an estimated duration to what it can tell.
+func estimateDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return "<1ms"
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	default:
+		return d.Round(100 * time.Millisecond).String()
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleCheck(args []string) {
+	checkCmd := flag.NewFlagSet("check", flag.ExitOnError)
+	vcfPath := checkCmd.String("vcf", "", "Path to VCF file, plain or compressed with gzip or bgzip, or - to read it from stdin")
+	panelPath := checkCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel whose variants to check")
+	gene := checkCmd.String("gene", "", "Only check this gene's trait panel variants")
+	region := checkCmd.String("region", "", "Only check the traits in, and only read the VCF records overlapping, this chrom:start-end region")
+	indexPath := checkCmd.String("index", "", "Tabix (.tbi) or CSI (.csi) index of a bgzip-compressed -vcf (default: <vcf>.tbi or <vcf>.csi if present, else scan)")
+	liftoverPath := checkCmd.String("liftover", "", "UCSC chain file lifting GRCh37 panel positions onto the -vcf's build")
+	imputationMode := checkCmd.String("imputation", "allow", "How to treat imputed genotype calls (allow, refuse, threshold)")
+	minImputedProb := checkCmd.Float64("min-imputed-prob", 0.9, "Minimum genotype probability for imputed calls when -imputation=threshold")
+	missingPolicy := checkCmd.String("missing", "fail", "Missing genotype policy proofs would be generated under (fail, treat-as-missing, require-coverage)")
+	requirePass := checkCmd.Bool("require-pass", false, "Only use records whose FILTER is PASS")
+	filterMinDepth := checkCmd.Int("filter-min-dp", 0, "Only use records with at least this FORMAT/DP (or INFO/DP) (0 for no minimum)")
+	filterMinAF := checkCmd.Float64("filter-min-af", 0, "Only use calls whose carried ALTs have at least this FORMAT/AF (or INFO/AF) (0 for no minimum)")
+	foundOnly := checkCmd.Bool("found-only", false, "Only list the traits whose variant the VCF holds")
+
+	checkCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s check [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Report, for each trait panel variant, whether the VCF holds it, the genotype\n")
+		fmt.Fprintf(os.Stderr, "called there and whether a proof over it could be generated. Genotypes are\n")
+		fmt.Fprintf(os.Stderr, "only printed, never written to a proof.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		checkCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s check -vcf data/genome.vcf\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s check -vcf data/genome.vcf.gz -gene HERC2 -found-only\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --json check -vcf data/genome.vcf -region 15:28000000-29000000\n", os.Args[0])
+	}
+
+	checkCmd.Parse(args)
+
+	if *vcfPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -vcf is required\n\n")
+		checkCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	mode, err := proofs.ParseImputationMode(*imputationMode)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+	missing, err := proofs.ParseMissingPolicy(*missingPolicy)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+	settings := proofs.Settings{
+		Imputation: proofs.ImputationPolicy{Mode: mode},
+		Missing:    missing,
+		Filter:     proofs.CallFilter{RequirePass: *requirePass, MinDepth: *filterMinDepth, MinAlleleFrequency: *filterMinAF},
+		Index:      *indexPath,
+	}
+	if mode == proofs.ImputationThreshold {
+		settings.Imputation.MinProbability = *minImputedProb
+	}
+	if *region != "" {
+		scan, err := proofs.ParseRegion(*region)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		settings.Region = &scan
+	}
+	if *liftoverPath != "" {
+		settings.Liftover, err = proofs.LoadLiftover(*liftoverPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
+
+	panel, err := proofs.LoadTraitPanel(*panelPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+	if *gene != "" {
+		var traits []proofs.TraitVariant
+		for _, t := range panel {
+			if strings.EqualFold(t.Gene, *gene) {
+				traits = append(traits, t)
+			}
+		}
+		if len(traits) == 0 {
+			fmt.Printf("Error: gene %s is not in the trait panel\n", *gene)
+			exit(exitBadInput)
+		}
+		panel = traits
+	}
+
+	vcfName := *vcfPath
+	if *vcfPath == stdinName {
+		if *vcfPath, err = stdinVCF(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitVCF)
+		}
+		vcfName = "stdin"
+	}
+
+	fmt.Printf("Checking %d traits from %s in %s...\n\n", len(panel), *panelPath, vcfName)
+	checks, err := proofs.CheckTraits(*vcfPath, settings, panel)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitCode(err, exitVCF))
+	}
+
+	found, provable := 0, 0
+	for _, c := range checks {
+		if c.Present {
+			found++
+		}
+		if c.Provable {
+			provable++
+		}
+		if jsonMode {
+			jsonReport.Checks = append(jsonReport.Checks, c)
+		}
+		if !c.Present && *foundOnly {
+			continue
+		}
+
+		mark, genotype := "✓", c.Genotype
+		if !c.Present {
+			mark, genotype = "-", "not in VCF"
+		}
+		status := "provable"
+		switch {
+		case !c.Provable:
+			status = "not provable: " + c.Reason
+		case !c.Present:
+			status = "provable as homozygous reference"
+		}
+		fmt.Printf("%s %s (%s) at %s: %s, %s\n", mark, c.Trait, c.Gene, c.Variant, genotype, status)
+	}
+	fmt.Printf("\nFound %d of %d trait variants; %d could be proved\n", found, len(checks), provable)
+}
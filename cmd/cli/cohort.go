@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleAggregate(args []string) {
+	aggregateCmd := flag.NewFlagSet("aggregate", flag.ExitOnError)
+	proofType := aggregateCmd.String("type", "cohort", "Aggregation to prove: cohort (noisy carrier count) or recursive (one proof that every -proofs proof verifies)")
+	variant := aggregateCmd.String("variant", "", "Variant as chrom:pos:ref:alt the carrier proofs commit to")
+	inputProofs := aggregateCmd.String("proofs", "", "Comma-separated participant carrier proof files, or for recursive aggregation groth16 proofs over bls12-377")
+	epsilon := aggregateCmd.Float64("epsilon", 1.0, "Differential privacy budget for the released count")
+	seedPath := aggregateCmd.String("seed", "", "File of the noise seed, committed with -commit-seed before the carrier proofs were collected (cohort)")
+	commitSeed := aggregateCmd.Bool("commit-seed", false, "Draw a new noise seed into -seed and print its commitment, to publish before collecting carrier proofs")
+	outputPath := aggregateCmd.String("output", "", "Output path for the aggregate proof (default: output/<type>_proof.bin)")
+	provingKeyPath := aggregateCmd.String("proving-key", "", "Path to existing proving key (optional)")
+	ccsCacheDir := aggregateCmd.String("ccs-cache-dir", "", "Directory to cache compiled circuits in, so later runs of the same circuit skip compilation (optional)")
+	force := aggregateCmd.Bool("force", false, "Overwrite an existing proof at the output path and the keys set up beside it")
+	keyCacheDir := aggregateCmd.String("key-cache-dir", "", "Directory to cache keys in by circuit hash, so setup runs once per circuit definition (optional)")
+
+	aggregateCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s aggregate [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Prove a differentially private carrier count over participants' carrier proofs,\n")
+		fmt.Fprintf(os.Stderr, "or aggregate proofs into a single recursive proof\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		aggregateCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s aggregate -commit-seed -seed cohort.seed\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s aggregate -variant 7:117199644:ATCT:A -proofs a/carrier_proof.bin,b/carrier_proof.bin -epsilon 0.5 -seed cohort.seed\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s aggregate -type recursive -proofs output/eyecolor_proof.bin,output/brca1_proof.bin\n", os.Args[0])
+	}
+
+	aggregateCmd.Parse(args)
+
+	if *commitSeed {
+		if *seedPath == "" {
+			fmt.Fprintf(os.Stderr, "Error: -commit-seed needs -seed\n\n")
+			aggregateCmd.Usage()
+			exit(exitBadInput)
+		}
+		commitment, err := proofs.CommitCohortSeed(*seedPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Noise seed saved to: %s (keep it secret)\n", *seedPath)
+		fmt.Printf("Noise seed commitment: %s\n", commitment)
+		fmt.Println("Publish the commitment before collecting carrier proofs; verifiers check it with verify -commitment.")
+		return
+	}
+
+	if *outputPath == "" {
+		*outputPath = filepath.Join("output", *proofType+"_proof.bin")
+	}
+
+	var proof interface {
+		Aggregate(ctx context.Context, provingKeyPath string, outputPath string) error
+	}
+	switch *proofType {
+	case "cohort":
+		if *variant == "" || *inputProofs == "" || *seedPath == "" {
+			fmt.Fprintf(os.Stderr, "Error: -variant, -proofs and -seed are required\n\n")
+			aggregateCmd.Usage()
+			exit(exitBadInput)
+		}
+		cohort := &proofs.CohortProof{
+			Settings:      proofs.Settings{CCSCacheDir: *ccsCacheDir, KeyCacheDir: *keyCacheDir, Force: *force},
+			Variant:       *variant,
+			CarrierProofs: strings.Split(*inputProofs, ","),
+			Epsilon:       *epsilon,
+			SeedFile:      *seedPath,
+		}
+		fmt.Printf("Aggregating %d carrier proofs...\n", len(cohort.CarrierProofs))
+		proof = cohort
+	case "recursive":
+		if *inputProofs == "" {
+			fmt.Fprintf(os.Stderr, "Error: -proofs is required\n\n")
+			aggregateCmd.Usage()
+			exit(exitBadInput)
+		}
+		recursive := &proofs.RecursiveProof{
+			Settings: proofs.Settings{CCSCacheDir: *ccsCacheDir, KeyCacheDir: *keyCacheDir, Force: *force},
+			Proofs:   strings.Split(*inputProofs, ","),
+		}
+		fmt.Printf("Aggregating %d proofs recursively...\n", len(recursive.Proofs))
+		proof = recursive
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown aggregation type %s\n\n", *proofType)
+		aggregateCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	if err := proof.Aggregate(cmdContext, *provingKeyPath, *outputPath); err != nil {
+		fmt.Printf("Error aggregating proofs: %v\n", err)
+		exit(exitCode(err, 1))
+	}
+
+	fmt.Printf("Successfully generated %s proof at: %s\n", *proofType, *outputPath)
+}
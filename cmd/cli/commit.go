@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleCommit(args []string) {
+	commitCmd := flag.NewFlagSet("commit", flag.ExitOnError)
+	proofType := commitCmd.String("type", "chromosome", "Type of proof the commitment is for (chromosome, membership, absence, region, haplotype, panel)")
+	vcfPath := commitCmd.String("vcf", "", "Path to VCF file")
+	imputationMode := commitCmd.String("imputation", "allow", "How to treat imputed genotype calls (allow, refuse, threshold)")
+	minImputedProb := commitCmd.Float64("min-imputed-prob", 0.9, "Minimum genotype probability for imputed calls when -imputation=threshold")
+	maxVariants := commitCmd.Int("max-variants", proofs.DefaultMaxVariants, "Number of VCF records the chromosome circuit holds (must match generate)")
+	treeDepth := commitCmd.Int("tree-depth", proofs.DefaultTreeDepth, "Depth of the variant Merkle tree (membership, absence, region, haplotype, panel)")
+	hashName := commitCmd.String("hash", "mimc", "Hash for variant tree commitments (mimc, poseidon2); must match generate")
+	maxRecords := commitCmd.Int("max-records", 0, "Fail once the VCF has more than this many records (0 for no limit)")
+	maxLineBytes := commitCmd.Int("max-line-bytes", 0, "Fail on VCF lines longer than this many bytes, bounding the memory each record takes (0 for no limit)")
+	outPath := commitCmd.String("out", "", "Output path for the commitment (default: <vcf>.commitment.json)")
+
+	commitCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s commit [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Commit to the private values of a VCF ahead of proving\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		commitCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s commit -type chromosome -vcf data/genome.vcf\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type chromosome -vcf data/genome.vcf -commitment data/genome.vcf.commitment.json\n", os.Args[0])
+	}
+
+	commitCmd.Parse(args)
+
+	if *vcfPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -vcf is required\n\n")
+		commitCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	mode, err := proofs.ParseImputationMode(*imputationMode)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	imputation := proofs.ImputationPolicy{Mode: mode}
+	if mode == proofs.ImputationThreshold {
+		imputation.MinProbability = *minImputedProb
+	}
+
+	hash, err := proofs.ParseHashAlgorithm(*hashName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	limits := proofs.ReadLimits{MaxRecords: *maxRecords, MaxLineBytes: *maxLineBytes}
+
+	var commitment *proofs.DatasetCommitment
+	switch strings.ToLower(*proofType) {
+	case "chromosome":
+		commitment, err = proofs.CommitChromosomes(*vcfPath, *maxVariants, imputation, limits)
+	case "membership", "absence", "region", "panel":
+		commitment, err = proofs.CommitVariants(*vcfPath, *treeDepth, imputation, hash, limits)
+	case "haplotype":
+		commitment, err = proofs.CommitHaplotypes(*vcfPath, *treeDepth, imputation, limits)
+	default:
+		err = fmt.Errorf("commitments are not supported for %s proofs", *proofType)
+	}
+	if err == nil && hash != proofs.HashMiMC && commitment.Hash != hash {
+		err = fmt.Errorf("%s commitments only support the mimc hash", *proofType)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	if *outPath == "" {
+		*outPath = *vcfPath + ".commitment.json"
+	}
+	if err := proofs.WriteCommitment(*outPath, commitment); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	fmt.Printf("Commitment: %s\n", commitment.Commitment)
+	if commitment.Salt != "" {
+		fmt.Printf("Commitment and salt saved to: %s (keep the salt private)\n", *outPath)
+	} else {
+		fmt.Printf("Commitment saved to: %s\n", *outPath)
+	}
+}
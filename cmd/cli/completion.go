@@ -0,0 +1,255 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// commands are the CLI's subcommands, in the order printUsage lists them.
+var commands = []string{
+	"generate", "solve", "prover", "serve", "verify", "inspect", "validate", "check", "prove-panel", "panel", "extract",
+	"setup", "keys", "commit", "aggregate", "keygen", "attest", "import",
+	"export-verifier", "export", "bench", "selftest", "list-types", "completion", "version", "help",
+}
+
+// keysCommands are the subcommands of keys.
+var keysCommands = []string{"list", "fingerprint", "export", "import", "did", "publish", "check", "contract", "help"}
+
+// panelCommands are the subcommands of panel.
+var panelCommands = []string{"list", "show", "add", "remove", "help"}
+
+// completionValue is a flag whose values a completion script offers.
+type completionValue struct {
+	Flag   string
+	Values []string
+}
+
+// completionValues lists the flags taking one of a fixed set of values.
+// Flag names themselves are read from the command's -h output when
+// completing, so they cannot drift from the flag sets.
+func completionValues() []completionValue {
+	var types []string
+	for _, t := range proofs.ProofTypes() {
+		types = append(types, t.Name)
+	}
+	var loci []string
+	for name := range proofs.RepeatLoci {
+		loci = append(loci, name)
+	}
+	slices.Sort(loci)
+
+	return []completionValue{
+		{"type", types},
+		{"backend", []string{string(proofs.BackendGroth16), string(proofs.BackendPlonk)}},
+		{"curve", []string{string(proofs.CurveBN254), string(proofs.CurveBLS12381), string(proofs.CurveBLS12377), string(proofs.CurveBW6761)}},
+		{"hash", []string{string(proofs.HashMiMC), string(proofs.HashPoseidon2)}},
+		{"imputation", []string{string(proofs.ImputationAllow), string(proofs.ImputationRefuse), string(proofs.ImputationThreshold)}},
+		{"missing", []string{string(proofs.MissingFail), string(proofs.MissingTreatAsMissing), string(proofs.MissingRequireCoverage)}},
+		{"digest", []string{string(proofs.DigestNone), string(proofs.DigestMetadata), string(proofs.DigestPublic)}},
+		{"accelerator", []string{string(proofs.AcceleratorCPU), string(proofs.AcceleratorGPU)}},
+		{"sv-type", []string{"DEL", "DUP"}},
+		{"locus", loci},
+		{"format", exportFormats},
+	}
+}
+
+func handleCompletion(args []string) {
+	completionCmd := flag.NewFlagSet("completion", flag.ExitOnError)
+
+	completionCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s completion bash|zsh|fish\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print a shell completion script covering the commands, their flags, proof types and flag values\n")
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  source <(%s completion bash)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s completion zsh > ~/.zsh/completions/_%s\n", os.Args[0], filepath.Base(os.Args[0]))
+		fmt.Fprintf(os.Stderr, "  %s completion fish > ~/.config/fish/completions/%s.fish\n", os.Args[0], filepath.Base(os.Args[0]))
+	}
+
+	completionCmd.Parse(args)
+
+	if completionCmd.NArg() != 1 {
+		completionCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	script, ok := completionScripts[completionCmd.Arg(0)]
+	if !ok {
+		fmt.Printf("Error: unsupported shell %q (expected bash, zsh or fish)\n", completionCmd.Arg(0))
+		exit(exitBadInput)
+	}
+
+	prog := filepath.Base(os.Args[0])
+	data := struct {
+		Prog, Func    string
+		Commands      string
+		KeysCommands  string
+		PanelCommands string
+		Values        []completionValue
+	}{
+		Prog:          prog,
+		Func:          "_" + regexp.MustCompile(`[^A-Za-z0-9_]`).ReplaceAllString(prog, "_"),
+		Commands:      strings.Join(commands, " "),
+		KeysCommands:  strings.Join(keysCommands, " "),
+		PanelCommands: strings.Join(panelCommands, " "),
+		Values:        completionValues(),
+	}
+	funcs := template.FuncMap{"join": strings.Join}
+	tmpl := template.Must(template.New(completionCmd.Arg(0)).Funcs(funcs).Parse(script))
+	if err := tmpl.Execute(os.Stdout, data); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+}
+
+// completionScripts are the completion script templates by shell. Each
+// reads a command's flags from "<command> -h", so it needs the binary on
+// the command line to be runnable.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for {{.Prog}}
+{{.Func}}() {
+    local cur prev cmd sub i=1
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    if [[ "${COMP_WORDS[1]}" == "--json" || "${COMP_WORDS[1]}" == "-json" ]]; then
+        i=2
+    fi
+
+    if (( COMP_CWORD == i )); then
+        local words="{{.Commands}}"
+        (( i == 1 )) && words="--json $words"
+        COMPREPLY=( $(compgen -W "$words" -- "$cur") )
+        return
+    fi
+    cmd="${COMP_WORDS[i]}"
+    if [[ "$cmd" == keys || "$cmd" == panel ]]; then
+        if (( COMP_CWORD == i + 1 )); then
+            local subs="{{.KeysCommands}}"
+            [[ "$cmd" == panel ]] && subs="{{.PanelCommands}}"
+            COMPREPLY=( $(compgen -W "$subs" -- "$cur") )
+            return
+        fi
+        sub="${COMP_WORDS[i+1]}"
+    fi
+    if [[ "$cmd" == completion ]]; then
+        COMPREPLY=( $(compgen -W "bash zsh fish" -- "$cur") )
+        return
+    fi
+
+    case "${prev#-}" in
+{{- range .Values}}
+        {{.Flag}}|-{{.Flag}})
+            COMPREPLY=( $(compgen -W "{{join .Values " "}}" -- "$cur") )
+            return ;;
+{{- end}}
+    esac
+
+    if [[ "$cur" == -* ]]; then
+        local flags
+        flags=$("${COMP_WORDS[0]}" "$cmd" $sub -h 2>&1 | sed -n 's/^  \(-[A-Za-z0-9-]*\).*/\1/p')
+        COMPREPLY=( $(compgen -W "$flags" -- "$cur") )
+        return
+    fi
+    COMPREPLY=( $(compgen -f -- "$cur") )
+}
+complete -o filenames -F {{.Func}} {{.Prog}}
+`,
+
+	"zsh": `#compdef {{.Prog}}
+# zsh completion for {{.Prog}}
+{{.Func}}() {
+    local i=2 cmd sub
+    if [[ $words[2] == (--json|-json) ]]; then
+        i=3
+    fi
+
+    if (( CURRENT == i )); then
+        (( i == 2 )) && compadd -- --json
+        compadd -- {{.Commands}}
+        return
+    fi
+    cmd=$words[i]
+    if [[ $cmd == (keys|panel) ]]; then
+        if (( CURRENT == i + 1 )); then
+            if [[ $cmd == panel ]]; then
+                compadd -- {{.PanelCommands}}
+            else
+                compadd -- {{.KeysCommands}}
+            fi
+            return
+        fi
+        sub=$words[i+1]
+    fi
+    if [[ $cmd == completion ]]; then
+        compadd -- bash zsh fish
+        return
+    fi
+
+    case ${words[CURRENT-1]#-} in
+{{- range .Values}}
+        ({{.Flag}}|-{{.Flag}})
+            compadd -- {{join .Values " "}}
+            return ;;
+{{- end}}
+    esac
+
+    if [[ $PREFIX == -* ]]; then
+        compadd -- ${(f)"$(${words[1]} $cmd $sub -h 2>&1 | sed -n 's/^  \(-[A-Za-z0-9-]*\).*/\1/p')"}
+        return
+    fi
+    _files
+}
+compdef {{.Func}} {{.Prog}}
+`,
+
+	"fish": `# fish completion for {{.Prog}}
+function {{.Func}}_args
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    if test (count $tokens) -gt 0; and contains -- $tokens[1] --json -json
+        set -e tokens[1]
+    end
+    printf '%s\n' $tokens
+end
+
+function {{.Func}}_needs_command
+    test (count ({{.Func}}_args)) -eq 0
+end
+
+function {{.Func}}_needs_subcommand
+    set -l args ({{.Func}}_args)
+    test (count $args) -eq 1; and contains -- $args[1] $argv
+end
+
+function {{.Func}}_after
+    set -l tokens (commandline -opc)
+    contains -- $tokens[-1] -$argv[1] --$argv[1]
+end
+
+function {{.Func}}_flags
+    set -l args ({{.Func}}_args)
+    set -l cmd $args[1]
+    if contains -- "$cmd" keys panel
+        set cmd $cmd $args[2]
+    end
+    set -l prog (commandline -opc)[1]
+    $prog $cmd -h 2>&1 | string replace -rf '^  (-[A-Za-z0-9-]+).*' '$1'
+end
+
+complete -c {{.Prog}} -n {{.Func}}_needs_command -f -a '--json {{.Commands}}'
+complete -c {{.Prog}} -n '{{.Func}}_needs_subcommand keys' -f -a '{{.KeysCommands}}'
+complete -c {{.Prog}} -n '{{.Func}}_needs_subcommand panel' -f -a '{{.PanelCommands}}'
+complete -c {{.Prog}} -n '{{.Func}}_needs_subcommand completion' -f -a 'bash zsh fish'
+complete -c {{.Prog}} -n 'not {{.Func}}_needs_command; and string match -q -- "-*" (commandline -ct)' -f -a '({{.Func}}_flags)'
+{{- range .Values}}
+complete -c {{$.Prog}} -n '{{$.Func}}_after {{.Flag}}' -f -a '{{join .Values " "}}'
+{{- end}}
+`,
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables supply the defaults of the flags shared across
+// commands, so a container can configure the tool without flags. A flag
+// given on the command line wins over its variable, which wins over the
+// built-in default.
+const (
+	envOutputDir   = "VCFPROOF_OUTPUT_DIR"
+	envKeyDir      = "VCFPROOF_KEY_DIR"
+	envPanel       = "VCFPROOF_PANEL"
+	envBackend     = "VCFPROOF_BACKEND"
+	envIPFSAPI     = "VCFPROOF_IPFS_API"
+	envIPFSGateway = "VCFPROOF_IPFS_GATEWAY"
+	envRegistry    = "VCFPROOF_REGISTRY"
+	envRegistryRPC = "VCFPROOF_REGISTRY_RPC"
+)
+
+// envVars lists the variables with the flags they set, for printUsage.
+var envVars = []struct{ name, flags string }{
+	{envOutputDir, "generate and prove-panel -output-dir"},
+	{envKeyDir, "setup -out, serve -key-dir, keys -dir; generate -proving-key and verify -verifying-key use the <type>.pk and <type>.vk in it"},
+	{envPanel, "-panel of every command but import"},
+	{envBackend, "generate, prove-panel, setup, bench and selftest -backend"},
+	{envIPFSAPI, "export -ipfs-api"},
+	{envIPFSGateway, "verify -ipfs-gateway"},
+	{envRegistry, "verify and keys publish, check -registry"},
+	{envRegistryRPC, "verify and keys publish, check -registry-rpc"},
+}
+
+// keyDirFile returns the key of proofType with extension ext (".pk" or
+// ".vk") in the VCFPROOF_KEY_DIR directory, where setup -out writes it, or
+// "" when the variable is unset or the directory holds no such key.
+func keyDirFile(proofType, ext string) string {
+	dir := os.Getenv(envKeyDir)
+	if dir == "" || proofType == "" {
+		return ""
+	}
+	path := filepath.Join(dir, strings.ToLower(proofType)+ext)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// envDefault returns the value of the environment variable name, or def
+// when it is unset or empty.
+func envDefault(name, def string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return def
+}
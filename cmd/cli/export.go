@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// Formats export writes a proof in.
+const (
+	// formatEVMCalldata is the hex calldata of a call to the exported
+	// verifier contract.
+	formatEVMCalldata = "evm-calldata"
+	// formatCalldataJSON is the proof and public inputs as separate
+	// arguments, as export-verifier writes them.
+	formatCalldataJSON = "calldata-json"
+	// formatEnvelope is the proof, its public inputs and metadata as one
+	// documented JSON document, which verify also reads.
+	formatEnvelope = "envelope"
+)
+
+var exportFormats = []string{formatEVMCalldata, formatCalldataJSON, formatEnvelope}
+
+func handleExport(args []string) {
+	exportCmd := flag.NewFlagSet("export", flag.ExitOnError)
+	proofPath := exportCmd.String("proof", "", "Path to the proof file to export")
+	format := exportCmd.String("format", formatEVMCalldata, "Format to export the proof in ("+strings.Join(exportFormats, ", ")+")")
+	publish := exportCmd.Bool("ipfs", false, "Pin the proof and its verifying key to IPFS and record their CIDs in the envelope (envelope only)")
+	ipfsAPI := exportCmd.String("ipfs-api", envDefault(envIPFSAPI, proofs.DefaultIPFSAPI), "RPC API of the IPFS node -ipfs pins through")
+	verifyingKeyPath := exportCmd.String("verifying-key", "", "Verifying key -ipfs publishes (default: <proof>.vk)")
+	outputPath := exportCmd.String("output", "", "Output path (default: <proof>.calldata for evm-calldata, <proof>"+proofs.CalldataSuffix+" for calldata-json, <proof>"+proofs.EnvelopeSuffix+" for envelope)")
+
+	exportCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s export -proof <file> [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Export a proof for an on-chain verifier. evm-calldata writes the 0x-prefixed hex\n")
+		fmt.Fprintf(os.Stderr, "calldata of a call to the contract export-verifier exports: the function selector\n")
+		fmt.Fprintf(os.Stderr, "followed by the ABI-encoded proof and public inputs, ready to send with eth_call or\n")
+		fmt.Fprintf(os.Stderr, "in a transaction. calldata-json writes the arguments separately. Proofs must be over\n")
+		fmt.Fprintf(os.Stderr, "bn254. envelope instead writes the proof, its public inputs, proof system and\n")
+		fmt.Fprintf(os.Stderr, "metadata as one versioned JSON document that verify accepts in place of the proof;\n")
+		fmt.Fprintf(os.Stderr, "with -ipfs the proof and verifying key are pinned to IPFS and the envelope records\n")
+		fmt.Fprintf(os.Stderr, "their CIDs, so a verifier holding only the envelope can fetch the key.\n")
+		fmt.Fprintf(os.Stderr, "The proof is not verified; use verify for that.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		exportCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s export -proof output/zygosity_proof.bin -format evm-calldata\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s export -proof output/zygosity_proof.bin -format envelope\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s export -proof output/zygosity_proof.bin -format envelope -ipfs\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  cast call $VERIFIER \"$(cat output/zygosity_proof.bin.calldata)\"\n")
+	}
+
+	exportCmd.Parse(args)
+
+	if *proofPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -proof is required\n\n")
+		exportCmd.Usage()
+		exit(exitBadInput)
+	}
+	if !slices.Contains(exportFormats, *format) {
+		fmt.Printf("Error: unknown format %q (want %s)\n", *format, strings.Join(exportFormats, " or "))
+		exit(exitBadInput)
+	}
+	if *publish && *format != formatEnvelope {
+		fmt.Printf("Error: -ipfs records the CIDs in an envelope; use -format envelope\n")
+		exit(exitBadInput)
+	}
+
+	switch *format {
+	case formatEVMCalldata:
+		if *outputPath == "" {
+			*outputPath = *proofPath + ".calldata"
+		}
+		calldata, err := proofs.EVMCalldata(*proofPath)
+		if err != nil {
+			fmt.Printf("Error exporting calldata: %v\n", err)
+			exit(exitCode(err, 1))
+		}
+		if err := os.WriteFile(*outputPath, []byte("0x"+hex.EncodeToString(calldata)+"\n"), 0644); err != nil {
+			fmt.Printf("Error writing calldata: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Calldata (%d bytes) saved to: %s\n", len(calldata), *outputPath)
+	case formatCalldataJSON:
+		if *outputPath == "" {
+			*outputPath = *proofPath + proofs.CalldataSuffix
+		}
+		calldata, err := proofs.ProofCalldata(*proofPath)
+		if err != nil {
+			fmt.Printf("Error exporting calldata: %v\n", err)
+			exit(exitCode(err, 1))
+		}
+		if err := proofs.WriteCalldata(*outputPath, calldata); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Calldata (%d public inputs) saved to: %s\n", len(calldata.Inputs), *outputPath)
+	case formatEnvelope:
+		if *outputPath == "" {
+			*outputPath = *proofPath + proofs.EnvelopeSuffix
+		}
+		env, err := proofs.ReadProofEnvelope(*proofPath)
+		if err != nil {
+			fmt.Printf("Error exporting envelope: %v\n", err)
+			exit(exitCode(err, 1))
+		}
+		if *publish {
+			if *verifyingKeyPath == "" {
+				*verifyingKeyPath = *proofPath + ".vk"
+			}
+			if err := env.Publish(cmdContext, &proofs.IPFS{API: *ipfsAPI}, *proofPath, *verifyingKeyPath); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				exit(exitCode(err, 1))
+			}
+			fmt.Printf("Proof pinned to IPFS as %s\n", env.ProofCID)
+			fmt.Printf("Verifying key pinned to IPFS as %s\n", env.VerifyingKeyCID)
+		}
+		data, err := json.MarshalIndent(env, "", "  ")
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		if err := os.WriteFile(*outputPath, append(data, '\n'), 0644); err != nil {
+			fmt.Printf("Error writing envelope: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Envelope (%d public inputs) saved to: %s\n", len(env.PublicInputs), *outputPath)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleExtract(args []string) {
+	extractCmd := flag.NewFlagSet("extract", flag.ExitOnError)
+	vcfPath := extractCmd.String("vcf", "", "Path to the whole-genome VCF, plain or compressed with gzip or bgzip")
+	outputPath := extractCmd.String("output", "", "Output path for the extract (default: the VCF path with .extract.vcf in place of .vcf or .vcf.gz)")
+	panelPath := extractCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel whose loci to keep (empty for none)")
+	gene := extractCmd.String("gene", "", "Only keep this gene's trait panel loci")
+	builtin := extractCmd.Bool("builtin", true, "Keep the loci of the built-in proof types (eyecolor, irisplex, bloodtype, brca1, diplotype, compoundhet)")
+	variants := extractCmd.String("variants", "", "Comma-separated chrom:pos:ref:alt variants to keep as well")
+	regions := extractCmd.String("regions", "", "Comma-separated chrom:start-end regions whose records to keep as well")
+	maxLineBytes := extractCmd.Int("max-line-bytes", 0, "Fail on VCF lines longer than this many bytes (0 for no limit)")
+
+	extractCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s extract [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Write a small VCF holding only the records proofs need, so the whole\n")
+		fmt.Fprintf(os.Stderr, "genome does not have to be passed to generate again\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		extractCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s extract -vcf data/genome.vcf.gz -output data/panel.vcf\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s extract -vcf data/genome.vcf.gz -builtin=false -gene APOE -regions 4:3076604-3076695\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type eyecolor -vcf data/panel.vcf\n", os.Args[0])
+	}
+
+	extractCmd.Parse(args)
+
+	if *vcfPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -vcf is required\n\n")
+		extractCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	opts := proofs.ExtractOptions{Limits: proofs.ReadLimits{MaxLineBytes: *maxLineBytes}}
+	if *builtin {
+		opts.Variants = proofs.BuiltinVariants()
+	}
+	if *panelPath != "" {
+		panel, err := proofs.LoadTraitPanel(*panelPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		specs, err := proofs.PanelVariants(panel, *gene)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		opts.Variants = append(opts.Variants, specs...)
+	}
+	if *variants != "" {
+		for _, s := range strings.Split(*variants, ",") {
+			v, err := proofs.ParseVariantSpec(strings.TrimSpace(s))
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				exit(1)
+			}
+			opts.Variants = append(opts.Variants, v)
+		}
+	}
+	if *regions != "" {
+		for _, s := range strings.Split(*regions, ",") {
+			r, err := proofs.ParseRegion(strings.TrimSpace(s))
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				exit(1)
+			}
+			opts.Regions = append(opts.Regions, r)
+		}
+	}
+
+	if *outputPath == "" {
+		*outputPath = strings.TrimSuffix(strings.TrimSuffix(*vcfPath, ".gz"), ".vcf") + ".extract.vcf"
+	}
+	stats, err := proofs.ExtractVCF(*vcfPath, *outputPath, opts)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	fmt.Printf("Kept %d of %d records for %d variants and %d regions\n", stats.Kept, stats.Records, len(opts.Variants), len(opts.Regions))
+	fmt.Printf("Dataset digest: %s\n", stats.Digest)
+	fmt.Printf("Extract saved to: %s\n", *outputPath)
+	fmt.Printf("Manifest saved to: %s\n", *outputPath+proofs.ManifestSuffix)
+}
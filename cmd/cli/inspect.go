@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleInspect(args []string) {
+	inspectCmd := flag.NewFlagSet("inspect", flag.ExitOnError)
+
+	inspectCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s inspect <proof file>...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print what a proof file and its metadata state about the proof without verifying it.\n")
+		fmt.Fprintf(os.Stderr, "Nothing printed is established until the proof passes verify.\n")
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s inspect output/eyecolor_proof.bin\n", os.Args[0])
+	}
+
+	inspectCmd.Parse(args)
+
+	if inspectCmd.NArg() == 0 {
+		inspectCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	for i, proofPath := range inspectCmd.Args() {
+		if i > 0 {
+			fmt.Println()
+		}
+		summary, err := proofs.InspectProof(proofPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		printProofSummary(proofPath, summary)
+		if jsonMode {
+			jsonReport.Summaries = append(jsonReport.Summaries, summaryReport{
+				Proof:        proofPath,
+				Size:         summary.Size,
+				Header:       summary.Header,
+				Metadata:     summary.Metadata,
+				inputsReport: summaryInputs(summary),
+			})
+		}
+	}
+}
+
+func printProofSummary(proofPath string, summary *proofs.ProofSummary) {
+	fmt.Printf("Proof:           %s (%d bytes, not verified)\n", proofPath, summary.Size)
+	if header := summary.Header; header != nil {
+		fmt.Printf("Header:          format %d, %s proof, circuit %s\n", header.Format, valueOrUnknown(header.Type), valueOrUnknown(header.CircuitHash))
+	} else {
+		fmt.Printf("Header:          none (written before proof files had one)\n")
+	}
+	if meta := summary.Metadata; meta != nil {
+		backend, curve := meta.Backend, meta.Curve
+		if backend == "" {
+			backend = proofs.BackendGroth16
+		}
+		if curve == "" {
+			curve = proofs.CurveBN254
+		}
+		fmt.Printf("Proof type:      %s\n", meta.ProofType)
+		fmt.Printf("Created:         %s\n", meta.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+		fmt.Printf("Backend:         %s over %s\n", backend, curve)
+		fmt.Printf("Circuit hash:    %s\n", valueOrUnknown(meta.CircuitHash))
+		if meta.Software != nil {
+			fmt.Printf("Generated by:    %s\n", meta.Software)
+		}
+		fmt.Printf("Proving key:     %s\n", valueOrUnknown(meta.ProvingKey))
+		fmt.Printf("Verifying key:   %s\n", valueOrUnknown(meta.VerifyingKey))
+		if meta.DatasetDigest != "" {
+			fmt.Printf("Dataset digest:  %s\n", meta.DatasetDigest)
+		}
+		if meta.Filter != nil {
+			fmt.Printf("Call filter:     %s\n", meta.Filter)
+		}
+		if meta.Provenance != nil {
+			fmt.Printf("Provenance:      lab %s (signature not checked)\n", meta.Provenance.Statement.LabID)
+		}
+		if meta.HolderSignature != nil {
+			fmt.Printf("Holder key:      %s (signature not checked)\n", hex.EncodeToString(meta.HolderSignature.PublicKey))
+		}
+	} else {
+		fmt.Printf("Metadata:        none (%s missing)\n", proofPath+proofs.MetadataSuffix)
+	}
+
+	fmt.Printf("Public inputs:   %d\n", len(summary.Inputs))
+	for i, input := range summary.Inputs {
+		fmt.Printf("  [%d] %s\n", i, input)
+	}
+	if summary.Nullifier != nil {
+		fmt.Printf("Nullifier:       %s\n", summary.Nullifier)
+	}
+	if summary.Nonce != nil {
+		if summary.Metadata != nil && summary.Metadata.Nonce != "" {
+			fmt.Printf("Nonce:           %q\n", summary.Metadata.Nonce)
+		} else {
+			fmt.Printf("Nonce:           %s\n", summary.Nonce)
+		}
+	}
+	if summary.Digest != nil {
+		fmt.Printf("Bound digest:    %s\n", summary.Digest)
+	}
+	if summary.Issued != nil {
+		fmt.Printf("Issued:          %s\n", unixTime(summary.Issued))
+	}
+	if summary.Expires != nil {
+		fmt.Printf("Expires:         %s\n", unixTime(summary.Expires))
+	}
+	for _, claim := range summary.Claims {
+		fmt.Printf("Claim:           %s\n", claim)
+	}
+}
+
+func valueOrUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
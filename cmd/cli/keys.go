@@ -0,0 +1,367 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleKeys(args []string) {
+	if len(args) == 0 {
+		printKeysUsage()
+		exit(exitBadInput)
+	}
+
+	switch args[0] {
+	case "list":
+		handleKeysList(args[1:])
+	case "fingerprint":
+		handleKeysFingerprint(args[1:])
+	case "export":
+		handleKeysExport(args[1:])
+	case "import":
+		handleKeysImport(args[1:])
+	case "did":
+		handleKeysDID(args[1:])
+	case "publish":
+		handleKeysPublish(args[1:])
+	case "check":
+		handleKeysCheck(args[1:])
+	case "contract":
+		handleKeysContract(args[1:])
+	case "help", "-h", "--help":
+		printKeysUsage()
+	default:
+		fmt.Printf("Unknown keys command: %s\n\n", args[0])
+		printKeysUsage()
+		exit(exitBadInput)
+	}
+}
+
+func printKeysUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s keys <command> [options]\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Manage proving and verifying keys, such as those written by setup\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "  list         List the key pairs in a directory with their fingerprints and circuits\n")
+	fmt.Fprintf(os.Stderr, "  fingerprint  Print the SHA-256 fingerprints of key files\n")
+	fmt.Fprintf(os.Stderr, "  export       Write key pairs to a portable bundle\n")
+	fmt.Fprintf(os.Stderr, "  import       Unpack a bundle into a key directory after checking its fingerprints\n")
+	fmt.Fprintf(os.Stderr, "  did          Write a did:web DID document publishing the verifying keys of a directory\n")
+	fmt.Fprintf(os.Stderr, "  publish      Publish the verifying keys of a directory to an on-chain key registry by circuit hash\n")
+	fmt.Fprintf(os.Stderr, "  check        Check the verifying keys of a directory against an on-chain key registry\n")
+	fmt.Fprintf(os.Stderr, "  contract     Write the Solidity source of the key registry contract\n")
+	fmt.Fprintf(os.Stderr, "\nExamples:\n")
+	fmt.Fprintf(os.Stderr, "  %s keys list -dir keys\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s keys fingerprint keys/eyecolor.vk\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s keys export -dir keys -type eyecolor,bloodtype -verifying-only -out verifier-keys.tar.gz\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s keys import -in verifier-keys.tar.gz -dir keys\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s keys contract -out VerifyingKeyRegistry.sol\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s keys publish -dir keys -registry 0x5FbDB2315678afecb367f032d93F642f64180aa3 -registry-rpc http://localhost:8545 -from 0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s keys check -dir keys -registry 0x5FbDB2315678afecb367f032d93F642f64180aa3 -registry-rpc https://rpc.example.org\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s keys did -did did:web:keys.example.org -dir keys -url https://keys.example.org/vk -out did.json\n", os.Args[0])
+}
+
+func handleKeysList(args []string) {
+	listCmd := flag.NewFlagSet("keys list", flag.ExitOnError)
+	dir := listCmd.String("dir", envDefault(envKeyDir, "keys"), "Key directory")
+	listCmd.Parse(args)
+
+	keys, err := proofs.ListKeys(*dir)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	if len(keys) == 0 {
+		fmt.Printf("No keys in %s\n", *dir)
+		return
+	}
+	for _, pair := range keys {
+		printKeyPair(pair)
+	}
+}
+
+func printKeyPair(pair proofs.KeyPair) {
+	fmt.Printf("%s\n", pair.Name)
+	if pair.PK != "" {
+		fmt.Printf("  Proving key:   %s\n", pair.PK)
+	}
+	if pair.VK != "" {
+		fmt.Printf("  Verifying key: %s\n", pair.VK)
+	}
+	info := pair.Info
+	if info == nil {
+		fmt.Printf("  Circuit:       unknown (no %s description)\n", proofs.KeyInfoSuffix)
+		return
+	}
+	fmt.Printf("  Circuit:       %s, %s over %s, %d constraints\n", info.ProofType, info.Backend, info.Curve, info.Constraints)
+	fmt.Printf("  Circuit hash:  %s\n", info.CircuitHash)
+	if info.Nullifier || info.Nonce || info.DigestBound || info.Timestamp {
+		fmt.Printf("  Envelope:      nullifier=%v nonce=%v digest=%v timestamp=%v expiry=%v\n", info.Nullifier, info.Nonce, info.DigestBound, info.Timestamp, info.Expiry)
+	}
+	fmt.Printf("  Created:       %s\n", info.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+}
+
+func handleKeysFingerprint(args []string) {
+	fingerprintCmd := flag.NewFlagSet("keys fingerprint", flag.ExitOnError)
+	fingerprintCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s keys fingerprint <key file>...\n", os.Args[0])
+	}
+	fingerprintCmd.Parse(args)
+
+	if fingerprintCmd.NArg() == 0 {
+		fingerprintCmd.Usage()
+		exit(exitBadInput)
+	}
+	for _, path := range fingerprintCmd.Args() {
+		fingerprint, err := proofs.KeyFingerprint(path)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("%s  %s\n", fingerprint, path)
+	}
+}
+
+func handleKeysExport(args []string) {
+	exportCmd := flag.NewFlagSet("keys export", flag.ExitOnError)
+	dir := exportCmd.String("dir", envDefault(envKeyDir, "keys"), "Key directory")
+	names := exportCmd.String("type", "", "Comma-separated key names to export, e.g. proof types set up with setup (default: all)")
+	outputPath := exportCmd.String("out", "keys.tar.gz", "Output path for the bundle")
+	verifyingOnly := exportCmd.Bool("verifying-only", false, "Leave the proving keys out, for bundles handed to verifiers")
+	exportCmd.Parse(args)
+
+	keys, err := proofs.ExportKeys(*dir, splitList(*names), *outputPath, *verifyingOnly)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	for _, pair := range keys {
+		printKeyPair(pair)
+	}
+	fmt.Printf("Bundle of %d key pairs saved to: %s\n", len(keys), *outputPath)
+}
+
+func handleKeysImport(args []string) {
+	importCmd := flag.NewFlagSet("keys import", flag.ExitOnError)
+	bundlePath := importCmd.String("in", "", "Bundle written by keys export")
+	dir := importCmd.String("dir", envDefault(envKeyDir, "keys"), "Key directory to unpack the bundle into")
+	importCmd.Parse(args)
+
+	if *bundlePath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -in is required\n\n")
+		importCmd.Usage()
+		exit(exitBadInput)
+	}
+	keys, err := proofs.ImportKeys(*bundlePath, *dir)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	for _, pair := range keys {
+		printKeyPair(pair)
+	}
+	fmt.Printf("Imported %d key pairs into %s\n", len(keys), *dir)
+}
+
+func handleKeysDID(args []string) {
+	didCmd := flag.NewFlagSet("keys did", flag.ExitOnError)
+	did := didCmd.String("did", "", "did:web identifier the document is for, e.g. did:web:keys.example.org")
+	dir := didCmd.String("dir", envDefault(envKeyDir, "keys"), "Key directory whose verifying keys the document publishes")
+	names := didCmd.String("type", "", "Comma-separated key names to publish (default: all)")
+	baseURL := didCmd.String("url", "", "URL the verifying key files are served under (default: beside the document)")
+	holderKeyPath := didCmd.String("holder-key", "", "Holder public key (PEM) to list as the DID's signing key, for signing proofs with generate -sign-did (optional)")
+	outputPath := didCmd.String("out", "did.json", "Output path for the DID document")
+	didCmd.Parse(args)
+
+	if *did == "" {
+		fmt.Fprintf(os.Stderr, "Error: -did is required\n\n")
+		didCmd.Usage()
+		exit(exitBadInput)
+	}
+	docURL, err := proofs.DIDWebURL(*did)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+	if *baseURL == "" {
+		*baseURL = docURL[:strings.LastIndex(docURL, "/")]
+	}
+	var holderKey ed25519.PublicKey
+	if *holderKeyPath != "" {
+		if holderKey, err = proofs.ReadPublicKey(*holderKeyPath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
+
+	keys, err := proofs.ListKeys(*dir)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	doc := proofs.NewDIDDocument(*did, holderKey)
+	wanted := splitList(*names)
+	for _, pair := range keys {
+		if pair.VK == "" || len(wanted) > 0 && !slices.Contains(wanted, pair.Name) {
+			continue
+		}
+		proofType := pair.Name
+		if pair.Info != nil {
+			proofType = pair.Info.ProofType
+		}
+		vkPath := filepath.Join(*dir, pair.Name+".vk")
+		endpoint := strings.TrimSuffix(*baseURL, "/") + "/" + filepath.Base(vkPath)
+		if err := doc.PublishVerifyingKey(proofType, vkPath, endpoint); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Publishing %s verifying key %s at %s\n", proofType, vkPath, endpoint)
+	}
+	if len(doc.Service) == 0 {
+		fmt.Printf("Error: no verifying keys to publish in %s\n", *dir)
+		exit(exitNotFound)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	if err := os.WriteFile(*outputPath, append(data, '\n'), 0644); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	fmt.Printf("DID document saved to: %s\n", *outputPath)
+	fmt.Printf("Serve it at %s and the verifying keys at %s\n", docURL, *baseURL)
+}
+
+// keyRegistry returns the registry at address, read through the node at
+// rpc.
+func keyRegistry(address, rpc string) (*proofs.KeyRegistry, error) {
+	if rpc == "" {
+		return nil, fmt.Errorf("-registry-rpc is required with -registry")
+	}
+	return &proofs.KeyRegistry{RPC: rpc, Address: address}, nil
+}
+
+// registryKeys returns the key pairs of dir with verifying keys and
+// circuit descriptions, restricted to the comma-separated names when set.
+func registryKeys(dir, names string) []proofs.KeyPair {
+	keys, err := proofs.ListKeys(dir)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	wanted := splitList(names)
+	var pairs []proofs.KeyPair
+	for _, pair := range keys {
+		if pair.VK == "" || len(wanted) > 0 && !slices.Contains(wanted, pair.Name) {
+			continue
+		}
+		if pair.Info == nil {
+			fmt.Printf("Skipping %s: no %s description names its circuit\n", pair.Name, proofs.KeyInfoSuffix)
+			continue
+		}
+		pairs = append(pairs, pair)
+	}
+	if len(pairs) == 0 {
+		fmt.Printf("Error: no verifying keys with circuit descriptions in %s\n", dir)
+		exit(exitNotFound)
+	}
+	return pairs
+}
+
+func handleKeysPublish(args []string) {
+	publishCmd := flag.NewFlagSet("keys publish", flag.ExitOnError)
+	dir := publishCmd.String("dir", envDefault(envKeyDir, "keys"), "Key directory whose verifying keys to publish")
+	names := publishCmd.String("type", "", "Comma-separated key names to publish (default: all)")
+	address := publishCmd.String("registry", envDefault(envRegistry, ""), "Address of the key registry contract")
+	rpc := publishCmd.String("registry-rpc", envDefault(envRegistryRPC, ""), "Ethereum JSON-RPC URL of a node of the chain the registry is deployed on")
+	from := publishCmd.String("from", "", "Registry owner account, unlocked on the node, to send the transactions from (default: print their calldata to send from a wallet)")
+	publishCmd.Parse(args)
+
+	if *address == "" {
+		fmt.Fprintf(os.Stderr, "Error: -registry is required\n\n")
+		publishCmd.Usage()
+		exit(exitBadInput)
+	}
+	pairs := registryKeys(*dir, *names)
+	var registry *proofs.KeyRegistry
+	if *from != "" {
+		var err error
+		if registry, err = keyRegistry(*address, *rpc); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
+	for _, pair := range pairs {
+		fmt.Printf("%s: circuit %s, verifying key %s\n", pair.Name, pair.Info.CircuitHash, pair.VK)
+		if registry == nil {
+			calldata, err := proofs.PublishCalldata(pair.Info.CircuitHash, pair.VK)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				exit(exitCode(err, 1))
+			}
+			fmt.Printf("  to %s data 0x%x\n", *address, calldata)
+			continue
+		}
+		tx, err := registry.Publish(cmdContext, *from, pair.Info.CircuitHash, pair.VK)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitCode(err, 1))
+		}
+		fmt.Printf("  sent transaction %s\n", tx)
+	}
+}
+
+func handleKeysCheck(args []string) {
+	checkCmd := flag.NewFlagSet("keys check", flag.ExitOnError)
+	dir := checkCmd.String("dir", envDefault(envKeyDir, "keys"), "Key directory whose verifying keys to check")
+	names := checkCmd.String("type", "", "Comma-separated key names to check (default: all)")
+	address := checkCmd.String("registry", envDefault(envRegistry, ""), "Address of the key registry contract")
+	rpc := checkCmd.String("registry-rpc", envDefault(envRegistryRPC, ""), "Ethereum JSON-RPC URL of a node of the chain the registry is deployed on")
+	checkCmd.Parse(args)
+
+	if *address == "" {
+		fmt.Fprintf(os.Stderr, "Error: -registry is required\n\n")
+		checkCmd.Usage()
+		exit(exitBadInput)
+	}
+	registry, err := keyRegistry(*address, *rpc)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+	failed := false
+	for _, pair := range registryKeys(*dir, *names) {
+		if err := registry.CheckVerifyingKey(cmdContext, pair.Info.CircuitHash, pair.VK); err != nil {
+			fmt.Printf("✗ %s: %v\n", pair.Name, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("✓ %s: verifying key %s is registered for circuit %s\n", pair.Name, pair.VK, pair.Info.CircuitHash)
+	}
+	if failed {
+		exit(exitVerification)
+	}
+}
+
+func handleKeysContract(args []string) {
+	contractCmd := flag.NewFlagSet("keys contract", flag.ExitOnError)
+	outputPath := contractCmd.String("out", "VerifyingKeyRegistry.sol", "Output path for the contract source")
+	contractCmd.Parse(args)
+
+	if err := os.WriteFile(*outputPath, []byte(proofs.KeyRegistryContract), 0644); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	fmt.Printf("Key registry contract saved to: %s\n", *outputPath)
+	fmt.Printf("Deploy it from the account that will publish keys, then run keys publish against its address\n")
+}
@@ -1,100 +1,575 @@
 package main
 
 import (
+	"context"
+	"encoding/hex"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/zkgenomics/vcf-proof-mvp/internal/proofs"
+	// Registers the s3:// and gs:// paths keys and proofs may be read
+	// from and written to.
+	_ "github.com/zkgenomics/vcf-proof-mvp/pkg/objectstore"
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
 )
 
+// cmdContext is cancelled on the first interrupt, stopping the command's
+// proving runs; a second interrupt kills the process.
+var cmdContext = context.Background()
+
 func main() {
-	if len(os.Args) < 2 {
+	var stop context.CancelFunc
+	cmdContext, stop = signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-cmdContext.Done()
+		stop()
+	}()
+
+	args := os.Args[1:]
+	if len(args) > 0 && (args[0] == "--json" || args[0] == "-json") {
+		jsonMode = true
+		args = args[1:]
+	}
+	if len(args) < 1 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	command := os.Args[1]
+	command := args[0]
+	if jsonMode {
+		if err := startJSON(command); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	switch command {
-	case "generate":
-		handleGenerate(os.Args[2:])
+	case "generate", "solve":
+		handleGenerate(command, args[1:])
 	case "verify":
-		handleVerify(os.Args[2:])
+		handleVerify(args[1:])
+	case "commit":
+		handleCommit(args[1:])
+	case "aggregate":
+		handleAggregate(args[1:])
+	case "keygen":
+		handleKeygen(args[1:])
+	case "attest":
+		handleAttest(args[1:])
+	case "import":
+		handleImport(args[1:])
+	case "validate":
+		handleValidate(args[1:])
+	case "check":
+		handleCheck(args[1:])
+	case "prove-panel":
+		handleProvePanel(args[1:])
+	case "panel":
+		handlePanel(args[1:])
+	case "extract":
+		handleExtract(args[1:])
+	case "setup":
+		handleSetup(args[1:])
+	case "keys":
+		handleKeys(args[1:])
+	case "inspect":
+		handleInspect(args[1:])
+	case "list-types":
+		handleListTypes(args[1:])
+	case "completion":
+		handleCompletion(args[1:])
+	case "export-verifier":
+		handleExportVerifier(args[1:])
+	case "export":
+		handleExport(args[1:])
+	case "prover":
+		handleProver(args[1:])
+	case "serve":
+		handleServe(args[1:])
+	case "bench":
+		handleBench(args[1:])
+	case "selftest":
+		handleSelfTest(args[1:])
+	case "version", "-version", "--version":
+		handleVersion(args[1:])
 	case "help", "-h", "--help":
 		printUsage()
 	default:
-		fmt.Printf("Unknown command: %s\n\n", command)
+		fmt.Printf("Error: unknown command: %s\n\n", command)
 		printUsage()
-		os.Exit(1)
+		exit(exitBadInput)
 	}
+	exit(0)
 }
 
-func handleGenerate(args []string) {
-	generateCmd := flag.NewFlagSet("generate", flag.ExitOnError)
-	proofType := generateCmd.String("type", "", "Type of proof to generate (chromosome, eyecolor, brca1)")
-	vcfPath := generateCmd.String("vcf", "", "Path to VCF file")
-	outputPath := generateCmd.String("output", "", "Output path for the proof file")
-	provingKeyPath := generateCmd.String("proving-key", "", "Path to existing proving key (optional)")
-	outputDir := generateCmd.String("output-dir", "output", "Output directory for proof files")
+// handleGenerate runs the generate command, or with command "solve" solves
+// the witness and delegates proving to a prover daemon.
+func handleGenerate(command string, args []string) {
+	generateCmd := flag.NewFlagSet(command, flag.ExitOnError)
+	proofType := generateCmd.String("type", "", "Type of proof to generate ("+proofTypeNames("cohort", "recursive")+"); comma-separate several to generate each from one read of the VCF, or all for every type needing no other options")
+	vcfPath := generateCmd.String("vcf", "", "Path to VCF file, plain or compressed with gzip or bgzip, or - to read it from stdin; comma-separate several to prove each sample")
+	indexPath := generateCmd.String("index", "", "Tabix (.tbi) or CSI (.csi) index of a bgzip-compressed -vcf, to read the proved loci without scanning the file (default: <vcf>.tbi or <vcf>.csi if present, else scan)")
+	liftoverPath := generateCmd.String("liftover", "", "UCSC chain file (e.g. hg19ToHg38.over.chain.gz) lifting GRCh37 panel positions onto the -vcf's build, for VCFs called against GRCh38")
+	maxRecords := generateCmd.Int("max-records", 0, "Fail once a scan of the VCF reads more than this many records (0 for no limit)")
+	maxLineBytes := generateCmd.Int("max-line-bytes", 0, "Fail on VCF lines longer than this many bytes, bounding the memory each record takes (0 for no limit)")
+	outputPath := generateCmd.String("output", "", "Output path for the proof file, or - to write it to stdout, with its metadata and keys under -output-dir")
+	provingKeyPath := generateCmd.String("proving-key", "", "Path to existing proving key; without it, <type>.pk in $"+envKeyDir+" when that holds one, else a key pair set up for the proof")
+	outputDir := generateCmd.String("output-dir", envDefault(envOutputDir, "output"), "Output directory for proof files")
+	imputationMode := generateCmd.String("imputation", "allow", "How to treat imputed genotype calls (allow, refuse, threshold)")
+	minImputedProb := generateCmd.Float64("min-imputed-prob", 0.9, "Minimum genotype probability for imputed calls when -imputation=threshold")
+	missingPolicy := generateCmd.String("missing", "fail", "How to treat no-calls (./.) and proved loci without a VCF record (fail: reject no-calls and read absent loci as homozygous reference; treat-as-missing: read no-calls as absent; require-coverage: reject both)")
+	requirePass := generateCmd.Bool("require-pass", false, "Only use records whose FILTER is PASS for the genotypes a proof reads")
+	filterMinDepth := generateCmd.Int("filter-min-dp", 0, "Only use records with at least this FORMAT/DP (or INFO/DP) for the genotypes a proof reads (0 for no minimum)")
+	filterMinAF := generateCmd.Float64("filter-min-af", 0, "Only use calls whose carried ALTs have at least this FORMAT/AF (or INFO/AF) for the genotypes a proof reads (0 for no minimum)")
+	locus := generateCmd.String("locus", "HTT", "Repeat locus ID for repeat proofs (HTT, FMR1, DMPK)")
+	repeatThreshold := generateCmd.Int("repeat-threshold", 0, "Pathogenic repeat count for repeat proofs (0 uses the locus default)")
+	gene := generateCmd.String("gene", "", "Gene for diplotype (CYP2C19, CYP2C9, TPMT), compoundhet (CFTR), region, sv, dosage, threshold and panel (panel gene) proofs")
+	snps := generateCmd.String("snps", "", "Comma-separated chrom:pos:ref:alt SNPs for dosage, threshold and panel proofs (default: the -panel entries)")
+	k := generateCmd.Int("k", 1, "Minimum number of panel variants present for threshold proofs")
+	variant := generateCmd.String("variant", "", "Variant as chrom:pos:ref:alt for somatic, carrier, membership, absence and haplotype proofs")
+	position := generateCmd.String("position", "", "Locus as chrom:pos for zygosity and wildtype proofs")
+	minQual := generateCmd.Int("min-qual", 0, "Minimum QUAL (GQ for gVCF reference blocks) the proven call must meet (zygosity, wildtype and somatic proofs)")
+	minDepth := generateCmd.Int("min-depth", 0, "Minimum FORMAT/DP the proven call must meet (zygosity, wildtype and somatic proofs)")
+	secondVariant := generateCmd.String("second-variant", "", "Variant whose phase relative to -variant a haplotype proof claims")
+	normalVCF := generateCmd.String("normal-vcf", "", "Matched normal VCF for somatic proofs (omit for paired tumor/normal VCFs)")
+	tumorSample := generateCmd.String("tumor-sample", "", "Tumor sample name in the VCF (default: first sample)")
+	normalSample := generateCmd.String("normal-sample", "", "Normal sample name in the VCF (default: first sample)")
+	sample := generateCmd.String("sample", "", "Sample of a multi-sample VCF whose calls are proved (default: first sample; somatic proofs use -tumor-sample and -normal-sample)")
+	maxVariants := generateCmd.Int("max-variants", proofs.DefaultMaxVariants, "Number of VCF records the chromosome circuit holds; larger VCFs are rejected")
+	target := generateCmd.Int("target", proofs.DefaultTargetChromosome, "Chromosome a chromosome proof shows is present in the VCF")
+	region := generateCmd.String("region", "", "Region as chrom:start-end for region and sv proofs (or use -gene with -panel); other proofs read only the VCF records overlapping it")
+	svType := generateCmd.String("sv-type", "", "Structural variant type (DEL, DUP) an sv proof claims (default: the first overlapping variant's)")
+	panelPath := generateCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel used to look up -gene regions for region and sv proofs and SNPs for dosage, threshold and panel proofs")
+	wholeLocus := generateCmd.Bool("whole-locus", false, "For absence proofs, claim no variant at all is carried at the locus")
+	treeDepth := generateCmd.Int("tree-depth", proofs.DefaultTreeDepth, "Depth of the variant Merkle tree for membership, absence, region, haplotype and panel proofs")
+	commitmentPath := generateCmd.String("commitment", "", "Commitment file from the commit command to bind the proof to (chromosome proofs)")
+	attestationPath := generateCmd.String("attestation", "", "Lab provenance attestation over the VCF to embed in the proof (optional)")
+	hashName := generateCmd.String("hash", "mimc", "Variant tree hash for membership, absence, region and panel proofs (mimc, poseidon2); must match commit")
+	backendName := generateCmd.String("backend", envDefault(envBackend, "groth16"), "Proof system (groth16, plonk); plonk derives keys from a universal KZG SRS instead of a per-circuit setup")
+	curveName := generateCmd.String("curve", "bn254", "Curve to prove over (bn254, bls12-381, bls12-377, bw6-761); use bls12-377 for proofs to aggregate recursively; proofs over committed hashes and holder nullifiers need bn254")
+	srsPath := generateCmd.String("srs", "", "KZG SRS file for -backend plonk, e.g. from keygen -srs (default: sample a development SRS)")
+	workers := generateCmd.Int("workers", 2, "Proofs generated at once when -type or -vcf lists several; each worker holds one circuit in memory")
+	acceleratorName := generateCmd.String("accelerator", "cpu", "Hardware to compute groth16 proofs over bn254 on (cpu, gpu); gpu needs a build with -tags icicle and falls back to cpu")
+	ccsCacheDir := generateCmd.String("ccs-cache-dir", "", "Directory to cache compiled circuits in, so later runs of the same circuit skip compilation (optional)")
+	keyCacheDir := generateCmd.String("key-cache-dir", "", "Directory to cache keys in by circuit hash, so setup runs once per circuit definition (optional)")
+	nonce := generateCmd.String("nonce", "", "Verifier-supplied challenge to bind into the proof as a public input (optional)")
+	timestamp := generateCmd.Bool("timestamp", false, "Bind the time the proof is issued into it as a public input")
+	validFor := generateCmd.Duration("valid-for", 0, "Bind an expiry this long after issuance into the proof, e.g. 720h; verify rejects the proof afterwards (implies -timestamp)")
+	digestMode := generateCmd.String("digest", "none", "Record the digest of the VCF's records in the proof (none, metadata, public); public also binds it as a public input")
+	manifestPath := generateCmd.String("manifest", "", "Manifest written by extract whose digest the VCF's records must match; the digest is recorded in the proof (optional)")
+	force := generateCmd.Bool("force", false, "Overwrite an existing proof at the output path and the keys set up beside it, which invalidates proofs issued against those keys")
+	dryRun := generateCmd.Bool("dry-run", false, "Only compile the circuit and estimate its constraints, proving key size, memory and time; nothing is set up or proved and -vcf is not read")
+	holderSecretPath := generateCmd.String("holder-secret", "", "Holder secret from keygen -holder; adds a public nullifier so reuse of the proof can be detected (optional)")
+	signKeyPath := generateCmd.String("sign-key", "", "Holder Ed25519 private key from keygen to sign the proof with, binding it to the holder's identity (optional)")
+	signDID := generateCmd.String("sign-did", "", "did:web identifier of the holder, whose DID document lists the -sign-key key, to record with the signature (default: the key's did:key)")
+	var proverKeyPath, submitURL *string
+	if command == "solve" {
+		proverKeyPath = generateCmd.String("prover-key", "", "Prover daemon public key from keygen -prover; the witness is encrypted to it")
+		submitURL = generateCmd.String("submit", "", "Prover daemon URL to submit the job to and wait for the proof (default: only write <output>"+proofs.JobSuffix+")")
+	}
 
 	generateCmd.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s generate [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [options]\n\n", os.Args[0], command)
+		if command == "solve" {
+			fmt.Fprintf(os.Stderr, "Solve a proof's witness locally and write it as an encrypted job for a prover daemon,\n")
+			fmt.Fprintf(os.Stderr, "for circuits too large to prove locally. The daemon decrypts the witness to prove it.\n\n")
+			fmt.Fprintf(os.Stderr, "Options:\n")
+			generateCmd.PrintDefaults()
+			fmt.Fprintf(os.Stderr, "\nExamples:\n")
+			fmt.Fprintf(os.Stderr, "  %s solve -type chromosome -vcf data/genome.vcf -prover-key keys/prover.pub\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  %s solve -type chromosome -vcf data/genome.vcf -prover-key keys/prover.pub -submit http://prover:8080\n", os.Args[0])
+			return
+		}
 		fmt.Fprintf(os.Stderr, "Generate a zero-knowledge proof from genomic data\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		generateCmd.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s generate -type chromosome -vcf data/genome.vcf -output-dir output\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type chromosome -vcf data/genome.vcf -max-variants 5000\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type chromosome -vcf data/genome.vcf -target 7\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s generate -type eyecolor -vcf data/genome.vcf -output my_proof.bin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type zygosity -vcf data/genome.vcf -position 15:28365618 -proving-key s3://keys/zygosity.pk -output gs://proofs/zygosity.bin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  bcftools view -r 15 data/genome.vcf.gz | %s generate -type zygosity -vcf - -position 15:28365618 -output - > proof.bin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type chromosome -max-variants 100000 -dry-run\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type bloodtype -vcf data/genome.vcf\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type chromosome -vcf data/array.vcf -imputation threshold -min-imputed-prob 0.95\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type dosage -vcf data/genome.g.vcf -gene APOE -missing require-coverage\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type brca1 -vcf data/genome.vcf -require-pass -filter-min-dp 20\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type brca1 -vcf data/panel.vcf -manifest data/panel.vcf.manifest.json -digest public\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type zygosity -vcf data/genome.vcf -position 15:28365618 -valid-for 720h\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type zygosity -vcf data/genome.vcf -position 15:28365618 -sign-key keys/me.key\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type repeat -vcf data/expansionhunter.vcf -locus HTT\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type diplotype -vcf data/phased.vcf -gene TPMT\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type compoundhet -vcf data/phased.vcf -gene CFTR\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type somatic -vcf data/tumor.vcf -normal-vcf data/normal.vcf -variant 17:7577120:C:T\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type membership -vcf data/genome.vcf -variant 17:41276045:CT:C\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type absence -vcf data/genome.vcf -variant 17:41276044:ACT:A -whole-locus\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type region -vcf data/genome.vcf -region 15:28356859-28567298\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type region -vcf data/genome.vcf -gene BRCA1 -panel panels_traits.json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type sv -vcf data/sv.vcf -gene BRCA1 -sv-type DEL\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type zygosity -vcf data/genome.vcf -position 15:28365618 -min-qual 30 -min-depth 10\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type wildtype -vcf data/genome.g.vcf -position 7:117199644 -min-qual 20 -min-depth 10\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type haplotype -vcf data/phased.vcf -variant 7:117199644:ATCT:A -second-variant 7:117227832:G:T\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type dosage -vcf data/genome.vcf -gene APOE -panel panels_traits.json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type dosage -vcf data/genome.vcf -snps 19:45411941:T:C,19:45412079:C:T\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type threshold -vcf data/genome.vcf -snps 7:117199644:ATCT:A,7:117227832:G:T,17:41276044:ACT:A -k 2\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type panel -vcf data/genome.vcf -gene CYP2C19 -panel panels_traits.json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type carrier -vcf data/genome.vcf -variant 7:117199644:ATCT:A\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type zygosity -vcf data/genome.vcf -position 15:28365618 -backend plonk -srs keys/bn254.srs\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type eyecolor -vcf data/genome.vcf -curve bls12-381\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type panel -vcf data/genome.vcf -gene CYP2C19 -accelerator gpu\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type eyecolor,bloodtype,brca1 -vcf data/a.vcf,data/b.vcf -workers 4\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type all -vcf data/genome.vcf.gz\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type zygosity -vcf data/genome.vcf.gz -index data/genome.vcf.gz.tbi -position chr15:28365618\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type eyecolor -vcf data/grch38.vcf -liftover hg19ToHg38.over.chain.gz\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type diplotype -vcf data/genome.vcf.gz -gene CYP2C19 -region 10:96500000-96620000\n", os.Args[0])
 	}
 
 	generateCmd.Parse(args)
 
-	if *proofType == "" || *vcfPath == "" {
+	if *proofType == "" || (*vcfPath == "" && !*dryRun) {
 		fmt.Fprintf(os.Stderr, "Error: -type and -vcf are required\n\n")
 		generateCmd.Usage()
-		os.Exit(1)
+		exit(exitBadInput)
+	}
+	if command == "solve" && *proverKeyPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -prover-key is required\n\n")
+		generateCmd.Usage()
+		exit(exitBadInput)
 	}
 
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(*outputDir, 0755); err != nil {
-		fmt.Printf("Error creating output directory: %v\n", err)
-		os.Exit(1)
+	// "-output -" keeps stdout for the proof; everything else printed
+	// goes to stderr.
+	var proofStdout *os.File
+	if *outputPath == stdinName && !*dryRun {
+		if jsonMode {
+			fmt.Fprintf(os.Stderr, "Error: -output - cannot be combined with -json, whose report is written to stdout\n\n")
+			generateCmd.Usage()
+			exit(exitBadInput)
+		}
+		if command == "solve" && *submitURL == "" {
+			fmt.Fprintf(os.Stderr, "Error: -output - needs -submit; solve alone writes no proof\n\n")
+			generateCmd.Usage()
+			exit(exitBadInput)
+		}
+		proofStdout = reserveStdout()
+	}
+
+	// Several types or VCFs generate every combination on a worker pool.
+	types, vcfs := expandTypes(splitList(*proofType)), splitList(*vcfPath)
+	batch := len(types) > 1 || len(vcfs) > 1
+	if len(types) == 1 {
+		*proofType = types[0]
+		if *provingKeyPath == "" {
+			*provingKeyPath = keyDirFile(*proofType, ".pk")
+		}
+	}
+	if batch && *outputPath != "" {
+		fmt.Fprintf(os.Stderr, "Error: -output names a single proof; use -output-dir with several types or VCFs\n\n")
+		generateCmd.Usage()
+		exit(exitBadInput)
+	}
+	if len(vcfs) > 1 && *indexPath != "" {
+		fmt.Fprintf(os.Stderr, "Error: -index names the index of a single VCF; indexes next to several VCFs are found on their own\n\n")
+		generateCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	// Create output directory if it doesn't exist; a bucket has none.
+	if !*dryRun && !proofs.IsStoragePath(*outputDir) {
+		if err := os.MkdirAll(*outputDir, 0755); err != nil {
+			fmt.Printf("Error creating output directory: %v\n", err)
+			exit(1)
+		}
 	}
 
-	// Set default output path if not specified
-	if *outputPath == "" {
+	// Set default output path if not specified; a proof written to stdout
+	// is generated there first, beside its metadata and keys.
+	if *outputPath == "" || proofStdout != nil {
 		*outputPath = filepath.Join(*outputDir, *proofType+"_proof.bin")
 	}
 
-	proof, err := createProof(*proofType)
+	mode, err := proofs.ParseImputationMode(*imputationMode)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		exit(exitBadInput)
+	}
+
+	missing, err := proofs.ParseMissingPolicy(*missingPolicy)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+
+	settings := proofs.Settings{
+		Imputation:  proofs.ImputationPolicy{Mode: mode},
+		Missing:     missing,
+		Filter:      proofs.CallFilter{RequirePass: *requirePass, MinDepth: *filterMinDepth, MinAlleleFrequency: *filterMinAF},
+		Sample:      *sample,
+		Nonce:       *nonce,
+		Timestamp:   *timestamp,
+		ValidFor:    *validFor,
+		Force:       *force,
+		Index:       *indexPath,
+		Limits:      proofs.ReadLimits{MaxRecords: *maxRecords, MaxLineBytes: *maxLineBytes},
+		CCSCacheDir: *ccsCacheDir,
+		KeyCacheDir: *keyCacheDir,
+	}
+	if hash, err := proofs.ParseHashAlgorithm(*hashName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	} else if hash != proofs.HashMiMC {
+		for _, t := range types {
+			switch strings.ToLower(t) {
+			case "membership", "absence", "region", "panel":
+				settings.Hash = hash
+			default:
+				fmt.Printf("Error: %s proofs only support the mimc hash\n", t)
+				exit(exitBadInput)
+			}
+		}
+	}
+	if backend, err := proofs.ParseBackend(*backendName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	} else if backend != proofs.BackendGroth16 {
+		settings.Backend = backend
+		settings.SRS = *srsPath
+	} else if *srsPath != "" {
+		fmt.Printf("Error: -srs requires -backend plonk\n")
+		exit(exitBadInput)
+	}
+	if curve, err := proofs.ParseCurve(*curveName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	} else if curve != proofs.CurveBN254 {
+		settings.Curve = curve
+	}
+	if settings.Accelerator, err = proofs.ParseAccelerator(*acceleratorName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+	if mode == proofs.ImputationThreshold {
+		settings.Imputation.MinProbability = *minImputedProb
+	}
+	if settings.Digest, err = proofs.ParseDigestMode(*digestMode); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+	if *manifestPath != "" {
+		manifest, err := proofs.ReadManifest(*manifestPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		settings.ExpectedDigest = manifest.Digest
+	}
+	if *liftoverPath != "" {
+		settings.Liftover, err = proofs.LoadLiftover(*liftoverPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
+	if *attestationPath != "" {
+		settings.Provenance, err = proofs.ReadProvenance(*attestationPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
+	if *holderSecretPath != "" {
+		settings.HolderSecret, err = proofs.ReadHolderSecret(*holderSecretPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
+	if *signKeyPath != "" {
+		settings.SignKey, err = proofs.ReadSigningKey(*signKeyPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
+	settings.SignDID = *signDID
+	if command == "solve" {
+		proverKey, err := proofs.ReadProverPublicKey(*proverKeyPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		settings.Delegate = &proofs.Delegation{ProverKey: proverKey, URL: strings.TrimSuffix(*submitURL, "/")}
+	}
+
+	if *region != "" {
+		// Proofs over the variant tree still read the whole VCF.
+		scan, err := proofs.ParseRegion(*region)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		settings.Region = &scan
+	}
+	if hasType(types, "region", "sv") && *region == "" && *gene != "" {
+		panel, err := proofs.LoadTraitPanel(*panelPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		geneRegion, err := proofs.GeneRegion(panel, *gene)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		*region = geneRegion.String()
+	}
+
+	var dosagePanel []proofs.VariantSpec
+	if hasType(types, "dosage", "threshold", "panel") {
+		dosagePanel, err = snpPanel(*snps, *panelPath, *gene)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
+
+	var commitment *proofs.DatasetCommitment
+	if *commitmentPath != "" {
+		commitment, err = proofs.ReadCommitment(*commitmentPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
+
+	cfg := proofs.ProofConfig{
+		Settings:        settings,
+		Commitment:      commitment,
+		MaxVariants:     *maxVariants,
+		Target:          *target,
+		TreeDepth:       *treeDepth,
+		WholeLocus:      *wholeLocus,
+		Region:          *region,
+		SVType:          *svType,
+		Panel:           dosagePanel,
+		K:               *k,
+		Locus:           *locus,
+		RepeatThreshold: *repeatThreshold,
+		Gene:            *gene,
+		Variant:         *variant,
+		Position:        *position,
+		Quality:         proofs.QualityThresholds{MinQuality: *minQual, MinDepth: *minDepth},
+		SecondVariant:   *secondVariant,
+		NormalVCF:       *normalVCF,
+		TumorSample:     *tumorSample,
+		NormalSample:    *normalSample,
+	}
+
+	if *dryRun {
+		estimateProofs(types, cfg, *provingKeyPath)
+		return
+	}
+
+	// A VCF on stdin is buffered, as proofs read their VCF more than once.
+	vcfName, fromStdin := *vcfPath, false
+	for i, vcf := range vcfs {
+		if vcf != stdinName {
+			continue
+		}
+		if fromStdin {
+			fmt.Printf("Error: stdin can only be read as one -vcf\n")
+			exit(exitBadInput)
+		}
+		if vcfs[i], err = stdinVCF(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitVCF)
+		}
+		vcfName, fromStdin = "stdin", true
+	}
+	if batch {
+		generateBatch(types, vcfs, cfg, *provingKeyPath, *outputDir, *workers)
+		return
+	}
+	*vcfPath = vcfs[0]
+
+	proof, err := proofs.NewProof(*proofType, cfg)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
 	}
 
 	fmt.Printf("Generating %s proof...\n", *proofType)
-	fmt.Printf("VCF file: %s\n", *vcfPath)
+	fmt.Printf("VCF file: %s\n", vcfName)
 	fmt.Printf("Output path: %s\n", *outputPath)
 	if *provingKeyPath != "" {
 		fmt.Printf("Using proving key: %s\n", *provingKeyPath)
 	}
 
-	if err := proof.Generate(*vcfPath, *provingKeyPath, *outputPath); err != nil {
+	start := time.Now()
+	err = proof.Generate(cmdContext, *vcfPath, *provingKeyPath, *outputPath)
+	reportProof(*proofType, vcfName, *outputPath, *provingKeyPath, time.Since(start), err)
+	if err != nil {
 		fmt.Printf("Error generating proof: %v\n", err)
-		os.Exit(1)
+		exit(exitCode(err, 1))
 	}
 
+	if settings.Delegate != nil && settings.Delegate.URL == "" {
+		fmt.Printf("Successfully solved %s witness; prove it with: %s prover -key <prover.key> -job %s%s -output %s\n", *proofType, os.Args[0], *outputPath, proofs.JobSuffix, *outputPath)
+		return
+	}
+	if proofStdout != nil {
+		if err := streamProof(proofStdout, *outputPath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Successfully generated %s proof to stdout; its metadata and keys are beside %s\n", *proofType, *outputPath)
+		return
+	}
 	fmt.Printf("Successfully generated %s proof at: %s\n", *proofType, *outputPath)
 }
 
 func handleVerify(args []string) {
 	verifyCmd := flag.NewFlagSet("verify", flag.ExitOnError)
-	proofType := verifyCmd.String("type", "", "Type of proof to verify (chromosome, eyecolor, brca1)")
+	proofType := verifyCmd.String("type", "", "Type of proof to verify, detected from the proof file when omitted ("+proofTypeNames()+")")
 	proofPath := verifyCmd.String("proof", "", "Path to proof file")
-	verifyingKeyPath := verifyCmd.String("verifying-key", "", "Path to verifying key file")
+	verifyingKeyPath := verifyCmd.String("verifying-key", "", "Path to verifying key file; without it, <type>.vk in $"+envKeyDir+" when that holds one, else the .vk beside the proof")
+	requireProvenance := verifyCmd.Bool("require-provenance", false, "Reject proofs without a lab provenance attestation")
+	trustedKeys := verifyCmd.String("trusted-lab-keys", "", "Comma-separated lab public keys (PEM) allowed to sign provenance")
+	accreditedLabs := verifyCmd.String("accredited-labs", "", "Comma-separated lab IDs accepted in provenance")
+	target := verifyCmd.Int("target", 0, "Chromosome a chromosome proof must show is present (optional)")
+	expectedCommitment := verifyCmd.String("commitment", "", "Dataset commitment the proof must be bound to (chromosome, membership, absence, region, haplotype and panel proofs), or for cohort proofs the noise seed commitment aggregate -commit-seed printed")
+	position := verifyCmd.String("position", "", "Locus as chrom:pos a zygosity or wildtype proof must be for (optional)")
+	variant := verifyCmd.String("variant", "", "Variant as chrom:pos:ref:alt a somatic, membership, absence, haplotype or cohort proof must be for (optional)")
+	secondVariant := verifyCmd.String("second-variant", "", "Variant as chrom:pos:ref:alt a haplotype proof must phase against -variant (optional)")
+	locus := verifyCmd.String("locus", "HTT", "Repeat locus ID a repeat proof must be for (HTT, FMR1, DMPK)")
+	repeatThreshold := verifyCmd.Int("repeat-threshold", 0, "Pathogenic repeat count a repeat proof's threshold must not exceed (0 uses the locus default)")
+	gene := verifyCmd.String("gene", "", "Gene a diplotype or compoundhet proof must be for, or whose -panel entries a dosage, threshold or panel proof must cover (optional)")
+	snps := verifyCmd.String("snps", "", "Comma-separated chrom:pos:ref:alt SNPs a dosage, threshold or panel proof must cover (optional)")
+	eyeColor := verifyCmd.String("eye-color", "", "Eye color (brown, intermediate, blue) an irisplex proof must predict (optional)")
+	epsilon := verifyCmd.Float64("epsilon", 0, "Privacy budget a cohort proof must spend (optional)")
+	k := verifyCmd.Int("k", 0, "Minimum number of panel variants a threshold proof must be for (optional)")
+	panelPath := verifyCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel holding the -gene entries a panel proof must cover")
+	minQual := verifyCmd.Int("min-qual", 0, "Minimum QUAL a zygosity, wildtype or somatic proof must enforce")
+	minDepth := verifyCmd.Int("min-depth", 0, "Minimum FORMAT/DP a zygosity, wildtype or somatic proof must enforce")
+	region := verifyCmd.String("region", "", "Region as chrom:start-end a region or sv proof must be for (optional)")
+	svType := verifyCmd.String("sv-type", "", "Structural variant type (DEL, DUP) an sv proof must be for (optional)")
+	nonce := verifyCmd.String("nonce", "", "Challenge the proof must be bound to; rejects stale proofs (optional)")
+	ignoreExpiry := verifyCmd.Bool("ignore-expiry", false, "Accept a proof past the expiry time bound into it")
+	datasetDigest := verifyCmd.String("dataset-digest", "", "Digest of the VCF records the proof must have been generated from, or the path of an extract manifest holding it (optional)")
+	nullifierLog := verifyCmd.String("nullifier-log", "", "File of nullifiers already seen; rejects a replayed proof and records new nullifiers (optional)")
+	holderKeyPath := verifyCmd.String("holder-key", "", "Holder public key (PEM) the proof must be signed with (optional)")
+	holderDID := verifyCmd.String("holder-did", "", "did:key or did:web identifier of the holder; the proof must be signed with a key its DID document lists (optional)")
+	registryAddress := verifyCmd.String("registry", envDefault(envRegistry, ""), "Address of an on-chain verifying key registry; the verifying key must be the one it holds for the proof's circuit (optional)")
+	registryRPC := verifyCmd.String("registry-rpc", envDefault(envRegistryRPC, ""), "Ethereum JSON-RPC URL of a node of the chain the -registry is deployed on")
+	keyDID := verifyCmd.String("key-did", "", "did:web identifier whose DID document publishes the verifying key; the key must match it and is fetched from there when none is beside the proof (optional)")
+	sequencedAfter := verifyCmd.String("sequenced-after", "", "Reject genomes sequenced before this date (YYYY-MM-DD)")
+	ipfsGateway := verifyCmd.String("ipfs-gateway", envDefault(envIPFSGateway, ""), "IPFS gateway, such as https://ipfs.io, to fetch the verifying key an envelope names by CID from when none is beside it (optional)")
+	batchDir := verifyCmd.String("batch", "", "Directory of proofs of one circuit to verify together against -verifying-key; -type filters by proof type (optional)")
 
 	verifyCmd.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s verify [options]\n\n", os.Args[0])
@@ -104,82 +579,438 @@ func handleVerify(args []string) {
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s verify -type chromosome -proof output/chromosome_proof.bin -verifying-key output/chromosome_proof.bin.vk\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s verify -type eyecolor -proof my_proof.bin -verifying-key my_proof.bin.vk\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -proof my_proof.bin\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -type zygosity -proof my_proof.bin -nonce 7f3a9c -nullifier-log seen.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -type zygosity -proof my_proof.bin -holder-key keys/me.pub\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -proof my_proof.bin -registry 0x5FbDB2315678afecb367f032d93F642f64180aa3 -registry-rpc https://rpc.example.org\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -proof my_proof.bin -key-did did:web:keys.example.org -holder-did did:web:alice.example.org\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -type brca1 -proof my_proof.bin -dataset-digest data/panel.vcf.manifest.json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -type zygosity -proof old_proof.bin -ignore-expiry\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -proof my_proof.bin.json -verifying-key my_proof.bin.vk\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -proof my_proof.bin.json -ipfs-gateway https://ipfs.io\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -batch proofs/ -type zygosity -verifying-key zygosity.vk\n", os.Args[0])
 	}
 
 	verifyCmd.Parse(args)
 
-	if *proofType == "" || *proofPath == "" {
-		// Try to auto-detect verifying key path if not provided
-		if *verifyingKeyPath == "" && *proofPath != "" {
-			*verifyingKeyPath = *proofPath + ".vk"
+	if *batchDir != "" {
+		if *verifyingKeyPath == "" {
+			*verifyingKeyPath = keyDirFile(*proofType, ".vk")
 		}
-
-		if *proofType == "" || *proofPath == "" {
-			fmt.Fprintf(os.Stderr, "Error: -type and -proof are required\n\n")
+		if *verifyingKeyPath == "" {
+			fmt.Fprintf(os.Stderr, "Error: -verifying-key is required with -batch\n\n")
 			verifyCmd.Usage()
-			os.Exit(1)
+			exit(exitBadInput)
 		}
+		verifyBatch(*batchDir, *proofType, *verifyingKeyPath, *ignoreExpiry)
+		return
+	}
+
+	if *proofPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -proof is required\n\n")
+		verifyCmd.Usage()
+		exit(exitBadInput)
 	}
 
-	// Auto-detect verifying key path if not provided
+	// An exported envelope is verified as the proof file it was exported
+	// from, with the verifying key beside that file by default.
+	envelopePath := ""
+	if proofs.IsProofEnvelope(*proofPath) {
+		unpacked, err := envelopeProof(*proofPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitCode(err, exitBadInput))
+		}
+		envelopePath, *proofPath = *proofPath, unpacked
+	}
+
+	// The proof file names its type; a -type that disagrees would only
+	// fail verification.
+	if *proofType == "" {
+		detected, err := proofs.DetectProofType(*proofPath)
+		if err != nil {
+			fmt.Printf("Error: cannot detect the proof type: %v\n", err)
+			exit(exitBadInput)
+		}
+		*proofType = detected
+		fmt.Printf("Detected proof type: %s\n", detected)
+	} else if err := proofs.CheckProofType(*proofPath, *proofType); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+
+	// Without -verifying-key, the key of the proof's type in the key
+	// directory wins over the one beside the proof or envelope.
+	if *verifyingKeyPath == "" {
+		*verifyingKeyPath = keyDirFile(*proofType, ".vk")
+	}
+	if *verifyingKeyPath == "" && envelopePath != "" {
+		*verifyingKeyPath = strings.TrimSuffix(envelopePath, proofs.EnvelopeSuffix) + ".vk"
+		if _, err := os.Stat(*verifyingKeyPath); err != nil && *ipfsGateway != "" {
+			fetched, err := envelopeKey(envelopePath, *ipfsGateway)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				exit(exitCode(err, 1))
+			}
+			*verifyingKeyPath = fetched
+		}
+	}
 	if *verifyingKeyPath == "" {
 		*verifyingKeyPath = *proofPath + ".vk"
 	}
+	// A key DID decides which verifying key is trusted: a local key must be
+	// the one it publishes, and without one the key is fetched from it.
+	if *keyDID != "" {
+		if _, err := os.Stat(*verifyingKeyPath); err != nil {
+			fetched, err := didKey(*keyDID, *proofType)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				exit(exitCode(err, 1))
+			}
+			*verifyingKeyPath = fetched
+		}
+		fingerprint, err := proofs.KeyFingerprint(*verifyingKeyPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		if err := (&proofs.DIDResolver{}).CheckVerifyingKey(cmdContext, *keyDID, *proofType, fingerprint); err != nil {
+			fmt.Printf("✗ Verifying key check failed: %v\n", err)
+			exit(exitCode(err, 1))
+		}
+		fmt.Printf("Verifying key is the one %s publishes\n", *keyDID)
+	}
+	// An on-chain registry pins each circuit to one verifying key, so a key
+	// that merely sits beside the proof is not trusted.
+	if *registryAddress != "" {
+		registry, err := keyRegistry(*registryAddress, *registryRPC)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		header, err := proofs.ReadProofHeader(*proofPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		fingerprint, err := proofs.KeyFingerprint(*verifyingKeyPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		if err := registry.CheckVerifyingKey(cmdContext, header.CircuitHash, fingerprint); err != nil {
+			fmt.Printf("✗ Key registry check failed: %v\n", err)
+			exit(exitCode(err, 1))
+		}
+		fmt.Printf("Verifying key is the one registry %s holds for circuit %s\n", *registryAddress, header.CircuitHash)
+	}
+
+	var panel []proofs.VariantSpec
+	if hasType([]string{*proofType}, "dosage", "threshold", "panel") && (*snps != "" || *gene != "") {
+		var err error
+		panel, err = snpPanel(*snps, *panelPath, *gene)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
 
-	proof, err := createProof(*proofType)
+	proof, err := proofs.NewProof(*proofType, proofs.ProofConfig{
+		ExpectedCommitment: *expectedCommitment,
+		Target:             *target,
+		Panel:              panel,
+		K:                  *k,
+		Epsilon:            *epsilon,
+		EyeColor:           *eyeColor,
+		Region:             *region,
+		SVType:             *svType,
+		Position:           *position,
+		Variant:            *variant,
+		SecondVariant:      *secondVariant,
+		Locus:              *locus,
+		RepeatThreshold:    *repeatThreshold,
+		Gene:               *gene,
+		Quality:            proofs.QualityThresholds{MinQuality: *minQual, MinDepth: *minDepth},
+	})
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		exit(exitBadInput)
 	}
 
 	fmt.Printf("Verifying %s proof...\n", *proofType)
 	fmt.Printf("Proof file: %s\n", *proofPath)
 	fmt.Printf("Verifying key: %s\n", *verifyingKeyPath)
 
-	verified, err := proof.Verify(*verifyingKeyPath, *proofPath)
+	verified, err := proof.Verify(cmdContext, *verifyingKeyPath, *proofPath)
 	if err != nil {
+		reportVerification(*proofType, *proofPath, *verifyingKeyPath, verified, err)
 		fmt.Printf("Error verifying proof: %v\n", err)
-		os.Exit(1)
+		exit(exitCode(err, 1))
+	}
+	// A check after the proof's own can still reject it, so the report
+	// records the proof once every check has run.
+	reject := func(code int, err error) {
+		reportVerification(*proofType, *proofPath, *verifyingKeyPath, false, err)
+		exit(code)
+	}
+
+	policy, err := provenancePolicy(*requireProvenance, *trustedKeys, *accreditedLabs, *sequencedAfter)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		reject(exitBadInput, err)
+	}
+
+	meta, err := proofs.ReadMetadata(*proofPath)
+	if err == nil {
+		fmt.Printf("Imputation policy: %s", meta.Imputation.Mode)
+		if meta.Imputation.Mode == proofs.ImputationThreshold {
+			fmt.Printf(" (min probability %g)", meta.Imputation.MinProbability)
+		}
+		fmt.Println()
+		if meta.Missing != "" {
+			fmt.Printf("Missing genotype policy: %s\n", meta.Missing)
+		}
+		if meta.Filter != nil {
+			fmt.Printf("Call filter: %s\n", meta.Filter)
+		}
+		if meta.Hash != "" {
+			fmt.Printf("Variant tree hash: %s\n", meta.Hash)
+		}
+		if meta.DatasetDigest != "" {
+			fmt.Printf("Dataset digest: %s", meta.DatasetDigest)
+			if meta.DigestBound {
+				fmt.Printf(" (bound into the proof)")
+			}
+			fmt.Println()
+		}
+		fmt.Printf("Backend: %s over %s\n", meta.Backend, meta.Curve)
+	} else {
+		meta = &proofs.ProofMetadata{}
+	}
+
+	if err := proofs.CheckProvenance(meta, policy); err != nil {
+		fmt.Printf("✗ Provenance check failed: %v\n", err)
+		reject(exitVerification, err)
+	}
+	if meta.Provenance != nil {
+		st := meta.Provenance.Statement
+		fmt.Printf("Provenance: lab %s, %s, sequenced %s, pipeline %s\n",
+			st.LabID, st.Platform, st.SequencedAt.Format(time.DateOnly), st.PipelineVersion)
+	}
+
+	if *holderKeyPath != "" {
+		holderKey, err := proofs.ReadPublicKey(*holderKeyPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			reject(exitBadInput, err)
+		}
+		if err := proofs.CheckHolderSignature(*proofPath, holderKey); err != nil {
+			fmt.Printf("✗ Holder signature check failed: %v\n", err)
+			reject(exitVerification, err)
+		}
+	}
+	// A signature that is present must match the proof even when no holder
+	// key is required.
+	if meta.HolderSignature != nil {
+		signer, _, err := proofs.ProofSigner(*proofPath)
+		if err != nil {
+			fmt.Printf("✗ Holder signature check failed: %v\n", err)
+			reject(exitVerification, err)
+		}
+		fmt.Printf("Signed by holder key: %s\n", hex.EncodeToString(signer))
+		if meta.HolderSignature.DID != "" {
+			fmt.Printf("Holder DID: %s\n", meta.HolderSignature.DID)
+		}
+	}
+	if *holderDID != "" {
+		if err := (&proofs.DIDResolver{}).CheckSigner(cmdContext, *proofPath, *holderDID); err != nil {
+			fmt.Printf("✗ Holder DID check failed: %v\n", err)
+			reject(exitCode(err, exitVerification), err)
+		}
+		fmt.Printf("Signed with a key of %s\n", *holderDID)
+	}
+
+	if *nonce != "" {
+		if err := proofs.CheckNonce(*proofPath, *nonce); err != nil {
+			fmt.Printf("✗ Nonce check failed: %v\n", err)
+			reject(exitVerification, err)
+		}
+		fmt.Println("Proof is bound to the expected nonce")
+	}
+
+	if err := proofs.CheckExpiry(*proofPath, time.Now()); err != nil {
+		if !*ignoreExpiry {
+			fmt.Printf("✗ Expiry check failed: %v\n", err)
+			reject(exitVerification, err)
+		}
+		fmt.Printf("Ignoring expiry: %v\n", err)
+	}
+	if meta.IssuedAt != nil {
+		fmt.Printf("Issued: %s\n", meta.IssuedAt.Format(time.RFC3339))
+	}
+	if meta.ExpiresAt != nil {
+		fmt.Printf("Valid until: %s\n", meta.ExpiresAt.Format(time.RFC3339))
+	}
+
+	if *datasetDigest != "" {
+		digest := *datasetDigest
+		if _, err := os.Stat(digest); err == nil {
+			manifest, err := proofs.ReadManifest(digest)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				reject(exitBadInput, err)
+			}
+			digest = manifest.Digest
+		}
+		bound, err := proofs.CheckDatasetDigest(*proofPath, digest)
+		if err != nil {
+			fmt.Printf("✗ Dataset digest check failed: %v\n", err)
+			reject(exitVerification, err)
+		}
+		if bound {
+			fmt.Println("Proof is bound to the expected dataset")
+		} else {
+			fmt.Println("Proof metadata records the expected dataset, but the digest is not bound into the proof")
+		}
+	}
+
+	nullifier, nullified, err := proofs.ProofNullifier(*proofPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		reject(1, err)
+	}
+	if nullified {
+		fmt.Printf("Nullifier: %s\n", nullifier)
+	}
+	if *nullifierLog != "" {
+		if !nullified {
+			fmt.Printf("✗ Proof has no nullifier to check against %s\n", *nullifierLog)
+			reject(exitVerification, fmt.Errorf("proof has no nullifier to check against %s", *nullifierLog))
+		}
+		if err := proofs.RecordNullifier(*nullifierLog, nullifier); err != nil {
+			fmt.Printf("✗ %v\n", err)
+			reject(exitVerification, err)
+		}
 	}
 
 	if verified {
+		reportVerification(*proofType, *proofPath, *verifyingKeyPath, true, nil)
 		fmt.Printf("✓ %s proof verified successfully!\n", strings.Title(*proofType))
 	} else {
 		fmt.Printf("✗ %s proof verification failed!\n", strings.Title(*proofType))
-		os.Exit(1)
+		reject(exitVerification, nil)
 	}
 }
 
-func createProof(proofType string) (proofs.Proof, error) {
-	switch strings.ToLower(proofType) {
-	case "chromosome":
-		return &proofs.ChromosomeProof{}, nil
-	case "eyecolor":
-		return &proofs.EyeColorProof{}, nil
-	case "brca1":
-		return &proofs.BRCA1Proof{}, nil
-	case "herc2":
-		return &proofs.HERC2Proof{}, nil
-	default:
-		return nil, fmt.Errorf("unknown proof type: %s. Supported types: chromosome, eyecolor, brca1", proofType)
+func provenancePolicy(required bool, trustedKeys, accreditedLabs, sequencedAfter string) (proofs.ProvenancePolicy, error) {
+	policy := proofs.ProvenancePolicy{Required: required}
+
+	if trustedKeys != "" {
+		for _, path := range strings.Split(trustedKeys, ",") {
+			key, err := proofs.ReadPublicKey(path)
+			if err != nil {
+				return policy, err
+			}
+			policy.TrustedKeys = append(policy.TrustedKeys, key)
+		}
+	}
+
+	if accreditedLabs != "" {
+		policy.AccreditedLabs = strings.Split(accreditedLabs, ",")
+	}
+
+	if sequencedAfter != "" {
+		t, err := time.Parse(time.DateOnly, sequencedAfter)
+		if err != nil {
+			return policy, fmt.Errorf("invalid -sequenced-after date: %w", err)
+		}
+		policy.SequencedAfter = t
 	}
+
+	// Any explicit requirement implies the proof must carry provenance.
+	policy.Required = policy.Required || len(policy.TrustedKeys) > 0 || len(policy.AccreditedLabs) > 0 || !policy.SequencedAfter.IsZero()
+	return policy, nil
+}
+
+// snpPanel returns the SNPs listed in snps, or the trait panel entries at
+// panelPath for gene (all entries when gene is empty).
+func snpPanel(snps, panelPath, gene string) ([]proofs.VariantSpec, error) {
+	if snps == "" {
+		panel, err := proofs.LoadTraitPanel(panelPath)
+		if err != nil {
+			return nil, err
+		}
+		return proofs.PanelVariants(panel, gene)
+	}
+
+	var panel []proofs.VariantSpec
+	for _, s := range strings.Split(snps, ",") {
+		v, err := proofs.ParseVariantSpec(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		panel = append(panel, v)
+	}
+	return panel, nil
 }
 
 func printUsage() {
 	fmt.Printf("VCF Proof CLI - Generate and verify zero-knowledge proofs for genomic data\n\n")
-	fmt.Printf("Usage: %s <command> [options]\n\n", os.Args[0])
+	fmt.Printf("Usage: %s [--json] <command> [options]\n\n", os.Args[0])
 	fmt.Printf("Commands:\n")
 	fmt.Printf("  generate    Generate a zero-knowledge proof from VCF data\n")
+	fmt.Printf("  solve       Solve a proof's witness and delegate proving to a prover daemon\n")
+	fmt.Printf("  prover      Run a prover daemon, or prove a single delegated job\n")
+	fmt.Printf("  serve       Serve an HTTP API generating and verifying proofs\n")
 	fmt.Printf("  verify      Verify a zero-knowledge proof\n")
+	fmt.Printf("  inspect     Print a proof's type, public inputs and key fingerprints without verifying it\n")
+	fmt.Printf("  validate    Check a VCF for problems before proving\n")
+	fmt.Printf("  check       Report which trait panel variants a VCF holds, their genotypes and whether they can be proved\n")
+	fmt.Printf("  prove-panel Prove every trait of a trait panel and write a report of the proofs\n")
+	fmt.Printf("  panel       List, show, add and remove trait panel entries\n")
+	fmt.Printf("  extract     Copy only the records proofs need out of a whole-genome VCF\n")
+	fmt.Printf("  setup       Generate a proof type's proving and verifying keys ahead of proving\n")
+	fmt.Printf("  keys        List, fingerprint, export, import and publish proving and verifying keys\n")
+	fmt.Printf("  commit      Commit to a VCF's private values ahead of proving\n")
+	fmt.Printf("  aggregate   Prove a private carrier count over carrier proofs, or aggregate proofs recursively\n")
+	fmt.Printf("  keygen      Generate an Ed25519 signing key pair, a prover key pair, a holder secret, an API key or a KZG SRS\n")
+	fmt.Printf("  attest      Sign a sequencing provenance statement over a VCF\n")
+	fmt.Printf("  import      Convert a 23andMe or AncestryDNA raw genotype export to a VCF\n")
+	fmt.Printf("  export-verifier  Export a Solidity verifier contract and calldata for a proof\n")
+	fmt.Printf("  export      Export a proof as ABI-encoded calldata for an on-chain verifier\n")
+	fmt.Printf("  bench       Benchmark each proof type's circuit on this machine\n")
+	fmt.Printf("  selftest    Set up, prove and verify every proof type over a built-in witness to check the install\n")
+	fmt.Printf("  list-types  List the proof types with the VCF fields they read and their public inputs\n")
+	fmt.Printf("  completion  Print a bash, zsh or fish completion script\n")
+	fmt.Printf("  version     Print the version, gnark versions and each proof type's circuit hash\n")
 	fmt.Printf("  help        Show this help message\n\n")
-	fmt.Printf("Supported proof types:\n")
-	fmt.Printf("  chromosome  Chromosome-based genomic proof\n")
-	fmt.Printf("  eyecolor    Eye color trait proof\n")
-	fmt.Printf("  brca1       BRCA1 gene mutation proof\n\n")
+	fmt.Printf("Supported proof types (see list-types for their inputs):\n")
+	for _, t := range proofs.ProofTypes() {
+		fmt.Printf("  %-11s %s\n", t.Name, t.Description)
+	}
+	fmt.Println()
 	fmt.Printf("Examples:\n")
 	fmt.Printf("  %s generate -type chromosome -vcf data/genome.vcf\n", os.Args[0])
 	fmt.Printf("  %s verify -type chromosome -proof output/chromosome_proof.bin\n", os.Args[0])
+	fmt.Printf("  %s --json verify -type chromosome -proof output/chromosome_proof.bin\n", os.Args[0])
 	fmt.Printf("  %s help\n\n", os.Args[0])
+	fmt.Printf("With --json, generate, verify, inspect and every other command print a single JSON\n")
+	fmt.Printf("report (status, error, duration, proof paths and public inputs) to stdout, and their\n")
+	fmt.Printf("progress messages to stderr.\n\n")
+	fmt.Printf("Environment:\n")
+	for _, v := range envVars {
+		fmt.Printf("  %-20s %s\n", v.name, v.flags)
+	}
+	fmt.Printf("A flag given on the command line wins over its variable, which wins over the\n")
+	fmt.Printf("built-in default; there is no configuration file.\n\n")
+	fmt.Printf("Exit codes:\n")
+	fmt.Printf("  0  success\n")
+	fmt.Printf("  1  any other failure\n")
+	fmt.Printf("  %d  invalid options or input files, or a proof type not supported yet\n", exitBadInput)
+	fmt.Printf("  %d  the VCF cannot be read or parsed\n", exitVCF)
+	fmt.Printf("  %d  the VCF does not hold the variant, genotype or trait a proof claims\n", exitNotFound)
+	fmt.Printf("  %d  compiling, setting up or proving the circuit failed\n", exitProving)
+	fmt.Printf("  %d  a proof did not verify or failed a verifier check\n\n", exitVerification)
 	fmt.Printf("For more detailed help on a specific command, use:\n")
 	fmt.Printf("  %s <command> -h\n", os.Args[0])
 }
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark/logger"
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// Exit codes say why a command failed, so scripts can branch on the cause.
+// Any other failure exits with 1.
+const (
+	// exitBadInput is an invalid option or input file; the flag package
+	// exits with it too.
+	exitBadInput = 2
+	// exitVCF is a VCF that cannot be read or parsed.
+	exitVCF = 3
+	// exitNotFound is a variant, genotype or trait the VCF does not hold.
+	exitNotFound = 4
+	// exitProving is a failure to compile, set up or prove a circuit.
+	exitProving = 5
+	// exitVerification is a proof that does not verify or fails a check.
+	exitVerification = 6
+)
+
+// errorKinds names the exit codes in JSON reports.
+var errorKinds = map[int]string{
+	exitBadInput:     "invalid_input",
+	exitVCF:          "vcf",
+	exitNotFound:     "not_found",
+	exitProving:      "proving",
+	exitVerification: "verification",
+}
+
+// exitCode returns the exit code for the kind of err, or fallback when err
+// has none.
+func exitCode(err error, fallback int) int {
+	switch proofs.ErrorKind(err) {
+	case proofs.ErrInvalidInput, proofs.ErrUnsupported:
+		return exitBadInput
+	case proofs.ErrVCF:
+		return exitVCF
+	case proofs.ErrNotFound:
+		return exitNotFound
+	case proofs.ErrProving:
+		return exitProving
+	case proofs.ErrVerification:
+		return exitVerification
+	}
+	return fallback
+}
+
+// batchExitCode returns the exit code the failed errs share, or 1 when
+// they failed for different reasons.
+func batchExitCode(errs []error) int {
+	code := 0
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if c := exitCode(err, 1); code == 0 {
+			code = c
+		} else if c != code {
+			return 1
+		}
+	}
+	return max(code, 1)
+}
+
+// jsonMode is set by the global --json flag. The command's progress and
+// error messages then go to stderr and stdout holds only the report.
+var jsonMode bool
+
+// report is what a command prints in JSON mode.
+type report struct {
+	Command string `json:"command"`
+	// Status is ok, failed when a proof or check was rejected, or error.
+	Status        string                `json:"status"`
+	Error         string                `json:"error,omitempty"`
+	ErrorKind     string                `json:"error_kind,omitempty"`
+	ExitCode      int                   `json:"exit_code"`
+	DurationMS    int64                 `json:"duration_ms"`
+	Proofs        []proofReport         `json:"proofs,omitempty"`
+	Verifications []verificationReport  `json:"verifications,omitempty"`
+	Summaries     []summaryReport       `json:"summaries,omitempty"`
+	Types         []proofs.ProofType    `json:"types,omitempty"`
+	Benchmarks    []benchReport         `json:"benchmarks,omitempty"`
+	Estimates     []estimateReport      `json:"estimates,omitempty"`
+	Checks        []proofs.TraitCheck   `json:"checks,omitempty"`
+	Panel         []proofs.TraitVariant `json:"panel,omitempty"`
+	Traits        []proofs.TraitResult  `json:"traits,omitempty"`
+	SelfTests     []selfTestReport      `json:"selftests,omitempty"`
+	Version       *versionReport        `json:"version,omitempty"`
+}
+
+// inputsReport is what a proof file states about its public inputs.
+type inputsReport struct {
+	PublicInputs []string `json:"public_inputs,omitempty"`
+	Nullifier    string   `json:"nullifier,omitempty"`
+	Nonce        string   `json:"nonce,omitempty"`
+	Digest       string   `json:"digest,omitempty"`
+	IssuedAt     string   `json:"issued_at,omitempty"`
+	ExpiresAt    string   `json:"expires_at,omitempty"`
+	Claims       []string `json:"claims,omitempty"`
+}
+
+// proofReport describes a proof generate wrote, or why it failed.
+type proofReport struct {
+	Type         string `json:"type"`
+	VCF          string `json:"vcf"`
+	Proof        string `json:"proof"`
+	Metadata     string `json:"metadata,omitempty"`
+	VerifyingKey string `json:"verifying_key,omitempty"`
+	DurationMS   int64  `json:"duration_ms,omitempty"`
+	Error        string `json:"error,omitempty"`
+	inputsReport
+}
+
+// verificationReport describes a proof verify checked.
+type verificationReport struct {
+	Type         string `json:"type,omitempty"`
+	Proof        string `json:"proof"`
+	VerifyingKey string `json:"verifying_key"`
+	Verified     bool   `json:"verified"`
+	Error        string `json:"error,omitempty"`
+	inputsReport
+}
+
+// summaryReport is the JSON form of inspect's summary of a proof.
+type summaryReport struct {
+	Proof    string                `json:"proof"`
+	Size     int64                 `json:"size"`
+	Header   *proofs.ProofHeader   `json:"header,omitempty"`
+	Metadata *proofs.ProofMetadata `json:"metadata,omitempty"`
+	inputsReport
+}
+
+var (
+	jsonReport  report
+	jsonStart   time.Time
+	jsonStdout  *os.File
+	jsonPipe    *os.File
+	jsonCopied  chan struct{}
+	jsonMessage string
+)
+
+// startJSON begins a command in JSON mode: stdout and stderr, including
+// gnark's log, are copied to stderr, and the last error or rejection line
+// printed becomes the report's error.
+func startJSON(command string) error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("redirecting output: %w", err)
+	}
+	jsonReport.Command = command
+	jsonStart = time.Now()
+	jsonStdout, jsonPipe, jsonCopied = os.Stdout, w, make(chan struct{})
+
+	stderr := os.Stderr
+	os.Stdout, os.Stderr = w, w
+	logger.SetOutput(w)
+
+	go func() {
+		defer close(jsonCopied)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			line := scanner.Text()
+			fmt.Fprintln(stderr, line)
+			if strings.HasPrefix(line, "Error") || strings.HasPrefix(line, "✗") {
+				jsonMessage = line
+			}
+		}
+	}()
+	return nil
+}
+
+// exit ends the command with code, printing the report first in JSON mode.
+func exit(code int) {
+	if jsonMode {
+		finishJSON(code)
+	}
+	for _, cleanup := range atExit {
+		cleanup()
+	}
+	os.Exit(code)
+}
+
+// finishJSON prints the report for a command ending with code.
+func finishJSON(code int) {
+	jsonPipe.Close()
+	<-jsonCopied
+
+	jsonReport.DurationMS = time.Since(jsonStart).Milliseconds()
+	jsonReport.Status = "ok"
+	jsonReport.ExitCode = code
+	jsonReport.ErrorKind = errorKinds[code]
+	if code != 0 {
+		jsonReport.Status = "error"
+		msg := jsonMessage
+		if rest, ok := strings.CutPrefix(msg, "✗"); ok {
+			jsonReport.Status = "failed"
+			msg = rest
+		} else {
+			msg = strings.TrimPrefix(msg, "Error: ")
+		}
+		jsonReport.Error = strings.TrimSpace(msg)
+		if jsonReport.Error == "" {
+			jsonReport.Error = fmt.Sprintf("%s exited with status %d", jsonReport.Command, code)
+		}
+	}
+
+	data, err := json.MarshalIndent(jsonReport, "", "  ")
+	if err != nil {
+		data = []byte(fmt.Sprintf(`{"command": %q, "status": "error", "error": %q}`, jsonReport.Command, err))
+	}
+	fmt.Fprintln(jsonStdout, string(data))
+}
+
+// describeInputs reads what the proof at proofPath states about its public
+// inputs, or nothing when it cannot be read.
+func describeInputs(proofPath string) inputsReport {
+	summary, err := proofs.InspectProof(proofPath)
+	if err != nil {
+		return inputsReport{}
+	}
+	return summaryInputs(summary)
+}
+
+func summaryInputs(summary *proofs.ProofSummary) inputsReport {
+	inputs := inputsReport{Claims: summary.Claims}
+	for _, input := range summary.Inputs {
+		inputs.PublicInputs = append(inputs.PublicInputs, input.String())
+	}
+	inputs.Nullifier = bigString(summary.Nullifier)
+	inputs.Nonce = bigString(summary.Nonce)
+	inputs.Digest = bigString(summary.Digest)
+	inputs.IssuedAt = unixTime(summary.Issued)
+	inputs.ExpiresAt = unixTime(summary.Expires)
+	return inputs
+}
+
+// unixTime formats a Unix time public input as RFC 3339.
+func unixTime(n *big.Int) string {
+	if n == nil {
+		return ""
+	}
+	return time.Unix(n.Int64(), 0).UTC().Format(time.RFC3339)
+}
+
+func bigString(n *big.Int) string {
+	if n == nil {
+		return ""
+	}
+	return n.String()
+}
+
+// reportProof adds a proof generate wrote to outputPath, or failed to, to
+// the report.
+func reportProof(proofType, vcfPath, outputPath, provingKeyPath string, elapsed time.Duration, err error) {
+	if !jsonMode {
+		return
+	}
+	r := proofReport{Type: proofType, VCF: vcfPath, Proof: outputPath, DurationMS: elapsed.Milliseconds()}
+	if err != nil {
+		r.Error = err.Error()
+		jsonReport.Proofs = append(jsonReport.Proofs, r)
+		return
+	}
+	if _, err := os.Stat(outputPath + proofs.MetadataSuffix); err == nil {
+		r.Metadata = outputPath + proofs.MetadataSuffix
+	}
+	vkPath := outputPath + ".vk"
+	if provingKeyPath != "" {
+		vkPath = strings.TrimSuffix(provingKeyPath, ".pk") + ".vk"
+	}
+	if _, err := os.Stat(vkPath); err == nil {
+		r.VerifyingKey = vkPath
+	}
+	r.inputsReport = describeInputs(outputPath)
+	jsonReport.Proofs = append(jsonReport.Proofs, r)
+}
+
+// reportVerification adds a proof verify checked to the report.
+func reportVerification(proofType, proofPath, verifyingKeyPath string, verified bool, err error) {
+	if !jsonMode {
+		return
+	}
+	r := verificationReport{Type: proofType, Proof: proofPath, VerifyingKey: verifyingKeyPath, Verified: verified}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	r.inputsReport = describeInputs(proofPath)
+	jsonReport.Verifications = append(jsonReport.Verifications, r)
+}
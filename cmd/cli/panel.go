@@ -0,0 +1,225 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handlePanel(args []string) {
+	if len(args) == 0 {
+		printPanelUsage()
+		exit(exitBadInput)
+	}
+
+	switch args[0] {
+	case "list":
+		handlePanelList(args[1:])
+	case "show":
+		handlePanelShow(args[1:])
+	case "add":
+		handlePanelAdd(args[1:])
+	case "remove":
+		handlePanelRemove(args[1:])
+	case "help", "-h", "--help":
+		printPanelUsage()
+	default:
+		fmt.Printf("Unknown panel command: %s\n\n", args[0])
+		printPanelUsage()
+		exit(exitBadInput)
+	}
+}
+
+func printPanelUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s panel <command> [options]\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Manage the entries of a trait panel such as panels_traits.json\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "  list    List the panel's traits and their variants\n")
+	fmt.Fprintf(os.Stderr, "  show    Print every field of the entries a trait, gene, rsID or variant names\n")
+	fmt.Fprintf(os.Stderr, "  add     Add an entry after checking it against the panel schema\n")
+	fmt.Fprintf(os.Stderr, "  remove  Remove the entry a trait, gene, rsID or variant names\n")
+	fmt.Fprintf(os.Stderr, "\nExamples:\n")
+	fmt.Fprintf(os.Stderr, "  %s panel list\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s panel show APOE\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s panel add -trait \"Lactase Persistence\" -gene MCM6 -rsid rs4988235 -chrom 2 -pos 136608646 -ref G -alt A\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s panel remove rs4988235\n", os.Args[0])
+}
+
+// loadPanel reads the panel at path, exiting on failure.
+func loadPanel(path string) []proofs.TraitVariant {
+	panel, err := proofs.LoadTraitPanel(path)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+	return panel
+}
+
+func printTrait(t proofs.TraitVariant) {
+	fmt.Printf("%s\n", t.Trait)
+	fmt.Printf("  Gene:    %s\n", t.Gene)
+	if t.RSID != "" {
+		fmt.Printf("  rsID:    %s\n", t.RSID)
+	}
+	fmt.Printf("  Variant: %s\n", t.Spec())
+	fmt.Printf("  Region:  %d:%d-%d\n", t.Chromosome, t.Region.Start, t.Region.End)
+}
+
+func handlePanelList(args []string) {
+	listCmd := flag.NewFlagSet("panel list", flag.ExitOnError)
+	panelPath := listCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel to list")
+	gene := listCmd.String("gene", "", "Only list this gene's entries")
+	listCmd.Parse(args)
+
+	panel := loadPanel(*panelPath)
+	listed := 0
+	for _, t := range panel {
+		if *gene != "" && !strings.EqualFold(t.Gene, *gene) {
+			continue
+		}
+		fmt.Printf("%-40s %-10s %-12s %s\n", t.Trait, t.Gene, t.RSID, t.Spec())
+		jsonReport.Panel = append(jsonReport.Panel, t)
+		listed++
+	}
+	fmt.Printf("\n%d of %d entries in %s\n", listed, len(panel), *panelPath)
+	if err := proofs.ValidateTraitPanel(panel); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
+
+func handlePanelShow(args []string) {
+	showCmd := flag.NewFlagSet("panel show", flag.ExitOnError)
+	panelPath := showCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel to read")
+	showCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s panel show [options] <trait|gene|rsid|chrom:pos[:ref:alt]>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		showCmd.PrintDefaults()
+	}
+	showCmd.Parse(args)
+
+	if showCmd.NArg() != 1 {
+		showCmd.Usage()
+		exit(exitBadInput)
+	}
+	query := showCmd.Arg(0)
+	shown := 0
+	for _, t := range loadPanel(*panelPath) {
+		if !t.Matches(query) {
+			continue
+		}
+		if shown > 0 {
+			fmt.Println()
+		}
+		printTrait(t)
+		jsonReport.Panel = append(jsonReport.Panel, t)
+		shown++
+	}
+	if shown == 0 {
+		fmt.Printf("Error: no entry in %s matches %s\n", *panelPath, query)
+		exit(exitNotFound)
+	}
+}
+
+func handlePanelAdd(args []string) {
+	addCmd := flag.NewFlagSet("panel add", flag.ExitOnError)
+	panelPath := addCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel to add the entry to")
+	trait := addCmd.String("trait", "", "Trait name")
+	gene := addCmd.String("gene", "", "Gene the variant lies in")
+	rsid := addCmd.String("rsid", "", "dbSNP ID of the variant (optional)")
+	chrom := addCmd.Int("chrom", 0, "Autosome the variant lies on (1-22)")
+	pos := addCmd.Int("pos", 0, "Position of the variant on GRCh37")
+	region := addCmd.String("region", "", "Gene region as start-end on -chrom, used by region and sv proofs (default: the position alone)")
+	ref := addCmd.String("ref", "", "Reference allele")
+	alt := addCmd.String("alt", "", "Alternate allele")
+	addCmd.Parse(args)
+
+	entry := proofs.TraitVariant{
+		Trait:      *trait,
+		Gene:       *gene,
+		RSID:       *rsid,
+		Chromosome: *chrom,
+		Position:   *pos,
+		Region:     proofs.TraitRegion{Start: *pos, End: *pos},
+		Ref:        strings.ToUpper(*ref),
+		Alt:        strings.ToUpper(*alt),
+	}
+	if *region != "" {
+		start, end, ok := strings.Cut(*region, "-")
+		var err error
+		if ok {
+			if entry.Region.Start, err = strconv.Atoi(start); err == nil {
+				entry.Region.End, err = strconv.Atoi(end)
+			}
+		}
+		if !ok || err != nil {
+			fmt.Printf("Error: invalid region %q: expected start-end\n", *region)
+			exit(exitBadInput)
+		}
+	}
+	if err := entry.Validate(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+
+	panel := append(loadPanel(*panelPath), entry)
+	if err := proofs.WriteTraitPanel(*panelPath, panel); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitCode(err, 1))
+	}
+	printTrait(entry)
+	jsonReport.Panel = append(jsonReport.Panel, entry)
+	fmt.Printf("\nAdded to %s (%d entries)\n", *panelPath, len(panel))
+}
+
+func handlePanelRemove(args []string) {
+	removeCmd := flag.NewFlagSet("panel remove", flag.ExitOnError)
+	panelPath := removeCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel to remove the entry from")
+	all := removeCmd.Bool("all", false, "Remove every entry the query matches, such as all of a gene's")
+	removeCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s panel remove [options] <trait|gene|rsid|chrom:pos[:ref:alt]>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		removeCmd.PrintDefaults()
+	}
+	removeCmd.Parse(args)
+
+	if removeCmd.NArg() != 1 {
+		removeCmd.Usage()
+		exit(exitBadInput)
+	}
+	query := removeCmd.Arg(0)
+
+	panel := loadPanel(*panelPath)
+	var kept, removed []proofs.TraitVariant
+	for _, t := range panel {
+		if t.Matches(query) {
+			removed = append(removed, t)
+		} else {
+			kept = append(kept, t)
+		}
+	}
+	switch {
+	case len(removed) == 0:
+		fmt.Printf("Error: no entry in %s matches %s\n", *panelPath, query)
+		exit(exitNotFound)
+	case len(removed) > 1 && !*all:
+		for _, t := range removed {
+			fmt.Printf("  %s (%s) at %s\n", t.Trait, t.Gene, t.Spec())
+		}
+		fmt.Printf("Error: %d entries match %s; name one by rsID or variant, or pass -all to remove them all\n", len(removed), query)
+		exit(exitBadInput)
+	}
+
+	if err := proofs.WriteTraitPanel(*panelPath, kept); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitCode(err, 1))
+	}
+	for _, t := range removed {
+		fmt.Printf("Removed %s (%s) at %s\n", t.Trait, t.Gene, t.Spec())
+	}
+	jsonReport.Panel = removed
+	fmt.Printf("%d entries left in %s\n", len(kept), *panelPath)
+}
@@ -0,0 +1,8 @@
+package main
+
+// Proof types other packages register with proofs.Register,
+// proofs.RegisterProofType or proofs.RegisterTrait are available to every command, listed by
+// list-types and offered by completion, once their package is imported
+// here for its side effects, e.g.:
+//
+//	import _ "example.org/hospital/panelproof"
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// generateBatch generates a proof of every type for every VCF on a pool of
+// workers and prints a per-proof report. With several VCFs each sample's
+// proofs go to a subdirectory of outputDir named after its VCF. With
+// several types, the genotypes they read are extracted from each VCF once.
+func generateBatch(types []string, vcfs []string, cfg proofs.ProofConfig, provingKeyPath string, outputDir string, workers int) {
+	if len(types) > 1 {
+		cfg.ShareExtraction()
+	}
+	var jobs []proofs.GenerateJob
+	for _, vcf := range vcfs {
+		dir := outputDir
+		if len(vcfs) > 1 {
+			dir = filepath.Join(outputDir, sampleName(vcf))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				fmt.Printf("Error creating output directory: %v\n", err)
+				exit(1)
+			}
+		}
+		for _, t := range types {
+			proof, err := proofs.NewProof(t, cfg)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				exit(1)
+			}
+			jobs = append(jobs, proofs.GenerateJob{
+				Proof:      proof,
+				VCF:        vcf,
+				ProvingKey: cmp.Or(provingKeyPath, keyDirFile(t, ".pk")),
+				Output:     filepath.Join(dir, t+"_proof.bin"),
+			})
+		}
+	}
+
+	fmt.Printf("Generating %d proofs with %d workers...\n", len(jobs), workers)
+	errs, err := proofs.GenerateAll(cmdContext, jobs, workers)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	failed := 0
+	for i, job := range jobs {
+		reportProof(types[i%len(types)], job.VCF, job.Output, job.ProvingKey, 0, errs[i])
+		if errs[i] != nil {
+			failed++
+			fmt.Printf("FAIL %s (%s): %v\n", job.Output, job.VCF, errs[i])
+			continue
+		}
+		fmt.Printf("OK   %s\n", job.Output)
+	}
+	if failed > 0 {
+		fmt.Printf("✗ %d of %d proofs failed\n", failed, len(jobs))
+		exit(batchExitCode(errs))
+	}
+	fmt.Printf("Successfully generated %d proofs in: %s\n", len(jobs), outputDir)
+}
+
+// sampleName names a VCF's output directory after its file name, without
+// the .vcf and .gz extensions.
+func sampleName(vcfPath string) string {
+	name := strings.TrimSuffix(filepath.Base(vcfPath), ".gz")
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// expandTypes replaces "all" in a -type list with the standalone proof
+// types, dropping types listed twice.
+func expandTypes(types []string) []string {
+	var expanded []string
+	for _, t := range types {
+		names := []string{t}
+		if strings.EqualFold(t, "all") {
+			names = proofs.StandaloneProofTypes()
+		}
+		for _, name := range names {
+			if !hasType(expanded, name) {
+				expanded = append(expanded, name)
+			}
+		}
+	}
+	return expanded
+}
+
+// hasType reports whether types includes any of names.
+func hasType(types []string, names ...string) bool {
+	for _, t := range types {
+		for _, name := range names {
+			if strings.EqualFold(t, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
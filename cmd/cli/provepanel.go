@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleProvePanel(args []string) {
+	provePanelCmd := flag.NewFlagSet("prove-panel", flag.ExitOnError)
+	vcfPath := provePanelCmd.String("vcf", "", "Path to VCF file, plain or compressed with gzip or bgzip, or - to read it from stdin")
+	panelPath := provePanelCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel whose traits to prove")
+	gene := provePanelCmd.String("gene", "", "Only prove this gene's traits")
+	proofType := provePanelCmd.String("type", "threshold", "Proof per trait: threshold proves only whether the trait's alt is carried, dosage how many copies are")
+	outputDir := provePanelCmd.String("output-dir", envDefault(envOutputDir, "output"), "Output directory for the proofs and "+proofs.TraitReportName)
+	workers := provePanelCmd.Int("workers", 2, "Number of proofs to generate at once")
+	force := provePanelCmd.Bool("force", false, "Overwrite existing proofs in -output-dir and the keys set up beside them")
+	indexPath := provePanelCmd.String("index", "", "Tabix (.tbi) or CSI (.csi) index of a bgzip-compressed -vcf (default: <vcf>.tbi or <vcf>.csi if present, else scan)")
+	missingPolicy := provePanelCmd.String("missing", "fail", "Missing genotype policy (fail, treat-as-missing, require-coverage)")
+	requirePass := provePanelCmd.Bool("require-pass", false, "Only use records whose FILTER is PASS")
+	filterMinDepth := provePanelCmd.Int("filter-min-dp", 0, "Only use records with at least this FORMAT/DP (or INFO/DP) (0 for no minimum)")
+	filterMinAF := provePanelCmd.Float64("filter-min-af", 0, "Only use calls whose carried ALTs have at least this FORMAT/AF (or INFO/AF) (0 for no minimum)")
+	backendName := provePanelCmd.String("backend", envDefault(envBackend, "groth16"), "Proof system (groth16, plonk)")
+
+	provePanelCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s prove-panel [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Prove each trait of a trait panel over its variant alone and write\n")
+		fmt.Fprintf(os.Stderr, "%s, listing each trait's proof or why it could not be\n", proofs.TraitReportName)
+		fmt.Fprintf(os.Stderr, "proved. Neither the proofs nor the report reveal a genotype.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		provePanelCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s prove-panel -vcf data/genome.vcf\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s prove-panel -vcf data/genome.vcf.gz -gene APOE -type dosage -output-dir apoe\n", os.Args[0])
+	}
+
+	provePanelCmd.Parse(args)
+
+	if *vcfPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -vcf is required\n\n")
+		provePanelCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	missing, err := proofs.ParseMissingPolicy(*missingPolicy)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+	settings := proofs.Settings{
+		Missing: missing,
+		Filter:  proofs.CallFilter{RequirePass: *requirePass, MinDepth: *filterMinDepth, MinAlleleFrequency: *filterMinAF},
+		Force:   *force,
+		Index:   *indexPath,
+	}
+	if backend, err := proofs.ParseBackend(*backendName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	} else if backend != proofs.BackendGroth16 {
+		settings.Backend = backend
+	}
+
+	panel := loadPanel(*panelPath)
+	if *gene != "" {
+		var traits []proofs.TraitVariant
+		for _, t := range panel {
+			if strings.EqualFold(t.Gene, *gene) {
+				traits = append(traits, t)
+			}
+		}
+		if len(traits) == 0 {
+			fmt.Printf("Error: gene %s is not in the trait panel\n", *gene)
+			exit(exitBadInput)
+		}
+		panel = traits
+	}
+
+	vcfName := *vcfPath
+	if *vcfPath == stdinName {
+		if *vcfPath, err = stdinVCF(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitVCF)
+		}
+		vcfName = "stdin"
+	}
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		fmt.Printf("Error creating output directory: %v\n", err)
+		exit(1)
+	}
+
+	fmt.Printf("Proving %d traits from %s in %s with %s proofs...\n\n", len(panel), *panelPath, vcfName, *proofType)
+	start := time.Now()
+	report, err := proofs.ProveTraits(cmdContext, *vcfPath, panel, proofs.ProofConfig{Settings: settings}, *proofType, *outputDir, *workers)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitCode(err, exitVCF))
+	}
+
+	fmt.Println()
+	counts := map[string]int{}
+	for _, r := range report.Results {
+		counts[r.Status]++
+		jsonReport.Traits = append(jsonReport.Traits, r)
+		switch r.Status {
+		case proofs.TraitProved:
+			reportProof(r.ProofType, vcfName, r.Proof, "", 0, nil)
+			fmt.Printf("✓ %s (%s) at %s: %s\n", r.Trait, r.Gene, r.Variant, r.Proof)
+		case proofs.TraitFailed:
+			reportProof(r.ProofType, vcfName, r.Proof, "", 0, fmt.Errorf("%s", r.Reason))
+			fmt.Printf("✗ %s (%s) at %s: %s\n", r.Trait, r.Gene, r.Variant, r.Reason)
+		default:
+			fmt.Printf("- %s (%s) at %s: not provable: %s\n", r.Trait, r.Gene, r.Variant, r.Reason)
+		}
+	}
+	fmt.Printf("\nProved %d of %d traits (%d not provable, %d failed) in %s\n",
+		counts[proofs.TraitProved], len(report.Results), counts[proofs.TraitNotProvable], counts[proofs.TraitFailed], time.Since(start).Round(time.Millisecond))
+	fmt.Printf("Report saved to: %s\n", filepath.Join(*outputDir, proofs.TraitReportName))
+	if counts[proofs.TraitFailed] > 0 {
+		exit(exitProving)
+	}
+}
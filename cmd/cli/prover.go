@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleProver(args []string) {
+	proverCmd := flag.NewFlagSet("prover", flag.ExitOnError)
+	keyPath := proverCmd.String("key", "", "Prover X25519 private key from keygen -prover")
+	listen := proverCmd.String("listen", ":8080", "Address the daemon serves POST /prove on")
+	jobPath := proverCmd.String("job", "", "Prove this job file once instead of serving (optional)")
+	outputPath := proverCmd.String("output", "", "Output path for the proof of -job (default: the job path without "+proofs.JobSuffix+")")
+	srsPath := proverCmd.String("srs", "", "KZG SRS file for PLONK jobs that need a setup (default: sample a development SRS)")
+	acceleratorName := proverCmd.String("accelerator", "cpu", "Hardware to compute groth16 proofs over bn254 on (cpu, gpu); gpu needs a build with -tags icicle and falls back to cpu")
+
+	proverCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s prover [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Prove witnesses solved and encrypted with the solve command. The prover decrypts\n")
+		fmt.Fprintf(os.Stderr, "each witness, so it sees the private genotypes and must be trusted with them.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		proverCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s prover -key keys/prover.key -listen :8080 -accelerator gpu\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s prover -key keys/prover.key -job output/chromosome_proof.bin.job\n", os.Args[0])
+	}
+
+	proverCmd.Parse(args)
+
+	if *keyPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -key is required\n\n")
+		proverCmd.Usage()
+		exit(exitBadInput)
+	}
+	key, err := proofs.ReadProverKey(*keyPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	accel, err := proofs.ParseAccelerator(*acceleratorName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	if *jobPath != "" {
+		if *outputPath == "" {
+			*outputPath = strings.TrimSuffix(*jobPath, proofs.JobSuffix)
+		}
+		if *outputPath == *jobPath {
+			fmt.Fprintf(os.Stderr, "Error: -output is required when -job does not end in %s\n\n", proofs.JobSuffix)
+			proverCmd.Usage()
+			exit(exitBadInput)
+		}
+		if err := proofs.ProveJob(cmdContext, key, *jobPath, *srsPath, accel, *outputPath); err != nil {
+			fmt.Printf("Error proving job: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Successfully generated proof at: %s\n", *outputPath)
+		return
+	}
+
+	fmt.Printf("Prover daemon listening on %s\n", *listen)
+	if err := http.ListenAndServe(*listen, proofs.ProverHandler(key, *srsPath, accel)); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+}
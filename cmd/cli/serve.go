@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/zkgenomics/vcf-proof-mvp/internal/telemetry"
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleServe(args []string) {
+	serveCmd := flag.NewFlagSet("serve", flag.ExitOnError)
+	listen := serveCmd.String("listen", ":8080", "Address the HTTP API is served on")
+	dir := serveCmd.String("dir", "proofs", "Directory generated proofs are stored in")
+	keyDir := serveCmd.String("key-dir", envDefault(envKeyDir, ""), "Directory of keys from setup named <type>.pk and <type>.vk, used to prove and verify proofs of those types (optional)")
+	panelPath := serveCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel gene fields are looked up in")
+	workers := serveCmd.Int("workers", 1, "Number of proofs generated at once")
+	timeout := serveCmd.Duration("timeout", 0, "Longest a proof may take to generate before it fails, e.g. 30m (default: no limit)")
+	keepFinished := serveCmd.Duration("keep-finished", time.Hour, "How long the status of a finished proof is kept in memory; done proofs are still served from -dir after it")
+	proverKeyPath := serveCmd.String("prover-key", "", "Prover X25519 private key from keygen -prover; lets clients upload jobs from solve instead of VCFs (optional)")
+	srsPath := serveCmd.String("srs", "", "KZG SRS file for PLONK jobs that need a setup (default: sample a development SRS)")
+	acceleratorName := serveCmd.String("accelerator", "cpu", "Hardware to compute groth16 proofs over bn254 on (cpu, gpu); gpu needs a build with -tags icicle and falls back to cpu")
+	ccsCacheDir := serveCmd.String("ccs-cache-dir", "", "Directory to cache compiled circuits in (optional)")
+	keyCacheDir := serveCmd.String("key-cache-dir", "", "Directory to cache keys in by circuit hash, so setup runs once per circuit definition (optional)")
+	tenantsPath := serveCmd.String("tenants", "", "JSON file of the tenants allowed to use the server, with their API key hashes from keygen -api-key and limits; requests without a tenant's key are refused (optional)")
+
+	serveCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s serve [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Serve an HTTP API generating and verifying proofs:\n\n")
+		fmt.Fprintf(os.Stderr, "  POST   /proofs       generate a proof from an uploaded vcf (or job) and the proof's\n")
+		fmt.Fprintf(os.Stderr, "                       type and options, named like the generate flags; answers 202\n")
+		fmt.Fprintf(os.Stderr, "  GET    /proofs/{id}  the proof's status and progress, public inputs and claims, and\n")
+		fmt.Fprintf(os.Stderr, "                       once done the proof, metadata and verifying key\n")
+		fmt.Fprintf(os.Stderr, "  DELETE /proofs/{id}  stop generating a pending proof, which then fails\n")
+		fmt.Fprintf(os.Stderr, "  POST   /verify       verify an uploaded proof, or the proof with an id, against\n")
+		fmt.Fprintf(os.Stderr, "                       options named like the verify flags\n")
+		fmt.Fprintf(os.Stderr, "  GET    /openapi.json the OpenAPI document of these endpoints\n\n")
+		fmt.Fprintf(os.Stderr, "Requests are multipart forms. The server reads the genotypes in uploaded VCFs and\n")
+		fmt.Fprintf(os.Stderr, "must be trusted with them; uploads are deleted once their proof is made.\n\n")
+		fmt.Fprintf(os.Stderr, "With -tenants, each request must bring a tenant's API key as a bearer token or\n")
+		fmt.Fprintf(os.Stderr, "X-API-Key header. A tenant's proofs are stored under <dir>/<tenant id> and hidden\n")
+		fmt.Fprintf(os.Stderr, "from other tenants, its keys are read from <key-dir>/<tenant id> before <key-dir>,\n")
+		fmt.Fprintf(os.Stderr, "and requests over its requests_per_minute or max_pending are refused with 429:\n\n")
+		fmt.Fprintf(os.Stderr, "  [{\"id\": \"acme\", \"api_keys\": [\"<sha256>\"], \"requests_per_minute\": 60, \"max_pending\": 4}]\n\n")
+		fmt.Fprintf(os.Stderr, "With OTEL_EXPORTER_OTLP_ENDPOINT set, the spans of each request and of extracting,\n")
+		fmt.Fprintf(os.Stderr, "compiling, setting up, proving and serializing its proof are exported over OTLP,\n")
+		fmt.Fprintf(os.Stderr, "continuing the trace in the request's traceparent header.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		serveCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s serve -listen :8080 -dir output/served -key-dir keys -workers 4\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  curl -F type=zygosity -F position=15:28365618 -F vcf=@sample.vcf localhost:8080/proofs\n")
+		fmt.Fprintf(os.Stderr, "  curl -F type=zygosity -F position=15:28365618 -F id=<id> localhost:8080/verify\n")
+		fmt.Fprintf(os.Stderr, "  %s serve -tenants tenants.json -key-dir keys -workers 4\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  curl -H \"Authorization: Bearer $(cat keys/acme.apikey)\" localhost:8080/proofs/<id>\n")
+	}
+
+	serveCmd.Parse(args)
+
+	if *workers < 1 {
+		fmt.Fprintf(os.Stderr, "Error: -workers must be at least 1\n\n")
+		serveCmd.Usage()
+		exit(exitBadInput)
+	}
+	if *timeout < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -timeout must not be negative\n\n")
+		serveCmd.Usage()
+		exit(exitBadInput)
+	}
+	if *keepFinished <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: -keep-finished must be positive\n\n")
+		serveCmd.Usage()
+		exit(exitBadInput)
+	}
+	accel, err := proofs.ParseAccelerator(*acceleratorName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	}
+
+	server := &proofs.ProofServer{
+		Dir:          *dir,
+		KeyDir:       *keyDir,
+		Settings:     proofs.Settings{Accelerator: accel, CCSCacheDir: *ccsCacheDir, KeyCacheDir: *keyCacheDir},
+		TraitPanel:   *panelPath,
+		SRS:          *srsPath,
+		Workers:      *workers,
+		Timeout:      *timeout,
+		KeepFinished: *keepFinished,
+	}
+	if *tenantsPath != "" {
+		if server.Tenants, err = proofs.ReadTenants(*tenantsPath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+		fmt.Printf("Serving %d tenants\n", len(server.Tenants))
+	}
+	if *proverKeyPath != "" {
+		if server.ProverKey, err = proofs.ReadProverKey(*proverKeyPath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(exitBadInput)
+		}
+	}
+	if err := os.MkdirAll(*dir, 0700); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	if _, err := telemetry.Setup(context.Background(), "vcfproof-serve"); err != nil {
+		fmt.Printf("Error: setting up tracing: %v\n", err)
+		exit(1)
+	}
+
+	fmt.Printf("Proof server listening on %s\n", *listen)
+	if err := http.ListenAndServe(*listen, server.Handler()); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+}
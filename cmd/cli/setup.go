@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleSetup(args []string) {
+	setupCmd := flag.NewFlagSet("setup", flag.ExitOnError)
+	proofType := setupCmd.String("type", "", "Type of proof to set up keys for (chromosome, eyecolor, brca1, repeat, diplotype, compoundhet, somatic, irisplex, carrier, membership, absence, region, haplotype, zygosity, wildtype, sv, dosage, threshold, panel, bloodtype); comma-separate several to set up each")
+	outDir := setupCmd.String("out", envDefault(envKeyDir, "keys"), "Directory to write <type>.pk and <type>.vk to")
+	gene := setupCmd.String("gene", "", "Gene for diplotype and compoundhet proofs, and for the -panel entries of dosage, threshold and panel proofs")
+	snps := setupCmd.String("snps", "", "Comma-separated chrom:pos:ref:alt SNPs for dosage, threshold and panel proofs (default: the -panel entries)")
+	panelPath := setupCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel to take dosage, threshold and panel proof SNPs from")
+	maxVariants := setupCmd.Int("max-variants", proofs.DefaultMaxVariants, "Number of VCF records the chromosome circuit holds")
+	treeDepth := setupCmd.Int("tree-depth", proofs.DefaultTreeDepth, "Depth of the variant Merkle tree for membership, absence, region, haplotype and panel proofs")
+	hashName := setupCmd.String("hash", "mimc", "Variant tree hash for membership, absence, region and panel proofs (mimc, poseidon2)")
+	backendName := setupCmd.String("backend", envDefault(envBackend, "groth16"), "Proof system (groth16, plonk)")
+	curveName := setupCmd.String("curve", "bn254", "Curve to set up over (bn254, bls12-381, bls12-377, bw6-761)")
+	srsPath := setupCmd.String("srs", "", "KZG SRS file for -backend plonk, e.g. from keygen -srs (default: sample a development SRS)")
+	nullifier := setupCmd.Bool("nullifier", false, "Set up for proofs generated with -holder-secret")
+	nonce := setupCmd.Bool("nonce", false, "Set up for proofs generated with -nonce")
+	timestamp := setupCmd.Bool("timestamp", false, "Set up for proofs generated with -timestamp")
+	expiry := setupCmd.Bool("expiry", false, "Set up for proofs generated with -valid-for (implies -timestamp)")
+	force := setupCmd.Bool("force", false, "Overwrite existing keys, which invalidates proofs issued against them")
+	digestMode := setupCmd.String("digest", "none", "Digest mode of the proofs to set up for (none, metadata, public); only public changes the circuit")
+	ccsCacheDir := setupCmd.String("ccs-cache-dir", "", "Directory to cache compiled circuits in (optional)")
+	keyCacheDir := setupCmd.String("key-cache-dir", "", "Directory to cache keys in by circuit hash (optional)")
+
+	setupCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s setup [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Compile circuits and generate their proving and verifying keys ahead of time,\n")
+		fmt.Fprintf(os.Stderr, "so provers need not run setup and verifiers can fetch the published verifying key.\n")
+		fmt.Fprintf(os.Stderr, "Keys only fit proofs generated with the same options, including -nullifier, -nonce,\n")
+		fmt.Fprintf(os.Stderr, "-timestamp, -expiry and -digest.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		setupCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s setup -type eyecolor -out keys/\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s setup -type eyecolor,bloodtype,brca1 -out keys/ -curve bls12-377\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s setup -type diplotype -gene CYP2C19 -out keys/ -nonce\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s setup -type zygosity -out keys/ -expiry\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s generate -type eyecolor -vcf data/genome.vcf -proving-key keys/eyecolor.pk\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -type eyecolor -proof output/eyecolor_proof.bin -verifying-key keys/eyecolor.vk\n", os.Args[0])
+	}
+
+	setupCmd.Parse(args)
+
+	if *proofType == "" {
+		fmt.Fprintf(os.Stderr, "Error: -type is required\n\n")
+		setupCmd.Usage()
+		exit(exitBadInput)
+	}
+	types := splitList(*proofType)
+
+	settings := proofs.Settings{CCSCacheDir: *ccsCacheDir, KeyCacheDir: *keyCacheDir}
+	if hash, err := proofs.ParseHashAlgorithm(*hashName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	} else if hash != proofs.HashMiMC {
+		settings.Hash = hash
+	}
+	if backend, err := proofs.ParseBackend(*backendName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	} else if backend != proofs.BackendGroth16 {
+		settings.Backend = backend
+		settings.SRS = *srsPath
+	} else if *srsPath != "" {
+		fmt.Printf("Error: -srs requires -backend plonk\n")
+		exit(1)
+	}
+	if curve, err := proofs.ParseCurve(*curveName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	} else if curve != proofs.CurveBN254 {
+		settings.Curve = curve
+	}
+	var err error
+	if settings.Digest, err = proofs.ParseDigestMode(*digestMode); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	cfg := proofs.ProofConfig{Settings: settings, MaxVariants: *maxVariants, TreeDepth: *treeDepth, Gene: *gene}
+	if hasType(types, "dosage", "threshold", "panel") {
+		cfg.Panel, err = snpPanel(*snps, *panelPath, *gene)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		fmt.Printf("Error creating output directory: %v\n", err)
+		exit(1)
+	}
+
+	opts := proofs.SetupOptions{Nullifier: *nullifier, Nonce: *nonce, Timestamp: *timestamp, Expiry: *expiry, Force: *force}
+	for _, t := range types {
+		proof, err := proofs.NewProof(t, cfg)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		keyPath := filepath.Join(*outDir, strings.ToLower(t))
+		fmt.Printf("Setting up %s keys...\n", t)
+		if err := proofs.SetupKeys(proof, keyPath, opts); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Keys saved to: %s.pk and %s.vk\n", keyPath, keyPath)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleExportVerifier(args []string) {
+	exportCmd := flag.NewFlagSet("export-verifier", flag.ExitOnError)
+	proofType := exportCmd.String("type", "", "Type of the proof whose verifier to export (any type generate supports)")
+	proofPath := exportCmd.String("proof", "", "Path to a proof made with the verifying key (default: output/<type>_proof.bin)")
+	verifyingKeyPath := exportCmd.String("verifying-key", "", "Path to verifying key file (default: <proof>.vk)")
+	outputPath := exportCmd.String("output", "", "Output path for the Solidity contract (default: <proof>.sol)")
+	calldataPath := exportCmd.String("calldata", "", "Output path for the proof's calldata (default: <proof>"+proofs.CalldataSuffix+")")
+
+	exportCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s export-verifier [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Export a Solidity verifier contract for a proof's verifying key, and the proof's\n")
+		fmt.Fprintf(os.Stderr, "public inputs as calldata, so it can be verified on EVM chains. Proofs must be over bn254.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		exportCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s export-verifier -type zygosity\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s export-verifier -type eyecolor -proof my_proof.bin -output EyeColorVerifier.sol\n", os.Args[0])
+	}
+
+	exportCmd.Parse(args)
+
+	if *proofType == "" {
+		fmt.Fprintf(os.Stderr, "Error: -type is required\n\n")
+		exportCmd.Usage()
+		exit(exitBadInput)
+	}
+	if _, err := proofs.NewProof(*proofType, proofs.ProofConfig{}); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	if *proofPath == "" {
+		*proofPath = filepath.Join("output", strings.ToLower(*proofType)+"_proof.bin")
+	}
+	if *verifyingKeyPath == "" {
+		*verifyingKeyPath = *proofPath + ".vk"
+	}
+	if *outputPath == "" {
+		*outputPath = *proofPath + ".sol"
+	}
+	if *calldataPath == "" {
+		*calldataPath = *proofPath + proofs.CalldataSuffix
+	}
+
+	meta, err := proofs.ReadMetadata(*proofPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	if !strings.EqualFold(meta.ProofType, *proofType) {
+		fmt.Printf("Error: %s is a %s proof, not %s\n", *proofPath, meta.ProofType, *proofType)
+		exit(1)
+	}
+
+	calldata, err := proofs.ExportSolidityVerifier(*verifyingKeyPath, *proofPath, *outputPath)
+	if err != nil {
+		fmt.Printf("Error exporting verifier: %v\n", err)
+		exit(1)
+	}
+	if err := proofs.WriteCalldata(*calldataPath, calldata); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	fmt.Printf("Successfully exported %s verifier contract to: %s\n", *proofType, *outputPath)
+	fmt.Printf("Calldata (%d public inputs) saved to: %s\n", len(calldata.Inputs), *calldataPath)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/gnark/logger"
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// stdinName is the path that names stdin or stdout in -vcf and -output.
+const stdinName = "-"
+
+// atExit holds the cleanups exit runs before the process ends.
+var atExit []func()
+
+// stdinVCF copies the VCF piped to stdin to a file only this user can read,
+// since proofs read their VCF more than once, and returns its path. The
+// file is removed when the command exits.
+func stdinVCF() (string, error) {
+	dir, err := os.MkdirTemp("", "vcf-stdin-")
+	if err != nil {
+		return "", fmt.Errorf("buffering VCF from stdin: %w", err)
+	}
+	atExit = append(atExit, func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "stdin.vcf")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", fmt.Errorf("buffering VCF from stdin: %w", err)
+	}
+	defer f.Close()
+	n, err := io.Copy(f, os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("reading VCF from stdin: %w", err)
+	}
+	if n == 0 {
+		return "", fmt.Errorf("no VCF on stdin")
+	}
+	return path, f.Close()
+}
+
+// envelopeProof writes the proof envelope at path as a proof file in a
+// temporary directory, removed at exit, and returns the file's path.
+func envelopeProof(path string) (string, error) {
+	env, err := proofs.ReadProofEnvelopeFile(path)
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp("", "vcf-envelope-")
+	if err != nil {
+		return "", fmt.Errorf("unpacking proof envelope: %w", err)
+	}
+	atExit = append(atExit, func() { os.RemoveAll(dir) })
+
+	proofPath := filepath.Join(dir, filepath.Base(strings.TrimSuffix(path, proofs.EnvelopeSuffix)))
+	if err := env.WriteProof(proofPath); err != nil {
+		return "", err
+	}
+	return proofPath, nil
+}
+
+// envelopeKey fetches the verifying key the proof envelope at path names
+// by CID from the IPFS gateway into a temporary file, removed at exit, and
+// returns the file's path.
+func envelopeKey(path, gateway string) (string, error) {
+	env, err := proofs.ReadProofEnvelopeFile(path)
+	if err != nil {
+		return "", err
+	}
+	vk, err := env.FetchVerifyingKey(cmdContext, &proofs.IPFS{Gateway: gateway})
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("Fetched verifying key %s from IPFS\n", env.VerifyingKeyCID)
+	return tempKey(vk)
+}
+
+// didKey fetches the verifying key the DID document of did publishes for
+// proofType to a temporary file removed at exit, and returns its path.
+func didKey(did, proofType string) (string, error) {
+	vk, err := (&proofs.DIDResolver{}).FetchVerifyingKey(cmdContext, did, proofType)
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("Fetched verifying key from %s\n", did)
+	return tempKey(vk)
+}
+
+// tempKey writes a fetched verifying key to a temporary file removed at
+// exit, and returns its path.
+func tempKey(vk []byte) (string, error) {
+	f, err := os.CreateTemp("", "vcf-fetched-*.vk")
+	if err != nil {
+		return "", err
+	}
+	atExit = append(atExit, func() { os.Remove(f.Name()) })
+	if _, err := f.Write(vk); err != nil {
+		f.Close()
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// reserveStdout sends everything the command prints, including gnark's
+// log, to stderr so that stdout carries only the proof, and returns the
+// original stdout to write it to.
+func reserveStdout() *os.File {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	logger.SetOutput(os.Stderr)
+	return stdout
+}
+
+// streamProof copies the proof at proofPath to w and removes the file,
+// leaving its metadata and any keys set up beside it.
+func streamProof(w io.Writer, proofPath string) error {
+	f, err := os.Open(proofPath)
+	if err != nil {
+		return fmt.Errorf("reading proof: %w", err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("writing proof to stdout: %w", err)
+	}
+	return os.Remove(proofPath)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// proofTypeNames lists the registered proof types, but those in exclude,
+// for flag help.
+func proofTypeNames(exclude ...string) string {
+	var names []string
+	for _, t := range proofs.ProofTypes() {
+		if !slices.Contains(exclude, t.Name) {
+			names = append(names, t.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func handleListTypes(args []string) {
+	listCmd := flag.NewFlagSet("list-types", flag.ExitOnError)
+	proofType := listCmd.String("type", "", "Only describe these comma-separated proof types")
+	asJSON := listCmd.Bool("json", false, "Print the descriptions as JSON")
+
+	listCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s list-types [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "List the supported proof types with the VCF fields they read and their public inputs\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		listCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s list-types\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s list-types -type zygosity,wildtype -json\n", os.Args[0])
+	}
+
+	listCmd.Parse(args)
+
+	types := proofs.ProofTypes()
+	if *proofType != "" {
+		types = types[:0]
+		for _, name := range splitList(*proofType) {
+			t, ok := proofs.LookupProofType(name)
+			if !ok {
+				fmt.Printf("Error: unknown proof type: %s\n", name)
+				exit(1)
+			}
+			types = append(types, t)
+		}
+	}
+
+	if jsonMode {
+		jsonReport.Types = types
+		return
+	}
+	if *asJSON {
+		data, err := json.MarshalIndent(types, "", "  ")
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	for i, t := range types {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s: %s\n", t.Name, t.Description)
+		if len(t.Fields) > 0 {
+			fmt.Printf("  VCF fields:    %s\n", strings.Join(t.Fields, ", "))
+		} else {
+			fmt.Printf("  VCF fields:    none (built from other proofs)\n")
+		}
+		fmt.Printf("  Public inputs:\n")
+		for j, input := range t.PublicInputs {
+			fmt.Printf("    [%d] %-18s %s\n", j, input.Name, input.Description)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func handleValidate(args []string) {
+	validateCmd := flag.NewFlagSet("validate", flag.ExitOnError)
+	vcfPath := validateCmd.String("vcf", "", "Path to VCF file, plain or compressed with gzip or bgzip")
+	panelPath := validateCmd.String("panel", envDefault(envPanel, "panels_traits.json"), "Trait panel whose loci the VCF must cover (empty to skip the coverage check)")
+	gene := validateCmd.String("gene", "", "Only check coverage of this gene's panel loci")
+	maxRecords := validateCmd.Int("max-records", 0, "Fail once the VCF has more than this many records (0 for no limit)")
+	maxLineBytes := validateCmd.Int("max-line-bytes", 0, "Fail on VCF lines longer than this many bytes (0 for no limit)")
+	outPath := validateCmd.String("out", "", "Output path for the JSON report (default: standard output)")
+
+	validateCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s validate [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Check a VCF before proving and report any problems as JSON. Exits with\n")
+		fmt.Fprintf(os.Stderr, "status 1 when the report has errors.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		validateCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s validate -vcf data/genome.vcf\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s validate -vcf data/genome.vcf.gz -gene BRCA1 -out report.json\n", os.Args[0])
+	}
+
+	validateCmd.Parse(args)
+
+	if *vcfPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -vcf is required\n\n")
+		validateCmd.Usage()
+		exit(exitBadInput)
+	}
+
+	var variants []proofs.VariantSpec
+	if *panelPath != "" {
+		panel, err := proofs.LoadTraitPanel(*panelPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		if variants, err = proofs.PanelVariants(panel, *gene); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+	}
+
+	report, err := proofs.ValidateVCF(*vcfPath, variants, proofs.ReadLimits{MaxRecords: *maxRecords, MaxLineBytes: *maxLineBytes})
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	if *outPath != "" {
+		if err := proofs.WriteValidationReport(*outPath, report); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Found %d issues in %d records; report saved to: %s\n", len(report.Issues), report.Records, *outPath)
+	} else {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	}
+
+	if !report.Valid {
+		exit(1)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// versionReport is the build and circuits version printed.
+type versionReport struct {
+	proofs.SoftwareVersion
+	Backend  string          `json:"backend,omitempty"`
+	Curve    string          `json:"curve,omitempty"`
+	Circuits []circuitReport `json:"circuits,omitempty"`
+}
+
+// circuitReport identifies one proof type's circuit.
+type circuitReport struct {
+	Type        string `json:"type"`
+	Constraints int    `json:"constraints"`
+	Hash        string `json:"circuit_hash"`
+}
+
+func handleVersion(args []string) {
+	versionCmd := flag.NewFlagSet("version", flag.ExitOnError)
+	short := versionCmd.Bool("short", false, "Only print the version, without compiling the circuits to hash them")
+	backendName := versionCmd.String("backend", envDefault(envBackend, "groth16"), "Proof system to compile the circuits for (groth16, plonk)")
+	curveName := versionCmd.String("curve", "bn254", "Curve to compile the circuits over (bn254, bls12-381, bls12-377, bw6-761)")
+
+	versionCmd.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s version [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print this build's version, the gnark and gnark-crypto versions it was built with and\n")
+		fmt.Fprintf(os.Stderr, "the hash of each proof type's compiled circuit. A prover and a verifier whose hashes\n")
+		fmt.Fprintf(os.Stderr, "differ run different circuits, so proofs from one will not verify with keys from the\n")
+		fmt.Fprintf(os.Stderr, "other. Proof metadata records the build that generated the proof.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		versionCmd.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s version\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s version -short\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --json version -backend plonk\n", os.Args[0])
+	}
+
+	versionCmd.Parse(args)
+
+	build := proofs.BuildVersion()
+	fmt.Printf("vcf-proof %s\n", build.Version)
+	if build.Commit != "" {
+		modified := ""
+		if build.Modified {
+			modified = " (modified)"
+		}
+		fmt.Printf("Commit:        %s%s\n", build.Commit, modified)
+	}
+	fmt.Printf("gnark:         %s\n", build.Gnark)
+	fmt.Printf("gnark-crypto:  %s\n", build.GnarkCrypto)
+	fmt.Printf("Go:            %s\n", build.GoVersion)
+	jsonReport.Version = &versionReport{SoftwareVersion: build}
+	if *short {
+		return
+	}
+
+	settings := proofs.Settings{}
+	if backend, err := proofs.ParseBackend(*backendName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	} else if backend != proofs.BackendGroth16 {
+		settings.Backend = backend
+	}
+	if curve, err := proofs.ParseCurve(*curveName); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitBadInput)
+	} else if curve != proofs.CurveBN254 {
+		settings.Curve = curve
+	}
+
+	ids, err := proofs.CircuitIDs(settings)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(exitCode(err, 1))
+	}
+	jsonReport.Version.Backend, jsonReport.Version.Curve = settings.Backend.String(), *curveName
+
+	fmt.Printf("\nCircuits (%s over %s):\n", settings.Backend, *curveName)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tCONSTRAINTS\tCIRCUIT HASH")
+	for _, id := range ids {
+		jsonReport.Version.Circuits = append(jsonReport.Version.Circuits, circuitReport{Type: id.Type, Constraints: id.Constraints, Hash: id.Hash})
+		fmt.Fprintf(w, "%s\t%d\t%s\n", id.Type, id.Constraints, id.Hash)
+	}
+	w.Flush()
+}
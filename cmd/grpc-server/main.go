@@ -0,0 +1,79 @@
+// Command grpc-server serves the gRPC ProofService of package proofspb:
+// generating proofs from VCFs streamed in chunks, verifying proofs,
+// handing out verifying keys and listing the proof types.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+
+	"github.com/zkgenomics/vcf-proof-mvp/internal/telemetry"
+	_ "github.com/zkgenomics/vcf-proof-mvp/pkg/objectstore"
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofspb"
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofsrpc"
+)
+
+func main() {
+	listen := flag.String("listen", ":9090", "Address the gRPC service is served on")
+	keyDir := flag.String("key-dir", os.Getenv("VCFPROOF_KEY_DIR"), "Directory of keys from setup named <type>.pk and <type>.vk, used to prove and verify proofs of those types (optional)")
+	maxVCFSize := flag.Int64("max-vcf-size", proofsrpc.DefaultMaxVCFSize, "Largest VCF accepted, in bytes; VCFs are held in memory")
+	workers := flag.Uint("workers", 4, "Number of requests served at once per connection")
+	acceleratorName := flag.String("accelerator", "cpu", "Hardware to compute groth16 proofs over bn254 on (cpu, gpu); gpu needs a build with -tags icicle and falls back to cpu")
+	ccsCacheDir := flag.String("ccs-cache-dir", "", "Directory to cache compiled circuits in (optional)")
+	keyCacheDir := flag.String("key-cache-dir", "", "Directory to cache keys in by circuit hash, so setup runs once per circuit definition (optional)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Serve the vcfproof.v1.ProofService gRPC API (pkg/proofspb/proofs.proto):\n\n")
+		fmt.Fprintf(os.Stderr, "  GenerateProof    prove a claim about a VCF streamed in chunks after a header\n")
+		fmt.Fprintf(os.Stderr, "  VerifyProof      verify a proof against its verifying key or the server's\n")
+		fmt.Fprintf(os.Stderr, "  GetVerifyingKey  the server's verifying key for a proof type\n")
+		fmt.Fprintf(os.Stderr, "  ListProofTypes   the proof types and their public inputs\n\n")
+		fmt.Fprintf(os.Stderr, "The server reads the genotypes in the VCFs it is sent and must be trusted with\n")
+		fmt.Fprintf(os.Stderr, "them; it holds them in memory and never writes them to disk.\n\n")
+		fmt.Fprintf(os.Stderr, "With OTEL_EXPORTER_OTLP_ENDPOINT set, the spans of each call and of extracting,\n")
+		fmt.Fprintf(os.Stderr, "compiling, setting up, proving and serializing its proof are exported over OTLP,\n")
+		fmt.Fprintf(os.Stderr, "continuing the trace propagated in the call's metadata.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	accel, err := proofs.ParseAccelerator(*acceleratorName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if *workers < 1 {
+		fmt.Fprintf(os.Stderr, "Error: -workers must be at least 1\n")
+		os.Exit(2)
+	}
+
+	server := proofsrpc.NewServer(*keyDir, proofs.Settings{Accelerator: accel, CCSCacheDir: *ccsCacheDir, KeyCacheDir: *keyCacheDir})
+	server.MaxVCFSize = *maxVCFSize
+
+	lis, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := telemetry.Setup(context.Background(), "vcfproof-grpc-server"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: setting up tracing: %v\n", err)
+		os.Exit(1)
+	}
+	// A VCF chunk may be up to 4 MiB, leaving room for the message around it.
+	s := grpc.NewServer(grpc.MaxRecvMsgSize(8<<20), grpc.MaxConcurrentStreams(uint32(*workers)),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	proofspb.RegisterProofServiceServer(s, server)
+	fmt.Printf("Serving the proof gRPC service on %s\n", lis.Addr())
+	if err := s.Serve(lis); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -1,11 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 
-	"github.com/zkgenomics/vcf-proof-mvp/internal/proofs"
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
 )
 
 func main() {
@@ -14,6 +15,8 @@ func main() {
 	fmt.Println("The Generate function creates proofs and serializes them to files")
 	fmt.Println("The Verify function reads serialized proofs and verifies them")
 
+	ctx := context.Background()
+
 	// Setup paths
 	vcfPath := "data/genome_example.vcf"
 	outputDir := "output"
@@ -30,9 +33,10 @@ func main() {
 	chromosomeVkPath := chromosomeProofPath + ".vk" // Verifying key has .vk suffix
 
 	// Generate chromosome proof
-	// - First parameter: VCF file to read genomic data from
-	// - Second parameter: Path to existing proving key (empty to generate a new one)
-	// - Third parameter: Output path where the proof will be saved
+	// - First parameter: Context cancelling the run when done
+	// - Second parameter: VCF file to read genomic data from
+	// - Third parameter: Path to existing proving key (empty to generate a new one)
+	// - Fourth parameter: Output path where the proof will be saved
 	// The Generate function will:
 	// 1. Read genomic data from the VCF file
 	// 2. Set up the proving system (or load existing keys)
@@ -41,20 +45,21 @@ func main() {
 	// 5. Save the proof and public witness to the output file
 	// 6. If generating new keys, save the proving key (.pk) and verifying key (.vk) files
 	var chromosomeProof proofs.ChromosomeProof
-	if err := chromosomeProof.Generate(vcfPath, "", chromosomeProofPath); err != nil {
+	if err := chromosomeProof.Generate(ctx, vcfPath, "", chromosomeProofPath); err != nil {
 		fmt.Printf("Error generating chromosome proof: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Verify chromosome proof
-	// - First parameter: Path to the verifying key file
-	// - Second parameter: Path to the proof file containing the proof and public witness
+	// - First parameter: Context cancelling the run when done
+	// - Second parameter: Path to the verifying key file
+	// - Third parameter: Path to the proof file containing the proof and public witness
 	// The Verify function will:
 	// 1. Load the verifying key
 	// 2. Read the proof and public witness from the proof file
 	// 3. Verify that the proof is valid using Groth16.Verify
 	// 4. Return true if verification succeeded, false otherwise
-	verified, err := chromosomeProof.Verify(chromosomeVkPath, chromosomeProofPath)
+	verified, err := chromosomeProof.Verify(ctx, chromosomeVkPath, chromosomeProofPath)
 	if err != nil {
 		fmt.Printf("Error verifying chromosome proof: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,294 @@
+//go:build js && wasm
+
+// Command wasm exposes proof generation and verification to JavaScript, so
+// a browser can prove claims about a VCF that never leaves the user's
+// machine. Built with
+//
+//	GOOS=js GOARCH=wasm go build -o vcfproof.wasm ./cmd/wasm
+//
+// and loaded with Go's wasm_exec.js, it defines two global functions
+// returning Promises:
+//
+//	generateProof(type, vcf, options) → {proof, metadata, provingKey, verifyingKey}
+//	verifyProof(proof, options) → {valid, proofType, circuitId, publicInputs, keyFingerprint, ...}
+//
+// vcf and the keys and proof files are Uint8Arrays; verifyProof takes the
+// object generateProof resolves to, or one with the same fields. options
+// name the proofs package's Options: position, variant, gene, target,
+// sample, panel (an array of chrom:pos:ref:alt), nonce, validFor (seconds),
+// backend, curve, proofType, publicInputs (an array of decimal strings or
+// nulls), provingKey and verifyingKey. An onProgress function in options
+// receives generateProof's progress events. A failed call rejects its
+// Promise with an Error whose kind names the proofs error kind.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"syscall/js"
+	"time"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func main() {
+	js.Global().Set("generateProof", js.FuncOf(generateProof))
+	js.Global().Set("verifyProof", js.FuncOf(verifyProof))
+	select {}
+}
+
+// generateProof is generateProof(type, vcf, options).
+func generateProof(this js.Value, args []js.Value) any {
+	return promise(func() (any, error) {
+		if len(args) < 2 || args[0].Type() != js.TypeString {
+			return nil, errors.New("generateProof(type, vcf, options) needs a proof type and a VCF")
+		}
+		vcf, err := bytesArg(args[1], "vcf")
+		if err != nil {
+			return nil, err
+		}
+		opts, err := options(optionsArg(args, 2))
+		if err != nil {
+			return nil, err
+		}
+		data, err := proofs.GenerateBytes(context.Background(), args[0].String(), vcf, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"proof":        uint8Array(data.Proof),
+			"metadata":     uint8Array(data.Metadata),
+			"provingKey":   uint8Array(data.ProvingKey),
+			"verifyingKey": uint8Array(data.VerifyingKey),
+		}, nil
+	})
+}
+
+// verifyProof is verifyProof(proof, options).
+func verifyProof(this js.Value, args []js.Value) any {
+	return promise(func() (any, error) {
+		if len(args) < 1 || args[0].Type() != js.TypeObject {
+			return nil, errors.New("verifyProof(proof, options) needs a proof object")
+		}
+		var data proofs.ProofData
+		fields := []struct {
+			name string
+			dst  *[]byte
+		}{
+			{"proof", &data.Proof},
+			{"metadata", &data.Metadata},
+			{"verifyingKey", &data.VerifyingKey},
+		}
+		for _, f := range fields {
+			v := args[0].Get(f.name)
+			if v.IsUndefined() || v.IsNull() {
+				continue
+			}
+			b, err := bytesArg(v, f.name)
+			if err != nil {
+				return nil, err
+			}
+			*f.dst = b
+		}
+		opts, err := options(optionsArg(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		res, err := proofs.VerifyBytes(context.Background(), &data, opts...)
+		if res == nil {
+			return nil, err
+		}
+		// A rejected proof still has a result; pass it on as JSON values.
+		encoded, jsonErr := json.Marshal(res)
+		if jsonErr != nil {
+			return nil, jsonErr
+		}
+		var out map[string]any
+		if jsonErr := json.Unmarshal(encoded, &out); jsonErr != nil {
+			return nil, jsonErr
+		}
+		if err != nil {
+			out["error"] = err.Error()
+		}
+		return out, nil
+	})
+}
+
+// options reads the proofs Options from a JavaScript options object.
+func options(o js.Value) ([]proofs.Option, error) {
+	if o.IsUndefined() || o.IsNull() {
+		return nil, nil
+	}
+	var opts []proofs.Option
+	str := func(name string) (string, bool) {
+		v := o.Get(name)
+		if v.Type() != js.TypeString {
+			return "", false
+		}
+		return v.String(), true
+	}
+	if v, ok := str("position"); ok {
+		opts = append(opts, proofs.WithPosition(v))
+	}
+	if v, ok := str("variant"); ok {
+		opts = append(opts, proofs.WithVariant(v))
+	}
+	if v, ok := str("gene"); ok {
+		opts = append(opts, proofs.WithGene(v))
+	}
+	if v, ok := str("sample"); ok {
+		opts = append(opts, proofs.WithSample(v))
+	}
+	if v, ok := str("nonce"); ok {
+		opts = append(opts, proofs.WithNonce(v))
+	}
+	if v, ok := str("proofType"); ok {
+		opts = append(opts, proofs.WithProofType(v))
+	}
+	if v, ok := str("backend"); ok {
+		backend, err := proofs.ParseBackend(v)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, proofs.WithBackend(backend))
+	}
+	if v, ok := str("curve"); ok {
+		curve, err := proofs.ParseCurve(v)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, proofs.WithCurve(curve))
+	}
+	if v := o.Get("target"); v.Type() == js.TypeNumber {
+		opts = append(opts, proofs.WithTarget(v.Int()))
+	}
+	if v := o.Get("validFor"); v.Type() == js.TypeNumber {
+		opts = append(opts, proofs.WithValidFor(time.Duration(v.Float()*float64(time.Second))))
+	}
+	if v := o.Get("panel"); v.InstanceOf(js.Global().Get("Array")) {
+		var panel []proofs.VariantSpec
+		for i := range v.Length() {
+			spec, err := proofs.ParseVariantSpec(v.Index(i).String())
+			if err != nil {
+				return nil, err
+			}
+			panel = append(panel, spec)
+		}
+		opts = append(opts, proofs.WithPanel(panel))
+	}
+	if v := o.Get("publicInputs"); v.InstanceOf(js.Global().Get("Array")) {
+		inputs := make([]*big.Int, v.Length())
+		for i := range inputs {
+			e := v.Index(i)
+			if e.IsNull() || e.IsUndefined() {
+				continue
+			}
+			n, ok := new(big.Int).SetString(e.String(), 10)
+			if !ok {
+				return nil, fmt.Errorf("public input %d is not a decimal number: %q", i, e.String())
+			}
+			inputs[i] = n
+		}
+		opts = append(opts, proofs.WithPublicInputs(inputs...))
+	}
+	keys := []struct {
+		name   string
+		option func([]byte) proofs.Option
+	}{
+		{"provingKey", proofs.WithProvingKeyData},
+		{"verifyingKey", proofs.WithVerifyingKeyData},
+	}
+	for _, k := range keys {
+		if v := o.Get(k.name); !v.IsUndefined() && !v.IsNull() {
+			b, err := bytesArg(v, k.name)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, k.option(b))
+		}
+	}
+	if v := o.Get("onProgress"); v.Type() == js.TypeFunction {
+		opts = append(opts, proofs.WithProgress(func(e proofs.ProgressEvent) {
+			v.Invoke(map[string]any{
+				"proofType":   e.ProofType,
+				"stage":       string(e.Stage),
+				"done":        e.Done,
+				"fraction":    e.Fraction,
+				"elapsed":     e.Elapsed.Seconds(),
+				"constraints": e.Constraints,
+			})
+		}))
+	}
+	return opts, nil
+}
+
+func optionsArg(args []js.Value, i int) js.Value {
+	if len(args) > i {
+		return args[i]
+	}
+	return js.Undefined()
+}
+
+// bytesArg copies the Uint8Array v out of JavaScript.
+func bytesArg(v js.Value, name string) ([]byte, error) {
+	if !v.InstanceOf(js.Global().Get("Uint8Array")) {
+		return nil, fmt.Errorf("%s is not a Uint8Array", name)
+	}
+	b := make([]byte, v.Length())
+	js.CopyBytesToGo(b, v)
+	return b, nil
+}
+
+// uint8Array copies b into a new Uint8Array, or is null for nil b.
+func uint8Array(b []byte) any {
+	if b == nil {
+		return nil
+	}
+	a := js.Global().Get("Uint8Array").New(len(b))
+	js.CopyBytesToJS(a, b)
+	return a
+}
+
+// promise runs f in the background, as proving takes long enough to block
+// the page, and returns a Promise of its result.
+func promise(f func() (any, error)) js.Value {
+	var handler js.Func
+	handler = js.FuncOf(func(this js.Value, args []js.Value) any {
+		resolve, reject := args[0], args[1]
+		go func() {
+			defer handler.Release()
+			result, err := f()
+			if err != nil {
+				jsErr := js.Global().Get("Error").New(err.Error())
+				jsErr.Set("kind", errorKind(err))
+				reject.Invoke(jsErr)
+				return
+			}
+			resolve.Invoke(result)
+		}()
+		return nil
+	})
+	return js.Global().Get("Promise").New(handler)
+}
+
+// errorKind names the kind of err, as the proof server does.
+func errorKind(err error) string {
+	switch proofs.ErrorKind(err) {
+	case proofs.ErrInvalidInput:
+		return "invalid_input"
+	case proofs.ErrVCF:
+		return "vcf"
+	case proofs.ErrNotFound:
+		return "not_found"
+	case proofs.ErrProving:
+		return "proving"
+	case proofs.ErrVerification:
+		return "verification"
+	case proofs.ErrUnsupported:
+		return "unsupported"
+	}
+	return ""
+}
@@ -0,0 +1,203 @@
+// Package mobile binds proof verification, and generation of proofs over
+// small circuits, for iOS and Android apps through gomobile:
+//
+//	gomobile bind -target=android ./pkg/mobile
+//	gomobile bind -target=ios ./pkg/mobile
+//
+// Its API keeps to the types gomobile binds: strings, numbers, booleans,
+// byte slices and pointers to the structs here. Proofs, keys and VCFs are
+// passed as bytes and never written to the device's storage, so an app can
+// verify a proof offline from the proof and verifying key it was sent.
+package mobile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// DefaultMaxConstraints bounds the circuits Generate proves unless
+// Options.MaxConstraints says otherwise: setup and proving of larger ones
+// take too long and too much memory on a phone.
+const DefaultMaxConstraints = 1 << 16
+
+// Options configure Generate and Verify, with the fields of the proofs
+// package's Options that apply. Lists are comma-separated strings.
+type Options struct {
+	Position  string
+	Variant   string
+	Gene      string
+	Sample    string
+	Nonce     string
+	ProofType string
+	Target    int
+	// ValidForSeconds binds an expiry this long after issuance into a
+	// generated proof.
+	ValidForSeconds int64
+	// Panel lists the chrom:pos:ref:alt SNPs of dosage, threshold and
+	// panel proofs.
+	Panel string
+	// PublicInputs lists the decimal values of the proof's leading public
+	// inputs; an empty entry matches any value.
+	PublicInputs string
+	// ProvingKey proves with this key instead of running a setup.
+	ProvingKey []byte
+	// MaxConstraints bounds the circuits Generate proves; zero means
+	// DefaultMaxConstraints.
+	MaxConstraints int
+}
+
+// NewOptions returns empty Options.
+func NewOptions() *Options {
+	return &Options{}
+}
+
+// Proof is a generated proof with the files generated beside it.
+type Proof struct {
+	Proof    []byte
+	Metadata []byte
+	// ProvingKey and VerifyingKey are the keys of the setup the proof ran,
+	// unset when Options.ProvingKey was given.
+	ProvingKey   []byte
+	VerifyingKey []byte
+}
+
+// Result is what Verify checked.
+type Result struct {
+	Valid     bool
+	ProofType string
+	CircuitID string
+	// PublicInputs is a JSON object of the proof's public inputs by name.
+	PublicInputs   string
+	KeyFingerprint string
+	// Error says why a rejected proof failed; empty when Valid.
+	Error string
+}
+
+// Generate proves a proof of proofType from a VCF held in vcf. Proof types
+// whose circuit is over opts.MaxConstraints constraints are refused before
+// any setup or proving.
+func Generate(proofType string, vcf []byte, opts *Options) (*Proof, error) {
+	if opts == nil {
+		opts = NewOptions()
+	}
+	cfg, extra, err := opts.config()
+	if err != nil {
+		return nil, err
+	}
+	p, err := proofs.NewProof(proofType, cfg)
+	if err != nil {
+		return nil, err
+	}
+	estimate, err := proofs.EstimateResources(p)
+	if err != nil {
+		return nil, err
+	}
+	limit := opts.MaxConstraints
+	if limit <= 0 {
+		limit = DefaultMaxConstraints
+	}
+	if estimate.Constraints > limit {
+		return nil, fmt.Errorf("a %s proof has %d constraints, more than the %d proved on a mobile device", proofType, estimate.Constraints, limit)
+	}
+
+	data, err := proofs.GenerateBytes(context.Background(), proofType, vcf, append([]proofs.Option{proofs.WithConfig(cfg)}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{Proof: data.Proof, Metadata: data.Metadata, ProvingKey: data.ProvingKey, VerifyingKey: data.VerifyingKey}, nil
+}
+
+// Verify verifies proof, with its metadata, against verifyingKey. A proof
+// failing a check gets a Result with Valid unset and the reason in Error;
+// an error is returned only when the proof or key cannot be read.
+func Verify(proof, metadata, verifyingKey []byte, opts *Options) (*Result, error) {
+	if opts == nil {
+		opts = NewOptions()
+	}
+	cfg, extra, err := opts.config()
+	if err != nil {
+		return nil, err
+	}
+	data := &proofs.ProofData{Proof: proof, Metadata: metadata, VerifyingKey: verifyingKey}
+	res, err := proofs.VerifyBytes(context.Background(), data, append([]proofs.Option{proofs.WithConfig(cfg)}, extra...)...)
+	if res == nil {
+		return nil, err
+	}
+	inputs, jsonErr := json.Marshal(res.PublicInputs)
+	if jsonErr != nil {
+		return nil, jsonErr
+	}
+	result := &Result{
+		Valid:          res.Valid,
+		ProofType:      res.ProofType,
+		CircuitID:      res.CircuitID,
+		PublicInputs:   string(inputs),
+		KeyFingerprint: res.KeyFingerprint,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+// config returns the proof configuration o describes and the options
+// outside it.
+func (o *Options) config() (proofs.ProofConfig, []proofs.Option, error) {
+	cfg := proofs.ProofConfig{
+		Position: o.Position,
+		Variant:  o.Variant,
+		Gene:     o.Gene,
+		Target:   o.Target,
+	}
+	cfg.Settings.Sample = o.Sample
+	cfg.Settings.Nonce = o.Nonce
+	cfg.Settings.ValidFor = time.Duration(o.ValidForSeconds) * time.Second
+	for _, snp := range splitList(o.Panel) {
+		spec, err := proofs.ParseVariantSpec(snp)
+		if err != nil {
+			return cfg, nil, err
+		}
+		cfg.Panel = append(cfg.Panel, spec)
+	}
+
+	var extra []proofs.Option
+	if o.ProofType != "" {
+		extra = append(extra, proofs.WithProofType(o.ProofType))
+	}
+	if o.PublicInputs != "" {
+		var inputs []*big.Int
+		for i, v := range strings.Split(o.PublicInputs, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				inputs = append(inputs, nil)
+				continue
+			}
+			n, ok := new(big.Int).SetString(v, 10)
+			if !ok {
+				return cfg, nil, fmt.Errorf("public input %d is not a decimal number: %q", i, v)
+			}
+			inputs = append(inputs, n)
+		}
+		extra = append(extra, proofs.WithPublicInputs(inputs...))
+	}
+	if o.ProvingKey != nil {
+		extra = append(extra, proofs.WithProvingKeyData(o.ProvingKey))
+	}
+	return cfg, extra, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,45 @@
+package mobile
+
+import (
+	"strings"
+	"testing"
+)
+
+const testVCF = `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`
+
+func TestGenerateVerify(t *testing.T) {
+	opts := NewOptions()
+	opts.Position = "15:28365618"
+	opts.Nonce = "challenge"
+	proof, err := Generate("zygosity", []byte(testVCF), opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	res, err := Verify(proof.Proof, proof.Metadata, proof.VerifyingKey, &Options{Nonce: "challenge"})
+	if err != nil || !res.Valid {
+		t.Fatalf("Verify = %+v, %v", res, err)
+	}
+	if res.ProofType != "zygosity" || !strings.Contains(res.PublicInputs, `"nonce":"challenge"`) {
+		t.Errorf("Verify result = %+v", res)
+	}
+
+	res, err = Verify(proof.Proof, proof.Metadata, proof.VerifyingKey, &Options{Nonce: "stale"})
+	if err != nil || res.Valid || res.Error == "" {
+		t.Errorf("Verify with a stale nonce = %+v, %v", res, err)
+	}
+	if _, err := Verify(nil, nil, nil, nil); err == nil {
+		t.Errorf("Verify of no proof succeeded")
+	}
+}
+
+func TestGenerateRefusesLargeCircuits(t *testing.T) {
+	opts := &Options{Position: "15:28365618", MaxConstraints: 10}
+	if _, err := Generate("zygosity", []byte(testVCF), opts); err == nil || !strings.Contains(err.Error(), "constraints") {
+		t.Errorf("Generate over the constraint bound: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// GCS is a proofs.Storage of the objects in a Google Cloud Storage bucket,
+// named by object name.
+type GCS struct {
+	Client *storage.Client
+	Bucket string
+}
+
+// OpenGCS returns the GCS storage of bucket with a client of the
+// application default credentials.
+func OpenGCS(ctx context.Context, bucket string) (proofs.Storage, error) {
+	if bucket == "" {
+		return nil, errors.New("a gs path names no bucket")
+	}
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &GCS{Client: client, Bucket: bucket}, nil
+}
+
+// Open reads the object name.
+func (g *GCS) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	r, err := g.Client.Bucket(g.Bucket).Object(name).NewReader(ctx)
+	if err != nil {
+		return nil, g.error("open", name, err)
+	}
+	return r, nil
+}
+
+// Create returns a writer of the object name, which GCS makes visible once
+// the writer is closed.
+func (g *GCS) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	return g.Client.Bucket(g.Bucket).Object(name).NewWriter(ctx), nil
+}
+
+// Stat describes the object name.
+func (g *GCS) Stat(ctx context.Context, name string) (fs.FileInfo, error) {
+	attrs, err := g.Client.Bucket(g.Bucket).Object(name).Attrs(ctx)
+	if err != nil {
+		return nil, g.error("stat", name, err)
+	}
+	return objectInfo{name: name, size: attrs.Size, modTime: attrs.Updated}, nil
+}
+
+// Remove deletes the object name.
+func (g *GCS) Remove(ctx context.Context, name string) error {
+	return g.error("remove", name, g.Client.Bucket(g.Bucket).Object(name).Delete(ctx))
+}
+
+// error wraps an error of op on the object name, as fs.ErrNotExist when
+// there is no such object.
+func (g *GCS) error(op, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		err = fs.ErrNotExist
+	}
+	return &fs.PathError{Op: op, Path: "gs://" + g.Bucket + "/" + name, Err: err}
+}
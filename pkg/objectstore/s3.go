@@ -0,0 +1,104 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+// S3 is a proofs.Storage of the objects in an S3 bucket, named by key.
+type S3 struct {
+	Client *s3.Client
+	Bucket string
+}
+
+// OpenS3 returns the S3 storage of bucket with a client of the default
+// AWS configuration. An endpoint other than AWS's, such as MinIO's, is set
+// with AWS_ENDPOINT_URL_S3.
+func OpenS3(ctx context.Context, bucket string) (proofs.Storage, error) {
+	if bucket == "" {
+		return nil, errors.New("an s3 path names no bucket")
+	}
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS configuration: %w", err)
+	}
+	return &S3{Client: s3.NewFromConfig(cfg), Bucket: bucket}, nil
+}
+
+// Open reads the object name.
+func (s *S3) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	out, err := s.Client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(s.Bucket), Key: aws.String(name)})
+	if err != nil {
+		return nil, s.error("open", name, err)
+	}
+	return out.Body, nil
+}
+
+// Create returns a writer of the object name, which is spooled to a
+// temporary file and uploaded on Close.
+func (s *S3) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	f, err := newSpoolFile()
+	if err != nil {
+		return nil, err
+	}
+	return &s3Writer{spoolFile: f, ctx: ctx, s: s, name: name}, nil
+}
+
+// Stat describes the object name.
+func (s *S3) Stat(ctx context.Context, name string) (fs.FileInfo, error) {
+	out, err := s.Client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(s.Bucket), Key: aws.String(name)})
+	if err != nil {
+		return nil, s.error("stat", name, err)
+	}
+	return objectInfo{name: name, size: aws.ToInt64(out.ContentLength), modTime: aws.ToTime(out.LastModified)}, nil
+}
+
+// Remove deletes the object name.
+func (s *S3) Remove(ctx context.Context, name string) error {
+	if _, err := s.Stat(ctx, name); err != nil {
+		return err
+	}
+	_, err := s.Client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(s.Bucket), Key: aws.String(name)})
+	return s.error("remove", name, err)
+}
+
+// error wraps an error of op on the object name, as fs.ErrNotExist when
+// there is no such object.
+func (s *S3) error(op, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var noKey *types.NoSuchKey
+	var notFound *types.NotFound
+	if errors.As(err, &noKey) || errors.As(err, &notFound) {
+		err = fs.ErrNotExist
+	}
+	return &fs.PathError{Op: op, Path: "s3://" + s.Bucket + "/" + name, Err: err}
+}
+
+// s3Writer uploads the object it spooled when closed.
+type s3Writer struct {
+	spoolFile
+	ctx  context.Context
+	s    *S3
+	name string
+}
+
+func (w *s3Writer) Close() error {
+	defer w.remove()
+	if _, err := w.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := w.s.Client.PutObject(w.ctx, &s3.PutObjectInput{Bucket: aws.String(w.s.Bucket), Key: aws.String(w.name), Body: w.File})
+	return w.s.error("write", w.name, err)
+}
@@ -0,0 +1,101 @@
+package objectstore
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// fakeS3 serves the object requests S3 makes of path-style URLs.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := r.URL.Path
+	data, ok := f.objects[key]
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+		return
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		if r.Method == http.MethodGet {
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>no such key</Message></Error>`)
+		}
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+	if r.Method == http.MethodGet {
+		w.Write(data)
+	}
+}
+
+func TestS3(t *testing.T) {
+	server := httptest.NewServer(&fakeS3{objects: map[string][]byte{}})
+	defer server.Close()
+	client := s3.New(s3.Options{
+		BaseEndpoint:               aws.String(server.URL),
+		Region:                     "us-east-1",
+		UsePathStyle:               true,
+		Credentials:                credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		RequestChecksumCalculation: aws.RequestChecksumCalculationWhenRequired,
+	})
+	store := &S3{Client: client, Bucket: "bucket"}
+
+	w, err := store.Create(t.Context(), "keys/zygosity.vk")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	io.WriteString(w, "key data")
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := store.Open(t.Context(), "keys/zygosity.vk")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || string(data) != "key data" {
+		t.Errorf("read %q, %v", data, err)
+	}
+	if info, err := store.Stat(t.Context(), "keys/zygosity.vk"); err != nil || info.Size() != 8 || info.Name() != "zygosity.vk" {
+		t.Errorf("Stat = %v, %v", info, err)
+	}
+
+	if err := store.Remove(t.Context(), "keys/zygosity.vk"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := store.Open(t.Context(), "keys/zygosity.vk"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open of a removed object: %v", err)
+	}
+	if _, err := store.Stat(t.Context(), "keys/zygosity.vk"); !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), "s3://bucket/keys/zygosity.vk") {
+		t.Errorf("Stat of a removed object: %v", err)
+	}
+	if err := store.Remove(t.Context(), "keys/zygosity.vk"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Remove of a removed object: %v", err)
+	}
+}
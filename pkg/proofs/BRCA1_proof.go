@@ -0,0 +1,109 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// brca1PathogenicVariants are the known pathogenic BRCA1 variants checked by
+// BRCA1Circuit, in GRCh37 coordinates on chr17.
+var brca1PathogenicVariants = [...]VariantSpec{
+	{Chromosome: "17", Position: 41276045, Ref: "C", Alt: "G"},                       // trait panel entry
+	{Chromosome: "17", Position: 41276044, Ref: "ACT", Alt: "A", RSID: "rs80357914"}, // c.68_69delAG (185delAG)
+	{Chromosome: "17", Position: 41258504, Ref: "A", Alt: "C", RSID: "rs28897672"},   // c.181T>G (C61G)
+	{Chromosome: "17", Position: 41209079, Ref: "G", Alt: "GC", RSID: "rs80357906"},  // c.5266dupC (5382insC)
+}
+
+// BRCA1Circuit proves whether the subject carries any of the known pathogenic
+// BRCA1 variants without revealing which one, or at what dosage.
+type BRCA1Circuit struct {
+	// Public input - 1 when at least one pathogenic variant is carried
+	Carrier frontend.Variable `gnark:",public"`
+
+	// Private inputs - ALT dosage at each of brca1PathogenicVariants
+	Dosages [len(brca1PathogenicVariants)]frontend.Variable
+}
+
+func (c *BRCA1Circuit) Define(api frontend.API) error {
+	total := frontend.Variable(0)
+	for _, dosage := range c.Dosages {
+		assertIsDosage(api, dosage)
+		total = api.Add(total, dosage)
+	}
+
+	api.AssertIsEqual(c.Carrier, api.Sub(1, api.IsZero(total)))
+
+	return nil
+}
+
+func (p *BRCA1Proof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	panel := brca1PathogenicVariants[:]
+
+	fmt.Println("Searching for pathogenic BRCA1 variants...")
+	genotypes, err := extractGenotypes(vcfPath, p.Settings, panel)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	dosages, err := panelDosages(panel, genotypes)
+	if err != nil {
+		return err
+	}
+
+	var assignment BRCA1Circuit
+	carrier := 0
+	for i, dosage := range dosages {
+		assignment.Dosages[i] = dosage
+		if dosage > 0 {
+			carrier = 1
+		}
+	}
+	assignment.Carrier = carrier
+
+	if err := p.Settings.prove(ctx, "brca1", &BRCA1Circuit{}, &assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("brca1", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	if carrier == 1 {
+		fmt.Println("We have proven the genome carries a known pathogenic BRCA1 variant")
+	} else {
+		fmt.Println("We have proven the genome carries none of the known pathogenic BRCA1 variants")
+	}
+	fmt.Println("without revealing which variant or any other genomic information.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *BRCA1Proof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 1 {
+		return false, withKind(ErrVerification, fmt.Errorf("BRCA1 proof has %d public inputs, expected 1", len(public)))
+	}
+	if public[0].Sign() != 0 {
+		fmt.Println("Carries a known pathogenic BRCA1 variant")
+	} else {
+		fmt.Println("Carries none of the known pathogenic BRCA1 variants")
+	}
+
+	return verified, nil
+}
@@ -0,0 +1,109 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestBRCA1Circuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+
+	tests := []struct {
+		name    string
+		dosages [len(brca1PathogenicVariants)]int
+		carrier int
+		valid   bool
+	}{
+		{"non-carrier", [...]int{0, 0, 0, 0}, 0, true},
+		{"heterozygous carrier", [...]int{0, 1, 0, 0}, 1, true},
+		{"homozygous carrier", [...]int{0, 0, 0, 2}, 1, true},
+		{"hidden carrier", [...]int{0, 1, 0, 0}, 0, false},
+		{"false carrier", [...]int{0, 0, 0, 0}, 1, false},
+		{"cancelling dosages", [...]int{1, -1, 0, 0}, 0, false},
+		{"out-of-range dosage", [...]int{3, 0, 0, 0}, 1, false},
+	}
+	for _, tt := range tests {
+		assignment := &BRCA1Circuit{Carrier: tt.carrier}
+		for i, d := range tt.dosages {
+			assignment.Dosages[i] = d
+		}
+
+		err := test.IsSolved(&BRCA1Circuit{}, assignment, field)
+		if tt.valid && err != nil {
+			t.Errorf("%s: valid witness rejected: %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: invalid witness accepted", tt.name)
+		}
+	}
+}
+
+func TestBRCA1Proof_GenerateAndVerify(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+17	41276045	.	C	G	60	PASS	.	GT	0/1
+`)
+	out := filepath.Join(t.TempDir(), "brca1_proof.bin")
+
+	proof := &BRCA1Proof{}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	verified, err := proof.Verify(t.Context(), out+".vk", out)
+	if err != nil || !verified {
+		t.Fatalf("Verify = %v, %v", verified, err)
+	}
+
+	public, err := readPublicInputs(out)
+	if err != nil {
+		t.Fatalf("readPublicInputs: %v", err)
+	}
+	if public[0].Int64() != 1 {
+		t.Errorf("Carrier = %s, want 1", public[0])
+	}
+}
+
+func TestBRCA1Proof_GenerateWithMissingPosition(t *testing.T) {
+	// Loci absent from the VCF are treated as homozygous reference.
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+17	12345678	.	A	G	60	PASS	.	GT	0/1
+`)
+	out := filepath.Join(t.TempDir(), "brca1_proof.bin")
+
+	if err := (&BRCA1Proof{}).Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	public, err := readPublicInputs(out)
+	if err != nil {
+		t.Fatalf("readPublicInputs: %v", err)
+	}
+	if public[0].Sign() != 0 {
+		t.Errorf("Carrier = %s, want 0", public[0])
+	}
+}
+
+func TestBRCA1Proof_GenerateRejectsSitesOnlyVCF(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##INFO=<ID=DP,Number=1,Type=Integer,Description="Approximate read depth">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO
+17	41276045	.	C	G	60	PASS	DP=30
+`)
+
+	if err := (&BRCA1Proof{}).Generate(t.Context(), vcf, "", filepath.Join(t.TempDir(), "proof.bin")); err == nil {
+		t.Errorf("Generate should fail without a sample genotype")
+	}
+}
+
+func TestBRCA1Proof_VerifyMissingKey(t *testing.T) {
+	if verified, err := (&BRCA1Proof{}).Verify(t.Context(), "", ""); err == nil || verified {
+		t.Errorf("Verify = %v, %v; want failure", verified, err)
+	}
+}
@@ -0,0 +1,32 @@
+package proofs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+type HERC2Circuit struct {
+	ClaimedColor frontend.Variable `gnark:",public"`
+	Genotype     frontend.Variable
+}
+
+func (c *HERC2Circuit) Define(api frontend.API) error {
+	api.Sub(c.ClaimedColor, c.Genotype)
+
+	return nil
+}
+
+// errHERC2Unsupported is returned for herc2 proofs: HERC2Circuit does not
+// yet constrain the claimed color to the genotype, so no proof of it would
+// mean anything.
+var errHERC2Unsupported = withKind(ErrUnsupported, errors.New("herc2 proofs have no circuit yet; prove eye color with the eyecolor or irisplex type"))
+
+func (p *HERC2Proof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	return errHERC2Unsupported
+}
+
+func (p *HERC2Proof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	return false, errHERC2Unsupported
+}
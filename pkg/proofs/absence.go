@@ -0,0 +1,227 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// VariantAbsenceCircuit proves that a public variant is not in the variant
+// tree with a public root, by opening two adjacent leaves that sort on
+// either side of it. With WholeLocus set the claim is stronger: no carried
+// variant at all sits at the locus.
+//
+// The proof relies on the tree being sorted, which the circuit cannot check;
+// the root should come from a trusted source such as an attested VCF.
+type VariantAbsenceCircuit struct {
+	Root       frontend.Variable `gnark:",public"`
+	LocusKey   frontend.Variable `gnark:",public"`
+	VariantID  frontend.Variable `gnark:",public"`
+	WholeLocus frontend.Variable `gnark:",public"`
+
+	LowIndex      frontend.Variable
+	LowLocusKey   frontend.Variable
+	LowVariantID  frontend.Variable
+	LowSiblings   []frontend.Variable
+	HighLocusKey  frontend.Variable
+	HighVariantID frontend.Variable
+	HighSiblings  []frontend.Variable
+
+	Hash HashAlgorithm `gnark:"-"`
+}
+
+// NewVariantAbsenceCircuit allocates a circuit for a tree of depth built with hash.
+func NewVariantAbsenceCircuit(depth int, hash HashAlgorithm) *VariantAbsenceCircuit {
+	return &VariantAbsenceCircuit{
+		LowSiblings:  make([]frontend.Variable, depth),
+		HighSiblings: make([]frontend.Variable, depth),
+		Hash:         hash,
+	}
+}
+
+func (c *VariantAbsenceCircuit) Define(api frontend.API) error {
+	api.AssertIsBoolean(c.WholeLocus)
+	alleleLevel := api.Sub(1, c.WholeLocus)
+
+	// low < target: a smaller locus, or the same locus with a smaller ID
+	// when only the allele is claimed absent
+	lowBelow := api.Add(
+		isGreater(api, c.LocusKey, c.LowLocusKey),
+		api.Mul(alleleLevel, api.IsZero(api.Sub(c.LocusKey, c.LowLocusKey)), isGreater(api, c.VariantID, c.LowVariantID)),
+	)
+	api.AssertIsEqual(lowBelow, 1)
+
+	highAbove := api.Add(
+		isGreater(api, c.HighLocusKey, c.LocusKey),
+		api.Mul(alleleLevel, api.IsZero(api.Sub(c.HighLocusKey, c.LocusKey)), isGreater(api, c.HighVariantID, c.VariantID)),
+	)
+	api.AssertIsEqual(highAbove, 1)
+
+	// Both neighbours are in the tree, next to each other.
+	h, err := newFieldHasher(api, c.Hash)
+	if err != nil {
+		return err
+	}
+
+	h.Write(c.LowLocusKey, c.LowVariantID)
+	low := h.Sum()
+	api.AssertIsEqual(merkleRoot(api, h, low, c.LowIndex, c.LowSiblings), c.Root)
+
+	h.Reset()
+	h.Write(c.HighLocusKey, c.HighVariantID)
+	high := h.Sum()
+	api.AssertIsEqual(merkleRoot(api, h, high, api.Add(c.LowIndex, 1), c.HighSiblings), c.Root)
+
+	return nil
+}
+
+// neighbours returns the index of the leaf sorting just below target, so that
+// target falls strictly between it and the next leaf. It reports false when
+// target is in the tree. With wholeLocus the comparison is by locus only.
+func (t *VariantTree) neighbours(target variantLeaf, wholeLocus bool) (int, bool) {
+	var index int
+	var found bool
+	if wholeLocus {
+		index, found = slices.BinarySearchFunc(t.leaves, target.LocusKey, func(l variantLeaf, key uint64) int {
+			switch {
+			case l.LocusKey < key:
+				return -1
+			case l.LocusKey > key:
+				return 1
+			default:
+				return 0
+			}
+		})
+	} else {
+		index, found = slices.BinarySearchFunc(t.leaves, target, compareLeaves)
+	}
+	if found {
+		return 0, false
+	}
+	// The sentinels guarantee a leaf on both sides.
+	return index - 1, true
+}
+
+func (p *VariantAbsenceProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	variant, err := ParseVariantSpec(p.Variant)
+	if err != nil {
+		return err
+	}
+	target, err := newVariantLeaf(variant)
+	if err != nil {
+		return err
+	}
+
+	depth := p.TreeDepth
+	if depth == 0 {
+		depth = DefaultTreeDepth
+	}
+
+	fmt.Println("Building variant tree from VCF...")
+	tree, err := buildVariantTree(vcfPath, p.Sample, depth, p.Imputation, p.Hash, p.Limits)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	lowIndex, absent := tree.neighbours(target, p.WholeLocus)
+	if !absent {
+		if p.WholeLocus {
+			return fmt.Errorf("a variant is carried at %s", variant.Locus())
+		}
+		return fmt.Errorf("variant %s is carried in the VCF", variant)
+	}
+	low, high := tree.leaves[lowIndex], tree.leaves[lowIndex+1]
+
+	assignment := NewVariantAbsenceCircuit(tree.Depth, tree.Hash)
+	assignment.Root = tree.Root()
+	assignment.LocusKey = target.LocusKey
+	assignment.VariantID = target.VariantID
+	assignment.WholeLocus = boolToInt(p.WholeLocus)
+	assignment.LowIndex = lowIndex
+	assignment.LowLocusKey = low.LocusKey
+	assignment.LowVariantID = low.VariantID
+	assignment.HighLocusKey = high.LocusKey
+	assignment.HighVariantID = high.VariantID
+	for i, sibling := range tree.path(lowIndex) {
+		assignment.LowSiblings[i] = sibling
+	}
+	for i, sibling := range tree.path(lowIndex + 1) {
+		assignment.HighSiblings[i] = sibling
+	}
+
+	if err := p.Settings.prove(ctx, "absence", NewVariantAbsenceCircuit(tree.Depth, tree.Hash), assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("absence", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	if p.WholeLocus {
+		fmt.Printf("We have proven that no variant is carried at %s\n", variant.Locus())
+	} else {
+		fmt.Printf("We have proven that variant %s is not carried\n", variant)
+	}
+	fmt.Println("without revealing any other variant.")
+	fmt.Printf("Variant tree root: %s\n", tree.Root())
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *VariantAbsenceProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 4 {
+		return false, withKind(ErrVerification, fmt.Errorf("absence proof has %d public inputs, expected 4", len(public)))
+	}
+	wholeLocus := public[3].Sign() != 0
+	variant := "the variant at " + provenLocus(public[1])
+	switch {
+	case p.Variant != "" && wholeLocus:
+		// A whole-locus proof covers every allele at the variant's locus.
+		spec, err := ParseVariantSpec(p.Variant)
+		if err != nil {
+			return false, withKind(ErrInvalidInput, err)
+		}
+		target, err := newVariantLeaf(spec)
+		if err != nil {
+			return false, withKind(ErrInvalidInput, err)
+		}
+		if !public[1].IsUint64() || public[1].Uint64() != target.LocusKey {
+			return false, withKind(ErrVerification, fmt.Errorf("proof is for a different locus than %s", spec.Locus()))
+		}
+	case p.Variant != "":
+		v, err := checkVariantInputs(public, 1, p.Variant)
+		if err != nil {
+			return false, err
+		}
+		variant = v.String()
+	}
+	if wholeLocus {
+		fmt.Printf("No variant is carried at %s\n", provenLocus(public[1]))
+	} else {
+		fmt.Printf("Not carried: %s\n", variant)
+	}
+
+	if p.ExpectedCommitment != "" {
+		if err := checkCommitment(proofPath, 0, p.ExpectedCommitment); err != nil {
+			return false, err
+		}
+	}
+	return verified, nil
+}
@@ -0,0 +1,141 @@
+package proofs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func absenceAssignment(tree *VariantTree, target variantLeaf, lowIndex int, wholeLocus bool) *VariantAbsenceCircuit {
+	low, high := tree.leaves[lowIndex], tree.leaves[lowIndex+1]
+
+	a := NewVariantAbsenceCircuit(tree.Depth, tree.Hash)
+	a.Root = tree.Root()
+	a.LocusKey = target.LocusKey
+	a.VariantID = target.VariantID
+	a.WholeLocus = boolToInt(wholeLocus)
+	a.LowIndex = lowIndex
+	a.LowLocusKey = low.LocusKey
+	a.LowVariantID = low.VariantID
+	a.HighLocusKey = high.LocusKey
+	a.HighVariantID = high.VariantID
+	for i, sibling := range tree.path(lowIndex) {
+		a.LowSiblings[i] = sibling
+	}
+	for i, sibling := range tree.path(lowIndex + 1) {
+		a.HighSiblings[i] = sibling
+	}
+	return a
+}
+
+func TestVariantAbsenceCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	vcf := writeTempVCF(t, membershipTestVCF)
+	tree, err := BuildVariantTree(vcf, 4, ImputationPolicy{}, HashMiMC, ReadLimits{})
+	if err != nil {
+		t.Fatalf("BuildVariantTree: %v", err)
+	}
+
+	tests := []struct {
+		variant    string
+		wholeLocus bool
+		absent     bool
+	}{
+		{"17:41276044:ACT:A", false, true}, // BRCA1 185delAG, not carried
+		{"17:41276044:ACT:A", true, true},
+		{"1:2000:C:T", false, true}, // another allele is carried at the locus
+		{"1:2000:C:T", true, false},
+		{"1:1000:A:G", false, false},
+		{"1:1:A:G", false, true},  // before every carried variant
+		{"MT:1:A:G", false, true}, // after every carried variant
+	}
+
+	for _, tt := range tests {
+		target, err := newVariantLeaf(mustParseVariant(t, tt.variant))
+		if err != nil {
+			t.Fatalf("newVariantLeaf: %v", err)
+		}
+
+		lowIndex, absent := tree.neighbours(target, tt.wholeLocus)
+		if absent != tt.absent {
+			t.Errorf("%s (whole locus %v): absent = %v, want %v", tt.variant, tt.wholeLocus, absent, tt.absent)
+			continue
+		}
+		if !absent {
+			continue
+		}
+
+		err = test.IsSolved(NewVariantAbsenceCircuit(tree.Depth, tree.Hash), absenceAssignment(tree, target, lowIndex, tt.wholeLocus), field)
+		if err != nil {
+			t.Errorf("%s (whole locus %v): absence should satisfy the circuit: %v", tt.variant, tt.wholeLocus, err)
+		}
+	}
+}
+
+func TestVariantAbsenceCircuitRejectsCarriedVariant(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	vcf := writeTempVCF(t, membershipTestVCF)
+	tree, err := BuildVariantTree(vcf, 4, ImputationPolicy{}, HashMiMC, ReadLimits{})
+	if err != nil {
+		t.Fatalf("BuildVariantTree: %v", err)
+	}
+
+	carried, err := newVariantLeaf(mustParseVariant(t, "1:1000:A:G"))
+	if err != nil {
+		t.Fatalf("newVariantLeaf: %v", err)
+	}
+	index, found, err := tree.find(mustParseVariant(t, "1:1000:A:G"))
+	if err != nil || !found {
+		t.Fatalf("carried variant not found: %v", err)
+	}
+
+	// Neither pair of adjacent leaves around a carried variant can bracket it.
+	for _, lowIndex := range []int{index - 1, index} {
+		if err := test.IsSolved(NewVariantAbsenceCircuit(tree.Depth, tree.Hash), absenceAssignment(tree, carried, lowIndex, false), field); err == nil {
+			t.Errorf("carried variant should not be provably absent using leaves %d and %d", lowIndex, lowIndex+1)
+		}
+	}
+
+	// Skipping over a leaf breaks adjacency.
+	a := absenceAssignment(tree, carried, index-1, false)
+	high := tree.leaves[index+1]
+	a.HighLocusKey, a.HighVariantID = high.LocusKey, high.VariantID
+	if err := test.IsSolved(NewVariantAbsenceCircuit(tree.Depth, tree.Hash), a, field); err == nil {
+		t.Errorf("non-adjacent leaves should not satisfy the circuit")
+	}
+}
+
+func TestVariantAbsenceProofBindsVariant(t *testing.T) {
+	vcf := writeTempVCF(t, membershipTestVCF)
+	dir := t.TempDir()
+
+	out := filepath.Join(dir, "absence_proof.bin")
+	proof := &VariantAbsenceProof{Variant: "17:41276044:ACT:A", TreeDepth: 4}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); !ok || err != nil {
+		t.Errorf("Verify for the proof's variant = %v, %v", ok, err)
+	}
+	other := &VariantAbsenceProof{Variant: "17:41276044:ACT:G"}
+	if ok, err := other.Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+		t.Errorf("Verify for another variant = %v, %v; want ErrVerification", ok, err)
+	}
+
+	// A whole-locus proof covers every allele at the locus, and no other.
+	out = filepath.Join(dir, "absence_locus_proof.bin")
+	proof = &VariantAbsenceProof{Variant: "17:41276044:ACT:A", WholeLocus: true, TreeDepth: 4}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate for the whole locus: %v", err)
+	}
+	if ok, err := other.Verify(t.Context(), out+".vk", out); !ok || err != nil {
+		t.Errorf("Verify of a whole-locus proof for another allele = %v, %v", ok, err)
+	}
+	elsewhere := &VariantAbsenceProof{Variant: "17:41276045:ACT:A"}
+	if ok, err := elsewhere.Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+		t.Errorf("Verify of a whole-locus proof for another locus = %v, %v; want ErrVerification", ok, err)
+	}
+}
@@ -0,0 +1,97 @@
+package proofs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	icicle_bn254 "github.com/consensys/gnark/backend/groth16/bn254/icicle"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// Accelerator selects the hardware Groth16 proofs are computed on. The zero
+// value is the CPU.
+type Accelerator string
+
+const (
+	AcceleratorCPU Accelerator = "cpu"
+	// AcceleratorGPU proves Groth16 over BN254 with ICICLE on a CUDA GPU.
+	// It needs a binary built with the icicle tag and the ICICLE libraries
+	// installed; anything else falls back to the CPU.
+	AcceleratorGPU Accelerator = "gpu"
+)
+
+// ParseAccelerator parses an accelerator name; empty means the CPU.
+func ParseAccelerator(s string) (Accelerator, error) {
+	switch a := Accelerator(strings.ToLower(s)); a {
+	case "", AcceleratorCPU:
+		return AcceleratorCPU, nil
+	case AcceleratorGPU:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown accelerator %q (expected cpu or gpu)", s)
+	}
+}
+
+func (a Accelerator) String() string {
+	if a == "" {
+		return string(AcceleratorCPU)
+	}
+	return string(a)
+}
+
+// gpuUnsupported reports why a proof cannot run on the GPU, or "" when it
+// can.
+func gpuUnsupported(b BackendName, curve Curve) string {
+	switch {
+	case b == BackendPlonk:
+		return "GPU proving only supports groth16"
+	case curve.id() != ecc.BN254:
+		return "GPU proving only supports bn254"
+	case !icicle_bn254.HasIcicle:
+		return "this binary was built without the icicle tag"
+	}
+	return ""
+}
+
+// proveOnGPU proves with ICICLE. ICICLE reports device failures by
+// panicking, so a panic in the calling goroutine is returned as an error
+// for the caller to fall back on.
+func proveOnGPU(cs constraint.ConstraintSystem, pk groth16.ProvingKey, w witness.Witness, opts []backend.ProverOption) (proof groth16.Proof, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return groth16.Prove(cs, pk, w, append(opts, backend.WithIcicleAcceleration())...)
+}
+
+// proveGroth16 proves on the accelerator, falling back to the CPU when the
+// GPU cannot be used or fails, and logs how long proving took.
+func proveGroth16(accel Accelerator, curve Curve, cs constraint.ConstraintSystem, pk groth16.ProvingKey, w witness.Witness, opts []backend.ProverOption) (groth16.Proof, error) {
+	if accel == AcceleratorGPU {
+		if reason := gpuUnsupported(BackendGroth16, curve); reason != "" {
+			fmt.Printf("Warning: %s; proving on CPU\n", reason)
+		} else {
+			start := time.Now()
+			proof, err := proveOnGPU(cs, pk, w, opts)
+			if err == nil {
+				fmt.Printf("Proved %d constraints on GPU in %s\n", cs.GetNbConstraints(), time.Since(start).Round(time.Millisecond))
+				return proof, nil
+			}
+			fmt.Printf("Warning: GPU proving failed after %s (%v); proving on CPU\n", time.Since(start).Round(time.Millisecond), err)
+		}
+	}
+
+	start := time.Now()
+	proof, err := groth16.Prove(cs, pk, w, opts...)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Proved %d constraints on CPU in %s\n", cs.GetNbConstraints(), time.Since(start).Round(time.Millisecond))
+	return proof, nil
+}
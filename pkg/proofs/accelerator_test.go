@@ -0,0 +1,44 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseAccelerator(t *testing.T) {
+	for s, want := range map[string]Accelerator{"": AcceleratorCPU, "cpu": AcceleratorCPU, "GPU": AcceleratorGPU} {
+		if got, err := ParseAccelerator(s); err != nil || got != want {
+			t.Errorf("ParseAccelerator(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseAccelerator("tpu"); err == nil {
+		t.Errorf("ParseAccelerator should reject unknown accelerators")
+	}
+}
+
+func TestGPUFallback(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+
+	// Without a usable GPU every proof falls back to the CPU.
+	for _, settings := range []Settings{
+		{Accelerator: AcceleratorGPU},
+		{Accelerator: AcceleratorGPU, Curve: CurveBLS12381},
+	} {
+		out := filepath.Join(dir, settings.Curve.String()+".bin")
+		proof := &ZygosityProof{Settings: settings, Locus: "15:28365618"}
+		if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+			t.Fatalf("%s: Generate: %v", settings.Curve, err)
+		}
+		if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+			t.Errorf("%s: Verify = %v, %v", settings.Curve, ok, err)
+		}
+	}
+	if reason := gpuUnsupported(BackendPlonk, CurveBN254); reason == "" {
+		t.Errorf("PLONK proofs should not run on the GPU")
+	}
+}
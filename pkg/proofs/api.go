@@ -0,0 +1,484 @@
+package proofs
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// An Option configures Generate or Verify.
+type Option func(*options)
+
+type options struct {
+	cfg          ProofConfig
+	provingKey   string
+	verifyingKey string
+	// provingKeyData and verifyingKeyData are keys given in memory.
+	provingKeyData   []byte
+	verifyingKeyData []byte
+	proofType        string
+	holderKey        ed25519.PublicKey
+	now              time.Time
+	publicInputs     []*big.Int
+	ipfs             *IPFS
+	keyDID           string
+	holderDID        string
+	resolver         *DIDResolver
+	registry         *KeyRegistry
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithConfig replaces the whole configuration with cfg; options after it
+// change individual fields. It reaches the options of ProofConfig no
+// other Option covers.
+func WithConfig(cfg ProofConfig) Option {
+	return func(o *options) { o.cfg = cfg }
+}
+
+// WithSettings replaces the settings shared by every proof type.
+func WithSettings(s Settings) Option {
+	return func(o *options) { o.cfg.Settings = s }
+}
+
+// WithProvingKey proves with the proving key at path instead of running
+// the setup, for Generate.
+func WithProvingKey(path string) Option {
+	return func(o *options) { o.provingKey, o.provingKeyData = path, nil }
+}
+
+// WithVerifyingKey verifies with the key at path instead of the one
+// beside the proof, for Verify.
+func WithVerifyingKey(path string) Option {
+	return func(o *options) { o.verifyingKey, o.verifyingKeyData = path, nil }
+}
+
+// WithProvingKeyData is WithProvingKey for a proving key held in memory.
+func WithProvingKeyData(data []byte) Option {
+	return func(o *options) { o.provingKey, o.provingKeyData = "", data }
+}
+
+// WithVerifyingKeyData is WithVerifyingKey for a verifying key held in
+// memory.
+func WithVerifyingKeyData(data []byte) Option {
+	return func(o *options) { o.verifyingKey, o.verifyingKeyData = "", data }
+}
+
+// WithIPFS has Verify fetch the verifying key of a ProofEnvelope from IPFS
+// through ipfs, by the CID the envelope records, when no key is given and
+// none is found beside the envelope.
+func WithIPFS(ipfs *IPFS) Option {
+	return func(o *options) { o.ipfs = ipfs }
+}
+
+// WithProofType has Verify reject proofs of any other type than name
+// rather than verify the type the proof file declares.
+func WithProofType(name string) Option {
+	return func(o *options) { o.proofType = name }
+}
+
+// WithBackend proves with backend; Verify reads it from the proof's
+// metadata.
+func WithBackend(backend BackendName) Option {
+	return func(o *options) { o.cfg.Settings.Backend = backend }
+}
+
+// WithCurve proves over curve; Verify reads it from the proof's metadata.
+func WithCurve(curve Curve) Option {
+	return func(o *options) { o.cfg.Settings.Curve = curve }
+}
+
+// WithNonce binds the verifier's challenge into the proof, or has Verify
+// require the proof to be bound to it.
+func WithNonce(nonce string) Option {
+	return func(o *options) { o.cfg.Settings.Nonce = nonce }
+}
+
+// WithValidFor binds the proof's issuance time and an expiry time d
+// later into it, for Generate.
+func WithValidFor(d time.Duration) Option {
+	return func(o *options) { o.cfg.Settings.ValidFor = d }
+}
+
+// WithKeyStore reads keys through ks, which calls sharing it read once.
+func WithKeyStore(ks *KeyStore) Option {
+	return func(o *options) { o.cfg.Settings.KeyStore = ks }
+}
+
+// WithProgress has Generate report the progress of compiling, setting up
+// and proving to progress.
+func WithProgress(progress ProgressFunc) Option {
+	return func(o *options) { o.cfg.Settings.Progress = progress }
+}
+
+// WithHolderSecret adds a nullifier derived from secret to the proof, for
+// Generate.
+func WithHolderSecret(secret *big.Int) Option {
+	return func(o *options) { o.cfg.Settings.HolderSecret = secret }
+}
+
+// WithSignKey signs the proof with the holder's key, for Generate.
+func WithSignKey(key ed25519.PrivateKey) Option {
+	return func(o *options) { o.cfg.Settings.SignKey = key }
+}
+
+// WithHolderKey has Verify require the proof to be signed with key.
+func WithHolderKey(key ed25519.PublicKey) Option {
+	return func(o *options) { o.holderKey = key }
+}
+
+// WithSignDID names the holder signing the proof by did, a did:web
+// identifier whose document lists the signing key, for Generate.
+func WithSignDID(did string) Option {
+	return func(o *options) { o.cfg.Settings.SignDID = did }
+}
+
+// WithHolderDID has Verify require the proof to be signed with a key the
+// DID document of did lists as an assertion method.
+func WithHolderDID(did string) Option {
+	return func(o *options) { o.holderDID = did }
+}
+
+// WithKeyDID has Verify require the verifying key to be the one the DID
+// document of did publishes for the proof type, fetching it from there
+// when no key is given and none is found beside the proof.
+func WithKeyDID(did string) Option {
+	return func(o *options) { o.keyDID = did }
+}
+
+// WithKeyRegistry has Verify require the verifying key to be the one the
+// on-chain registry r holds for the proof's circuit.
+func WithKeyRegistry(r *KeyRegistry) Option {
+	return func(o *options) { o.registry = r }
+}
+
+// WithDIDResolver resolves the DIDs of WithHolderDID and WithKeyDID
+// through r rather than a DIDResolver with the default HTTP client.
+func WithDIDResolver(r *DIDResolver) Option {
+	return func(o *options) { o.resolver = r }
+}
+
+// WithTime has Verify check the proof's expiry at now rather than the
+// current time.
+func WithTime(now time.Time) Option {
+	return func(o *options) { o.now = now }
+}
+
+// WithSample proves the calls of the named sample of a multi-sample VCF
+// rather than the first sample's, for Generate.
+func WithSample(name string) Option {
+	return func(o *options) { o.cfg.Settings.Sample = name }
+}
+
+// WithPublicInputs states the values of the proof's leading public inputs,
+// in the order its ProofType lists them; a nil value matches any. Generate
+// fails with an error of kind ErrNotFound and writes no proof when the VCF
+// gives other values, such as another eye color than the claimed one, and
+// Verify rejects proofs stating other values.
+func WithPublicInputs(inputs ...*big.Int) Option {
+	return func(o *options) { o.publicInputs = inputs }
+}
+
+// WithTarget sets the chromosome a chromosome proof shows is present, or
+// that Verify requires it to show.
+func WithTarget(chromosome int) Option {
+	return func(o *options) { o.cfg.Target = chromosome }
+}
+
+// WithPosition sets the chrom:pos locus of zygosity and wildtype proofs.
+func WithPosition(position string) Option {
+	return func(o *options) { o.cfg.Position = position }
+}
+
+// WithVariant sets the chrom:pos:ref:alt variant of the proof types
+// proving one.
+func WithVariant(variant string) Option {
+	return func(o *options) { o.cfg.Variant = variant }
+}
+
+// WithGene sets the gene of diplotype, compound heterozygosity and gene
+// panel proofs.
+func WithGene(gene string) Option {
+	return func(o *options) { o.cfg.Gene = gene }
+}
+
+// WithPanel sets the SNP panel of dosage, threshold and panel proofs.
+func WithPanel(panel []VariantSpec) Option {
+	return func(o *options) { o.cfg.Panel = panel }
+}
+
+// WithSecondVariant sets the variant whose phase relative to the
+// WithVariant variant a haplotype proof claims.
+func WithSecondVariant(variant string) Option {
+	return func(o *options) { o.cfg.SecondVariant = variant }
+}
+
+// WithRegion sets the chrom:start-end region of region and structural
+// variant proofs.
+func WithRegion(region string) Option {
+	return func(o *options) { o.cfg.Region = region }
+}
+
+// WithSVType sets the structural variant type, DEL or DUP, of structural
+// variant proofs.
+func WithSVType(svType string) Option {
+	return func(o *options) { o.cfg.SVType = svType }
+}
+
+// WithK sets the minimum number of panel variants of threshold proofs;
+// Verify leaves it unchecked when zero.
+func WithK(k int) Option {
+	return func(o *options) { o.cfg.K = k }
+}
+
+// WithLocus sets the repeat locus ID, such as HTT, of repeat proofs, and
+// the pathogenic repeat count threshold; zero means the locus default.
+func WithLocus(locus string, threshold int) Option {
+	return func(o *options) { o.cfg.Locus, o.cfg.RepeatThreshold = locus, threshold }
+}
+
+// WithQuality sets the call quality a proof enforces, or that Verify
+// requires it to have enforced.
+func WithQuality(q QualityThresholds) Option {
+	return func(o *options) { o.cfg.Quality = q }
+}
+
+// Generate proves a proof of type proofType from the VCF at vcfPath and
+// writes it to outputPath, with its metadata and, unless WithProvingKey is
+// given, its proving and verifying keys beside it. The directory of
+// outputPath is created if needed. Its stages are traced as spans under a
+// proofs.Generate span, a child of ctx's span.
+func Generate(ctx context.Context, proofType string, vcfPath string, outputPath string, opts ...Option) (err error) {
+	ctx, span := startSpan(ctx, "proofs.Generate", attribute.String("proof.type", proofType))
+	defer func() { endSpan(span, err) }()
+	o := newOptions(opts)
+	proof, err := NewProof(proofType, o.cfg)
+	if err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+	if o.provingKeyData != nil {
+		path, release, err := memoryKey(o.provingKeyData, "proof.pk")
+		if err != nil {
+			return err
+		}
+		defer release()
+		o.provingKey = path
+	}
+	if err := mkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	if err := proof.Generate(ctx, vcfPath, o.provingKey, outputPath); err != nil {
+		return err
+	}
+	if err := checkPublicInputs(outputPath, o.publicInputs); err != nil {
+		removeFile(outputPath)
+		removeFile(outputPath + MetadataSuffix)
+		return withKind(ErrNotFound, fmt.Errorf("the VCF does not support the claim: %w", err))
+	}
+	return nil
+}
+
+// VerificationResult records what Verify checked, for a relying party to
+// log or display.
+type VerificationResult struct {
+	// Valid is set when the proof passed every check.
+	Valid     bool   `json:"valid"`
+	ProofType string `json:"proof_type"`
+	// CircuitID is the hex SHA-256 of the constraint system named by the
+	// proof file's header; empty for proofs written before proof files had
+	// one.
+	CircuitID string `json:"circuit_id,omitempty"`
+	// PublicInputs are the proof's public inputs by name, as
+	// ReadPublicInputs returns them.
+	PublicInputs map[string]any `json:"public_inputs,omitempty"`
+	// KeyFingerprint is the KeyFingerprint of the verifying key the proof
+	// was checked against.
+	KeyFingerprint string        `json:"key_fingerprint"`
+	VerifiedAt     time.Time     `json:"verified_at"`
+	Duration       time.Duration `json:"duration"`
+}
+
+// Verify verifies the proof at proofPath against the verifying key beside
+// it, as a proof of the type its file declares. proofPath may instead name
+// a ProofEnvelope, whose verifying key is looked for beside the proof it
+// was exported from: without EnvelopeSuffix, plus .vk. The options add the
+// checks a verifier needs beyond the proof itself: WithNonce, WithHolderKey,
+// WithPublicInputs and the fields a proof type checks its public inputs
+// against, such as WithPosition. A proof past its bound expiry time is
+// rejected.
+//
+// A proof failing any check gets an error of kind ErrVerification and a
+// result with Valid unset, so that the rejection can be logged as well.
+// The result is nil when the proof or key cannot be read.
+func Verify(ctx context.Context, proofPath string, opts ...Option) (res *VerificationResult, err error) {
+	ctx, span := startSpan(ctx, "proofs.Verify")
+	defer func() {
+		if res != nil {
+			span.SetAttributes(attribute.String("proof.type", res.ProofType), attribute.Bool("proofs.valid", res.Valid))
+		}
+		endSpan(span, err)
+	}()
+	o := newOptions(opts)
+	if IsProofEnvelope(proofPath) {
+		if o.verifyingKey == "" && o.verifyingKeyData == nil {
+			o.verifyingKey = strings.TrimSuffix(proofPath, EnvelopeSuffix) + ".vk"
+			if _, err := statFile(o.verifyingKey); err != nil && o.ipfs != nil {
+				env, err := ReadProofEnvelopeFile(proofPath)
+				if err != nil {
+					return nil, err
+				}
+				if o.verifyingKeyData, err = env.FetchVerifyingKey(ctx, o.ipfs); err != nil {
+					return nil, err
+				}
+			}
+		}
+		unpacked, cleanup, err := unpackEnvelope(proofPath)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		proofPath = unpacked
+	}
+	proofType := o.proofType
+	if proofType == "" {
+		detected, err := DetectProofType(proofPath)
+		if err != nil {
+			return nil, withKind(ErrInvalidInput, err)
+		}
+		proofType = detected
+	} else if err := CheckProofType(proofPath, proofType); err != nil {
+		return nil, err
+	}
+	if o.resolver == nil {
+		o.resolver = &DIDResolver{}
+	}
+	if o.keyDID != "" && o.verifyingKeyData == nil {
+		path := o.verifyingKey
+		if path == "" {
+			path = proofPath + ".vk"
+		}
+		if _, err := statFile(path); err != nil {
+			if o.verifyingKeyData, err = o.resolver.FetchVerifyingKey(ctx, o.keyDID, proofType); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if o.verifyingKeyData != nil {
+		path, release, err := memoryKey(o.verifyingKeyData, "proof.vk")
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		o.verifyingKey = path
+	}
+	if o.verifyingKey == "" {
+		o.verifyingKey = proofPath + ".vk"
+	}
+	if o.now.IsZero() {
+		o.now = time.Now()
+	}
+
+	proof, err := NewProof(proofType, o.cfg)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+	fingerprint, err := o.cfg.Settings.KeyStore.fingerprint(o.verifyingKey)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	res = &VerificationResult{ProofType: proofType, KeyFingerprint: fingerprint, VerifiedAt: start}
+	if header, err := ReadProofHeader(proofPath); err == nil {
+		res.CircuitID = header.CircuitHash
+	}
+	if inputs, err := ReadPublicInputs(proofPath); err == nil {
+		res.PublicInputs = inputs
+	}
+	if o.keyDID != "" {
+		err = o.resolver.CheckVerifyingKey(ctx, o.keyDID, proofType, fingerprint)
+	}
+	if err == nil && o.registry != nil {
+		err = o.registry.CheckVerifyingKey(ctx, res.CircuitID, fingerprint)
+	}
+	if err == nil {
+		err = verifyChecks(ctx, proofType, proof, proofPath, o)
+	}
+	res.Duration = time.Since(start)
+	if err != nil {
+		if !errors.Is(ErrorKind(err), ErrVerification) {
+			return nil, err
+		}
+		return res, err
+	}
+	res.Valid = true
+	return res, nil
+}
+
+// verifyChecks verifies the proof of proofType at proofPath and runs the
+// checks o asks for on it.
+func verifyChecks(ctx context.Context, proofType string, proof Proof, proofPath string, o *options) error {
+	verified, err := proof.Verify(ctx, o.verifyingKey, proofPath)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return withKind(ErrVerification, fmt.Errorf("%s proof does not verify", proofType))
+	}
+
+	if o.cfg.Settings.Nonce != "" {
+		if err := CheckNonce(proofPath, o.cfg.Settings.Nonce); err != nil {
+			return err
+		}
+	}
+	if err := CheckExpiry(proofPath, o.now); err != nil {
+		return err
+	}
+	if o.holderKey != nil {
+		if err := CheckHolderSignature(proofPath, o.holderKey); err != nil {
+			return err
+		}
+	}
+	if o.holderDID != "" {
+		if err := o.resolver.CheckSigner(ctx, proofPath, o.holderDID); err != nil {
+			return err
+		}
+	}
+	if err := checkPublicInputs(proofPath, o.publicInputs); err != nil {
+		return withKind(ErrVerification, err)
+	}
+	return nil
+}
+
+// checkPublicInputs checks that the leading public inputs of the proof at
+// proofPath are want, skipping nil values.
+func checkPublicInputs(proofPath string, want []*big.Int) error {
+	if len(want) == 0 {
+		return nil
+	}
+	inputs, err := readPublicInputs(proofPath)
+	if err != nil {
+		return err
+	}
+	if len(want) > len(inputs) {
+		return withKind(ErrInvalidInput, fmt.Errorf("%d public inputs given, but the proof has %d", len(want), len(inputs)))
+	}
+	for i, v := range want {
+		if v != nil && v.Cmp(inputs[i]) != 0 {
+			return fmt.Errorf("public input %d is %s, not %s", i, inputs[i], v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package proofs
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerateVerify(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	out := filepath.Join(t.TempDir(), "proofs", "zygosity_proof.bin")
+	if err := Generate(t.Context(), "zygosity", vcf, out, WithPosition("15:28365618"), WithNonce("challenge"), WithValidFor(time.Hour), WithSignKey(key)); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	res, err := Verify(t.Context(), out, WithNonce("challenge"), WithHolderKey(pub), WithPosition("15:28365618"))
+	if err != nil || !res.Valid {
+		t.Fatalf("Verify = %+v, %v", res, err)
+	}
+	header, _ := ReadProofHeader(out)
+	vk, _ := KeyFingerprint(out + ".vk")
+	if res.ProofType != "zygosity" || res.CircuitID != header.CircuitHash || res.KeyFingerprint != vk || res.PublicInputs["nonce"] != "challenge" || res.VerifiedAt.IsZero() {
+		t.Errorf("Verify result = %+v", res)
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	tests := []struct {
+		name string
+		opts []Option
+		kind error
+	}{
+		{"stale nonce", []Option{WithNonce("old")}, ErrVerification},
+		{"other locus", []Option{WithPosition("15:28365619")}, nil},
+		{"other holder", []Option{WithHolderKey(otherPub)}, ErrVerification},
+		{"expired", []Option{WithTime(time.Now().Add(2 * time.Hour))}, ErrVerification},
+		{"other type", []Option{WithProofType("wildtype")}, ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		res, err := Verify(t.Context(), out, tt.opts...)
+		if err == nil {
+			t.Errorf("%s: Verify = %+v; want an error", tt.name, res)
+			continue
+		}
+		if tt.kind != nil && !errors.Is(ErrorKind(err), tt.kind) {
+			t.Errorf("%s: error kind %v, want %v (%v)", tt.name, ErrorKind(err), tt.kind, err)
+		}
+		if tt.kind == ErrVerification && (res == nil || res.Valid) {
+			t.Errorf("%s: rejected proof's result = %+v", tt.name, res)
+		}
+	}
+
+	if err := Generate(t.Context(), "nosuchtype", vcf, out); !errors.Is(ErrorKind(err), ErrInvalidInput) {
+		t.Errorf("Generate of an unknown type: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	cancelled := filepath.Join(t.TempDir(), "cancelled_proof.bin")
+	if err := Generate(ctx, "zygosity", vcf, cancelled, WithPosition("15:28365618")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Generate with a cancelled context: %v", err)
+	}
+	if _, err := os.Stat(cancelled); !os.IsNotExist(err) {
+		t.Errorf("cancelled Generate wrote a proof")
+	}
+	if _, err := Verify(ctx, out); !errors.Is(err, context.Canceled) {
+		t.Errorf("Verify with a cancelled context: %v", err)
+	}
+}
+
+func TestGenerateClaims(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1	S2
+15	28365618	.	A	G	60	PASS	.	GT	1|0	1|1
+`)
+	dir := t.TempDir()
+	homozygous := WithPublicInputs(nil, big.NewInt(0))
+
+	out := filepath.Join(dir, "s2.bin")
+	if err := Generate(t.Context(), "zygosity", vcf, out, WithPosition("15:28365618"), WithSample("S2"), homozygous); err != nil {
+		t.Fatalf("Generate for S2: %v", err)
+	}
+	if res, err := Verify(t.Context(), out, WithPosition("15:28365618"), homozygous); err != nil || !res.Valid {
+		t.Errorf("Verify of the claim = %+v, %v", res, err)
+	}
+	if _, err := Verify(t.Context(), out, WithPublicInputs(nil, big.NewInt(1))); !errors.Is(ErrorKind(err), ErrVerification) {
+		t.Errorf("Verify of another claim: %v", err)
+	}
+
+	// The first sample is heterozygous, so the claim fails and no proof
+	// is left behind.
+	first := filepath.Join(dir, "s1.bin")
+	if err := Generate(t.Context(), "zygosity", vcf, first, WithPosition("15:28365618"), homozygous); !errors.Is(ErrorKind(err), ErrNotFound) {
+		t.Errorf("Generate of an unsupported claim: %v", err)
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Errorf("Generate of an unsupported claim left a proof")
+	}
+
+	if err := Generate(t.Context(), "zygosity", vcf, filepath.Join(dir, "s3.bin"), WithPosition("15:28365618"), WithSample("S3")); !errors.Is(ErrorKind(err), ErrNotFound) {
+		t.Errorf("Generate for a missing sample: %v", err)
+	}
+}
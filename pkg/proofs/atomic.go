@@ -0,0 +1,92 @@
+package proofs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes path with write through a temporary file in the
+// same directory that is renamed over path once complete, so a crash or a
+// failed write never leaves a truncated file behind: readers see the old
+// file or the new one. A Storage replaces files whole on its own.
+func writeFileAtomic(path string, perm os.FileMode, write func(io.Writer) error) error {
+	if s, name, err := storagePath(path); s != nil || err != nil {
+		if err != nil {
+			return err
+		}
+		return writeStorageFile(s, name, write)
+	}
+	if _, _, ok := memoryPath(path); ok {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			return err
+		}
+		return writeFile(path, buf.Bytes(), perm)
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// writeTo adapts v to writeFileAtomic.
+func writeTo(v io.WriterTo) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := v.WriteTo(w)
+		return err
+	}
+}
+
+// writeBytes adapts data to writeFileAtomic.
+func writeBytes(data []byte) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}
+}
+
+// checkOutput refuses to replace an existing proof at outputPath, or the
+// key pair a setup would write beside it when no proving key is given,
+// unless the settings force it. Replacing keys invalidates every proof
+// already issued against them.
+func (s Settings) checkOutput(outputPath string, provingKeyPath string) error {
+	if s.Force {
+		return nil
+	}
+	paths := []string{outputPath}
+	if provingKeyPath == "" {
+		paths = append(paths, outputPath+".pk", outputPath+".vk")
+	}
+	return refuseOverwrite(paths...)
+}
+
+// refuseOverwrite rejects the first of paths that exists.
+func refuseOverwrite(paths ...string) error {
+	for _, path := range paths {
+		if _, err := statFile(path); err == nil {
+			return withKind(ErrInvalidInput, fmt.Errorf("%s: %w; force overwriting to replace it", path, os.ErrExist))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package proofs
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverwriteProtection(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	proof := &ZygosityProof{Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	vk, err := os.ReadFile(out + ".vk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = proof.Generate(t.Context(), vcf, "", out)
+	if ErrorKind(err) != ErrInvalidInput || !errors.Is(err, os.ErrExist) {
+		t.Errorf("Generate over an existing proof = %v; want it refused", err)
+	}
+	if again, _ := os.ReadFile(out + ".vk"); !bytes.Equal(again, vk) {
+		t.Error("refused Generate replaced the verifying key")
+	}
+
+	// A proof beside keys from setup is refused too, but one proved with
+	// a given key leaves the keys alone.
+	other := filepath.Join(dir, "other.bin")
+	if err := os.WriteFile(other+".vk", vk, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.Generate(t.Context(), vcf, "", other); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Generate over existing keys = %v; want it refused", err)
+	}
+	if err := proof.Generate(t.Context(), vcf, out+".pk", other); err != nil {
+		t.Errorf("Generate with a proving key beside existing keys: %v", err)
+	}
+
+	proof.Force = true
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("forced Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Errorf("Verify after forced Generate = %v, %v", ok, err)
+	}
+
+	keys := filepath.Join(dir, "keys")
+	setup := &ZygosityProof{Locus: "15:28365618"}
+	if err := SetupKeys(setup, keys, SetupOptions{}); err != nil {
+		t.Fatalf("SetupKeys: %v", err)
+	}
+	if err := SetupKeys(setup, keys, SetupOptions{}); !errors.Is(err, os.ErrExist) {
+		t.Errorf("SetupKeys over existing keys = %v; want it refused", err)
+	}
+	if err := SetupKeys(setup, keys, SetupOptions{Force: true}); err != nil {
+		t.Errorf("forced SetupKeys: %v", err)
+	}
+
+	// Writes go through temporary files that never outlive them.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name()[0] == '.' {
+			t.Errorf("temporary file %s left behind", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package proofs
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	kzg_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+)
+
+// BackendName selects the proof system proofs are generated with, and is
+// recorded in their metadata. The zero value is Groth16.
+type BackendName string
+
+const (
+	// BackendGroth16 needs a trusted setup per circuit, run on the first
+	// Generate without a proving key.
+	BackendGroth16 BackendName = "groth16"
+	// BackendPlonk derives its keys from a universal KZG SRS, so one
+	// ceremony serves every circuit up to the SRS size.
+	BackendPlonk BackendName = "plonk"
+)
+
+// ParseBackend parses a backend name; empty means Groth16.
+func ParseBackend(s string) (BackendName, error) {
+	switch b := BackendName(strings.ToLower(s)); b {
+	case "", BackendGroth16:
+		return BackendGroth16, nil
+	case BackendPlonk:
+		return b, nil
+	default:
+		if _, ok := backends[b]; ok {
+			return b, nil
+		}
+		return "", fmt.Errorf("unknown backend %q (expected groth16 or plonk)", s)
+	}
+}
+
+func (b BackendName) String() string {
+	if b == "" {
+		return string(BackendGroth16)
+	}
+	return string(b)
+}
+
+// Serializable is a key or proof of a backend.
+type Serializable interface {
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// Backend is a proof system. Proofs are compiled, set up, proved, verified
+// and deserialized only through it, so another proving system, or a mock in
+// tests, is added with RegisterBackend without changing any trait.
+type Backend interface {
+	// Compile compiles circuit to the backend's constraint system over the
+	// curve's scalar field.
+	Compile(curve Curve, circuit frontend.Circuit) (constraint.ConstraintSystem, error)
+	// Setup derives a proving and verifying key for cs. srsPath is the
+	// universal SRS for backends that use one; empty samples a
+	// development SRS.
+	Setup(cs constraint.ConstraintSystem, curve Curve, srsPath string) (pk Serializable, vk Serializable, err error)
+	// Prove proves the full witness w, on accel where the backend
+	// supports it.
+	Prove(cs constraint.ConstraintSystem, curve Curve, accel Accelerator, pk Serializable, w witness.Witness) (Serializable, error)
+	// Verify checks proof against vk and the public witness.
+	Verify(curve Curve, proof, vk Serializable, publicWitness witness.Witness) error
+
+	// NewConstraintSystem, NewProvingKey, NewVerifyingKey and NewProof
+	// return empty values over curve to deserialize into.
+	NewConstraintSystem(curve Curve) constraint.ConstraintSystem
+	NewProvingKey(curve Curve) Serializable
+	NewVerifyingKey(curve Curve) Serializable
+	NewProof(curve Curve) Serializable
+}
+
+var backends = map[BackendName]Backend{
+	BackendGroth16: groth16Backend{},
+	BackendPlonk:   plonkBackend{},
+}
+
+// RegisterBackend makes b available as name to Settings, ParseBackend and
+// the verification of proofs whose metadata records name. It replaces any
+// backend already registered as name and is not safe for concurrent use,
+// so call it during initialization.
+func RegisterBackend(name BackendName, b Backend) {
+	backends[name] = b
+}
+
+// backend returns the backend registered as b.
+func (b BackendName) backend() (Backend, error) {
+	if b == "" {
+		b = BackendGroth16
+	}
+	impl, ok := backends[b]
+	if !ok {
+		return nil, fmt.Errorf("unknown backend %q", string(b))
+	}
+	return impl, nil
+}
+
+// groth16Backend is Groth16 through gnark.
+type groth16Backend struct{}
+
+func (groth16Backend) Compile(curve Curve, circuit frontend.Circuit) (constraint.ConstraintSystem, error) {
+	return frontend.Compile(curve.id().ScalarField(), r1cs.NewBuilder, circuit)
+}
+
+func (groth16Backend) Setup(cs constraint.ConstraintSystem, curve Curve, srsPath string) (Serializable, Serializable, error) {
+	return groth16.Setup(cs)
+}
+
+// Prove hashes the commitments of proofs over BLS12-377 to the field the
+// way a BW6-761 verifier circuit does, so they can be aggregated
+// recursively.
+func (groth16Backend) Prove(cs constraint.ConstraintSystem, curve Curve, accel Accelerator, pk Serializable, w witness.Witness) (Serializable, error) {
+	groth16Key, ok := pk.(groth16.ProvingKey)
+	if !ok {
+		return nil, fmt.Errorf("proving key is not a Groth16 key")
+	}
+	var opts []backend.ProverOption
+	if curve.id() == ecc.BLS12_377 {
+		opts = append(opts, stdgroth16.GetNativeProverOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField()))
+	}
+	return proveGroth16(accel, curve, cs, groth16Key, w, opts)
+}
+
+func (groth16Backend) Verify(curve Curve, proof, vk Serializable, publicWitness witness.Witness) error {
+	groth16Proof, ok := proof.(groth16.Proof)
+	if !ok {
+		return fmt.Errorf("proof is not a Groth16 proof")
+	}
+	groth16Key, ok := vk.(groth16.VerifyingKey)
+	if !ok {
+		return fmt.Errorf("verifying key is not a Groth16 key")
+	}
+	var opts []backend.VerifierOption
+	if curve.id() == ecc.BLS12_377 {
+		opts = append(opts, stdgroth16.GetNativeVerifierOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField()))
+	}
+	return groth16.Verify(groth16Proof, groth16Key, publicWitness, opts...)
+}
+
+func (groth16Backend) NewConstraintSystem(curve Curve) constraint.ConstraintSystem {
+	return groth16.NewCS(curve.id())
+}
+
+func (groth16Backend) NewProvingKey(curve Curve) Serializable {
+	return groth16.NewProvingKey(curve.id())
+}
+
+func (groth16Backend) NewVerifyingKey(curve Curve) Serializable {
+	return groth16.NewVerifyingKey(curve.id())
+}
+
+func (groth16Backend) NewProof(curve Curve) Serializable {
+	return groth16.NewProof(curve.id())
+}
+
+// plonkBackend is PLONK with KZG commitments through gnark. It compiles to
+// sparse R1CS.
+type plonkBackend struct{}
+
+func (plonkBackend) Compile(curve Curve, circuit frontend.Circuit) (constraint.ConstraintSystem, error) {
+	return frontend.Compile(curve.id().ScalarField(), scs.NewBuilder, circuit)
+}
+
+// Setup derives the keys from the KZG SRS at srsPath, or from a freshly
+// sampled development SRS when it is empty.
+func (plonkBackend) Setup(cs constraint.ConstraintSystem, curve Curve, srsPath string) (Serializable, Serializable, error) {
+	canonical, lagrange, err := loadSRS(cs, curve, srsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plonk.Setup(cs, canonical, lagrange)
+}
+
+func (plonkBackend) Prove(cs constraint.ConstraintSystem, curve Curve, accel Accelerator, pk Serializable, w witness.Witness) (Serializable, error) {
+	if accel == AcceleratorGPU {
+		fmt.Printf("Warning: %s; proving on CPU\n", gpuUnsupported(BackendPlonk, curve))
+	}
+	plonkKey, ok := pk.(plonk.ProvingKey)
+	if !ok {
+		return nil, fmt.Errorf("proving key is not a PLONK key")
+	}
+	return plonk.Prove(cs, plonkKey, w)
+}
+
+func (plonkBackend) Verify(curve Curve, proof, vk Serializable, publicWitness witness.Witness) error {
+	plonkProof, ok := proof.(plonk.Proof)
+	if !ok {
+		return fmt.Errorf("proof is not a PLONK proof")
+	}
+	plonkKey, ok := vk.(plonk.VerifyingKey)
+	if !ok {
+		return fmt.Errorf("verifying key is not a PLONK key")
+	}
+	return plonk.Verify(plonkProof, plonkKey, publicWitness)
+}
+
+func (plonkBackend) NewConstraintSystem(curve Curve) constraint.ConstraintSystem {
+	return plonk.NewCS(curve.id())
+}
+
+func (plonkBackend) NewProvingKey(curve Curve) Serializable {
+	return plonk.NewProvingKey(curve.id())
+}
+
+func (plonkBackend) NewVerifyingKey(curve Curve) Serializable {
+	return plonk.NewVerifyingKey(curve.id())
+}
+
+func (plonkBackend) NewProof(curve Curve) Serializable {
+	return plonk.NewProof(curve.id())
+}
+
+// proofSystem returns the backend and curve recorded in the header of the
+// proof at proofPath, or for headers that record none, in the metadata next
+// to it. Proofs with neither are Groth16 over BN254.
+func proofSystem(proofPath string) (BackendName, Curve) {
+	header, _ := ReadProofHeader(proofPath)
+	return headerProofSystem(proofPath, header)
+}
+
+// headerProofSystem is proofSystem for the proof at proofPath with header,
+// which is nil for proof files without one.
+func headerProofSystem(proofPath string, header *ProofHeader) (BackendName, Curve) {
+	if header != nil && header.Backend != "" && header.Curve != "" {
+		return header.Backend, header.Curve
+	}
+	meta, err := ReadMetadata(proofPath)
+	if err != nil {
+		return BackendGroth16, CurveBN254
+	}
+	return BackendName(meta.Backend.String()), Curve(meta.Curve.String())
+}
+
+// loadSRS reads a canonical KZG SRS over curve from srsPath, truncated to
+// the size cs needs, and converts it to Lagrange form. An empty srsPath
+// samples a development SRS whose toxic waste this process saw, so proofs
+// built on it are only as trustworthy as the prover.
+func loadSRS(cs constraint.ConstraintSystem, curve Curve, srsPath string) (kzg.SRS, kzg.SRS, error) {
+	sizeCanonical, sizeLagrange := plonk.SRSSize(cs)
+
+	var srs kzg.SRS
+	if srsPath == "" {
+		fmt.Println("Warning: no SRS given; sampling a development SRS (not for production use)")
+		var err error
+		if srs, err = newSRS(curve.id(), uint64(sizeCanonical), rand.Reader); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		fmt.Println("Loading KZG SRS...")
+		f, err := os.Open(srsPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening SRS file: %w", err)
+		}
+		defer f.Close()
+
+		srs = kzg.NewSRS(curve.id())
+		if _, err := srs.ReadFrom(f); err != nil {
+			return nil, nil, fmt.Errorf("reading SRS: %w", err)
+		}
+	}
+
+	return truncateSRS(srs, sizeCanonical, sizeLagrange)
+}
+
+// truncateSRS cuts srs down to sizeCanonical points and derives the
+// Lagrange form of its first sizeLagrange points.
+func truncateSRS(srs kzg.SRS, sizeCanonical, sizeLagrange int) (kzg.SRS, kzg.SRS, error) {
+	tooSmall := func(n int) error {
+		return fmt.Errorf("SRS has %d points, circuit needs %d", n, sizeCanonical)
+	}
+
+	var err error
+	switch srs := srs.(type) {
+	case *kzg_bn254.SRS:
+		if len(srs.Pk.G1) < sizeCanonical {
+			return nil, nil, tooSmall(len(srs.Pk.G1))
+		}
+		srs.Pk.G1 = srs.Pk.G1[:sizeCanonical]
+		lagrange := &kzg_bn254.SRS{Vk: srs.Vk}
+		if lagrange.Pk.G1, err = kzg_bn254.ToLagrangeG1(slices.Clone(srs.Pk.G1[:sizeLagrange])); err != nil {
+			return nil, nil, fmt.Errorf("converting SRS to Lagrange form: %w", err)
+		}
+		return srs, lagrange, nil
+	case *kzg_bls12381.SRS:
+		if len(srs.Pk.G1) < sizeCanonical {
+			return nil, nil, tooSmall(len(srs.Pk.G1))
+		}
+		srs.Pk.G1 = srs.Pk.G1[:sizeCanonical]
+		lagrange := &kzg_bls12381.SRS{Vk: srs.Vk}
+		if lagrange.Pk.G1, err = kzg_bls12381.ToLagrangeG1(slices.Clone(srs.Pk.G1[:sizeLagrange])); err != nil {
+			return nil, nil, fmt.Errorf("converting SRS to Lagrange form: %w", err)
+		}
+		return srs, lagrange, nil
+	case *kzg_bls12377.SRS:
+		if len(srs.Pk.G1) < sizeCanonical {
+			return nil, nil, tooSmall(len(srs.Pk.G1))
+		}
+		srs.Pk.G1 = srs.Pk.G1[:sizeCanonical]
+		lagrange := &kzg_bls12377.SRS{Vk: srs.Vk}
+		if lagrange.Pk.G1, err = kzg_bls12377.ToLagrangeG1(slices.Clone(srs.Pk.G1[:sizeLagrange])); err != nil {
+			return nil, nil, fmt.Errorf("converting SRS to Lagrange form: %w", err)
+		}
+		return srs, lagrange, nil
+	case *kzg_bw6761.SRS:
+		if len(srs.Pk.G1) < sizeCanonical {
+			return nil, nil, tooSmall(len(srs.Pk.G1))
+		}
+		srs.Pk.G1 = srs.Pk.G1[:sizeCanonical]
+		lagrange := &kzg_bw6761.SRS{Vk: srs.Vk}
+		if lagrange.Pk.G1, err = kzg_bw6761.ToLagrangeG1(slices.Clone(srs.Pk.G1[:sizeLagrange])); err != nil {
+			return nil, nil, fmt.Errorf("converting SRS to Lagrange form: %w", err)
+		}
+		return srs, lagrange, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported SRS curve")
+	}
+}
+
+// newSRS samples a canonical KZG SRS over curve with size points from a
+// secret read from random.
+func newSRS(curve ecc.ID, size uint64, random io.Reader) (kzg.SRS, error) {
+	tau, err := rand.Int(random, curve.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("sampling SRS secret: %w", err)
+	}
+
+	var srs kzg.SRS
+	switch curve {
+	case ecc.BN254:
+		srs, err = kzg_bn254.NewSRS(size, tau)
+	case ecc.BLS12_381:
+		srs, err = kzg_bls12381.NewSRS(size, tau)
+	case ecc.BLS12_377:
+		srs, err = kzg_bls12377.NewSRS(size, tau)
+	case ecc.BW6_761:
+		srs, err = kzg_bw6761.NewSRS(size, tau)
+	default:
+		return nil, fmt.Errorf("unsupported SRS curve %s", curve)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating SRS: %w", err)
+	}
+	return srs, nil
+}
+
+// GenerateSRS writes a development KZG SRS over curve with size points to
+// path, for reuse across PLONK circuits. Its secret comes from a single
+// party, so production deployments should use the output of a multi-party
+// ceremony instead.
+func GenerateSRS(path string, curve Curve, size uint64) error {
+	return generateSRS(path, curve, size, rand.Reader)
+}
+
+// generateSRS writes an SRS as GenerateSRS does, its secret read from
+// random.
+func generateSRS(path string, curve Curve, size uint64, random io.Reader) error {
+	srs, err := newSRS(curve.id(), size, random)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating SRS file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := srs.WriteTo(f); err != nil {
+		return fmt.Errorf("writing SRS: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package proofs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// mockBackend compiles like Groth16 but only checks that the witness solves
+// the circuit; its proof is the public witness itself.
+type mockBackend struct {
+	groth16Backend
+}
+
+// mockValue is a mock key or proof: length-prefixed bytes.
+type mockValue struct {
+	data []byte
+}
+
+func (v *mockValue) WriteTo(w io.Writer) (int64, error) {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(v.data))); err != nil {
+		return 0, err
+	}
+	n, err := w.Write(v.data)
+	return int64(n) + 4, err
+}
+
+func (v *mockValue) ReadFrom(r io.Reader) (int64, error) {
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return 0, err
+	}
+	v.data = make([]byte, size)
+	n, err := io.ReadFull(r, v.data)
+	return int64(n) + 4, err
+}
+
+func (mockBackend) Setup(cs constraint.ConstraintSystem, curve Curve, srsPath string) (Serializable, Serializable, error) {
+	return &mockValue{}, &mockValue{}, nil
+}
+
+func (mockBackend) Prove(cs constraint.ConstraintSystem, curve Curve, accel Accelerator, pk Serializable, w witness.Witness) (Serializable, error) {
+	if err := cs.IsSolved(w); err != nil {
+		return nil, err
+	}
+	public, err := w.Public()
+	if err != nil {
+		return nil, err
+	}
+	data, err := public.MarshalBinary()
+	return &mockValue{data: data}, err
+}
+
+func (mockBackend) Verify(curve Curve, proof, vk Serializable, publicWitness witness.Witness) error {
+	data, err := publicWitness.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(proof.(*mockValue).data, data) {
+		return fmt.Errorf("proof is not for this public witness")
+	}
+	return nil
+}
+
+func (mockBackend) NewProvingKey(curve Curve) Serializable   { return &mockValue{} }
+func (mockBackend) NewVerifyingKey(curve Curve) Serializable { return &mockValue{} }
+func (mockBackend) NewProof(curve Curve) Serializable        { return &mockValue{} }
+
+func TestPlonkProof(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	srs := filepath.Join(dir, "bn254.srs")
+	if err := GenerateSRS(srs, CurveBN254, 1<<12); err != nil {
+		t.Fatalf("GenerateSRS: %v", err)
+	}
+
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	proof := &ZygosityProof{Settings: Settings{Backend: BackendPlonk, SRS: srs, Nonce: "challenge"}, Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+	if meta, err := ReadMetadata(out); err != nil || meta.Backend != BackendPlonk {
+		t.Errorf("metadata backend = %v, %v; want plonk", meta, err)
+	}
+
+	// A proof reuses the PLONK proving key like a Groth16 one.
+	again := filepath.Join(dir, "again.bin")
+	if err := proof.Generate(t.Context(), vcf, out+".pk", again); err != nil {
+		t.Fatalf("Generate with existing key: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", again); err != nil || !ok {
+		t.Errorf("Verify with existing key = %v, %v", ok, err)
+	}
+
+	// A Groth16 verifying key for the same circuit does not verify it.
+	groth16Out := filepath.Join(dir, "groth16.bin")
+	if err := (&ZygosityProof{Locus: "15:28365618"}).Generate(t.Context(), vcf, "", groth16Out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := (Settings{}).verifyProof(t.Context(), groth16Out+".vk", out); err == nil {
+		t.Errorf("PLONK proof should not verify against a Groth16 key")
+	}
+}
+
+func TestRegisteredBackend(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	RegisterBackend("mock", mockBackend{})
+	backend, err := ParseBackend("mock")
+	if err != nil {
+		t.Fatalf("ParseBackend: %v", err)
+	}
+
+	// Traits generate and verify through the registered backend unchanged.
+	out := filepath.Join(t.TempDir(), "zygosity_proof.bin")
+	proof := &ZygosityProof{Settings: Settings{Backend: backend}, Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+	if meta, err := ReadMetadata(out); err != nil || meta.Backend != backend {
+		t.Errorf("metadata backend = %v, %v; want mock", meta, err)
+	}
+
+	unknown := &ZygosityProof{Settings: Settings{Backend: "stark"}, Locus: "15:28365618"}
+	if err := unknown.Generate(t.Context(), vcf, "", filepath.Join(t.TempDir(), "stark.bin")); err == nil {
+		t.Errorf("Generate should reject an unregistered backend")
+	}
+}
+
+func TestParseBackend(t *testing.T) {
+	for s, want := range map[string]BackendName{"": BackendGroth16, "groth16": BackendGroth16, "PLONK": BackendPlonk} {
+		if got, err := ParseBackend(s); err != nil || got != want {
+			t.Errorf("ParseBackend(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseBackend("stark"); err == nil {
+		t.Errorf("ParseBackend should reject unknown backends")
+	}
+}
+
+func TestCurveProofs(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+
+	for _, settings := range []Settings{
+		{Curve: CurveBLS12381, Nonce: "challenge"},
+		{Curve: CurveBW6761, Backend: BackendPlonk},
+	} {
+		out := filepath.Join(dir, settings.Curve.String()+".bin")
+		proof := &ZygosityProof{Settings: settings, Locus: "15:28365618"}
+		if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+			t.Fatalf("%s: Generate: %v", settings.Curve, err)
+		}
+		if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+			t.Errorf("%s: Verify = %v, %v", settings.Curve, ok, err)
+		}
+		if meta, err := ReadMetadata(out); err != nil || meta.Curve != settings.Curve {
+			t.Errorf("%s: metadata curve = %v, %v", settings.Curve, meta, err)
+		}
+	}
+
+	// Proofs over natively computed hashes stay on BN254.
+	membership := &VariantMembershipProof{Settings: Settings{Curve: CurveBLS12381}, Variant: "15:28365618:A:G"}
+	if err := membership.Generate(t.Context(), vcf, "", filepath.Join(dir, "membership.bin")); err == nil {
+		t.Errorf("membership proof over BLS12-381 should be rejected")
+	}
+}
+
+func TestParseCurve(t *testing.T) {
+	for s, want := range map[string]Curve{"": CurveBN254, "BN254": CurveBN254, "bls12-381": CurveBLS12381, "bw6-761": CurveBW6761} {
+		if got, err := ParseCurve(s); err != nil || got != want {
+			t.Errorf("ParseCurve(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseCurve("secp256k1"); err == nil {
+		t.Errorf("ParseCurve should reject unknown curves")
+	}
+}
@@ -0,0 +1,196 @@
+package proofs
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+)
+
+// ProofFile is a proof to verify in a batch.
+type ProofFile struct {
+	Path string
+}
+
+// ProofFilesInDir returns the proofs in dir, identified by their metadata
+// files. A non-empty proofType keeps only proofs of that type.
+func ProofFilesInDir(dir string, proofType string) ([]ProofFile, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+MetadataSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+
+	var files []ProofFile
+	for _, match := range matches {
+		path := strings.TrimSuffix(match, MetadataSuffix)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if proofType != "" {
+			meta, err := ReadMetadata(path)
+			if err != nil || !strings.EqualFold(meta.ProofType, proofType) {
+				continue
+			}
+		}
+		files = append(files, ProofFile{Path: path})
+	}
+	return files, nil
+}
+
+// VerifyBatch verifies proofs of one circuit against its verifying key and
+// returns each proof's verification error, nil when it verifies. Groth16
+// proofs over BN254 are checked together with a random linear combination
+// of their pairing equations, which costs one final exponentiation and
+// three Miller loops plus one per proof instead of a pairing check per proof.
+// When the combined check fails, or the circuit uses commitments, the
+// proofs are verified one by one to tell which are invalid.
+func VerifyBatch(verifyingKeyPath string, files []ProofFile) ([]error, error) {
+	errs := make([]error, len(files))
+	if len(files) == 0 {
+		return errs, nil
+	}
+
+	name, curveName := proofSystem(files[0].Path)
+	backend, err := name.backend()
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+	vk, err := readVerifyingKey(verifyingKeyPath, backend, curveName)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+
+	verifyOne := func(i int) error {
+		if b, c := proofSystem(files[i].Path); b != name || c != curveName {
+			return withKind(ErrInvalidInput, fmt.Errorf("proof is %s over %s, batch is %s over %s", b, c, name, curveName))
+		}
+		proof, publicWitness, err := readProofFile(files[i].Path)
+		if err != nil {
+			return withKind(ErrInvalidInput, err)
+		}
+		return withKind(ErrVerification, backend.Verify(curveName, proof, vk, publicWitness))
+	}
+
+	bn254Key, ok := vk.(*groth16_bn254.VerifyingKey)
+	if name != BackendGroth16 || !ok || len(bn254Key.CommitmentKeys) > 0 {
+		for i := range files {
+			errs[i] = verifyOne(i)
+		}
+		return errs, nil
+	}
+
+	// Proofs that cannot join the combined check are verified on their own.
+	var batch []int
+	var proofs []*groth16_bn254.Proof
+	var witnesses []fr.Vector
+	for i, file := range files {
+		proof, w, err := readBN254Proof(file.Path, bn254Key)
+		if err != nil {
+			errs[i] = verifyOne(i)
+			continue
+		}
+		batch = append(batch, i)
+		proofs = append(proofs, proof)
+		witnesses = append(witnesses, w)
+	}
+
+	if err := batchPairingCheck(bn254Key, proofs, witnesses); err != nil {
+		for _, i := range batch {
+			errs[i] = verifyOne(i)
+		}
+	}
+	return errs, nil
+}
+
+// readBN254Proof reads a Groth16 proof over BN254 for vk and checks its
+// points lie in the prime-order subgroups.
+func readBN254Proof(proofPath string, vk *groth16_bn254.VerifyingKey) (*groth16_bn254.Proof, fr.Vector, error) {
+	if b, c := proofSystem(proofPath); b != BackendGroth16 || c != CurveBN254 {
+		return nil, nil, fmt.Errorf("proof is %s over %s", b, c)
+	}
+	p, publicWitness, err := readProofFile(proofPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof := p.(*groth16_bn254.Proof)
+	w, ok := publicWitness.Vector().(fr.Vector)
+	if !ok || len(w) != len(vk.G1.K)-1 {
+		return nil, nil, fmt.Errorf("public witness does not match the verifying key")
+	}
+	if !proof.Ar.IsInSubGroup() || !proof.Krs.IsInSubGroup() || !proof.Bs.IsInSubGroup() {
+		return nil, nil, fmt.Errorf("proof points are not in the correct subgroup")
+	}
+	return proof, w, nil
+}
+
+// batchPairingCheck checks Π e(rᵢAᵢ, Bᵢ) · e(-Σ rᵢLᵢ, γ) · e(-Σ rᵢCᵢ, δ) ·
+// e(-Σ rᵢα, β) = 1 for random rᵢ, where Lᵢ is the public input commitment
+// of proof i. It holds for all valid proofs and, except with negligible
+// probability, fails if any proof is invalid.
+func batchPairingCheck(vk *groth16_bn254.VerifyingKey, proofs []*groth16_bn254.Proof, witnesses []fr.Vector) error {
+	if len(proofs) == 0 {
+		return nil
+	}
+
+	var (
+		p          = make([]curve.G1Affine, 0, len(proofs)+3)
+		q          = make([]curve.G2Affine, 0, len(proofs)+3)
+		sumL, sumC curve.G1Jac
+		sumR       fr.Element
+	)
+	for i, proof := range proofs {
+		r, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+		if err != nil {
+			return fmt.Errorf("sampling batch coefficient: %w", err)
+		}
+		var rFr fr.Element
+		rFr.SetBigInt(r)
+		sumR.Add(&sumR, &rFr)
+
+		var rA curve.G1Affine
+		rA.ScalarMultiplication(&proof.Ar, r)
+		p = append(p, rA)
+		q = append(q, proof.Bs)
+
+		var l curve.G1Jac
+		if _, err := l.MultiExp(vk.G1.K[1:], witnesses[i], ecc.MultiExpConfig{}); err != nil {
+			return err
+		}
+		l.AddMixed(&vk.G1.K[0])
+		l.ScalarMultiplication(&l, r)
+		sumL.AddAssign(&l)
+
+		var c curve.G1Jac
+		c.FromAffine(&proof.Krs)
+		c.ScalarMultiplication(&c, r)
+		sumC.AddAssign(&c)
+	}
+
+	var negL, negC, negAlpha curve.G1Affine
+	negL.FromJacobian(&sumL)
+	negL.Neg(&negL)
+	negC.FromJacobian(&sumC)
+	negC.Neg(&negC)
+	negAlpha.ScalarMultiplication(&vk.G1.Alpha, sumR.BigInt(new(big.Int)))
+	negAlpha.Neg(&negAlpha)
+	p = append(p, negL, negC, negAlpha)
+	q = append(q, vk.G2.Gamma, vk.G2.Delta, vk.G2.Beta)
+
+	ok, err := curve.PairingCheck(p, q)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("batch pairing check failed")
+	}
+	return nil
+}
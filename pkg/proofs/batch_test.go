@@ -0,0 +1,94 @@
+package proofs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+15	28365619	.	C	T	60	PASS	.	GT	1/1
+`)
+	dir := t.TempDir()
+	keys := filepath.Join(dir, "keys")
+
+	// All proofs share one key pair, as a batch must.
+	if err := (&ZygosityProof{Locus: "15:28365618"}).Generate(t.Context(), vcf, "", keys); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	var files []ProofFile
+	for i, locus := range []string{"15:28365618", "15:28365619", "15:28365618"} {
+		out := filepath.Join(dir, "zygosity_"+string(rune('a'+i))+".bin")
+		if err := (&ZygosityProof{Locus: locus}).Generate(t.Context(), vcf, keys+".pk", out); err != nil {
+			t.Fatalf("Generate %s: %v", locus, err)
+		}
+		files = append(files, ProofFile{Path: out})
+	}
+
+	errs, err := VerifyBatch(keys+".vk", files)
+	if err != nil {
+		t.Fatalf("VerifyBatch: %v", err)
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("%s: %v", files[i].Path, err)
+		}
+	}
+
+	// The per-proof fallback would hide a broken combined check.
+	vk, err := readVerifyingKey(keys+".vk", groth16Backend{}, CurveBN254)
+	if err != nil {
+		t.Fatalf("readVerifyingKey: %v", err)
+	}
+	var proofs []*groth16_bn254.Proof
+	var witnesses []fr.Vector
+	for _, file := range files {
+		proof, w, err := readBN254Proof(file.Path, vk.(*groth16_bn254.VerifyingKey))
+		if err != nil {
+			t.Fatalf("readBN254Proof: %v", err)
+		}
+		proofs, witnesses = append(proofs, proof), append(witnesses, w)
+	}
+	if err := batchPairingCheck(vk.(*groth16_bn254.VerifyingKey), proofs, witnesses); err != nil {
+		t.Errorf("batchPairingCheck: %v", err)
+	}
+
+	// A tampered proof fails on its own without failing the others.
+	p, w, err := readProofFile(files[1].Path)
+	if err != nil {
+		t.Fatalf("readProofFile: %v", err)
+	}
+	w.Vector().(fr.Vector)[0].SetUint64(2)
+	if err := writeProofFile(files[1].Path, nil, p, w); err != nil {
+		t.Fatalf("writeProofFile: %v", err)
+	}
+	errs, err = VerifyBatch(keys+".vk", files)
+	if err != nil {
+		t.Fatalf("VerifyBatch: %v", err)
+	}
+	if errs[0] != nil || errs[1] == nil || errs[2] != nil {
+		t.Errorf("VerifyBatch errors = %v; want only the second proof rejected", errs)
+	}
+
+	found, err := ProofFilesInDir(dir, "zygosity")
+	if err != nil {
+		t.Fatalf("ProofFilesInDir: %v", err)
+	}
+	// keys is itself a proof with metadata.
+	if len(found) != 4 {
+		t.Errorf("ProofFilesInDir found %d proofs, want 4", len(found))
+	}
+	if found, _ := ProofFilesInDir(dir, "eyecolor"); len(found) != 0 {
+		t.Errorf("ProofFilesInDir should filter by proof type, found %v", found)
+	}
+	if _, err := os.Stat(keys + MetadataSuffix); err != nil {
+		t.Fatalf("missing metadata: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+// BenchResult is what Benchmark measured of one proof's circuit.
+type BenchResult struct {
+	Type        string
+	Backend     BackendName
+	Curve       Curve
+	Constraints int
+	// ProvingKeySize, VerifyingKeySize and ProofSize are serialized sizes
+	// in bytes; ProofSize includes the public witness.
+	ProvingKeySize   int64
+	VerifyingKeySize int64
+	ProofSize        int64
+	CompileTime      time.Duration
+	SetupTime        time.Duration
+	// ProveTime and VerifyTime are zero when no VCF was proved.
+	ProveTime  time.Duration
+	VerifyTime time.Duration
+	// PeakHeap is the most Go heap in use while benchmarking, sampled, in
+	// bytes.
+	PeakHeap uint64
+	// ProveError is why the VCF could not be proved or verified.
+	ProveError string
+}
+
+// Benchmark compiles and sets up the circuit p proves, as SetupKeys does,
+// timing each step uncached, and writes the keys to dir, replacing any from
+// an earlier benchmark. With vcfPath it then proves the VCF with those keys
+// and verifies the proof; a VCF p cannot be proved over is reported in
+// ProveError rather than failing the benchmark.
+func Benchmark(ctx context.Context, p Proof, vcfPath string, dir string) (*BenchResult, error) {
+	proofType, circuit, err := proofCircuit(p)
+	if err != nil {
+		return nil, err
+	}
+	s := proofSettings(p)
+	if err := s.checkCurve(proofType); err != nil {
+		return nil, err
+	}
+	if s.enveloped() {
+		circuit = newEnvelopeCircuit(circuit, proofType, *s)
+	}
+	backend, err := s.Backend.backend()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BenchResult{Type: proofType, Backend: s.Backend, Curve: s.Curve}
+	if result.Backend == "" {
+		result.Backend = BackendGroth16
+	}
+	if result.Curve == "" {
+		result.Curve = CurveBN254
+	}
+	stop := sampleHeap(&result.PeakHeap)
+	defer stop()
+
+	start := time.Now()
+	cs, err := backend.Compile(s.Curve, circuit)
+	if err != nil {
+		return nil, withKind(ErrProving, fmt.Errorf("compiling %s circuit: %w", proofType, err))
+	}
+	result.CompileTime = time.Since(start)
+	result.Constraints = cs.GetNbConstraints()
+
+	start = time.Now()
+	pk, vk, err := backend.Setup(cs, s.Curve, s.SRS)
+	if err != nil {
+		return nil, withKind(ErrProving, fmt.Errorf("setup error: %w", err))
+	}
+	result.SetupTime = time.Since(start)
+
+	keyPath := filepath.Join(dir, proofType)
+	if err := writeKeyPair(keyPath, pk, vk); err != nil {
+		return nil, err
+	}
+	if result.ProvingKeySize, err = fileSize(keyPath + ".pk"); err != nil {
+		return nil, err
+	}
+	if result.VerifyingKeySize, err = fileSize(keyPath + ".vk"); err != nil {
+		return nil, err
+	}
+	if vcfPath == "" {
+		return result, nil
+	}
+
+	proofPath := keyPath + "_proof.bin"
+	defer func(force bool) { s.proveTime, s.Force = nil, force }(s.Force)
+	s.proveTime, s.Force = &result.ProveTime, true
+	if err := p.Generate(ctx, vcfPath, keyPath+".pk", proofPath); err != nil {
+		result.ProveError = err.Error()
+		return result, nil
+	}
+	if result.ProofSize, err = fileSize(proofPath); err != nil {
+		return nil, err
+	}
+
+	start = time.Now()
+	ok, err := p.Verify(ctx, keyPath+".vk", proofPath)
+	result.VerifyTime = time.Since(start)
+	switch {
+	case err != nil:
+		result.ProveError = err.Error()
+	case !ok:
+		result.ProveError = "proof did not verify"
+	}
+	return result, nil
+}
+
+// sampleHeap records the most heap in use in *peak until the returned
+// function is called.
+func sampleHeap(peak *uint64) func() {
+	runtime.GC()
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	read := func() {
+		metrics.Read(sample)
+		if v := sample[0].Value.Uint64(); v > *peak {
+			*peak = v
+		}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			read()
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+		read()
+	}
+}
+
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
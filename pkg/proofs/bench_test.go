@@ -0,0 +1,43 @@
+package proofs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBenchmark(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	proof := &ZygosityProof{Locus: "15:28365618"}
+	result, err := Benchmark(t.Context(), proof, vcf, t.TempDir())
+	if err != nil {
+		t.Fatalf("Benchmark: %v", err)
+	}
+	if result.Type != "zygosity" || result.Backend != BackendGroth16 || result.Curve != CurveBN254 {
+		t.Errorf("result = %s %s %s", result.Type, result.Backend, result.Curve)
+	}
+	if result.Constraints == 0 || result.ProvingKeySize == 0 || result.VerifyingKeySize == 0 || result.ProofSize == 0 || result.PeakHeap == 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if result.ProveTime == 0 || result.VerifyTime == 0 || result.ProveError != "" {
+		t.Errorf("proving not timed: %+v", result)
+	}
+	if proof.proveTime != nil {
+		t.Errorf("Benchmark left its proving timer on the proof")
+	}
+
+	// Without a VCF only the circuit is measured; a VCF the proof does not
+	// hold is reported rather than failing.
+	if result, err := Benchmark(t.Context(), &ZygosityProof{Locus: "15:28365618"}, "", t.TempDir()); err != nil || result.ProveTime != 0 {
+		t.Errorf("Benchmark without a VCF = %+v, %v", result, err)
+	}
+	if result, err := Benchmark(t.Context(), &ChromosomeProof{Target: 7}, vcf, t.TempDir()); err != nil || result.ProveError == "" {
+		t.Errorf("Benchmark of an unprovable VCF = %+v, %v", result, err)
+	}
+	if _, err := Benchmark(t.Context(), &CohortProof{}, "", t.TempDir()); !errors.Is(err, ErrNoCircuit) {
+		t.Errorf("Benchmark of a cohort proof = %v, want ErrNoCircuit", err)
+	}
+}
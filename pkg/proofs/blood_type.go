@@ -0,0 +1,82 @@
+package proofs
+
+import "context"
+
+// ABO blood groups. Zero is reserved for genotypes with no defined group.
+const (
+	BloodGroupO = iota + 1
+	BloodGroupA
+	BloodGroupB
+	BloodGroupAB
+)
+
+// bloodTypeCode packs an ABO group and RhD status into one phenotype value.
+func bloodTypeCode(group int, rhPositive bool) int {
+	return 2*group + boolToInt(rhPositive)
+}
+
+// BloodTypeName returns the display name of a blood type, e.g. "AB+".
+func BloodTypeName(code int) string {
+	groups := map[int]string{BloodGroupO: "O", BloodGroupA: "A", BloodGroupB: "B", BloodGroupAB: "AB"}
+	group, ok := groups[code/2]
+	if !ok {
+		return "unknown"
+	}
+	if code%2 == 1 {
+		return group + "+"
+	}
+	return group + "-"
+}
+
+// BloodTypeTrait predicts ABO group and RhD status (GRCh37).
+//
+// rs8176719 is the c.261delG frameshift of the O allele; the reference
+// carries the deletion, so its ALT counts functional A or B alleles.
+// rs8176746 (c.796C>A) distinguishes B from A among those. The RhD negative
+// phenotype is a deletion of RHD that SNV calls cannot see directly, so it is
+// tagged by rs590787, which is in strong linkage with it in Europeans.
+var BloodTypeTrait = CompositeTrait{
+	Name: "blood type",
+	SNPs: []VariantSpec{
+		{Chromosome: "9", Position: 136132908, Ref: "T", Alt: "TC", RSID: "rs8176719"},
+		{Chromosome: "9", Position: 136131322, Ref: "G", Alt: "T", RSID: "rs8176746"},
+		{Chromosome: "1", Position: 25648478, Ref: "C", Alt: "T", RSID: "rs590787"},
+	},
+	Table: bloodTypeTable(),
+}
+
+// bloodTypeTable derives the decision table from unphased dosages of the
+// functional (A or B), B and RhD-negative tagging alleles.
+func bloodTypeTable() []int {
+	table := make([]int, compositeTableSize(3))
+	for functional := 0; functional <= 2; functional++ {
+		for b := 0; b <= 2; b++ {
+			if b > functional {
+				continue // B alleles are a subset of the functional alleles
+			}
+
+			group := BloodGroupO
+			switch a := functional - b; {
+			case a > 0 && b > 0:
+				group = BloodGroupAB
+			case a > 0:
+				group = BloodGroupA
+			case b > 0:
+				group = BloodGroupB
+			}
+
+			for rhNegative := 0; rhNegative <= 2; rhNegative++ {
+				table[functional+3*b+9*rhNegative] = bloodTypeCode(group, rhNegative < 2)
+			}
+		}
+	}
+	return table
+}
+
+func (p *BloodTypeProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	return generateTrait(ctx, bloodTypeDefinition, p.Settings, vcfPath, provingKeyPath, outputPath)
+}
+
+func (p *BloodTypeProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	return p.verifyTrait(ctx, bloodTypeDefinition, verifyingKeyPath, proofPath)
+}
@@ -0,0 +1,83 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestBloodTypeTrait(t *testing.T) {
+	if err := BloodTypeTrait.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	tests := []struct {
+		functional, b, rhNegative int
+		want                      string
+	}{
+		{0, 0, 0, "O+"},
+		{0, 0, 2, "O-"},
+		{1, 0, 1, "A+"},
+		{2, 0, 2, "A-"},
+		{1, 1, 0, "B+"},
+		{2, 2, 0, "B+"},
+		{2, 1, 0, "AB+"},
+		{2, 1, 2, "AB-"},
+	}
+	field := ecc.BN254.ScalarField()
+	for _, tt := range tests {
+		dosages := []int{tt.functional, tt.b, tt.rhNegative}
+		got, err := BloodTypeTrait.Phenotype(dosages)
+		if err != nil {
+			t.Fatalf("Phenotype(%v): %v", dosages, err)
+		}
+		if BloodTypeName(got) != tt.want {
+			t.Errorf("Phenotype(%v) = %s, want %s", dosages, BloodTypeName(got), tt.want)
+		}
+
+		assignment := compositeAssignment(BloodTypeTrait, got, dosages...)
+		if err := test.IsSolved(NewCompositeTraitCircuit(3), assignment, field); err != nil {
+			t.Errorf("%v should prove %s: %v", dosages, tt.want, err)
+		}
+		assignment = compositeAssignment(BloodTypeTrait, bloodTypeCode(BloodGroupO, true), dosages...)
+		if tt.want != "O+" {
+			if err := test.IsSolved(NewCompositeTraitCircuit(3), assignment, field); err == nil {
+				t.Errorf("%v should not prove O+", dosages)
+			}
+		}
+	}
+
+	// More B alleles than functional alleles is not a valid genotype.
+	if _, err := BloodTypeTrait.Phenotype([]int{0, 1, 0}); err == nil {
+		t.Errorf("inconsistent ABO dosages should have no blood type")
+	}
+}
+
+func TestBloodTypeProof(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+1	25648478	rs590787	C	T	60	PASS	.	GT	1/1
+9	136131322	rs8176746	G	T	60	PASS	.	GT	0/1
+9	136132908	rs8176719	T	TC	60	PASS	.	GT	1/1
+`)
+	out := filepath.Join(t.TempDir(), "bloodtype_proof.bin")
+
+	proof := &BloodTypeProof{}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if verified, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !verified {
+		t.Fatalf("Verify = %v, %v", verified, err)
+	}
+
+	bloodType, err := Settings{}.verifyCompositeTrait(t.Context(), BloodTypeTrait, out+".vk", out)
+	if err != nil {
+		t.Fatalf("verifyCompositeTrait: %v", err)
+	}
+	if got := BloodTypeName(bloodType); got != "AB-" {
+		t.Errorf("proven blood type = %s, want AB-", got)
+	}
+}
@@ -0,0 +1,228 @@
+package proofs
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/brentp/vcfgo"
+)
+
+// GenomeBuild names a human reference assembly.
+type GenomeBuild string
+
+const (
+	GRCh37 GenomeBuild = "GRCh37"
+	GRCh38 GenomeBuild = "GRCh38"
+)
+
+// PanelBuild is the build of the positions in panels_traits.json and in
+// the built-in trait, IrisPlex and pharmacogene panels.
+const PanelBuild = GRCh37
+
+// buildContigLengths are chromosome lengths that differ between builds.
+var buildContigLengths = map[GenomeBuild]map[string]uint64{
+	GRCh37: {"1": 249250621, "2": 243199373, "22": 51304566, "X": 155270560},
+	GRCh38: {"1": 248956422, "2": 242193529, "22": 50818468, "X": 156040895},
+}
+
+// buildAliases are the names assemblies go by in ##reference and contig
+// assembly fields, lower-cased.
+var buildAliases = []struct {
+	alias string
+	build GenomeBuild
+}{
+	{"grch38", GRCh38}, {"hg38", GRCh38}, {"hs38", GRCh38}, {"b38", GRCh38},
+	{"grch37", GRCh37}, {"hg19", GRCh37}, {"hs37", GRCh37}, {"b37", GRCh37}, {"g1k_v37", GRCh37},
+}
+
+// DetectBuild infers the VCF's genome build from its header: from contig
+// lengths when they are declared, otherwise from the assembly named in
+// contig lines or the ##reference line. It returns "" when the header does
+// not say.
+func DetectBuild(header *vcfgo.Header) GenomeBuild {
+	for _, contig := range header.Contigs {
+		length, err := strconv.ParseUint(contig["length"], 10, 64)
+		if err != nil {
+			continue
+		}
+		name := normalizeChromosome(contig["ID"])
+		for build, lengths := range buildContigLengths {
+			if lengths[name] == length {
+				return build
+			}
+		}
+	}
+
+	var names []string
+	for _, contig := range header.Contigs {
+		names = append(names, contig["assembly"])
+	}
+	for _, line := range header.Extras {
+		if value, ok := strings.CutPrefix(line, "##reference="); ok {
+			names = append(names, value)
+		}
+	}
+	for _, name := range names {
+		name = strings.ToLower(name)
+		for _, a := range buildAliases {
+			if strings.Contains(name, a.alias) {
+				return a.build
+			}
+		}
+	}
+	return ""
+}
+
+// chainBlock is an ungapped block of a liftover chain: size bases from
+// start on a source chromosome align to target from targetStart. All
+// coordinates are 0-based; a reverse block's targetStart counts from the
+// end of the target chromosome, as chain files give it.
+type chainBlock struct {
+	start, size uint64
+	target      string
+	targetStart uint64
+	targetSize  uint64
+	reverse     bool
+}
+
+// Liftover maps positions between genome builds using a UCSC chain file,
+// such as hg19ToHg38.over.chain.gz.
+type Liftover struct {
+	blocks map[string][]chainBlock
+}
+
+// LoadLiftover reads the chain file at path, which may be gzip-compressed.
+// The chain's source build must be PanelBuild, so the chain lifts panel
+// positions onto the VCF's build.
+func LoadLiftover(path string) (*Liftover, error) {
+	f, err := openDecompressed(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening chain file: %w", err)
+	}
+	defer f.Close()
+
+	l := &Liftover{blocks: map[string][]chainBlock{}}
+	var (
+		source     string
+		block      chainBlock
+		inChain    bool
+		tPos, qPos uint64
+		lineNum    int
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 0 || strings.HasPrefix(fields[0], "#"):
+			continue
+		case fields[0] == "chain":
+			// chain score tName tSize tStrand tStart tEnd qName qSize qStrand qStart qEnd id
+			if len(fields) < 12 {
+				return nil, fmt.Errorf("chain file line %d: invalid chain header", lineNum)
+			}
+			values, err := parseUints(fields[5], fields[8], fields[10])
+			if err != nil {
+				return nil, fmt.Errorf("chain file line %d: %w", lineNum, err)
+			}
+			source = normalizeChromosome(fields[2])
+			block = chainBlock{target: normalizeChromosome(fields[7]), targetSize: values[1], reverse: fields[9] == "-"}
+			tPos, qPos, inChain = values[0], values[2], true
+		case inChain && (len(fields) == 1 || len(fields) == 3):
+			values, err := parseUints(fields...)
+			if err != nil {
+				return nil, fmt.Errorf("chain file line %d: %w", lineNum, err)
+			}
+			block.start, block.size, block.targetStart = tPos, values[0], qPos
+			l.blocks[source] = append(l.blocks[source], block)
+			if len(fields) == 1 {
+				inChain = false
+				continue
+			}
+			tPos += values[0] + values[1]
+			qPos += values[0] + values[2]
+		default:
+			return nil, fmt.Errorf("chain file line %d: unexpected %q", lineNum, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading chain file: %w", err)
+	}
+
+	for _, blocks := range l.blocks {
+		slices.SortFunc(blocks, func(a, b chainBlock) int { return cmp.Compare(a.start, b.start) })
+	}
+	return l, nil
+}
+
+func parseUints(values ...string) ([]uint64, error) {
+	parsed := make([]uint64, len(values))
+	for i, v := range values {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", v)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
+
+// Lift returns the position of l on the target build, and whether the
+// target is on the opposite strand, so alleles there are complemented. It
+// returns false for positions the chain does not map.
+func (lo *Liftover) Lift(l Locus) (Locus, bool, bool) {
+	blocks := lo.blocks[normalizeChromosome(l.Chromosome)]
+	if l.Position == 0 {
+		return Locus{}, false, false
+	}
+	pos := l.Position - 1
+
+	i, _ := slices.BinarySearchFunc(blocks, pos, func(b chainBlock, p uint64) int {
+		if b.start > p {
+			return 1
+		}
+		return -1
+	})
+	if i == 0 || pos >= blocks[i-1].start+blocks[i-1].size {
+		return Locus{}, false, false
+	}
+	b := blocks[i-1]
+	target := b.targetStart + pos - b.start
+	if b.reverse {
+		target = b.targetSize - 1 - target
+	}
+	return Locus{b.target, target + 1}, b.reverse, true
+}
+
+// complementAlleles complements the bases of alleles, for calls lifted
+// across a strand flip.
+func complementAlleles(alleles ...string) []string {
+	complemented := make([]string, len(alleles))
+	for i, a := range alleles {
+		if strings.Trim(strings.ToUpper(a), "ACGTN") != "" {
+			// Symbolic and missing alleles have no strand.
+			complemented[i] = a
+			continue
+		}
+		b := []byte(strings.ToUpper(a))
+		for j, c := range b {
+			switch c {
+			case 'A':
+				b[j] = 'T'
+			case 'T':
+				b[j] = 'A'
+			case 'C':
+				b[j] = 'G'
+			case 'G':
+				b[j] = 'C'
+			}
+		}
+		slices.Reverse(b)
+		complemented[i] = string(b)
+	}
+	return complemented
+}
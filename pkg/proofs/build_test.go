@@ -0,0 +1,130 @@
+package proofs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/brentp/vcfgo"
+)
+
+func TestNormalizeChromosome(t *testing.T) {
+	for in, want := range map[string]string{
+		"chr22": "22", "22": "22", "CHRX": "X", "chrM": "MT", "M": "MT", "MT": "MT", "chrMT": "MT", "chr": "chr",
+	} {
+		if got := normalizeChromosome(in); got != want {
+			t.Errorf("normalizeChromosome(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDetectBuild(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		header *vcfgo.Header
+		want   GenomeBuild
+	}{
+		{"contig length", &vcfgo.Header{Contigs: []map[string]string{{"ID": "chr1", "length": "248956422"}}}, GRCh38},
+		{"contig length without prefix", &vcfgo.Header{Contigs: []map[string]string{{"ID": "22", "length": "51304566"}}}, GRCh37},
+		{"contig assembly", &vcfgo.Header{Contigs: []map[string]string{{"ID": "1", "assembly": "b37"}}}, GRCh37},
+		{"reference line", &vcfgo.Header{Extras: []string{"##reference=file:///refs/Homo_sapiens_assembly38.hg38.fasta"}}, GRCh38},
+		{"length wins", &vcfgo.Header{
+			Contigs: []map[string]string{{"ID": "chr1", "length": "249250621", "assembly": "hg38"}},
+		}, GRCh37},
+		{"unknown", &vcfgo.Header{Contigs: []map[string]string{{"ID": "chr1", "length": "1000"}}}, ""},
+	} {
+		if got := DetectBuild(tt.header); got != tt.want {
+			t.Errorf("%s: DetectBuild = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// testChain lifts chr1 across a 10-base deletion and chr22 onto the
+// reverse strand of a 600-base target.
+const testChain = `chain 1000 chr1 1000 + 0 310 chr1 1100 + 0 300 1
+100	10	0
+200
+
+chain 1000 chr22 500 + 0 500 chr22 600 - 0 500 2
+500
+`
+
+func writeTestChain(t *testing.T) *Liftover {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.over.chain")
+	if err := os.WriteFile(path, []byte(testChain), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lo, err := LoadLiftover(path)
+	if err != nil {
+		t.Fatalf("LoadLiftover: %v", err)
+	}
+	return lo
+}
+
+func TestLiftover(t *testing.T) {
+	lo := writeTestChain(t)
+	for _, tt := range []struct {
+		from    Locus
+		want    Locus
+		reverse bool
+		ok      bool
+	}{
+		{Locus{"1", 50}, Locus{"1", 50}, false, true},
+		{Locus{"chr1", 100}, Locus{"1", 100}, false, true},
+		{Locus{"1", 105}, Locus{}, false, false},
+		{Locus{"1", 111}, Locus{"1", 101}, false, true},
+		{Locus{"1", 310}, Locus{"1", 300}, false, true},
+		{Locus{"1", 311}, Locus{}, false, false},
+		{Locus{"22", 1}, Locus{"22", 600}, true, true},
+		{Locus{"22", 500}, Locus{"22", 101}, true, true},
+		{Locus{"2", 1}, Locus{}, false, false},
+	} {
+		got, reverse, ok := lo.Lift(tt.from)
+		if got != tt.want || reverse != tt.reverse || ok != tt.ok {
+			t.Errorf("Lift(%s) = %s, %v, %v; want %s, %v, %v", tt.from, got, reverse, ok, tt.want, tt.reverse, tt.ok)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "bad.chain")
+	if err := os.WriteFile(path, []byte("chain 1000 chr1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLiftover(path); err == nil {
+		t.Errorf("LoadLiftover should reject a truncated chain header")
+	}
+}
+
+func TestExtractGenotypesLiftover(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##contig=<ID=chr1,length=248956422>
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+chr1	101	.	A	G	60	PASS	.	GT	0/1
+chr22	101	.	A	C	60	PASS	.	GT	1/1
+chrM	73	.	A	G	60	PASS	.	GT	1
+`)
+	loci := []Locus{{"1", 111}, {"22", 500}, {"1", 105}}
+	got, err := extractGenotypes(vcf, Settings{Liftover: writeTestChain(t)}, locusSpecs(loci...))
+	if err != nil {
+		t.Fatalf("extractGenotypes: %v", err)
+	}
+	want := map[Locus]Genotype{
+		{"1", 111}:  {Ref: "A", Alt: []string{"G"}, Alleles: []int{0, 1}, Quality: 60},
+		{"22", 500}: {Ref: "T", Alt: []string{"G"}, Alleles: []int{1, 1}, Quality: 60},
+	}
+	for locus, g := range got {
+		g.Depth = 0
+		got[locus] = g
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lifted genotypes = %+v, want %+v", got, want)
+	}
+
+	// Without a liftover the VCF's chrM matches the panel's MT.
+	got, err = extractGenotypes(vcf, Settings{}, locusSpecs(Locus{"MT", 73}))
+	if err != nil || len(got) != 1 {
+		t.Errorf("chrM genotype = %v, %v; want one call", got, err)
+	}
+}
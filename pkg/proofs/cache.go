@@ -0,0 +1,278 @@
+package proofs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// CCSSuffix is the extension of cached compiled constraint systems.
+const CCSSuffix = ".ccs"
+
+var (
+	buildDigestOnce sync.Once
+	buildDigest     []byte
+	buildDigestErr  error
+)
+
+// circuitVersion identifies the circuit code of this binary: the SHA-256 of
+// its executable, so a rebuilt binary never loads a constraint system
+// compiled from older circuits.
+func circuitVersion() ([]byte, error) {
+	buildDigestOnce.Do(func() {
+		path, err := os.Executable()
+		if err != nil {
+			buildDigestErr = fmt.Errorf("locating executable: %w", err)
+			return
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			buildDigestErr = fmt.Errorf("opening executable: %w", err)
+			return
+		}
+		defer f.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			buildDigestErr = fmt.Errorf("hashing executable: %w", err)
+			return
+		}
+		buildDigest = h.Sum(nil)
+	})
+	return buildDigest, buildDigestErr
+}
+
+// ccsCachePath returns the file in dir a constraint system for circuit is
+// cached in. Its name digests the circuit version, backend, curve and the
+// shape of circuit: its types, slice lengths and configuration fields,
+// which with the code fix the constraints.
+func (s Settings) ccsCachePath(dir string, circuit frontend.Circuit) (string, error) {
+	version, err := circuitVersion()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write(version)
+	fmt.Fprintf(h, "%s/%s/", s.Backend, s.Curve)
+	writeShape(h, reflect.ValueOf(circuit))
+	return filepath.Join(dir, hex.EncodeToString(h.Sum(nil)[:16])+CCSSuffix), nil
+}
+
+// writeShape writes v to w deterministically, following pointers and
+// interfaces rather than printing their addresses.
+func writeShape(w io.Writer, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		io.WriteString(w, "nil")
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprintf(w, "%s(nil)", v.Type())
+			return
+		}
+		fmt.Fprintf(w, "%s(", v.Type())
+		writeShape(w, v.Elem())
+		io.WriteString(w, ")")
+	case reflect.Struct:
+		fmt.Fprintf(w, "%s{", v.Type())
+		for i := range v.NumField() {
+			fmt.Fprintf(w, "%s:", v.Type().Field(i).Name)
+			writeShape(w, v.Field(i))
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, "}")
+	case reflect.Slice, reflect.Array:
+		fmt.Fprintf(w, "%s[%d]{", v.Type(), v.Len())
+		for i := range v.Len() {
+			writeShape(w, v.Index(i))
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, "}")
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		fmt.Fprintf(w, "%s", v.Type())
+	default:
+		fmt.Fprintf(w, "%s(%v)", v.Type(), v)
+	}
+}
+
+// compileCircuit compiles circuit with backend over the settings' curve.
+// With a cache directory it loads a constraint system compiled earlier for
+// the same circuit instead, and caches the ones it compiles; cache errors
+// fall back to compiling.
+func (s Settings) compileCircuit(backend Backend, circuit frontend.Circuit) (constraint.ConstraintSystem, error) {
+	var cachePath string
+	if s.CCSCacheDir != "" {
+		var err error
+		if cachePath, err = s.ccsCachePath(s.CCSCacheDir, circuit); err != nil {
+			fmt.Printf("Warning: not caching the compiled circuit: %v\n", err)
+		} else if cs, err := readCCS(backend, s.Curve, cachePath); err == nil {
+			fmt.Printf("Loaded compiled circuit from %s\n", cachePath)
+			return cs, nil
+		} else if !os.IsNotExist(err) {
+			fmt.Printf("Warning: ignoring cached circuit %s: %v\n", cachePath, err)
+		}
+	}
+
+	fmt.Println("Compiling circuit...")
+	cs, err := backend.Compile(s.Curve, circuit)
+	if err != nil {
+		return nil, fmt.Errorf("circuit compilation error: %w", err)
+	}
+
+	if cachePath != "" {
+		if err := writeCacheFile(cachePath, cs); err != nil {
+			fmt.Printf("Warning: not caching the compiled circuit: %v\n", err)
+		}
+	}
+	return cs, nil
+}
+
+func readCCS(backend Backend, curve Curve, path string) (constraint.ConstraintSystem, error) {
+	cs := backend.NewConstraintSystem(curve)
+	if err := readCacheFile(path, cs); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// writeCacheFile writes v to path through a temporary file, so concurrent
+// generations never read a partial cache entry.
+func writeCacheFile(path string, v io.WriterTo) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	if err := writeFileAtomic(path, 0600, writeTo(v)); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// circuitHash is the SHA-256 of the serialized constraint system cs, which
+// changes with any change to the circuit's definition.
+func circuitHash(cs constraint.ConstraintSystem) ([]byte, error) {
+	h := sha256.New()
+	if _, err := cs.WriteTo(h); err != nil {
+		return nil, fmt.Errorf("hashing constraint system: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// keyCachePath returns the directory in dir that keys for cs are cached
+// in, as key.pk and key.vk. It digests the backend, curve and
+// circuit hash, and for a PLONK setup from an SRS file the SRS's contents,
+// so a changed circuit or SRS never reuses stale keys.
+func (s Settings) keyCachePath(dir string, cs constraint.ConstraintSystem) (string, error) {
+	circuit, err := circuitHash(cs)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s/%s/", s.Backend, s.Curve)
+	h.Write(circuit)
+	if s.Backend == BackendPlonk && s.SRS != "" {
+		f, err := os.Open(s.SRS)
+		if err != nil {
+			return "", fmt.Errorf("opening SRS file: %w", err)
+		}
+		defer f.Close()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", fmt.Errorf("hashing SRS: %w", err)
+		}
+	}
+	return filepath.Join(dir, hex.EncodeToString(h.Sum(nil)[:16])), nil
+}
+
+// setupKeys runs a setup for cs with backend. With a key cache directory it
+// returns the keys of an earlier setup for the same circuit instead, and
+// caches the keys of new setups; cache errors fall back to a fresh setup.
+func (s Settings) setupKeys(backend Backend, cs constraint.ConstraintSystem) (Serializable, Serializable, error) {
+	var cachePath string
+	if s.KeyCacheDir != "" {
+		var err error
+		if cachePath, err = s.keyCachePath(s.KeyCacheDir, cs); err != nil {
+			fmt.Printf("Warning: not caching keys: %v\n", err)
+		} else if pk, vk, err := readCachedKeys(backend, s.Curve, cachePath); err == nil {
+			fmt.Printf("Loaded keys for this circuit from %s\n", cachePath)
+			return pk, vk, nil
+		} else if !os.IsNotExist(err) {
+			fmt.Printf("Warning: replacing unreadable cached keys %s: %v\n", cachePath, err)
+			os.RemoveAll(cachePath)
+		}
+	}
+
+	fmt.Println("Setting up new proving system...")
+	pk, vk, err := backend.Setup(cs, s.Curve, s.SRS)
+	if err != nil {
+		return nil, nil, fmt.Errorf("setup error: %w", err)
+	}
+
+	if cachePath != "" {
+		if err := writeCachedKeys(cachePath, pk, vk); err != nil {
+			fmt.Printf("Warning: not caching keys: %v\n", err)
+		}
+	}
+	return pk, vk, nil
+}
+
+func readCachedKeys(backend Backend, curve Curve, cachePath string) (Serializable, Serializable, error) {
+	pk := backend.NewProvingKey(curve)
+	if err := readCacheFile(filepath.Join(cachePath, "key.pk"), pk); err != nil {
+		return nil, nil, err
+	}
+	vk := backend.NewVerifyingKey(curve)
+	if err := readCacheFile(filepath.Join(cachePath, "key.vk"), vk); err != nil {
+		return nil, nil, err
+	}
+	return pk, vk, nil
+}
+
+// writeCachedKeys writes a key pair to a temporary directory and renames it
+// to cachePath, so a concurrent setup of the same circuit can never leave a
+// proving key next to another setup's verifying key. When another setup
+// got there first its keys are kept.
+func writeCachedKeys(cachePath string, pk, vk Serializable) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(cachePath), ".keys-*")
+	if err != nil {
+		return fmt.Errorf("creating cache entry: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := writeCacheFile(filepath.Join(tmp, "key.pk"), pk); err != nil {
+		return err
+	}
+	if err := writeCacheFile(filepath.Join(tmp, "key.vk"), vk); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, cachePath); err != nil {
+		if _, statErr := os.Stat(cachePath); statErr == nil {
+			return nil
+		}
+		return fmt.Errorf("storing cache entry: %w", err)
+	}
+	return nil
+}
+
+func readCacheFile(path string, v io.ReaderFrom) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := v.ReadFrom(f); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return nil
+}
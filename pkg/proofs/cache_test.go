@@ -0,0 +1,124 @@
+package proofs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCCSCache(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	cache := filepath.Join(dir, "cache")
+	proof := &ZygosityProof{Settings: Settings{CCSCacheDir: cache, Nonce: "challenge"}, Locus: "15:28365618"}
+
+	first := filepath.Join(dir, "first.bin")
+	if err := proof.Generate(t.Context(), vcf, "", first); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	entries, err := filepath.Glob(filepath.Join(cache, "*"+CCSSuffix))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("cache holds %v, %v; want one constraint system", entries, err)
+	}
+
+	// The cached constraint system proves against the key set up from the
+	// compiled one.
+	second := filepath.Join(dir, "second.bin")
+	if err := proof.Generate(t.Context(), vcf, first+".pk", second); err != nil {
+		t.Fatalf("Generate from cache: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), first+".vk", second); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	// A corrupt entry is recompiled and replaced.
+	if err := os.WriteFile(entries[0], []byte("corrupt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.Generate(t.Context(), vcf, first+".pk", filepath.Join(dir, "third.bin")); err != nil {
+		t.Fatalf("Generate over a corrupt cache entry: %v", err)
+	}
+	if info, err := os.Stat(entries[0]); err != nil || info.Size() <= int64(len("corrupt")) {
+		t.Errorf("corrupt cache entry was not replaced: %v, %v", info, err)
+	}
+
+	// Circuits of another shape or for another backend get their own entry.
+	paths := map[string]bool{}
+	for _, tt := range []struct {
+		settings Settings
+		depth    int
+	}{
+		{Settings{}, 4},
+		{Settings{}, 5},
+		{Settings{Hash: HashPoseidon2}, 4},
+		{Settings{Backend: BackendPlonk}, 4},
+	} {
+		path, err := tt.settings.ccsCachePath(cache, NewVariantMembershipCircuit(tt.depth, tt.settings.Hash))
+		if err != nil {
+			t.Fatalf("ccsCachePath: %v", err)
+		}
+		paths[path] = true
+	}
+	if len(paths) != 4 {
+		t.Errorf("distinct circuits share cache entries: %v", paths)
+	}
+}
+
+func TestKeyCache(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	cache := filepath.Join(dir, "keys")
+	settings := Settings{KeyCacheDir: cache}
+
+	// The second setup of the same circuit reuses the first one's keys, so
+	// either verifying key verifies either proof.
+	first, second := filepath.Join(dir, "first.bin"), filepath.Join(dir, "second.bin")
+	for _, out := range []string{first, second} {
+		if err := (&ZygosityProof{Settings: settings, Locus: "15:28365618"}).Generate(t.Context(), vcf, "", out); err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+	}
+	if ok, err := (Settings{}).verifyProof(t.Context(), first+".vk", second); err != nil || !ok {
+		t.Fatalf("Verify with the cached key = %v, %v", ok, err)
+	}
+	entries, err := os.ReadDir(cache)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("cache holds %v, %v; want one key pair", entries, err)
+	}
+
+	// A changed circuit definition, here the envelope's nonce input, sets
+	// up new keys.
+	settings.Nonce = "challenge"
+	third := filepath.Join(dir, "third.bin")
+	if err := (&ZygosityProof{Settings: settings, Locus: "15:28365618"}).Generate(t.Context(), vcf, "", third); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if entries, err = os.ReadDir(cache); err != nil || len(entries) != 2 {
+		t.Errorf("cache holds %v, %v; want a key pair per circuit", entries, err)
+	}
+	if _, err := (Settings{}).verifyProof(t.Context(), first+".vk", third); err == nil {
+		t.Errorf("a changed circuit should not reuse the old keys")
+	}
+
+	// An unreadable entry is replaced by a fresh setup.
+	for _, entry := range entries {
+		if err := os.WriteFile(filepath.Join(cache, entry.Name(), "key.pk"), []byte("corrupt"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fourth := filepath.Join(dir, "fourth.bin")
+	if err := (&ZygosityProof{Locus: "15:28365618", Settings: Settings{KeyCacheDir: cache}}).Generate(t.Context(), vcf, "", fourth); err != nil {
+		t.Fatalf("Generate over a corrupt entry: %v", err)
+	}
+	if ok, err := (Settings{}).verifyProof(t.Context(), fourth+".vk", fourth); err != nil || !ok {
+		t.Errorf("Verify after replacing the entry = %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,109 @@
+package proofs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TraitCheck is what a VCF holds at a trait panel variant, and whether a
+// proof over it could be generated from the VCF.
+type TraitCheck struct {
+	Trait   string `json:"trait"`
+	Gene    string `json:"gene"`
+	RSID    string `json:"rsid,omitempty"`
+	Variant string `json:"variant"`
+	// Present is set when the VCF has a record for the variant that passes
+	// the imputation policy and call filter.
+	Present bool `json:"present"`
+	// Genotype is the call at the variant's record, such as A|G, with . for
+	// a missing allele.
+	Genotype string `json:"genotype,omitempty"`
+	// Dosage is how many copies of the panel's alt the call carries, for
+	// calls a proof can read.
+	Dosage *int `json:"dosage,omitempty"`
+	// Provable is set when proofs can read the variant's dosage under the
+	// missing genotype policy; Reason says why not otherwise.
+	Provable bool   `json:"provable"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// CheckTraits reports, for each trait panel entry, whether the VCF holds
+// its variant, the call there and whether a proof could be generated over
+// it, reading the VCF once as proofs with s would. With s.Region, only the
+// entries within it are checked.
+func CheckTraits(vcfPath string, s Settings, panel []TraitVariant) ([]TraitCheck, error) {
+	if s.Region != nil {
+		var inRegion []TraitVariant
+		for _, t := range panel {
+			if s.Region.Contains(t.Spec().Locus()) {
+				inRegion = append(inRegion, t)
+			}
+		}
+		panel = inRegion
+	}
+
+	specs := make([]VariantSpec, len(panel))
+	for i, t := range panel {
+		specs[i] = t.Spec()
+	}
+	genotypes, err := readSampleGenotypes(vcfPath, s, s.Sample, specs)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make([]TraitCheck, len(panel))
+	for i, t := range panel {
+		spec := specs[i]
+		genotype, ok := genotypes[spec.Locus()]
+		check := TraitCheck{Trait: t.Trait, Gene: t.Gene, RSID: t.RSID, Variant: spec.String(), Present: ok}
+		if ok {
+			check.Genotype = formatGenotype(genotype)
+		}
+		if dosage, err := checkDosage(spec, genotype, ok, s.Missing); err != nil {
+			check.Reason = err.Error()
+		} else {
+			check.Provable, check.Dosage = true, &dosage
+		}
+		checks[i] = check
+	}
+	return checks, nil
+}
+
+// checkDosage returns the dosage of spec's alt a proof under the missing
+// genotype policy would read from the call, where ok is whether there is
+// one; a variant without a call is read as homozygous reference when the
+// policy allows.
+func checkDosage(spec VariantSpec, genotype Genotype, ok bool, missing MissingPolicy) (int, error) {
+	genotypes := make(map[Locus]Genotype)
+	if ok {
+		genotypes[spec.Locus()] = genotype
+	}
+	if err := missing.apply([]VariantSpec{spec}, genotypes); err != nil {
+		return 0, err
+	}
+	genotype, ok = genotypes[spec.Locus()]
+	if !ok {
+		return 0, nil
+	}
+	dosage, err := genotype.Dosage(spec.Alt)
+	if err != nil {
+		return 0, fmt.Errorf("genotype at %s: %w", spec.Locus(), err)
+	}
+	return dosage, nil
+}
+
+// formatGenotype writes the call's alleles as the VCF's GT does, spelled
+// out, such as A|G or ./. for a no-call.
+func formatGenotype(g Genotype) string {
+	alleles := make([]string, len(g.Alleles))
+	for i, index := range g.Alleles {
+		if alleles[i] = g.Allele(index); alleles[i] == "" {
+			alleles[i] = "."
+		}
+	}
+	separator := "/"
+	if g.Phased {
+		separator = "|"
+	}
+	return strings.Join(alleles, separator)
+}
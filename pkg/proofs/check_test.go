@@ -0,0 +1,55 @@
+package proofs
+
+import "testing"
+
+func TestCheckTraits(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+16	89919709	.	C	T	60	PASS	.	GT	./.
+`)
+	panel := []TraitVariant{
+		{Trait: "Eye color", Gene: "HERC2", Chromosome: 15, Position: 28365618, Ref: "A", Alt: "G"},
+		{Trait: "Red hair", Gene: "MC1R", Chromosome: 16, Position: 89919709, Ref: "C", Alt: "T"},
+		{Trait: "Lactase persistence", Gene: "MCM6", Chromosome: 2, Position: 136608646, Ref: "G", Alt: "A"},
+	}
+
+	checks, err := CheckTraits(vcf, Settings{}, panel)
+	if err != nil {
+		t.Fatalf("CheckTraits: %v", err)
+	}
+	if len(checks) != 3 {
+		t.Fatalf("CheckTraits returned %d checks, want 3", len(checks))
+	}
+
+	if c := checks[0]; !c.Present || c.Genotype != "G|A" || !c.Provable || c.Dosage == nil || *c.Dosage != 1 {
+		t.Errorf("called variant: %+v", c)
+	}
+	if c := checks[1]; !c.Present || c.Genotype != "./." || c.Provable || c.Reason == "" {
+		t.Errorf("no-call under the fail policy: %+v", c)
+	}
+	if c := checks[2]; c.Present || c.Genotype != "" || !c.Provable || *c.Dosage != 0 {
+		t.Errorf("absent variant read as homozygous reference: %+v", c)
+	}
+
+	checks, err = CheckTraits(vcf, Settings{Missing: MissingRequireCoverage}, panel)
+	if err != nil {
+		t.Fatalf("CheckTraits: %v", err)
+	}
+	if c := checks[2]; c.Provable {
+		t.Errorf("absent variant should not be provable when coverage is required: %+v", c)
+	}
+
+	region, err := ParseRegion("15:28000000-29000000")
+	if err != nil {
+		t.Fatalf("ParseRegion: %v", err)
+	}
+	checks, err = CheckTraits(vcf, Settings{Region: &region}, panel)
+	if err != nil {
+		t.Fatalf("CheckTraits: %v", err)
+	}
+	if len(checks) != 1 || checks[0].Gene != "HERC2" {
+		t.Errorf("CheckTraits in %s = %+v, want only HERC2", region, checks)
+	}
+}
@@ -0,0 +1,247 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// DefaultMaxVariants is the number of chromosome slots used when a
+// ChromosomeProof does not set MaxVariants.
+const DefaultMaxVariants = 5
+
+// DefaultTargetChromosome is the chromosome a ChromosomeProof shows is
+// present when it does not set Target.
+const DefaultTargetChromosome = 22
+
+// ChromosomeCircuit defines a minimal circuit that proves
+// a specific chromosome exists in the genome without revealing
+// other genomic information
+type ChromosomeCircuit struct {
+	// Public input - the chromosome number we want to prove exists
+	TargetChromosome frontend.Variable `gnark:",public"`
+
+	// Public input - MiMC commitment to the private chromosome list and salt,
+	// binding the proof to a specific committed dataset
+	Commitment frontend.Variable `gnark:",public"`
+
+	// Private inputs - chromosome data from the VCF file, one slot per
+	// record. The slice length fixes the circuit size at compile time.
+	Chromosomes []frontend.Variable
+
+	// Private salt that keeps the commitment hiding
+	Salt frontend.Variable
+}
+
+// NewChromosomeCircuit allocates a circuit with maxVariants chromosome slots.
+func NewChromosomeCircuit(maxVariants int) *ChromosomeCircuit {
+	return &ChromosomeCircuit{Chromosomes: make([]frontend.Variable, maxVariants)}
+}
+
+// Define declares the circuit constraints
+func (circuit *ChromosomeCircuit) Define(api frontend.API) error {
+	// We want to prove that TargetChromosome exists in our dataset
+	// without revealing which position it was found at
+
+	// If all diffs are non-zero, their product will be non-zero
+	product := frontend.Variable(1)
+	for _, chromosome := range circuit.Chromosomes {
+		product = api.Mul(product, api.Sub(chromosome, circuit.TargetChromosome))
+	}
+	api.AssertIsEqual(product, 0)
+
+	// The private chromosomes must be the ones that were committed to
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	h.Write(circuit.Chromosomes...)
+	h.Write(circuit.Salt)
+	api.AssertIsEqual(h.Sum(), circuit.Commitment)
+
+	return nil
+}
+
+func extractChromosomeNumbers(vcfPath string, maxCount int, imputation ImputationPolicy, limits ReadLimits) ([]int, error) {
+	rdr, err := openVCF(vcfPath, limits, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	chromosomes := make([]int, 0, maxCount)
+	count := 0
+
+	for {
+		variant, err := rdr.next()
+		if err != nil {
+			return nil, err
+		}
+		if variant == nil {
+			break
+		}
+
+		if !imputation.Accepts(variant) {
+			continue
+		}
+
+		chrStr := variant.Chromosome
+		chrStr = strings.TrimPrefix(chrStr, "chr")
+
+		chrNum, err := strconv.Atoi(chrStr)
+		if err == nil {
+			chromosomes = append(chromosomes, chrNum)
+			count++
+		}
+
+		if count >= maxCount {
+			break
+		}
+	}
+
+	return chromosomes, nil
+}
+
+// chromosomeWitness reads the chromosome list the circuit is built over,
+// padded to maxVariants slots. VCFs with more usable records than that are
+// rejected rather than truncated.
+func chromosomeWitness(vcfPath string, maxVariants int, imputation ImputationPolicy, limits ReadLimits) ([]int, error) {
+	if maxVariants <= 0 {
+		return nil, fmt.Errorf("max variants must be positive, got %d", maxVariants)
+	}
+
+	fmt.Println("Reading VCF file...")
+	chromosomes, err := extractChromosomeNumbers(vcfPath, maxVariants+1, imputation, limits)
+	if err != nil {
+		return nil, fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	if len(chromosomes) == 0 {
+		return nil, fmt.Errorf("no valid chromosome entries found in the VCF file")
+	}
+	if len(chromosomes) > maxVariants {
+		return nil, fmt.Errorf("VCF has more than %d chromosome entries; increase the maximum number of variants", maxVariants)
+	}
+
+	fmt.Printf("Found %d chromosome entries: %v\n", len(chromosomes), chromosomes)
+
+	// Pad chromosomes to the fixed circuit size
+	paddedChromosomes := make([]int, maxVariants)
+	copy(paddedChromosomes, chromosomes)
+	return paddedChromosomes, nil
+}
+
+// CommitChromosomes commits to the chromosome list of the VCF at vcfPath,
+// padded to maxVariants slots, so the commitment can be published before any
+// chromosome proof is made.
+func CommitChromosomes(vcfPath string, maxVariants int, imputation ImputationPolicy, limits ReadLimits) (*DatasetCommitment, error) {
+	chromosomes, err := chromosomeWitness(vcfPath, maxVariants, imputation, limits)
+	if err != nil {
+		return nil, err
+	}
+	return newDatasetCommitment("chromosome", chromosomes)
+}
+
+func (p ChromosomeProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	maxVariants := p.MaxVariants
+	if maxVariants == 0 {
+		maxVariants = DefaultMaxVariants
+	}
+
+	// Padding slots hold 0, so only real chromosome numbers can be targets.
+	if p.Target < 0 {
+		return withKind(ErrInvalidInput, fmt.Errorf("target chromosome must be positive, got %d", p.Target))
+	}
+
+	paddedChromosomes, err := chromosomeWitness(vcfPath, maxVariants, p.Imputation, p.Limits)
+	if err != nil {
+		return err
+	}
+
+	commitment := p.Commitment
+	if commitment != nil && commitment.ProofType != "chromosome" {
+		return fmt.Errorf("commitment is for %s proofs, not chromosome", commitment.ProofType)
+	}
+	if commitment == nil {
+		if commitment, err = newDatasetCommitment("chromosome", paddedChromosomes); err != nil {
+			return err
+		}
+	}
+	salt, err := commitment.salt()
+	if err != nil {
+		return err
+	}
+	committed := commitValues(paddedChromosomes, salt)
+	if committed.String() != commitment.Commitment {
+		return fmt.Errorf("VCF chromosomes do not match commitment %s", commitment.Commitment)
+	}
+
+	targetChromosome := p.Target
+	if targetChromosome == 0 {
+		targetChromosome = DefaultTargetChromosome
+	}
+	if !slices.Contains(paddedChromosomes, targetChromosome) {
+		return withKind(ErrNotFound, fmt.Errorf("chromosome %d not found in the VCF", targetChromosome))
+	}
+
+	witness := NewChromosomeCircuit(maxVariants)
+	witness.TargetChromosome = targetChromosome
+	witness.Commitment = committed
+	witness.Salt = salt
+	for i, chromosome := range paddedChromosomes {
+		witness.Chromosomes[i] = chromosome
+	}
+
+	if err := p.Settings.prove(ctx, "chromosome", NewChromosomeCircuit(maxVariants), witness, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("chromosome", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven knowledge of chromosome %d's presence in the genomic data\n", targetChromosome)
+	fmt.Println("without revealing which entries contain this chromosome or any other genomic information.")
+	fmt.Printf("Dataset commitment: %s\n", committed)
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *ChromosomeProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	if p.Target != 0 {
+		public, err := readPublicInputs(proofPath)
+		if err != nil {
+			return false, err
+		}
+		if len(public) != 2 {
+			return false, withKind(ErrVerification, fmt.Errorf("chromosome proof has %d public inputs, expected 2", len(public)))
+		}
+		if !public[0].IsInt64() || public[0].Int64() != int64(p.Target) {
+			return false, withKind(ErrVerification, fmt.Errorf("proof is for chromosome %s, expected %d", public[0], p.Target))
+		}
+		fmt.Printf("Proof shows chromosome %d is present\n", p.Target)
+	}
+
+	if p.ExpectedCommitment != "" {
+		if err := checkCommitment(proofPath, 1, p.ExpectedCommitment); err != nil {
+			return false, err
+		}
+	}
+	return verified, nil
+}
@@ -0,0 +1,231 @@
+package proofs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestChromosomeCircuitCommitment(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	chromosomes := []int{1, 22, 7, 0, 0}
+	salt := big.NewInt(99)
+	commitment := commitValues(chromosomes, salt)
+
+	assignment := func(values []int) *ChromosomeCircuit {
+		a := NewChromosomeCircuit(len(values))
+		a.TargetChromosome = 22
+		a.Commitment = commitment
+		a.Salt = salt
+		for i, v := range values {
+			a.Chromosomes[i] = v
+		}
+		return a
+	}
+
+	if err := test.IsSolved(NewChromosomeCircuit(5), assignment(chromosomes), field); err != nil {
+		t.Fatalf("committed chromosomes should satisfy the circuit: %v", err)
+	}
+
+	// Fabricated chromosomes that still contain the target must not match
+	// the committed dataset.
+	if err := test.IsSolved(NewChromosomeCircuit(5), assignment([]int{22, 0, 0, 0, 0}), field); err == nil {
+		t.Errorf("uncommitted chromosomes should not satisfy the circuit")
+	}
+}
+
+func TestCommitChromosomes(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+chr1	100	.	A	G	60	PASS	.	GT	0/1
+chr22	200	.	C	T	60	PASS	.	GT	0/1
+`)
+
+	c, err := CommitChromosomes(vcf, DefaultMaxVariants, ImputationPolicy{}, ReadLimits{})
+	if err != nil {
+		t.Fatalf("CommitChromosomes: %v", err)
+	}
+	salt, err := c.salt()
+	if err != nil {
+		t.Fatalf("salt: %v", err)
+	}
+	if got := commitValues([]int{1, 22, 0, 0, 0}, salt).String(); got != c.Commitment {
+		t.Errorf("commitment = %s, want %s", c.Commitment, got)
+	}
+}
+
+func TestChromosomeCircuitSizes(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	salt := big.NewInt(5)
+
+	for _, n := range []int{1, 5, 64} {
+		chromosomes := make([]int, n)
+		chromosomes[n-1] = 22
+
+		assignment := NewChromosomeCircuit(n)
+		assignment.TargetChromosome = 22
+		assignment.Commitment = commitValues(chromosomes, salt)
+		assignment.Salt = salt
+		for i, c := range chromosomes {
+			assignment.Chromosomes[i] = c
+		}
+
+		if err := test.IsSolved(NewChromosomeCircuit(n), assignment, field); err != nil {
+			t.Errorf("%d slots: target in the last slot should satisfy the circuit: %v", n, err)
+		}
+
+		assignment.TargetChromosome = 21
+		if err := test.IsSolved(NewChromosomeCircuit(n), assignment, field); err == nil {
+			t.Errorf("%d slots: absent target should not satisfy the circuit", n)
+		}
+	}
+}
+
+func TestChromosomeTarget(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO
+chr1	100	.	A	G	60	PASS	.
+chr7	200	.	C	T	60	PASS	.
+`)
+	out := filepath.Join(t.TempDir(), "chromosome_proof.bin")
+
+	// The default target, chromosome 22, is absent, and negative targets
+	// are rejected.
+	for _, target := range []int{0, -1} {
+		if err := (ChromosomeProof{Target: target}).Generate(t.Context(), vcf, "", out); err == nil {
+			t.Errorf("target %d: expected an error", target)
+		}
+	}
+	if err := (ChromosomeProof{Target: 22}).Generate(t.Context(), vcf, "", out); err == nil || !strings.Contains(err.Error(), "chromosome 22 not found") {
+		t.Errorf("absent target: err = %v", err)
+	}
+
+	if err := (ChromosomeProof{Target: 7}).Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := (&ChromosomeProof{Target: 7}).Verify(t.Context(), out+".vk", out); !ok || err != nil {
+		t.Errorf("Verify(target 7) = %v, %v", ok, err)
+	}
+	if ok, err := (&ChromosomeProof{Target: 1}).Verify(t.Context(), out+".vk", out); ok || err == nil {
+		t.Errorf("Verify(target 1) = %v, %v, want a mismatch", ok, err)
+	}
+}
+
+func TestChromosomeWitnessRejectsTruncation(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO
+1	100	.	A	G	60	PASS	.
+2	100	.	A	G	60	PASS	.
+3	100	.	A	G	60	PASS	.
+`)
+
+	if _, err := chromosomeWitness(vcf, 2, ImputationPolicy{}, ReadLimits{}); err == nil {
+		t.Errorf("three records should not fit in two slots")
+	}
+
+	got, err := chromosomeWitness(vcf, 4, ImputationPolicy{}, ReadLimits{})
+	if err != nil {
+		t.Fatalf("chromosomeWitness: %v", err)
+	}
+	if want := []int{1, 2, 3, 0}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("witness = %v, want %v", got, want)
+	}
+}
+
+func TestCompressedVCF(t *testing.T) {
+	content := `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+1	1000	.	A	G	60	PASS	.	GT	0/1
+7	2000	.	C	T	60	PASS	.	GT	1/1
+22	3000	.	G	A	60	PASS	.	GT	0/1
+`
+	want, err := extractChromosomeNumbers(writeTempVCF(t, content), 10, ImputationPolicy{}, ReadLimits{})
+	if err != nil {
+		t.Fatalf("plain VCF: %v", err)
+	}
+
+	// bgzip writes a series of gzip members ending in an empty one; the
+	// split falls mid-record, as BGZF block boundaries may.
+	split := len(content) - 20
+	members := map[string][]string{
+		"genome.vcf.gz": {content},
+		"genome.bgz":    {content[:split], content[split:], ""},
+		"misnamed.vcf":  {content},
+	}
+	dir := t.TempDir()
+	for name, parts := range members {
+		var buf bytes.Buffer
+		for _, part := range parts {
+			zw := gzip.NewWriter(&buf)
+			zw.Header.Extra = []byte{'B', 'C', 2, 0, 0, 0}
+			zw.Write([]byte(part))
+			zw.Close()
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := extractChromosomeNumbers(path, 10, ImputationPolicy{}, ReadLimits{})
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if !slices.Equal(got, want) {
+			t.Errorf("%s: chromosomes = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestReadLimits(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+1	1000	.	A	G	60	PASS	.	GT	0/1
+1	1000	.	A	G	60	PASS	.	GT	0/1
+7	2000	.	C	T	60	PASS	NOTE=`+strings.Repeat("x", 200)+`	GT	1/1
+22	3000	.	G	A	60	PASS	.	GT	0/1
+`)
+
+	// Extraction stops at the last wanted locus, so records past it do not
+	// count against the limits.
+	first := []Locus{{"1", 1000}}
+	if _, err := extractGenotypes(vcf, Settings{Limits: ReadLimits{MaxRecords: 1, MaxLineBytes: 100}}, locusSpecs(first...)); err != nil {
+		t.Errorf("early stop: %v", err)
+	}
+
+	last := []Locus{{"22", 3000}}
+	if _, err := extractGenotypes(vcf, Settings{Limits: ReadLimits{MaxRecords: 3}}, locusSpecs(last...)); err == nil || !strings.Contains(err.Error(), "more than 3 records") {
+		t.Errorf("record limit: err = %v", err)
+	}
+	if _, err := extractGenotypes(vcf, Settings{Limits: ReadLimits{MaxLineBytes: 100}}, locusSpecs(last...)); err == nil || !strings.Contains(err.Error(), "longer than 100 bytes") {
+		t.Errorf("line limit: err = %v", err)
+	}
+	if _, err := extractGenotypes(vcf, Settings{Limits: ReadLimits{MaxLineBytes: 40}}, locusSpecs(first...)); err == nil || !strings.Contains(err.Error(), "longer than 40 bytes") {
+		t.Errorf("line limit in the header: err = %v", err)
+	}
+	if got, err := extractGenotypes(vcf, Settings{Limits: ReadLimits{MaxRecords: 4, MaxLineBytes: 300}}, locusSpecs(last...)); err != nil || len(got) != 1 {
+		t.Errorf("within limits: genotypes = %v, %v", got, err)
+	}
+
+	// A repeated record takes one leaf, so two variants fill a tree of depth
+	// two besides its sentinels and a third overflows it.
+	header := "##fileformat=VCFv4.2\n#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\n"
+	records := "1\t1000\t.\tA\tG\t60\tPASS\t.\n1\t1000\t.\tA\tG\t60\tPASS\t.\n22\t3000\t.\tG\tA\t60\tPASS\t.\n"
+	if _, err := BuildVariantTree(writeTempVCF(t, header+records), 2, ImputationPolicy{}, HashMiMC, ReadLimits{}); err != nil {
+		t.Errorf("tree with a repeated record: %v", err)
+	}
+	records += "7\t2000\t.\tC\tT\t60\tPASS\t.\n"
+	if _, err := BuildVariantTree(writeTempVCF(t, header+records), 2, ImputationPolicy{}, HashMiMC, ReadLimits{}); err == nil {
+		t.Errorf("variants overflowing the tree should be rejected")
+	}
+}
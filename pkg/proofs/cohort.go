@@ -0,0 +1,482 @@
+package proofs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"os"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// OpeningSuffix is appended to a carrier proof path to locate the opening of
+// its commitment. The opening is private: participants hand it only to the
+// cohort aggregator.
+const OpeningSuffix = ".opening.json"
+
+// noiseBits is the precision of the uniform draw that is mapped onto the
+// noise distribution.
+const noiseBits = 24
+
+// CarrierCircuit commits to a participant's carrier status for a public
+// variant. The commitment is MiMC(VariantID, carrier, Salt).
+type CarrierCircuit struct {
+	VariantID  frontend.Variable `gnark:",public"`
+	Commitment frontend.Variable `gnark:",public"`
+
+	Dosage frontend.Variable
+	Salt   frontend.Variable
+}
+
+func (c *CarrierCircuit) Define(api frontend.API) error {
+	assertIsDosage(api, c.Dosage)
+	carrier := api.Sub(1, api.IsZero(c.Dosage))
+
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.VariantID, carrier, c.Salt)
+	api.AssertIsEqual(h.Sum(), c.Commitment)
+
+	return nil
+}
+
+// CarrierOpening reveals the committed carrier status to the aggregator.
+type CarrierOpening struct {
+	Variant string `json:"variant"`
+	Carrier bool   `json:"carrier"`
+	Salt    string `json:"salt"`
+}
+
+// LaplaceMechanism adds two-sided geometric noise (the discrete Laplace
+// distribution) calibrated to a counting query of sensitivity 1. The noise
+// is truncated to [-Bound, Bound], where the dropped tail mass is below the
+// resolution of the uniform draw.
+type LaplaceMechanism struct {
+	// EpsilonMilli is the privacy budget in thousandths.
+	EpsilonMilli int
+}
+
+// NewLaplaceMechanism returns the mechanism for privacy budget epsilon.
+func NewLaplaceMechanism(epsilon float64) (LaplaceMechanism, error) {
+	if epsilon < 0.01 || epsilon > 20 {
+		return LaplaceMechanism{}, fmt.Errorf("epsilon must be between 0.01 and 20, got %g", epsilon)
+	}
+	return LaplaceMechanism{EpsilonMilli: int(math.Round(epsilon * 1000))}, nil
+}
+
+func (m LaplaceMechanism) epsilon() float64 {
+	return float64(m.EpsilonMilli) / 1000
+}
+
+// Bound is the largest noise magnitude the mechanism can add.
+func (m LaplaceMechanism) Bound() int {
+	return int(math.Ceil(noiseBits * math.Ln2 / m.epsilon()))
+}
+
+// thresholds returns the 2*Bound cut points that map a uniform noiseBits-bit
+// draw r onto the noise: the noise is (number of thresholds <= r) - Bound.
+func (m LaplaceMechanism) thresholds() []uint64 {
+	bound := m.Bound()
+	alpha := math.Exp(-m.epsilon())
+
+	weights := make([]float64, 2*bound+1)
+	total := 0.0
+	for i := range weights {
+		weights[i] = math.Pow(alpha, math.Abs(float64(i-bound)))
+		total += weights[i]
+	}
+
+	scale := float64(uint64(1) << noiseBits)
+	thresholds := make([]uint64, 2*bound)
+	cumulative := 0.0
+	for i := range thresholds {
+		cumulative += weights[i]
+		thresholds[i] = uint64(math.Round(cumulative / total * scale))
+	}
+	return thresholds
+}
+
+// sample maps the uniform draw r onto the noise distribution.
+func (m LaplaceMechanism) sample(r uint64) int {
+	shifted := 0
+	for _, t := range m.thresholds() {
+		if r >= t {
+			shifted++
+		}
+	}
+	return shifted - m.Bound()
+}
+
+// release adds the noise drawn from r to count and clamps the result to the
+// feasible range [0, participants].
+func (m LaplaceMechanism) release(count, participants int, r uint64) int {
+	return min(max(count+m.sample(r), 0), participants)
+}
+
+// CohortCircuit proves a noisy count of carriers among committed
+// participants. The noise is drawn in-circuit from a hash of the
+// aggregator's committed seed and the participant commitments, so the
+// released count is fixed once the seed is committed.
+type CohortCircuit struct {
+	VariantID      frontend.Variable   `gnark:",public"`
+	EpsilonMilli   frontend.Variable   `gnark:",public"`
+	SeedCommitment frontend.Variable   `gnark:",public"`
+	NoisyCount     frontend.Variable   `gnark:",public"`
+	Commitments    []frontend.Variable `gnark:",public"`
+
+	Carriers []frontend.Variable
+	Salts    []frontend.Variable
+	Seed     frontend.Variable
+
+	Mechanism LaplaceMechanism `gnark:"-"`
+}
+
+// newCohortCircuit allocates a circuit for the given number of participants.
+func newCohortCircuit(participants int, mechanism LaplaceMechanism) *CohortCircuit {
+	return &CohortCircuit{
+		Commitments: make([]frontend.Variable, participants),
+		Carriers:    make([]frontend.Variable, participants),
+		Salts:       make([]frontend.Variable, participants),
+		Mechanism:   mechanism,
+	}
+}
+
+func (c *CohortCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.EpsilonMilli, c.Mechanism.EpsilonMilli)
+
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+
+	count := frontend.Variable(0)
+	for i := range c.Commitments {
+		api.AssertIsBoolean(c.Carriers[i])
+		count = api.Add(count, c.Carriers[i])
+
+		h.Reset()
+		h.Write(c.VariantID, c.Carriers[i], c.Salts[i])
+		api.AssertIsEqual(h.Sum(), c.Commitments[i])
+	}
+
+	h.Reset()
+	h.Write(c.Seed)
+	api.AssertIsEqual(h.Sum(), c.SeedCommitment)
+
+	h.Reset()
+	h.Write(c.Seed)
+	h.Write(c.Commitments...)
+	draw := api.ToBinary(h.Sum())
+	r := api.FromBinary(draw[:noiseBits]...)
+
+	shifted := frontend.Variable(0)
+	for _, t := range c.Mechanism.thresholds() {
+		shifted = api.Add(shifted, isAtLeast(api, r, t, noiseBits+1))
+	}
+
+	participants := len(c.Commitments)
+	bound := c.Mechanism.Bound()
+	countBits := bits.Len(uint(participants + 2*bound + 1))
+
+	// noisy = clamp(count + shifted - bound, 0, participants)
+	value := api.Add(count, shifted)
+	lower := api.Select(isAtLeast(api, value, bound, countBits), api.Sub(value, bound), 0)
+	noisy := api.Select(isAtLeast(api, lower, participants, countBits), participants, lower)
+	api.AssertIsEqual(noisy, c.NoisyCount)
+
+	return nil
+}
+
+// isAtLeast returns 1 when a >= b, for a and b both below 2^nbBits.
+func isAtLeast(api frontend.API, a, b frontend.Variable, nbBits int) frontend.Variable {
+	diff := api.Add(api.Sub(a, b), new(big.Int).Lsh(big.NewInt(1), uint(nbBits)))
+	return api.ToBinary(diff, nbBits+1)[nbBits]
+}
+
+func randomFieldElement() (*big.Int, error) {
+	return rand.Int(rand.Reader, ecc.BN254.ScalarField())
+}
+
+// CommitCohortSeed draws the secret seed the noise of a cohort release is
+// drawn from, saves it to seedPath and returns its commitment. The
+// aggregator publishes the commitment before collecting carrier proofs, so
+// it cannot pick the noise once it knows who carries the variant.
+func CommitCohortSeed(seedPath string) (*big.Int, error) {
+	seed, err := randomFieldElement()
+	if err != nil {
+		return nil, fmt.Errorf("generating noise seed: %w", err)
+	}
+	// A seed whose commitment is already published must not be replaced.
+	f, err := os.OpenFile(seedPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("writing noise seed: %w", err)
+	}
+	if _, err := fmt.Fprintln(f, seed); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("writing noise seed: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("writing noise seed: %w", err)
+	}
+	return mimcHash(seed), nil
+}
+
+func readCohortSeed(seedPath string) (*big.Int, error) {
+	data, err := os.ReadFile(seedPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading noise seed: %w", err)
+	}
+	seed, ok := new(big.Int).SetString(strings.TrimSpace(string(data)), 10)
+	if !ok || seed.Sign() < 0 || seed.Cmp(ecc.BN254.ScalarField()) >= 0 {
+		return nil, fmt.Errorf("%s does not hold a noise seed", seedPath)
+	}
+	return seed, nil
+}
+
+func writeOpening(outputPath string, opening CarrierOpening) error {
+	data, err := json.MarshalIndent(opening, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding commitment opening: %w", err)
+	}
+	if err := os.WriteFile(outputPath+OpeningSuffix, data, 0600); err != nil {
+		return fmt.Errorf("writing commitment opening: %w", err)
+	}
+	return nil
+}
+
+func readOpening(proofPath string) (*CarrierOpening, error) {
+	data, err := os.ReadFile(proofPath + OpeningSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("reading commitment opening: %w", err)
+	}
+
+	var opening CarrierOpening
+	if err := json.Unmarshal(data, &opening); err != nil {
+		return nil, fmt.Errorf("parsing commitment opening: %w", err)
+	}
+	return &opening, nil
+}
+
+func (p *CarrierProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	variant, err := ParseVariantSpec(p.Variant)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Reading VCF...")
+	genotypes, err := extractGenotypes(vcfPath, p.Settings, []VariantSpec{variant})
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	dosage, err := variantDosage(genotypes, variant)
+	if err != nil {
+		return err
+	}
+	carrier := dosage > 0
+
+	salt, err := randomFieldElement()
+	if err != nil {
+		return fmt.Errorf("generating salt: %w", err)
+	}
+
+	assignment := &CarrierCircuit{
+		VariantID:  variant.ID(),
+		Commitment: mimcHash(variant.ID(), big.NewInt(int64(boolToInt(carrier))), salt),
+		Dosage:     dosage,
+		Salt:       salt,
+	}
+
+	if err := p.Settings.prove(ctx, "carrier", &CarrierCircuit{}, assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("carrier", p.Settings)); err != nil {
+		return err
+	}
+
+	if err := writeOpening(outputPath, CarrierOpening{
+		Variant: variant.String(),
+		Carrier: carrier,
+		Salt:    salt.String(),
+	}); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have committed to the carrier status for %s\n", variant)
+	fmt.Println("without revealing it to anyone but the cohort aggregator.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+	fmt.Printf("Opening saved to: %s%s (share only with the aggregator)\n", outputPath, OpeningSuffix)
+
+	return nil
+}
+
+func (p *CarrierProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	return p.verifyProof(ctx, verifyingKeyPath, proofPath)
+}
+
+// Generate is not supported for cohort proofs, which are built from carrier
+// proofs rather than a VCF; use Aggregate.
+func (p *CohortProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	return fmt.Errorf("cohort proofs are aggregated from carrier proofs, not generated from a VCF")
+}
+
+// Aggregate verifies each participant's carrier proof, checks its opening
+// against the public commitment and proves the noisy carrier count, with
+// noise drawn from the seed in SeedFile.
+func (p *CohortProof) Aggregate(ctx context.Context, provingKeyPath string, outputPath string) error {
+	variant, err := ParseVariantSpec(p.Variant)
+	if err != nil {
+		return err
+	}
+	mechanism, err := NewLaplaceMechanism(p.Epsilon)
+	if err != nil {
+		return err
+	}
+	if len(p.CarrierProofs) == 0 {
+		return fmt.Errorf("at least one carrier proof is required")
+	}
+	if p.SeedFile == "" {
+		return withKind(ErrInvalidInput, fmt.Errorf("a noise seed committed before the carrier proofs were collected is required"))
+	}
+	seed, err := readCohortSeed(p.SeedFile)
+	if err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+	seedCommitment := mimcHash(seed)
+	if p.SeedCommitment != "" && seedCommitment.String() != p.SeedCommitment {
+		return withKind(ErrInvalidInput, fmt.Errorf("noise seed %s does not match commitment %s", p.SeedFile, p.SeedCommitment))
+	}
+
+	participants := len(p.CarrierProofs)
+	assignment := newCohortCircuit(participants, mechanism)
+	commitments := make([]*big.Int, participants)
+	seen := make(map[string]bool)
+	count := 0
+
+	for i, proofPath := range p.CarrierProofs {
+		fmt.Printf("Checking carrier proof %s...\n", proofPath)
+		if _, err := p.verifyProof(ctx, proofPath+".vk", proofPath); err != nil {
+			return fmt.Errorf("carrier proof %s: %w", proofPath, err)
+		}
+
+		public, err := readPublicInputs(proofPath)
+		if err != nil {
+			return fmt.Errorf("carrier proof %s: %w", proofPath, err)
+		}
+		if len(public) != 2 {
+			return fmt.Errorf("carrier proof %s has %d public inputs, expected 2", proofPath, len(public))
+		}
+		if public[0].Cmp(variant.ID()) != 0 {
+			return fmt.Errorf("carrier proof %s is for a different variant", proofPath)
+		}
+		commitment := public[1]
+		if seen[commitment.String()] {
+			return fmt.Errorf("carrier proof %s duplicates another participant", proofPath)
+		}
+		seen[commitment.String()] = true
+
+		opening, err := readOpening(proofPath)
+		if err != nil {
+			return fmt.Errorf("carrier proof %s: %w", proofPath, err)
+		}
+		salt, ok := new(big.Int).SetString(opening.Salt, 10)
+		if !ok {
+			return fmt.Errorf("carrier proof %s: invalid salt in opening", proofPath)
+		}
+		carrier := big.NewInt(int64(boolToInt(opening.Carrier)))
+		if mimcHash(variant.ID(), carrier, salt).Cmp(commitment) != 0 {
+			return fmt.Errorf("carrier proof %s: opening does not match the commitment", proofPath)
+		}
+
+		commitments[i] = commitment
+		assignment.Commitments[i] = commitment
+		assignment.Carriers[i] = carrier
+		assignment.Salts[i] = salt
+		count += boolToInt(opening.Carrier)
+	}
+
+	draw := mimcHash(append([]*big.Int{seed}, commitments...)...)
+	r := new(big.Int).And(draw, big.NewInt(1<<noiseBits-1)).Uint64()
+	noisy := mechanism.release(count, participants, r)
+
+	assignment.VariantID = variant.ID()
+	assignment.EpsilonMilli = mechanism.EpsilonMilli
+	assignment.SeedCommitment = seedCommitment
+	assignment.NoisyCount = noisy
+	assignment.Seed = seed
+
+	p.Settings.stamp()
+	if err := p.Settings.prove(ctx, "cohort", newCohortCircuit(participants, mechanism), assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("cohort", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven a noisy count of %d carriers of %s among %d participants (ε = %g)\n",
+		noisy, variant, participants, mechanism.epsilon())
+	fmt.Println("without revealing any participant's carrier status.")
+	fmt.Printf("Noise seed commitment: %s\n", seedCommitment)
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *CohortProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) < 4 {
+		return false, withKind(ErrVerification, fmt.Errorf("cohort proof has %d public inputs, expected at least 4", len(public)))
+	}
+	fmt.Printf("Noisy carrier count: %s of %d participants (ε = %g)\n",
+		public[3], len(public)-4, float64(public[1].Int64())/1000)
+
+	if p.Variant != "" {
+		variant, err := ParseVariantSpec(p.Variant)
+		if err != nil {
+			return false, withKind(ErrInvalidInput, err)
+		}
+		if public[0].Cmp(variant.ID()) != 0 {
+			return false, withKind(ErrVerification, fmt.Errorf("proof is for a different variant than %s", variant))
+		}
+		fmt.Printf("Variant: %s\n", variant)
+	}
+	if p.Epsilon != 0 {
+		mechanism, err := NewLaplaceMechanism(p.Epsilon)
+		if err != nil {
+			return false, withKind(ErrInvalidInput, err)
+		}
+		if public[1].Cmp(big.NewInt(int64(mechanism.EpsilonMilli))) != 0 {
+			return false, withKind(ErrVerification, fmt.Errorf("proof spends ε = %g, not %g", float64(public[1].Int64())/1000, mechanism.epsilon()))
+		}
+	}
+	if p.SeedCommitment != "" && public[2].String() != p.SeedCommitment {
+		return false, withKind(ErrVerification, fmt.Errorf("proof's noise seed has commitment %s, expected %s", public[2], p.SeedCommitment))
+	}
+
+	return verified, nil
+}
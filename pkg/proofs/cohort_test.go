@@ -0,0 +1,167 @@
+package proofs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestLaplaceMechanism(t *testing.T) {
+	m, err := NewLaplaceMechanism(1.0)
+	if err != nil {
+		t.Fatalf("NewLaplaceMechanism: %v", err)
+	}
+
+	thresholds := m.thresholds()
+	if len(thresholds) != 2*m.Bound() {
+		t.Fatalf("got %d thresholds, want %d", len(thresholds), 2*m.Bound())
+	}
+	for i := 1; i < len(thresholds); i++ {
+		if thresholds[i] < thresholds[i-1] {
+			t.Fatalf("thresholds are not monotonic at %d", i)
+		}
+	}
+
+	if got := m.sample(0); got >= 0 || got < -m.Bound() {
+		t.Errorf("sample(0) = %d, want in [%d, 0)", got, -m.Bound())
+	}
+	if got := m.sample(1<<noiseBits - 1); got <= 0 || got > m.Bound() {
+		t.Errorf("sample(max) = %d, want in (0, %d]", got, m.Bound())
+	}
+	if got := m.sample(1 << (noiseBits - 1)); got != 0 {
+		t.Errorf("sample(midpoint) = %d, want 0", got)
+	}
+
+	if got := m.release(0, 5, 0); got != 0 {
+		t.Errorf("release clamps below zero: got %d", got)
+	}
+	if got := m.release(5, 5, 1<<noiseBits-1); got != 5 {
+		t.Errorf("release clamps above participants: got %d", got)
+	}
+
+	if _, err := NewLaplaceMechanism(0); err == nil {
+		t.Errorf("epsilon 0 should be rejected")
+	}
+}
+
+func TestCarrierCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	variantID := big.NewInt(42)
+	salt := big.NewInt(7)
+
+	for dosage := 0; dosage <= 2; dosage++ {
+		carrier := big.NewInt(int64(boolToInt(dosage > 0)))
+		assignment := &CarrierCircuit{
+			VariantID:  variantID,
+			Commitment: mimcHash(variantID, carrier, salt),
+			Dosage:     dosage,
+			Salt:       salt,
+		}
+		if err := test.IsSolved(&CarrierCircuit{}, assignment, field); err != nil {
+			t.Errorf("dosage %d should satisfy the circuit: %v", dosage, err)
+		}
+
+		flipped := big.NewInt(1 - carrier.Int64())
+		assignment.Commitment = mimcHash(variantID, flipped, salt)
+		if err := test.IsSolved(&CarrierCircuit{}, assignment, field); err == nil {
+			t.Errorf("dosage %d should not open a commitment to carrier=%d", dosage, flipped)
+		}
+	}
+}
+
+func TestCohortCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	m, err := NewLaplaceMechanism(2.0)
+	if err != nil {
+		t.Fatalf("NewLaplaceMechanism: %v", err)
+	}
+
+	variantID := big.NewInt(42)
+	seed := big.NewInt(1234)
+	carriers := []int{1, 0, 1, 1}
+
+	assignment := newCohortCircuit(len(carriers), m)
+	commitments := []*big.Int{seed}
+	count := 0
+	for i, c := range carriers {
+		salt := big.NewInt(int64(100 + i))
+		commitment := mimcHash(variantID, big.NewInt(int64(c)), salt)
+		assignment.Commitments[i] = commitment
+		assignment.Carriers[i] = c
+		assignment.Salts[i] = salt
+		commitments = append(commitments, commitment)
+		count += c
+	}
+
+	draw := mimcHash(commitments...)
+	r := new(big.Int).And(draw, big.NewInt(1<<noiseBits-1)).Uint64()
+	noisy := m.release(count, len(carriers), r)
+
+	assignment.VariantID = variantID
+	assignment.EpsilonMilli = m.EpsilonMilli
+	assignment.SeedCommitment = mimcHash(seed)
+	assignment.Seed = seed
+
+	for claimed := 0; claimed <= len(carriers); claimed++ {
+		assignment.NoisyCount = claimed
+		err := test.IsSolved(newCohortCircuit(len(carriers), m), assignment, field)
+		if claimed == noisy && err != nil {
+			t.Errorf("noisy count %d should satisfy the circuit: %v", claimed, err)
+		}
+		if claimed != noisy && err == nil {
+			t.Errorf("count %d should not satisfy the circuit (noisy count is %d)", claimed, noisy)
+		}
+	}
+}
+
+func TestCohortProof(t *testing.T) {
+	dir := t.TempDir()
+	const variant = "7:117199644:ATCT:A"
+	var carriers []string
+	for i, gt := range []string{"0/1", "0/0", "1/1"} {
+		vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+7	117199644	.	ATCT	A	60	PASS	.	GT	`+gt+"\n")
+		out := filepath.Join(dir, fmt.Sprintf("carrier_%d.bin", i))
+		if err := (&CarrierProof{Variant: variant}).Generate(t.Context(), vcf, "", out); err != nil {
+			t.Fatalf("carrier %d: Generate: %v", i, err)
+		}
+		carriers = append(carriers, out)
+	}
+
+	out := filepath.Join(dir, "cohort_proof.bin")
+	if err := (&CohortProof{Variant: variant, CarrierProofs: carriers, Epsilon: 1}).Aggregate(t.Context(), "", out); err == nil {
+		t.Errorf("Aggregate without a committed noise seed should fail")
+	}
+	seedPath := filepath.Join(dir, "cohort.seed")
+	commitment, err := CommitCohortSeed(seedPath)
+	if err != nil {
+		t.Fatalf("CommitCohortSeed: %v", err)
+	}
+	if _, err := CommitCohortSeed(seedPath); err == nil {
+		t.Errorf("CommitCohortSeed should not replace a committed seed")
+	}
+	proof := &CohortProof{Variant: variant, CarrierProofs: carriers, Epsilon: 1, SeedFile: seedPath, SeedCommitment: commitment.String()}
+	if err := proof.Aggregate(t.Context(), "", out); err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); !ok || err != nil {
+		t.Errorf("Verify for the proof's variant, epsilon and seed = %v, %v", ok, err)
+	}
+	for _, other := range []*CohortProof{
+		{Variant: "7:117199644:ATCT:G"},
+		{Epsilon: 0.5},
+		{SeedCommitment: mimcHash(big.NewInt(1)).String()},
+	} {
+		if ok, err := other.Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+			t.Errorf("Verify for variant %q, epsilon %g, seed commitment %q = %v, %v; want ErrVerification", other.Variant, other.Epsilon, other.SeedCommitment, ok, err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package proofs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// DatasetCommitment is a commitment to the private values a proof is
+// built from. Commitment is public and can be published ahead of any proof;
+// Salt, for commitments that need one to stay hiding, must stay with the
+// data owner.
+type DatasetCommitment struct {
+	ProofType  string `json:"proof_type"`
+	Commitment string `json:"commitment"`
+	Salt       string `json:"salt,omitempty"`
+	// Hash is the hash a variant tree commitment was built with; empty
+	// means MiMC.
+	Hash HashAlgorithm `json:"hash,omitempty"`
+}
+
+func newDatasetCommitment(proofType string, values []int) (*DatasetCommitment, error) {
+	salt, err := randomFieldElement()
+	if err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+	return &DatasetCommitment{
+		ProofType:  proofType,
+		Commitment: commitValues(values, salt).String(),
+		Salt:       salt.String(),
+	}, nil
+}
+
+// salt returns the commitment salt as a field element.
+func (c *DatasetCommitment) salt() (*big.Int, error) {
+	salt, ok := new(big.Int).SetString(c.Salt, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid commitment salt")
+	}
+	return salt, nil
+}
+
+// commitValues hashes values followed by salt with MiMC.
+func commitValues(values []int, salt *big.Int) *big.Int {
+	inputs := make([]*big.Int, 0, len(values)+1)
+	for _, v := range values {
+		inputs = append(inputs, big.NewInt(int64(v)))
+	}
+	return mimcHash(append(inputs, salt)...)
+}
+
+// ReadCommitment loads a commitment written by WriteCommitment.
+func ReadCommitment(path string) (*DatasetCommitment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading commitment: %w", err)
+	}
+
+	var c DatasetCommitment
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing commitment: %w", err)
+	}
+	return &c, nil
+}
+
+// WriteCommitment saves c, including its private salt, to path.
+func WriteCommitment(path string, c *DatasetCommitment) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding commitment: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing commitment: %w", err)
+	}
+	return nil
+}
+
+// checkCommitment checks that the public input at index of the proof at
+// proofPath equals the expected commitment.
+func checkCommitment(proofPath string, index int, expected string) error {
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return err
+	}
+	if index >= len(public) {
+		return withKind(ErrVerification, fmt.Errorf("proof has %d public inputs, expected more than %d", len(public), index))
+	}
+	if public[index].String() != expected {
+		return withKind(ErrVerification, fmt.Errorf("proof is bound to commitment %s, expected %s", public[index], expected))
+	}
+
+	fmt.Printf("Proof is bound to dataset commitment %s\n", expected)
+	return nil
+}
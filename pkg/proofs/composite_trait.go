@@ -0,0 +1,152 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// CompositeTrait is a phenotype determined jointly by several SNPs. Table
+// holds the phenotype for every combination of ALT dosages, indexed by
+// sum(dosage[i] * 3^i). Zero marks combinations with no defined phenotype.
+type CompositeTrait struct {
+	Name  string
+	SNPs  []VariantSpec
+	Table []int
+}
+
+// validate checks that the decision table covers every genotype combination.
+func (t CompositeTrait) validate() error {
+	if len(t.SNPs) == 0 {
+		return fmt.Errorf("trait %s has no SNPs", t.Name)
+	}
+	if want := compositeTableSize(len(t.SNPs)); len(t.Table) != want {
+		return fmt.Errorf("trait %s decision table has %d entries, expected %d", t.Name, len(t.Table), want)
+	}
+	return nil
+}
+
+// Phenotype looks up the phenotype for the given per-SNP ALT dosages.
+func (t CompositeTrait) Phenotype(dosages []int) (int, error) {
+	if len(dosages) != len(t.SNPs) {
+		return 0, fmt.Errorf("trait %s takes %d genotypes, got %d", t.Name, len(t.SNPs), len(dosages))
+	}
+
+	index, scale := 0, 1
+	for i, dosage := range dosages {
+		if dosage < 0 || dosage > 2 {
+			return 0, fmt.Errorf("dosage %d of %s is out of range", dosage, t.SNPs[i])
+		}
+		index += dosage * scale
+		scale *= 3
+	}
+	if t.Table[index] == 0 {
+		return 0, withKind(ErrNotFound, fmt.Errorf("genotypes %v do not determine a %s phenotype", dosages, t.Name))
+	}
+	return t.Table[index], nil
+}
+
+func compositeTableSize(snps int) int {
+	size := 1
+	for range snps {
+		size *= 3
+	}
+	return size
+}
+
+// CompositeTraitCircuit proves that private genotypes at K SNPs map to the
+// public phenotype under a public decision table.
+type CompositeTraitCircuit struct {
+	Phenotype frontend.Variable   `gnark:",public"`
+	Table     []frontend.Variable `gnark:",public"`
+
+	// Private inputs - ALT dosage at each SNP
+	Genotypes []frontend.Variable
+}
+
+// NewCompositeTraitCircuit allocates a circuit over k SNPs.
+func NewCompositeTraitCircuit(k int) *CompositeTraitCircuit {
+	return &CompositeTraitCircuit{
+		Table:     make([]frontend.Variable, compositeTableSize(k)),
+		Genotypes: make([]frontend.Variable, k),
+	}
+}
+
+func (c *CompositeTraitCircuit) Define(api frontend.API) error {
+	index := frontend.Variable(0)
+	scale := 1
+	for _, genotype := range c.Genotypes {
+		assertIsDosage(api, genotype)
+		index = api.Add(index, api.Mul(genotype, scale))
+		scale *= 3
+	}
+
+	api.AssertIsEqual(selector.Mux(api, index, c.Table...), c.Phenotype)
+
+	return nil
+}
+
+// proveCompositeTrait reads the trait's SNPs from the VCF and proves the
+// resulting phenotype as proofType, returning the phenotype.
+func proveCompositeTrait(ctx context.Context, trait CompositeTrait, proofType string, vcfPath string, settings Settings, provingKeyPath string, outputPath string) (int, error) {
+	if err := trait.validate(); err != nil {
+		return 0, err
+	}
+
+	fmt.Printf("Reading %d %s SNPs from VCF...\n", len(trait.SNPs), trait.Name)
+	genotypes, err := extractGenotypes(vcfPath, settings, trait.SNPs)
+	if err != nil {
+		return 0, fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	dosages, err := panelDosages(trait.SNPs, genotypes)
+	if err != nil {
+		return 0, err
+	}
+	phenotype, err := trait.Phenotype(dosages)
+	if err != nil {
+		return 0, err
+	}
+
+	assignment := NewCompositeTraitCircuit(len(trait.SNPs))
+	assignment.Phenotype = phenotype
+	for i, entry := range trait.Table {
+		assignment.Table[i] = entry
+	}
+	for i, dosage := range dosages {
+		assignment.Genotypes[i] = dosage
+	}
+
+	if err := settings.prove(ctx, proofType, NewCompositeTraitCircuit(len(trait.SNPs)), assignment, provingKeyPath, outputPath); err != nil {
+		return 0, err
+	}
+	return phenotype, nil
+}
+
+// verifyCompositeTrait verifies a composite trait proof, checks that it was
+// made against the trait's decision table and returns the proven phenotype.
+func (s Settings) verifyCompositeTrait(ctx context.Context, trait CompositeTrait, verifyingKeyPath string, proofPath string) (int, error) {
+	if _, err := s.verifyProof(ctx, verifyingKeyPath, proofPath); err != nil {
+		return 0, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return 0, err
+	}
+	if len(public) != 1+len(trait.Table) {
+		return 0, fmt.Errorf("%s proof has %d public inputs, expected %d", trait.Name, len(public), 1+len(trait.Table))
+	}
+	for i, entry := range trait.Table {
+		if public[1+i].Cmp(big.NewInt(int64(entry))) != 0 {
+			return 0, fmt.Errorf("%s proof was made against a different decision table", trait.Name)
+		}
+	}
+	if public[0].Sign() == 0 {
+		return 0, fmt.Errorf("%s proof claims no defined phenotype", trait.Name)
+	}
+	return int(public[0].Int64()), nil
+}
@@ -0,0 +1,75 @@
+package proofs
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func compositeAssignment(trait CompositeTrait, phenotype int, genotypes ...int) *CompositeTraitCircuit {
+	assignment := NewCompositeTraitCircuit(len(trait.SNPs))
+	assignment.Phenotype = phenotype
+	for i, entry := range trait.Table {
+		assignment.Table[i] = entry
+	}
+	for i, g := range genotypes {
+		assignment.Genotypes[i] = g
+	}
+	return assignment
+}
+
+func TestCompositeTraitCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	trait := CompositeTrait{
+		Name: "test",
+		SNPs: []VariantSpec{mustParseVariant(t, "1:100:A:G"), mustParseVariant(t, "2:200:C:T")},
+		Table: []int{
+			1, 2, 3,
+			4, 5, 6,
+			7, 8, 9,
+		},
+	}
+	if err := trait.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	for a := 0; a <= 2; a++ {
+		for b := 0; b <= 2; b++ {
+			want, err := trait.Phenotype([]int{a, b})
+			if err != nil {
+				t.Fatalf("Phenotype: %v", err)
+			}
+			if want != 1+a+3*b {
+				t.Errorf("Phenotype(%d, %d) = %d, want %d", a, b, want, 1+a+3*b)
+			}
+
+			circuit := NewCompositeTraitCircuit(2)
+			if err := test.IsSolved(circuit, compositeAssignment(trait, want, a, b), field); err != nil {
+				t.Errorf("genotypes (%d, %d) should prove phenotype %d: %v", a, b, want, err)
+			}
+			if err := test.IsSolved(circuit, compositeAssignment(trait, want%9+1, a, b), field); err == nil {
+				t.Errorf("genotypes (%d, %d) should not prove phenotype %d", a, b, want%9+1)
+			}
+		}
+	}
+
+	// Dosage 3 at the first SNP would otherwise alias (0, 1).
+	if err := test.IsSolved(NewCompositeTraitCircuit(2), compositeAssignment(trait, 4, 3, 0), field); err == nil {
+		t.Errorf("out-of-range genotype should not satisfy the circuit")
+	}
+}
+
+func TestCompositeTraitValidate(t *testing.T) {
+	trait := CompositeTrait{Name: "short", SNPs: []VariantSpec{mustParseVariant(t, "1:100:A:G")}, Table: []int{0, 1}}
+	if err := trait.validate(); err == nil {
+		t.Errorf("table with 2 entries for 1 SNP should be rejected")
+	}
+	trait.Table = []int{1, 0, 2}
+	if _, err := trait.Phenotype([]int{1}); err == nil {
+		t.Errorf("Phenotype should reject an undefined table entry")
+	}
+	if _, err := EyeColorTrait.Phenotype([]int{0}); err == nil {
+		t.Errorf("Phenotype should reject the wrong number of genotypes")
+	}
+}
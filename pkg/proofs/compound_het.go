@@ -0,0 +1,218 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// maxPathogenicVariants is the number of per-haplotype slots in the
+// compound-het circuit; genes with fewer listed variants leave the rest zero.
+const maxPathogenicVariants = 8
+
+// PathogenicVariants lists, per gene, the recessive pathogenic variants a
+// compound-het proof may draw on (GRCh37).
+var PathogenicVariants = map[string][]DefiningVariant{
+	"CFTR": {
+		{RSID: "rs113993960", Chromosome: "7", Position: 117199644, Ref: "ATCT", Alt: "A"}, // F508del
+		{RSID: "rs113993959", Chromosome: "7", Position: 117227832, Ref: "G", Alt: "T"},    // G542X
+		{RSID: "rs75527207", Chromosome: "7", Position: 117227865, Ref: "G", Alt: "A"},     // G551D
+		{RSID: "rs77010898", Chromosome: "7", Position: 117282620, Ref: "G", Alt: "A"},     // W1282X
+	},
+}
+
+// CompoundHetCircuit proves whether two distinct pathogenic variants of a
+// gene sit on opposite haplotypes. Only the gene and the verdict are
+// public; which variants are carried, and on which haplotype, stays
+// private. The gene's variant list is compiled into the circuit, so each
+// gene has keys of its own.
+type CompoundHetCircuit struct {
+	GeneID      frontend.Variable `gnark:",public"`
+	CompoundHet frontend.Variable `gnark:",public"`
+
+	Haplotype1 [maxPathogenicVariants]frontend.Variable
+	Haplotype2 [maxPathogenicVariants]frontend.Variable
+
+	Gene string `gnark:"-"`
+}
+
+func (c *CompoundHetCircuit) Define(api frontend.API) error {
+	variants, ok := PathogenicVariants[c.Gene]
+	if !ok {
+		return fmt.Errorf("no pathogenic variant list for gene: %s", c.Gene)
+	}
+
+	// The public gene ID must name the gene whose variant list is compiled
+	// in, and slots past the end of the list carry no variant.
+	api.AssertIsEqual(c.GeneID, geneID(c.Gene))
+
+	count1 := frontend.Variable(0)
+	count2 := frontend.Variable(0)
+	homozygous := frontend.Variable(0)
+
+	for i := 0; i < maxPathogenicVariants; i++ {
+		api.AssertIsBoolean(c.Haplotype1[i])
+		api.AssertIsBoolean(c.Haplotype2[i])
+		if i >= len(variants) {
+			api.AssertIsEqual(c.Haplotype1[i], 0)
+			api.AssertIsEqual(c.Haplotype2[i], 0)
+			continue
+		}
+
+		count1 = api.Add(count1, c.Haplotype1[i])
+		count2 = api.Add(count2, c.Haplotype2[i])
+		homozygous = api.Add(homozygous, api.Mul(c.Haplotype1[i], c.Haplotype2[i]))
+	}
+
+	// The number of (i, j) pairs with i on haplotype 1 and j on haplotype 2
+	// is count1*count2; removing the i == j pairs leaves the compound-het
+	// pairs, which is non-zero exactly when the subject is compound het.
+	pairs := api.Sub(api.Mul(count1, count2), homozygous)
+	verdict := api.Sub(1, api.IsZero(pairs))
+	api.AssertIsEqual(verdict, c.CompoundHet)
+
+	return nil
+}
+
+// phaseHaplotypes returns, for each haplotype, which of variants it carries.
+// Heterozygous calls must be phased, and in the same phase set, whenever more
+// than one variant is heterozygous.
+func phaseHaplotypes(variants []DefiningVariant, genotypes map[Locus]Genotype) ([2][]bool, error) {
+	var haplotypes [2][]bool
+	for h := range haplotypes {
+		haplotypes[h] = make([]bool, len(variants))
+	}
+
+	var phaseSets []string
+	heterozygous, phased := 0, true
+	for i, v := range variants {
+		genotype, ok := genotypes[Locus{normalizeChromosome(v.Chromosome), v.Position}]
+		if !ok {
+			continue
+		}
+		if genotype.Ref != v.Ref {
+			return haplotypes, fmt.Errorf("%s reference allele is %s, expected %s", v.RSID, genotype.Ref, v.Ref)
+		}
+		if len(genotype.Alleles) != 2 {
+			return haplotypes, fmt.Errorf("%s genotype is not diploid", v.RSID)
+		}
+
+		altIndex := genotype.altIndex(v.Alt)
+		for h, allele := range genotype.Alleles {
+			if allele < 0 {
+				return haplotypes, fmt.Errorf("%s genotype is missing", v.RSID)
+			}
+			haplotypes[h][i] = allele == altIndex
+		}
+
+		if haplotypes[0][i] != haplotypes[1][i] {
+			heterozygous++
+			phased = phased && genotype.Phased
+			if genotype.PhaseSet != "" && genotype.PhaseSet != "." {
+				phaseSets = append(phaseSets, genotype.PhaseSet)
+			}
+		}
+	}
+
+	if heterozygous > 1 {
+		if !phased {
+			return haplotypes, fmt.Errorf("%d heterozygous variants are not phased", heterozygous)
+		}
+		for _, ps := range phaseSets {
+			if ps != phaseSets[0] {
+				return haplotypes, fmt.Errorf("heterozygous variants lie in different phase sets")
+			}
+		}
+	}
+
+	return haplotypes, nil
+}
+
+func (p *CompoundHetProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	gene := strings.ToUpper(p.Gene)
+	variants, ok := PathogenicVariants[gene]
+	if !ok {
+		return fmt.Errorf("no pathogenic variant list for gene: %s. Supported genes: CFTR", p.Gene)
+	}
+
+	specs := make([]VariantSpec, len(variants))
+	for i, v := range variants {
+		specs[i] = v.Spec()
+	}
+
+	fmt.Printf("Reading %s pathogenic variants from VCF...\n", gene)
+	genotypes, err := extractGenotypes(vcfPath, p.Settings, specs)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	haplotypes, err := phaseHaplotypes(variants, genotypes)
+	if err != nil {
+		return fmt.Errorf("cannot phase %s variants: %w", gene, err)
+	}
+
+	assignment := &CompoundHetCircuit{GeneID: geneID(gene), Gene: gene}
+	count1, count2, homozygous := 0, 0, 0
+	for i := 0; i < maxPathogenicVariants; i++ {
+		assignment.Haplotype1[i], assignment.Haplotype2[i] = 0, 0
+		if i < len(variants) {
+			h1, h2 := boolToInt(haplotypes[0][i]), boolToInt(haplotypes[1][i])
+			assignment.Haplotype1[i], assignment.Haplotype2[i] = h1, h2
+			count1, count2, homozygous = count1+h1, count2+h2, homozygous+h1*h2
+		}
+	}
+	compoundHet := count1*count2-homozygous > 0
+	assignment.CompoundHet = boolToInt(compoundHet)
+
+	if err := p.Settings.prove(ctx, "compoundhet", &CompoundHetCircuit{Gene: gene}, assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("compoundhet", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	if compoundHet {
+		fmt.Printf("We have proven the subject is compound heterozygous for %s pathogenic variants\n", gene)
+	} else {
+		fmt.Printf("We have proven the subject is not compound heterozygous for %s pathogenic variants\n", gene)
+	}
+	fmt.Println("without revealing which variants are carried or on which haplotype.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *CompoundHetProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 2 {
+		return false, withKind(ErrVerification, fmt.Errorf("compoundhet proof has %d public inputs, expected 2", len(public)))
+	}
+
+	gene := string(public[0].Bytes())
+	if p.Gene != "" && !strings.EqualFold(p.Gene, gene) {
+		return false, withKind(ErrVerification, fmt.Errorf("proof is for %s, not %s", gene, p.Gene))
+	}
+	if public[1].Sign() != 0 {
+		fmt.Printf("The subject is compound heterozygous for %s pathogenic variants\n", gene)
+	} else {
+		fmt.Printf("The subject is not compound heterozygous for %s pathogenic variants\n", gene)
+	}
+
+	return verified, nil
+}
@@ -0,0 +1,105 @@
+package proofs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const compoundHetVCF = `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	SAMPLE1
+7	117227832	rs113993959	G	T	.	PASS	.	GT	0|1
+7	117227865	rs75527207	G	A	.	PASS	.	GT	1|0
+`
+
+func TestCompoundHetProofBindsGene(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "compoundhet_proof.bin")
+	if err := (&CompoundHetProof{Gene: "CFTR"}).Generate(t.Context(), writeTempVCF(t, compoundHetVCF), "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	assertInputBound(t, out, 0)
+
+	if ok, err := (&CompoundHetProof{Gene: "cftr"}).Verify(t.Context(), out+".vk", out); !ok || err != nil {
+		t.Errorf("Verify for the proof's gene = %v, %v", ok, err)
+	}
+	if ok, err := (&CompoundHetProof{Gene: "BRCA1"}).Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+		t.Errorf("Verify for another gene = %v, %v; want ErrVerification", ok, err)
+	}
+}
+
+func TestCompoundHetCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+
+	haplotypes := func(h1, h2 []int) ([maxPathogenicVariants]frontend.Variable, [maxPathogenicVariants]frontend.Variable) {
+		var a, b [maxPathogenicVariants]frontend.Variable
+		for i := range a {
+			a[i], b[i] = h1[i], h2[i]
+		}
+		return a, b
+	}
+
+	tests := []struct {
+		name    string
+		h1, h2  []int
+		verdict int
+	}{
+		{"trans", []int{1, 0, 0, 0, 0, 0, 0, 0}, []int{0, 1, 0, 0, 0, 0, 0, 0}, 1},
+		{"cis", []int{1, 1, 0, 0, 0, 0, 0, 0}, []int{0, 0, 0, 0, 0, 0, 0, 0}, 0},
+		{"homozygous", []int{1, 0, 0, 0, 0, 0, 0, 0}, []int{1, 0, 0, 0, 0, 0, 0, 0}, 0},
+	}
+
+	for _, tt := range tests {
+		h1, h2 := haplotypes(tt.h1, tt.h2)
+		honest := &CompoundHetCircuit{GeneID: geneID("CFTR"), CompoundHet: tt.verdict, Haplotype1: h1, Haplotype2: h2}
+		if err := test.IsSolved(&CompoundHetCircuit{Gene: "CFTR"}, honest, field); err != nil {
+			t.Errorf("%s: the honest verdict should satisfy the circuit: %v", tt.name, err)
+		}
+
+		lying := &CompoundHetCircuit{GeneID: geneID("CFTR"), CompoundHet: 1 - tt.verdict, Haplotype1: h1, Haplotype2: h2}
+		if err := test.IsSolved(&CompoundHetCircuit{Gene: "CFTR"}, lying, field); err == nil {
+			t.Errorf("%s: the opposite verdict should not satisfy the circuit", tt.name)
+		}
+	}
+
+	// CFTR lists four variants, so a witness may neither claim another gene
+	// nor set a slot past them.
+	h1, h2 := haplotypes([]int{1, 0, 0, 0, 0, 0, 0, 0}, []int{0, 1, 0, 0, 0, 0, 0, 0})
+	otherGene := &CompoundHetCircuit{GeneID: geneID("BRCA1"), CompoundHet: 1, Haplotype1: h1, Haplotype2: h2}
+	if err := test.IsSolved(&CompoundHetCircuit{Gene: "CFTR"}, otherGene, field); err == nil {
+		t.Errorf("another gene's ID should not satisfy the CFTR circuit")
+	}
+	h1, h2 = haplotypes([]int{1, 0, 0, 0, 0, 0, 0, 0}, []int{0, 0, 0, 0, 0, 1, 0, 0})
+	unlisted := &CompoundHetCircuit{GeneID: geneID("CFTR"), CompoundHet: 1, Haplotype1: h1, Haplotype2: h2}
+	if err := test.IsSolved(&CompoundHetCircuit{Gene: "CFTR"}, unlisted, field); err == nil {
+		t.Errorf("a variant past CFTR's list should not satisfy the circuit")
+	}
+}
+
+func TestPhaseHaplotypes_RequiresPhasing(t *testing.T) {
+	variants := PathogenicVariants["CFTR"]
+	genotypes := map[Locus]Genotype{
+		{"7", 117227832}: {Ref: "G", Alt: []string{"T"}, Alleles: []int{0, 1}},
+		{"7", 117227865}: {Ref: "G", Alt: []string{"A"}, Alleles: []int{1, 0}},
+	}
+
+	if _, err := phaseHaplotypes(variants, genotypes); err == nil {
+		t.Errorf("Two unphased heterozygous variants should be rejected")
+	}
+
+	for locus, g := range genotypes {
+		g.Phased = true
+		genotypes[locus] = g
+	}
+	haplotypes, err := phaseHaplotypes(variants, genotypes)
+	if err != nil {
+		t.Fatalf("Phased variants should not return error: %v", err)
+	}
+	if !haplotypes[1][1] || !haplotypes[0][2] {
+		t.Errorf("Variants were assigned to the wrong haplotypes: %v", haplotypes)
+	}
+}
@@ -0,0 +1,119 @@
+package proofs
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// Curve selects the pairing-friendly curve proofs are compiled and proved
+// over. The zero value is BN254.
+type Curve string
+
+const (
+	CurveBN254 Curve = "bn254"
+	// CurveBLS12381 has a larger security margin than BN254 at the cost of
+	// slower proving.
+	CurveBLS12381 Curve = "bls12-381"
+	// CurveBLS12377 is the inner curve of recursive aggregation; its
+	// Groth16 proofs can be verified cheaply inside BW6-761 circuits.
+	CurveBLS12377 Curve = "bls12-377"
+	// CurveBW6761 has BLS12-377's base field as its scalar field, so its
+	// circuits can verify BLS12-377 proofs recursively.
+	CurveBW6761 Curve = "bw6-761"
+)
+
+// ParseCurve parses a curve name; empty means BN254.
+func ParseCurve(s string) (Curve, error) {
+	switch c := Curve(strings.ToLower(s)); c {
+	case "", CurveBN254:
+		return CurveBN254, nil
+	case CurveBLS12381, CurveBLS12377, CurveBW6761:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unknown curve %q (expected bn254, bls12-381, bls12-377 or bw6-761)", s)
+	}
+}
+
+func (c Curve) String() string {
+	if c == "" {
+		return string(CurveBN254)
+	}
+	return string(c)
+}
+
+func (c Curve) id() ecc.ID {
+	switch c {
+	case CurveBLS12381:
+		return ecc.BLS12_381
+	case CurveBLS12377:
+		return ecc.BLS12_377
+	case CurveBW6761:
+		return ecc.BW6_761
+	default:
+		return ecc.BN254
+	}
+}
+
+// nativeHashProofs are the proof types whose public inputs include MiMC or
+// Poseidon2 digests computed outside the circuit. Those digests are over
+// BN254, so these proofs cannot be proved over another curve.
+var nativeHashProofs = map[string]bool{
+	"chromosome": true,
+	"carrier":    true,
+	"cohort":     true,
+	"dosage":     true,
+	"membership": true,
+	"absence":    true,
+	"region":     true,
+	"haplotype":  true,
+	"panel":      true,
+}
+
+// checkCurve reports whether a proofType proof can be proved over c.
+func (s Settings) checkCurve(proofType string) error {
+	if s.Curve.id() == ecc.BN254 {
+		return nil
+	}
+	if nativeHashProofs[proofType] {
+		return fmt.Errorf("%s proofs only support the bn254 curve", proofType)
+	}
+	if s.HolderSecret != nil {
+		return fmt.Errorf("holder nullifiers only support the bn254 curve")
+	}
+	return nil
+}
+
+// witnessValues returns the values of a public witness over any supported
+// curve's scalar field.
+func witnessValues(w witness.Witness) ([]*big.Int, error) {
+	var values []*big.Int
+	switch vector := w.Vector().(type) {
+	case fr_bn254.Vector:
+		for i := range vector {
+			values = append(values, vector[i].BigInt(new(big.Int)))
+		}
+	case fr_bls12381.Vector:
+		for i := range vector {
+			values = append(values, vector[i].BigInt(new(big.Int)))
+		}
+	case fr_bls12377.Vector:
+		for i := range vector {
+			values = append(values, vector[i].BigInt(new(big.Int)))
+		}
+	case fr_bw6761.Vector:
+		for i := range vector {
+			values = append(values, vector[i].BigInt(new(big.Int)))
+		}
+	default:
+		return nil, fmt.Errorf("public witness is over an unsupported field")
+	}
+	return values, nil
+}
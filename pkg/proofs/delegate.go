@@ -0,0 +1,434 @@
+package proofs
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// JobSuffix is appended to a proof path to name its delegated proving job.
+const JobSuffix = ".job"
+
+// maxJobSize bounds the proving jobs a prover daemon accepts.
+const maxJobSize = 4 << 30
+
+// Delegation hands proof computation to a prover daemon, for circuits too
+// large to prove locally. The witness is solved locally and encrypted to
+// the daemon's key, so it is protected in transit and at rest, but the
+// daemon decrypts it to prove: it sees the private genotypes and must be
+// trusted with them.
+type Delegation struct {
+	// ProverKey is the daemon's X25519 public key.
+	ProverKey *ecdh.PublicKey
+	// URL, when set, is the daemon the job is submitted to; the proof is
+	// written once it returns. When empty, only the job is written.
+	URL string
+}
+
+// provingJob is everything a prover needs to prove one witness.
+type provingJob struct {
+	// ProofType names the proof in the header of the proof file.
+	ProofType string `json:"proof_type,omitempty"`
+	// Envelope names the envelope inputs of the proof for its header; it
+	// is null in jobs from before headers recorded them.
+	Envelope         []string    `json:"envelope"`
+	Backend          BackendName `json:"backend"`
+	Curve            Curve       `json:"curve"`
+	ConstraintSystem []byte      `json:"constraint_system"`
+	Witness          []byte      `json:"witness"`
+	// ProvingKey is the client's proving key, if it has one; otherwise
+	// the prover runs a setup and returns the new keys.
+	ProvingKey []byte `json:"proving_key,omitempty"`
+}
+
+// sealedJob is a provingJob encrypted to the prover's key with an
+// ephemeral X25519 key agreement and AES-256-GCM.
+type sealedJob struct {
+	EphemeralKey []byte `json:"ephemeral_key"`
+	Nonce        []byte `json:"nonce"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
+// provingResult is a prover's answer to a job. The keys are set only when
+// the prover ran the setup.
+type provingResult struct {
+	Proof        []byte `json:"proof"`
+	ProvingKey   []byte `json:"proving_key,omitempty"`
+	VerifyingKey []byte `json:"verifying_key,omitempty"`
+}
+
+// delegateProof solves the witness for assignment, seals it with cs into a
+// job for the prover and writes the job next to outputPath. When the
+// delegation names a daemon, the job is submitted and the returned proof
+// written to outputPath.
+func (s Settings) delegateProof(ctx context.Context, proofType string, cs constraint.ConstraintSystem, assignment frontend.Circuit, provingKeyPath string, outputPath string) error {
+	fmt.Println("Solving witness...")
+	w, err := frontend.NewWitness(assignment, s.Curve.id().ScalarField())
+	if err != nil {
+		return fmt.Errorf("witness creation error: %w", err)
+	}
+	if err := cs.IsSolved(w); err != nil {
+		return fmt.Errorf("witness does not satisfy the circuit: %w", err)
+	}
+
+	job := provingJob{ProofType: proofType, Envelope: envelopeLayout(assignment), Backend: s.Backend, Curve: s.Curve}
+	if job.Witness, err = w.MarshalBinary(); err != nil {
+		return fmt.Errorf("serializing witness: %w", err)
+	}
+	var buf bytes.Buffer
+	if _, err := cs.WriteTo(&buf); err != nil {
+		return fmt.Errorf("serializing constraint system: %w", err)
+	}
+	job.ConstraintSystem = buf.Bytes()
+	if provingKeyPath != "" {
+		if job.ProvingKey, err = readFile(provingKeyPath); err != nil {
+			return fmt.Errorf("reading proving key: %w", err)
+		}
+	}
+
+	sealed, err := sealJob(s.Delegate.ProverKey, &job)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(outputPath+JobSuffix, sealed, 0600); err != nil {
+		return fmt.Errorf("writing proving job: %w", err)
+	}
+	fmt.Printf("Encrypted proving job saved to: %s%s\n", outputPath, JobSuffix)
+
+	if s.Delegate.URL == "" {
+		return nil
+	}
+	fmt.Printf("Submitting job to %s...\n", s.Delegate.URL)
+	return SubmitJob(ctx, s.Delegate.URL, outputPath+JobSuffix, outputPath)
+}
+
+// printGenerated announces a finished Generate; a delegation without a
+// daemon URL has only solved the witness.
+func (s Settings) printGenerated() {
+	if s.Delegate != nil && s.Delegate.URL == "" {
+		fmt.Println("✅ Witness solved; the proof is computed by the prover daemon.")
+		return
+	}
+	fmt.Println("✅ Proof successfully generated!")
+}
+
+func sealJob(key *ecdh.PublicKey, job *provingJob) ([]byte, error) {
+	plaintext, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("encoding proving job: %w", err)
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating ephemeral key: %w", err)
+	}
+	shared, err := ephemeral.ECDH(key)
+	if err != nil {
+		return nil, fmt.Errorf("key agreement: %w", err)
+	}
+	aead, err := jobCipher(shared, ephemeral.PublicKey().Bytes(), key.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := sealedJob{EphemeralKey: ephemeral.PublicKey().Bytes(), Nonce: make([]byte, aead.NonceSize())}
+	if _, err := rand.Read(sealed.Nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	sealed.Ciphertext = aead.Seal(nil, sealed.Nonce, plaintext, nil)
+	return json.Marshal(sealed)
+}
+
+func openJob(key *ecdh.PrivateKey, data []byte) (*provingJob, error) {
+	var sealed sealedJob
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, fmt.Errorf("parsing proving job: %w", err)
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed.EphemeralKey)
+	if err != nil {
+		return nil, fmt.Errorf("parsing ephemeral key: %w", err)
+	}
+	shared, err := key.ECDH(ephemeral)
+	if err != nil {
+		return nil, fmt.Errorf("key agreement: %w", err)
+	}
+	aead, err := jobCipher(shared, sealed.EphemeralKey, key.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("proving job has a malformed nonce")
+	}
+	plaintext, err := aead.Open(nil, sealed.Nonce, sealed.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting proving job (was it sealed to this prover's key?): %w", err)
+	}
+
+	var job provingJob
+	if err := json.Unmarshal(plaintext, &job); err != nil {
+		return nil, fmt.Errorf("parsing proving job: %w", err)
+	}
+	return &job, nil
+}
+
+// jobCipher derives the job's AES-256-GCM key from the X25519 shared secret
+// and both public keys.
+func jobCipher(shared, ephemeralKey, proverKey []byte) (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write([]byte("vcf-proof proving job v1"))
+	h.Write(shared)
+	h.Write(ephemeralKey)
+	h.Write(proverKey)
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// runJob proves a sealed job on accel. srsPath is the KZG SRS for PLONK
+// jobs that need a setup. It returns ctx's error once ctx is done.
+func runJob(ctx context.Context, key *ecdh.PrivateKey, data []byte, srsPath string, accel Accelerator) (*provingResult, error) {
+	job, err := openJob(key, data)
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := job.Backend.backend()
+	if err != nil {
+		return nil, err
+	}
+	cs := backend.NewConstraintSystem(job.Curve)
+	if _, err := cs.ReadFrom(bytes.NewReader(job.ConstraintSystem)); err != nil {
+		return nil, fmt.Errorf("reading constraint system: %w", err)
+	}
+	w, err := witness.New(job.Curve.id().ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("creating witness: %w", err)
+	}
+	if err := w.UnmarshalBinary(job.Witness); err != nil {
+		return nil, fmt.Errorf("reading witness: %w", err)
+	}
+
+	result := &provingResult{}
+	var pk Serializable
+	if job.ProvingKey != nil {
+		pk = backend.NewProvingKey(job.Curve)
+		if _, err := pk.ReadFrom(bytes.NewReader(job.ProvingKey)); err != nil {
+			return nil, fmt.Errorf("reading proving key: %w", err)
+		}
+	} else {
+		fmt.Println("Setting up new proving system...")
+		var vk Serializable
+		keys, err := runContext(ctx, func() ([2]Serializable, error) {
+			pk, vk, err := backend.Setup(cs, job.Curve, srsPath)
+			return [2]Serializable{pk, vk}, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("setup error: %w", err)
+		}
+		pk, vk = keys[0], keys[1]
+		if result.ProvingKey, err = marshal(pk); err != nil {
+			return nil, err
+		}
+		if result.VerifyingKey, err = marshal(vk); err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Printf("Generating %s proof over %s...\n", job.Backend, job.Curve)
+	proof, err := runContext(ctx, func() (Serializable, error) {
+		return backend.Prove(cs, job.Curve, accel, pk, w)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("proving error: %w", err)
+	}
+	publicWitness, err := w.Public()
+	if err != nil {
+		return nil, fmt.Errorf("public witness error: %w", err)
+	}
+
+	header, err := newProofHeader(job.ProofType, cs, job.Backend, job.Curve)
+	if err != nil {
+		return nil, err
+	}
+	header.Envelope = job.Envelope
+	var buf bytes.Buffer
+	if err := encodeProof(&buf, header, proof, publicWitness); err != nil {
+		return nil, err
+	}
+	result.Proof = buf.Bytes()
+	return result, nil
+}
+
+func marshal(v io.WriterTo) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := v.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("serializing key: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeResult writes a prover's proof to outputPath and any keys it set up
+// next to it as .pk and .vk files.
+func writeResult(result *provingResult, outputPath string) error {
+	if err := writeFileAtomic(outputPath, 0644, writeBytes(result.Proof)); err != nil {
+		return fmt.Errorf("writing proof: %w", err)
+	}
+	if result.VerifyingKey == nil {
+		return nil
+	}
+	if err := writeFileAtomic(outputPath+".pk", 0644, writeBytes(result.ProvingKey)); err != nil {
+		return fmt.Errorf("writing proving key: %w", err)
+	}
+	if err := writeFileAtomic(outputPath+".vk", 0644, writeBytes(result.VerifyingKey)); err != nil {
+		return fmt.Errorf("writing verifying key: %w", err)
+	}
+	fmt.Printf("Keys saved to: %s.pk and %s.vk\n", outputPath, outputPath)
+	return nil
+}
+
+// ProveJob proves the job at jobPath on accel with the prover's key and
+// writes the proof, and any keys it set up, to outputPath.
+func ProveJob(ctx context.Context, key *ecdh.PrivateKey, jobPath string, srsPath string, accel Accelerator, outputPath string) error {
+	data, err := os.ReadFile(jobPath)
+	if err != nil {
+		return fmt.Errorf("reading proving job: %w", err)
+	}
+	result, err := runJob(ctx, key, data, srsPath, accel)
+	if err != nil {
+		return err
+	}
+	return writeResult(result, outputPath)
+}
+
+// SubmitJob sends the job at jobPath to the prover daemon at url and
+// writes the returned proof, and any keys it set up, to outputPath.
+// Cancelling ctx abandons the request.
+func SubmitJob(ctx context.Context, url string, jobPath string, outputPath string) error {
+	job, err := os.Open(jobPath)
+	if err != nil {
+		return fmt.Errorf("opening proving job: %w", err)
+	}
+	defer job.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+"/prove", job)
+	if err != nil {
+		return fmt.Errorf("submitting proving job: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("submitting proving job: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("prover returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	var result provingResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("reading prover response: %w", err)
+	}
+	return writeResult(&result, outputPath)
+}
+
+// ProverHandler returns an HTTP handler that proves jobs POSTed to /prove
+// on accel with the prover's key and answers with the proof. A job whose
+// client disconnects is abandoned.
+func ProverHandler(key *ecdh.PrivateKey, srsPath string, accel Accelerator) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /prove", func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxJobSize))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("reading proving job: %v", err), http.StatusBadRequest)
+			return
+		}
+		result, err := runJob(r.Context(), key, data, srsPath, accel)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	})
+	return mux
+}
+
+// GenerateProverKey creates an X25519 key pair for a prover daemon and
+// writes it as PEM to path+".key" (PKCS#8) and path+".pub" (PKIX).
+func GenerateProverKey(path string) error {
+	priv, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generating key: %w", err)
+	}
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return fmt.Errorf("encoding private key: %w", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(priv.PublicKey())
+	if err != nil {
+		return fmt.Errorf("encoding public key: %w", err)
+	}
+
+	if err := os.WriteFile(path+".key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0600); err != nil {
+		return fmt.Errorf("writing private key: %w", err)
+	}
+	if err := os.WriteFile(path+".pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0644); err != nil {
+		return fmt.Errorf("writing public key: %w", err)
+	}
+	return nil
+}
+
+// ReadProverKey loads a PEM-encoded X25519 private key.
+func ReadProverKey(path string) (*ecdh.PrivateKey, error) {
+	block, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+	priv, ok := key.(*ecdh.PrivateKey)
+	if !ok || priv.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("%s is not an X25519 private key", path)
+	}
+	return priv, nil
+}
+
+// ReadProverPublicKey loads a PEM-encoded X25519 public key.
+func ReadProverPublicKey(path string) (*ecdh.PublicKey, error) {
+	block, err := readPEM(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	pub, ok := key.(*ecdh.PublicKey)
+	if !ok || pub.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("%s is not an X25519 public key", path)
+	}
+	return pub, nil
+}
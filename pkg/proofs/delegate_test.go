@@ -0,0 +1,69 @@
+package proofs
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDelegatedProof(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	keys := filepath.Join(dir, "prover")
+	if err := GenerateProverKey(keys); err != nil {
+		t.Fatalf("GenerateProverKey: %v", err)
+	}
+	priv, err := ReadProverKey(keys + ".key")
+	if err != nil {
+		t.Fatalf("ReadProverKey: %v", err)
+	}
+	pub, err := ReadProverPublicKey(keys + ".pub")
+	if err != nil {
+		t.Fatalf("ReadProverPublicKey: %v", err)
+	}
+
+	// Solving only writes the job; the prover sets up keys and proves it.
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	proof := &ZygosityProof{Settings: Settings{Delegate: &Delegation{ProverKey: pub}}, Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := os.Stat(out); err == nil {
+		t.Fatalf("solving should not write a proof")
+	}
+	if err := ProveJob(t.Context(), priv, out+JobSuffix, "", AcceleratorCPU, out); err != nil {
+		t.Fatalf("ProveJob: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	// A daemon proves a submitted job with the client's proving key.
+	server := httptest.NewServer(ProverHandler(priv, "", AcceleratorCPU))
+	defer server.Close()
+	remote := filepath.Join(dir, "remote.bin")
+	proof.Delegate.URL = server.URL
+	if err := proof.Generate(t.Context(), vcf, out+".pk", remote); err != nil {
+		t.Fatalf("Generate via daemon: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", remote); err != nil || !ok {
+		t.Errorf("Verify daemon proof = %v, %v", ok, err)
+	}
+
+	other := filepath.Join(dir, "other")
+	if err := GenerateProverKey(other); err != nil {
+		t.Fatalf("GenerateProverKey: %v", err)
+	}
+	otherKey, err := ReadProverKey(other + ".key")
+	if err != nil {
+		t.Fatalf("ReadProverKey: %v", err)
+	}
+	if err := ProveJob(t.Context(), otherKey, out+JobSuffix, "", AcceleratorCPU, filepath.Join(dir, "p.bin")); err == nil {
+		t.Errorf("a job should only open with the prover's key")
+	}
+}
@@ -0,0 +1,367 @@
+package proofs
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// DIDVerifyingKeyService is the type of the DID document service entries
+// that publish a verifying key: the key's URL as serviceEndpoint, with the
+// proof type it verifies and its KeyFingerprint.
+const DIDVerifyingKeyService = "ZKProofVerifyingKey"
+
+// maxDIDDocument bounds the DID documents and published keys fetched.
+const maxDIDDocument = 64 << 20
+
+// ed25519Multicodec prefixes an Ed25519 public key in a multibase key.
+var ed25519Multicodec = []byte{0xed, 0x01}
+
+// DIDDocument is the part of a W3C DID document proofs use: the keys its
+// subject signs with and the verifying keys it publishes.
+type DIDDocument struct {
+	Context            []string                `json:"@context,omitempty"`
+	ID                 string                  `json:"id"`
+	VerificationMethod []DIDVerificationMethod `json:"verificationMethod,omitempty"`
+	// AssertionMethod lists the verification methods the subject signs
+	// claims with, such as proofs, by ID or embedded.
+	AssertionMethod []json.RawMessage `json:"assertionMethod,omitempty"`
+	Service         []DIDService      `json:"service,omitempty"`
+}
+
+// DIDVerificationMethod is a public key of a DID document. Ed25519 keys
+// are read from Multikey and Ed25519VerificationKey2020 methods'
+// publicKeyMultibase and from JsonWebKey2020 methods' OKP publicKeyJwk.
+type DIDVerificationMethod struct {
+	ID                 string  `json:"id"`
+	Type               string  `json:"type"`
+	Controller         string  `json:"controller"`
+	PublicKeyMultibase string  `json:"publicKeyMultibase,omitempty"`
+	PublicKeyJwk       *didJWK `json:"publicKeyJwk,omitempty"`
+}
+
+type didJWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// DIDService is a service entry of a DID document. Entries of type
+// DIDVerifyingKeyService publish a verifying key.
+type DIDService struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+	ProofType       string `json:"proofType,omitempty"`
+	KeyFingerprint  string `json:"keyFingerprint,omitempty"`
+}
+
+// DIDKey returns the did:key identifier of an Ed25519 public key, which
+// resolves to a document holding that key alone.
+func DIDKey(key ed25519.PublicKey) string {
+	return "did:key:" + multibaseKey(key)
+}
+
+// multibaseKey encodes key as a base58btc multibase Ed25519 key.
+func multibaseKey(key ed25519.PublicKey) string {
+	return "z" + base58Encode(append(slices.Clone(ed25519Multicodec), key...))
+}
+
+// parseMultibaseKey decodes a base58btc multibase Ed25519 key.
+func parseMultibaseKey(s string) (ed25519.PublicKey, error) {
+	raw, err := base58Decode(strings.TrimPrefix(s, "z"))
+	if err != nil || !strings.HasPrefix(s, "z") || len(raw) != 2+ed25519.PublicKeySize || !bytes.HasPrefix(raw, ed25519Multicodec) {
+		return nil, fmt.Errorf("%q is not a base58btc multibase Ed25519 key", s)
+	}
+	return ed25519.PublicKey(raw[2:]), nil
+}
+
+// PublicKey returns the method's Ed25519 public key.
+func (m DIDVerificationMethod) PublicKey() (ed25519.PublicKey, error) {
+	switch {
+	case m.PublicKeyMultibase != "":
+		return parseMultibaseKey(m.PublicKeyMultibase)
+	case m.PublicKeyJwk != nil && m.PublicKeyJwk.Kty == "OKP" && m.PublicKeyJwk.Crv == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(m.PublicKeyJwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("verification method %s has an invalid Ed25519 JWK", m.ID)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("verification method %s holds no Ed25519 key", m.ID)
+}
+
+// AssertionKeys returns the Ed25519 keys of the document's assertion
+// methods, the keys its subject signs proofs with.
+func (d *DIDDocument) AssertionKeys() []ed25519.PublicKey {
+	var keys []ed25519.PublicKey
+	for _, raw := range d.AssertionMethod {
+		var method DIDVerificationMethod
+		var ref string
+		if json.Unmarshal(raw, &ref) == nil {
+			i := slices.IndexFunc(d.VerificationMethod, func(m DIDVerificationMethod) bool {
+				return m.ID == ref || strings.HasPrefix(ref, "#") && m.ID == d.ID+ref
+			})
+			if i < 0 {
+				continue
+			}
+			method = d.VerificationMethod[i]
+		} else if json.Unmarshal(raw, &method) != nil {
+			continue
+		}
+		if key, err := method.PublicKey(); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// VerifyingKey returns the verifying key service the document publishes
+// for proofType.
+func (d *DIDDocument) VerifyingKey(proofType string) (*DIDService, bool) {
+	for i, s := range d.Service {
+		if s.Type == DIDVerifyingKeyService && strings.EqualFold(s.ProofType, proofType) {
+			return &d.Service[i], true
+		}
+	}
+	return nil, false
+}
+
+// PublishVerifyingKey adds or replaces the service entry publishing the
+// verifying key at vkPath for proofType, to be downloaded from endpoint.
+func (d *DIDDocument) PublishVerifyingKey(proofType, vkPath, endpoint string) error {
+	fingerprint, err := KeyFingerprint(vkPath)
+	if err != nil {
+		return err
+	}
+	service := DIDService{
+		ID:              d.ID + "#vk-" + strings.ToLower(proofType),
+		Type:            DIDVerifyingKeyService,
+		ServiceEndpoint: endpoint,
+		ProofType:       strings.ToLower(proofType),
+		KeyFingerprint:  fingerprint,
+	}
+	if i := slices.IndexFunc(d.Service, func(s DIDService) bool { return s.ID == service.ID }); i >= 0 {
+		d.Service[i] = service
+	} else {
+		d.Service = append(d.Service, service)
+	}
+	return nil
+}
+
+// NewDIDDocument returns the document of a did:web identifier did, with
+// key, when set, as its assertion method, for publishing at the URL
+// DIDWebURL returns.
+func NewDIDDocument(did string, key ed25519.PublicKey) *DIDDocument {
+	doc := &DIDDocument{Context: []string{"https://www.w3.org/ns/did/v1", "https://w3id.org/security/multikey/v1"}, ID: did}
+	if key != nil {
+		doc.addKey(key)
+	}
+	return doc
+}
+
+func (d *DIDDocument) addKey(key ed25519.PublicKey) {
+	id := d.ID + "#key-" + fmt.Sprint(len(d.VerificationMethod)+1)
+	if strings.HasPrefix(d.ID, "did:key:") {
+		id = d.ID + "#" + strings.TrimPrefix(d.ID, "did:key:")
+	}
+	d.VerificationMethod = append(d.VerificationMethod, DIDVerificationMethod{ID: id, Type: "Multikey", Controller: d.ID, PublicKeyMultibase: multibaseKey(key)})
+	ref, _ := json.Marshal(id)
+	d.AssertionMethod = append(d.AssertionMethod, ref)
+}
+
+// DIDWebURL returns the URL the document of a did:web identifier is
+// served at: https://<host>/.well-known/did.json, or
+// https://<host>/<path>/did.json for did:web:<host>:<path>.
+func DIDWebURL(did string) (string, error) {
+	rest, ok := strings.CutPrefix(did, "did:web:")
+	if !ok || rest == "" {
+		return "", fmt.Errorf("%q is not a did:web identifier", did)
+	}
+	parts := strings.Split(rest, ":")
+	host, err := url.PathUnescape(parts[0])
+	if err != nil || host == "" || strings.ContainsAny(host, "/?#") {
+		return "", fmt.Errorf("%q names no valid host", did)
+	}
+	if len(parts) == 1 {
+		return "https://" + host + "/.well-known/did.json", nil
+	}
+	return "https://" + host + "/" + strings.Join(parts[1:], "/") + "/did.json", nil
+}
+
+// DIDResolver resolves did:key and did:web identifiers. did:key
+// documents are derived from the identifier; did:web documents are
+// fetched over HTTPS.
+type DIDResolver struct {
+	// Client makes the requests; nil means http.DefaultClient.
+	Client *http.Client
+}
+
+// Resolve returns the document of did.
+func (r *DIDResolver) Resolve(ctx context.Context, did string) (*DIDDocument, error) {
+	switch {
+	case strings.HasPrefix(did, "did:key:"):
+		key, err := parseMultibaseKey(strings.TrimPrefix(did, "did:key:"))
+		if err != nil {
+			return nil, withKind(ErrInvalidInput, fmt.Errorf("resolving %s: %w", did, err))
+		}
+		doc := &DIDDocument{Context: []string{"https://www.w3.org/ns/did/v1"}, ID: did}
+		doc.addKey(key)
+		return doc, nil
+	case strings.HasPrefix(did, "did:web:"):
+		u, err := DIDWebURL(did)
+		if err != nil {
+			return nil, withKind(ErrInvalidInput, err)
+		}
+		data, err := r.get(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", did, err)
+		}
+		var doc DIDDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("resolving %s: parsing DID document: %w", did, err)
+		}
+		if doc.ID != did {
+			return nil, withKind(ErrVerification, fmt.Errorf("resolving %s: the document is for %s", did, doc.ID))
+		}
+		return &doc, nil
+	}
+	return nil, withKind(ErrInvalidInput, fmt.Errorf("unsupported DID %q: want did:key or did:web", did))
+}
+
+// FetchVerifyingKey fetches the verifying key the document of did
+// publishes for proofType, checking it against the published fingerprint.
+func (r *DIDResolver) FetchVerifyingKey(ctx context.Context, did, proofType string) ([]byte, error) {
+	doc, err := r.Resolve(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	service, ok := doc.VerifyingKey(proofType)
+	if !ok {
+		return nil, withKind(ErrNotFound, fmt.Errorf("%s publishes no %s verifying key", did, proofType))
+	}
+	vk, err := r.get(ctx, service.ServiceEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the %s verifying key of %s: %w", proofType, did, err)
+	}
+	if fingerprint := bytesFingerprint(vk); fingerprint != service.KeyFingerprint {
+		return nil, withKind(ErrVerification, fmt.Errorf("the %s verifying key at %s has fingerprint %s, not the %s that %s publishes", proofType, service.ServiceEndpoint, fingerprint, service.KeyFingerprint, did))
+	}
+	return vk, nil
+}
+
+// CheckVerifyingKey checks that the verifying key with the given
+// fingerprint is the one the document of did publishes for proofType.
+func (r *DIDResolver) CheckVerifyingKey(ctx context.Context, did, proofType, fingerprint string) error {
+	doc, err := r.Resolve(ctx, did)
+	if err != nil {
+		return err
+	}
+	service, ok := doc.VerifyingKey(proofType)
+	if !ok {
+		return withKind(ErrVerification, fmt.Errorf("%s publishes no %s verifying key", did, proofType))
+	}
+	if !strings.EqualFold(service.KeyFingerprint, fingerprint) {
+		return withKind(ErrVerification, fmt.Errorf("the verifying key %s is not the %s key %s publishes", fingerprint, proofType, did))
+	}
+	return nil
+}
+
+// CheckSigner checks that the proof at proofPath is signed with a key the
+// document of did lists as an assertion method. An empty did checks the
+// DID recorded with the signature.
+func (r *DIDResolver) CheckSigner(ctx context.Context, proofPath, did string) error {
+	signer, signed, err := ProofSigner(proofPath)
+	if err != nil {
+		return err
+	}
+	if !signed {
+		return withKind(ErrVerification, errors.New("proof is not signed by its holder"))
+	}
+	if did == "" {
+		meta, err := ReadMetadata(proofPath)
+		if err != nil {
+			return err
+		}
+		if did = meta.HolderSignature.DID; did == "" {
+			return withKind(ErrVerification, errors.New("the proof's signature names no DID"))
+		}
+	}
+	doc, err := r.Resolve(ctx, did)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(doc.AssertionKeys(), func(k ed25519.PublicKey) bool { return k.Equal(signer) }) {
+		return withKind(ErrVerification, fmt.Errorf("proof was signed by a key %s does not list", did))
+	}
+	return nil
+}
+
+// get fetches u, over HTTPS only.
+func (r *DIDResolver) get(ctx context.Context, u string) ([]byte, error) {
+	if !strings.HasPrefix(u, "https://") {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("%s is not an https URL", u))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s answered %s", u, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxDIDDocument))
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Encode encodes data in the base58btc alphabet.
+func base58Encode(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	var out []byte
+	for mod, base := new(big.Int), big.NewInt(58); n.Sign() > 0; {
+		n.DivMod(n, base, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	slices.Reverse(out)
+	return string(out)
+}
+
+// base58Decode decodes base58btc.
+func base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	for _, c := range []byte(s) {
+		i := strings.IndexByte(base58Alphabet, c)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		n.Mul(n, big.NewInt(58))
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	zeros := len(s) - len(strings.TrimLeft(s, base58Alphabet[:1]))
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
@@ -0,0 +1,141 @@
+package proofs
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDIDKey(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	did := DIDKey(pub)
+	// Every Ed25519 did:key starts z6Mk: the multicodec prefix in base58.
+	if !strings.HasPrefix(did, "did:key:z6Mk") {
+		t.Errorf("DIDKey = %s, want a did:key:z6Mk… identifier", did)
+	}
+	doc, err := (&DIDResolver{}).Resolve(t.Context(), did)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if keys := doc.AssertionKeys(); len(keys) != 1 || !keys[0].Equal(pub) {
+		t.Errorf("did:key document holds keys %x, want %x", keys, pub)
+	}
+
+	for _, data := range [][]byte{{}, {0}, {0, 0, 1}, {0xff, 0, 0x10}} {
+		got, err := base58Decode(base58Encode(data))
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("base58 round trip of %x = %x, %v", data, got, err)
+		}
+	}
+}
+
+func TestDIDWebURL(t *testing.T) {
+	tests := []struct {
+		did, want string
+	}{
+		{"did:web:example.org", "https://example.org/.well-known/did.json"},
+		{"did:web:example.org:keys:lab", "https://example.org/keys/lab/did.json"},
+		{"did:web:localhost%3A8443", "https://localhost:8443/.well-known/did.json"},
+	}
+	for _, tt := range tests {
+		if got, err := DIDWebURL(tt.did); err != nil || got != tt.want {
+			t.Errorf("DIDWebURL(%s) = %s, %v; want %s", tt.did, got, err, tt.want)
+		}
+	}
+	for _, did := range []string{"did:key:z6Mk", "did:web:", "did:web:a%2Fb"} {
+		if _, err := DIDWebURL(did); err == nil {
+			t.Errorf("DIDWebURL(%s) succeeded", did)
+		}
+	}
+}
+
+func TestVerifyWithDIDs(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	dir := t.TempDir()
+	proofPath := filepath.Join(dir, "proof.bin")
+	vk := proofPath + ".vk"
+
+	var doc []byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/did.json":
+			w.Write(doc)
+		case "/zygosity.vk":
+			http.ServeFile(w, r, vk)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	did := "did:web:" + strings.ReplaceAll(strings.TrimPrefix(server.URL, "https://"), ":", "%3A")
+	resolver := WithDIDResolver(&DIDResolver{Client: server.Client()})
+	publish := func(holder ed25519.PublicKey) {
+		t.Helper()
+		d := NewDIDDocument(did, holder)
+		if err := d.PublishVerifyingKey("zygosity", vk, server.URL+"/zygosity.vk"); err != nil {
+			t.Fatalf("PublishVerifyingKey: %v", err)
+		}
+		if doc, err = json.Marshal(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Generate(t.Context(), "zygosity", vcf, proofPath, WithPosition("15:28365618"), WithSignKey(key), WithSignDID(did)); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	meta, err := ReadMetadata(proofPath)
+	if err != nil || meta.HolderSignature == nil || meta.HolderSignature.DID != did {
+		t.Fatalf("signature names DID %+v, %v; want %s", meta.HolderSignature, err, did)
+	}
+	publish(pub)
+
+	if _, err := Verify(t.Context(), proofPath, WithKeyDID(did), WithHolderDID(did), resolver); err != nil {
+		t.Errorf("Verify against the DID's key and holder: %v", err)
+	}
+	// Without a key beside the proof, the DID's key is fetched.
+	moved := filepath.Join(dir, "served.vk")
+	if err := os.Rename(vk, moved); err != nil {
+		t.Fatal(err)
+	}
+	vk = moved
+	if _, err := Verify(t.Context(), proofPath, WithKeyDID(did), resolver); err != nil {
+		t.Errorf("Verify with the key fetched from the DID: %v", err)
+	}
+
+	// A local key the DID does not publish is not trusted.
+	otherKey := filepath.Join(dir, "other.vk")
+	if err := os.WriteFile(otherKey, []byte("not the published key"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(t.Context(), proofPath, WithVerifyingKey(otherKey), WithKeyDID(did), resolver); !errors.Is(ErrorKind(err), ErrVerification) {
+		t.Errorf("Verify with a key the DID does not publish = %v, want a verification error", err)
+	}
+
+	publish(otherPub)
+	if _, err := Verify(t.Context(), proofPath, WithVerifyingKey(vk), WithHolderDID(did), resolver); !errors.Is(ErrorKind(err), ErrVerification) {
+		t.Errorf("Verify against a DID not listing the signing key = %v, want a verification error", err)
+	}
+	// The did:key recorded by default names the signing key itself.
+	if err := (&DIDResolver{}).CheckSigner(t.Context(), proofPath, DIDKey(pub)); err != nil {
+		t.Errorf("CheckSigner with the key's did:key: %v", err)
+	}
+}
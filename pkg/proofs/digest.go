@@ -0,0 +1,181 @@
+package proofs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// ManifestSuffix is appended to an extracted VCF's path to locate the
+// manifest ExtractVCF writes beside it.
+const ManifestSuffix = ".manifest.json"
+
+// DigestMode selects whether a proof records the digest of the records of
+// the VCF it was generated from.
+type DigestMode string
+
+const (
+	// DigestNone records no digest.
+	DigestNone DigestMode = "none"
+	// DigestMetadata records the digest in the proof metadata only.
+	DigestMetadata DigestMode = "metadata"
+	// DigestPublic also binds the digest into the proof as a public input,
+	// so the proof cannot be relabeled with another dataset's digest.
+	DigestPublic DigestMode = "public"
+)
+
+// ParseDigestMode converts a CLI string into a DigestMode.
+func ParseDigestMode(s string) (DigestMode, error) {
+	switch mode := DigestMode(strings.ToLower(s)); mode {
+	case "", DigestNone:
+		return DigestNone, nil
+	case DigestMetadata, DigestPublic:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown digest mode: %s. Supported modes: none, metadata, public", s)
+	}
+}
+
+// DatasetManifest describes a VCF written by ExtractVCF, so the digest a
+// proof over it records can be checked against the dataset.
+type DatasetManifest struct {
+	// Source is the file name of the VCF the records were extracted from.
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+	Records   int       `json:"records"`
+	// Digest is the dataset digest of the extracted VCF, as DatasetDigest
+	// computes it.
+	Digest string `json:"digest"`
+}
+
+// WriteManifest writes manifest to path as JSON.
+func WriteManifest(path string, manifest DatasetManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest reads a manifest written by WriteManifest.
+func ReadManifest(path string) (*DatasetManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	var manifest DatasetManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if _, err := hex.DecodeString(manifest.Digest); err != nil || len(manifest.Digest) != 2*sha256.Size {
+		return nil, fmt.Errorf("manifest digest %q is not a SHA-256 digest", manifest.Digest)
+	}
+	return &manifest, nil
+}
+
+// DatasetDigest returns the hex SHA-256 digest of the VCF's records in
+// their canonical form, reading within limits. The header and the file's
+// compression are left out, so an extract that copies records unchanged
+// has the same digest however its header was rewritten.
+func DatasetDigest(vcfPath string, limits ReadLimits) (string, error) {
+	in, err := OpenVCFStream(vcfPath)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	digest := newRecordDigest()
+	records := 0
+	stream := &lineLimiter{r: in, maxLine: limits.MaxLineBytes, keep: func(line []byte) bool {
+		if digest.add(line) {
+			records++
+		}
+		return false
+	}}
+	if _, err := io.Copy(io.Discard, stream); err != nil {
+		return "", fmt.Errorf("reading VCF: %w", err)
+	}
+	if stream.err != nil {
+		return "", stream.err
+	}
+	if limits.MaxRecords > 0 && records > limits.MaxRecords {
+		return "", fmt.Errorf("VCF has more than %d records", limits.MaxRecords)
+	}
+	return digest.String(), nil
+}
+
+// recordDigest hashes VCF data lines in canonical form: line endings
+// dropped, the chromosome named as normalizeChromosome names it and the
+// alleles uppercased, one record per line.
+type recordDigest struct {
+	h hash.Hash
+}
+
+func newRecordDigest() *recordDigest {
+	return &recordDigest{h: sha256.New()}
+}
+
+// add hashes line if it is a data line, reporting whether it was.
+func (d *recordDigest) add(line []byte) bool {
+	line = bytes.TrimRight(line, "\r\n")
+	if len(line) == 0 || line[0] == '#' {
+		return false
+	}
+	fields := strings.Split(string(line), "\t")
+	fields[0] = normalizeChromosome(fields[0])
+	for i := 3; i <= 4 && i < len(fields); i++ {
+		fields[i] = strings.ToUpper(fields[i])
+	}
+	io.WriteString(d.h, strings.Join(fields, "\t"))
+	d.h.Write([]byte{'\n'})
+	return true
+}
+
+func (d *recordDigest) String() string {
+	return hex.EncodeToString(d.h.Sum(nil))
+}
+
+// DigestID encodes a dataset digest as a field element for use as a public
+// input.
+func DigestID(digest string) (*big.Int, error) {
+	b, err := hex.DecodeString(digest)
+	if err != nil || len(b) != sha256.Size {
+		return nil, fmt.Errorf("dataset digest %q is not a SHA-256 digest", digest)
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(b), ecc.BN254.ScalarField()), nil
+}
+
+// CheckDatasetDigest reports whether the proof at proofPath was generated
+// from the dataset with the given digest, and whether the digest is bound
+// into the proof rather than only recorded in its metadata.
+func CheckDatasetDigest(proofPath string, digest string) (bool, error) {
+	meta, err := ReadMetadata(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if meta.DatasetDigest == "" {
+		return false, withKind(ErrVerification, fmt.Errorf("proof records no dataset digest"))
+	}
+	if !strings.EqualFold(meta.DatasetDigest, digest) {
+		return false, withKind(ErrVerification, fmt.Errorf("proof was generated from dataset %s, not %s", meta.DatasetDigest, digest))
+	}
+
+	env, err := readEnvelope(proofPath)
+	if err != nil {
+		return false, err
+	}
+	return env.Digest != nil, nil
+}
@@ -0,0 +1,97 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func TestDatasetDigest(t *testing.T) {
+	records := "15\t28365618\t.\tA\tG\t60\tPASS\t.\tGT\t1|0\n19\t45411941\t.\tT\tC\t60\tPASS\t.\tGT\t0/1\n"
+	digest, err := DatasetDigest(writeTempVCF(t, "##fileformat=VCFv4.2\n#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\tFORMAT\tS1\n"+records), ReadLimits{})
+	if err != nil {
+		t.Fatalf("DatasetDigest: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		vcf  string
+		same bool
+	}{
+		{"other header", "##fileformat=VCFv4.3\n##source=extract\n#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\tFORMAT\tS1\n" + records, true},
+		{"chr prefix and case", "#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\tFORMAT\tS1\nchr15\t28365618\t.\ta\tg\t60\tPASS\t.\tGT\t1|0\r\nchr19\t45411941\t.\tT\tC\t60\tPASS\t.\tGT\t0/1", true},
+		{"other genotype", "#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\tFORMAT\tS1\n15\t28365618\t.\tA\tG\t60\tPASS\t.\tGT\t1|1\n19\t45411941\t.\tT\tC\t60\tPASS\t.\tGT\t0/1\n", false},
+		{"record dropped", "#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\tFORMAT\tS1\n15\t28365618\t.\tA\tG\t60\tPASS\t.\tGT\t1|0\n", false},
+	} {
+		got, err := DatasetDigest(writeTempVCF(t, tt.vcf), ReadLimits{})
+		if err != nil {
+			t.Fatalf("%s: DatasetDigest: %v", tt.name, err)
+		}
+		if (got == digest) != tt.same {
+			t.Errorf("%s: digest %s, original %s, want same = %v", tt.name, got, digest, tt.same)
+		}
+	}
+
+	if _, err := DatasetDigest(writeTempVCF(t, records), ReadLimits{MaxRecords: 1}); err == nil {
+		t.Error("DatasetDigest read past MaxRecords")
+	}
+}
+
+func TestExtractManifestDigest(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+1	1000	.	A	G	60	PASS	.	GT	0/1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	extract := filepath.Join(dir, "extract.vcf")
+	stats, err := ExtractVCF(vcf, extract, ExtractOptions{Variants: locusSpecs(Locus{"15", HERC2Pos})})
+	if err != nil {
+		t.Fatalf("ExtractVCF: %v", err)
+	}
+	manifest, err := ReadManifest(extract + ManifestSuffix)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if digest, err := DatasetDigest(extract, ReadLimits{}); err != nil || manifest.Digest != digest || stats.Digest != digest {
+		t.Fatalf("manifest digest %s, stats %s, extract %s (%v)", manifest.Digest, stats.Digest, digest, err)
+	}
+
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	proof := &ZygosityProof{Settings: Settings{Digest: DigestPublic, ExpectedDigest: manifest.Digest}, Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), extract, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+	if bound, err := CheckDatasetDigest(out, manifest.Digest); err != nil || !bound {
+		t.Errorf("CheckDatasetDigest = %v, %v", bound, err)
+	}
+	if public, err := readPublicInputs(out); err != nil || len(public) != 4 {
+		t.Errorf("readPublicInputs = %d inputs, %v; want the circuit's 4", len(public), err)
+	}
+
+	// The whole VCF is another dataset.
+	other := &ZygosityProof{Settings: Settings{ExpectedDigest: manifest.Digest}, Locus: "15:28365618"}
+	if err := other.Generate(t.Context(), vcf, "", filepath.Join(dir, "other.bin")); err == nil {
+		t.Error("Generate accepted a VCF with another digest")
+	}
+
+	// Swapping the digest in the public witness breaks the proof.
+	p, w, err := readProofFile(out)
+	if err != nil {
+		t.Fatalf("readProofFile: %v", err)
+	}
+	vector := w.Vector().(fr.Vector)
+	vector[len(vector)-1].SetUint64(1)
+	tampered := filepath.Join(dir, "tampered.bin")
+	if err := writeProofFile(tampered, nil, p, w); err != nil {
+		t.Fatalf("writeProofFile: %v", err)
+	}
+	if _, err := (Settings{}).verifyProof(t.Context(), out+".vk", tampered); err == nil {
+		t.Errorf("proof should not verify with a substituted digest")
+	}
+}
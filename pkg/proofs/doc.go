@@ -0,0 +1,66 @@
+// Package proofs generates and verifies zero-knowledge proofs about the
+// genotypes in a VCF file, such as a carried variant, a zygosity or a
+// trait, without revealing the rest of the genome.
+//
+// Most callers need only Generate and Verify, configured with Options:
+//
+//	err := proofs.Generate(ctx, "zygosity", "genome.vcf", "out/zygosity_proof.bin",
+//		proofs.WithPosition("15:28365618"), proofs.WithNonce(challenge))
+//
+//	res, err := proofs.Verify(ctx, "out/zygosity_proof.bin", proofs.WithNonce(challenge))
+//
+// Verify's VerificationResult records what was verified: the circuit, the
+// public inputs by name and the verifying key's fingerprint.
+//
+// Cancelling ctx stops a long proving run: Generate returns ctx's error at
+// once and writes no proof. WithProgress reports how far compiling, setup
+// and proving have got, for showing runs that take minutes. Each stage,
+// from reading the VCF to writing the proof, is also traced as an
+// OpenTelemetry span under ctx's span, for finding where a slow run spends
+// its time.
+//
+// Generate writes the proof, its metadata (MetadataSuffix) and its keys
+// beside the output path; Verify reads them from there unless told
+// otherwise. GenerateTo and VerifyFrom do the same over an io.Reader and
+// io.Writer, with the files packed into one proof bundle. GenerateBytes
+// and VerifyBytes keep the VCF, keys and proof in memory and never touch
+// the disk, for servers and for environments where genomic material must
+// not be written out; WithProvingKeyData and WithVerifyingKeyData give
+// them keys held in memory. Paths scheme://bucket/name, for a scheme
+// registered with RegisterStorage, keep keys and proofs in a Storage such
+// as an object store bucket. A ProofEnvelope carries a proof, its public
+// inputs and metadata as one documented JSON document, which Verify also
+// accepts; Publish pins its proof and verifying key to IPFS and records
+// their CIDs, from which Verify fetches the key WithIPFS. A holder signs
+// proofs as a did:key or did:web identifier, and WithKeyDID and
+// WithHolderDID have Verify trust only the verifying key and signing keys
+// the identifier's DID document publishes. A KeyRegistry maps circuit
+// hashes to verifying key fingerprints in an on-chain contract, and
+// WithKeyRegistry has Verify trust only the key it holds for the proof's
+// circuit. ReadPublicInputs returns what a proof claims, by input name,
+// so a verifier need not be told the claim separately. ProofTypes lists the types with the options each reads.
+// Calls sharing a KeyStore, given with WithKeyStore, read each key file
+// once and may run concurrently. Callers needing more control build a
+// Proof with NewProof from a ProofConfig and call its Generate and Verify
+// methods directly.
+//
+// A trait predicted from a few SNPs is added as data: a TraitDefinition
+// giving its loci, decision table and phenotype names, registered with
+// RegisterTrait, from which the circuit and witness follow.
+//
+// # Stability
+//
+// The package follows semantic versioning: within a major version
+// Generate, Verify, the Options, the Proof interface, NewProof,
+// ProofConfig, Settings and the other exported names keep their meaning,
+// and proofs generated by one minor version verify with the next: a proof
+// file starts with a magic and format version, ReadProofHeader reports
+// the format, and every earlier format stays readable. New
+// proof types, options and fields may be added in minor versions; the
+// zero value of a new field keeps the earlier behaviour. The printed
+// progress messages and the layout of the metadata file beyond its
+// documented fields are not part of the API.
+//
+// Errors carry a kind, read with ErrorKind, saying whether the input, the
+// VCF, proving or verification failed.
+package proofs
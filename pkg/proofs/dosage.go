@@ -0,0 +1,152 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// DosageCircuit proves the total ALT allele dosage across a SNP panel,
+// keeping each per-SNP dosage private. The panel is fixed at compile time
+// and identified by the public PanelID.
+type DosageCircuit struct {
+	PanelID frontend.Variable `gnark:",public"`
+	Total   frontend.Variable `gnark:",public"`
+
+	Dosages []frontend.Variable
+
+	Panel []VariantSpec `gnark:"-"`
+}
+
+// NewDosageCircuit allocates a circuit for panel.
+func NewDosageCircuit(panel []VariantSpec) *DosageCircuit {
+	return &DosageCircuit{Dosages: make([]frontend.Variable, len(panel)), Panel: panel}
+}
+
+func (c *DosageCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.PanelID, panelID(c.Panel))
+
+	total := frontend.Variable(0)
+	for _, dosage := range c.Dosages {
+		assertIsDosage(api, dosage)
+		total = api.Add(total, dosage)
+	}
+	api.AssertIsEqual(total, c.Total)
+
+	return nil
+}
+
+// panelID identifies a SNP panel by the MiMC hash of its variant IDs.
+func panelID(panel []VariantSpec) *big.Int {
+	ids := make([]*big.Int, len(panel))
+	for i, v := range panel {
+		ids[i] = v.ID()
+	}
+	return mimcHash(ids...)
+}
+
+// Dosage returns how many copies of alt the diploid genotype carries.
+// Missing or non-diploid calls are an error rather than a dosage of zero.
+func (g Genotype) Dosage(alt string) (int, error) {
+	if len(g.Alleles) != 2 {
+		return 0, fmt.Errorf("genotype is not diploid")
+	}
+	for _, allele := range g.Alleles {
+		if allele < 0 {
+			return 0, fmt.Errorf("genotype is missing")
+		}
+	}
+	return g.Carries(alt), nil
+}
+
+// panelDosages returns the ALT dosage of each panel SNP. SNPs without a VCF
+// record are taken to be homozygous reference.
+func panelDosages(panel []VariantSpec, genotypes map[Locus]Genotype) ([]int, error) {
+	dosages := make([]int, len(panel))
+	for i, v := range panel {
+		genotype, ok := genotypes[v.Locus()]
+		if !ok {
+			continue
+		}
+		if genotype.Ref != v.Ref {
+			return nil, fmt.Errorf("reference allele at %s is %s, expected %s", v.Locus(), genotype.Ref, v.Ref)
+		}
+
+		dosage, err := genotype.Dosage(v.Alt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v, err)
+		}
+		dosages[i] = dosage
+	}
+	return dosages, nil
+}
+
+func (p *DosageProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+	if len(p.Panel) == 0 {
+		return fmt.Errorf("a SNP panel is required")
+	}
+
+	fmt.Printf("Reading %d panel SNPs from VCF...\n", len(p.Panel))
+	genotypes, err := extractGenotypes(vcfPath, p.Settings, p.Panel)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	dosages, err := panelDosages(p.Panel, genotypes)
+	if err != nil {
+		return err
+	}
+
+	assignment := NewDosageCircuit(p.Panel)
+	assignment.PanelID = panelID(p.Panel)
+	total := 0
+	for i, dosage := range dosages {
+		assignment.Dosages[i] = dosage
+		total += dosage
+	}
+	assignment.Total = total
+
+	if err := p.Settings.prove(ctx, "dosage", NewDosageCircuit(p.Panel), assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("dosage", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven a total ALT allele dosage of %d across %d panel SNPs\n", total, len(p.Panel))
+	fmt.Println("without revealing the genotype at any individual SNP.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *DosageProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 2 {
+		return false, withKind(ErrVerification, fmt.Errorf("dosage proof has %d public inputs, expected 2", len(public)))
+	}
+	if len(p.Panel) > 0 {
+		if public[0].Cmp(panelID(p.Panel)) != 0 {
+			return false, withKind(ErrVerification, fmt.Errorf("proof was made over a different variant panel"))
+		}
+		fmt.Println("Proof is bound to the given variant panel")
+	}
+	fmt.Printf("Total ALT allele dosage: %s\n", public[1])
+
+	return verified, nil
+}
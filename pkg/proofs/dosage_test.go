@@ -0,0 +1,120 @@
+package proofs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+const dosageTestVCF = `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+chr19	45411941	.	T	C	60	PASS	.	GT	0/1
+chr19	45412079	.	C	T	60	PASS	.	GT	1|1
+chr15	28365618	.	A	G	60	PASS	.	GT	./.
+`
+
+func TestPanelDosages(t *testing.T) {
+	vcf := writeTempVCF(t, dosageTestVCF)
+
+	tests := []struct {
+		name    string
+		panel   []string
+		missing MissingPolicy
+		want    []int
+		wantErr bool
+	}{
+		{"het and hom-alt", []string{"19:45411941:T:C", "19:45412079:C:T"}, "", []int{1, 2}, false},
+		{"absent record is hom-ref", []string{"19:45411941:T:C", "1:1000:A:G"}, "", []int{1, 0}, false},
+		{"other ALT", []string{"19:45411941:T:G"}, "", []int{0}, false},
+		{"missing call", []string{"15:28365618:A:G"}, "", nil, true},
+		{"missing call treated as absent", []string{"15:28365618:A:G"}, MissingTreatAsMissing, []int{0}, false},
+		{"absent record without coverage", []string{"19:45411941:T:C", "1:1000:A:G"}, MissingRequireCoverage, nil, true},
+		{"missing call without coverage", []string{"15:28365618:A:G"}, MissingRequireCoverage, nil, true},
+		{"reference mismatch", []string{"19:45411941:G:C"}, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			panel := make([]VariantSpec, len(tt.panel))
+			for i, s := range tt.panel {
+				panel[i] = mustParseVariant(t, s)
+			}
+			var got []int
+			genotypes, err := extractGenotypes(vcf, Settings{Missing: tt.missing}, panel)
+			if err == nil {
+				got, err = panelDosages(panel, genotypes)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("panelDosages = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("panelDosages: %v", err)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("dosage of %s = %d, want %d", tt.panel[i], got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDosageCircuit(t *testing.T) {
+	panel := []VariantSpec{
+		mustParseVariant(t, "19:45411941:T:C"),
+		mustParseVariant(t, "19:45412079:C:T"),
+		mustParseVariant(t, "1:1000:A:G"),
+	}
+	other := []VariantSpec{panel[0], panel[1], mustParseVariant(t, "1:1000:A:T")}
+
+	assignment := func(id any, total int, dosages ...int) *DosageCircuit {
+		c := NewDosageCircuit(panel)
+		c.PanelID = id
+		c.Total = total
+		for i, d := range dosages {
+			c.Dosages[i] = d
+		}
+		return c
+	}
+
+	if err := test.IsSolved(NewDosageCircuit(panel), assignment(panelID(panel), 3, 1, 2, 0), ecc.BN254.ScalarField()); err != nil {
+		t.Errorf("valid dosages rejected: %v", err)
+	}
+
+	invalid := map[string]*DosageCircuit{
+		"wrong total":      assignment(panelID(panel), 2, 1, 2, 0),
+		"dosage above two": assignment(panelID(panel), 3, 3, 0, 0),
+		"negative dosage":  assignment(panelID(panel), 3, 4, -1, 0),
+		"different panel":  assignment(panelID(other), 3, 1, 2, 0),
+	}
+	for name, a := range invalid {
+		if err := test.IsSolved(NewDosageCircuit(panel), a, ecc.BN254.ScalarField()); err == nil {
+			t.Errorf("%s: circuit accepted invalid witness", name)
+		}
+	}
+}
+
+func TestDosageProofBindsPanel(t *testing.T) {
+	panel := []VariantSpec{
+		mustParseVariant(t, "19:45411941:T:C"),
+		mustParseVariant(t, "19:45412079:C:T"),
+	}
+	out := filepath.Join(t.TempDir(), "dosage_proof.bin")
+	proof := &DosageProof{Panel: panel}
+	if err := proof.Generate(t.Context(), writeTempVCF(t, dosageTestVCF), "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); !ok || err != nil {
+		t.Errorf("Verify for the proof's panel = %v, %v", ok, err)
+	}
+	other := &DosageProof{Panel: panel[:1]}
+	if ok, err := other.Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+		t.Errorf("Verify for another panel = %v, %v; want ErrVerification", ok, err)
+	}
+}
@@ -0,0 +1,312 @@
+package proofs
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// envelopeCircuit wraps a proof circuit with the optional public inputs
+// every proof type supports: a holder nullifier, a verifier nonce, a
+// dataset digest and the issuance and expiry times. Each slice holds zero
+// or one element. Their public inputs follow the wrapped circuit's in that
+// order, so the wrapped circuit's input indices are unchanged.
+type envelopeCircuit struct {
+	Circuit   frontend.Circuit
+	Nullifier []frontend.Variable `gnark:",public"`
+	Nonce     []frontend.Variable `gnark:",public"`
+	Digest    []frontend.Variable `gnark:",public"`
+	Issued    []frontend.Variable `gnark:",public"`
+	Expires   []frontend.Variable `gnark:",public"`
+
+	Secret []frontend.Variable
+
+	ProofType string `gnark:"-"`
+}
+
+// newEnvelopeCircuit allocates an envelope around circuit for settings.
+func newEnvelopeCircuit(circuit frontend.Circuit, proofType string, settings Settings) *envelopeCircuit {
+	c := &envelopeCircuit{Circuit: circuit, ProofType: proofType}
+	if settings.HolderSecret != nil {
+		c.Nullifier = make([]frontend.Variable, 1)
+		c.Secret = make([]frontend.Variable, 1)
+	}
+	if settings.Nonce != "" {
+		c.Nonce = make([]frontend.Variable, 1)
+	}
+	if settings.Digest == DigestPublic {
+		c.Digest = make([]frontend.Variable, 1)
+	}
+	if settings.timestamped() {
+		c.Issued = make([]frontend.Variable, 1)
+	}
+	if settings.ValidFor > 0 {
+		c.Expires = make([]frontend.Variable, 1)
+	}
+	return c
+}
+
+// enveloped reports whether proofs generated with the settings are wrapped
+// in an envelope.
+func (s Settings) enveloped() bool {
+	return s.HolderSecret != nil || s.Nonce != "" || s.Digest == DigestPublic || s.timestamped()
+}
+
+func (c *envelopeCircuit) Define(api frontend.API) error {
+	if err := c.Circuit.Define(api); err != nil {
+		return err
+	}
+
+	if len(c.Nullifier) > 0 {
+		h, err := mimc.NewMiMC(api)
+		if err != nil {
+			return err
+		}
+		h.Write(c.Secret[0], circuitID(c.ProofType))
+		api.AssertIsEqual(h.Sum(), c.Nullifier[0])
+	}
+
+	// A public input that appears in no constraint is not bound by the
+	// proof, so square the nonce, digest and times into constraints.
+	bindPublic(api, c.Nonce...)
+	bindPublic(api, c.Digest...)
+	bindPublic(api, c.Issued...)
+	bindPublic(api, c.Expires...)
+
+	return nil
+}
+
+// prove generates a proof of assignment, wrapped in an envelope for
+// proofType when the settings carry a holder secret or a nonce, or bind the
+// dataset digest or issuance time.
+func (s Settings) prove(ctx context.Context, proofType string, circuit, assignment frontend.Circuit, provingKeyPath string, outputPath string) error {
+	s.traceExtract(ctx, proofType)
+	if err := s.checkCurve(proofType); err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+	if err := s.checkOutput(outputPath, provingKeyPath); err != nil {
+		return err
+	}
+	if !s.enveloped() {
+		return withKind(ErrProving, s.generateProof(ctx, proofType, circuit, assignment, provingKeyPath, outputPath))
+	}
+
+	wrapped := newEnvelopeCircuit(assignment, proofType, s)
+	if s.HolderSecret != nil {
+		wrapped.Nullifier[0] = Nullifier(s.HolderSecret, proofType)
+		wrapped.Secret[0] = s.HolderSecret
+	}
+	if s.Nonce != "" {
+		wrapped.Nonce[0] = NonceID(s.Nonce)
+	}
+	if s.Digest == DigestPublic {
+		id, err := DigestID(s.datasetDigest)
+		if err != nil {
+			return withKind(ErrInvalidInput, err)
+		}
+		wrapped.Digest[0] = id
+	}
+	if s.timestamped() {
+		if s.issuedAt.IsZero() {
+			return fmt.Errorf("issuance time of the %s proof was not fixed", proofType)
+		}
+		wrapped.Issued[0] = s.issuedAt.Unix()
+	}
+	if s.ValidFor > 0 {
+		wrapped.Expires[0] = s.issuedAt.Add(s.ValidFor).Unix()
+	}
+
+	return withKind(ErrProving, s.generateProof(ctx, proofType, newEnvelopeCircuit(circuit, proofType, s), wrapped, provingKeyPath, outputPath))
+}
+
+// NonceID encodes a verifier challenge as a field element for use as a
+// public input.
+func NonceID(nonce string) *big.Int {
+	digest := sha256.Sum256([]byte(nonce))
+	return new(big.Int).Mod(new(big.Int).SetBytes(digest[:]), ecc.BN254.ScalarField())
+}
+
+// proofEnvelope is a proof's public inputs split into the wrapped circuit's
+// and the envelope's.
+type proofEnvelope struct {
+	Inputs    []*big.Int
+	Nullifier *big.Int
+	Nonce     *big.Int
+	Digest    *big.Int
+	Issued    *big.Int
+	Expires   *big.Int
+}
+
+// envelopeInputs name the envelope's public inputs in the order they
+// follow the wrapped circuit's.
+var envelopeInputs = []string{"nullifier", "nonce", "digest", "issued", "expires"}
+
+// envelopeLayout names the envelope inputs of circuit, which is empty
+// unless circuit is an envelope.
+func envelopeLayout(circuit frontend.Circuit) []string {
+	layout := []string{}
+	c, ok := circuit.(*envelopeCircuit)
+	if !ok {
+		return layout
+	}
+	for i, inputs := range [][]frontend.Variable{c.Nullifier, c.Nonce, c.Digest, c.Issued, c.Expires} {
+		if len(inputs) > 0 {
+			layout = append(layout, envelopeInputs[i])
+		}
+	}
+	return layout
+}
+
+// envelopeLayout names the envelope inputs the metadata declares.
+func (m *ProofMetadata) envelopeLayout() []string {
+	layout := []string{}
+	for i, declared := range []bool{m.Nullifier != "", m.Nonce != "", m.DigestBound, m.IssuedAt != nil, m.ExpiresAt != nil} {
+		if declared {
+			layout = append(layout, envelopeInputs[i])
+		}
+	}
+	return layout
+}
+
+// readEnvelope reads the proof's public inputs and splits off the envelope
+// inputs its header records, or for files whose header does not, its
+// metadata declares, checking they match the values the metadata records.
+// A proof recording neither is read as carrying no envelope only when it
+// has no more inputs than its circuit, so deleting the metadata cannot hide
+// an expiry time or nonce.
+func readEnvelope(proofPath string) (*proofEnvelope, error) {
+	values, err := readWitnessValues(proofPath)
+	if err != nil {
+		return nil, err
+	}
+	header, err := ReadProofHeader(proofPath)
+	if err != nil && !errors.Is(err, ErrNoHeader) {
+		return nil, err
+	}
+	meta, err := ReadMetadata(proofPath)
+	if err != nil {
+		meta = nil
+	}
+
+	var layout []string
+	switch {
+	case header != nil && header.Envelope != nil:
+		layout = header.Envelope
+		if meta != nil && !slices.Equal(layout, meta.envelopeLayout()) {
+			return nil, fmt.Errorf("proof metadata declares envelope inputs %v, but the proof carries %v", meta.envelopeLayout(), layout)
+		}
+	case meta != nil:
+		layout = meta.envelopeLayout()
+	default:
+		if err := checkBareInputs(header, len(values)); err != nil {
+			return nil, err
+		}
+		return &proofEnvelope{Inputs: values}, nil
+	}
+	if len(layout) > len(values) {
+		return nil, fmt.Errorf("proof has %d public inputs, fewer than its %d envelope inputs", len(values), len(layout))
+	}
+
+	n := len(values) - len(layout)
+	env := &proofEnvelope{Inputs: values[:n]}
+	for i, name := range layout {
+		v := values[n+i]
+		switch name {
+		case "nullifier":
+			env.Nullifier = v
+		case "nonce":
+			env.Nonce = v
+		case "digest":
+			env.Digest = v
+		case "issued":
+			env.Issued = v
+		case "expires":
+			env.Expires = v
+		}
+	}
+	if meta == nil {
+		return env, nil
+	}
+	if meta.ExpiresAt != nil && env.Expires.Cmp(big.NewInt(meta.ExpiresAt.Unix())) != 0 {
+		return nil, fmt.Errorf("proof expiry time does not match its metadata")
+	}
+	if meta.IssuedAt != nil && env.Issued.Cmp(big.NewInt(meta.IssuedAt.Unix())) != 0 {
+		return nil, fmt.Errorf("proof issuance time does not match its metadata")
+	}
+	if meta.DigestBound {
+		id, err := DigestID(meta.DatasetDigest)
+		if err != nil {
+			return nil, err
+		}
+		if env.Digest.Cmp(id) != 0 {
+			return nil, fmt.Errorf("proof dataset digest does not match its metadata")
+		}
+	}
+	if meta.Nonce != "" && env.Nonce.Cmp(NonceID(meta.Nonce)) != 0 {
+		return nil, fmt.Errorf("proof nonce does not match its metadata")
+	}
+	if meta.Nullifier != "" && env.Nullifier.String() != meta.Nullifier {
+		return nil, fmt.Errorf("proof nullifier does not match its metadata")
+	}
+	return env, nil
+}
+
+// checkBareInputs rejects a proof with n public inputs whose header, nil
+// for files without one, names no type whose circuit has as many, since
+// the extra inputs may be envelope inputs nothing records.
+func checkBareInputs(header *ProofHeader, n int) error {
+	if header == nil || header.Type == "" {
+		return fmt.Errorf("proof records neither its type nor its envelope inputs, and has no metadata declaring them")
+	}
+	circuitInputs, err := circuitPublicInputs(header.Type)
+	if err != nil {
+		return fmt.Errorf("proof records no envelope inputs and has no metadata declaring them: %w", err)
+	}
+	if n > circuitInputs {
+		return fmt.Errorf("proof has %d public inputs, more than the %d of a %s circuit, and nothing records which are envelope inputs; its metadata may have been removed", n, circuitInputs, header.Type)
+	}
+	return nil
+}
+
+// circuitPublicInputs counts the public inputs of the circuit of proofType
+// in its default configuration.
+func circuitPublicInputs(proofType string) (int, error) {
+	t, ok := LookupProofType(proofType)
+	if !ok {
+		return 0, fmt.Errorf("unknown proof type %q", proofType)
+	}
+	_, circuit, err := proofCircuit(t.New(ProofConfig{}))
+	if err != nil {
+		return 0, err
+	}
+	count, err := schema.Walk(circuit, reflect.TypeOf((*frontend.Variable)(nil)).Elem(), nil)
+	if err != nil {
+		return 0, err
+	}
+	return count.Public, nil
+}
+
+// CheckNonce reports whether the proof at proofPath is bound to the
+// verifier-supplied nonce.
+func CheckNonce(proofPath string, nonce string) error {
+	env, err := readEnvelope(proofPath)
+	if err != nil {
+		return err
+	}
+	if env.Nonce == nil {
+		return withKind(ErrVerification, fmt.Errorf("proof is not bound to a nonce"))
+	}
+	if env.Nonce.Cmp(NonceID(nonce)) != 0 {
+		return withKind(ErrVerification, fmt.Errorf("proof is bound to a different nonce; it may be stale"))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package proofs
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func TestNonceProof(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "zygosity_proof.bin")
+
+	settings := Settings{HolderSecret: mustHolderSecret(t, dir), Nonce: "challenge-2026-10-17T09:00Z"}
+	proof := &ZygosityProof{Settings: settings, Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	if err := CheckNonce(out, settings.Nonce); err != nil {
+		t.Errorf("CheckNonce with the issued nonce: %v", err)
+	}
+	if err := CheckNonce(out, "challenge-2026-10-16T09:00Z"); err == nil {
+		t.Errorf("proof should not match a different nonce")
+	}
+	if nullifier, ok, err := ProofNullifier(out); err != nil || !ok || nullifier.Cmp(Nullifier(settings.HolderSecret, "zygosity")) != 0 {
+		t.Errorf("ProofNullifier = %v, %v, %v", nullifier, ok, err)
+	}
+	if public, err := readPublicInputs(out); err != nil || len(public) != 4 {
+		t.Errorf("readPublicInputs = %d inputs, %v; want the circuit's 4", len(public), err)
+	}
+
+	// Swapping the nonce in the public witness breaks the proof.
+	p, w, err := readProofFile(out)
+	if err != nil {
+		t.Fatalf("readProofFile: %v", err)
+	}
+	vector := w.Vector().(fr.Vector)
+	vector[len(vector)-1].SetBigInt(NonceID("fresh-nonce"))
+	tampered := filepath.Join(dir, "tampered.bin")
+	if err := writeProofFile(tampered, nil, p, w); err != nil {
+		t.Fatalf("writeProofFile: %v", err)
+	}
+	if _, err := (Settings{}).verifyProof(t.Context(), out+".vk", tampered); err == nil {
+		t.Errorf("proof should not verify with a substituted nonce")
+	}
+}
+
+func TestProofWithoutNonce(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	out := filepath.Join(t.TempDir(), "zygosity_proof.bin")
+	if err := (&ZygosityProof{Locus: "15:28365618"}).Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if err := CheckNonce(out, "anything"); err == nil {
+		t.Errorf("proof without a nonce should fail a nonce check")
+	}
+}
+
+func mustHolderSecret(t *testing.T, dir string) *big.Int {
+	t.Helper()
+	path := filepath.Join(dir, "holder")
+	if err := GenerateHolderSecret(path); err != nil {
+		t.Fatalf("GenerateHolderSecret: %v", err)
+	}
+	secret, err := ReadHolderSecret(path + HolderSecretSuffix)
+	if err != nil {
+		t.Fatalf("ReadHolderSecret: %v", err)
+	}
+	return secret
+}
@@ -0,0 +1,56 @@
+package proofs
+
+import "errors"
+
+// Error kinds say why generating or verifying a proof failed, so callers
+// can branch on the cause with errors.Is instead of matching messages.
+var (
+	// ErrInvalidInput is an invalid option, or an input file other than
+	// the VCF that cannot be read, such as a key or proof file.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrVCF is a VCF that cannot be opened or parsed, or breaks a read
+	// limit.
+	ErrVCF = errors.New("invalid VCF")
+	// ErrNotFound is a variant, locus, genotype or trait a proof claims
+	// that the VCF does not hold.
+	ErrNotFound = errors.New("not found in VCF")
+	// ErrProving is a failure to compile, set up or prove a circuit.
+	ErrProving = errors.New("proving failed")
+	// ErrVerification is a proof that does not verify, or does not state
+	// what the verifier expects of it.
+	ErrVerification = errors.New("verification failed")
+	// ErrUnsupported is a proof type that is registered but cannot be
+	// generated or verified yet.
+	ErrUnsupported = errors.New("not supported")
+)
+
+var errorKinds = []error{ErrInvalidInput, ErrVCF, ErrNotFound, ErrProving, ErrVerification, ErrUnsupported}
+
+// kindError classifies err as kind without changing its message.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string   { return e.err.Error() }
+func (e *kindError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// withKind classifies err as kind, unless it already has a kind: the cause
+// closest to where the error arose wins.
+func withKind(kind error, err error) error {
+	if err == nil || ErrorKind(err) != nil {
+		return err
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+// ErrorKind returns the error kind err was classified as, or nil when it
+// has none.
+func ErrorKind(err error) error {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
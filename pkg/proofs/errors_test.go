@@ -0,0 +1,52 @@
+package proofs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestErrorKinds(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	0/1
+`)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	if err := (&ZygosityProof{Locus: "15:28365618"}).Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"bad locus", (&ZygosityProof{Locus: "15"}).Generate(t.Context(), vcf, "", out), ErrInvalidInput},
+		{"missing VCF", (&ZygosityProof{Locus: "15:28365618"}).Generate(t.Context(), filepath.Join(dir, "none.vcf"), "", out), ErrVCF},
+		{"absent locus", (&WildTypeProof{Locus: "15:28365618"}).Generate(t.Context(), vcf, "", filepath.Join(dir, "wildtype_proof.bin")), ErrNotFound},
+		{"wrong locus", verifyErr(t, &ZygosityProof{Locus: "15:1"}, out+".vk", out), ErrVerification},
+		{"missing key", verifyErr(t, &ZygosityProof{}, filepath.Join(dir, "none.vk"), out), ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s: err = %v, want kind %v", tt.name, tt.err, tt.want)
+		}
+	}
+
+	// Wrapping keeps the message, and the first kind given wins.
+	err := withKind(ErrProving, fmt.Errorf("proving: %w", withKind(ErrNotFound, errors.New("no call"))))
+	if ErrorKind(err) != ErrNotFound || err.Error() != "proving: no call" {
+		t.Errorf("withKind = %q of kind %v", err, ErrorKind(err))
+	}
+	if withKind(ErrProving, nil) != nil || ErrorKind(errors.New("plain")) != nil {
+		t.Errorf("nil and unclassified errors should have no kind")
+	}
+}
+
+func verifyErr(t *testing.T, p Proof, verifyingKeyPath, proofPath string) error {
+	_, err := p.Verify(t.Context(), verifyingKeyPath, proofPath)
+	return err
+}
@@ -0,0 +1,125 @@
+package proofs
+
+import (
+	"math/bits"
+	"runtime"
+	"time"
+)
+
+// Estimate is what generating a proof is expected to take, from its
+// compiled circuit alone. Sizes are about right; memory and times are
+// extrapolated from bench runs and only fit within a factor of two or so.
+type Estimate struct {
+	Type         string
+	Backend      BackendName
+	Curve        Curve
+	Constraints  int
+	PublicInputs int
+	SecretInputs int
+	// ProvingKeySize is the serialized proving key in bytes.
+	ProvingKeySize int64
+	// Memory is the peak heap of setup and proving in bytes.
+	Memory uint64
+	// SetupTime and ProveTime are over CPUs cores; setup is skipped when
+	// a proving key is given.
+	SetupTime time.Duration
+	ProveTime time.Duration
+	CPUs      int
+}
+
+// pointSizes are the compressed sizes of a curve's G1 and G2 points in
+// bytes, as keys are serialized.
+var pointSizes = map[Curve][2]int64{
+	CurveBN254:    {32, 64},
+	CurveBLS12381: {48, 96},
+	CurveBLS12377: {48, 96},
+	CurveBW6761:   {96, 96},
+}
+
+// Per-constraint costs on one core, measured with bench over BN254, and how
+// much slower each curve's group operations make them.
+var (
+	setupCost = map[BackendName]time.Duration{
+		BackendGroth16: 700 * time.Microsecond,
+		BackendPlonk:   1400 * time.Microsecond,
+	}
+	proveCost = map[BackendName]time.Duration{
+		BackendGroth16: 75 * time.Microsecond,
+		BackendPlonk:   220 * time.Microsecond,
+	}
+	curveCost = map[Curve]float64{
+		CurveBN254:    1,
+		CurveBLS12381: 1.9,
+		CurveBLS12377: 1.9,
+		CurveBW6761:   5.6,
+	}
+)
+
+// baseMemory and constraintMemory model the peak heap of setting up and
+// proving a circuit, which varies little between curves.
+const (
+	baseMemory       = 2 << 20
+	constraintMemory = 2560
+)
+
+// EstimateResources compiles the circuit p would prove, as its
+// configuration and settings shape it, and estimates the proving key size,
+// memory and time generating it takes, without reading a VCF, running
+// setup or proving. The compiled circuit is cached as Generate caches it.
+func EstimateResources(p Proof) (*Estimate, error) {
+	proofType, circuit, err := proofCircuit(p)
+	if err != nil {
+		return nil, err
+	}
+	s := *proofSettings(p)
+	if err := s.checkCurve(proofType); err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+	if s.enveloped() {
+		circuit = newEnvelopeCircuit(circuit, proofType, s)
+	}
+	backend, err := s.Backend.backend()
+	if err != nil {
+		return nil, err
+	}
+	cs, err := s.compileCircuit(backend, circuit)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Estimate{
+		Type:         proofType,
+		Backend:      s.Backend,
+		Curve:        s.Curve,
+		Constraints:  cs.GetNbConstraints(),
+		PublicInputs: cs.GetNbPublicVariables(),
+		SecretInputs: cs.GetNbSecretVariables(),
+		CPUs:         runtime.NumCPU(),
+	}
+	if e.Backend == "" {
+		e.Backend = BackendGroth16
+	}
+	if e.Backend == BackendGroth16 {
+		// R1CS counts the constant one wire as public.
+		e.PublicInputs--
+	}
+	if e.Curve == "" {
+		e.Curve = CurveBN254
+	}
+
+	// Groth16 keys hold three G1 points and a G2 point per wire and a G1
+	// point per evaluation domain element; PLONK keys hold the SRS in
+	// canonical and Lagrange form, a G1 point per domain element each.
+	wires := int64(cs.GetNbInternalVariables() + cs.GetNbSecretVariables() + cs.GetNbPublicVariables())
+	domain := int64(1) << bits.Len64(uint64(e.Constraints+e.PublicInputs-1))
+	g1, g2 := pointSizes[e.Curve][0], pointSizes[e.Curve][1]
+	if e.Backend == BackendPlonk {
+		e.ProvingKeySize = 2 * (domain + 3) * g1
+	} else {
+		e.ProvingKeySize = (3*wires+domain)*g1 + wires*g2
+	}
+
+	e.SetupTime, e.ProveTime = stageTimes(e.Backend, e.Curve, e.Constraints)
+	e.Memory = baseMemory + uint64(constraintMemory*e.Constraints)
+	return e, nil
+}
@@ -0,0 +1,46 @@
+package proofs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEstimateResources(t *testing.T) {
+	for _, curve := range []Curve{CurveBN254, CurveBLS12381} {
+		proof := &ZygosityProof{Settings: Settings{Curve: curve}, Locus: "15:28365618"}
+		e, err := EstimateResources(proof)
+		if err != nil {
+			t.Fatalf("EstimateResources over %s: %v", curve, err)
+		}
+		if e.Type != "zygosity" || e.PublicInputs != 4 || e.Constraints == 0 || e.ProveTime == 0 || e.Memory == 0 {
+			t.Errorf("estimate over %s = %+v", curve, e)
+		}
+
+		// The proving key estimate is within a quarter of the real key.
+		keyPath := filepath.Join(t.TempDir(), "zygosity")
+		if err := SetupKeys(proof, keyPath, SetupOptions{}); err != nil {
+			t.Fatalf("SetupKeys: %v", err)
+		}
+		info, err := os.Stat(keyPath + ".pk")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ratio := float64(e.ProvingKeySize) / float64(info.Size()); ratio < 0.75 || ratio > 1.25 {
+			t.Errorf("proving key estimate over %s = %d bytes, key has %d", curve, e.ProvingKeySize, info.Size())
+		}
+	}
+
+	// A nonce is bound as another public input.
+	e, err := EstimateResources(&ZygosityProof{Settings: Settings{Nonce: "n"}, Locus: "15:28365618"})
+	if err != nil || e.PublicInputs != 5 {
+		t.Errorf("estimate with a nonce = %+v, %v", e, err)
+	}
+	if _, err := EstimateResources(&VariantMembershipProof{Settings: Settings{Curve: CurveBLS12381}}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("membership over bls12-381 = %v, want ErrInvalidInput", err)
+	}
+	if _, err := EstimateResources(&CohortProof{}); !errors.Is(err, ErrNoCircuit) {
+		t.Errorf("cohort estimate = %v, want ErrNoCircuit", err)
+	}
+}
@@ -0,0 +1,30 @@
+package proofs_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+func Example() {
+	// The verifier sends a fresh challenge; the holder proves their
+	// genotype at the locus bound to it.
+	ctx := context.Background()
+	challenge := "7f3a9c"
+	err := proofs.Generate(ctx, "zygosity", "genome.vcf", "out/zygosity_proof.bin",
+		proofs.WithPosition("15:28365618"), proofs.WithNonce(challenge))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The verifier checks the proof, that it is for the locus they asked
+	// about and that it answers their challenge.
+	res, err := proofs.Verify(ctx, "out/zygosity_proof.bin",
+		proofs.WithPosition("15:28365618"), proofs.WithNonce(challenge))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("verified:", res.Valid, "with key", res.KeyFingerprint)
+}
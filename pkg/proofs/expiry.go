@@ -0,0 +1,51 @@
+package proofs
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockSkew is how far in the future CheckExpiry accepts a proof's
+// issuance time, allowing for the prover's clock running ahead.
+const ClockSkew = 5 * time.Minute
+
+// timestamped reports whether proofs generated with the settings bind their
+// issuance time.
+func (s Settings) timestamped() bool {
+	return s.Timestamp || s.ValidFor > 0
+}
+
+// stamp fixes the issuance time bound into the next proof generated with
+// the settings at the current second, as it is bound.
+func (s *Settings) stamp() {
+	s.issuedAt = time.Time{}
+	if s.timestamped() {
+		s.issuedAt = time.Now().UTC().Truncate(time.Second)
+	}
+}
+
+// CheckExpiry reports whether the proof at proofPath is still valid at now,
+// going by the expiry time bound into it rather than its metadata alone. A
+// proof without an expiry time never expires; one issued further than
+// ClockSkew after now is rejected too. The proof itself must still be
+// verified.
+func CheckExpiry(proofPath string, now time.Time) error {
+	env, err := readEnvelope(proofPath)
+	if err != nil {
+		return err
+	}
+	if env.Issued != nil {
+		issued := time.Unix(env.Issued.Int64(), 0).UTC()
+		if issued.After(now.Add(ClockSkew)) {
+			return withKind(ErrVerification, fmt.Errorf("proof was issued in the future, at %s", issued.Format(time.RFC3339)))
+		}
+	}
+	if env.Expires == nil {
+		return nil
+	}
+	expires := time.Unix(env.Expires.Int64(), 0).UTC()
+	if !now.Before(expires) {
+		return withKind(ErrVerification, fmt.Errorf("proof expired at %s", expires.Format(time.RFC3339)))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package proofs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func TestExpiringProof(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "zygosity_proof.bin")
+
+	proof := &ZygosityProof{Settings: Settings{ValidFor: 24 * time.Hour}, Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	meta, err := ReadMetadata(out)
+	if err != nil {
+		t.Fatalf("ReadMetadata: %v", err)
+	}
+	if meta.IssuedAt == nil || meta.ExpiresAt == nil || meta.ExpiresAt.Sub(*meta.IssuedAt) != 24*time.Hour {
+		t.Fatalf("metadata issued %v, expires %v; want a day apart", meta.IssuedAt, meta.ExpiresAt)
+	}
+	summary, err := InspectProof(out)
+	if err != nil {
+		t.Fatalf("InspectProof: %v", err)
+	}
+	if summary.Issued.Int64() != meta.IssuedAt.Unix() || summary.Expires.Int64() != meta.ExpiresAt.Unix() || len(summary.Inputs) != 4 {
+		t.Errorf("InspectProof = issued %v, expires %v, %d inputs", summary.Issued, summary.Expires, len(summary.Inputs))
+	}
+
+	if err := CheckExpiry(out, time.Now()); err != nil {
+		t.Errorf("CheckExpiry now: %v", err)
+	}
+	if err := CheckExpiry(out, meta.ExpiresAt.Add(time.Second)); ErrorKind(err) != ErrVerification {
+		t.Errorf("CheckExpiry after expiry = %v; want a verification error", err)
+	}
+	if err := CheckExpiry(out, meta.IssuedAt.Add(-time.Hour)); ErrorKind(err) != ErrVerification {
+		t.Errorf("CheckExpiry an hour before issuance = %v; want a verification error", err)
+	}
+
+	// Extending the expiry in the public witness breaks the proof.
+	p, w, err := readProofFile(out)
+	if err != nil {
+		t.Fatalf("readProofFile: %v", err)
+	}
+	vector := w.Vector().(fr.Vector)
+	vector[len(vector)-1].SetInt64(meta.ExpiresAt.Add(365 * 24 * time.Hour).Unix())
+	tampered := filepath.Join(dir, "tampered.bin")
+	if err := writeProofFile(tampered, nil, p, w); err != nil {
+		t.Fatalf("writeProofFile: %v", err)
+	}
+	if _, err := (Settings{}).verifyProof(t.Context(), out+".vk", tampered); err == nil {
+		t.Errorf("proof should not verify with an extended expiry")
+	}
+
+	// Removing the metadata hides no expiry: the header records it, and a
+	// file rewritten without that record has more inputs than its circuit.
+	if err := os.Remove(out + MetadataSuffix); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckExpiry(out, meta.ExpiresAt.Add(time.Second)); ErrorKind(err) != ErrVerification {
+		t.Errorf("CheckExpiry after expiry without metadata = %v; want a verification error", err)
+	}
+	p, w, err = readProofFile(out)
+	if err != nil {
+		t.Fatalf("readProofFile: %v", err)
+	}
+	for _, header := range []*ProofHeader{nil, {Format: proofFormat, Type: "zygosity"}} {
+		if err := writeProofFile(tampered, header, p, w); err != nil {
+			t.Fatalf("writeProofFile: %v", err)
+		}
+		if err := CheckExpiry(tampered, meta.ExpiresAt.Add(time.Second)); err == nil {
+			t.Errorf("CheckExpiry of a proof with header %+v and no metadata passed", header)
+		}
+	}
+}
+
+func TestTimestampWithoutExpiry(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	out := filepath.Join(t.TempDir(), "zygosity_proof.bin")
+	proof := &ZygosityProof{Settings: Settings{Timestamp: true}, Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	summary, err := InspectProof(out)
+	if err != nil {
+		t.Fatalf("InspectProof: %v", err)
+	}
+	if summary.Issued == nil || summary.Expires != nil {
+		t.Errorf("InspectProof = issued %v, expires %v; want only an issuance time", summary.Issued, summary.Expires)
+	}
+	if err := CheckExpiry(out, time.Now().Add(10*365*24*time.Hour)); err != nil {
+		t.Errorf("proof without an expiry should not expire: %v", err)
+	}
+
+	negative := &ZygosityProof{Settings: Settings{ValidFor: -time.Hour}, Locus: "15:28365618"}
+	if err := negative.Generate(t.Context(), vcf, "", out); ErrorKind(err) != ErrInvalidInput {
+		t.Errorf("Generate with a negative validity = %v; want invalid input", err)
+	}
+}
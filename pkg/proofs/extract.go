@@ -0,0 +1,161 @@
+package proofs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brentp/vcfgo"
+)
+
+// ExtractOptions selects the records ExtractVCF keeps.
+type ExtractOptions struct {
+	// Variants are kept when the VCF has a record for them, found as
+	// extractGenotypes finds it: by rsID, or at their locus whatever the
+	// alleles, so a later proof still sees and reports a mismatch.
+	Variants []VariantSpec
+	// Regions keep every record overlapping them, for the proofs that read
+	// a whole region, such as sv and region proofs.
+	Regions []GenomicRegion
+	// Limits bound the reading of the input VCF; MaxRecords is not applied.
+	Limits ReadLimits
+}
+
+// ExtractStats counts the records ExtractVCF read and kept, and gives the
+// dataset digest of the extract.
+type ExtractStats struct {
+	Records int
+	Kept    int
+	Digest  string
+}
+
+// BuiltinVariants returns the variants the built-in proof types read: the
+// eye color, IrisPlex, blood type and BRCA1 panels, and the variants of
+// the pharmacogenes and compound-het genes. Repeat loci are found by
+// ExpansionHunter IDs rather than position, so an extract for repeat
+// proofs needs a region around the locus.
+func BuiltinVariants() []VariantSpec {
+	variants := slices.Concat(EyeColorTrait.SNPs, BloodTypeTrait.SNPs, brca1PathogenicVariants[:])
+	for _, snp := range IrisPlexSNPs {
+		variants = append(variants, snp.Spec())
+	}
+	for _, gene := range slices.Sorted(maps.Keys(Pharmacogenes)) {
+		for _, v := range Pharmacogenes[gene].Variants {
+			variants = append(variants, v.Spec())
+		}
+	}
+	for _, gene := range slices.Sorted(maps.Keys(PathogenicVariants)) {
+		for _, v := range PathogenicVariants[gene] {
+			variants = append(variants, v.Spec())
+		}
+	}
+	return variants
+}
+
+// ExtractVCF copies the header of the VCF at vcfPath and the records opts
+// selects to a new VCF at outputPath, so later proofs can read a few
+// hundred records instead of the whole genome. Kept lines are copied
+// unchanged, in their original order, and every sample column is kept.
+// The input may be compressed with gzip or bgzip; the extract is plain.
+// A DatasetManifest with the extract's digest is written beside it, at
+// outputPath with ManifestSuffix appended, for proofs to be tied to.
+func ExtractVCF(vcfPath string, outputPath string, opts ExtractOptions) (ExtractStats, error) {
+	var stats ExtractStats
+	if len(opts.Variants) == 0 && len(opts.Regions) == 0 {
+		return stats, fmt.Errorf("no variants or regions to extract")
+	}
+
+	in, err := OpenVCFStream(vcfPath)
+	if err != nil {
+		return stats, err
+	}
+	defer in.Close()
+
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return stats, fmt.Errorf("creating VCF: %w", err)
+	}
+	defer out.Close()
+
+	lookup := lookupSpecs(opts.Variants)
+	digest := newRecordDigest()
+	sawHeader := false
+	keep := func(line []byte) bool {
+		if len(line) == 0 || line[0] == '#' {
+			sawHeader = sawHeader || bytes.HasPrefix(line, []byte("#CHROM"))
+			return true
+		}
+		stats.Records++
+		if extractsLine(line, lookup, opts.Regions) {
+			stats.Kept++
+			digest.add(line)
+			return true
+		}
+		return false
+	}
+
+	stream := &lineLimiter{r: in, maxLine: opts.Limits.MaxLineBytes, keep: keep}
+	w := bufio.NewWriter(out)
+	if _, err := io.Copy(w, stream); err != nil {
+		return stats, fmt.Errorf("reading VCF: %w", err)
+	}
+	if stream.err != nil {
+		return stats, stream.err
+	}
+	if !sawHeader {
+		return stats, fmt.Errorf("%s has no #CHROM header line", vcfPath)
+	}
+	if err := w.Flush(); err != nil {
+		return stats, fmt.Errorf("writing VCF: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return stats, fmt.Errorf("writing VCF: %w", err)
+	}
+
+	stats.Digest = digest.String()
+	return stats, WriteManifest(outputPath+ManifestSuffix, DatasetManifest{
+		Source:    filepath.Base(vcfPath),
+		CreatedAt: time.Now().UTC(),
+		Records:   stats.Kept,
+		Digest:    stats.Digest,
+	})
+}
+
+// extractsLine reports whether a VCF data line is a record of one of the
+// lookup's variants or overlaps one of regions. Only the leading columns
+// are parsed; lines too short to parse are dropped.
+func extractsLine(line []byte, lookup *variantLookup, regions []GenomicRegion) bool {
+	for _, r := range regions {
+		if r.overlapsLine(line) {
+			return true
+		}
+	}
+
+	fields := strings.SplitN(strings.TrimRight(string(line), "\r\n"), "\t", 6)
+	if len(fields) < 5 {
+		return false
+	}
+	pos, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return false
+	}
+	variant := &vcfgo.Variant{
+		Chromosome: fields[0],
+		Pos:        pos,
+		Id_:        fields[2],
+		Reference:  fields[3],
+		Alternate:  strings.Split(fields[4], ","),
+	}
+	return slices.ContainsFunc(lookup.match(variant), func(i int) bool {
+		rank, _ := lookup.rank(i, variant, variant.Reference, variant.Alternate)
+		return rank != matchNone
+	})
+}
@@ -0,0 +1,65 @@
+package proofs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractVCF(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+chr1	1000	.	A	G	60	PASS	.	GT	0/1
+chr5	33951693	.	C	G	60	PASS	.	GT	1/1
+chr15	28356859	rs12913832	A	G	60	PASS	.	GT	0/1
+chr17	41276043	.	GACT	GA,G	60	PASS	.	GT	0/1
+chr17	41276044	.	A	T	60	PASS	.	GT	0/0
+chr22	500	.	T	<DEL>	60	PASS	SVTYPE=DEL;END=900	GT	0/1
+chr22	1000	.	C	T	60	PASS	.	GT	0/1
+`)
+	out := filepath.Join(t.TempDir(), "extract.vcf")
+	stats, err := ExtractVCF(vcf, out, ExtractOptions{
+		Variants: []VariantSpec{
+			{Chromosome: "15", Position: HERC2Pos, Ref: "A", Alt: "G", RSID: "rs12913832"},
+			{Chromosome: "5", Position: 33951693, Ref: "C", Alt: "G"},
+			{Chromosome: "17", Position: 41276044, Ref: "ACT", Alt: "A"},
+		},
+		Regions: []GenomicRegion{{Chromosome: "22", Start: 800, End: 850}},
+	})
+	if err != nil {
+		t.Fatalf("ExtractVCF: %v", err)
+	}
+	if stats.Records != 7 || stats.Kept != 5 {
+		t.Errorf("stats = %+v, want 5 of 7 records kept", stats)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var positions []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			positions = append(positions, strings.Join(strings.Fields(line)[:2], ":"))
+		}
+	}
+	want := "chr5:33951693 chr15:28356859 chr17:41276043 chr17:41276044 chr22:500"
+	if got := strings.Join(positions, " "); got != want {
+		t.Errorf("kept records %s, want %s", got, want)
+	}
+
+	// Proofs read the same genotypes from the extract.
+	genotypes, err := extractGenotypes(out, Settings{}, []VariantSpec{{Chromosome: "17", Position: 41276044, Ref: "ACT", Alt: "A"}})
+	if err != nil {
+		t.Fatalf("extractGenotypes: %v", err)
+	}
+	if g := genotypes[Locus{"17", 41276044}]; g.Carries("A") != 1 {
+		t.Errorf("extracted genotype %+v does not carry the deletion", g)
+	}
+
+	if _, err := ExtractVCF(vcf, out, ExtractOptions{}); err == nil {
+		t.Error("ExtractVCF without variants or regions succeeded")
+	}
+}
@@ -0,0 +1,28 @@
+package proofs
+
+import "context"
+
+// EyeColorTrait predicts eye color from rs12913832 (HERC2) and rs16891982
+// (SLC45A2). The G allele at each is associated with lighter eyes; blue
+// needs rs12913832 GG and at least one light SLC45A2 allele.
+var EyeColorTrait = CompositeTrait{
+	Name: "eye color",
+	SNPs: []VariantSpec{
+		{Chromosome: "15", Position: HERC2Pos, Ref: "A", Alt: "G", RSID: "rs12913832"},
+		{Chromosome: "5", Position: 33951693, Ref: "C", Alt: "G", RSID: "rs16891982"},
+	},
+	// Rows are rs16891982 dosage 0, 1, 2; columns rs12913832 dosage 0, 1, 2.
+	Table: []int{
+		EyeColorBrown, EyeColorBrown, EyeColorIntermediate,
+		EyeColorBrown, EyeColorBrown, EyeColorBlue,
+		EyeColorBrown, EyeColorIntermediate, EyeColorBlue,
+	},
+}
+
+func (p *EyeColorProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	return generateTrait(ctx, eyeColorDefinition, p.Settings, vcfPath, provingKeyPath, outputPath)
+}
+
+func (p *EyeColorProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	return p.verifyTrait(ctx, eyeColorDefinition, verifyingKeyPath, proofPath)
+}
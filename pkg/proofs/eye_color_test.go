@@ -0,0 +1,80 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestEyeColorTrait(t *testing.T) {
+	if err := EyeColorTrait.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	tests := []struct {
+		herc2, slc45a2 int
+		want           int
+	}{
+		{0, 0, EyeColorBrown},
+		{0, 2, EyeColorBrown},
+		{1, 2, EyeColorIntermediate},
+		{2, 0, EyeColorIntermediate},
+		{2, 1, EyeColorBlue},
+		{2, 2, EyeColorBlue},
+	}
+	field := ecc.BN254.ScalarField()
+	for _, tt := range tests {
+		got, err := EyeColorTrait.Phenotype([]int{tt.herc2, tt.slc45a2})
+		if err != nil {
+			t.Fatalf("Phenotype: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("HERC2 %d, SLC45A2 %d: color = %s, want %s", tt.herc2, tt.slc45a2, EyeColorName(got), EyeColorName(tt.want))
+		}
+
+		for color := EyeColorBrown; color <= EyeColorBlue; color++ {
+			err := test.IsSolved(NewCompositeTraitCircuit(2), compositeAssignment(EyeColorTrait, color, tt.herc2, tt.slc45a2), field)
+			if color == tt.want && err != nil {
+				t.Errorf("HERC2 %d, SLC45A2 %d should prove %s: %v", tt.herc2, tt.slc45a2, EyeColorName(color), err)
+			}
+			if color != tt.want && err == nil {
+				t.Errorf("HERC2 %d, SLC45A2 %d should not prove %s", tt.herc2, tt.slc45a2, EyeColorName(color))
+			}
+		}
+	}
+}
+
+func TestEyeColorProof(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+5	33951693	.	C	G	60	PASS	.	GT	0/1
+15	28365618	.	A	G	60	PASS	.	GT	1/1
+`)
+	out := filepath.Join(t.TempDir(), "eyecolor_proof.bin")
+
+	proof := &EyeColorProof{}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if verified, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !verified {
+		t.Fatalf("Verify = %v, %v", verified, err)
+	}
+
+	color, err := Settings{}.verifyCompositeTrait(t.Context(), EyeColorTrait, out+".vk", out)
+	if err != nil {
+		t.Fatalf("verifyCompositeTrait: %v", err)
+	}
+	if color != EyeColorBlue {
+		t.Errorf("proven color = %s, want blue", EyeColorName(color))
+	}
+
+	// A proof is only meaningful against the table it was made with.
+	other := EyeColorTrait
+	other.Table = append([]int{EyeColorBlue}, EyeColorTrait.Table[1:]...)
+	if _, err := (Settings{}).verifyCompositeTrait(t.Context(), other, out+".vk", out); err == nil {
+		t.Errorf("proof verified against a different decision table")
+	}
+}
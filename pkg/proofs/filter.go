@@ -0,0 +1,127 @@
+package proofs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/brentp/vcfgo"
+)
+
+// CallFilter excludes low-confidence records from proof witnesses. A record
+// it rejects is read as if it were absent, so the missing genotype policy
+// decides what becomes of its locus. The zero value accepts every record.
+type CallFilter struct {
+	// RequirePass rejects records whose FILTER column is not PASS,
+	// including records with "." there, whose filters were never applied.
+	RequirePass bool `json:"require_pass,omitempty"`
+	// MinDepth is the minimum read depth, taken from the sample's FORMAT/DP
+	// or else the record's INFO/DP. Records with neither are rejected when
+	// it is set.
+	MinDepth int `json:"min_depth,omitempty"`
+	// MinAlleleFrequency is the minimum frequency of each ALT the sample
+	// carries, taken from its FORMAT/AF, as somatic callers report it, or
+	// else the record's INFO/AF. Calls without an ALT are not checked.
+	MinAlleleFrequency float64 `json:"min_allele_frequency,omitempty"`
+}
+
+// Validate reports whether the filter's thresholds are in range.
+func (f CallFilter) Validate() error {
+	if f.MinDepth < 0 {
+		return fmt.Errorf("minimum depth must not be negative, got %d", f.MinDepth)
+	}
+	if f.MinAlleleFrequency < 0 || f.MinAlleleFrequency > 1 {
+		return fmt.Errorf("minimum allele frequency must be in [0, 1], got %g", f.MinAlleleFrequency)
+	}
+	return nil
+}
+
+// IsZero reports whether the filter accepts every record.
+func (f CallFilter) IsZero() bool {
+	return f == CallFilter{}
+}
+
+func (f CallFilter) String() string {
+	var parts []string
+	if f.RequirePass {
+		parts = append(parts, "FILTER=PASS")
+	}
+	if f.MinDepth > 0 {
+		parts = append(parts, fmt.Sprintf("DP>=%d", f.MinDepth))
+	}
+	if f.MinAlleleFrequency > 0 {
+		parts = append(parts, fmt.Sprintf("AF>=%g", f.MinAlleleFrequency))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// accepts reports whether the call of the sample with the given index at
+// variant may be used in a witness.
+func (f CallFilter) accepts(variant *vcfgo.Variant, sampleIndex int) bool {
+	if f.IsZero() {
+		return true
+	}
+	if f.RequirePass && variant.Filter != "PASS" {
+		return false
+	}
+
+	var sample *vcfgo.SampleGenotype
+	if sampleIndex < len(variant.Samples) {
+		sample = variant.Samples[sampleIndex]
+	}
+	if f.MinDepth > 0 && callDepth(variant, sample) < f.MinDepth {
+		return false
+	}
+	if f.MinAlleleFrequency > 0 && sample != nil {
+		frequencies := alleleFrequencies(variant, sample)
+		for _, allele := range sample.GT {
+			if allele <= 0 {
+				continue
+			}
+			if allele > len(frequencies) || frequencies[allele-1] < f.MinAlleleFrequency {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// callDepth returns the sample's FORMAT/DP, or else the record's INFO/DP,
+// or -1 when neither is given.
+func callDepth(variant *vcfgo.Variant, sample *vcfgo.SampleGenotype) int {
+	if sample != nil {
+		if dp, ok := sample.Fields["DP"]; ok && dp != "." {
+			return sample.DP
+		}
+	}
+	if dp, ok := infoString(variant, "DP"); ok {
+		if n, err := strconv.Atoi(dp); err == nil {
+			return n
+		}
+	}
+	return -1
+}
+
+// alleleFrequencies returns the frequency of each ALT from the sample's
+// FORMAT/AF or else the record's INFO/AF, with -1 for missing values.
+func alleleFrequencies(variant *vcfgo.Variant, sample *vcfgo.SampleGenotype) []float64 {
+	af, ok := sample.Fields["AF"]
+	if !ok || af == "." {
+		if af, ok = infoString(variant, "AF"); !ok {
+			return nil
+		}
+	}
+
+	var frequencies []float64
+	for _, v := range strings.Split(af, ",") {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			f = -1
+		}
+		frequencies = append(frequencies, f)
+	}
+	return frequencies
+}
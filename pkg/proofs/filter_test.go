@@ -0,0 +1,80 @@
+package proofs
+
+import "testing"
+
+func TestCallFilter_Accepts(t *testing.T) {
+	variants := readTestVariants(t, `##fileformat=VCFv4.2
+##INFO=<ID=DP,Number=1,Type=Integer,Description="Depth">
+##INFO=<ID=AF,Number=A,Type=Float,Description="Allele frequency">
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=DP,Number=1,Type=Integer,Description="Depth">
+##FORMAT=<ID=AF,Number=A,Type=Float,Description="Allele fraction">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	SAMPLE1
+22	100	.	A	G	60	PASS	DP=40	GT:DP:AF	0/1:35:0.45
+22	200	.	A	G	60	LowQual	DP=40	GT:DP:AF	0/1:35:0.45
+22	300	.	A	G	60	.	DP=8	GT	0/1
+22	400	.	A	G,T	60	PASS	DP=50;AF=0.5,0.05	GT	1/2
+22	500	.	A	G	60	PASS	.	GT:DP:AF	0/0:30:.
+`)
+
+	tests := []struct {
+		name   string
+		filter CallFilter
+		want   []bool
+	}{
+		{"none", CallFilter{}, []bool{true, true, true, true, true}},
+		{"pass", CallFilter{RequirePass: true}, []bool{true, false, false, true, true}},
+		// FORMAT/DP wins over INFO/DP; INFO/DP is the fallback.
+		{"depth", CallFilter{MinDepth: 30}, []bool{true, true, false, true, true}},
+		// Every carried ALT must meet the minimum; hom-ref calls are not checked.
+		{"allele frequency", CallFilter{MinAlleleFrequency: 0.2}, []bool{true, true, false, false, true}},
+	}
+	for _, tt := range tests {
+		for i, variant := range variants {
+			if got := tt.filter.accepts(variant, 0); got != tt.want[i] {
+				t.Errorf("%s: variant at %d accepted = %v, want %v", tt.name, variant.Pos, got, tt.want[i])
+			}
+		}
+	}
+
+	for _, bad := range []CallFilter{{MinDepth: -1}, {MinAlleleFrequency: 1.5}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("%+v: Validate succeeded", bad)
+		}
+	}
+}
+
+func TestExtractGenotypesCallFilter(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=DP,Number=1,Type=Integer,Description="Depth">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+1	100	.	A	G	60	PASS	.	GT:DP	0/1:30
+1	200	.	C	T	60	LowQual	.	GT:DP	1/1:30
+1	300	.	G	A	60	PASS	.	GT:DP	0/1:4
+`)
+	specs := []VariantSpec{
+		{Chromosome: "1", Position: 100, Ref: "A", Alt: "G"},
+		{Chromosome: "1", Position: 200, Ref: "C", Alt: "T"},
+		{Chromosome: "1", Position: 300, Ref: "G", Alt: "A"},
+	}
+	s := Settings{Filter: CallFilter{RequirePass: true, MinDepth: 10}}
+	genotypes, err := extractGenotypes(vcf, s, specs)
+	if err != nil {
+		t.Fatalf("extractGenotypes: %v", err)
+	}
+	if _, ok := genotypes[specs[0].Locus()]; !ok {
+		t.Errorf("%s: passing call dropped", specs[0])
+	}
+	for _, spec := range specs[1:] {
+		if g, ok := genotypes[spec.Locus()]; ok {
+			t.Errorf("%s: filtered call read as %+v", spec, g)
+		}
+	}
+
+	// A filtered call cannot stand in for coverage.
+	s.Missing = MissingRequireCoverage
+	if _, err := extractGenotypes(vcf, s, specs); err == nil {
+		t.Error("filtered calls met require-coverage")
+	}
+}
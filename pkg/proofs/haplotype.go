@@ -0,0 +1,400 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// HaplotypeTrees commits to the alt alleles on each of the first sample's
+// two haplotypes. Leaves are variant tree leaves whose variant ID is bound
+// to the call's phase set, since haplotype labels are only consistent within
+// one phase set.
+type HaplotypeTrees [2]*VariantTree
+
+// Root commits to both haplotype trees.
+func (t HaplotypeTrees) Root() *big.Int {
+	return mimcHash(t[0].Root(), t[1].Root())
+}
+
+// phaseSetID returns the numeric FORMAT/PS of a phased call. Calls without a
+// phase set share the whole-chromosome phase set 0.
+func phaseSetID(g Genotype) (uint64, error) {
+	if g.PhaseSet == "" || g.PhaseSet == "." {
+		return 0, nil
+	}
+	ps, err := strconv.ParseUint(g.PhaseSet, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid phase set %q: %w", g.PhaseSet, err)
+	}
+	return ps, nil
+}
+
+// newPhasedLeaf returns the haplotype tree leaf of v in phase set ps.
+func newPhasedLeaf(v VariantSpec, ps uint64) (variantLeaf, error) {
+	leaf, err := newVariantLeaf(v)
+	if err != nil {
+		return variantLeaf{}, err
+	}
+	leaf.VariantID = mimcHash(leaf.VariantID, new(big.Int).SetUint64(ps))
+	return leaf, nil
+}
+
+// BuildHaplotypeTrees commits to the alt alleles on each haplotype of the
+// first sample. Homozygous alt calls are on both haplotypes; heterozygous
+// calls are placed by phase and skipped when unphased. Records on
+// non-primary contigs and non-diploid calls are skipped too.
+func BuildHaplotypeTrees(vcfPath string, depth int, imputation ImputationPolicy, limits ReadLimits) (HaplotypeTrees, error) {
+	return buildHaplotypeTrees(vcfPath, "", depth, imputation, limits)
+}
+
+// buildHaplotypeTrees is BuildHaplotypeTrees for the sample named sample,
+// or the first sample when it is empty.
+func buildHaplotypeTrees(vcfPath string, sample string, depth int, imputation ImputationPolicy, limits ReadLimits) (HaplotypeTrees, error) {
+	var trees HaplotypeTrees
+
+	rdr, err := openVCF(vcfPath, limits, nil)
+	if err != nil {
+		return trees, err
+	}
+	defer rdr.Close()
+	sampleIndex, err := sampleColumn(rdr.Header, sample)
+	if err != nil {
+		return trees, err
+	}
+
+	var leaves [2][]variantLeaf
+	unphased, skipped := 0, 0
+	for {
+		variant, err := rdr.next()
+		if err != nil {
+			return trees, err
+		}
+		if variant == nil {
+			break
+		}
+		if !imputation.acceptsSample(variant, sampleIndex) {
+			continue
+		}
+		if len(variant.Samples) <= sampleIndex || variant.Samples[sampleIndex] == nil {
+			return trees, fmt.Errorf("no sample genotype at %s:%d", variant.Chromosome, variant.Pos)
+		}
+
+		call := variant.Samples[sampleIndex]
+		genotype := Genotype{
+			Ref:      variant.Reference,
+			Alt:      variant.Alternate,
+			Alleles:  call.GT,
+			Phased:   call.Phased,
+			PhaseSet: call.Fields["PS"],
+		}
+		if len(genotype.Alleles) != 2 {
+			skipped++
+			continue
+		}
+
+		for i, alt := range genotype.Alt {
+			onFirst, onSecond := genotype.Alleles[0] == i+1, genotype.Alleles[1] == i+1
+			if !onFirst && !onSecond {
+				continue
+			}
+			if onFirst != onSecond && !genotype.Phased {
+				unphased++
+				continue
+			}
+
+			ps, err := phaseSetID(genotype)
+			if err != nil {
+				return trees, fmt.Errorf("%s:%d: %w", variant.Chromosome, variant.Pos, err)
+			}
+			leaf, err := newPhasedLeaf(recordSpec(variant, alt), ps)
+			if err != nil {
+				skipped++
+				continue
+			}
+
+			for h, on := range []bool{onFirst, onSecond} {
+				if !on {
+					continue
+				}
+				if leaves[h], err = fitLeaves(append(leaves[h], leaf), depth); err != nil {
+					return trees, err
+				}
+			}
+		}
+	}
+
+	if unphased > 0 {
+		fmt.Printf("Skipped %d unphased heterozygous variants\n", unphased)
+	}
+	if skipped > 0 {
+		fmt.Printf("Skipped %d variants on non-primary contigs or with non-diploid calls\n", skipped)
+	}
+	fmt.Printf("Committing to %d and %d variants on haplotypes 1 and 2\n", len(leaves[0]), len(leaves[1]))
+
+	for h := range trees {
+		if trees[h], err = newVariantTree(leaves[h], depth, HashMiMC); err != nil {
+			return trees, err
+		}
+	}
+	return trees, nil
+}
+
+// CommitHaplotypes builds the haplotype trees of the VCF at vcfPath and
+// returns their combined root as a publishable commitment.
+func CommitHaplotypes(vcfPath string, depth int, imputation ImputationPolicy, limits ReadLimits) (*DatasetCommitment, error) {
+	trees, err := BuildHaplotypeTrees(vcfPath, depth, imputation, limits)
+	if err != nil {
+		return nil, err
+	}
+	return &DatasetCommitment{ProofType: "haplotype", Commitment: trees.Root().String()}, nil
+}
+
+// HaplotypeMembershipCircuit proves that two public variants are carried on
+// the same haplotype (cis) or on opposite haplotypes (trans) of the
+// committed genome, without revealing which haplotype either is on.
+type HaplotypeMembershipCircuit struct {
+	Root       frontend.Variable `gnark:",public"`
+	LocusKeyA  frontend.Variable `gnark:",public"`
+	VariantIDA frontend.Variable `gnark:",public"`
+	LocusKeyB  frontend.Variable `gnark:",public"`
+	VariantIDB frontend.Variable `gnark:",public"`
+	Cis        frontend.Variable `gnark:",public"`
+
+	// Private inputs - the two haplotype roots, the phase set both calls
+	// belong to, and for each variant its haplotype and Merkle path
+	HaplotypeRoots [2]frontend.Variable
+	PhaseSet       frontend.Variable
+	HaplotypeA     frontend.Variable
+	IndexA         frontend.Variable
+	SiblingsA      []frontend.Variable
+	HaplotypeB     frontend.Variable
+	IndexB         frontend.Variable
+	SiblingsB      []frontend.Variable
+}
+
+// NewHaplotypeMembershipCircuit allocates a circuit for haplotype trees of
+// depth.
+func NewHaplotypeMembershipCircuit(depth int) *HaplotypeMembershipCircuit {
+	return &HaplotypeMembershipCircuit{
+		SiblingsA: make([]frontend.Variable, depth),
+		SiblingsB: make([]frontend.Variable, depth),
+	}
+}
+
+func (c *HaplotypeMembershipCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+
+	h.Write(c.HaplotypeRoots[0], c.HaplotypeRoots[1])
+	api.AssertIsEqual(h.Sum(), c.Root)
+
+	// Phase is only meaningful within one chromosome.
+	keyA := api.ToBinary(c.LocusKeyA, 40)
+	keyB := api.ToBinary(c.LocusKeyB, 40)
+	api.AssertIsEqual(api.FromBinary(keyA[32:]...), api.FromBinary(keyB[32:]...))
+
+	api.AssertIsBoolean(c.HaplotypeA)
+	api.AssertIsBoolean(c.HaplotypeB)
+	api.AssertIsBoolean(c.Cis)
+
+	for _, v := range []struct {
+		locusKey, variantID, haplotype, index frontend.Variable
+		siblings                              []frontend.Variable
+	}{
+		{c.LocusKeyA, c.VariantIDA, c.HaplotypeA, c.IndexA, c.SiblingsA},
+		{c.LocusKeyB, c.VariantIDB, c.HaplotypeB, c.IndexB, c.SiblingsB},
+	} {
+		h.Reset()
+		h.Write(v.variantID, c.PhaseSet)
+		phasedID := h.Sum()
+
+		h.Reset()
+		h.Write(v.locusKey, phasedID)
+		leaf := h.Sum()
+
+		root := api.Select(v.haplotype, c.HaplotypeRoots[1], c.HaplotypeRoots[0])
+		api.AssertIsEqual(merkleRoot(api, &h, leaf, v.index, v.siblings), root)
+	}
+
+	api.AssertIsEqual(api.Xor(c.HaplotypeA, c.HaplotypeB), api.Sub(1, c.Cis))
+
+	return nil
+}
+
+func (p *HaplotypeProof) treeDepth() int {
+	if p.TreeDepth == 0 {
+		return DefaultTreeDepth
+	}
+	return p.TreeDepth
+}
+
+// phasedVariant is a variant located on one or both haplotype trees.
+type phasedVariant struct {
+	leaf    variantLeaf
+	indices [2]int
+	on      [2]bool
+}
+
+func (t HaplotypeTrees) locate(v VariantSpec, ps uint64) (phasedVariant, error) {
+	leaf, err := newPhasedLeaf(v, ps)
+	if err != nil {
+		return phasedVariant{}, err
+	}
+
+	located := phasedVariant{leaf: leaf}
+	for h, tree := range t {
+		located.indices[h], located.on[h] = tree.findLeaf(leaf)
+	}
+	if !located.on[0] && !located.on[1] {
+		return located, withKind(ErrNotFound, fmt.Errorf("variant %s is not carried on a phased haplotype", v))
+	}
+	return located, nil
+}
+
+func (p *HaplotypeProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	var variants [2]VariantSpec
+	for i, s := range p.Variants {
+		v, err := ParseVariantSpec(s)
+		if err != nil {
+			return err
+		}
+		variants[i] = v
+	}
+	if variants[0].Chromosome != variants[1].Chromosome {
+		return fmt.Errorf("variants %s and %s are on different chromosomes", variants[0], variants[1])
+	}
+
+	genotypes, err := extractGenotypes(vcfPath, p.Settings, variants[:])
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+	var phaseSets [2]uint64
+	for i, v := range variants {
+		genotype, ok := genotypes[v.Locus()]
+		if !ok {
+			return withKind(ErrNotFound, fmt.Errorf("variant %s is not carried in the VCF", v))
+		}
+		if phaseSets[i], err = phaseSetID(genotype); err != nil {
+			return fmt.Errorf("%s: %w", v, err)
+		}
+	}
+	if phaseSets[0] != phaseSets[1] {
+		return fmt.Errorf("variants %s and %s are in different phase sets", variants[0], variants[1])
+	}
+
+	fmt.Println("Building haplotype trees from VCF...")
+	trees, err := buildHaplotypeTrees(vcfPath, p.Sample, p.treeDepth(), p.Imputation, p.Limits)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	a, err := trees.locate(variants[0], phaseSets[0])
+	if err != nil {
+		return err
+	}
+	b, err := trees.locate(variants[1], phaseSets[1])
+	if err != nil {
+		return err
+	}
+
+	// Prefer a shared haplotype, so a homozygous variant proves cis.
+	haplotypeA, haplotypeB := 0, 1
+	switch {
+	case a.on[0] && b.on[0]:
+		haplotypeA, haplotypeB = 0, 0
+	case a.on[1] && b.on[1]:
+		haplotypeA, haplotypeB = 1, 1
+	case a.on[1]:
+		haplotypeA, haplotypeB = 1, 0
+	}
+	cis := haplotypeA == haplotypeB
+
+	assignment := NewHaplotypeMembershipCircuit(p.treeDepth())
+	assignment.Root = trees.Root()
+	assignment.LocusKeyA = a.leaf.LocusKey
+	assignment.VariantIDA = variants[0].ID()
+	assignment.LocusKeyB = b.leaf.LocusKey
+	assignment.VariantIDB = variants[1].ID()
+	assignment.Cis = boolToInt(cis)
+	assignment.HaplotypeRoots = [2]frontend.Variable{trees[0].Root(), trees[1].Root()}
+	assignment.PhaseSet = phaseSets[0]
+	assignment.HaplotypeA = haplotypeA
+	assignment.IndexA = a.indices[haplotypeA]
+	for i, sibling := range trees[haplotypeA].path(a.indices[haplotypeA]) {
+		assignment.SiblingsA[i] = sibling
+	}
+	assignment.HaplotypeB = haplotypeB
+	assignment.IndexB = b.indices[haplotypeB]
+	for i, sibling := range trees[haplotypeB].path(b.indices[haplotypeB]) {
+		assignment.SiblingsB[i] = sibling
+	}
+
+	if err := p.Settings.prove(ctx, "haplotype", NewHaplotypeMembershipCircuit(p.treeDepth()), assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("haplotype", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	if cis {
+		fmt.Printf("We have proven that %s and %s are in cis (on the same haplotype)\n", variants[0], variants[1])
+	} else {
+		fmt.Printf("We have proven that %s and %s are in trans (on opposite haplotypes)\n", variants[0], variants[1])
+	}
+	fmt.Println("without revealing any other variant.")
+	fmt.Printf("Haplotype commitment: %s\n", trees.Root())
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *HaplotypeProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 6 {
+		return false, withKind(ErrVerification, fmt.Errorf("haplotype proof has %d public inputs, expected 6", len(public)))
+	}
+	names := [2]string{"the variant at " + provenLocus(public[1]), "the variant at " + provenLocus(public[3])}
+	for i, spec := range p.Variants {
+		if spec == "" {
+			continue
+		}
+		variant, err := checkVariantInputs(public, 1+2*i, spec)
+		if err != nil {
+			return false, err
+		}
+		names[i] = variant.String()
+	}
+	if public[5].Sign() != 0 {
+		fmt.Printf("Variants %s and %s are in cis\n", names[0], names[1])
+	} else {
+		fmt.Printf("Variants %s and %s are in trans\n", names[0], names[1])
+	}
+
+	if p.ExpectedCommitment != "" {
+		if err := checkCommitment(proofPath, 0, p.ExpectedCommitment); err != nil {
+			return false, err
+		}
+	}
+	return verified, nil
+}
@@ -0,0 +1,166 @@
+package proofs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+const haplotypeTestVCF = `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=PS,Number=1,Type=Integer,Description="Phase set">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+7	117199644	.	ATCT	A	60	PASS	.	GT:PS	0|1:100
+7	117227832	.	G	T	60	PASS	.	GT:PS	1|0:100
+7	117227865	.	G	A	60	PASS	.	GT:PS	0|1:100
+7	117282620	.	G	A	60	PASS	.	GT:PS	0|1:200
+7	117300000	.	C	T	60	PASS	.	GT:PS	0/1:.
+8	100	.	A	G	60	PASS	.	GT:PS	1|0:100
+`
+
+func TestBuildHaplotypeTrees(t *testing.T) {
+	vcf := writeTempVCF(t, haplotypeTestVCF)
+	trees, err := BuildHaplotypeTrees(vcf, 4, ImputationPolicy{}, ReadLimits{})
+	if err != nil {
+		t.Fatalf("BuildHaplotypeTrees: %v", err)
+	}
+
+	tests := []struct {
+		variant string
+		ps      uint64
+		on      [2]bool
+		wantErr bool
+	}{
+		{"7:117199644:ATCT:A", 100, [2]bool{false, true}, false},
+		{"7:117227832:G:T", 100, [2]bool{true, false}, false},
+		{"7:117282620:G:A", 200, [2]bool{false, true}, false},
+		{"7:117282620:G:A", 100, [2]bool{}, true}, // wrong phase set
+		{"7:117300000:C:T", 0, [2]bool{}, true},   // unphased
+	}
+	for _, tt := range tests {
+		located, err := trees.locate(mustParseVariant(t, tt.variant), tt.ps)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s in phase set %d: located on %v, want error", tt.variant, tt.ps, located.on)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("locate %s: %v", tt.variant, err)
+		}
+		if located.on != tt.on {
+			t.Errorf("%s: on haplotypes %v, want %v", tt.variant, located.on, tt.on)
+		}
+	}
+}
+
+func TestHaplotypeProof(t *testing.T) {
+	vcf := writeTempVCF(t, haplotypeTestVCF)
+	dir := t.TempDir()
+
+	tests := []struct {
+		a, b    string
+		cis     bool
+		wantErr bool
+	}{
+		{"7:117199644:ATCT:A", "7:117227865:G:A", true, false},
+		{"7:117199644:ATCT:A", "7:117227832:G:T", false, false},
+		{"7:117199644:ATCT:A", "7:117282620:G:A", false, true}, // different phase sets
+		{"7:117227832:G:T", "8:100:A:G", false, true},          // different chromosomes
+	}
+	for i, tt := range tests {
+		out := filepath.Join(dir, fmt.Sprintf("haplotype_proof_%d.bin", i))
+		proof := &HaplotypeProof{Variants: [2]string{tt.a, tt.b}, TreeDepth: 4}
+		err := proof.Generate(t.Context(), vcf, "", out)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("case %d: Generate should fail", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case %d: Generate: %v", i, err)
+		}
+
+		commitment, err := CommitHaplotypes(vcf, 4, ImputationPolicy{}, ReadLimits{})
+		if err != nil {
+			t.Fatalf("CommitHaplotypes: %v", err)
+		}
+		proof.ExpectedCommitment = commitment.Commitment
+		if verified, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !verified {
+			t.Fatalf("case %d: Verify = %v, %v", i, verified, err)
+		}
+
+		public, err := readPublicInputs(out)
+		if err != nil {
+			t.Fatalf("readPublicInputs: %v", err)
+		}
+		if got := public[5].Sign() != 0; got != tt.cis {
+			t.Errorf("case %d: cis = %v, want %v", i, got, tt.cis)
+		}
+
+		swapped := &HaplotypeProof{Variants: [2]string{tt.b, tt.a}}
+		if ok, err := swapped.Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+			t.Errorf("case %d: Verify for the variants swapped = %v, %v; want ErrVerification", i, ok, err)
+		}
+		second := &HaplotypeProof{Variants: [2]string{"", tt.b}}
+		if ok, err := second.Verify(t.Context(), out+".vk", out); !ok || err != nil {
+			t.Errorf("case %d: Verify for the second variant only = %v, %v", i, ok, err)
+		}
+	}
+}
+
+func TestHaplotypeMembershipCircuitRejectsFalsePhase(t *testing.T) {
+	vcf := writeTempVCF(t, haplotypeTestVCF)
+	trees, err := BuildHaplotypeTrees(vcf, 4, ImputationPolicy{}, ReadLimits{})
+	if err != nil {
+		t.Fatalf("BuildHaplotypeTrees: %v", err)
+	}
+	a, err := trees.locate(mustParseVariant(t, "7:117199644:ATCT:A"), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := trees.locate(mustParseVariant(t, "7:117227865:G:A"), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assignment := func(cis, haplotypeB int) *HaplotypeMembershipCircuit {
+		c := NewHaplotypeMembershipCircuit(4)
+		c.Root = trees.Root()
+		c.LocusKeyA = a.leaf.LocusKey
+		c.VariantIDA = mustParseVariant(t, "7:117199644:ATCT:A").ID()
+		c.LocusKeyB = b.leaf.LocusKey
+		c.VariantIDB = mustParseVariant(t, "7:117227865:G:A").ID()
+		c.Cis = cis
+		c.HaplotypeRoots[0], c.HaplotypeRoots[1] = trees[0].Root(), trees[1].Root()
+		c.PhaseSet = 100
+		c.HaplotypeA = 1
+		c.IndexA = a.indices[1]
+		for i, s := range trees[1].path(a.indices[1]) {
+			c.SiblingsA[i] = s
+		}
+		c.HaplotypeB = haplotypeB
+		c.IndexB = b.indices[1]
+		for i, s := range trees[1].path(b.indices[1]) {
+			c.SiblingsB[i] = s
+		}
+		return c
+	}
+
+	field := ecc.BN254.ScalarField()
+	if err := test.IsSolved(NewHaplotypeMembershipCircuit(4), assignment(1, 1), field); err != nil {
+		t.Fatalf("cis witness rejected: %v", err)
+	}
+	// Both variants are on haplotype 2; claiming trans needs B on haplotype 1.
+	if err := test.IsSolved(NewHaplotypeMembershipCircuit(4), assignment(0, 1), field); err == nil {
+		t.Errorf("trans claim accepted for variants in cis")
+	}
+	if err := test.IsSolved(NewHaplotypeMembershipCircuit(4), assignment(0, 0), field); err == nil {
+		t.Errorf("variant accepted on a haplotype that does not carry it")
+	}
+}
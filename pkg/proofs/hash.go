@@ -0,0 +1,134 @@
+package proofs
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	nativemimc "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+	gnarkposeidon2 "github.com/consensys/gnark/std/permutation/poseidon2"
+)
+
+// mimcHash computes the BN254 MiMC hash of values outside the circuit. It
+// matches std/hash/mimc writing the same values in the same order.
+func mimcHash(values ...*big.Int) *big.Int {
+	h := nativemimc.NewMiMC()
+	for _, v := range values {
+		var e fr.Element
+		e.SetBigInt(v)
+		b := e.Bytes()
+		h.Write(b[:])
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// HashAlgorithm selects the hash a variant tree commitment is built with.
+// The zero value is MiMC.
+type HashAlgorithm string
+
+const (
+	HashMiMC HashAlgorithm = "mimc"
+	// HashPoseidon2 is the Poseidon2 permutation over BN254 (t=2, d=5, 6
+	// full and 50 partial rounds) in a Merkle-Damgård chain with a
+	// feed-forward compression. It is not circomlib's original Poseidon,
+	// whose constants differ, so roots match only Poseidon2 implementations
+	// with the same parameters.
+	HashPoseidon2 HashAlgorithm = "poseidon2"
+)
+
+// ParseHashAlgorithm parses a hash algorithm name; empty means MiMC.
+func ParseHashAlgorithm(s string) (HashAlgorithm, error) {
+	switch a := HashAlgorithm(strings.ToLower(s)); a {
+	case "", HashMiMC:
+		return HashMiMC, nil
+	case HashPoseidon2:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown hash algorithm %q (expected mimc or poseidon2)", s)
+	}
+}
+
+func (a HashAlgorithm) String() string {
+	if a == "" {
+		return string(HashMiMC)
+	}
+	return string(a)
+}
+
+// sum hashes values outside the circuit, matching newFieldHasher.
+func (a HashAlgorithm) sum(values ...*big.Int) *big.Int {
+	if a == HashPoseidon2 {
+		return poseidon2Hash(values...)
+	}
+	return mimcHash(values...)
+}
+
+// newFieldHasher returns the in-circuit gadget for a.
+func newFieldHasher(api frontend.API, a HashAlgorithm) (hash.FieldHasher, error) {
+	if a == HashPoseidon2 {
+		return &poseidon2Hasher{api: api, perm: gnarkposeidon2.NewHash(2, 5, poseidon2FullRounds, poseidon2PartialRounds, poseidon2Seed, ecc.BN254)}, nil
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+const (
+	poseidon2FullRounds    = 6
+	poseidon2PartialRounds = 50
+	poseidon2Seed          = "Poseidon2 hash for BN254 with t=2, rF=6, rP=50, d=5"
+)
+
+var poseidon2Permutation = poseidon2.NewHash(2, poseidon2FullRounds, poseidon2PartialRounds, poseidon2Seed)
+
+// poseidon2Hash chains values through the Poseidon2 compression from a zero
+// state.
+func poseidon2Hash(values ...*big.Int) *big.Int {
+	var state fr.Element
+	for _, v := range values {
+		var x fr.Element
+		x.SetBigInt(v)
+		buf := []fr.Element{state, x}
+		if err := poseidon2Permutation.Permutation(buf); err != nil {
+			panic(err) // the buffer always matches the permutation width
+		}
+		state.Add(&buf[1], &x)
+	}
+	return state.BigInt(new(big.Int))
+}
+
+// poseidon2Hasher is the in-circuit counterpart of poseidon2Hash.
+type poseidon2Hasher struct {
+	api  frontend.API
+	perm gnarkposeidon2.Hash
+	data []frontend.Variable
+}
+
+func (h *poseidon2Hasher) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+func (h *poseidon2Hasher) Reset() {
+	h.data = nil
+}
+
+func (h *poseidon2Hasher) Sum() frontend.Variable {
+	state := frontend.Variable(0)
+	for _, x := range h.data {
+		buf := []frontend.Variable{state, x}
+		if err := h.perm.Permutation(h.api, buf); err != nil {
+			panic(err)
+		}
+		state = h.api.Add(buf[1], x)
+	}
+	h.data = nil
+	return state
+}
@@ -0,0 +1,142 @@
+package proofs
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// hashCircuit checks the in-circuit hash of Inputs against Digest.
+type hashCircuit struct {
+	Digest frontend.Variable `gnark:",public"`
+	Inputs []frontend.Variable
+
+	Hash HashAlgorithm `gnark:"-"`
+}
+
+func (c *hashCircuit) Define(api frontend.API) error {
+	h, err := newFieldHasher(api, c.Hash)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Inputs...)
+	api.AssertIsEqual(h.Sum(), c.Digest)
+	return nil
+}
+
+func TestFieldHasherMatchesNative(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	inputs := []*big.Int{big.NewInt(0), big.NewInt(17), new(big.Int).Sub(field, big.NewInt(1))}
+
+	for _, hash := range []HashAlgorithm{HashMiMC, HashPoseidon2} {
+		assignment := &hashCircuit{Digest: hash.sum(inputs...), Inputs: make([]frontend.Variable, len(inputs))}
+		for i, v := range inputs {
+			assignment.Inputs[i] = v
+		}
+		circuit := &hashCircuit{Inputs: make([]frontend.Variable, len(inputs)), Hash: hash}
+		if err := test.IsSolved(circuit, assignment, field); err != nil {
+			t.Errorf("%s: in-circuit hash differs from native: %v", hash, err)
+		}
+	}
+
+	if HashMiMC.sum(inputs...).Cmp(HashPoseidon2.sum(inputs...)) == 0 {
+		t.Errorf("mimc and poseidon2 should give different digests")
+	}
+	if HashPoseidon2.sum(big.NewInt(1), big.NewInt(2)).Cmp(HashPoseidon2.sum(big.NewInt(2), big.NewInt(1))) == 0 {
+		t.Errorf("poseidon2 digest should depend on input order")
+	}
+}
+
+func TestParseHashAlgorithm(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    HashAlgorithm
+		wantErr bool
+	}{
+		{"", HashMiMC, false},
+		{"mimc", HashMiMC, false},
+		{"Poseidon2", HashPoseidon2, false},
+		{"poseidon", "", true},
+		{"sha256", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseHashAlgorithm(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseHashAlgorithm(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestPoseidon2VariantTree(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	vcf := writeTempVCF(t, membershipTestVCF)
+	tree, err := BuildVariantTree(vcf, 4, ImputationPolicy{}, HashPoseidon2, ReadLimits{})
+	if err != nil {
+		t.Fatalf("BuildVariantTree: %v", err)
+	}
+	mimcTree, err := BuildVariantTree(vcf, 4, ImputationPolicy{}, HashMiMC, ReadLimits{})
+	if err != nil {
+		t.Fatalf("BuildVariantTree: %v", err)
+	}
+	if tree.Root().Cmp(mimcTree.Root()) == 0 {
+		t.Fatalf("poseidon2 and mimc trees should have different roots")
+	}
+
+	index, found, err := tree.find(mustParseVariant(t, "1:1000:A:G"))
+	if err != nil || !found {
+		t.Fatalf("variant not found: %v", err)
+	}
+	leaf := tree.leaves[index]
+	assignment := NewVariantMembershipCircuit(tree.Depth, tree.Hash)
+	assignment.Root = tree.Root()
+	assignment.LocusKey = leaf.LocusKey
+	assignment.VariantID = leaf.VariantID
+	assignment.Index = index
+	for i, sibling := range tree.path(index) {
+		assignment.Siblings[i] = sibling
+	}
+
+	if err := test.IsSolved(NewVariantMembershipCircuit(tree.Depth, HashPoseidon2), assignment, field); err != nil {
+		t.Errorf("poseidon2 path should satisfy the poseidon2 circuit: %v", err)
+	}
+	if err := test.IsSolved(NewVariantMembershipCircuit(tree.Depth, HashMiMC), assignment, field); err == nil {
+		t.Errorf("poseidon2 path should not satisfy the mimc circuit")
+	}
+}
+
+func TestPoseidon2MembershipProof(t *testing.T) {
+	vcf := writeTempVCF(t, membershipTestVCF)
+	commitment, err := CommitVariants(vcf, 4, ImputationPolicy{}, HashPoseidon2, ReadLimits{})
+	if err != nil {
+		t.Fatalf("CommitVariants: %v", err)
+	}
+	if commitment.Hash != HashPoseidon2 {
+		t.Errorf("commitment hash = %q, want poseidon2", commitment.Hash)
+	}
+
+	out := filepath.Join(t.TempDir(), "membership_proof.bin")
+	proof := &VariantMembershipProof{
+		Settings:           Settings{Hash: HashPoseidon2},
+		Variant:            "1:1000:A:G",
+		TreeDepth:          4,
+		ExpectedCommitment: commitment.Commitment,
+	}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	meta, err := ReadMetadata(out)
+	if err != nil {
+		t.Fatalf("ReadMetadata: %v", err)
+	}
+	if meta.Hash != HashPoseidon2 {
+		t.Errorf("metadata hash = %q, want poseidon2", meta.Hash)
+	}
+}
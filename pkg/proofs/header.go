@@ -0,0 +1,331 @@
+package proofs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/consensys/gnark/constraint"
+)
+
+// proofMagic starts proof files with a header. Files written before the
+// header was added start with the proof itself, a compressed point whose
+// first byte has its top bit set, or is the point at infinity, but is
+// never 'Z'.
+var proofMagic = [4]byte{'Z', 'K', 'G', 'P'}
+
+// Versions of the proof file layout after the magic. Format 1 is a header
+// of the type and circuit hash, then the proof and length-prefixed public
+// witness as headerless files hold them. Format 2, the one written, is a
+// sequence of sections, each a tag byte, a big-endian uint32 length and
+// the CRC-32C of its data, then the data.
+const (
+	proofFormatV1 = 1
+	proofFormat   = 2
+)
+
+// Tags of the sections of a format 2 proof file. Readers skip sections
+// whose tags they do not know, so a later version may add sections without
+// breaking them.
+const (
+	sectionHeader  byte = 1
+	sectionProof   byte = 2
+	sectionWitness byte = 3
+)
+
+var sectionNames = map[byte]string{sectionHeader: "header", sectionProof: "proof", sectionWitness: "public witness"}
+
+// maxSectionSize bounds the sections read, far above any proof or public
+// witness, so a corrupt length cannot exhaust memory.
+const maxSectionSize = 64 << 20
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrNoHeader is returned by ReadProofHeader for proof files written before
+// proof files had a header.
+var ErrNoHeader = errors.New("proof file has no header")
+
+// ProofHeader is what a proof file states about its proof ahead of the
+// proof itself, so it can be verified without knowing its type.
+type ProofHeader struct {
+	// Format is the version of the proof file layout.
+	Format int `json:"format"`
+	// Type is the proof type, as NewProof takes it.
+	Type string `json:"type"`
+	// CircuitHash is the hex SHA-256 of the compiled constraint system the
+	// proof is for, which changes with the circuit's version and options.
+	CircuitHash string `json:"circuit_hash"`
+	// Backend and Curve are the proof system of the proof. Format 1
+	// headers leave them empty; their proofs' metadata records them.
+	Backend BackendName `json:"backend,omitempty"`
+	Curve   Curve       `json:"curve,omitempty"`
+	// Envelope names the envelope inputs that follow the circuit's public
+	// inputs, in order: "nullifier", "nonce", "digest", "issued" and
+	// "expires". It is empty for a proof without an envelope, and nil when
+	// the header does not record it, as in format 1 files.
+	Envelope []string `json:"envelope,omitempty"`
+}
+
+// newProofHeader describes a proof of proofType over cs with backend and
+// curve.
+func newProofHeader(proofType string, cs constraint.ConstraintSystem, backend BackendName, curve Curve) (*ProofHeader, error) {
+	h, err := circuitHash(cs)
+	if err != nil {
+		return nil, err
+	}
+	return &ProofHeader{
+		Format:      proofFormat,
+		Type:        proofType,
+		CircuitHash: hex.EncodeToString(h),
+		Backend:     BackendName(backend.String()),
+		Curve:       Curve(curve.String()),
+	}, nil
+}
+
+// writeProofContainer writes a format 2 proof file: the magic, the format
+// version, then the header, proof and public witness sections.
+func writeProofContainer(w io.Writer, h *ProofHeader, proof, publicWitness []byte) error {
+	var buf bytes.Buffer
+	buf.Write(proofMagic[:])
+	buf.WriteByte(proofFormat)
+	fields := []string{h.Type, h.CircuitHash, h.Backend.String(), h.Curve.String()}
+	if h.Envelope != nil {
+		fields = append(fields, strings.Join(h.Envelope, ","))
+	}
+	header, err := appendHeaderFields(nil, fields...)
+	if err != nil {
+		return err
+	}
+	for _, s := range []struct {
+		tag  byte
+		data []byte
+	}{{sectionHeader, header}, {sectionProof, proof}, {sectionWitness, publicWitness}} {
+		if len(s.data) > maxSectionSize {
+			return fmt.Errorf("proof file %s section of %d bytes is too large", sectionNames[s.tag], len(s.data))
+		}
+		var head [9]byte
+		head[0] = s.tag
+		binary.BigEndian.PutUint32(head[1:5], uint32(len(s.data)))
+		binary.BigEndian.PutUint32(head[5:], crc32.Checksum(s.data, castagnoli))
+		buf.Write(head[:])
+		buf.Write(s.data)
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing proof file: %w", err)
+	}
+	return nil
+}
+
+// appendHeaderFields appends fields to b, each prefixed with its length.
+func appendHeaderFields(b []byte, fields ...string) ([]byte, error) {
+	for _, field := range fields {
+		if len(field) > 255 {
+			return nil, fmt.Errorf("proof header field %q is too long", field)
+		}
+		b = append(b, byte(len(field)))
+		b = append(b, field...)
+	}
+	return b, nil
+}
+
+// readHeaderFields reads n length-prefixed fields from r.
+func readHeaderFields(r io.Reader, n int) ([]string, error) {
+	fields := make([]string, n)
+	for i := range fields {
+		var size byte
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return nil, fmt.Errorf("reading proof header: %w", err)
+		}
+		field := make([]byte, size)
+		if _, err := io.ReadFull(r, field); err != nil {
+			return nil, fmt.Errorf("reading proof header: %w", err)
+		}
+		fields[i] = string(field)
+	}
+	return fields, nil
+}
+
+// proofSections are the sections of a format 2 proof file by tag.
+type proofSections map[byte][]byte
+
+// readProofContainer reads the header at the start of r. A format 2 file
+// is read whole, checking each section's checksum, and its sections are
+// returned with the header; a format 1 file is left at the proof, with nil
+// sections. A file without a header is rewound and reported as
+// ErrNoHeader.
+func readProofContainer(r io.ReadSeeker) (*ProofHeader, proofSections, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil || magic != proofMagic {
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return nil, nil, fmt.Errorf("rewinding proof file: %w", err)
+		}
+		return nil, nil, ErrNoHeader
+	}
+
+	var format byte
+	if err := binary.Read(r, binary.BigEndian, &format); err != nil {
+		return nil, nil, fmt.Errorf("reading proof header: %w", err)
+	}
+	switch format {
+	case proofFormatV1:
+		fields, err := readHeaderFields(r, 2)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &ProofHeader{Format: proofFormatV1, Type: fields[0], CircuitHash: fields[1]}, nil, nil
+	case proofFormat:
+		sections, err := readSections(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		data := bytes.NewReader(sections[sectionHeader])
+		fields, err := readHeaderFields(data, 4)
+		if err != nil {
+			return nil, nil, err
+		}
+		backend, err := ParseBackend(fields[2])
+		if err != nil {
+			return nil, nil, fmt.Errorf("proof header: %w", err)
+		}
+		curve, err := ParseCurve(fields[3])
+		if err != nil {
+			return nil, nil, fmt.Errorf("proof header: %w", err)
+		}
+		header := &ProofHeader{Format: proofFormat, Type: fields[0], CircuitHash: fields[1], Backend: backend, Curve: curve}
+		// Headers written before the envelope inputs were recorded end
+		// after the curve.
+		if data.Len() > 0 {
+			envelope, err := readHeaderFields(data, 1)
+			if err != nil {
+				return nil, nil, err
+			}
+			if header.Envelope, err = parseEnvelopeLayout(envelope[0]); err != nil {
+				return nil, nil, fmt.Errorf("proof header: %w", err)
+			}
+		}
+		return header, sections, nil
+	default:
+		return nil, nil, fmt.Errorf("proof file format %d is not supported; upgrade to read it", format)
+	}
+}
+
+// readSections reads the sections of a format 2 proof file up to its end,
+// requiring the header, proof and public witness.
+func readSections(r io.Reader) (proofSections, error) {
+	sections := proofSections{}
+	for {
+		var head [9]byte
+		if _, err := io.ReadFull(r, head[:]); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading proof file section: %w", err)
+		}
+		tag, size, sum := head[0], binary.BigEndian.Uint32(head[1:5]), binary.BigEndian.Uint32(head[5:])
+		if size > maxSectionSize {
+			return nil, fmt.Errorf("proof file section %d claims %d bytes; the file is corrupt", tag, size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("reading proof file section %d: %w", tag, err)
+		}
+		if crc32.Checksum(data, castagnoli) != sum {
+			return nil, fmt.Errorf("proof file section %d does not match its checksum; the file is corrupt", tag)
+		}
+		if _, ok := sections[tag]; ok {
+			return nil, fmt.Errorf("proof file has two sections %d", tag)
+		}
+		sections[tag] = data
+	}
+	for _, tag := range []byte{sectionHeader, sectionProof, sectionWitness} {
+		if _, ok := sections[tag]; !ok {
+			return nil, fmt.Errorf("proof file has no %s section", sectionNames[tag])
+		}
+	}
+	return sections, nil
+}
+
+// ReadProofHeader reads the header of the proof file at proofPath. The
+// header of a format 2 file is returned only once every section matches
+// its checksum.
+func ReadProofHeader(proofPath string) (*ProofHeader, error) {
+	f, err := openFile(proofPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening proof file: %w", err)
+	}
+	defer f.Close()
+	header, _, err := readProofContainer(f)
+	return header, err
+}
+
+// DetectProofType returns the type of the proof at proofPath from its
+// header, or from its metadata for proof files whose header names none.
+func DetectProofType(proofPath string) (string, error) {
+	header, err := ReadProofHeader(proofPath)
+	if err == nil && header.Type != "" {
+		return header.Type, nil
+	}
+	if err != nil && !errors.Is(err, ErrNoHeader) {
+		return "", err
+	}
+	meta, err := ReadMetadata(proofPath)
+	if err != nil {
+		return "", fmt.Errorf("proof file names no type and its metadata cannot be read; pass its type")
+	}
+	return meta.ProofType, nil
+}
+
+// CheckProofType rejects the proof at proofPath when its header or metadata
+// names another type than proofType, which would otherwise fail
+// verification with no hint why. A proof of unknown type passes.
+func CheckProofType(proofPath string, proofType string) error {
+	detected, err := DetectProofType(proofPath)
+	if err != nil || detected == "" {
+		return nil
+	}
+	if !strings.EqualFold(detected, proofType) {
+		return withKind(ErrInvalidInput, fmt.Errorf("%s holds a %s proof, not a %s proof", proofPath, detected, proofType))
+	}
+	return nil
+}
+
+// checkProofCircuit rejects a verifying key whose description, when setup
+// wrote one, is for another circuit than the proof's header names; such a
+// proof could only fail to verify.
+func checkProofCircuit(verifyingKeyPath string, header *ProofHeader) error {
+	if header == nil || header.CircuitHash == "" {
+		return nil
+	}
+	info, err := ReadKeyInfo(strings.TrimSuffix(verifyingKeyPath, ".vk"))
+	if err != nil {
+		return nil
+	}
+	if info.CircuitHash != header.CircuitHash {
+		return fmt.Errorf("verifying key %s was set up for circuit %s, but the proof is for circuit %s; the proof was generated with other options or another version", verifyingKeyPath, info.CircuitHash, header.CircuitHash)
+	}
+	return nil
+}
+
+// parseEnvelopeLayout parses the comma-separated envelope inputs of a
+// header, which must be envelopeInputs in their order.
+func parseEnvelopeLayout(s string) ([]string, error) {
+	layout := []string{}
+	if s == "" {
+		return layout, nil
+	}
+	next := 0
+	for _, name := range strings.Split(s, ",") {
+		i := slices.Index(envelopeInputs, name)
+		if i < next {
+			return nil, fmt.Errorf("invalid envelope inputs %q", s)
+		}
+		layout = append(layout, name)
+		next = i + 1
+	}
+	return layout, nil
+}
@@ -0,0 +1,184 @@
+package proofs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProofHeader(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	proof := &ZygosityProof{Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	header, err := ReadProofHeader(out)
+	if err != nil {
+		t.Fatalf("ReadProofHeader: %v", err)
+	}
+	meta, err := ReadMetadata(out)
+	if err != nil {
+		t.Fatalf("ReadMetadata: %v", err)
+	}
+	if header.Type != "zygosity" || header.CircuitHash != meta.CircuitHash {
+		t.Errorf("header = %+v; want zygosity over circuit %s", header, meta.CircuitHash)
+	}
+	if header.Format != proofFormat || header.Backend != BackendGroth16 || header.Curve != CurveBN254 {
+		t.Errorf("header = %+v; want format %d, groth16 over bn254", header, proofFormat)
+	}
+	if detected, err := DetectProofType(out); err != nil || detected != "zygosity" {
+		t.Errorf("DetectProofType = %q, %v", detected, err)
+	}
+	if err := CheckProofType(out, "Zygosity"); err != nil {
+		t.Errorf("CheckProofType with the proof's type: %v", err)
+	}
+	if err := CheckProofType(out, "wildtype"); ErrorKind(err) != ErrInvalidInput {
+		t.Errorf("CheckProofType with another type = %v; want invalid input", err)
+	}
+
+	// Proof files written before the header still read, their type coming
+	// from the metadata.
+	p, w, err := readProofFile(out)
+	if err != nil {
+		t.Fatalf("readProofFile: %v", err)
+	}
+	if err := writeProofFile(out, nil, p, w); err != nil {
+		t.Fatalf("writeProofFile: %v", err)
+	}
+	if _, err := ReadProofHeader(out); !errors.Is(err, ErrNoHeader) {
+		t.Errorf("ReadProofHeader of a headerless file = %v; want ErrNoHeader", err)
+	}
+	if detected, err := DetectProofType(out); err != nil || detected != "zygosity" {
+		t.Errorf("DetectProofType of a headerless file = %q, %v", detected, err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Errorf("Verify of a headerless file = %v, %v", ok, err)
+	}
+}
+
+func TestProofHeaderCircuitMismatch(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	keys := filepath.Join(dir, "zygosity")
+	if err := SetupKeys(&ZygosityProof{Locus: "15:28365618"}, keys, SetupOptions{Nonce: true}); err != nil {
+		t.Fatalf("SetupKeys: %v", err)
+	}
+
+	// A proof without a nonce is for another circuit than the keys.
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	proof := &ZygosityProof{Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	ok, err := proof.Verify(t.Context(), keys+".vk", out)
+	if ok || ErrorKind(err) != ErrInvalidInput || !strings.Contains(err.Error(), "circuit") {
+		t.Errorf("Verify with keys for another circuit = %v, %v; want a circuit mismatch", ok, err)
+	}
+}
+
+func TestProofFileFormats(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	proof := &ZygosityProof{Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	header, err := ReadProofHeader(out)
+	if err != nil {
+		t.Fatalf("ReadProofHeader: %v", err)
+	}
+	p, w, err := readProofFile(out)
+	if err != nil {
+		t.Fatalf("readProofFile: %v", err)
+	}
+	var proofData bytes.Buffer
+	p.WriteTo(&proofData)
+	witnessData, err := w.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A format 1 file: the type and circuit hash, then the proof and
+	// length-prefixed public witness.
+	v1 := append(proofMagic[:], proofFormatV1)
+	v1, _ = appendHeaderFields(v1, header.Type, header.CircuitHash)
+	v1 = append(v1, proofData.Bytes()...)
+	v1 = binary.BigEndian.AppendUint32(v1, uint32(len(witnessData)))
+	v1 = append(v1, witnessData...)
+
+	// Sections of tags a reader does not know are skipped.
+	unknown := []byte("from a later version")
+	extended := append(bytes.Clone(current), 200)
+	extended = binary.BigEndian.AppendUint32(extended, uint32(len(unknown)))
+	extended = binary.BigEndian.AppendUint32(extended, crc32.Checksum(unknown, castagnoli))
+	extended = append(extended, unknown...)
+
+	for _, tt := range []struct {
+		name   string
+		data   []byte
+		format int
+	}{
+		{"format 1", v1, proofFormatV1},
+		{"unknown section", extended, proofFormat},
+	} {
+		if err := os.WriteFile(out, tt.data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if h, err := ReadProofHeader(out); err != nil || h.Format != tt.format || h.CircuitHash != header.CircuitHash {
+			t.Errorf("%s: ReadProofHeader = %+v, %v", tt.name, h, err)
+		}
+		if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+			t.Errorf("%s: Verify = %v, %v", tt.name, ok, err)
+		}
+	}
+
+	corrupt := bytes.Clone(current)
+	corrupt[len(corrupt)-1] ^= 1
+	truncated := current[:len(current)-len(witnessData)-9]
+	future := bytes.Clone(current)
+	future[len(proofMagic)] = proofFormat + 1
+	for _, tt := range []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"corrupt", corrupt, "checksum"},
+		{"truncated", truncated, "no public witness section"},
+		{"future format", future, "not supported"},
+	} {
+		if err := os.WriteFile(out, tt.data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadProofHeader(out); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: ReadProofHeader = %v; want %q", tt.name, err, tt.err)
+		}
+		if ok, err := proof.Verify(t.Context(), out+".vk", out); ok || err == nil {
+			t.Errorf("%s: Verify = %v, %v; want an error", tt.name, ok, err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package proofs
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/brentp/vcfgo"
+)
+
+// ImputationMode selects how imputed genotype calls are treated when a
+// variant is considered for a proof witness.
+type ImputationMode string
+
+const (
+	// ImputationAllow accepts imputed calls like any other call.
+	ImputationAllow ImputationMode = "allow"
+	// ImputationRefuse excludes every imputed call from the witness.
+	ImputationRefuse ImputationMode = "refuse"
+	// ImputationThreshold accepts imputed calls only when the genotype
+	// probability reaches MinProbability.
+	ImputationThreshold ImputationMode = "threshold"
+)
+
+// ImputationPolicy decides whether array-derived, imputed genotypes may back
+// a proof. Calls are considered imputed when the sample carries GP or DS
+// FORMAT fields, or the record has the IMP INFO flag set by Minimac/Beagle.
+type ImputationPolicy struct {
+	Mode           ImputationMode `json:"mode"`
+	MinProbability float64        `json:"min_probability,omitempty"`
+}
+
+// ParseImputationMode converts a CLI string into an ImputationMode.
+func ParseImputationMode(s string) (ImputationMode, error) {
+	switch mode := ImputationMode(strings.ToLower(s)); mode {
+	case "", ImputationAllow:
+		return ImputationAllow, nil
+	case ImputationRefuse, ImputationThreshold:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown imputation mode: %s. Supported modes: allow, refuse, threshold", s)
+	}
+}
+
+// Validate reports whether the policy is internally consistent.
+func (p ImputationPolicy) Validate() error {
+	if _, err := ParseImputationMode(string(p.Mode)); err != nil {
+		return err
+	}
+	if p.Mode == ImputationThreshold && (p.MinProbability <= 0 || p.MinProbability > 1) {
+		return fmt.Errorf("imputation threshold must be in (0, 1], got %g", p.MinProbability)
+	}
+	return nil
+}
+
+// Accepts reports whether the first sample's call at variant may be used
+// in a witness under this policy.
+func (p ImputationPolicy) Accepts(variant *vcfgo.Variant) bool {
+	return p.acceptsSample(variant, 0)
+}
+
+// acceptsSample is Accepts for the call of the sample with the given
+// index.
+func (p ImputationPolicy) acceptsSample(variant *vcfgo.Variant, sampleIndex int) bool {
+	if p.Mode == "" || p.Mode == ImputationAllow {
+		return true
+	}
+
+	prob, imputed := imputedProbability(variant, sampleIndex)
+	if !imputed {
+		return true
+	}
+
+	switch p.Mode {
+	case ImputationRefuse:
+		return false
+	case ImputationThreshold:
+		return prob >= p.MinProbability
+	default:
+		return false
+	}
+}
+
+// imputedProbability returns the confidence of the indexed sample's call and
+// whether the call looks imputed. GP is preferred; when only the dosage is
+// available its distance to the nearest integer is used as a proxy.
+func imputedProbability(variant *vcfgo.Variant, sampleIndex int) (float64, bool) {
+	imputed := infoHasKey(variant, "IMP")
+
+	if len(variant.Samples) <= sampleIndex || variant.Samples[sampleIndex] == nil {
+		return 0, imputed
+	}
+	fields := variant.Samples[sampleIndex].Fields
+
+	if gp, ok := fields["GP"]; ok && gp != "." {
+		best := 0.0
+		for _, v := range strings.Split(gp, ",") {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return 0, true
+			}
+			best = math.Max(best, f)
+		}
+		return best, true
+	}
+
+	if ds, ok := fields["DS"]; ok && ds != "." {
+		f, err := strconv.ParseFloat(ds, 64)
+		if err != nil {
+			return 0, true
+		}
+		return 1 - math.Abs(f-math.Round(f)), true
+	}
+
+	return 0, imputed
+}
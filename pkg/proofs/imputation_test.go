@@ -0,0 +1,74 @@
+package proofs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brentp/vcfgo"
+)
+
+func readTestVariants(t *testing.T, vcfContent string) []*vcfgo.Variant {
+	t.Helper()
+
+	rdr, err := vcfgo.NewReader(strings.NewReader(vcfContent), false)
+	if err != nil {
+		t.Fatalf("Failed to create VCF reader: %v", err)
+	}
+
+	var variants []*vcfgo.Variant
+	for {
+		variant := rdr.Read()
+		if variant == nil {
+			break
+		}
+		variants = append(variants, variant)
+	}
+	return variants
+}
+
+func TestImputationPolicy_Accepts(t *testing.T) {
+	vcfContent := `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=GP,Number=G,Type=Float,Description="Genotype probabilities">
+##FORMAT=<ID=DS,Number=1,Type=Float,Description="Dosage">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	SAMPLE1
+22	100	.	A	G	60	PASS	.	GT	0/1
+22	200	.	A	G	60	PASS	.	GT:GP	0/1:0.02,0.97,0.01
+22	300	.	A	G	60	PASS	.	GT:GP	0/1:0.30,0.60,0.10
+22	400	.	A	G	60	PASS	.	GT:DS	1/1:1.7
+`
+	variants := readTestVariants(t, vcfContent)
+	if len(variants) != 4 {
+		t.Fatalf("Expected 4 variants, got %d", len(variants))
+	}
+
+	tests := []struct {
+		name   string
+		policy ImputationPolicy
+		want   []bool
+	}{
+		{"allow", ImputationPolicy{Mode: ImputationAllow}, []bool{true, true, true, true}},
+		{"refuse", ImputationPolicy{Mode: ImputationRefuse}, []bool{true, false, false, false}},
+		{"threshold", ImputationPolicy{Mode: ImputationThreshold, MinProbability: 0.9}, []bool{true, true, false, false}},
+	}
+
+	for _, tt := range tests {
+		for i, variant := range variants {
+			if got := tt.policy.Accepts(variant); got != tt.want[i] {
+				t.Errorf("%s: variant at %d accepted = %v, want %v", tt.name, variant.Pos, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestImputationPolicy_Validate(t *testing.T) {
+	if err := (ImputationPolicy{Mode: ImputationThreshold}).Validate(); err == nil {
+		t.Errorf("Threshold mode without a probability should be rejected")
+	}
+	if err := (ImputationPolicy{Mode: "sometimes"}).Validate(); err == nil {
+		t.Errorf("Unknown mode should be rejected")
+	}
+	if err := (ImputationPolicy{}).Validate(); err != nil {
+		t.Errorf("Zero policy should be valid: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package proofs
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ProofSummary is what a proof file and its metadata state about a proof,
+// read without verifying it. None of it is trustworthy until the proof
+// verifies against the expected verifying key.
+type ProofSummary struct {
+	// Metadata is nil when the proof has no metadata file, and Header when
+	// the proof file was written before proof files had a header.
+	Metadata *ProofMetadata
+	Header   *ProofHeader
+	// Size is the size of the proof file in bytes.
+	Size int64
+	// Inputs are the proof circuit's public inputs; the envelope's
+	// nullifier, nonce, digest and time inputs are split off into their
+	// fields. Issued and Expires are Unix times.
+	Inputs    []*big.Int
+	Nullifier *big.Int
+	Nonce     *big.Int
+	Digest    *big.Int
+	Issued    *big.Int
+	Expires   *big.Int
+	// Claims describe the public inputs of proof types whose inputs
+	// state a result, such as a predicted eye color.
+	Claims []string
+}
+
+// InspectProof reads the proof at proofPath and its metadata without
+// verifying the proof.
+func InspectProof(proofPath string) (*ProofSummary, error) {
+	stat, err := statFile(proofPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading proof file: %w", err)
+	}
+	env, err := readEnvelope(proofPath)
+	if err != nil {
+		return nil, err
+	}
+	summary := &ProofSummary{
+		Size:      stat.Size(),
+		Inputs:    env.Inputs,
+		Nullifier: env.Nullifier,
+		Nonce:     env.Nonce,
+		Digest:    env.Digest,
+		Issued:    env.Issued,
+		Expires:   env.Expires,
+	}
+	if header, err := ReadProofHeader(proofPath); err == nil {
+		summary.Header = header
+	}
+	if meta, err := ReadMetadata(proofPath); err == nil {
+		summary.Metadata = meta
+		summary.Claims = describeClaims(meta.ProofType, env.Inputs)
+	}
+	return summary, nil
+}
+
+// ReadPublicInputs returns what the proof at proofPath, or the
+// ProofEnvelope there, claims, without verifying it: each public input of
+// its circuit under the name its type's PublicInputs give it, as a
+// *big.Int. The last named input holds the remaining inputs as a
+// []*big.Int when there are more than names, as for a decision table.
+// Envelope inputs the proof carries follow under "nullifier" (*big.Int),
+// "nonce" (the nonce string), "dataset_digest" (the digest string) and
+// "issued_at" and "expires_at" (time.Time).
+//
+// The values are what the proof states; they are established only once the
+// proof verifies.
+func ReadPublicInputs(proofPath string) (map[string]any, error) {
+	if IsProofEnvelope(proofPath) {
+		unpacked, cleanup, err := unpackEnvelope(proofPath)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		proofPath = unpacked
+	}
+	proofType, err := DetectProofType(proofPath)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+	t, ok := LookupProofType(proofType)
+	if !ok {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("unknown proof type %q", proofType))
+	}
+	env, err := readEnvelope(proofPath)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+	names := t.PublicInputs
+	if len(env.Inputs) < len(names) {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("%s proof has %d public inputs, want at least %d", t.Name, len(env.Inputs), len(names)))
+	}
+
+	inputs := make(map[string]any, len(names)+5)
+	for i, input := range names {
+		if i == len(names)-1 && len(env.Inputs) > len(names) {
+			inputs[input.Name] = env.Inputs[i:]
+			break
+		}
+		inputs[input.Name] = env.Inputs[i]
+	}
+	meta, _ := ReadMetadata(proofPath)
+	if env.Nullifier != nil {
+		inputs["nullifier"] = env.Nullifier
+	}
+	if env.Nonce != nil {
+		inputs["nonce"] = meta.Nonce
+	}
+	if env.Digest != nil {
+		inputs["dataset_digest"] = meta.DatasetDigest
+	}
+	if env.Issued != nil {
+		inputs["issued_at"] = time.Unix(env.Issued.Int64(), 0).UTC()
+	}
+	if env.Expires != nil {
+		inputs["expires_at"] = time.Unix(env.Expires.Int64(), 0).UTC()
+	}
+	return inputs, nil
+}
+
+// describeClaims states the results the public inputs of a proof of
+// proofType claim, or nothing when the inputs do not have the layout the
+// proof type's Verify expects.
+func describeClaims(proofType string, public []*big.Int) []string {
+	if d, ok := traitDefinitions[proofType]; ok {
+		if len(public) != 1+len(d.Trait.Table) {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: %s", d.Output, d.PhenotypeName(int(public[0].Int64())))}
+	}
+	switch proofType {
+	case "brca1":
+		if len(public) != 1 {
+			return nil
+		}
+		if public[0].Sign() != 0 {
+			return []string{"Carries a known pathogenic BRCA1 variant"}
+		}
+		return []string{"Carries none of the known pathogenic BRCA1 variants"}
+	case "diplotype":
+		if len(public) != 2 {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s status: %s", public[0].Bytes(), MetabolizerPhenotype(public[1].Int64()))}
+	case "zygosity":
+		if len(public) != 4 {
+			return nil
+		}
+		return []string{
+			fmt.Sprintf("Zygosity at %s: %s", locusFromKey(public[0].Uint64()), ZygosityName(int(public[1].Int64()))),
+			fmt.Sprintf("Call quality at least %s, read depth at least %s", public[2], public[3]),
+		}
+	case "wildtype":
+		if len(public) != 3 {
+			return nil
+		}
+		return []string{
+			fmt.Sprintf("Homozygous reference at %s", locusFromKey(public[0].Uint64())),
+			fmt.Sprintf("Call quality at least %s, read depth at least %s", public[1], public[2]),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package proofs
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInspectProof(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	keys := filepath.Join(dir, "zygosity")
+	if err := SetupKeys(&ZygosityProof{}, keys, SetupOptions{Nonce: true}); err != nil {
+		t.Fatalf("SetupKeys: %v", err)
+	}
+	info, err := ReadKeyInfo(keys)
+	if err != nil {
+		t.Fatalf("ReadKeyInfo: %v", err)
+	}
+
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	proof := &ZygosityProof{Settings: Settings{Nonce: "challenge"}, Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, keys+".pk", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	summary, err := InspectProof(out)
+	if err != nil {
+		t.Fatalf("InspectProof: %v", err)
+	}
+	meta := summary.Metadata
+	if meta == nil || meta.ProofType != "zygosity" {
+		t.Fatalf("metadata = %+v, want a zygosity proof's", meta)
+	}
+	if meta.CircuitHash != info.CircuitHash {
+		t.Errorf("circuit hash %s, keys set up for %s", meta.CircuitHash, info.CircuitHash)
+	}
+	if vk, _ := KeyFingerprint(keys + ".vk"); meta.VerifyingKey != vk {
+		t.Errorf("verifying key fingerprint %s, want %s", meta.VerifyingKey, vk)
+	}
+	if pk, _ := KeyFingerprint(keys + ".pk"); meta.ProvingKey != pk {
+		t.Errorf("proving key fingerprint %s, want %s", meta.ProvingKey, pk)
+	}
+	if len(summary.Inputs) != 4 || summary.Nonce == nil || summary.Nonce.Cmp(NonceID("challenge")) != 0 {
+		t.Errorf("inputs %v, nonce %v; want 4 inputs and the challenge", summary.Inputs, summary.Nonce)
+	}
+	if len(summary.Claims) == 0 || !strings.Contains(summary.Claims[0], "heterozygous") {
+		t.Errorf("claims = %q, want a heterozygous call", summary.Claims)
+	}
+
+	// Without a proving key the fingerprints are of the keys set up beside
+	// the proof.
+	fresh := filepath.Join(dir, "fresh.bin")
+	if err := (&ZygosityProof{Locus: "15:28365618"}).Generate(t.Context(), vcf, "", fresh); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	meta, err = ReadMetadata(fresh)
+	if err != nil {
+		t.Fatalf("ReadMetadata: %v", err)
+	}
+	if vk, _ := KeyFingerprint(fresh + ".vk"); meta.VerifyingKey != vk || meta.CircuitHash == "" {
+		t.Errorf("verifying key fingerprint %s, want %s; circuit hash %q", meta.VerifyingKey, vk, meta.CircuitHash)
+	}
+}
+
+func TestReadPublicInputs(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|1
+`)
+	out := filepath.Join(t.TempDir(), "zygosity_proof.bin")
+	if err := Generate(t.Context(), "zygosity", vcf, out, WithPosition("15:28365618"), WithNonce("challenge"), WithValidFor(time.Hour)); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	env, err := ReadProofEnvelope(out)
+	if err != nil {
+		t.Fatalf("ReadProofEnvelope: %v", err)
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := os.WriteFile(out+EnvelopeSuffix, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{out, out + EnvelopeSuffix} {
+		inputs, err := ReadPublicInputs(path)
+		if err != nil {
+			t.Fatalf("ReadPublicInputs(%s): %v", path, err)
+		}
+		if got := inputs["locus_key"].(*big.Int).Uint64(); got != 15<<32|28365618 {
+			t.Errorf("locus_key = %d", got)
+		}
+		if got := inputs["zygosity"].(*big.Int).Int64(); got != Homozygous {
+			t.Errorf("zygosity = %d, want homozygous", got)
+		}
+		if inputs["nonce"] != "challenge" {
+			t.Errorf("nonce = %v", inputs["nonce"])
+		}
+		if expires, ok := inputs["expires_at"].(time.Time); !ok || !expires.Equal(env.ExpiresAt.Truncate(time.Second)) {
+			t.Errorf("expires_at = %v, want %v", inputs["expires_at"], env.ExpiresAt)
+		}
+		if _, ok := inputs["nullifier"]; ok {
+			t.Errorf("proof without a nullifier has one: %v", inputs["nullifier"])
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package proofs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultIPFSAPI is the RPC API of a local IPFS (Kubo) node.
+const DefaultIPFSAPI = "http://127.0.0.1:5001"
+
+// maxIPFSBlock bounds the files pinned and fetched: each is one raw block,
+// and IPFS nodes do not exchange blocks over 1 MiB. Proofs and verifying
+// keys are far smaller; proving keys are not published.
+const maxIPFSBlock = 1 << 20
+
+// IPFS publishes proofs and verifying keys to IPFS and fetches them back.
+// Each file is stored as a single raw block, so its CID is the SHA-256 of
+// its bytes and a fetched file is checked against its CID without trusting
+// the node or gateway it came from.
+type IPFS struct {
+	// API is the base URL of the Kubo RPC API that files are pinned
+	// through; empty means DefaultIPFSAPI.
+	API string
+	// Gateway, when set, is the base URL of an IPFS gateway, such as
+	// https://ipfs.io, that files are fetched from instead of the API.
+	Gateway string
+	// Client makes the requests; nil means http.DefaultClient.
+	Client *http.Client
+}
+
+// IPFSCID returns the CID data has as a raw IPFS block: a base32 CIDv1 of
+// the raw codec and its SHA-256.
+func IPFSCID(data []byte) string {
+	digest := sha256.Sum256(data)
+	return "b" + strings.ToLower(cidEncoding.EncodeToString(append([]byte{0x01, 0x55, 0x12, 0x20}, digest[:]...)))
+}
+
+var cidEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// parseIPFSCID returns the SHA-256 a CID of IPFSCID's form names.
+func parseIPFSCID(cid string) ([]byte, error) {
+	raw, err := cidEncoding.DecodeString(strings.ToUpper(strings.TrimPrefix(cid, "b")))
+	if err != nil || !strings.HasPrefix(cid, "b") || len(raw) != 36 || !bytes.Equal(raw[:4], []byte{0x01, 0x55, 0x12, 0x20}) {
+		return nil, fmt.Errorf("%q is not a CIDv1 of a raw SHA-256 block", cid)
+	}
+	return raw[4:], nil
+}
+
+// Pin stores data as a raw block on the IPFS node, pinned so the node keeps
+// it, and returns its CID.
+func (c *IPFS) Pin(ctx context.Context, data []byte) (string, error) {
+	if len(data) > maxIPFSBlock {
+		return "", fmt.Errorf("%d bytes are more than the %d published as one IPFS block", len(data), maxIPFSBlock)
+	}
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("data", "data")
+	if err != nil {
+		return "", err
+	}
+	part.Write(data)
+	form.Close()
+
+	resp, err := c.post(ctx, "block/put", url.Values{"cid-codec": {"raw"}, "mhtype": {"sha2-256"}, "pin": {"true"}}, form.FormDataContentType(), &body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var put struct{ Key string }
+	if err := json.NewDecoder(resp.Body).Decode(&put); err != nil {
+		return "", fmt.Errorf("reading IPFS block/put response: %w", err)
+	}
+	cid := IPFSCID(data)
+	if put.Key != cid {
+		return "", fmt.Errorf("IPFS stored the block as %s, not %s", put.Key, cid)
+	}
+	return cid, nil
+}
+
+// Fetch returns the file with CID cid, from the gateway when one is set,
+// checking that its bytes hash to the CID.
+func (c *IPFS) Fetch(ctx context.Context, cid string) ([]byte, error) {
+	digest, err := parseIPFSCID(cid)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+	var resp *http.Response
+	if c.Gateway != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.Gateway, "/")+"/ipfs/"+cid, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.ipld.raw")
+		if resp, err = c.client().Do(req); err != nil {
+			return nil, fmt.Errorf("fetching %s from IPFS: %w", cid, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("fetching %s from IPFS: gateway answered %s", cid, resp.Status)
+		}
+	} else if resp, err = c.post(ctx, "block/get", url.Values{"arg": {cid}}, "", nil); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIPFSBlock+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s from IPFS: %w", cid, err)
+	}
+	if sum := sha256.Sum256(data); !bytes.Equal(sum[:], digest) {
+		return nil, withKind(ErrVerification, fmt.Errorf("the file IPFS returned for %s does not match its CID", cid))
+	}
+	return data, nil
+}
+
+// post calls the Kubo RPC command cmd with args.
+func (c *IPFS) post(ctx context.Context, cmd string, args url.Values, contentType string, body io.Reader) (*http.Response, error) {
+	api := c.API
+	if api == "" {
+		api = DefaultIPFSAPI
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(api, "/")+"/api/v0/"+cmd+"?"+args.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("IPFS %s: %w", cmd, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var rpcErr struct{ Message string }
+		json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&rpcErr)
+		return nil, fmt.Errorf("IPFS %s: %s: %s", cmd, resp.Status, rpcErr.Message)
+	}
+	return resp, nil
+}
+
+func (c *IPFS) client() *http.Client {
+	if c.Client != nil {
+		return c.Client
+	}
+	return http.DefaultClient
+}
+
+// Publish pins the proof file at proofPath and the verifying key at
+// verifyingKeyPath to IPFS and records their CIDs in the envelope, so a
+// verifier holding only the envelope can fetch the key it was issued
+// against with FetchVerifyingKey.
+func (e *ProofEnvelope) Publish(ctx context.Context, ipfs *IPFS, proofPath, verifyingKeyPath string) error {
+	proof, err := readFile(proofPath)
+	if err != nil {
+		return withKind(ErrInvalidInput, fmt.Errorf("reading proof: %w", err))
+	}
+	vk, err := readFile(verifyingKeyPath)
+	if err != nil {
+		return withKind(ErrInvalidInput, fmt.Errorf("reading verifying key: %w", err))
+	}
+	if e.ProofCID, err = ipfs.Pin(ctx, proof); err != nil {
+		return fmt.Errorf("publishing proof: %w", err)
+	}
+	if e.VerifyingKeyCID, err = ipfs.Pin(ctx, vk); err != nil {
+		return fmt.Errorf("publishing verifying key: %w", err)
+	}
+	return nil
+}
+
+// FetchVerifyingKey fetches the verifying key the envelope names by CID
+// from IPFS.
+func (e *ProofEnvelope) FetchVerifyingKey(ctx context.Context, ipfs *IPFS) ([]byte, error) {
+	if e.VerifyingKeyCID == "" {
+		return nil, withKind(ErrNotFound, errors.New("the proof envelope names no verifying key on IPFS"))
+	}
+	return ipfs.Fetch(ctx, e.VerifyingKeyCID)
+}
@@ -0,0 +1,118 @@
+package proofs
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeIPFS serves the Kubo RPC block commands and the gateway path of a
+// node storing raw blocks.
+type fakeIPFS struct {
+	mu     sync.Mutex
+	blocks map[string][]byte
+}
+
+func (f *fakeIPFS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.URL.Path == "/api/v0/block/put":
+		file, _, err := r.FormFile("data")
+		if err != nil || r.URL.Query().Get("pin") != "true" {
+			http.Error(w, `{"Message":"bad request"}`, http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		cid := IPFSCID(data)
+		f.blocks[cid] = data
+		json.NewEncoder(w).Encode(map[string]any{"Key": cid, "Size": len(data)})
+	case r.URL.Path == "/api/v0/block/get":
+		data, ok := f.blocks[r.URL.Query().Get("arg")]
+		if !ok {
+			http.Error(w, `{"Message":"block not found"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Write(data)
+	case strings.HasPrefix(r.URL.Path, "/ipfs/"):
+		data, ok := f.blocks[strings.TrimPrefix(r.URL.Path, "/ipfs/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestIPFSCID(t *testing.T) {
+	// The CID every IPFS implementation gives an empty raw block.
+	if cid := IPFSCID(nil); cid != "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku" {
+		t.Errorf("IPFSCID(nil) = %s", cid)
+	}
+	if _, err := parseIPFSCID("QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"); err == nil {
+		t.Errorf("parsed a CIDv0")
+	}
+}
+
+func TestPublishEnvelope(t *testing.T) {
+	node := &fakeIPFS{blocks: map[string][]byte{}}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	dir := t.TempDir()
+	vcf := filepath.Join(dir, "input.vcf")
+	if err := os.WriteFile(vcf, []byte(`##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	proofPath := filepath.Join(dir, "proof.bin")
+	if err := Generate(t.Context(), "zygosity", vcf, proofPath, WithPosition("15:28365618")); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	env, err := ReadProofEnvelope(proofPath)
+	if err != nil {
+		t.Fatalf("ReadProofEnvelope: %v", err)
+	}
+	if err := env.Publish(t.Context(), &IPFS{API: server.URL}, proofPath, proofPath+".vk"); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	vk, _ := os.ReadFile(proofPath + ".vk")
+	if env.VerifyingKeyCID != IPFSCID(vk) || node.blocks[env.ProofCID] == nil {
+		t.Fatalf("published CIDs %s and %s", env.ProofCID, env.VerifyingKeyCID)
+	}
+
+	// An envelope elsewhere, without the key beside it, verifies with the
+	// key fetched through the gateway.
+	envPath := filepath.Join(t.TempDir(), "proof.bin"+EnvelopeSuffix)
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(envPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(t.Context(), envPath, WithPosition("15:28365618")); err == nil {
+		t.Errorf("Verify without the key or IPFS succeeded")
+	}
+	if _, err := Verify(t.Context(), envPath, WithPosition("15:28365618"), WithIPFS(&IPFS{Gateway: server.URL})); err != nil {
+		t.Errorf("Verify with the key from IPFS: %v", err)
+	}
+
+	// A node returning other bytes than a CID names is caught.
+	node.blocks[env.VerifyingKeyCID] = []byte("forged key")
+	if _, err := env.FetchVerifyingKey(t.Context(), &IPFS{API: server.URL}); !errors.Is(ErrorKind(err), ErrVerification) {
+		t.Errorf("FetchVerifyingKey of a forged block: %v", err)
+	}
+}
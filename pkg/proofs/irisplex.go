@@ -0,0 +1,244 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// IrisPlexSNP is one predictor of the IrisPlex model. Counted is the allele,
+// in GRCh37 forward-strand orientation, whose copies enter the model.
+type IrisPlexSNP struct {
+	RSID       string
+	Gene       string
+	Chromosome string
+	Position   uint64
+	Ref        string
+	Alt        string
+	Counted    string
+	// Blue and Intermediate are the model coefficients for this SNP relative
+	// to brown, in hundredths.
+	Blue         int
+	Intermediate int
+}
+
+// Spec returns the predictor as a variant.
+func (s IrisPlexSNP) Spec() VariantSpec {
+	return VariantSpec{Chromosome: s.Chromosome, Position: s.Position, Ref: s.Ref, Alt: s.Alt, RSID: s.RSID}
+}
+
+// irisPlexOffset keeps every scaled score positive so the circuit can compare
+// scores as unsigned integers.
+const irisPlexOffset = 10000
+
+// Intercepts of the blue and intermediate logits relative to brown, in
+// hundredths.
+const (
+	irisPlexBlueIntercept         = 394
+	irisPlexIntermediateIntercept = 65
+)
+
+// IrisPlexSNPs are the six IrisPlex predictors with rounded multinomial
+// logistic regression coefficients after Walsh et al. (2011). Brown is the
+// reference category.
+var IrisPlexSNPs = []IrisPlexSNP{
+	{RSID: "rs12913832", Gene: "HERC2", Chromosome: "15", Position: HERC2Pos, Ref: "A", Alt: "G", Counted: "A", Blue: -481, Intermediate: -140},
+	{RSID: "rs1800407", Gene: "OCA2", Chromosome: "15", Position: 28230318, Ref: "C", Alt: "T", Counted: "T", Blue: 140, Intermediate: 87},
+	{RSID: "rs12896399", Gene: "SLC24A4", Chromosome: "14", Position: 92773663, Ref: "G", Alt: "T", Counted: "T", Blue: -58, Intermediate: -3},
+	{RSID: "rs16891982", Gene: "SLC45A2", Chromosome: "5", Position: 33951693, Ref: "C", Alt: "G", Counted: "C", Blue: -246, Intermediate: -117},
+	{RSID: "rs1393350", Gene: "TYR", Chromosome: "11", Position: 89011046, Ref: "G", Alt: "A", Counted: "A", Blue: 92, Intermediate: 28},
+	{RSID: "rs12203592", Gene: "IRF4", Chromosome: "6", Position: 396321, Ref: "C", Alt: "T", Counted: "T", Blue: -4, Intermediate: 81},
+}
+
+// Eye color categories shared by IrisPlexCircuit and EyeColorTrait.
+const (
+	EyeColorBrown        = 1
+	EyeColorIntermediate = 2
+	EyeColorBlue         = 3
+)
+
+// EyeColorName returns the display name of an eye color category.
+func EyeColorName(color int) string {
+	switch color {
+	case EyeColorBrown:
+		return "brown"
+	case EyeColorIntermediate:
+		return "intermediate"
+	case EyeColorBlue:
+		return "blue"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseEyeColor parses an eye color category name, as EyeColorName
+// returns it.
+func ParseEyeColor(s string) (int, error) {
+	for _, color := range []int{EyeColorBrown, EyeColorIntermediate, EyeColorBlue} {
+		if strings.EqualFold(s, EyeColorName(color)) {
+			return color, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown eye color %q (expected brown, intermediate or blue)", s)
+}
+
+// IrisPlexCircuit predicts the IrisPlex eye color category from private
+// allele counts and exposes only the predicted category.
+type IrisPlexCircuit struct {
+	Color frontend.Variable `gnark:",public"`
+
+	Counts [6]frontend.Variable
+}
+
+func (c *IrisPlexCircuit) Define(api frontend.API) error {
+	blue := frontend.Variable(irisPlexOffset + irisPlexBlueIntercept)
+	intermediate := frontend.Variable(irisPlexOffset + irisPlexIntermediateIntercept)
+	brown := frontend.Variable(irisPlexOffset)
+
+	for i, snp := range IrisPlexSNPs {
+		assertIsDosage(api, c.Counts[i])
+		blue = api.Add(blue, api.Mul(c.Counts[i], snp.Blue))
+		intermediate = api.Add(intermediate, api.Mul(c.Counts[i], snp.Intermediate))
+	}
+
+	// The softmax is monotonic, so the most probable category is the one
+	// with the largest logit. Ties favour blue, then intermediate.
+	isBlue := api.And(isGreater(api, blue, brown), api.Sub(1, isGreater(api, intermediate, blue)))
+	isIntermediate := api.And(api.Sub(1, isBlue), isGreater(api, intermediate, brown))
+	isBrown := api.Sub(1, api.Or(isBlue, isIntermediate))
+
+	color := api.Add(
+		api.Mul(isBlue, EyeColorBlue),
+		api.Mul(isIntermediate, EyeColorIntermediate),
+		api.Mul(isBrown, EyeColorBrown),
+	)
+	api.AssertIsEqual(color, c.Color)
+
+	return nil
+}
+
+// isGreater returns 1 when a > b and 0 otherwise.
+func isGreater(api frontend.API, a, b frontend.Variable) frontend.Variable {
+	return api.IsZero(api.Sub(api.Cmp(a, b), 1))
+}
+
+// predictIrisPlex evaluates the same fixed-point model outside the circuit.
+func predictIrisPlex(counts [6]int) int {
+	blue := irisPlexOffset + irisPlexBlueIntercept
+	intermediate := irisPlexOffset + irisPlexIntermediateIntercept
+	brown := irisPlexOffset
+	for i, snp := range IrisPlexSNPs {
+		blue += counts[i] * snp.Blue
+		intermediate += counts[i] * snp.Intermediate
+	}
+
+	switch {
+	case blue > brown && intermediate <= blue:
+		return EyeColorBlue
+	case intermediate > brown:
+		return EyeColorIntermediate
+	default:
+		return EyeColorBrown
+	}
+}
+
+// irisPlexCounts returns the counted-allele copies for every IrisPlex SNP.
+// SNPs absent from the VCF are taken as homozygous reference.
+func irisPlexCounts(genotypes map[Locus]Genotype) ([6]int, error) {
+	var counts [6]int
+	for i, snp := range IrisPlexSNPs {
+		genotype, ok := genotypes[Locus{snp.Chromosome, snp.Position}]
+		if !ok {
+			fmt.Printf("%s (%s) not in VCF, assuming homozygous reference\n", snp.RSID, snp.Gene)
+			genotype = Genotype{Ref: snp.Ref, Alleles: []int{0, 0}}
+		}
+		if genotype.Ref != snp.Ref {
+			return counts, fmt.Errorf("%s reference allele is %s, expected %s", snp.RSID, genotype.Ref, snp.Ref)
+		}
+		if len(genotype.Alleles) != 2 || slices.Contains(genotype.Alleles, -1) {
+			return counts, fmt.Errorf("%s genotype is missing or not diploid", snp.RSID)
+		}
+
+		alt := genotype.Carries(snp.Alt)
+		if snp.Counted == snp.Ref {
+			counts[i] = 2 - alt
+		} else {
+			counts[i] = alt
+		}
+	}
+	return counts, nil
+}
+
+func (p *IrisPlexProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	specs := make([]VariantSpec, len(IrisPlexSNPs))
+	for i, snp := range IrisPlexSNPs {
+		specs[i] = snp.Spec()
+	}
+
+	fmt.Println("Reading IrisPlex SNPs from VCF...")
+	genotypes, err := extractGenotypes(vcfPath, p.Settings, specs)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	counts, err := irisPlexCounts(genotypes)
+	if err != nil {
+		return err
+	}
+	color := predictIrisPlex(counts)
+
+	assignment := &IrisPlexCircuit{Color: color}
+	for i, n := range counts {
+		assignment.Counts[i] = n
+	}
+
+	if err := p.Settings.prove(ctx, "irisplex", &IrisPlexCircuit{}, assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("irisplex", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven the IrisPlex predicted eye color is %s\n", EyeColorName(color))
+	fmt.Println("without revealing the genotypes at any of the six IrisPlex SNPs.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *IrisPlexProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 1 {
+		return false, withKind(ErrVerification, fmt.Errorf("irisplex proof has %d public inputs, expected 1", len(public)))
+	}
+	color := int(public[0].Int64())
+	fmt.Printf("Predicted eye color: %s\n", EyeColorName(color))
+
+	if p.Color != "" {
+		want, err := ParseEyeColor(p.Color)
+		if err != nil {
+			return false, withKind(ErrInvalidInput, err)
+		}
+		if color != want {
+			return false, withKind(ErrVerification, fmt.Errorf("proof predicts %s eyes, not %s", EyeColorName(color), EyeColorName(want)))
+		}
+	}
+	return verified, nil
+}
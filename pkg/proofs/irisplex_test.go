@@ -0,0 +1,73 @@
+package proofs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestIrisPlexCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+
+	tests := []struct {
+		name   string
+		counts [6]int
+		want   int
+	}{
+		// rs12913832 GG with no darkening alleles is the classic blue profile.
+		{"blue", [6]int{0, 0, 0, 0, 0, 0}, EyeColorBlue},
+		{"brown", [6]int{2, 0, 0, 2, 0, 0}, EyeColorBrown},
+		{"intermediate", [6]int{1, 0, 0, 0, 0, 2}, EyeColorIntermediate},
+	}
+
+	for _, tt := range tests {
+		if got := predictIrisPlex(tt.counts); got != tt.want {
+			t.Errorf("%s: predicted color = %d, want %d", tt.name, got, tt.want)
+		}
+
+		for color := EyeColorBrown; color <= EyeColorBlue; color++ {
+			assignment := &IrisPlexCircuit{Color: color}
+			for i, n := range tt.counts {
+				assignment.Counts[i] = n
+			}
+
+			err := test.IsSolved(&IrisPlexCircuit{}, assignment, field)
+			if color == tt.want && err != nil {
+				t.Errorf("%s: predicted color %d should satisfy the circuit: %v", tt.name, color, err)
+			}
+			if color != tt.want && err == nil {
+				t.Errorf("%s: color %d should not satisfy the circuit", tt.name, color)
+			}
+		}
+	}
+}
+
+func TestIrisPlexProofChecksColor(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+5	33951693	rs16891982	C	G	60	PASS	.	GT	1/1
+6	396321	rs12203592	C	T	60	PASS	.	GT	0/0
+11	89011046	rs1393350	G	A	60	PASS	.	GT	0/1
+14	92773663	rs12896399	G	T	60	PASS	.	GT	1/1
+15	28230318	rs1800407	C	T	60	PASS	.	GT	0/0
+15	28365618	rs12913832	A	G	60	PASS	.	GT	1/1
+`)
+	out := filepath.Join(t.TempDir(), "irisplex_proof.bin")
+	if err := (&IrisPlexProof{}).Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	if ok, err := (&IrisPlexProof{Color: "Blue"}).Verify(t.Context(), out+".vk", out); !ok || err != nil {
+		t.Errorf("Verify for the predicted color = %v, %v", ok, err)
+	}
+	if ok, err := (&IrisPlexProof{Color: "brown"}).Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+		t.Errorf("Verify for another color = %v, %v; want ErrVerification", ok, err)
+	}
+	if ok, err := (&IrisPlexProof{Color: "green"}).Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Verify for an unknown color = %v, %v; want ErrInvalidInput", ok, err)
+	}
+}
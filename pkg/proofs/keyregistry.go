@@ -0,0 +1,198 @@
+package proofs
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// KeyRegistryContract is the Solidity source of the verifying key registry
+// a KeyRegistry reads and publishes to. It maps circuit hashes, which
+// change with any change to a circuit, to the fingerprints of the
+// verifying keys set up for them. A mapping is published once, by the
+// contract's owner, and never replaced, so taking over the owner's account
+// cannot swap the key of a circuit verifiers already trust.
+const KeyRegistryContract = `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+// VerifyingKeyRegistry maps the circuit hash of a vcf-proof circuit to the
+// SHA-256 fingerprint of the verifying key set up for it.
+contract VerifyingKeyRegistry {
+    address public immutable owner;
+    mapping(bytes32 => bytes32) public verifyingKeys;
+
+    event VerifyingKeyPublished(bytes32 indexed circuit, bytes32 fingerprint);
+
+    constructor() {
+        owner = msg.sender;
+    }
+
+    function publish(bytes32 circuit, bytes32 fingerprint) external {
+        require(msg.sender == owner, "not the registry owner");
+        require(fingerprint != bytes32(0), "empty fingerprint");
+        require(verifyingKeys[circuit] == bytes32(0), "circuit already published");
+        verifyingKeys[circuit] = fingerprint;
+        emit VerifyingKeyPublished(circuit, fingerprint);
+    }
+}
+`
+
+// KeyRegistry reads and publishes the verifying keys of circuits in a
+// VerifyingKeyRegistry contract, deployed from KeyRegistryContract, through
+// an Ethereum JSON-RPC endpoint.
+type KeyRegistry struct {
+	// RPC is the URL of the JSON-RPC endpoint of a node of the chain the
+	// registry is deployed on.
+	RPC string
+	// Address is the 0x-prefixed address of the registry contract.
+	Address string
+	// Client makes the requests; nil means http.DefaultClient.
+	Client *http.Client
+}
+
+// rpcID numbers JSON-RPC requests.
+var rpcID atomic.Int64
+
+// Lookup returns the fingerprint of the verifying key the registry holds
+// for the circuit with hex hash circuitHash.
+func (r *KeyRegistry) Lookup(ctx context.Context, circuitHash string) (string, error) {
+	fingerprint, err := r.lookup(ctx, circuitHash)
+	if err == nil && fingerprint == "" {
+		return "", withKind(ErrNotFound, fmt.Errorf("key registry %s holds no verifying key for circuit %s", r.Address, circuitHash))
+	}
+	return fingerprint, err
+}
+
+// lookup is Lookup, returning an empty fingerprint for a circuit the
+// registry does not hold.
+func (r *KeyRegistry) lookup(ctx context.Context, circuitHash string) (string, error) {
+	circuit, err := registryWord(circuitHash)
+	if err != nil {
+		return "", withKind(ErrInvalidInput, fmt.Errorf("circuit hash: %w", err))
+	}
+	call := map[string]string{"to": r.Address, "data": "0x" + hex.EncodeToString(append(abiSelector("verifyingKeys(bytes32)"), circuit...))}
+	var result string
+	if err := r.call(ctx, "eth_call", []any{call, "latest"}, &result); err != nil {
+		return "", err
+	}
+	word, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+	if err != nil || len(word) != 32 {
+		return "", fmt.Errorf("key registry returned %q, not a bytes32", result)
+	}
+	if bytes.Equal(word, make([]byte, 32)) {
+		return "", nil
+	}
+	return hex.EncodeToString(word), nil
+}
+
+// CheckVerifyingKey checks that the verifying key with the given
+// fingerprint is the one the registry holds for the circuit with hex hash
+// circuitHash.
+func (r *KeyRegistry) CheckVerifyingKey(ctx context.Context, circuitHash, fingerprint string) error {
+	if circuitHash == "" {
+		return withKind(ErrVerification, errors.New("the proof names no circuit to look up in the key registry"))
+	}
+	published, err := r.lookup(ctx, circuitHash)
+	if err != nil {
+		return err
+	}
+	if published == "" {
+		return withKind(ErrVerification, fmt.Errorf("key registry %s holds no verifying key for circuit %s", r.Address, circuitHash))
+	}
+	if !strings.EqualFold(published, fingerprint) {
+		return withKind(ErrVerification, fmt.Errorf("the verifying key %s is not the key %s the registry holds for circuit %s", fingerprint, published, circuitHash))
+	}
+	return nil
+}
+
+// PublishCalldata returns the calldata of the registry transaction
+// publishing the verifying key with the given fingerprint for the circuit
+// with hex hash circuitHash, for sending from the owner's wallet.
+func PublishCalldata(circuitHash, fingerprint string) ([]byte, error) {
+	circuit, err := registryWord(circuitHash)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("circuit hash: %w", err))
+	}
+	vk, err := registryWord(fingerprint)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("key fingerprint: %w", err))
+	}
+	calldata := abiSelector("publish(bytes32,bytes32)")
+	calldata = append(calldata, circuit...)
+	return append(calldata, vk...), nil
+}
+
+// Publish sends the registry transaction publishing the verifying key with
+// the given fingerprint for the circuit with hex hash circuitHash from the
+// account from, which the node must hold unlocked, and returns the
+// transaction hash. Nodes that hold no accounts take the transaction signed
+// elsewhere from PublishCalldata.
+func (r *KeyRegistry) Publish(ctx context.Context, from, circuitHash, fingerprint string) (string, error) {
+	calldata, err := PublishCalldata(circuitHash, fingerprint)
+	if err != nil {
+		return "", err
+	}
+	tx := map[string]string{"from": from, "to": r.Address, "data": "0x" + hex.EncodeToString(calldata)}
+	var hash string
+	if err := r.call(ctx, "eth_sendTransaction", []any{tx}, &hash); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// call makes the JSON-RPC call method with params and decodes its result
+// into result.
+func (r *KeyRegistry) call(ctx context.Context, method string, params []any, result any) error {
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": rpcID.Add(1), "method": method, "params": params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.RPC, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("key registry %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("key registry %s: node answered %s", method, resp.Status)
+	}
+	var reply struct {
+		Result json.RawMessage
+		Error  *struct {
+			Code    int
+			Message string
+		}
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&reply); err != nil {
+		return fmt.Errorf("key registry %s: reading response: %w", method, err)
+	}
+	if reply.Error != nil {
+		return fmt.Errorf("key registry %s: %s (code %d)", method, reply.Error.Message, reply.Error.Code)
+	}
+	return json.Unmarshal(reply.Result, result)
+}
+
+// registryWord decodes a hex SHA-256, such as a circuit hash or key
+// fingerprint, into a bytes32 word.
+func registryWord(s string) ([]byte, error) {
+	word, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(word) != 32 {
+		return nil, fmt.Errorf("%q is not a hex SHA-256", s)
+	}
+	return word, nil
+}
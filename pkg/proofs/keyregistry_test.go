@@ -0,0 +1,139 @@
+package proofs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeChain serves the JSON-RPC calls a KeyRegistry makes to a node
+// running a VerifyingKeyRegistry contract owned by owner.
+type fakeChain struct {
+	mu    sync.Mutex
+	owner string
+	keys  map[string][]byte
+}
+
+func (f *fakeChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var req struct {
+		ID     json.RawMessage
+		Method string
+		Params []json.RawMessage
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Params) == 0 {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	var tx struct{ From, Data string }
+	json.Unmarshal(req.Params[0], &tx)
+	data, _ := hex.DecodeString(strings.TrimPrefix(tx.Data, "0x"))
+	reply := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+	switch {
+	case req.Method == "eth_call" && len(data) == 36 && bytes.Equal(data[:4], abiSelector("verifyingKeys(bytes32)")):
+		word := make([]byte, 32)
+		copy(word, f.keys[hex.EncodeToString(data[4:])])
+		reply["result"] = "0x" + hex.EncodeToString(word)
+	case req.Method == "eth_sendTransaction" && len(data) == 68 && bytes.Equal(data[:4], abiSelector("publish(bytes32,bytes32)")):
+		circuit := hex.EncodeToString(data[4:36])
+		if tx.From != f.owner || f.keys[circuit] != nil {
+			reply["error"] = map[string]any{"code": 3, "message": "execution reverted"}
+			break
+		}
+		f.keys[circuit] = data[36:]
+		reply["result"] = "0x" + strings.Repeat("ab", 32)
+	default:
+		reply["error"] = map[string]any{"code": -32601, "message": "method not found"}
+	}
+	json.NewEncoder(w).Encode(reply)
+}
+
+func TestKeyRegistry(t *testing.T) {
+	const owner = "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"
+	server := httptest.NewServer(&fakeChain{owner: owner, keys: map[string][]byte{}})
+	defer server.Close()
+	registry := &KeyRegistry{RPC: server.URL, Address: "0x5fbdb2315678afecb367f032d93f642f64180aa3"}
+	circuit, fingerprint := strings.Repeat("01", 32), strings.Repeat("02", 32)
+
+	if _, err := registry.Lookup(t.Context(), circuit); !errors.Is(ErrorKind(err), ErrNotFound) {
+		t.Errorf("Lookup of an unpublished circuit = %v, want a not found error", err)
+	}
+	if err := registry.CheckVerifyingKey(t.Context(), circuit, fingerprint); !errors.Is(ErrorKind(err), ErrVerification) {
+		t.Errorf("CheckVerifyingKey of an unpublished circuit = %v, want a verification error", err)
+	}
+	if _, err := registry.Publish(t.Context(), "0x0000000000000000000000000000000000000001", circuit, fingerprint); err == nil {
+		t.Errorf("Publish from another account than the owner succeeded")
+	}
+	if _, err := registry.Publish(t.Context(), owner, circuit, fingerprint); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if got, err := registry.Lookup(t.Context(), circuit); err != nil || got != fingerprint {
+		t.Errorf("Lookup = %s, %v; want %s", got, err, fingerprint)
+	}
+	if err := registry.CheckVerifyingKey(t.Context(), circuit, strings.ToUpper(fingerprint)); err != nil {
+		t.Errorf("CheckVerifyingKey with the published key: %v", err)
+	}
+	if err := registry.CheckVerifyingKey(t.Context(), circuit, strings.Repeat("03", 32)); !errors.Is(ErrorKind(err), ErrVerification) {
+		t.Errorf("CheckVerifyingKey with another key = %v, want a verification error", err)
+	}
+	// A published circuit keeps its key.
+	if _, err := registry.Publish(t.Context(), owner, circuit, strings.Repeat("03", 32)); err == nil {
+		t.Errorf("Publish replaced a published key")
+	}
+	if _, err := PublishCalldata("not hex", fingerprint); !errors.Is(ErrorKind(err), ErrInvalidInput) {
+		t.Errorf("PublishCalldata of an invalid circuit hash = %v, want an invalid input error", err)
+	}
+}
+
+func TestVerifyWithKeyRegistry(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	const owner = "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"
+	server := httptest.NewServer(&fakeChain{owner: owner, keys: map[string][]byte{}})
+	defer server.Close()
+	registry := &KeyRegistry{RPC: server.URL, Address: "0x5fbdb2315678afecb367f032d93f642f64180aa3"}
+
+	dir := t.TempDir()
+	proofPath := filepath.Join(dir, "proof.bin")
+	if err := Generate(t.Context(), "zygosity", vcf, proofPath, WithPosition("15:28365618")); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := Verify(t.Context(), proofPath, WithKeyRegistry(registry)); !errors.Is(ErrorKind(err), ErrVerification) {
+		t.Errorf("Verify of a circuit the registry does not hold = %v, want a verification error", err)
+	}
+
+	header, err := ReadProofHeader(proofPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err := KeyFingerprint(proofPath + ".vk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.Publish(t.Context(), owner, header.CircuitHash, fingerprint); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if _, err := Verify(t.Context(), proofPath, WithKeyRegistry(registry)); err != nil {
+		t.Errorf("Verify with the registered key: %v", err)
+	}
+
+	// A key beside the proof is not trusted for being there.
+	if err := os.WriteFile(proofPath+".vk", []byte("another key"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(t.Context(), proofPath, WithKeyRegistry(registry)); !errors.Is(ErrorKind(err), ErrVerification) {
+		t.Errorf("Verify with an unregistered key = %v, want a verification error", err)
+	}
+}
@@ -0,0 +1,389 @@
+package proofs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark/constraint"
+)
+
+// KeyInfoSuffix is appended to a key path to locate the description
+// SetupKeys writes beside its .pk and .vk files.
+const KeyInfoSuffix = ".keys.json"
+
+// bundleManifest is the name of the manifest inside a key bundle.
+const bundleManifest = "bundle.json"
+
+// KeyInfo describes the circuit a key pair was set up for.
+type KeyInfo struct {
+	ProofType string      `json:"proof_type"`
+	Backend   BackendName `json:"backend"`
+	Curve     Curve       `json:"curve"`
+	// CircuitHash is the SHA-256 of the compiled constraint system, which
+	// changes with any change to the circuit, so keys set up for another
+	// version of a circuit can be told apart.
+	CircuitHash string    `json:"circuit_hash"`
+	Constraints int       `json:"constraints"`
+	Nullifier   bool      `json:"nullifier,omitempty"`
+	Nonce       bool      `json:"nonce,omitempty"`
+	DigestBound bool      `json:"dataset_digest_bound,omitempty"`
+	Timestamp   bool      `json:"timestamp,omitempty"`
+	Expiry      bool      `json:"expiry,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func writeKeyInfo(keyPath string, info KeyInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding key info: %w", err)
+	}
+	if err := writeFileAtomic(keyPath+KeyInfoSuffix, 0644, writeBytes(data)); err != nil {
+		return fmt.Errorf("writing key info: %w", err)
+	}
+	return nil
+}
+
+// ReadKeyInfo reads the description written next to the keys at keyPath.
+func ReadKeyInfo(keyPath string) (*KeyInfo, error) {
+	data, err := readFile(keyPath + KeyInfoSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("reading key info: %w", err)
+	}
+	var info KeyInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing key info: %w", err)
+	}
+	return &info, nil
+}
+
+// keyRecord is the circuit and key files a proof was generated with.
+type keyRecord struct {
+	circuitHash  string
+	provingKey   string
+	verifyingKey string
+}
+
+// record fingerprints cs and the key files proving it used: the proving
+// key at provingKeyPath and the verifying key beside it, or the pair
+// set up at outputPath. Fingerprints ks holds are not computed again;
+// those that cannot be read are left empty.
+func (r *keyRecord) record(cs constraint.ConstraintSystem, ks *KeyStore, provingKeyPath, outputPath string) {
+	if h, err := circuitHash(cs); err == nil {
+		r.circuitHash = hex.EncodeToString(h)
+	}
+	keyPath := strings.TrimSuffix(provingKeyPath, ".pk")
+	if provingKeyPath == "" {
+		keyPath, provingKeyPath = outputPath, outputPath+".pk"
+	}
+	r.provingKey, _ = ks.fingerprint(provingKeyPath)
+	r.verifyingKey, _ = ks.fingerprint(keyPath + ".vk")
+}
+
+// checkKeyCircuit rejects a proving key whose description, when setup wrote
+// one, is for another circuit than cs; proving with it would fail deep in
+// the backend.
+func checkKeyCircuit(cs constraint.ConstraintSystem, provingKeyPath string) error {
+	info, err := ReadKeyInfo(strings.TrimSuffix(provingKeyPath, ".pk"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	h, err := circuitHash(cs)
+	if err != nil {
+		return err
+	}
+	if circuit := hex.EncodeToString(h); circuit != info.CircuitHash {
+		return fmt.Errorf("proving key %s was set up for circuit %s, not this proof's %s; set up keys with the same options, including the nullifier, nonce and digest", provingKeyPath, info.CircuitHash, circuit)
+	}
+	return nil
+}
+
+// KeyFingerprint returns the hex SHA-256 of the key file at path. Comparing
+// a verifying key's fingerprint with the published one shows a verifier has
+// the canonical key.
+func KeyFingerprint(path string) (string, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return "", fmt.Errorf("opening key file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hashing key file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// bytesFingerprint returns the KeyFingerprint of a key file's contents.
+func bytesFingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// KeyPair is a key pair in a key directory, named by the path of its key
+// files without their extensions. Either key may be missing: verifiers
+// only hold verifying keys.
+type KeyPair struct {
+	Name string   `json:"name"`
+	PK   string   `json:"pk_sha256,omitempty"`
+	VK   string   `json:"vk_sha256,omitempty"`
+	Info *KeyInfo `json:"info,omitempty"`
+}
+
+// ListKeys returns the key pairs in dir, sorted by name, with the
+// fingerprints of their key files and the description SetupKeys wrote, if
+// any.
+func ListKeys(dir string) ([]KeyPair, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading key directory: %w", err)
+	}
+
+	pairs := map[string]*KeyPair{}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".pk" && ext != ".vk") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ext)
+		pair, ok := pairs[name]
+		if !ok {
+			pair = &KeyPair{Name: name}
+			pairs[name] = pair
+		}
+		fingerprint, err := KeyFingerprint(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if ext == ".pk" {
+			pair.PK = fingerprint
+		} else {
+			pair.VK = fingerprint
+		}
+	}
+
+	var keys []KeyPair
+	for name, pair := range pairs {
+		if info, err := ReadKeyInfo(filepath.Join(dir, name)); err == nil {
+			pair.Info = info
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		keys = append(keys, *pair)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys, nil
+}
+
+// keyBundle is the manifest of a key bundle, listing its key pairs.
+type keyBundle struct {
+	CreatedAt time.Time `json:"created_at"`
+	Keys      []KeyPair `json:"keys"`
+}
+
+// ExportKeys writes the named key pairs in dir (all of them when names is
+// empty) to a gzip-compressed tar bundle at bundlePath, with a manifest of
+// their fingerprints that ImportKeys checks. With verifyingOnly the
+// proving keys are left out, for bundles handed to verifiers.
+func ExportKeys(dir string, names []string, bundlePath string, verifyingOnly bool) ([]KeyPair, error) {
+	keys, err := ListKeys(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) > 0 {
+		byName := map[string]KeyPair{}
+		for _, pair := range keys {
+			byName[pair.Name] = pair
+		}
+		keys = keys[:0]
+		for _, name := range names {
+			pair, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("no keys named %s in %s", name, dir)
+			}
+			keys = append(keys, pair)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys in %s", dir)
+	}
+	for i := range keys {
+		if verifyingOnly {
+			keys[i].PK = ""
+		}
+		if keys[i].VK == "" {
+			return nil, fmt.Errorf("%s has no verifying key", keys[i].Name)
+		}
+	}
+
+	out, err := os.Create(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("creating key bundle: %w", err)
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.MarshalIndent(keyBundle{CreatedAt: time.Now().UTC(), Keys: keys}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding key bundle manifest: %w", err)
+	}
+	if err := writeTarEntry(tw, bundleManifest, bytes.NewReader(manifest), int64(len(manifest))); err != nil {
+		return nil, err
+	}
+	for _, pair := range keys {
+		files := []string{pair.Name + ".vk"}
+		if pair.PK != "" {
+			files = append(files, pair.Name+".pk")
+		}
+		if pair.Info != nil {
+			files = append(files, pair.Name+KeyInfoSuffix)
+		}
+		for _, name := range files {
+			if err := addTarFile(tw, filepath.Join(dir, name), name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("writing key bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("writing key bundle: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return nil, fmt.Errorf("writing key bundle: %w", err)
+	}
+	return keys, nil
+}
+
+func addTarFile(tw *tar.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return writeTarEntry(tw, name, f, stat.Size())
+}
+
+func writeTarEntry(tw *tar.Writer, name string, r io.Reader, size int64) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: size, ModTime: time.Now()}); err != nil {
+		return fmt.Errorf("writing key bundle: %w", err)
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		return fmt.Errorf("writing key bundle: %w", err)
+	}
+	return nil
+}
+
+// ImportKeys unpacks a bundle written by ExportKeys into dir, after
+// checking every key file against the fingerprint in its manifest. Key
+// files already in dir are only replaced by identical ones, so an import
+// never silently swaps a verifying key.
+func ImportKeys(bundlePath string, dir string) ([]KeyPair, error) {
+	in, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening key bundle: %w", err)
+	}
+	defer in.Close()
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, fmt.Errorf("reading key bundle: %w", err)
+	}
+	defer gz.Close()
+
+	var manifest *keyBundle
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading key bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Name != path.Base(hdr.Name) {
+			return nil, fmt.Errorf("key bundle entry %q is not a plain file name", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading key bundle: %w", err)
+		}
+		if hdr.Name == bundleManifest {
+			manifest = &keyBundle{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, fmt.Errorf("parsing key bundle manifest: %w", err)
+			}
+			continue
+		}
+		files[hdr.Name] = data
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("key bundle has no %s", bundleManifest)
+	}
+
+	// Check the whole bundle before writing any of it.
+	expected := map[string]string{}
+	for _, pair := range manifest.Keys {
+		if pair.Name != path.Base(pair.Name) || pair.Name == "." || pair.Name == ".." {
+			return nil, fmt.Errorf("key bundle names keys %q", pair.Name)
+		}
+		expected[pair.Name+".vk"] = pair.VK
+		if pair.PK != "" {
+			expected[pair.Name+".pk"] = pair.PK
+		}
+		if pair.Info != nil {
+			expected[pair.Name+KeyInfoSuffix] = ""
+		}
+	}
+	for name, fingerprint := range expected {
+		data, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("key bundle is missing %s", name)
+		}
+		sum := sha256.Sum256(data)
+		if fingerprint != "" && hex.EncodeToString(sum[:]) != fingerprint {
+			return nil, fmt.Errorf("%s does not match its fingerprint %s", name, fingerprint)
+		}
+		if existing, err := os.ReadFile(filepath.Join(dir, name)); err == nil && !bytes.Equal(existing, data) {
+			return nil, fmt.Errorf("%s already exists in %s with other contents", name, dir)
+		}
+	}
+	for name := range files {
+		if _, ok := expected[name]; !ok {
+			return nil, fmt.Errorf("key bundle holds %s, which its manifest does not list", name)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating key directory: %w", err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	return manifest.Keys, nil
+}
@@ -0,0 +1,76 @@
+package proofs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyBundle(t *testing.T) {
+	dir := t.TempDir()
+	if err := SetupKeys(&ZygosityProof{}, filepath.Join(dir, "zygosity"), SetupOptions{Nonce: true}); err != nil {
+		t.Fatalf("SetupKeys: %v", err)
+	}
+	// Keys Generate set up next to a proof have no description.
+	if err := os.WriteFile(filepath.Join(dir, "proof.bin.vk"), []byte("vk"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := ListKeys(dir)
+	if err != nil {
+		t.Fatalf("ListKeys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].Name != "proof.bin" || keys[1].Name != "zygosity" {
+		t.Fatalf("ListKeys = %+v, want proof.bin and zygosity", keys)
+	}
+	if keys[0].PK != "" || keys[0].Info != nil {
+		t.Errorf("proof.bin listed as %+v, want only a verifying key", keys[0])
+	}
+	zygosity := keys[1]
+	if info := zygosity.Info; info == nil || info.ProofType != "zygosity" || !info.Nonce || len(info.CircuitHash) != 64 || info.Constraints == 0 {
+		t.Errorf("zygosity key info = %+v", info)
+	}
+	if fp, err := KeyFingerprint(filepath.Join(dir, "zygosity.vk")); err != nil || fp != zygosity.VK {
+		t.Errorf("KeyFingerprint = %s, %v; listed %s", fp, err, zygosity.VK)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "keys.tar.gz")
+	if _, err := ExportKeys(dir, []string{"zygosity"}, bundle, false); err != nil {
+		t.Fatalf("ExportKeys: %v", err)
+	}
+	imported := t.TempDir()
+	if _, err := ImportKeys(bundle, imported); err != nil {
+		t.Fatalf("ImportKeys: %v", err)
+	}
+	got, err := ListKeys(imported)
+	if err != nil || len(got) != 1 || got[0].PK != zygosity.PK || got[0].VK != zygosity.VK || got[0].Info == nil {
+		t.Fatalf("imported keys = %+v, %v; want %+v", got, err, zygosity)
+	}
+	// Importing the same bundle again changes nothing.
+	if _, err := ImportKeys(bundle, imported); err != nil {
+		t.Errorf("re-import: %v", err)
+	}
+
+	verifiers := filepath.Join(t.TempDir(), "verifier.tar.gz")
+	if _, err := ExportKeys(dir, nil, verifiers, true); err != nil {
+		t.Fatalf("ExportKeys verifying only: %v", err)
+	}
+	other := t.TempDir()
+	if _, err := ImportKeys(verifiers, other); err != nil {
+		t.Fatalf("ImportKeys: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(other, "zygosity.pk")); !os.IsNotExist(err) {
+		t.Errorf("verifying-only bundle carried a proving key")
+	}
+
+	// A different key of the same name is not overwritten.
+	if err := os.WriteFile(filepath.Join(other, "zygosity.vk"), []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportKeys(verifiers, other); err == nil {
+		t.Error("ImportKeys replaced a different verifying key")
+	}
+	if _, err := ExportKeys(dir, []string{"missing"}, bundle, false); err == nil {
+		t.Error("ExportKeys accepted an unknown key name")
+	}
+}
@@ -0,0 +1,190 @@
+package proofs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultKeyStoreSize is the number of keys a KeyStore from NewKeyStore
+// holds.
+const DefaultKeyStoreSize = 64
+
+// A KeyStore holds the proving and verifying keys read from key files, so
+// Generate and Verify calls sharing it through Settings.KeyStore parse each
+// key file once rather than on every call. It is safe for concurrent use,
+// as by a proof server proving and verifying many requests with the same
+// keys. A key file replaced on disk, as by a forced setup, is read again.
+// Keys held in memory, as given by WithProvingKeyData, are never stored.
+type KeyStore struct {
+	// Size bounds the keys held; the least recently used key is dropped
+	// to make room for another.
+	Size int
+
+	mu   sync.Mutex
+	keys map[storedKeyID]*storedKey
+	tick uint64
+}
+
+// storedKeyID identifies a key by its file, the version of the file it was
+// read from and how it was parsed.
+type storedKeyID struct {
+	path    string
+	size    int64
+	modTime time.Time
+	proving bool
+	backend BackendName
+	curve   Curve
+}
+
+// storedKey is a key read once.
+type storedKey struct {
+	once        sync.Once
+	key         Serializable
+	fingerprint string
+	err         error
+	used        uint64
+}
+
+// NewKeyStore returns an empty KeyStore holding up to
+// DefaultKeyStoreSize keys.
+func NewKeyStore() *KeyStore {
+	return &KeyStore{Size: DefaultKeyStoreSize}
+}
+
+// ProvingKey returns the proving key for backend over curve in the file at
+// path, reading it unless the store holds it.
+func (ks *KeyStore) ProvingKey(path string, backend BackendName, curve Curve) (Serializable, error) {
+	return ks.load(path, true, backend, curve)
+}
+
+// VerifyingKey returns the verifying key for backend over curve in the file
+// at path, reading it unless the store holds it.
+func (ks *KeyStore) VerifyingKey(path string, backend BackendName, curve Curve) (Serializable, error) {
+	return ks.load(path, false, backend, curve)
+}
+
+// Len returns the number of keys the store holds.
+func (ks *KeyStore) Len() int {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	return len(ks.keys)
+}
+
+// load returns the key in the file at path, read through the store. A nil
+// store, and a key held in memory, are read directly. Concurrent loads of
+// one key wait for a single read; a failed read is not kept, so the next
+// load tries again.
+func (ks *KeyStore) load(path string, proving bool, backend BackendName, curve Curve) (Serializable, error) {
+	b, err := backend.backend()
+	if err != nil {
+		return nil, err
+	}
+	if _, _, ok := memoryPath(path); ks == nil || ok {
+		key, _, err := readKey(path, proving, b, curve)
+		return key, err
+	}
+	entry, id, err := ks.entry(path, proving, backend, curve)
+	if err != nil {
+		return nil, err
+	}
+	entry.once.Do(func() {
+		entry.key, entry.fingerprint, entry.err = readKey(path, proving, b, curve)
+	})
+	if entry.err != nil {
+		ks.mu.Lock()
+		if ks.keys[id] == entry {
+			delete(ks.keys, id)
+		}
+		ks.mu.Unlock()
+	}
+	return entry.key, entry.err
+}
+
+// fingerprint returns the KeyFingerprint of the key file at path, from the
+// store when it holds the file's current version.
+func (ks *KeyStore) fingerprint(path string) (string, error) {
+	if ks == nil {
+		return KeyFingerprint(path)
+	}
+	info, err := statFile(path)
+	if err != nil {
+		return "", fmt.Errorf("opening key file: %w", err)
+	}
+	ks.mu.Lock()
+	for id, entry := range ks.keys {
+		if id.path == path && id.size == info.Size() && id.modTime.Equal(info.ModTime()) && entry.fingerprint != "" {
+			ks.mu.Unlock()
+			return entry.fingerprint, nil
+		}
+	}
+	ks.mu.Unlock()
+	return KeyFingerprint(path)
+}
+
+// entry returns the store's entry for the current version of the key file
+// at path, adding an unread one when it has none.
+func (ks *KeyStore) entry(path string, proving bool, backend BackendName, curve Curve) (*storedKey, storedKeyID, error) {
+	info, err := statFile(path)
+	if err != nil {
+		return nil, storedKeyID{}, fmt.Errorf("opening key file: %w", err)
+	}
+	id := storedKeyID{path: path, size: info.Size(), modTime: info.ModTime(), proving: proving, backend: backend, curve: curve}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.keys == nil {
+		ks.keys = make(map[storedKeyID]*storedKey)
+	}
+	ks.tick++
+	if entry, ok := ks.keys[id]; ok {
+		entry.used = ks.tick
+		return entry, id, nil
+	}
+	for len(ks.keys) >= max(ks.Size, 1) {
+		ks.evict()
+	}
+	entry := &storedKey{used: ks.tick}
+	ks.keys[id] = entry
+	return entry, id, nil
+}
+
+// evict drops the least recently used key.
+func (ks *KeyStore) evict() {
+	var oldest storedKeyID
+	var used uint64
+	first := true
+	for id, entry := range ks.keys {
+		if first || entry.used < used {
+			oldest, used, first = id, entry.used, false
+		}
+	}
+	delete(ks.keys, oldest)
+}
+
+// readKey reads the proving or verifying key for backend over curve from
+// the file at path, returning it with the file's KeyFingerprint.
+func readKey(path string, proving bool, backend Backend, curve Curve) (Serializable, string, error) {
+	kind, key := "verifying", backend.NewVerifyingKey(curve)
+	if proving {
+		kind, key = "proving", backend.NewProvingKey(curve)
+	}
+	f, err := openFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("opening %s key file: %w", kind, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := key.ReadFrom(io.TeeReader(f, h)); err != nil {
+		return nil, "", fmt.Errorf("reading %s key: %w", kind, err)
+	}
+	// The key may not be all of the file.
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, "", fmt.Errorf("reading %s key: %w", kind, err)
+	}
+	return key, hex.EncodeToString(h.Sum(nil)), nil
+}
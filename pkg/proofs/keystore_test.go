@@ -0,0 +1,93 @@
+package proofs
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyStoreConcurrent(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	keys := filepath.Join(dir, "keys")
+	if err := Generate(t.Context(), "zygosity", vcf, keys, WithPosition("15:28365618")); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	ks := NewKeyStore()
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out := filepath.Join(dir, "proof"+string(rune('a'+i)))
+			if errs[i] = Generate(t.Context(), "zygosity", vcf, out, WithPosition("15:28365618"), WithProvingKey(keys+".pk"), WithKeyStore(ks)); errs[i] != nil {
+				return
+			}
+			res, err := Verify(t.Context(), out, WithVerifyingKey(keys+".vk"), WithKeyStore(ks))
+			if err == nil && !res.Valid {
+				t.Errorf("Verify of proof %d is not valid", i)
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("proof %d: %v", i, err)
+		}
+	}
+	if n := ks.Len(); n != 2 {
+		t.Errorf("key store holds %d keys, want the proving and verifying key", n)
+	}
+
+	// A replaced key file is read again rather than served from the store.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(keys+".vk", later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.VerifyingKey(keys+".vk", BackendGroth16, CurveBN254); err != nil {
+		t.Fatalf("VerifyingKey: %v", err)
+	}
+	if n := ks.Len(); n != 3 {
+		t.Errorf("key store holds %d keys after the key file changed, want 3", n)
+	}
+}
+
+func TestKeyStoreEvicts(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	keys := filepath.Join(t.TempDir(), "keys")
+	if err := Generate(t.Context(), "zygosity", vcf, keys, WithPosition("15:28365618")); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	ks := &KeyStore{Size: 1}
+	if _, err := ks.ProvingKey(keys+".pk", BackendGroth16, CurveBN254); err != nil {
+		t.Fatalf("ProvingKey: %v", err)
+	}
+	if _, err := ks.VerifyingKey(keys+".vk", BackendGroth16, CurveBN254); err != nil {
+		t.Fatalf("VerifyingKey: %v", err)
+	}
+	if n := ks.Len(); n != 1 {
+		t.Errorf("key store of size 1 holds %d keys", n)
+	}
+	if _, err := ks.VerifyingKey(keys+".missing", BackendGroth16, CurveBN254); err == nil {
+		t.Errorf("VerifyingKey of a missing file succeeded")
+	}
+	if fp, err := ks.fingerprint(keys + ".vk"); err != nil {
+		t.Errorf("fingerprint: %v", err)
+	} else if want, _ := KeyFingerprint(keys + ".vk"); fp != want {
+		t.Errorf("fingerprint = %s, want %s", fp, want)
+	}
+}
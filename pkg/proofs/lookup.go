@@ -0,0 +1,141 @@
+package proofs
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/brentp/vcfgo"
+)
+
+// How well a record matches a requested variant. Reading stops early once
+// every variant has a record matching its alleles.
+const (
+	matchNone = iota
+	// matchLocus is a record at the variant's locus with other alleles,
+	// kept so callers can report the mismatch.
+	matchLocus
+	// matchAlleles is a record with the variant's ref and alt at its
+	// locus, or a record carrying the variant's rsID.
+	matchAlleles
+)
+
+// lookupTarget is a requested variant and the locus of its normalized form
+// in the VCF, which differs from the variant's own when lifted over.
+type lookupTarget struct {
+	spec    VariantSpec
+	locus   Locus
+	flipped bool
+}
+
+// variantLookup finds the VCF records of requested variants. Records match
+// by rsID when both the request and the record's ID column carry one, which
+// holds across builds and allele representations; otherwise by chromosome
+// and position, preferring a record with the requested ref and alt. Indels
+// compare in their normalized form, so a record that pads an indel or
+// lists it among other ALTs matches too.
+type variantLookup struct {
+	targets []lookupTarget
+	byLocus map[Locus][]int
+	byID    map[string][]int
+}
+
+func newVariantLookup(targets []lookupTarget) *variantLookup {
+	l := &variantLookup{targets: targets, byLocus: map[Locus][]int{}, byID: map[string][]int{}}
+	for i, t := range targets {
+		if t.locus != (Locus{}) {
+			l.byLocus[t.locus] = append(l.byLocus[t.locus], i)
+		}
+		if id := strings.ToLower(t.spec.RSID); id != "" {
+			l.byID[id] = append(l.byID[id], i)
+		}
+	}
+	return l
+}
+
+// lookupSpecs is the lookup of specs on their own build.
+func lookupSpecs(specs []VariantSpec) *variantLookup {
+	targets := make([]lookupTarget, len(specs))
+	for i, spec := range specs {
+		spec.Chromosome = normalizeChromosome(spec.Chromosome)
+		targets[i] = lookupTarget{spec: spec, locus: spec.normalized().Locus()}
+	}
+	return newVariantLookup(targets)
+}
+
+// locusSpecs requests the variants at loci, whatever their alleles.
+func locusSpecs(loci ...Locus) []VariantSpec {
+	specs := make([]VariantSpec, len(loci))
+	for i, l := range loci {
+		specs[i] = VariantSpec{Chromosome: l.Chromosome, Position: l.Position}
+	}
+	return specs
+}
+
+// loci returns the VCF loci of the targets, for reading through an index.
+// Targets that only an rsID can find are left out.
+func (l *variantLookup) loci() []Locus {
+	var loci []Locus
+	for _, t := range l.targets {
+		if t.locus != (Locus{}) && !slices.Contains(loci, t.locus) {
+			loci = append(loci, t.locus)
+		}
+	}
+	return loci
+}
+
+// match returns the indexes of the targets the record is at, has an ALT
+// at once normalized, or names.
+func (l *variantLookup) match(variant *vcfgo.Variant) []int {
+	matched := l.byLocus[Locus{normalizeChromosome(variant.Chromosome), variant.Pos}]
+	add := func(targets []int) {
+		for _, i := range targets {
+			if !slices.Contains(matched, i) {
+				matched = append(slices.Clip(matched), i)
+			}
+		}
+	}
+	for _, alt := range variant.Alternate {
+		if spec := recordSpec(variant, alt); spec.Position != variant.Pos {
+			add(l.byLocus[spec.Locus()])
+		}
+	}
+	if len(l.byID) == 0 || variant.Id() == "." {
+		return matched
+	}
+	for _, id := range strings.Split(variant.Id(), ";") {
+		add(l.byID[strings.ToLower(id)])
+	}
+	return matched
+}
+
+// rank grades how well a record with the given alleles, already on the
+// variant's strand, matches target i, and returns the GT index of the ALT
+// that is the target's alt, or -1 when no ALT is.
+func (l *variantLookup) rank(i int, variant *vcfgo.Variant, ref string, alt []string) (int, int) {
+	t := l.targets[i]
+	want := t.spec.normalized()
+	named := want.RSID != "" && slices.ContainsFunc(strings.Split(variant.Id(), ";"), func(id string) bool { return strings.EqualFold(id, want.RSID) })
+	atLocus := Locus{normalizeChromosome(variant.Chromosome), variant.Pos} == t.locus
+	if want.Ref == "" && want.Alt == "" {
+		if named || atLocus {
+			return matchAlleles, -1
+		}
+		return matchNone, -1
+	}
+
+	for k, a := range alt {
+		got := VariantSpec{Position: variant.Pos, Ref: ref, Alt: a}.normalized()
+		if (want.Ref == "" || got.Ref == want.Ref) && (want.Alt == "" || got.Alt == want.Alt) && (named || got.Position == t.locus.Position) {
+			return matchAlleles, k + 1
+		}
+	}
+	switch {
+	case named:
+		return matchAlleles, -1
+	case atLocus:
+		return matchLocus, -1
+	default:
+		// An indel whose ALT trims to the locus but is another variant.
+		return matchNone, -1
+	}
+}
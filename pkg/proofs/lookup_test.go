@@ -0,0 +1,121 @@
+package proofs
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractGenotypesLookup(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+chr5	33951693	.	C	A	60	PASS	.	GT	0/1
+chr5	33951693	.	C	G	60	PASS	.	GT	1/1
+chr15	28356859	rs12913832	A	G	60	PASS	.	GT	0/1
+19	45411941	.	G	C	60	PASS	.	GT	0/1
+19	45412079	rs1;RS7412	C	T	60	PASS	.	GT	0/0
+`)
+	specs := []VariantSpec{
+		// Found by rsID at another position, as in a VCF on another build.
+		{Chromosome: "15", Position: HERC2Pos, Ref: "A", Alt: "G", RSID: "rs12913832"},
+		// The split multiallelic record with the requested alt wins.
+		{Chromosome: "5", Position: 33951693, Ref: "C", Alt: "G", RSID: "rs16891982"},
+		// A record with other alleles is kept so callers see the mismatch.
+		{Chromosome: "19", Position: 45411941, Ref: "T", Alt: "C"},
+		{Chromosome: "19", Position: 45412079, Ref: "C", Alt: "T", RSID: "rs7412"},
+		{Chromosome: "1", Position: 100, Ref: "A", Alt: "G", RSID: "rs999"},
+	}
+	genotypes, err := extractGenotypes(vcf, Settings{}, specs)
+	if err != nil {
+		t.Fatalf("extractGenotypes: %v", err)
+	}
+
+	for _, tt := range []struct {
+		locus   Locus
+		ref     string
+		alleles []int
+	}{
+		{Locus{"15", HERC2Pos}, "A", []int{0, 1}},
+		{Locus{"5", 33951693}, "C", []int{1, 1}},
+		{Locus{"19", 45411941}, "G", []int{0, 1}},
+		{Locus{"19", 45412079}, "C", []int{0, 0}},
+	} {
+		g, ok := genotypes[tt.locus]
+		if !ok {
+			t.Errorf("%s: no genotype", tt.locus)
+			continue
+		}
+		if g.Ref != tt.ref || len(g.Alleles) != 2 || g.Alleles[0] != tt.alleles[0] || g.Alleles[1] != tt.alleles[1] {
+			t.Errorf("%s: genotype = %+v, want ref %s alleles %v", tt.locus, g, tt.ref, tt.alleles)
+		}
+	}
+	if g, ok := genotypes[Locus{"1", 100}]; ok {
+		t.Errorf("absent variant has genotype %+v", g)
+	}
+}
+
+func TestVariantSpecNormalized(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"17:41276045:c:g", "17:41276045:C:G"},
+		{"17:41276045:CT:C", "17:41276045:CT:C"},
+		{"17:41276043:GACT:GA", "17:41276044:ACT:A"},
+		{"17:41209079:TGA:TGAGA", "17:41209079:T:TGA"},
+		{"1:100:ATG:ACG", "1:101:T:C"},
+		{"1:100:A:<DEL>", "1:100:A:<DEL>"},
+	} {
+		if got := mustParseVariant(t, tt.in).String(); got != tt.want {
+			t.Errorf("%s normalized = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExtractGenotypesMultiallelic(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+17	41276043	.	GACT	GA,G	60	PASS	.	GT	0/1
+17	41276044	.	A	G	60	PASS	.	GT	1/1
+17	41209079	.	G	GC,GCC	60	PASS	.	GT	1/2
+17	41209100	.	CA	C	60	PASS	.	GT	0/1
+`)
+	specs := []VariantSpec{
+		// A deletion written with an extra base in a multiallelic record.
+		{Chromosome: "17", Position: 41276044, Ref: "ACT", Alt: "A"},
+		{Chromosome: "17", Position: 41209079, Ref: "G", Alt: "GCC"},
+		// The deletion trims to 41209100, but is not this SNV.
+		{Chromosome: "17", Position: 41209101, Ref: "A", Alt: "G"},
+	}
+	genotypes, err := extractGenotypes(vcf, Settings{}, specs)
+	if err != nil {
+		t.Fatalf("extractGenotypes: %v", err)
+	}
+
+	for _, tt := range []struct {
+		spec    VariantSpec
+		carries int
+		alleles []string
+	}{
+		{specs[0], 1, []string{"ACT", "A"}},
+		{specs[1], 1, []string{"GC", "GCC"}},
+	} {
+		g, ok := genotypes[tt.spec.Locus()]
+		if !ok {
+			t.Errorf("%s: no genotype", tt.spec)
+			continue
+		}
+		if g.Ref != tt.spec.Ref {
+			t.Errorf("%s: ref = %s", tt.spec, g.Ref)
+		}
+		if got := g.Carries(tt.spec.Alt); got != tt.carries {
+			t.Errorf("%s: carries %d copies, want %d", tt.spec, got, tt.carries)
+		}
+		if got := []string{g.Allele(g.Alleles[0]), g.Allele(g.Alleles[1])}; !slices.Equal(got, tt.alleles) {
+			t.Errorf("%s: alleles = %v, want %v", tt.spec, got, tt.alleles)
+		}
+	}
+	if g, ok := genotypes[specs[2].Locus()]; ok {
+		t.Errorf("%s matched the deletion before it: %+v", specs[2], g)
+	}
+}
@@ -0,0 +1,139 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// VariantMembershipCircuit proves that a public variant is a leaf of the
+// variant tree with a public root. Only the Merkle path is private, so the
+// witness stays logarithmic in the size of the VCF.
+type VariantMembershipCircuit struct {
+	Root      frontend.Variable `gnark:",public"`
+	LocusKey  frontend.Variable `gnark:",public"`
+	VariantID frontend.Variable `gnark:",public"`
+
+	Index    frontend.Variable
+	Siblings []frontend.Variable
+
+	Hash HashAlgorithm `gnark:"-"`
+}
+
+// NewVariantMembershipCircuit allocates a circuit for a tree of depth built with hash.
+func NewVariantMembershipCircuit(depth int, hash HashAlgorithm) *VariantMembershipCircuit {
+	return &VariantMembershipCircuit{Siblings: make([]frontend.Variable, depth), Hash: hash}
+}
+
+func (c *VariantMembershipCircuit) Define(api frontend.API) error {
+	h, err := newFieldHasher(api, c.Hash)
+	if err != nil {
+		return err
+	}
+
+	h.Write(c.LocusKey, c.VariantID)
+	leaf := h.Sum()
+
+	api.AssertIsEqual(merkleRoot(api, h, leaf, c.Index, c.Siblings), c.Root)
+	return nil
+}
+
+func (p *VariantMembershipProof) treeDepth() int {
+	if p.TreeDepth == 0 {
+		return DefaultTreeDepth
+	}
+	return p.TreeDepth
+}
+
+// CommitVariants builds the variant tree of the VCF at vcfPath with hash and
+// returns its root as a publishable commitment.
+func CommitVariants(vcfPath string, depth int, imputation ImputationPolicy, hash HashAlgorithm, limits ReadLimits) (*DatasetCommitment, error) {
+	tree, err := BuildVariantTree(vcfPath, depth, imputation, hash, limits)
+	if err != nil {
+		return nil, err
+	}
+	return &DatasetCommitment{ProofType: "membership", Commitment: tree.Root().String(), Hash: hash}, nil
+}
+
+func (p *VariantMembershipProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	variant, err := ParseVariantSpec(p.Variant)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Building variant tree from VCF...")
+	tree, err := buildVariantTree(vcfPath, p.Sample, p.treeDepth(), p.Imputation, p.Hash, p.Limits)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	index, found, err := tree.find(variant)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return withKind(ErrNotFound, fmt.Errorf("variant %s is not carried in the VCF", variant))
+	}
+	leaf := tree.leaves[index]
+
+	assignment := NewVariantMembershipCircuit(tree.Depth, tree.Hash)
+	assignment.Root = tree.Root()
+	assignment.LocusKey = leaf.LocusKey
+	assignment.VariantID = leaf.VariantID
+	assignment.Index = index
+	for i, sibling := range tree.path(index) {
+		assignment.Siblings[i] = sibling
+	}
+
+	if err := p.Settings.prove(ctx, "membership", NewVariantMembershipCircuit(tree.Depth, tree.Hash), assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("membership", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven that variant %s is in the committed VCF\n", variant)
+	fmt.Println("without revealing any other variant.")
+	fmt.Printf("Variant tree root: %s\n", tree.Root())
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *VariantMembershipProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 3 {
+		return false, withKind(ErrVerification, fmt.Errorf("membership proof has %d public inputs, expected 3", len(public)))
+	}
+	if p.Variant != "" {
+		variant, err := checkVariantInputs(public, 1, p.Variant)
+		if err != nil {
+			return false, err
+		}
+		fmt.Printf("Carried: %s\n", variant)
+	} else {
+		fmt.Printf("Carried: a variant at %s\n", provenLocus(public[1]))
+	}
+
+	if p.ExpectedCommitment != "" {
+		if err := checkCommitment(proofPath, 0, p.ExpectedCommitment); err != nil {
+			return false, err
+		}
+	}
+	return verified, nil
+}
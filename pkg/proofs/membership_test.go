@@ -0,0 +1,126 @@
+package proofs
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+const membershipTestVCF = `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+chr17	41276045	.	CT	C	60	PASS	.	GT	0/1
+chr17	41209079	.	TGA	TGAGA,T	60	PASS	.	GT	0/1
+chr1	1000	.	A	G	60	PASS	.	GT	1/1
+chr1	2000	.	C	T,G	60	PASS	.	GT	0/2
+chr2	500	.	G	A	60	PASS	.	GT	0/0
+chrUn_gl000220	10	.	A	C	60	PASS	.	GT	0/1
+`
+
+func TestBuildVariantTree(t *testing.T) {
+	vcf := writeTempVCF(t, membershipTestVCF)
+	tree, err := BuildVariantTree(vcf, 4, ImputationPolicy{}, HashMiMC, ReadLimits{})
+	if err != nil {
+		t.Fatalf("BuildVariantTree: %v", err)
+	}
+
+	tests := []struct {
+		variant string
+		want    bool
+	}{
+		{"17:41276045:CT:C", true},
+		{"17:41209079:T:TGA", true}, // padded in the record
+		{"17:41209079:TGA:TGAGA", true},
+		{"17:41209079:TGA:T", false},
+		{"1:1000:A:G", true},
+		{"1:2000:C:G", true},
+		{"1:2000:C:T", false}, // ALT not in the genotype
+		{"2:500:G:A", false},  // hom-ref call
+		{"3:100:A:G", false},
+	}
+	for _, tt := range tests {
+		_, found, err := tree.find(mustParseVariant(t, tt.variant))
+		if err != nil {
+			t.Fatalf("find %s: %v", tt.variant, err)
+		}
+		if found != tt.want {
+			t.Errorf("%s: found = %v, want %v", tt.variant, found, tt.want)
+		}
+	}
+
+	// The root must not depend on record order.
+	reversed := slices.Clone(tree.leaves)
+	slices.Reverse(reversed)
+	other, err := newVariantTree(reversed, 4, HashMiMC)
+	if err != nil {
+		t.Fatalf("newVariantTree: %v", err)
+	}
+	if other.Root().Cmp(tree.Root()) != 0 {
+		t.Errorf("root depends on leaf order")
+	}
+}
+
+func TestVariantMembershipCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	vcf := writeTempVCF(t, membershipTestVCF)
+	tree, err := BuildVariantTree(vcf, 4, ImputationPolicy{}, HashMiMC, ReadLimits{})
+	if err != nil {
+		t.Fatalf("BuildVariantTree: %v", err)
+	}
+
+	assignmentFor := func(index int, leaf variantLeaf) *VariantMembershipCircuit {
+		a := NewVariantMembershipCircuit(tree.Depth, tree.Hash)
+		a.Root = tree.Root()
+		a.LocusKey = leaf.LocusKey
+		a.VariantID = leaf.VariantID
+		a.Index = index
+		for i, sibling := range tree.path(index) {
+			a.Siblings[i] = sibling
+		}
+		return a
+	}
+
+	for index, leaf := range tree.leaves {
+		if err := test.IsSolved(NewVariantMembershipCircuit(tree.Depth, tree.Hash), assignmentFor(index, leaf), field); err != nil {
+			t.Errorf("leaf %d should satisfy the circuit: %v", index, err)
+		}
+	}
+
+	absent, err := newVariantLeaf(mustParseVariant(t, "1:2000:C:T"))
+	if err != nil {
+		t.Fatalf("newVariantLeaf: %v", err)
+	}
+	if err := test.IsSolved(NewVariantMembershipCircuit(tree.Depth, tree.Hash), assignmentFor(0, absent), field); err == nil {
+		t.Errorf("a variant outside the tree should not satisfy the circuit")
+	}
+}
+
+func TestVariantMembershipProofBindsVariant(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "membership_proof.bin")
+	proof := &VariantMembershipProof{Variant: "1:1000:A:G", TreeDepth: 4}
+	if err := proof.Generate(t.Context(), writeTempVCF(t, membershipTestVCF), "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); !ok || err != nil {
+		t.Errorf("Verify for the proof's variant = %v, %v", ok, err)
+	}
+	for _, variant := range []string{"1:1000:A:C", "1:2000:C:G"} {
+		other := &VariantMembershipProof{Variant: variant}
+		if ok, err := other.Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+			t.Errorf("Verify for %s = %v, %v; want ErrVerification", variant, ok, err)
+		}
+	}
+}
+
+func mustParseVariant(t *testing.T, s string) VariantSpec {
+	t.Helper()
+	v, err := ParseVariantSpec(s)
+	if err != nil {
+		t.Fatalf("ParseVariantSpec(%q): %v", s, err)
+	}
+	return v
+}
@@ -0,0 +1,298 @@
+package proofs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProofData is a proof held in memory with the files Generate writes
+// beside it, as GenerateBytes returns it.
+type ProofData struct {
+	Proof    []byte `json:"proof"`
+	Metadata []byte `json:"metadata"`
+	// ProvingKey and VerifyingKey are the keys of the setup the proof ran;
+	// both are nil when the proof was generated with a given proving key.
+	ProvingKey   []byte `json:"proving_key,omitempty"`
+	VerifyingKey []byte `json:"verifying_key,omitempty"`
+}
+
+// GenerateBytes is Generate for a VCF held in memory, returning the proof
+// and the files beside it rather than writing them. Neither the VCF nor
+// anything derived from it is written to disk; with WithProvingKeyData
+// neither is the key. Plain and gzip-compressed VCFs are read.
+func GenerateBytes(ctx context.Context, proofType string, vcf []byte, opts ...Option) (*ProofData, error) {
+	root, release := newMemoryStore()
+	defer release()
+
+	vcfPath := root + "/input.vcf"
+	if err := writeFile(vcfPath, vcf, 0600); err != nil {
+		return nil, err
+	}
+	proofPath := root + "/" + bundleProof
+	if err := Generate(ctx, proofType, vcfPath, proofPath, opts...); err != nil {
+		return nil, err
+	}
+
+	data := &ProofData{}
+	var err error
+	if data.Proof, err = readFile(proofPath); err != nil {
+		return nil, fmt.Errorf("reading proof: %w", err)
+	}
+	if data.Metadata, err = readFile(proofPath + MetadataSuffix); err != nil {
+		return nil, fmt.Errorf("reading proof metadata: %w", err)
+	}
+	if fileExists(proofPath + ".vk") {
+		data.ProvingKey, _ = readFile(proofPath + ".pk")
+		data.VerifyingKey, _ = readFile(proofPath + ".vk")
+	}
+	return data, nil
+}
+
+// VerifyBytes is Verify for a proof held in memory. The proof is verified
+// against proof.VerifyingKey unless WithVerifyingKey or
+// WithVerifyingKeyData gives another; a verifier that does not trust the
+// prover's setup passes its own key. proof.Proof may also hold a
+// ProofEnvelope, which carries its own metadata.
+func VerifyBytes(ctx context.Context, proof *ProofData, opts ...Option) (*VerificationResult, error) {
+	if len(proof.Proof) == 0 {
+		return nil, withKind(ErrInvalidInput, errors.New("no proof given"))
+	}
+	root, release := newMemoryStore()
+	defer release()
+
+	proofPath := root + "/" + bundleProof
+	files := map[string][]byte{
+		proofPath:                  proof.Proof,
+		proofPath + MetadataSuffix: proof.Metadata,
+		proofPath + ".vk":          proof.VerifyingKey,
+	}
+	for path, data := range files {
+		if data == nil {
+			continue
+		}
+		if err := writeFile(path, data, 0600); err != nil {
+			return nil, err
+		}
+	}
+	return Verify(ctx, proofPath, opts...)
+}
+
+// memoryKey holds a key given in memory as the file name in a new memory
+// store, returning its path and a func dropping the store.
+func memoryKey(data []byte, name string) (string, func(), error) {
+	root, release := newMemoryStore()
+	path := root + "/" + name
+	if err := writeFile(path, data, 0600); err != nil {
+		release()
+		return "", nil, err
+	}
+	return path, release, nil
+}
+
+// memoryScheme starts the paths of files held in a memory store rather
+// than on disk. The file functions generating and verifying proofs go
+// through use such paths like any other, so a proof generated from and
+// into them never touches the disk.
+const memoryScheme = "mem://"
+
+// memoryStore holds the files under one memory root.
+type memoryStore struct {
+	mu    sync.Mutex
+	files map[string]memoryFile
+}
+
+type memoryFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+// memoryStores holds the live memory stores by root name.
+var memoryStores sync.Map
+
+// newMemoryStore returns the root path of a new, empty memory store and a
+// func dropping the store with its files.
+func newMemoryStore() (string, func()) {
+	var id [8]byte
+	rand.Read(id[:])
+	name := hex.EncodeToString(id[:])
+	memoryStores.Store(name, &memoryStore{files: map[string]memoryFile{}})
+	return memoryScheme + name, func() { memoryStores.Delete(name) }
+}
+
+// memoryPath returns the store holding the file at p and the file's name
+// in it, or false when p is not a memory path. Paths cleaned by filepath,
+// which collapses the scheme's slashes to mem:/, are memory paths too.
+func memoryPath(p string) (*memoryStore, string, bool) {
+	rest, ok := strings.CutPrefix(p, memoryScheme)
+	if !ok {
+		if rest, ok = strings.CutPrefix(p, strings.TrimSuffix(memoryScheme, "/")); !ok {
+			return nil, "", false
+		}
+	}
+	root, name, _ := strings.Cut(rest, "/")
+	store, ok := memoryStores.Load(root)
+	if !ok {
+		return nil, "", true
+	}
+	return store.(*memoryStore), path.Clean("/" + name), true
+}
+
+func (m *memoryStore) get(op, p, name string) (memoryFile, error) {
+	if m != nil {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if f, ok := m.files[name]; ok {
+			return f, nil
+		}
+	}
+	return memoryFile{}, &fs.PathError{Op: op, Path: p, Err: fs.ErrNotExist}
+}
+
+func (m *memoryStore) put(p, name string, data []byte) error {
+	if m == nil {
+		return &fs.PathError{Op: "write", Path: p, Err: fs.ErrNotExist}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = memoryFile{data: bytes.Clone(data), modTime: time.Now()}
+	return nil
+}
+
+// openFile opens the file at p for reading, from memory for a memory
+// path and from its Storage for a storage path.
+func openFile(p string) (io.ReadSeekCloser, error) {
+	store, name, ok := memoryPath(p)
+	if !ok {
+		if s, name, err := storagePath(p); s != nil || err != nil {
+			if err != nil {
+				return nil, err
+			}
+			return openStorageFile(s, name)
+		}
+		return os.Open(p)
+	}
+	f, err := store.get("open", p, name)
+	if err != nil {
+		return nil, err
+	}
+	return memoryReader{bytes.NewReader(f.data)}, nil
+}
+
+// readFile is os.ReadFile for memory and storage paths too.
+func readFile(p string) ([]byte, error) {
+	store, name, ok := memoryPath(p)
+	if !ok {
+		if s, name, err := storagePath(p); s != nil || err != nil {
+			if err != nil {
+				return nil, err
+			}
+			r, err := s.Open(context.Background(), name)
+			if err != nil {
+				return nil, err
+			}
+			defer r.Close()
+			return io.ReadAll(r)
+		}
+		return os.ReadFile(p)
+	}
+	f, err := store.get("open", p, name)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(f.data), nil
+}
+
+// writeFile is os.WriteFile for memory and storage paths too.
+func writeFile(p string, data []byte, perm os.FileMode) error {
+	store, name, ok := memoryPath(p)
+	if !ok {
+		if s, name, err := storagePath(p); s != nil || err != nil {
+			if err != nil {
+				return err
+			}
+			return writeStorageFile(s, name, writeBytes(data))
+		}
+		return os.WriteFile(p, data, perm)
+	}
+	return store.put(p, name, data)
+}
+
+// statFile is os.Stat for memory and storage paths too.
+func statFile(p string) (fs.FileInfo, error) {
+	store, name, ok := memoryPath(p)
+	if !ok {
+		if s, name, err := storagePath(p); s != nil || err != nil {
+			if err != nil {
+				return nil, err
+			}
+			return s.Stat(context.Background(), name)
+		}
+		return os.Stat(p)
+	}
+	f, err := store.get("stat", p, name)
+	if err != nil {
+		return nil, err
+	}
+	return memoryFileInfo{name: path.Base(name), size: int64(len(f.data)), modTime: f.modTime}, nil
+}
+
+// removeFile is os.Remove for memory and storage paths too.
+func removeFile(p string) error {
+	store, name, ok := memoryPath(p)
+	if !ok {
+		if s, name, err := storagePath(p); s != nil || err != nil {
+			if err != nil {
+				return err
+			}
+			return s.Remove(context.Background(), name)
+		}
+		return os.Remove(p)
+	}
+	if _, err := store.get("remove", p, name); err != nil {
+		return err
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.files, name)
+	return nil
+}
+
+// mkdirAll is os.MkdirAll, with nothing to do for memory and storage
+// paths, whose directories are implied by the files in them.
+func mkdirAll(p string, perm os.FileMode) error {
+	if _, _, ok := memoryPath(p); ok || IsStoragePath(p) {
+		return nil
+	}
+	return os.MkdirAll(p, perm)
+}
+
+// memoryReader reads an open memory file.
+type memoryReader struct {
+	*bytes.Reader
+}
+
+func (memoryReader) Close() error { return nil }
+
+type memoryFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (i memoryFileInfo) Name() string       { return i.name }
+func (i memoryFileInfo) Size() int64        { return i.size }
+func (i memoryFileInfo) Mode() fs.FileMode  { return 0600 }
+func (i memoryFileInfo) ModTime() time.Time { return i.modTime }
+func (i memoryFileInfo) IsDir() bool        { return false }
+func (i memoryFileInfo) Sys() any           { return nil }
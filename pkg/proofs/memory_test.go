@@ -0,0 +1,67 @@
+package proofs
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateBytesVerifyBytes(t *testing.T) {
+	vcf := []byte(`##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	data, err := GenerateBytes(t.Context(), "zygosity", vcf, WithPosition("15:28365618"))
+	if err != nil {
+		t.Fatalf("GenerateBytes: %v", err)
+	}
+	if len(data.Proof) == 0 || len(data.Metadata) == 0 || len(data.ProvingKey) == 0 || len(data.VerifyingKey) == 0 {
+		t.Fatalf("GenerateBytes left files empty: proof %d, metadata %d, keys %d/%d bytes", len(data.Proof), len(data.Metadata), len(data.ProvingKey), len(data.VerifyingKey))
+	}
+	if res, err := VerifyBytes(t.Context(), data, WithPosition("15:28365618")); err != nil || !res.Valid {
+		t.Fatalf("VerifyBytes = %+v, %v", res, err)
+	}
+
+	again, err := GenerateBytes(t.Context(), "zygosity", vcf, WithPosition("15:28365618"), WithProvingKeyData(data.ProvingKey))
+	if err != nil {
+		t.Fatalf("GenerateBytes with a proving key: %v", err)
+	}
+	if again.ProvingKey != nil || again.VerifyingKey != nil {
+		t.Errorf("GenerateBytes with a proving key returned keys")
+	}
+	if res, err := VerifyBytes(t.Context(), again, WithVerifyingKeyData(data.VerifyingKey)); err != nil || !res.Valid {
+		t.Fatalf("VerifyBytes with a verifying key = %+v, %v", res, err)
+	}
+	if _, err := VerifyBytes(t.Context(), again); err == nil {
+		t.Errorf("VerifyBytes without a verifying key succeeded")
+	}
+	if _, err := VerifyBytes(t.Context(), &ProofData{}); !errors.Is(ErrorKind(err), ErrInvalidInput) {
+		t.Errorf("VerifyBytes of no proof: %v", err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	root, release := newMemoryStore()
+	path := root + "/dir/file"
+	if err := writeFile(path, []byte("data"), 0600); err != nil {
+		t.Fatalf("writeFile: %v", err)
+	}
+	if data, err := readFile(root + "/dir/../dir/file"); err != nil || string(data) != "data" {
+		t.Errorf("readFile = %q, %v", data, err)
+	}
+	if info, err := statFile(path); err != nil || info.Size() != 4 || info.Name() != "file" {
+		t.Errorf("statFile = %v, %v", info, err)
+	}
+	if data, err := readFile(filepath.Clean(path)); err != nil || string(data) != "data" {
+		t.Errorf("readFile of the cleaned path = %q, %v", data, err)
+	}
+	release()
+	if _, err := readFile(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("readFile after release: %v", err)
+	}
+	if err := writeFile(path, nil, 0600); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("writeFile after release: %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+package proofs
+
+import (
+	"cmp"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// DefaultTreeDepth is the variant tree depth used when none is configured.
+// 2^24 leaves comfortably hold a whole-genome VCF.
+const DefaultTreeDepth = 24
+
+// sentinelLocusKey sorts after every real locus key.
+const sentinelLocusKey uint64 = 1 << 40
+
+// variantLeaf is one carried alt allele committed to in a variant tree.
+type variantLeaf struct {
+	LocusKey  uint64
+	VariantID *big.Int
+}
+
+func (l variantLeaf) hash(a HashAlgorithm) *big.Int {
+	return a.sum(new(big.Int).SetUint64(l.LocusKey), l.VariantID)
+}
+
+func compareLeaves(a, b variantLeaf) int {
+	if c := cmp.Compare(a.LocusKey, b.LocusKey); c != 0 {
+		return c
+	}
+	return a.VariantID.Cmp(b.VariantID)
+}
+
+// chromosomeIndex orders the primary assembly chromosomes 1-22, X, Y, MT.
+func chromosomeIndex(chrom string) (uint64, bool) {
+	switch c := strings.ToUpper(normalizeChromosome(chrom)); c {
+	case "X":
+		return 23, true
+	case "Y":
+		return 24, true
+	case "M", "MT":
+		return 25, true
+	default:
+		n, err := strconv.ParseUint(c, 10, 64)
+		if err != nil || n < 1 || n > 22 {
+			return 0, false
+		}
+		return n, true
+	}
+}
+
+// locusKey packs a locus into a single integer ordered by chromosome, then
+// position.
+func locusKey(l Locus) (uint64, error) {
+	index, ok := chromosomeIndex(l.Chromosome)
+	if !ok {
+		return 0, fmt.Errorf("chromosome %s is not a primary assembly chromosome", l.Chromosome)
+	}
+	return index<<32 | l.Position, nil
+}
+
+// locusFromKey inverts locusKey.
+func locusFromKey(key uint64) Locus {
+	chrom := strconv.FormatUint(key>>32, 10)
+	switch key >> 32 {
+	case 23:
+		chrom = "X"
+	case 24:
+		chrom = "Y"
+	case 25:
+		chrom = "MT"
+	}
+	return Locus{chrom, key & (1<<32 - 1)}
+}
+
+func newVariantLeaf(v VariantSpec) (variantLeaf, error) {
+	key, err := locusKey(v.Locus())
+	if err != nil {
+		return variantLeaf{}, err
+	}
+	return variantLeaf{LocusKey: key, VariantID: v.ID()}, nil
+}
+
+// checkVariantInputs checks that the locus key and variant ID public
+// inputs at public[i] and public[i+1] are those of the variant spec.
+func checkVariantInputs(public []*big.Int, i int, spec string) (VariantSpec, error) {
+	variant, err := ParseVariantSpec(spec)
+	if err != nil {
+		return VariantSpec{}, withKind(ErrInvalidInput, err)
+	}
+	leaf, err := newVariantLeaf(variant)
+	if err != nil {
+		return VariantSpec{}, withKind(ErrInvalidInput, err)
+	}
+	if !public[i].IsUint64() || public[i].Uint64() != leaf.LocusKey || public[i+1].Cmp(leaf.VariantID) != 0 {
+		return VariantSpec{}, withKind(ErrVerification, fmt.Errorf("proof is for a different variant than %s", variant))
+	}
+	return variant, nil
+}
+
+// provenLocus names the locus of the locus key public input key.
+func provenLocus(key *big.Int) string {
+	if !key.IsUint64() {
+		return key.String()
+	}
+	return locusFromKey(key.Uint64()).String()
+}
+
+// VariantTree is a fixed-depth Merkle tree over the variants a subject
+// carries, sorted by locus so the root does not depend on VCF record order.
+// The sorted leaves are bracketed by a low and a high sentinel, so every
+// absent variant falls between two adjacent leaves.
+type VariantTree struct {
+	Depth  int
+	Hash   HashAlgorithm
+	leaves []variantLeaf
+	// levels[0] holds the leaf hashes, levels[Depth] the root.
+	levels [][]*big.Int
+	// empty[i] is the hash of an all-empty subtree of height i.
+	empty []*big.Int
+}
+
+func newVariantTree(leaves []variantLeaf, depth int, hash HashAlgorithm) (*VariantTree, error) {
+	if depth < 1 || depth > 32 {
+		return nil, fmt.Errorf("tree depth must be between 1 and 32, got %d", depth)
+	}
+	if len(leaves)+2 > 1<<depth {
+		return nil, fmt.Errorf("%d variants do not fit in a tree of depth %d", len(leaves), depth)
+	}
+
+	leaves = append(slices.Clone(leaves),
+		variantLeaf{LocusKey: 0, VariantID: big.NewInt(0)},
+		variantLeaf{LocusKey: sentinelLocusKey, VariantID: big.NewInt(0)},
+	)
+	slices.SortFunc(leaves, compareLeaves)
+	leaves = slices.CompactFunc(leaves, func(a, b variantLeaf) bool { return compareLeaves(a, b) == 0 })
+
+	t := &VariantTree{Depth: depth, Hash: hash, leaves: leaves}
+
+	t.empty = make([]*big.Int, depth+1)
+	t.empty[0] = hash.sum(big.NewInt(0), big.NewInt(0))
+	for i := 1; i <= depth; i++ {
+		t.empty[i] = hash.sum(t.empty[i-1], t.empty[i-1])
+	}
+
+	level := make([]*big.Int, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = leaf.hash(hash)
+	}
+	t.levels = [][]*big.Int{level}
+
+	for height := 1; height <= depth; height++ {
+		below := t.levels[height-1]
+		level := make([]*big.Int, (len(below)+1)/2)
+		for i := range level {
+			left, right := below[2*i], t.empty[height-1]
+			if 2*i+1 < len(below) {
+				right = below[2*i+1]
+			}
+			level[i] = hash.sum(left, right)
+		}
+		t.levels = append(t.levels, level)
+	}
+
+	return t, nil
+}
+
+// fitLeaves lets tree builders stop reading a VCF as soon as its variants
+// cannot fit in a tree of depth, rather than buffering the rest of it.
+// Duplicate leaves are dropped once leaves outgrow the tree.
+func fitLeaves(leaves []variantLeaf, depth int) ([]variantLeaf, error) {
+	if depth < 1 || depth > 32 || len(leaves)+2 <= 1<<depth {
+		return leaves, nil
+	}
+	slices.SortFunc(leaves, compareLeaves)
+	leaves = slices.CompactFunc(leaves, func(a, b variantLeaf) bool { return compareLeaves(a, b) == 0 })
+	if len(leaves)+2 > 1<<depth {
+		return nil, fmt.Errorf("more than %d variants do not fit in a tree of depth %d", 1<<depth-2, depth)
+	}
+	return leaves, nil
+}
+
+// BuildVariantTree commits to every alt allele the first sample carries,
+// hashing with hash. Sites-only VCFs contribute every ALT. Each ALT of a
+// multiallelic record is its own variant, with indels trimmed to their
+// minimal form. Records on non-primary contigs are skipped.
+func BuildVariantTree(vcfPath string, depth int, imputation ImputationPolicy, hash HashAlgorithm, limits ReadLimits) (*VariantTree, error) {
+	return buildVariantTree(vcfPath, "", depth, imputation, hash, limits)
+}
+
+// buildVariantTree is BuildVariantTree for the sample named sample, or the
+// first sample when it is empty.
+func buildVariantTree(vcfPath string, sample string, depth int, imputation ImputationPolicy, hash HashAlgorithm, limits ReadLimits) (*VariantTree, error) {
+	rdr, err := openVCF(vcfPath, limits, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	sampleIndex, err := sampleColumn(rdr.Header, sample)
+	if err != nil {
+		return nil, err
+	}
+
+	var leaves []variantLeaf
+	skipped := 0
+	for {
+		variant, err := rdr.next()
+		if err != nil {
+			return nil, err
+		}
+		if variant == nil {
+			break
+		}
+		if !imputation.acceptsSample(variant, sampleIndex) {
+			continue
+		}
+
+		for i, alt := range variant.Alternate {
+			if len(variant.Samples) > sampleIndex && variant.Samples[sampleIndex] != nil && !slices.Contains(variant.Samples[sampleIndex].GT, i+1) {
+				continue
+			}
+
+			leaf, err := newVariantLeaf(recordSpec(variant, alt))
+			if err != nil {
+				skipped++
+				continue
+			}
+			if leaves, err = fitLeaves(append(leaves, leaf), depth); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Printf("Skipped %d variants on non-primary contigs\n", skipped)
+	}
+	fmt.Printf("Committing to %d variants\n", len(leaves))
+
+	return newVariantTree(leaves, depth, hash)
+}
+
+// Root returns the Merkle root committing to the tree's variants.
+func (t *VariantTree) Root() *big.Int {
+	if top := t.levels[t.Depth]; len(top) > 0 {
+		return top[0]
+	}
+	return t.empty[t.Depth]
+}
+
+// find returns the leaf index of v, if the tree contains it.
+func (t *VariantTree) find(v VariantSpec) (int, bool, error) {
+	leaf, err := newVariantLeaf(v)
+	if err != nil {
+		return 0, false, err
+	}
+	index, found := t.findLeaf(leaf)
+	return index, found, nil
+}
+
+// findLeaf returns the index of leaf, or where it would be inserted.
+func (t *VariantTree) findLeaf(leaf variantLeaf) (int, bool) {
+	return slices.BinarySearchFunc(t.leaves, leaf, compareLeaves)
+}
+
+// path returns the sibling hashes from leaf index up to the root.
+func (t *VariantTree) path(index int) []*big.Int {
+	siblings := make([]*big.Int, t.Depth)
+	for height := 0; height < t.Depth; height++ {
+		sibling := index ^ 1
+		if sibling < len(t.levels[height]) {
+			siblings[height] = t.levels[height][sibling]
+		} else {
+			siblings[height] = t.empty[height]
+		}
+		index >>= 1
+	}
+	return siblings
+}
+
+// merkleRoot recomputes the root from a leaf hash, its index and the sibling
+// path, matching VariantTree.
+func merkleRoot(api frontend.API, h hash.FieldHasher, leaf, index frontend.Variable, siblings []frontend.Variable) frontend.Variable {
+	bits := api.ToBinary(index, len(siblings))
+	node := leaf
+	for i, sibling := range siblings {
+		// bit i set means the node is a right child at height i
+		left := api.Select(bits[i], sibling, node)
+		right := api.Select(bits[i], node, sibling)
+		h.Reset()
+		h.Write(left, right)
+		node = h.Sum()
+	}
+	return node
+}
@@ -0,0 +1,157 @@
+package proofs
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MetadataSuffix is appended to a proof path to locate its metadata file,
+// alongside the .pk and .vk key files.
+const MetadataSuffix = ".meta.json"
+
+// ProofMetadata records how a proof was produced so a verifier can see which
+// policies governed witness construction without access to the VCF.
+type ProofMetadata struct {
+	ProofType  string           `json:"proof_type"`
+	CreatedAt  time.Time        `json:"created_at"`
+	Imputation ImputationPolicy `json:"imputation_policy"`
+	// Missing is how no-calls and loci without a record were treated.
+	Missing MissingPolicy `json:"missing_genotype_policy"`
+	// Filter is the call filter records had to pass, if any.
+	Filter *CallFilter `json:"call_filter,omitempty"`
+	// DatasetDigest is the DatasetDigest of the VCF the proof was generated
+	// from, if one was recorded. DigestBound is set when it is also a
+	// public input, following the nonce.
+	DatasetDigest string `json:"dataset_digest,omitempty"`
+	DigestBound   bool   `json:"dataset_digest_bound,omitempty"`
+	// Provenance is the lab attestation over the VCF the proof was built
+	// from, if one was supplied.
+	Provenance *ProvenanceAttestation `json:"provenance,omitempty"`
+	// Nullifier is the proof's public nullifier, the last public input, when
+	// it was generated with a holder secret.
+	Nullifier string `json:"nullifier,omitempty"`
+	// Nonce is the verifier challenge the proof is bound to, if any; it
+	// follows the nullifier in the public inputs.
+	Nonce string `json:"nonce,omitempty"`
+	// IssuedAt and ExpiresAt are the issuance and expiry times bound into
+	// the proof, if any; they follow the digest in the public inputs.
+	IssuedAt  *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Hash is the variant tree hash, for proofs built over one.
+	Hash HashAlgorithm `json:"hash,omitempty"`
+	// Backend is the proof system the proof and its keys belong to; empty
+	// means Groth16.
+	Backend BackendName `json:"backend,omitempty"`
+	// Curve is the curve the proof and its keys are over; empty means
+	// BN254.
+	Curve Curve `json:"curve,omitempty"`
+	// CircuitHash is the SHA-256 of the compiled constraint system the
+	// proof was generated for, as setup records it beside its keys.
+	CircuitHash string `json:"circuit_hash,omitempty"`
+	// ProvingKey and VerifyingKey are the KeyFingerprint of the key files
+	// the proof was generated with, where they could be read.
+	ProvingKey   string `json:"proving_key_sha256,omitempty"`
+	VerifyingKey string `json:"verifying_key_sha256,omitempty"`
+	// Software is the build that generated the proof, to tell whether a
+	// proof failing to verify comes from another version.
+	Software *SoftwareVersion `json:"software,omitempty"`
+	// HolderSignature is the holder's signature over the proof file, when
+	// it was generated with a signing key.
+	HolderSignature *HolderSignature `json:"holder_signature,omitempty"`
+	// Aggregated lists the inner proofs of a recursive proof, whose public
+	// inputs follow in this order.
+	Aggregated []AggregatedProof `json:"aggregated,omitempty"`
+
+	// signKey, when set, is the holder key writeMetadata signs the proof
+	// with.
+	signKey ed25519.PrivateKey
+	// signDID names the holder in the signature.
+	signDID string
+}
+
+func newMetadata(proofType string, settings Settings) ProofMetadata {
+	imputation := settings.Imputation
+	if imputation.Mode == "" {
+		imputation.Mode = ImputationAllow
+	}
+
+	missing := settings.Missing
+	if missing == "" {
+		missing = MissingFail
+	}
+
+	build := BuildVersion()
+	meta := ProofMetadata{
+		ProofType:  proofType,
+		CreatedAt:  time.Now().UTC(),
+		Imputation: imputation,
+		Missing:    missing,
+		Provenance: settings.Provenance,
+		Software:   &build,
+		signKey:    settings.SignKey,
+		signDID:    settings.SignDID,
+	}
+	if !settings.Filter.IsZero() {
+		filter := settings.Filter
+		meta.Filter = &filter
+	}
+	if settings.HolderSecret != nil {
+		meta.Nullifier = Nullifier(settings.HolderSecret, proofType).String()
+	}
+	meta.Nonce = settings.Nonce
+	meta.DatasetDigest = settings.datasetDigest
+	meta.DigestBound = settings.Digest == DigestPublic
+	if settings.timestamped() {
+		issued := settings.issuedAt
+		meta.IssuedAt = &issued
+	}
+	if settings.ValidFor > 0 {
+		expires := settings.issuedAt.Add(settings.ValidFor)
+		meta.ExpiresAt = &expires
+	}
+	meta.Hash = settings.Hash
+	meta.Backend = settings.Backend
+	meta.Curve = settings.Curve
+	if settings.keys != nil {
+		meta.CircuitHash = settings.keys.circuitHash
+		meta.ProvingKey = settings.keys.provingKey
+		meta.VerifyingKey = settings.keys.verifyingKey
+	}
+	return meta
+}
+
+func writeMetadata(outputPath string, meta ProofMetadata) error {
+	if meta.signKey != nil {
+		sig, err := signProof(outputPath, meta.signKey, meta.signDID)
+		if err != nil {
+			return fmt.Errorf("signing proof: %w", err)
+		}
+		meta.HolderSignature = sig
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding proof metadata: %w", err)
+	}
+
+	if err := writeFileAtomic(outputPath+MetadataSuffix, 0644, writeBytes(data)); err != nil {
+		return fmt.Errorf("writing proof metadata: %w", err)
+	}
+	return nil
+}
+
+// ReadMetadata loads the metadata written next to the proof at proofPath.
+func ReadMetadata(proofPath string) (*ProofMetadata, error) {
+	data, err := readFile(proofPath + MetadataSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("reading proof metadata: %w", err)
+	}
+
+	var meta ProofMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("parsing proof metadata: %w", err)
+	}
+	return &meta, nil
+}
@@ -0,0 +1,63 @@
+package proofs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MissingPolicy selects how missing genotypes are treated when a variant
+// is considered for a proof witness: no-calls such as "./." or ".", and
+// proved loci without a record in the VCF.
+type MissingPolicy string
+
+const (
+	// MissingFail rejects a no-call at any proved locus. Loci without a
+	// record are taken as homozygous reference by the proofs that allow it,
+	// since most VCFs list variant sites only.
+	MissingFail MissingPolicy = "fail"
+	// MissingTreatAsMissing handles a no-call as if its record were absent.
+	MissingTreatAsMissing MissingPolicy = "treat-as-missing"
+	// MissingRequireCoverage requires a called genotype at every proved
+	// locus, rejecting no-calls and loci without a record alike. Use it
+	// with gVCFs or VCFs that emit reference calls.
+	MissingRequireCoverage MissingPolicy = "require-coverage"
+)
+
+// ParseMissingPolicy converts a CLI string into a MissingPolicy.
+func ParseMissingPolicy(s string) (MissingPolicy, error) {
+	switch policy := MissingPolicy(strings.ToLower(s)); policy {
+	case "", MissingFail:
+		return MissingFail, nil
+	case MissingTreatAsMissing, MissingRequireCoverage:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown missing genotype policy: %s. Supported policies: fail, treat-as-missing, require-coverage", s)
+	}
+}
+
+// isNoCall reports whether any allele of the call is missing.
+func (g Genotype) isNoCall() bool {
+	return len(g.Alleles) == 0 || slices.Contains(g.Alleles, -1)
+}
+
+// apply enforces the policy on the genotypes read for specs, dropping the
+// no-calls it treats as missing.
+func (p MissingPolicy) apply(specs []VariantSpec, genotypes map[Locus]Genotype) error {
+	for _, spec := range specs {
+		locus := Locus{normalizeChromosome(spec.Chromosome), spec.Position}
+		genotype, ok := genotypes[locus]
+		switch {
+		case !ok && p == MissingRequireCoverage:
+			return withKind(ErrNotFound, fmt.Errorf("no call at %s, and the missing genotype policy requires coverage", locus))
+		case !ok || !genotype.isNoCall():
+		case p == MissingTreatAsMissing:
+			delete(genotypes, locus)
+		case p == MissingRequireCoverage:
+			return withKind(ErrNotFound, fmt.Errorf("genotype at %s is a no-call, and the missing genotype policy requires coverage", locus))
+		default:
+			return withKind(ErrNotFound, fmt.Errorf("genotype at %s is a no-call; use the treat-as-missing policy to read it as absent", locus))
+		}
+	}
+	return nil
+}
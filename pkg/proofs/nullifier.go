@@ -0,0 +1,89 @@
+package proofs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// HolderSecretSuffix is appended to a keygen output path for holder secrets.
+const HolderSecretSuffix = ".secret"
+
+// circuitID encodes a proof type as a field element, so each proof type has
+// its own nullifier space.
+func circuitID(proofType string) *big.Int {
+	return new(big.Int).SetBytes([]byte(proofType))
+}
+
+// Nullifier returns MiMC(secret, circuit ID) for proofType. The same holder
+// gets the same nullifier for every proof of one type, so a relying party
+// can spot a proof being replayed or shared without learning the secret.
+func Nullifier(secret *big.Int, proofType string) *big.Int {
+	return mimcHash(secret, circuitID(proofType))
+}
+
+// GenerateHolderSecret writes a random holder secret to path+".secret".
+func GenerateHolderSecret(path string) error {
+	secret, err := randomFieldElement()
+	if err != nil {
+		return fmt.Errorf("generating holder secret: %w", err)
+	}
+	if err := os.WriteFile(path+HolderSecretSuffix, []byte(secret.String()+"\n"), 0600); err != nil {
+		return fmt.Errorf("writing holder secret: %w", err)
+	}
+	return nil
+}
+
+// ReadHolderSecret loads a holder secret written by GenerateHolderSecret.
+func ReadHolderSecret(path string) (*big.Int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading holder secret: %w", err)
+	}
+	secret, ok := new(big.Int).SetString(strings.TrimSpace(string(data)), 10)
+	if !ok || secret.Sign() <= 0 {
+		return nil, fmt.Errorf("holder secret in %s is not a positive integer", path)
+	}
+	return secret, nil
+}
+
+// ProofNullifier returns the nullifier a proof was generated with, and false
+// when it has none. The value comes from the proof's public inputs and must
+// match the one recorded in its metadata.
+func ProofNullifier(proofPath string) (*big.Int, bool, error) {
+	env, err := readEnvelope(proofPath)
+	if err != nil {
+		return nil, false, err
+	}
+	return env.Nullifier, env.Nullifier != nil, nil
+}
+
+// RecordNullifier appends nullifier to the log at logPath, one per line, and
+// returns an error if it was already there.
+func RecordNullifier(logPath string, nullifier *big.Int) error {
+	value := nullifier.String()
+
+	f, err := os.OpenFile(logPath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("opening nullifier log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == value {
+			return withKind(ErrVerification, errors.New("nullifier has already been used: the proof is a replay or was shared"))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading nullifier log: %w", err)
+	}
+
+	if _, err := fmt.Fprintln(f, value); err != nil {
+		return fmt.Errorf("writing nullifier log: %w", err)
+	}
+	return nil
+}
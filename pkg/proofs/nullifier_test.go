@@ -0,0 +1,142 @@
+package proofs
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestEnvelopeNullifier(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	secret := big.NewInt(123456789)
+
+	inner := func() *ZygosityCircuit {
+		return &ZygosityCircuit{
+			LocusKey:   15<<32 | HERC2Pos,
+			Zygosity:   Heterozygous,
+			MinQuality: 0,
+			MinDepth:   0,
+			Alleles:    [2]frontend.Variable{0, 1},
+			Quality:    0,
+			Depth:      0,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		nullifier *big.Int
+		valid     bool
+	}{
+		{"matching nullifier", Nullifier(secret, "zygosity"), true},
+		{"other proof type", Nullifier(secret, "wildtype"), false},
+		{"other secret", Nullifier(big.NewInt(1), "zygosity"), false},
+	}
+	settings := Settings{HolderSecret: secret}
+	for _, tt := range tests {
+		assignment := newEnvelopeCircuit(inner(), "zygosity", settings)
+		assignment.Nullifier[0], assignment.Secret[0] = tt.nullifier, secret
+		err := test.IsSolved(newEnvelopeCircuit(&ZygosityCircuit{}, "zygosity", settings), assignment, field)
+		if tt.valid && err != nil {
+			t.Errorf("%s: rejected: %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+
+	// The wrapped circuit's constraints still apply.
+	bad := inner()
+	bad.Zygosity = Homozygous
+	assignment := newEnvelopeCircuit(bad, "zygosity", settings)
+	assignment.Nullifier[0], assignment.Secret[0] = Nullifier(secret, "zygosity"), secret
+	if err := test.IsSolved(newEnvelopeCircuit(&ZygosityCircuit{}, "zygosity", settings), assignment, field); err == nil {
+		t.Errorf("wrapped circuit constraints should be enforced")
+	}
+}
+
+func TestNullifiedProof(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	if err := GenerateHolderSecret(filepath.Join(dir, "holder")); err != nil {
+		t.Fatalf("GenerateHolderSecret: %v", err)
+	}
+	secret, err := ReadHolderSecret(filepath.Join(dir, "holder"+HolderSecretSuffix))
+	if err != nil {
+		t.Fatalf("ReadHolderSecret: %v", err)
+	}
+
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	proof := &ZygosityProof{Settings: Settings{HolderSecret: secret}, Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	public, err := readPublicInputs(out)
+	if err != nil {
+		t.Fatalf("readPublicInputs: %v", err)
+	}
+	if len(public) != 4 {
+		t.Errorf("readPublicInputs returned %d inputs, want the circuit's 4", len(public))
+	}
+
+	nullifier, ok, err := ProofNullifier(out)
+	if err != nil || !ok {
+		t.Fatalf("ProofNullifier = %v, %v", ok, err)
+	}
+	if nullifier.Cmp(Nullifier(secret, "zygosity")) != 0 {
+		t.Errorf("nullifier = %s, want MiMC(secret, zygosity)", nullifier)
+	}
+
+	log := filepath.Join(dir, "seen.txt")
+	if err := RecordNullifier(log, nullifier); err != nil {
+		t.Fatalf("first use rejected: %v", err)
+	}
+	if err := RecordNullifier(log, big.NewInt(42)); err != nil {
+		t.Fatalf("other nullifier rejected: %v", err)
+	}
+	if err := RecordNullifier(log, nullifier); err == nil {
+		t.Errorf("replayed nullifier should be rejected")
+	}
+
+	// Metadata claiming a different nullifier is caught.
+	meta, err := ReadMetadata(out)
+	if err != nil {
+		t.Fatalf("ReadMetadata: %v", err)
+	}
+	meta.Nullifier = "42"
+	data, _ := json.Marshal(meta)
+	if err := os.WriteFile(out+MetadataSuffix, data, 0644); err != nil {
+		t.Fatalf("writing metadata: %v", err)
+	}
+	if _, _, err := ProofNullifier(out); err == nil {
+		t.Errorf("mismatched metadata nullifier should be rejected")
+	}
+}
+
+func TestProofWithoutNullifier(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	out := filepath.Join(t.TempDir(), "zygosity_proof.bin")
+	if err := (&ZygosityProof{Locus: "15:28365618"}).Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, ok, err := ProofNullifier(out); err != nil || ok {
+		t.Errorf("ProofNullifier = %v, %v; want no nullifier", ok, err)
+	}
+}
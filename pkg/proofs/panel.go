@@ -0,0 +1,243 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// PanelHash commits to the exact loci and alleles of a reference panel, in
+// panel order, so a published hash pins down which variants a panel proof
+// was computed over.
+func PanelHash(panel []VariantSpec) (*big.Int, error) {
+	values := make([]*big.Int, 0, 2*len(panel))
+	for _, v := range panel {
+		leaf, err := newVariantLeaf(v)
+		if err != nil {
+			return nil, fmt.Errorf("panel variant %s: %w", v, err)
+		}
+		values = append(values, new(big.Int).SetUint64(leaf.LocusKey), leaf.VariantID)
+	}
+	return mimcHash(values...), nil
+}
+
+// panelEntry is the private witness for one panel variant: the two adjacent
+// tree leaves around it. When the variant is carried the low leaf is the
+// variant itself.
+type panelEntry struct {
+	LocusKey      frontend.Variable
+	VariantID     frontend.Variable
+	LowIndex      frontend.Variable
+	LowLocusKey   frontend.Variable
+	LowVariantID  frontend.Variable
+	LowSiblings   []frontend.Variable
+	HighLocusKey  frontend.Variable
+	HighVariantID frontend.Variable
+	HighSiblings  []frontend.Variable
+}
+
+// PanelConsistencyCircuit proves how many variants of a reference panel are
+// carried in the variant tree with a public root. The panel's loci and
+// alleles are private witnesses hashed into the public PanelHash, so the
+// prover cannot substitute loci other than the ones the panel specifies.
+//
+// Like VariantAbsenceCircuit it relies on the tree being sorted.
+type PanelConsistencyCircuit struct {
+	PanelHash frontend.Variable `gnark:",public"`
+	Root      frontend.Variable `gnark:",public"`
+	Count     frontend.Variable `gnark:",public"`
+
+	Entries []panelEntry
+
+	Hash HashAlgorithm `gnark:"-"`
+}
+
+// NewPanelConsistencyCircuit allocates a circuit for a panel of n variants
+// over a tree of depth built with hash.
+func NewPanelConsistencyCircuit(n, depth int, hash HashAlgorithm) *PanelConsistencyCircuit {
+	c := &PanelConsistencyCircuit{Entries: make([]panelEntry, n), Hash: hash}
+	for i := range c.Entries {
+		c.Entries[i].LowSiblings = make([]frontend.Variable, depth)
+		c.Entries[i].HighSiblings = make([]frontend.Variable, depth)
+	}
+	return c
+}
+
+// isLeafBelow returns 1 when leaf (aKey, aID) sorts strictly before
+// (bKey, bID), matching compareLeaves.
+func isLeafBelow(api frontend.API, aKey, aID, bKey, bID frontend.Variable) frontend.Variable {
+	return api.Add(
+		isGreater(api, bKey, aKey),
+		api.Mul(api.IsZero(api.Sub(aKey, bKey)), isGreater(api, bID, aID)),
+	)
+}
+
+func (c *PanelConsistencyCircuit) Define(api frontend.API) error {
+	// The panel hash is published independently of any tree, so it stays
+	// MiMC whichever hash the tree uses.
+	panelHasher, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	for _, e := range c.Entries {
+		panelHasher.Write(e.LocusKey, e.VariantID)
+	}
+	api.AssertIsEqual(panelHasher.Sum(), c.PanelHash)
+
+	h, err := newFieldHasher(api, c.Hash)
+	if err != nil {
+		return err
+	}
+
+	count := frontend.Variable(0)
+	for _, e := range c.Entries {
+		carried := api.Mul(api.IsZero(api.Sub(e.LowLocusKey, e.LocusKey)), api.IsZero(api.Sub(e.LowVariantID, e.VariantID)))
+		count = api.Add(count, carried)
+
+		// Not carried: the variant falls strictly between the two leaves.
+		lowBelow := isLeafBelow(api, e.LowLocusKey, e.LowVariantID, e.LocusKey, e.VariantID)
+		api.AssertIsEqual(api.Mul(api.Sub(1, carried), api.Sub(lowBelow, 1)), 0)
+		api.AssertIsEqual(isLeafBelow(api, e.LocusKey, e.VariantID, e.HighLocusKey, e.HighVariantID), 1)
+
+		h.Reset()
+		h.Write(e.LowLocusKey, e.LowVariantID)
+		low := h.Sum()
+		api.AssertIsEqual(merkleRoot(api, h, low, e.LowIndex, e.LowSiblings), c.Root)
+
+		h.Reset()
+		h.Write(e.HighLocusKey, e.HighVariantID)
+		high := h.Sum()
+		api.AssertIsEqual(merkleRoot(api, h, high, api.Add(e.LowIndex, 1), e.HighSiblings), c.Root)
+	}
+	api.AssertIsEqual(count, c.Count)
+
+	return nil
+}
+
+func (p *PanelProof) treeDepth() int {
+	if p.TreeDepth == 0 {
+		return DefaultTreeDepth
+	}
+	return p.TreeDepth
+}
+
+// panelAssignment builds the witness for panel over tree and returns it with
+// the number of panel variants carried.
+func panelAssignment(panel []VariantSpec, tree *VariantTree) (*PanelConsistencyCircuit, int, error) {
+	hash, err := PanelHash(panel)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	assignment := NewPanelConsistencyCircuit(len(panel), tree.Depth, tree.Hash)
+	assignment.PanelHash = hash
+	assignment.Root = tree.Root()
+
+	count := 0
+	for i, v := range panel {
+		target, err := newVariantLeaf(v)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		lowIndex, carried := tree.findLeaf(target)
+		if !carried {
+			lowIndex--
+		} else {
+			count++
+		}
+		low, high := tree.leaves[lowIndex], tree.leaves[lowIndex+1]
+
+		e := &assignment.Entries[i]
+		e.LocusKey = target.LocusKey
+		e.VariantID = target.VariantID
+		e.LowIndex = lowIndex
+		e.LowLocusKey = low.LocusKey
+		e.LowVariantID = low.VariantID
+		e.HighLocusKey = high.LocusKey
+		e.HighVariantID = high.VariantID
+		for j, sibling := range tree.path(lowIndex) {
+			e.LowSiblings[j] = sibling
+		}
+		for j, sibling := range tree.path(lowIndex + 1) {
+			e.HighSiblings[j] = sibling
+		}
+	}
+	assignment.Count = count
+
+	return assignment, count, nil
+}
+
+func (p *PanelProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+	if len(p.Panel) == 0 {
+		return fmt.Errorf("a reference panel is required")
+	}
+
+	fmt.Println("Building variant tree from VCF...")
+	tree, err := buildVariantTree(vcfPath, p.Sample, p.treeDepth(), p.Imputation, p.Hash, p.Limits)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	assignment, count, err := panelAssignment(p.Panel, tree)
+	if err != nil {
+		return err
+	}
+
+	if err := p.Settings.prove(ctx, "panel", NewPanelConsistencyCircuit(len(p.Panel), tree.Depth, tree.Hash), assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("panel", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven that %d of the %d reference panel variants are carried\n", count, len(p.Panel))
+	fmt.Println("at exactly the panel's loci, without revealing which.")
+	fmt.Printf("Panel hash: %s\n", assignment.PanelHash)
+	fmt.Printf("Variant tree root: %s\n", tree.Root())
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *PanelProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 3 {
+		return false, withKind(ErrVerification, fmt.Errorf("panel proof has %d public inputs, expected 3", len(public)))
+	}
+
+	if len(p.Panel) > 0 {
+		want, err := PanelHash(p.Panel)
+		if err != nil {
+			return false, err
+		}
+		if public[0].Cmp(want) != 0 {
+			return false, withKind(ErrVerification, fmt.Errorf("proof was made over a different reference panel"))
+		}
+		fmt.Println("Proof is bound to the given reference panel")
+	}
+	if p.ExpectedCommitment != "" {
+		if err := checkCommitment(proofPath, 1, p.ExpectedCommitment); err != nil {
+			return false, err
+		}
+	}
+	fmt.Printf("Panel variants carried: %s\n", public[2])
+
+	return verified, nil
+}
@@ -0,0 +1,117 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestPanelConsistencyCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	vcf := writeTempVCF(t, membershipTestVCF)
+	tree, err := BuildVariantTree(vcf, 4, ImputationPolicy{}, HashMiMC, ReadLimits{})
+	if err != nil {
+		t.Fatalf("BuildVariantTree: %v", err)
+	}
+
+	panel := []VariantSpec{
+		mustParseVariant(t, "1:1000:A:G"),
+		mustParseVariant(t, "1:2000:C:T"), // another allele is carried at the locus
+		mustParseVariant(t, "17:41276044:ACT:A"),
+		mustParseVariant(t, "17:41276045:CT:C"),
+	}
+
+	assignment, count, err := panelAssignment(panel, tree)
+	if err != nil {
+		t.Fatalf("panelAssignment: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("count = %d, want 2", count)
+	}
+	if err := test.IsSolved(NewPanelConsistencyCircuit(len(panel), tree.Depth, tree.Hash), assignment, field); err != nil {
+		t.Fatalf("panel consistency should satisfy the circuit: %v", err)
+	}
+
+	wrong, _, _ := panelAssignment(panel, tree)
+	wrong.Count = 3
+	if err := test.IsSolved(NewPanelConsistencyCircuit(len(panel), tree.Depth, tree.Hash), wrong, field); err == nil {
+		t.Errorf("wrong count should not satisfy the circuit")
+	}
+
+	// Substituting a carried variant at a different locus for an uncarried
+	// panel entry changes the panel hash.
+	substituted := append([]VariantSpec{}, panel...)
+	substituted[2] = mustParseVariant(t, "1:2000:C:G")
+	swapped, _, err := panelAssignment(substituted, tree)
+	if err != nil {
+		t.Fatalf("panelAssignment: %v", err)
+	}
+	swapped.PanelHash = assignment.PanelHash
+	if err := test.IsSolved(NewPanelConsistencyCircuit(len(panel), tree.Depth, tree.Hash), swapped, field); err == nil {
+		t.Errorf("substituted loci should not match the panel hash")
+	}
+
+	// A carried entry cannot be bracketed as absent.
+	hidden, _, _ := panelAssignment(panel, tree)
+	hidden.Count = 1
+	e := &hidden.Entries[0]
+	index, _, _ := tree.find(panel[0])
+	low, high := tree.leaves[index-1], tree.leaves[index]
+	e.LowIndex = index - 1
+	e.LowLocusKey, e.LowVariantID = low.LocusKey, low.VariantID
+	e.HighLocusKey, e.HighVariantID = high.LocusKey, high.VariantID
+	for i, sibling := range tree.path(index - 1) {
+		e.LowSiblings[i] = sibling
+	}
+	for i, sibling := range tree.path(index) {
+		e.HighSiblings[i] = sibling
+	}
+	if err := test.IsSolved(NewPanelConsistencyCircuit(len(panel), tree.Depth, tree.Hash), hidden, field); err == nil {
+		t.Errorf("carried panel variant should not be provably absent")
+	}
+}
+
+func TestPanelHash(t *testing.T) {
+	a := []VariantSpec{mustParseVariant(t, "1:1000:A:G"), mustParseVariant(t, "2:500:G:A")}
+	b := []VariantSpec{mustParseVariant(t, "1:1000:A:G"), mustParseVariant(t, "2:501:G:A")}
+
+	ha, err := PanelHash(a)
+	if err != nil {
+		t.Fatalf("PanelHash: %v", err)
+	}
+	hb, err := PanelHash(b)
+	if err != nil {
+		t.Fatalf("PanelHash: %v", err)
+	}
+	if ha.Cmp(hb) == 0 {
+		t.Errorf("panels at different positions should hash differently")
+	}
+
+	if _, err := PanelHash([]VariantSpec{mustParseVariant(t, "Un:1:A:G")}); err == nil {
+		t.Errorf("non-primary chromosome should be rejected")
+	}
+}
+
+func TestPanelProof(t *testing.T) {
+	vcf := writeTempVCF(t, membershipTestVCF)
+	panel := []VariantSpec{mustParseVariant(t, "17:41276044:ACT:A")}
+	out := filepath.Join(t.TempDir(), "panel_proof.bin")
+
+	if err := (&PanelProof{Panel: panel, TreeDepth: 4}).Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := (&PanelProof{Panel: panel}).Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	other := []VariantSpec{mustParseVariant(t, "17:41276045:CT:C")}
+	if _, err := (&PanelProof{Panel: other}).Verify(t.Context(), out+".vk", out); err == nil {
+		t.Errorf("proof should not verify against a different panel")
+	}
+
+	if err := (&PanelProof{TreeDepth: 4}).Generate(t.Context(), vcf, "", out); err == nil {
+		t.Errorf("empty panel should be rejected")
+	}
+}
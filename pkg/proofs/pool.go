@@ -0,0 +1,63 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// GenerateJob is one proof for GenerateAll to generate.
+type GenerateJob struct {
+	Proof      Proof
+	VCF        string
+	ProvingKey string
+	Output     string
+}
+
+// GenerateAll generates jobs on a pool of workers and returns each job's
+// error, nil when its proof was generated. At most workers circuits are
+// compiled and proved at once, which bounds peak memory to about workers
+// times that of the largest circuit; each proof already proves on every
+// core, so a few workers are usually enough to keep the CPU busy through
+// the single-threaded compile and witness steps.
+// Once ctx is done, jobs not yet started fail with its error.
+func GenerateAll(ctx context.Context, jobs []GenerateJob, workers int) ([]error, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("at least one worker is required")
+	}
+	outputs := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		if outputs[job.Output] {
+			return nil, fmt.Errorf("two jobs write the same output %s", job.Output)
+		}
+		outputs[job.Output] = true
+	}
+
+	errs := make([]error, len(jobs))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				start := time.Now()
+				job := jobs[i]
+				errs[i] = job.Proof.Generate(ctx, job.VCF, job.ProvingKey, job.Output)
+				fmt.Printf("Job %d/%d (%s) finished in %s\n", i+1, len(jobs), job.Output, time.Since(start).Round(time.Millisecond))
+			}
+		}()
+	}
+	for i := range jobs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	return errs, nil
+}
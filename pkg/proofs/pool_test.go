@@ -0,0 +1,45 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateAll(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+15	28365619	.	C	T	60	PASS	.	GT	1/1
+`)
+	dir := t.TempDir()
+
+	var jobs []GenerateJob
+	for i, locus := range []string{"15:28365618", "15:28365619", "chrUn:10", "15:1000"} {
+		jobs = append(jobs, GenerateJob{
+			Proof:  &ZygosityProof{Locus: locus},
+			VCF:    vcf,
+			Output: filepath.Join(dir, string(rune('a'+i))+".bin"),
+		})
+	}
+
+	errs, err := GenerateAll(t.Context(), jobs, 2)
+	if err != nil {
+		t.Fatalf("GenerateAll: %v", err)
+	}
+	for i, job := range jobs {
+		if wantErr := i == 2; (errs[i] != nil) != wantErr {
+			t.Errorf("%s: error = %v, want error %v", job.Output, errs[i], wantErr)
+			continue
+		}
+		if errs[i] == nil {
+			if ok, err := job.Proof.Verify(t.Context(), job.Output+".vk", job.Output); err != nil || !ok {
+				t.Errorf("%s: Verify = %v, %v", job.Output, ok, err)
+			}
+		}
+	}
+
+	if _, err := GenerateAll(t.Context(), []GenerateJob{jobs[0], jobs[0]}, 2); err == nil {
+		t.Errorf("jobs writing the same output should be rejected")
+	}
+}
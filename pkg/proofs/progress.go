@@ -0,0 +1,97 @@
+package proofs
+
+import (
+	"cmp"
+	"runtime"
+	"time"
+)
+
+// ProgressStage is a step of generating a proof.
+type ProgressStage string
+
+const (
+	// StageCompile compiles the circuit, or loads it from the circuit
+	// cache.
+	StageCompile ProgressStage = "compile"
+	// StageSetup runs the setup producing a new key pair, or loads the
+	// keys from the key cache. It is skipped when a proving key is given.
+	StageSetup ProgressStage = "setup"
+	// StageProve proves the witness, on a prover daemon for a delegated
+	// proof.
+	StageProve ProgressStage = "prove"
+)
+
+// ProgressEvent reports how far a stage of generating a proof has got.
+// Every stage reports an event when it starts and one with Done set when
+// it ends, successfully or not; setup and proving report in between every
+// progressInterval.
+type ProgressEvent struct {
+	ProofType string        `json:"proof_type"`
+	Stage     ProgressStage `json:"stage"`
+	Done      bool          `json:"done,omitempty"`
+	// Fraction estimates how much of the stage has run, from 0 at its
+	// start to 1 when Done. gnark reports nothing while it runs a stage,
+	// so in between Fraction is the time elapsed over the stage's
+	// duration as EstimateResources estimates it, held below 1.
+	Fraction float64       `json:"fraction"`
+	Elapsed  time.Duration `json:"elapsed"`
+	// Constraints is the size of the compiled circuit, set for setup and
+	// proving.
+	Constraints int `json:"constraints,omitempty"`
+}
+
+// A ProgressFunc receives the progress events of generating a proof. The
+// calls for one proof do not overlap, but proofs generated concurrently,
+// as by GenerateAll, call it concurrently. It should return quickly.
+type ProgressFunc func(ProgressEvent)
+
+// progressInterval is how often a running setup or proof reports progress.
+const progressInterval = time.Second
+
+// stageTimes estimates how long setup and proving a circuit of constraints
+// constraints take with backend over curve, on all CPUs.
+func stageTimes(backend BackendName, curve Curve, constraints int) (setup, prove time.Duration) {
+	backend, curve = cmp.Or(backend, BackendGroth16), cmp.Or(curve, CurveBN254)
+	factor := curveCost[curve] * float64(constraints) / float64(runtime.NumCPU())
+	return time.Duration(factor * float64(setupCost[backend])), time.Duration(factor * float64(proveCost[backend]))
+}
+
+// track reports stage of generating a proof of proofType starting and, when
+// an estimate of its duration is given, its progress until the returned
+// func reports it done.
+func (s Settings) track(proofType string, stage ProgressStage, estimate time.Duration, constraints int) func() {
+	if s.Progress == nil {
+		return func() {}
+	}
+	event := ProgressEvent{ProofType: proofType, Stage: stage, Constraints: constraints}
+	start := time.Now()
+	s.Progress(event)
+
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if estimate <= 0 {
+			<-stop
+			return
+		}
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				event.Elapsed = time.Since(start)
+				event.Fraction = min(float64(event.Elapsed)/float64(estimate), 0.99)
+				s.Progress(event)
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-stopped
+		event.Done, event.Fraction, event.Elapsed = true, 1, time.Since(start)
+		s.Progress(event)
+	}
+}
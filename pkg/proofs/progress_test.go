@@ -0,0 +1,65 @@
+package proofs
+
+import (
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestGenerateProgress(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	var (
+		mu     sync.Mutex
+		events []ProgressEvent
+	)
+	progress := WithProgress(func(e ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	})
+	out := filepath.Join(t.TempDir(), "zygosity_proof.bin")
+	if err := Generate(t.Context(), "zygosity", vcf, out, WithPosition("15:28365618"), progress); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	var stages []string
+	for _, e := range events {
+		if e.ProofType != "zygosity" {
+			t.Errorf("event for proof type %q", e.ProofType)
+		}
+		if e.Done {
+			if e.Fraction != 1 {
+				t.Errorf("%s done at fraction %v", e.Stage, e.Fraction)
+			}
+			stages = append(stages, string(e.Stage)+" done")
+			continue
+		}
+		if e.Fraction >= 1 {
+			t.Errorf("running %s at fraction %v", e.Stage, e.Fraction)
+		}
+		if e.Fraction == 0 {
+			stages = append(stages, string(e.Stage))
+		}
+	}
+	want := []string{"compile", "compile done", "setup", "setup done", "prove", "prove done"}
+	if !slices.Equal(stages, want) {
+		t.Errorf("stages = %v, want %v", stages, want)
+	}
+
+	// With a proving key there is no setup to report.
+	events = nil
+	again := filepath.Join(t.TempDir(), "zygosity_proof.bin")
+	if err := Generate(t.Context(), "zygosity", vcf, again, WithPosition("15:28365618"), WithProvingKey(out+".pk"), progress); err != nil {
+		t.Fatalf("Generate with a proving key: %v", err)
+	}
+	for _, e := range events {
+		if e.Stage == StageSetup {
+			t.Errorf("setup reported with a proving key: %+v", e)
+		}
+	}
+}
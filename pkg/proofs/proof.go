@@ -0,0 +1,427 @@
+package proofs
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Proof generates and verifies proofs of one type. Cancelling ctx, or its
+// deadline passing, stops Generate before its next compile, setup or
+// proving step and makes it return ctx's error at once; gnark cannot stop
+// a step already running, which finishes in the background without
+// writing the proof.
+type Proof interface {
+	Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error
+	Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error)
+}
+
+// Settings holds the witness-construction policies shared by every proof
+// type. The zero value keeps the historical behaviour.
+type Settings struct {
+	Imputation ImputationPolicy
+	// Missing selects how no-calls and proved loci without a record are
+	// treated; the zero value is MissingFail.
+	Missing MissingPolicy
+	// Digest selects whether the digest of the input VCF's records is
+	// recorded in the proof metadata, and whether it is also bound into
+	// the proof as a public input; the zero value records none.
+	Digest DigestMode
+	// ExpectedDigest, when set, is the digest the input VCF's records must
+	// have, such as one from the manifest of an extract. The digest is
+	// then recorded even when Digest is DigestNone.
+	ExpectedDigest string
+	// datasetDigest is the digest validate computed for the input VCF.
+	datasetDigest string
+	// keys receives the circuit hash and key fingerprints of the proof
+	// generated with these settings, for its metadata.
+	keys *keyRecord
+	// proveTime, when set, receives how long proving took, for Benchmark.
+	proveTime *time.Duration
+	// started is when validate started generating the proof, the start of
+	// its extraction span.
+	started time.Time
+	// Filter excludes records failing FILTER, depth or allele frequency
+	// requirements from the genotypes, coverage and structural variants
+	// read. Proofs over variant trees and chromosome lists commit to the
+	// whole VCF and are not filtered.
+	Filter CallFilter
+	// Sample names the sample of a multi-sample VCF whose calls are
+	// proved; empty selects the first sample. Somatic proofs name theirs
+	// with TumorSample and NormalSample instead.
+	Sample string
+	// Provenance, when set, is a lab attestation over the input VCF that is
+	// checked before proving and recorded in the proof metadata.
+	Provenance *ProvenanceAttestation
+	// HolderSecret, when set, adds a public nullifier derived from it and
+	// the proof type, so reuse of the proof can be detected.
+	HolderSecret *big.Int
+	// SignKey, when set, is the holder's Ed25519 key the proof file is
+	// signed with, the signature recorded in its metadata, binding the
+	// proof to the holder's identity.
+	SignKey ed25519.PrivateKey
+	// SignDID, when set, is the did:web identifier of the holder recorded
+	// with the signature, whose DID document lists SignKey. Without it the
+	// holder is named by the did:key of SignKey.
+	SignDID string
+	// Nonce, when set, is a verifier-supplied challenge bound into the proof
+	// as a public input, so a verifier can reject stale proofs.
+	Nonce string
+	// Timestamp, when set, binds the time the proof is issued into it as a
+	// public input. The prover chooses the time, so it stops anyone
+	// holding the proof from changing when it was issued, not the holder
+	// from issuing a new one.
+	Timestamp bool
+	// ValidFor, when positive, also binds the time the proof expires,
+	// ValidFor after it is issued; CheckExpiry rejects it afterwards. It
+	// implies Timestamp.
+	ValidFor time.Duration
+	// issuedAt is the issuance time bound into the proof, fixed by stamp
+	// so the proof and its metadata agree.
+	issuedAt time.Time
+	// Index, when set, is a tabix or CSI index of the bgzip-compressed
+	// input VCF, through which genotypes at the proved loci are read
+	// without scanning the file. When empty, an index next to the VCF is
+	// used if there is one.
+	Index string
+	// Limits bounds how much of the input VCF is read and held in memory;
+	// the zero value is unlimited.
+	Limits ReadLimits
+	// Liftover, when set, lifts the loci whose genotypes are read from
+	// PanelBuild onto the input VCF's build, for VCFs called against
+	// another build. Proofs over variant trees commit to the VCF's own
+	// coordinates and are not lifted.
+	Liftover *Liftover
+	// Region, when set, restricts reading genotypes, coverage, structural
+	// variants and repeats to the records overlapping it; the rest of the
+	// VCF is skipped without being parsed. Proofs over variant trees and
+	// chromosome lists commit to the whole VCF and read all of it.
+	Region *GenomicRegion
+	// shared, when set, holds the calls read in one pass over each VCF for
+	// all the proofs generated with these settings; see ShareExtraction.
+	shared *sharedExtraction
+	// Hash selects the variant tree hash for membership, absence, region and
+	// panel proofs; the zero value is MiMC.
+	Hash HashAlgorithm
+	// Backend selects the proof system; the zero value is Groth16.
+	Backend BackendName
+	// Curve selects the curve proofs are compiled and proved over; the zero
+	// value is BN254.
+	Curve Curve
+	// SRS is the path to a KZG SRS over Curve for PLONK setup. When empty, PLONK
+	// setup samples a development SRS.
+	SRS string
+	// Accelerator selects the hardware Groth16 proofs are computed on; the
+	// zero value is the CPU.
+	Accelerator Accelerator
+	// CCSCacheDir, when set, is a directory compiled circuits are cached in
+	// and reloaded from instead of being recompiled.
+	CCSCacheDir string
+	// KeyCacheDir, when set, is a directory the keys of each setup are
+	// cached in by circuit hash, so the setup runs once per circuit
+	// definition.
+	KeyCacheDir string
+	// Delegate, when set, solves the witness locally and leaves proving to
+	// a prover daemon.
+	Delegate *Delegation
+	// KeyStore, when set, holds the keys read from key files, so proofs
+	// generated and verified with the same keys read them once.
+	KeyStore *KeyStore
+	// Progress, when set, receives the progress of compiling, setting up
+	// and proving, for showing long runs in a UI.
+	Progress ProgressFunc
+	// Force lets Generate replace an existing proof at its output path, and
+	// the keys set up beside it. Without it Generate refuses, since
+	// replacing keys invalidates the proofs already issued against them.
+	Force bool
+}
+
+// validate checks the settings against the input VCF before any proving
+// work starts, computing the VCF's dataset digest when one is recorded.
+func (s *Settings) validate(vcfPath string) error {
+	s.keys = &keyRecord{}
+	s.started = time.Now()
+	if s.ValidFor < 0 {
+		return withKind(ErrInvalidInput, fmt.Errorf("validity period %s is negative", s.ValidFor))
+	}
+	s.stamp()
+	if err := s.Imputation.Validate(); err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+	if _, err := ParseMissingPolicy(string(s.Missing)); err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+	if err := s.Filter.Validate(); err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+	if _, err := ParseDigestMode(string(s.Digest)); err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+	if (s.Digest != "" && s.Digest != DigestNone) || s.ExpectedDigest != "" {
+		digest, err := DatasetDigest(vcfPath, s.Limits)
+		if err != nil {
+			return fmt.Errorf("computing dataset digest: %w", err)
+		}
+		if s.ExpectedDigest != "" && !strings.EqualFold(digest, s.ExpectedDigest) {
+			return withKind(ErrInvalidInput, fmt.Errorf("VCF records have digest %s, not the expected %s", digest, s.ExpectedDigest))
+		}
+		s.datasetDigest = digest
+	}
+	if s.SignKey != nil && s.Delegate != nil && s.Delegate.URL == "" {
+		return withKind(ErrInvalidInput, fmt.Errorf("a proof left to the prover daemon cannot be signed; submit the job to have the proof returned and signed"))
+	}
+	if s.SignDID != "" && s.SignKey == nil {
+		return withKind(ErrInvalidInput, fmt.Errorf("a holder DID needs a signing key"))
+	}
+	if s.Provenance != nil {
+		return withKind(ErrInvalidInput, s.Provenance.VerifyGenome(vcfPath))
+	}
+	return nil
+}
+
+type ChromosomeProof struct {
+	Proof
+	Settings
+	// MaxVariants is the number of chromosome slots in the circuit;
+	// zero means DefaultMaxVariants.
+	MaxVariants int
+	// Target is the chromosome the proof shows is present; zero means
+	// DefaultTargetChromosome. When verifying, a non-zero Target is the
+	// chromosome a verified proof must be for.
+	Target int
+	// Commitment, when set, is a previously published commitment to the
+	// VCF's chromosome list; its salt is reused so the proof binds to it.
+	Commitment *DatasetCommitment
+	// ExpectedCommitment, when set, is the commitment value a verified
+	// proof must carry.
+	ExpectedCommitment string
+}
+
+type EyeColorProof struct {
+	Proof
+	Settings
+}
+
+type BRCA1Proof struct {
+	Proof
+	Settings
+}
+
+type HERC2Proof struct {
+	Proof
+	Settings
+}
+
+// RepeatExpansionProof proves a repeat locus (e.g. HTT CAG) is below its
+// pathogenic threshold. Threshold falls back to the RepeatLoci default.
+type RepeatExpansionProof struct {
+	Proof
+	Settings
+	Locus     string
+	Threshold int
+}
+
+// StarAlleleProof proves the metabolizer phenotype implied by the star-allele
+// diplotype of a pharmacogene (CYP2C19, CYP2C9 or TPMT).
+type StarAlleleProof struct {
+	Proof
+	Settings
+	Gene string
+}
+
+// CompoundHetProof proves whether two distinct pathogenic variants of Gene lie
+// on different haplotypes, revealing only the verdict.
+type CompoundHetProof struct {
+	Proof
+	Settings
+	Gene string
+}
+
+// SomaticProof classifies Variant (chrom:pos:ref:alt) as somatic or germline
+// from a tumor VCF and its matched normal. The normal either comes from
+// NormalVCF or, for paired VCFs, from the NormalSample column. The tumor
+// call must meet Quality.
+type SomaticProof struct {
+	Proof
+	Settings
+	Variant      string
+	NormalVCF    string
+	TumorSample  string
+	NormalSample string
+	Quality      QualityThresholds
+}
+
+// IrisPlexProof proves the eye color category predicted by the six-SNP
+// IrisPlex model.
+type IrisPlexProof struct {
+	Proof
+	Settings
+	// Color, when set, is the eye color (brown, intermediate or blue) a
+	// verified proof must predict.
+	Color string
+}
+
+// CarrierProof commits to whether the subject carries Variant
+// (chrom:pos:ref:alt) so the carrier status can later be counted in a
+// cohort proof. The commitment opening is written next to the proof.
+type CarrierProof struct {
+	Proof
+	Settings
+	Variant string
+}
+
+// CohortProof aggregates participants' carrier proofs into a differentially
+// private count of carriers of Variant.
+type CohortProof struct {
+	Proof
+	Settings
+	Variant string
+	// CarrierProofs are the participants' carrier proof files; each needs
+	// its .vk and opening files alongside.
+	CarrierProofs []string
+	// Epsilon is the differential privacy budget spent on the release.
+	Epsilon float64
+	// SeedFile holds the noise seed CommitCohortSeed drew before the
+	// carrier proofs were collected.
+	SeedFile string
+	// SeedCommitment, when set, is the published commitment the noise seed
+	// must match.
+	SeedCommitment string
+}
+
+// RecursiveProof aggregates Groth16 proofs over BLS12-377 into one BW6-761
+// proof that they all verify.
+type RecursiveProof struct {
+	Proof
+	Settings
+	// Proofs are the proof files to aggregate; each needs its .vk and
+	// metadata files alongside.
+	Proofs []string
+}
+
+// VariantMembershipProof proves Variant (chrom:pos:ref:alt) is carried in
+// a VCF, against the root of a Merkle tree over all its carried variants.
+type VariantMembershipProof struct {
+	Proof
+	Settings
+	Variant string
+	// TreeDepth is the depth of the variant tree; zero means
+	// DefaultTreeDepth.
+	TreeDepth int
+	// ExpectedCommitment, when set, is the tree root a verified proof must
+	// carry.
+	ExpectedCommitment string
+}
+
+// VariantAbsenceProof proves Variant (chrom:pos:ref:alt) is not carried in a
+// VCF, against the root of the same sorted variant tree used for membership.
+type VariantAbsenceProof struct {
+	Proof
+	Settings
+	Variant string
+	// WholeLocus extends the claim to every allele at the variant's locus.
+	WholeLocus bool
+	// TreeDepth is the depth of the variant tree; zero means
+	// DefaultTreeDepth.
+	TreeDepth int
+	// ExpectedCommitment, when set, is the tree root a verified proof must
+	// carry.
+	ExpectedCommitment string
+}
+
+// RegionProof proves at least one variant carried in a VCF lies within
+// Region (chrom:start-end), against the root of the variant tree.
+type RegionProof struct {
+	Proof
+	Settings
+	Region string
+	// TreeDepth is the depth of the variant tree; zero means
+	// DefaultTreeDepth.
+	TreeDepth int
+	// ExpectedCommitment, when set, is the tree root a verified proof must
+	// carry.
+	ExpectedCommitment string
+}
+
+// StructuralVariantProof proves a carried deletion or duplication overlaps
+// Region (chrom:start-end) without revealing its breakpoints.
+type StructuralVariantProof struct {
+	Proof
+	Settings
+	Region string
+	// SVType, when set, restricts the proof to DEL or DUP; otherwise the
+	// first overlapping variant's type is proven.
+	SVType string
+}
+
+// PanelProof proves how many variants of a reference panel are carried,
+// bound to both the panel's hash and the variant tree commitment.
+type PanelProof struct {
+	Proof
+	Settings
+	// Panel lists the reference panel variants in the order they are hashed.
+	// Verify checks the proof against it when set.
+	Panel []VariantSpec
+	// TreeDepth is the depth of the variant tree; zero means
+	// DefaultTreeDepth.
+	TreeDepth int
+	// ExpectedCommitment, when set, is the tree root a verified proof must
+	// carry.
+	ExpectedCommitment string
+}
+
+// BloodTypeProof proves the ABO group and RhD status.
+type BloodTypeProof struct {
+	Proof
+	Settings
+}
+
+// ThresholdProof proves whether at least K variants of Panel are present,
+// as for a carrier screening panel.
+type ThresholdProof struct {
+	Proof
+	Settings
+	Panel []VariantSpec
+	K     int
+}
+
+// ZygosityProof proves whether the subject is heterozygous or homozygous at
+// Locus, given as chrom:pos, from a call meeting Quality.
+type ZygosityProof struct {
+	Proof
+	Settings
+	Locus   string
+	Quality QualityThresholds
+}
+
+// WildTypeProof proves the subject is homozygous reference at Locus, given
+// as chrom:pos, from a covering call or gVCF reference block meeting Quality.
+type WildTypeProof struct {
+	Proof
+	Settings
+	Locus   string
+	Quality QualityThresholds
+}
+
+// HaplotypeProof proves whether two phased variants are in cis or trans,
+// bound to the haplotype tree commitment.
+type HaplotypeProof struct {
+	Proof
+	Settings
+	Variants           [2]string
+	TreeDepth          int
+	ExpectedCommitment string
+}
+
+// DosageProof proves the total ALT allele dosage across Panel, a simple
+// additive score over a fixed set of SNPs.
+type DosageProof struct {
+	Proof
+	Settings
+	Panel []VariantSpec
+}
+
+const HERC2Pos uint64 = 28365618
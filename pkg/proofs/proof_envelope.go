@@ -0,0 +1,259 @@
+package proofs
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// EnvelopeSuffix is appended to a proof path to name the proof envelope
+// exported from it.
+const EnvelopeSuffix = ".json"
+
+// envelopeFormat is the version of the proof envelope's JSON layout.
+const envelopeFormat = 1
+
+// ProofEnvelope is a proof with everything needed to verify it but the
+// verifying key, as one self-describing JSON document. Unlike the proof
+// file and its metadata, its layout is documented and versioned:
+//
+//	{
+//	  "format": 1,
+//	  "type": "zygosity",
+//	  "circuit_id": "<hex SHA-256 of the constraint system>",
+//	  "backend": "groth16",
+//	  "curve": "bn254",
+//	  "proof": "<base64 of the compressed proof>",
+//	  "public_inputs": ["<decimal>", ...],
+//	  "created_at": "...", "issued_at": "...", "expires_at": "...",
+//	  "metadata": { ... },
+//	  "proof_cid": "<IPFS CID>", "verifying_key_cid": "<IPFS CID>"
+//	}
+//
+// Readers reject formats they do not know.
+type ProofEnvelope struct {
+	// Type is the proof type, as NewProof takes it.
+	Type string
+	// CircuitID is the hex SHA-256 of the compiled constraint system the
+	// proof is for; empty for proofs written before proof files had a
+	// header.
+	CircuitID string
+	Backend   BackendName
+	Curve     Curve
+	// Proof is the proof in the backend's compressed encoding.
+	Proof []byte
+	// PublicInputs are every public input of the proof in circuit order,
+	// including the nullifier, nonce, digest and time inputs following the
+	// proof type's own.
+	PublicInputs []*big.Int
+	CreatedAt    time.Time
+	IssuedAt     *time.Time
+	ExpiresAt    *time.Time
+	// Metadata is the proof's metadata, when it has any.
+	Metadata *ProofMetadata
+	// ProofCID and VerifyingKeyCID, set by Publish, are the IPFS CIDs of
+	// the proof file and of the verifying key it verifies against.
+	ProofCID        string
+	VerifyingKeyCID string
+}
+
+// proofEnvelopeJSON is ProofEnvelope's JSON layout.
+type proofEnvelopeJSON struct {
+	Format          int            `json:"format"`
+	Type            string         `json:"type"`
+	CircuitID       string         `json:"circuit_id,omitempty"`
+	Backend         BackendName    `json:"backend"`
+	Curve           Curve          `json:"curve"`
+	Proof           []byte         `json:"proof"`
+	PublicInputs    []string       `json:"public_inputs"`
+	CreatedAt       time.Time      `json:"created_at"`
+	IssuedAt        *time.Time     `json:"issued_at,omitempty"`
+	ExpiresAt       *time.Time     `json:"expires_at,omitempty"`
+	Metadata        *ProofMetadata `json:"metadata,omitempty"`
+	ProofCID        string         `json:"proof_cid,omitempty"`
+	VerifyingKeyCID string         `json:"verifying_key_cid,omitempty"`
+}
+
+// MarshalJSON encodes the envelope in its documented layout, with public
+// inputs as decimal strings.
+func (e ProofEnvelope) MarshalJSON() ([]byte, error) {
+	return json.Marshal(proofEnvelopeJSON{
+		Format:          envelopeFormat,
+		Type:            e.Type,
+		CircuitID:       e.CircuitID,
+		Backend:         e.Backend,
+		Curve:           e.Curve,
+		Proof:           e.Proof,
+		PublicInputs:    bigStrings(e.PublicInputs),
+		CreatedAt:       e.CreatedAt,
+		IssuedAt:        e.IssuedAt,
+		ExpiresAt:       e.ExpiresAt,
+		Metadata:        e.Metadata,
+		ProofCID:        e.ProofCID,
+		VerifyingKeyCID: e.VerifyingKeyCID,
+	})
+}
+
+// UnmarshalJSON decodes an envelope written by MarshalJSON.
+func (e *ProofEnvelope) UnmarshalJSON(data []byte) error {
+	var raw proofEnvelopeJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Format != envelopeFormat {
+		return fmt.Errorf("unsupported proof envelope format %d", raw.Format)
+	}
+	inputs := make([]*big.Int, len(raw.PublicInputs))
+	for i, s := range raw.PublicInputs {
+		v, ok := new(big.Int).SetString(s, 10)
+		if !ok || v.Sign() < 0 {
+			return fmt.Errorf("invalid public input %q", s)
+		}
+		inputs[i] = v
+	}
+	*e = ProofEnvelope{
+		Type:            raw.Type,
+		CircuitID:       raw.CircuitID,
+		Backend:         raw.Backend,
+		Curve:           raw.Curve,
+		Proof:           raw.Proof,
+		PublicInputs:    inputs,
+		CreatedAt:       raw.CreatedAt,
+		IssuedAt:        raw.IssuedAt,
+		ExpiresAt:       raw.ExpiresAt,
+		Metadata:        raw.Metadata,
+		ProofCID:        raw.ProofCID,
+		VerifyingKeyCID: raw.VerifyingKeyCID,
+	}
+	return nil
+}
+
+// ReadProofEnvelope reads the proof at proofPath and its metadata into an
+// envelope.
+func ReadProofEnvelope(proofPath string) (*ProofEnvelope, error) {
+	header, err := ReadProofHeader(proofPath)
+	if err != nil && !errors.Is(err, ErrNoHeader) {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+	proof, publicWitness, err := readProofFile(proofPath)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+	proofBytes, err := marshal(proof)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := witnessValues(publicWitness)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+
+	backend, curve := proofSystem(proofPath)
+	env := &ProofEnvelope{Backend: backend, Curve: curve, Proof: proofBytes, PublicInputs: inputs}
+	if header != nil {
+		env.Type, env.CircuitID = header.Type, header.CircuitHash
+	}
+	if meta, err := ReadMetadata(proofPath); err == nil {
+		env.Metadata = meta
+		env.Type = cmp.Or(env.Type, meta.ProofType)
+		env.CreatedAt, env.IssuedAt, env.ExpiresAt = meta.CreatedAt, meta.IssuedAt, meta.ExpiresAt
+	}
+	return env, nil
+}
+
+// ReadProofEnvelopeFile reads an envelope JSON document from path.
+func ReadProofEnvelopeFile(path string) (*ProofEnvelope, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("reading proof envelope: %w", err))
+	}
+	var env ProofEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("parsing proof envelope: %w", err))
+	}
+	return &env, nil
+}
+
+// IsProofEnvelope reports whether the file at path holds a proof envelope
+// rather than a proof file, which never starts with '{'.
+func IsProofEnvelope(path string) bool {
+	f, err := openFile(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	var buf [64]byte
+	n, _ := f.Read(buf[:])
+	return strings.HasPrefix(strings.TrimSpace(string(buf[:n])), "{")
+}
+
+// WriteProof writes the envelope as a proof file at proofPath with its
+// metadata beside it, as Generate writes them, for the functions reading
+// proofs from files. An envelope without metadata gets metadata holding
+// its type, proof system and times. The header records the envelope inputs
+// the metadata declares.
+func (e *ProofEnvelope) WriteProof(proofPath string) error {
+	if _, err := e.Backend.backend(); err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+	if _, err := ParseCurve(string(e.Curve)); err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+	publicWitness, err := witness.New(e.Curve.id().ScalarField())
+	if err != nil {
+		return withKind(ErrInvalidInput, fmt.Errorf("creating witness: %w", err))
+	}
+	values := make(chan any, len(e.PublicInputs))
+	for _, v := range e.PublicInputs {
+		values <- v
+	}
+	close(values)
+	if err := publicWitness.Fill(len(e.PublicInputs), 0, values); err != nil {
+		return withKind(ErrInvalidInput, fmt.Errorf("filling public witness: %w", err))
+	}
+
+	header := &ProofHeader{Format: proofFormat, Type: e.Type, CircuitHash: e.CircuitID, Backend: e.Backend, Curve: e.Curve}
+	if e.Metadata != nil {
+		header.Envelope = e.Metadata.envelopeLayout()
+	}
+	if err := writeProofFile(proofPath, header, bytes.NewReader(e.Proof), publicWitness); err != nil {
+		return err
+	}
+
+	meta := ProofMetadata{
+		ProofType:   e.Type,
+		CreatedAt:   e.CreatedAt,
+		IssuedAt:    e.IssuedAt,
+		ExpiresAt:   e.ExpiresAt,
+		Backend:     e.Backend,
+		Curve:       e.Curve,
+		CircuitHash: e.CircuitID,
+	}
+	if e.Metadata != nil {
+		meta = *e.Metadata
+	}
+	return writeMetadata(proofPath, meta)
+}
+
+// unpackEnvelope writes the envelope at path as a proof file in a memory
+// store and returns the file's path and a func dropping the store.
+func unpackEnvelope(path string) (string, func(), error) {
+	env, err := ReadProofEnvelopeFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	root, cleanup := newMemoryStore()
+	proofPath := root + "/" + bundleProof
+	if err := env.WriteProof(proofPath); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return proofPath, cleanup, nil
+}
@@ -0,0 +1,80 @@
+package proofs
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProofEnvelope(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	if err := Generate(t.Context(), "zygosity", vcf, out, WithPosition("15:28365618"), WithNonce("challenge"), WithValidFor(time.Hour), WithSignKey(key)); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	env, err := ReadProofEnvelope(out)
+	if err != nil {
+		t.Fatalf("ReadProofEnvelope: %v", err)
+	}
+	if env.Type != "zygosity" || env.CircuitID == "" || env.Backend != BackendGroth16 || env.Curve != CurveBN254 || env.ExpiresAt == nil {
+		t.Errorf("envelope = %+v", env)
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	envPath := out + EnvelopeSuffix
+	if err := os.WriteFile(envPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The proof file rebuilt from the envelope is the one it was read
+	// from, so the holder's signature over it still holds.
+	back, err := ReadProofEnvelopeFile(envPath)
+	if err != nil {
+		t.Fatalf("ReadProofEnvelopeFile: %v", err)
+	}
+	rebuilt := filepath.Join(dir, "rebuilt.bin")
+	if err := back.WriteProof(rebuilt); err != nil {
+		t.Fatalf("WriteProof: %v", err)
+	}
+	want, _ := os.ReadFile(out)
+	got, _ := os.ReadFile(rebuilt)
+	if !bytes.Equal(got, want) {
+		t.Errorf("rebuilt proof file differs from the original")
+	}
+
+	if !IsProofEnvelope(envPath) || IsProofEnvelope(out) {
+		t.Errorf("IsProofEnvelope misclassified the envelope or the proof file")
+	}
+	if res, err := Verify(t.Context(), envPath, WithNonce("challenge"), WithHolderKey(pub), WithPosition("15:28365618")); err != nil || !res.Valid {
+		t.Fatalf("Verify of the envelope = %+v, %v", res, err)
+	}
+	if _, err := Verify(t.Context(), envPath, WithNonce("old")); !errors.Is(ErrorKind(err), ErrVerification) {
+		t.Errorf("Verify of the envelope with a stale nonce: %v", err)
+	}
+
+	var e ProofEnvelope
+	if err := json.Unmarshal([]byte(strings.Replace(string(data), `"format":1`, `"format":2`, 1)), &e); err == nil {
+		t.Errorf("Unmarshal accepted an unknown envelope format")
+	}
+	if err := json.Unmarshal([]byte(strings.Replace(string(data), `"public_inputs":["`, `"public_inputs":["-`, 1)), &e); err == nil {
+		t.Errorf("Unmarshal accepted a negative public input")
+	}
+}
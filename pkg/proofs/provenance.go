@@ -0,0 +1,237 @@
+package proofs
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+)
+
+// ProvenanceStatement is what a sequencing lab attests to about a genome.
+type ProvenanceStatement struct {
+	LabID           string    `json:"lab_id"`
+	Platform        string    `json:"platform"`
+	SequencedAt     time.Time `json:"sequenced_at"`
+	PipelineVersion string    `json:"pipeline_version"`
+	// GenomeDigest is the hex SHA-256 of the VCF the statement covers.
+	GenomeDigest string `json:"genome_digest"`
+}
+
+// ProvenanceAttestation is a ProvenanceStatement signed by the lab's Ed25519
+// key.
+type ProvenanceAttestation struct {
+	Statement ProvenanceStatement `json:"statement"`
+	PublicKey []byte              `json:"public_key"`
+	Signature []byte              `json:"signature"`
+}
+
+// ProvenancePolicy is what a verifier requires of a proof's provenance.
+type ProvenancePolicy struct {
+	Required       bool
+	TrustedKeys    []ed25519.PublicKey
+	AccreditedLabs []string
+	SequencedAfter time.Time
+}
+
+// GenomeDigest returns the hex SHA-256 of the file at vcfPath.
+func GenomeDigest(vcfPath string) (string, error) {
+	f, err := openFile(vcfPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hashing VCF: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (s ProvenanceStatement) signingBytes() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// SignProvenance signs statement with the lab's private key.
+func SignProvenance(statement ProvenanceStatement, key ed25519.PrivateKey) (*ProvenanceAttestation, error) {
+	msg, err := statement.signingBytes()
+	if err != nil {
+		return nil, fmt.Errorf("encoding provenance statement: %w", err)
+	}
+
+	return &ProvenanceAttestation{
+		Statement: statement,
+		PublicKey: key.Public().(ed25519.PublicKey),
+		Signature: ed25519.Sign(key, msg),
+	}, nil
+}
+
+// VerifySignature checks the attestation was signed by its embedded key.
+func (a *ProvenanceAttestation) VerifySignature() error {
+	if len(a.PublicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("provenance public key has invalid length %d", len(a.PublicKey))
+	}
+
+	msg, err := a.Statement.signingBytes()
+	if err != nil {
+		return fmt.Errorf("encoding provenance statement: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(a.PublicKey), msg, a.Signature) {
+		return fmt.Errorf("provenance signature is invalid")
+	}
+	return nil
+}
+
+// VerifyGenome checks the signature and that the attestation covers the VCF
+// at vcfPath.
+func (a *ProvenanceAttestation) VerifyGenome(vcfPath string) error {
+	if err := a.VerifySignature(); err != nil {
+		return withKind(ErrVerification, err)
+	}
+
+	digest, err := GenomeDigest(vcfPath)
+	if err != nil {
+		return err
+	}
+	if digest != a.Statement.GenomeDigest {
+		return fmt.Errorf("provenance attestation covers genome %s, not this VCF (%s)", a.Statement.GenomeDigest, digest)
+	}
+	return nil
+}
+
+// CheckProvenance applies policy to the attestation recorded in meta.
+func CheckProvenance(meta *ProofMetadata, policy ProvenancePolicy) error {
+	a := meta.Provenance
+	if a == nil {
+		if policy.Required {
+			return withKind(ErrVerification, fmt.Errorf("proof carries no provenance attestation"))
+		}
+		return nil
+	}
+
+	if err := a.VerifySignature(); err != nil {
+		return err
+	}
+
+	if len(policy.TrustedKeys) > 0 && !slices.ContainsFunc(policy.TrustedKeys, func(k ed25519.PublicKey) bool {
+		return k.Equal(ed25519.PublicKey(a.PublicKey))
+	}) {
+		return withKind(ErrVerification, fmt.Errorf("provenance was signed by an untrusted key"))
+	}
+
+	if len(policy.AccreditedLabs) > 0 && !slices.Contains(policy.AccreditedLabs, a.Statement.LabID) {
+		return withKind(ErrVerification, fmt.Errorf("lab %s is not accredited", a.Statement.LabID))
+	}
+
+	if !policy.SequencedAfter.IsZero() && a.Statement.SequencedAt.Before(policy.SequencedAfter) {
+		return withKind(ErrVerification, fmt.Errorf("genome was sequenced on %s, before the required %s",
+			a.Statement.SequencedAt.Format(time.DateOnly), policy.SequencedAfter.Format(time.DateOnly)))
+	}
+
+	return nil
+}
+
+// ReadProvenance loads an attestation written by WriteProvenance.
+func ReadProvenance(path string) (*ProvenanceAttestation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading provenance attestation: %w", err)
+	}
+
+	var a ProvenanceAttestation
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("parsing provenance attestation: %w", err)
+	}
+	return &a, nil
+}
+
+// WriteProvenance saves the attestation as JSON.
+func WriteProvenance(path string, a *ProvenanceAttestation) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding provenance attestation: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// GenerateSigningKey creates an Ed25519 key pair and writes it as PEM to
+// path+".key" (PKCS#8) and path+".pub" (PKIX).
+func GenerateSigningKey(path string) error {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return fmt.Errorf("generating key: %w", err)
+	}
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return fmt.Errorf("encoding private key: %w", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return fmt.Errorf("encoding public key: %w", err)
+	}
+
+	if err := os.WriteFile(path+".key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0600); err != nil {
+		return fmt.Errorf("writing private key: %w", err)
+	}
+	if err := os.WriteFile(path+".pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0644); err != nil {
+		return fmt.Errorf("writing public key: %w", err)
+	}
+	return nil
+}
+
+// ReadSigningKey loads a PEM-encoded Ed25519 private key.
+func ReadSigningKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 private key", path)
+	}
+	return priv, nil
+}
+
+// ReadPublicKey loads a PEM-encoded Ed25519 public key.
+func ReadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 public key", path)
+	}
+	return pub, nil
+}
+
+func readPEM(path string, blockType string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s does not contain a PEM %s", path, blockType)
+	}
+	return block, nil
+}
@@ -0,0 +1,98 @@
+package proofs
+
+import (
+	"crypto/ed25519"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func signedTestAttestation(t *testing.T, vcfPath string) (*ProvenanceAttestation, ed25519.PublicKey) {
+	t.Helper()
+
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	digest, err := GenomeDigest(vcfPath)
+	if err != nil {
+		t.Fatalf("GenomeDigest: %v", err)
+	}
+
+	a, err := SignProvenance(ProvenanceStatement{
+		LabID:           "LAB-1",
+		Platform:        "NovaSeq",
+		SequencedAt:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		PipelineVersion: "v1",
+		GenomeDigest:    digest,
+	}, key)
+	if err != nil {
+		t.Fatalf("SignProvenance: %v", err)
+	}
+	return a, pub
+}
+
+func TestProvenanceVerifyGenome(t *testing.T) {
+	vcf := writeTempVCF(t, "##fileformat=VCFv4.2\n#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\n")
+	other := writeTempVCF(t, "##fileformat=VCFv4.3\n#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\n")
+	a, _ := signedTestAttestation(t, vcf)
+
+	if err := a.VerifyGenome(vcf); err != nil {
+		t.Fatalf("VerifyGenome on attested VCF: %v", err)
+	}
+	if err := a.VerifyGenome(other); err == nil {
+		t.Errorf("VerifyGenome accepted a different VCF")
+	}
+
+	a.Statement.LabID = "LAB-2"
+	if err := a.VerifySignature(); err == nil {
+		t.Errorf("VerifySignature accepted a tampered statement")
+	}
+}
+
+func TestCheckProvenance(t *testing.T) {
+	vcf := writeTempVCF(t, "##fileformat=VCFv4.2\n#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\n")
+	a, pub := signedTestAttestation(t, vcf)
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+
+	tests := []struct {
+		name    string
+		meta    *ProofMetadata
+		policy  ProvenancePolicy
+		wantErr bool
+	}{
+		{"no policy, no attestation", &ProofMetadata{}, ProvenancePolicy{}, false},
+		{"required, no attestation", &ProofMetadata{}, ProvenancePolicy{Required: true}, true},
+		{"trusted key", &ProofMetadata{Provenance: a}, ProvenancePolicy{TrustedKeys: []ed25519.PublicKey{pub}}, false},
+		{"untrusted key", &ProofMetadata{Provenance: a}, ProvenancePolicy{TrustedKeys: []ed25519.PublicKey{otherPub}}, true},
+		{"accredited lab", &ProofMetadata{Provenance: a}, ProvenancePolicy{AccreditedLabs: []string{"LAB-1"}}, false},
+		{"unaccredited lab", &ProofMetadata{Provenance: a}, ProvenancePolicy{AccreditedLabs: []string{"LAB-9"}}, true},
+		{"sequenced too early", &ProofMetadata{Provenance: a}, ProvenancePolicy{SequencedAfter: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}, true},
+	}
+
+	for _, tt := range tests {
+		err := CheckProvenance(tt.meta, tt.policy)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: CheckProvenance error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSigningKeyRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lab")
+	if err := GenerateSigningKey(path); err != nil {
+		t.Fatalf("GenerateSigningKey: %v", err)
+	}
+
+	key, err := ReadSigningKey(path + ".key")
+	if err != nil {
+		t.Fatalf("ReadSigningKey: %v", err)
+	}
+	pub, err := ReadPublicKey(path + ".pub")
+	if err != nil {
+		t.Fatalf("ReadPublicKey: %v", err)
+	}
+	if !pub.Equal(key.Public()) {
+		t.Errorf("public key does not match private key")
+	}
+}
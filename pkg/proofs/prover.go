@@ -0,0 +1,364 @@
+package proofs
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// loadOrSetupProvingKey loads the proving key at provingKeyPath, through
+// the settings' key store when they have one, or runs a fresh setup with
+// backend when it is empty and saves the new key pair next to outputPath as
+// .pk and .vk files. With a key cache directory, the setup runs once per
+// circuit and later calls reuse its keys.
+func (s Settings) loadOrSetupProvingKey(ctx context.Context, proofType string, backend Backend, cs constraint.ConstraintSystem, provingKeyPath string, outputPath string) (Serializable, error) {
+	if provingKeyPath != "" {
+		if err := checkKeyCircuit(cs, provingKeyPath); err != nil {
+			return nil, withKind(ErrInvalidInput, err)
+		}
+		fmt.Println("Loading existing proving key...")
+		_, span := startSpan(ctx, SpanSetup, attribute.String("proof.type", proofType), attribute.String("proofs.key", provingKeyPath))
+		pk, err := s.KeyStore.ProvingKey(provingKeyPath, s.Backend, s.Curve)
+		endSpan(span, err)
+		if err != nil {
+			return nil, withKind(ErrInvalidInput, err)
+		}
+		return pk, nil
+	}
+
+	setupTime, _ := stageTimes(s.Backend, s.Curve, cs.GetNbConstraints())
+	done := s.track(proofType, StageSetup, setupTime, cs.GetNbConstraints())
+	_, span := startSpan(ctx, SpanSetup, attribute.String("proof.type", proofType), attribute.Int("proofs.constraints", cs.GetNbConstraints()))
+	keys, err := runContext(ctx, func() ([2]Serializable, error) {
+		pk, vk, err := s.setupKeys(backend, cs)
+		return [2]Serializable{pk, vk}, err
+	})
+	done()
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	pk, vk := keys[0], keys[1]
+
+	err = writeKeyPair(outputPath, pk, vk)
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Keys saved to: %s.pk and %s.vk\n", outputPath, outputPath)
+	return pk, nil
+}
+
+// writeKeyPair writes pk and vk to path with .pk and .vk appended.
+func writeKeyPair(path string, pk, vk Serializable) error {
+	if err := writeFileAtomic(path+".pk", 0644, writeTo(pk)); err != nil {
+		return fmt.Errorf("writing proving key: %w", err)
+	}
+	if err := writeFileAtomic(path+".vk", 0644, writeTo(vk)); err != nil {
+		return fmt.Errorf("writing verifying key: %w", err)
+	}
+	return nil
+}
+
+// generateProof compiles circuit, obtains a proving key, proves assignment
+// with the settings' backend and curve and writes the proof of proofType
+// with its public witness to outputPath. With a delegation, proving is left
+// to the prover daemon. Once ctx is done generateProof returns its error
+// without waiting for the running step; nothing more is written.
+func (s Settings) generateProof(ctx context.Context, proofType string, circuit frontend.Circuit, assignment frontend.Circuit, provingKeyPath string, outputPath string) error {
+	backend, err := s.Backend.backend()
+	if err != nil {
+		return err
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("proof.type", proofType),
+		attribute.String("proofs.backend", string(cmp.Or(s.Backend, BackendGroth16))),
+		attribute.String("proofs.curve", string(cmp.Or(s.Curve, CurveBN254))),
+	}
+	done := s.track(proofType, StageCompile, 0, 0)
+	_, span := startSpan(ctx, SpanCompile, attrs...)
+	cs, err := runContext(ctx, func() (constraint.ConstraintSystem, error) {
+		return s.compileCircuit(backend, circuit)
+	})
+	done()
+	endSpan(span, err)
+	if err != nil {
+		return err
+	}
+	constraints := cs.GetNbConstraints()
+	attrs = append(attrs, attribute.Int("proofs.constraints", constraints))
+	_, proveTime := stageTimes(s.Backend, s.Curve, constraints)
+
+	if s.Delegate != nil {
+		done := s.track(proofType, StageProve, proveTime, constraints)
+		defer done()
+		ctx, span := startSpan(ctx, SpanProve, append(attrs, attribute.Bool("proofs.delegated", true))...)
+		err := s.delegateProof(ctx, proofType, cs, assignment, provingKeyPath, outputPath)
+		endSpan(span, err)
+		return err
+	}
+
+	pk, err := s.loadOrSetupProvingKey(ctx, proofType, backend, cs, provingKeyPath, outputPath)
+	if err != nil {
+		return err
+	}
+	if s.keys != nil {
+		s.keys.record(cs, s.KeyStore, provingKeyPath, outputPath)
+	}
+
+	fmt.Println("Creating witness...")
+	w, err := frontend.NewWitness(assignment, s.Curve.id().ScalarField())
+	if err != nil {
+		return fmt.Errorf("witness creation error: %w", err)
+	}
+
+	publicWitness, err := w.Public()
+	if err != nil {
+		return fmt.Errorf("public witness error: %w", err)
+	}
+
+	fmt.Println("Generating proof...")
+	start := time.Now()
+	done = s.track(proofType, StageProve, proveTime, constraints)
+	_, span = startSpan(ctx, SpanProve, attrs...)
+	proof, err := runContext(ctx, func() (Serializable, error) {
+		return backend.Prove(cs, s.Curve, s.Accelerator, pk, w)
+	})
+	done()
+	endSpan(span, err)
+	if err != nil {
+		return fmt.Errorf("proving error: %w", err)
+	}
+	if s.proveTime != nil {
+		*s.proveTime = time.Since(start)
+	}
+
+	header, err := newProofHeader(proofType, cs, s.Backend, s.Curve)
+	if err != nil {
+		return err
+	}
+	header.Envelope = envelopeLayout(circuit)
+	_, span = startSpan(ctx, SpanSerialize, attrs...)
+	err = writeProofFile(outputPath, header, proof, publicWitness)
+	endSpan(span, err)
+	return err
+}
+
+// runContext runs f and returns its result, or ctx's error as soon as ctx
+// is done. The gnark calls f makes cannot be interrupted, so a cancelled f
+// finishes in the background and its result is dropped.
+func runContext[T any](ctx context.Context, f func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := f()
+		done <- result{value, err}
+	}()
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// writeProofFile writes the header, proof and public witness as a format 2
+// proof file, or, with a nil header, in the headerless layout of files
+// written before proof files had a header.
+func writeProofFile(outputPath string, header *ProofHeader, proof io.WriterTo, publicWitness witness.Witness) error {
+	err := writeFileAtomic(outputPath, 0644, func(w io.Writer) error {
+		return encodeProof(w, header, proof, publicWitness)
+	})
+	if err != nil {
+		return fmt.Errorf("writing proof file: %w", err)
+	}
+	return nil
+}
+
+// encodeProof writes the proof file format to outFile.
+func encodeProof(outFile io.Writer, header *ProofHeader, proof io.WriterTo, publicWitness witness.Witness) error {
+	publicWitnessData, err := publicWitness.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("serializing public witness: %w", err)
+	}
+	if header != nil {
+		var proofData bytes.Buffer
+		if _, err := proof.WriteTo(&proofData); err != nil {
+			return fmt.Errorf("writing proof: %w", err)
+		}
+		return writeProofContainer(outFile, header, proofData.Bytes(), publicWitnessData)
+	}
+
+	// Write proof to file (with point compression)
+	if _, err := proof.WriteTo(outFile); err != nil {
+		return fmt.Errorf("writing proof: %w", err)
+	}
+
+	// Write the size of the public witness data first
+	witnessSize := uint32(len(publicWitnessData))
+	if err := binary.Write(outFile, binary.BigEndian, witnessSize); err != nil {
+		return fmt.Errorf("writing witness size: %w", err)
+	}
+
+	if _, err := outFile.Write(publicWitnessData); err != nil {
+		return fmt.Errorf("writing public witness: %w", err)
+	}
+
+	return nil
+}
+
+// readProofFile reads a proof and public witness written by writeProofFile
+// in any format, for the backend and curve its header records, or else its
+// metadata.
+func readProofFile(proofPath string) (Serializable, witness.Witness, error) {
+	proofFile, err := openFile(proofPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening proof file: %w", err)
+	}
+	defer proofFile.Close()
+	header, sections, err := readProofContainer(proofFile)
+	if err != nil && !errors.Is(err, ErrNoHeader) {
+		return nil, nil, err
+	}
+
+	name, curve := headerProofSystem(proofPath, header)
+	backend, err := name.backend()
+	if err != nil {
+		return nil, nil, err
+	}
+	proof := backend.NewProof(curve)
+	var publicWitnessData []byte
+	if sections != nil {
+		if _, err := proof.ReadFrom(bytes.NewReader(sections[sectionProof])); err != nil {
+			return nil, nil, fmt.Errorf("reading proof: %w", err)
+		}
+		publicWitnessData = sections[sectionWitness]
+	} else {
+		if _, err := proof.ReadFrom(proofFile); err != nil {
+			return nil, nil, fmt.Errorf("reading proof: %w", err)
+		}
+
+		var witnessSize uint32
+		if err := binary.Read(proofFile, binary.BigEndian, &witnessSize); err != nil {
+			return nil, nil, fmt.Errorf("reading witness size: %w", err)
+		}
+
+		publicWitnessData = make([]byte, witnessSize)
+		if _, err := io.ReadFull(proofFile, publicWitnessData); err != nil {
+			return nil, nil, fmt.Errorf("reading public witness data: %w", err)
+		}
+	}
+
+	publicWitness, err := witness.New(curve.id().ScalarField())
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating witness: %w", err)
+	}
+
+	if err := publicWitness.UnmarshalBinary(publicWitnessData); err != nil {
+		return nil, nil, fmt.Errorf("unmarshalling public witness: %w", err)
+	}
+
+	return proof, publicWitness, nil
+}
+
+// verifyProof checks the proof at proofPath against the verifying key at
+// verifyingKeyPath, read through the settings' key store when they have
+// one, using the backend and curve recorded in the proof's header or metadata. A verification is quick, so ctx is checked only before it
+// starts.
+func (s Settings) verifyProof(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	name, curve := proofSystem(proofPath)
+	backend, err := name.backend()
+	if err != nil {
+		return false, withKind(ErrInvalidInput, err)
+	}
+
+	vk, err := s.KeyStore.VerifyingKey(verifyingKeyPath, name, curve)
+	if err != nil {
+		return false, withKind(ErrInvalidInput, err)
+	}
+	header, err := ReadProofHeader(proofPath)
+	if err != nil && !errors.Is(err, ErrNoHeader) {
+		return false, withKind(ErrInvalidInput, err)
+	}
+	if err := checkProofCircuit(verifyingKeyPath, header); err != nil {
+		return false, withKind(ErrInvalidInput, fmt.Errorf("%w%s", err, versionMismatch(proofPath)))
+	}
+
+	proof, publicWitness, err := readProofFile(proofPath)
+	if err != nil {
+		return false, withKind(ErrInvalidInput, err)
+	}
+
+	fmt.Printf("Verifying %s proof over %s...\n", name, curve)
+	if err := backend.Verify(curve, proof, vk, publicWitness); err != nil {
+		return false, withKind(ErrVerification, fmt.Errorf("verification failed: %w%s", err, versionMismatch(proofPath)))
+	}
+
+	fmt.Println("✅ Proof successfully verified!")
+	return true, nil
+}
+
+// versionMismatch names the build that generated the proof at proofPath
+// when its metadata records another build than this one, which explains
+// a failure to verify; it is empty otherwise.
+func versionMismatch(proofPath string) string {
+	meta, err := ReadMetadata(proofPath)
+	if err != nil || meta.Software == nil {
+		return ""
+	}
+	build := BuildVersion()
+	if theirs := *meta.Software; theirs.Version == build.Version && theirs.Commit == build.Commit &&
+		theirs.Gnark == build.Gnark && theirs.GnarkCrypto == build.GnarkCrypto {
+		return ""
+	}
+	return fmt.Sprintf(" (the proof was generated by %s; this is %s)", meta.Software, build)
+}
+
+// readVerifyingKey reads a backend verifying key over curve.
+func readVerifyingKey(verifyingKeyPath string, backend Backend, curve Curve) (Serializable, error) {
+	vk, _, err := readKey(verifyingKeyPath, false, backend, curve)
+	return vk, err
+}
+
+// readPublicInputs returns the public inputs of the proved circuit, in
+// circuit field order, without the envelope's nullifier and nonce.
+func readPublicInputs(proofPath string) ([]*big.Int, error) {
+	env, err := readEnvelope(proofPath)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+	return env.Inputs, nil
+}
+
+// readWitnessValues returns every public witness value stored in the proof
+// file, in circuit field order.
+func readWitnessValues(proofPath string) ([]*big.Int, error) {
+	_, publicWitness, err := readProofFile(proofPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return witnessValues(publicWitness)
+}
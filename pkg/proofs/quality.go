@@ -0,0 +1,65 @@
+package proofs
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// qualityBits bounds the call quality and depth values a circuit compares.
+const qualityBits = 32
+
+// QualityThresholds are the public minimum call quality (Phred-scaled QUAL,
+// rounded down) and FORMAT/DP read depth a proven call must meet. The zero
+// value accepts any call.
+type QualityThresholds struct {
+	MinQuality int
+	MinDepth   int
+}
+
+func (q QualityThresholds) validate() error {
+	if q.MinQuality < 0 || q.MinDepth < 0 || q.MinQuality >= 1<<qualityBits || q.MinDepth >= 1<<qualityBits {
+		return fmt.Errorf("quality thresholds must be between 0 and 2^%d", qualityBits)
+	}
+	return nil
+}
+
+// check reports whether a genotype meets the thresholds. A call absent from
+// the VCF has no quality, so it only meets zero thresholds.
+func (q QualityThresholds) check(g Genotype) error {
+	if quality := callQuality(g); quality < q.MinQuality {
+		return fmt.Errorf("call quality %d is below the minimum %d", quality, q.MinQuality)
+	}
+	if g.Depth < q.MinDepth {
+		return fmt.Errorf("read depth %d is below the minimum %d", g.Depth, q.MinDepth)
+	}
+	return nil
+}
+
+// atLeast reports whether public thresholds from a proof are at least as
+// strict as q.
+func (q QualityThresholds) atLeast(minQuality, minDepth int64) error {
+	if minQuality < int64(q.MinQuality) {
+		return withKind(ErrVerification, fmt.Errorf("proof requires call quality %d, below the required %d", minQuality, q.MinQuality))
+	}
+	if minDepth < int64(q.MinDepth) {
+		return withKind(ErrVerification, fmt.Errorf("proof requires read depth %d, below the required %d", minDepth, q.MinDepth))
+	}
+	return nil
+}
+
+// callQuality returns the genotype's QUAL as the integer the circuit sees.
+func callQuality(g Genotype) int {
+	return int(min(math.Floor(g.Quality), 1<<qualityBits-1))
+}
+
+// assertCallQuality constrains a private call quality and depth to meet
+// public minimums.
+func assertCallQuality(api frontend.API, quality, depth, minQuality, minDepth frontend.Variable) {
+	for _, v := range []frontend.Variable{quality, depth, minQuality, minDepth} {
+		api.ToBinary(v, qualityBits)
+	}
+	api.AssertIsEqual(isAtLeast(api, quality, minQuality, qualityBits), 1)
+	api.AssertIsEqual(isAtLeast(api, depth, minDepth, qualityBits), 1)
+}
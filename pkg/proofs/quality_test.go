@@ -0,0 +1,100 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type callQualityCircuit struct {
+	MinQuality frontend.Variable `gnark:",public"`
+	MinDepth   frontend.Variable `gnark:",public"`
+	Quality    frontend.Variable
+	Depth      frontend.Variable
+}
+
+func (c *callQualityCircuit) Define(api frontend.API) error {
+	assertCallQuality(api, c.Quality, c.Depth, c.MinQuality, c.MinDepth)
+	return nil
+}
+
+func TestAssertCallQuality(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+
+	tests := []struct {
+		minQuality, minDepth, quality, depth int
+		valid                                bool
+	}{
+		{30, 10, 60, 25, true},
+		{30, 10, 30, 10, true},
+		{0, 0, 0, 0, true},
+		{30, 10, 29, 25, false},
+		{30, 10, 60, 9, false},
+		{30, 10, -1, 25, false}, // wraps to a huge field element
+		{-1, 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		assignment := &callQualityCircuit{MinQuality: tt.minQuality, MinDepth: tt.minDepth, Quality: tt.quality, Depth: tt.depth}
+		err := test.IsSolved(&callQualityCircuit{}, assignment, field)
+		if tt.valid && err != nil {
+			t.Errorf("%+v: rejected: %v", tt, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%+v: accepted", tt)
+		}
+	}
+}
+
+func TestExtractGenotypesQuality(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=DP,Number=1,Type=Integer,Description="Read depth">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	57.9	PASS	.	GT:DP	0/1:23
+15	28365700	.	C	T	.	PASS	.	GT	0/1
+`)
+	loci := []Locus{{"15", 28365618}, {"15", 28365700}}
+	genotypes, err := extractGenotypes(vcf, Settings{}, locusSpecs(loci...))
+	if err != nil {
+		t.Fatalf("extractGenotypes: %v", err)
+	}
+
+	if g := genotypes[loci[0]]; callQuality(g) != 57 || g.Depth != 23 {
+		t.Errorf("quality, depth = %d, %d; want 57, 23", callQuality(g), g.Depth)
+	}
+	if g := genotypes[loci[1]]; callQuality(g) != 0 || g.Depth != 0 {
+		t.Errorf("missing quality, depth = %d, %d; want 0, 0", callQuality(g), g.Depth)
+	}
+}
+
+func TestZygosityProofQuality(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=DP,Number=1,Type=Integer,Description="Read depth">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	45	PASS	.	GT:DP	0/1:18
+`)
+	out := filepath.Join(t.TempDir(), "zygosity_proof.bin")
+
+	strict := &ZygosityProof{Locus: "15:28365618", Quality: QualityThresholds{MinQuality: 50, MinDepth: 10}}
+	if err := strict.Generate(t.Context(), vcf, "", out); err == nil {
+		t.Errorf("a QUAL 45 call should not meet a minimum of 50")
+	}
+
+	proof := &ZygosityProof{Locus: "15:28365618", Quality: QualityThresholds{MinQuality: 30, MinDepth: 10}}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if verified, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !verified {
+		t.Fatalf("Verify = %v, %v", verified, err)
+	}
+
+	// A verifier demanding more than the proof was made with must reject it.
+	demanding := &ZygosityProof{Quality: QualityThresholds{MinQuality: 40}}
+	if _, err := demanding.Verify(t.Context(), out+".vk", out); err == nil {
+		t.Errorf("proof made with minimum quality 30 accepted by a verifier requiring 40")
+	}
+}
@@ -0,0 +1,175 @@
+package proofs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RawImportStats counts how the rows of a raw genotype file were converted.
+type RawImportStats struct {
+	// Records is the number of VCF records written.
+	Records int
+	// NoCalls is the number of rows the array failed to call.
+	NoCalls int
+	// Indels is the number of insertion and deletion calls, which raw
+	// exports report as I and D without the alleles.
+	Indels int
+	// NoReference is the number of rows at positions whose reference
+	// allele is unknown.
+	NoReference int
+}
+
+// rawChromosomes maps the numeric chromosome codes of AncestryDNA exports
+// and the pseudoautosomal XY of 23andMe and FTDNA exports to VCF names.
+var rawChromosomes = map[string]string{
+	"23": "X",
+	"24": "Y",
+	"25": "X",
+	"26": "MT",
+	"XY": "X",
+}
+
+// ImportRawGenotypes converts the consumer genotyping export at rawPath to a
+// single-sample VCF at outputPath, so every proof type can read it. It reads
+// 23andMe's rsid, chromosome, position and genotype columns, AncestryDNA's
+// separate allele1 and allele2 columns, and the comma-separated form of
+// both used by other vendors.
+//
+// Exports list the called bases but not the reference allele, so ref must
+// supply it; its build must match the export's, GRCh37 for most vendors.
+// Rows without a call or a known reference allele, and indel calls, are
+// skipped and counted in the returned stats.
+func ImportRawGenotypes(rawPath string, outputPath string, sample string, ref ReferenceAlleles) (RawImportStats, error) {
+	var stats RawImportStats
+	in, err := os.Open(rawPath)
+	if err != nil {
+		return stats, fmt.Errorf("opening raw genotypes: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return stats, fmt.Errorf("creating VCF: %w", err)
+	}
+	defer out.Close()
+
+	w := bufio.NewWriter(out)
+	fmt.Fprintf(w, "##fileformat=VCFv4.2\n")
+	fmt.Fprintf(w, "##source=raw genotype import of %s\n", filepath.Base(rawPath))
+	fmt.Fprintf(w, "##FORMAT=<ID=GT,Number=1,Type=String,Description=\"Genotype\">\n")
+	fmt.Fprintf(w, "#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\tFORMAT\t%s\n", sample)
+
+	scanner := bufio.NewScanner(in)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := splitRawRow(line)
+		if strings.EqualFold(fields[0], "rsid") {
+			continue
+		}
+		var calls string
+		switch len(fields) {
+		case 4:
+			calls = fields[3]
+		case 5:
+			calls = fields[3] + fields[4]
+		default:
+			return stats, fmt.Errorf("line %d: expected 4 or 5 columns, got %d", lineNum, len(fields))
+		}
+
+		chrom := strings.ToUpper(normalizeChromosome(fields[1]))
+		if name, ok := rawChromosomes[chrom]; ok {
+			chrom = name
+		}
+		pos, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return stats, fmt.Errorf("line %d: invalid position %q", lineNum, fields[2])
+		}
+
+		alleles := strings.ToUpper(calls)
+		switch {
+		case strings.Trim(alleles, "-0") == "":
+			stats.NoCalls++
+			continue
+		case strings.ContainsAny(alleles, "ID"):
+			stats.Indels++
+			continue
+		case len(alleles) > 2 || strings.Trim(alleles, "ACGT") != "":
+			return stats, fmt.Errorf("line %d: invalid genotype %q", lineNum, calls)
+		}
+
+		refBase, err := ref.Base(chrom, pos)
+		if err != nil {
+			return stats, err
+		}
+		if refBase == "" {
+			stats.NoReference++
+			continue
+		}
+
+		alt, gt := rawRecord(refBase, alleles)
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t.\t.\t.\tGT\t%s\n", chrom, pos, fields[0], refBase, alt, gt)
+		stats.Records++
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, fmt.Errorf("reading raw genotypes: %w", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return stats, fmt.Errorf("writing VCF: %w", err)
+	}
+	return stats, nil
+}
+
+// splitRawRow splits a tab-, comma- or space-separated row, dropping the
+// quotes some vendors put around each field.
+func splitRawRow(line string) []string {
+	var fields []string
+	switch {
+	case strings.Contains(line, "\t"):
+		fields = strings.Split(line, "\t")
+	case strings.Contains(line, ","):
+		fields = strings.Split(line, ",")
+	default:
+		fields = strings.Fields(line)
+	}
+	for i, f := range fields {
+		fields[i] = strings.Trim(strings.TrimSpace(f), `"`)
+	}
+	return fields
+}
+
+// rawRecord returns the ALT column and GT of a call of one or two bases
+// against refBase; a single base is a haploid call on X, Y or MT.
+func rawRecord(refBase string, alleles string) (string, string) {
+	var alts []string
+	var gt []string
+	for _, a := range strings.Split(alleles, "") {
+		if a == refBase {
+			gt = append(gt, "0")
+			continue
+		}
+		i := slices.Index(alts, a)
+		if i < 0 {
+			alts = append(alts, a)
+			i = len(alts) - 1
+		}
+		gt = append(gt, strconv.Itoa(i+1))
+	}
+
+	alt := strings.Join(alts, ",")
+	if alt == "" {
+		alt = "."
+	}
+	return alt, strings.Join(gt, "/")
+}
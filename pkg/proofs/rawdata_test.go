@@ -0,0 +1,127 @@
+package proofs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testFASTA is chr1 as ACGT repeated over 25 lines of 4 bases, and chrM.
+func writeTestFASTA(t *testing.T, withIndex bool) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ref.fa")
+	content := ">chr1 test\n" + strings.Repeat("ACGT\n", 25) + ">chrM\nGATC\nNN\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if withIndex {
+		// Offsets of each sequence's first base, as samtools faidx writes.
+		fai := "chr1\t100\t11\t4\t5\nchrM\t6\t142\t4\t5\n"
+		if err := os.WriteFile(path+".fai", []byte(fai), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestFASTAReference(t *testing.T) {
+	for _, withIndex := range []bool{false, true} {
+		ref, err := OpenFASTA(writeTestFASTA(t, withIndex))
+		if err != nil {
+			t.Fatalf("OpenFASTA: %v", err)
+		}
+		for _, tt := range []struct {
+			chrom string
+			pos   uint64
+			want  string
+		}{
+			{"1", 1, "A"}, {"chr1", 6, "C"}, {"1", 100, "T"}, {"1", 101, ""},
+			{"MT", 2, "A"}, {"chrM", 5, ""}, {"2", 1, ""},
+		} {
+			if got, err := ref.Base(tt.chrom, tt.pos); err != nil || got != tt.want {
+				t.Errorf("index=%v: Base(%s, %d) = %q, %v; want %q", withIndex, tt.chrom, tt.pos, got, err, tt.want)
+			}
+		}
+		ref.Close()
+	}
+}
+
+func TestImportRawGenotypes(t *testing.T) {
+	ref, err := OpenFASTA(writeTestFASTA(t, false))
+	if err != nil {
+		t.Fatalf("OpenFASTA: %v", err)
+	}
+	defer ref.Close()
+
+	dir := t.TempDir()
+	raw := filepath.Join(dir, "genome_23andme.txt")
+	if err := os.WriteFile(raw, []byte(`# This data file generated by 23andMe
+# rsid	chromosome	position	genotype
+rs1	1	5	AG
+rs2	1	7	GG
+rs3	1	8	CG
+i4	1	9	--
+rs5	1	10	DI
+rs6	MT	2	G
+rs7	2	5	AA
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	vcf := filepath.Join(dir, "genome.vcf")
+	stats, err := ImportRawGenotypes(raw, vcf, "me", ref)
+	if err != nil {
+		t.Fatalf("ImportRawGenotypes: %v", err)
+	}
+	if want := (RawImportStats{Records: 4, NoCalls: 1, Indels: 1, NoReference: 1}); stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+
+	loci := []Locus{{"1", 5}, {"1", 7}, {"1", 8}, {"MT", 2}}
+	genotypes, err := extractGenotypes(vcf, Settings{}, locusSpecs(loci...))
+	if err != nil {
+		t.Fatalf("extractGenotypes: %v", err)
+	}
+	for locus, want := range map[Locus]Genotype{
+		{"1", 5}:  {Ref: "A", Alt: []string{"G"}, Alleles: []int{0, 1}},
+		{"1", 7}:  {Ref: "G", Alt: []string{"."}, Alleles: []int{0, 0}},
+		{"1", 8}:  {Ref: "T", Alt: []string{"C", "G"}, Alleles: []int{1, 2}},
+		{"MT", 2}: {Ref: "A", Alt: []string{"G"}, Alleles: []int{1}},
+	} {
+		got := genotypes[locus]
+		if got.Ref != want.Ref || !reflect.DeepEqual(got.Alt, want.Alt) || !reflect.DeepEqual(got.Alleles, want.Alleles) {
+			t.Errorf("%v: genotype = %+v, want %+v", locus, got, want)
+		}
+	}
+
+	// An AncestryDNA export against a panel's reference alleles proves like
+	// a sequenced genome.
+	ancestry := filepath.Join(dir, "AncestryDNA.txt")
+	if err := os.WriteFile(ancestry, []byte(`#AncestryDNA raw data download
+rsid	chromosome	position	allele1	allele2
+rs12913832	15	28365618	G	A
+rs1800407	15	28230318	0	0
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	panel := NewPanelReference([]VariantSpec{{Chromosome: "15", Position: 28365618, Ref: "A", Alt: "G"}})
+	if _, err := ImportRawGenotypes(ancestry, vcf, "me", panel); err != nil {
+		t.Fatalf("ImportRawGenotypes: %v", err)
+	}
+	out := filepath.Join(dir, "zygosity_proof.bin")
+	proof := &ZygosityProof{Locus: "15:28365618"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Errorf("Verify = %v, %v", ok, err)
+	}
+
+	bad := filepath.Join(dir, "bad.txt")
+	os.WriteFile(bad, []byte("rs1\t1\t5\tAX\n"), 0644)
+	if _, err := ImportRawGenotypes(bad, vcf, "me", ref); err == nil {
+		t.Errorf("an invalid genotype should be rejected")
+	}
+}
@@ -0,0 +1,208 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/commitments/pedersen"
+	"github.com/consensys/gnark/std/math/emulated"
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+)
+
+type (
+	innerProof        = stdgroth16.Proof[sw_bls12377.G1Affine, sw_bls12377.G2Affine]
+	innerVerifyingKey = stdgroth16.VerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]
+	innerWitness      = stdgroth16.Witness[sw_bls12377.ScalarField]
+)
+
+// innerLimbs is the number of BW6-761 public inputs that carry one
+// emulated BLS12-377 scalar, least significant limb first.
+const innerLimbs = 4
+
+// aggregationCircuit verifies Groth16 proofs over BLS12-377 inside a
+// BW6-761 circuit. The inner verifying keys are circuit constants, so the
+// outer verifying key pins which circuits were proved; the inner public
+// inputs are re-exposed as the outer proof's public inputs.
+type aggregationCircuit struct {
+	Proofs    []innerProof
+	Witnesses []innerWitness `gnark:",public"`
+
+	VerifyingKeys []innerVerifyingKey `gnark:"-"`
+}
+
+func (c *aggregationCircuit) Define(api frontend.API) error {
+	verifier, err := stdgroth16.NewVerifier[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](api)
+	if err != nil {
+		return err
+	}
+	// Inner public inputs are often small constants such as 0 or 1, which
+	// the default incomplete point arithmetic cannot handle. The prover
+	// supplies the proof points, so check they lie in the right subgroup.
+	for i := range c.Proofs {
+		if err := verifier.AssertProof(c.VerifyingKeys[i], c.Proofs[i], c.Witnesses[i], stdgroth16.WithCompleteArithmetic(), stdgroth16.WithSubgroupCheck()); err != nil {
+			return fmt.Errorf("inner proof %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// AggregatedProof describes one inner proof of a recursive proof.
+type AggregatedProof struct {
+	ProofType string `json:"proof_type"`
+	// Inputs is the number of public inputs the inner proof exposes,
+	// including its nullifier and nonce.
+	Inputs int `json:"inputs"`
+}
+
+// loadInnerProof verifies the proof at proofPath natively and returns it
+// as outer circuit values.
+func (s Settings) loadInnerProof(ctx context.Context, proofPath string) (innerProof, innerVerifyingKey, innerWitness, error) {
+	var (
+		proof innerProof
+		vk    innerVerifyingKey
+		w     innerWitness
+	)
+
+	backend, curve := proofSystem(proofPath)
+	if backend != BackendGroth16 || curve != CurveBLS12377 {
+		return proof, vk, w, fmt.Errorf("proof is %s over %s; recursive aggregation needs groth16 proofs over %s", backend, curve, CurveBLS12377)
+	}
+	if _, err := s.verifyProof(ctx, proofPath+".vk", proofPath); err != nil {
+		return proof, vk, w, err
+	}
+
+	nativeVK, err := readVerifyingKey(proofPath+".vk", groth16Backend{}, curve)
+	if err != nil {
+		return proof, vk, w, err
+	}
+	nativeProof, publicWitness, err := readProofFile(proofPath)
+	if err != nil {
+		return proof, vk, w, err
+	}
+
+	if vk, err = stdgroth16.ValueOfVerifyingKeyFixed[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](nativeVK.(groth16.VerifyingKey)); err != nil {
+		return proof, vk, w, err
+	}
+	if proof, err = stdgroth16.ValueOfProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](nativeProof.(groth16.Proof)); err != nil {
+		return proof, vk, w, err
+	}
+	w, err = stdgroth16.ValueOfWitness[sw_bls12377.ScalarField](publicWitness)
+	return proof, vk, w, err
+}
+
+// Generate is not supported for recursive proofs, which are built from
+// other proofs rather than a VCF; use Aggregate.
+func (p *RecursiveProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	return fmt.Errorf("recursive proofs are aggregated from other proofs, not generated from a VCF")
+}
+
+// Aggregate verifies each inner proof and proves over BW6-761 that all of
+// them verify, so a verifier checks a single proof.
+func (p *RecursiveProof) Aggregate(ctx context.Context, provingKeyPath string, outputPath string) error {
+	if len(p.Proofs) == 0 {
+		return fmt.Errorf("at least one proof is required")
+	}
+	if p.Settings.Backend == BackendPlonk {
+		return fmt.Errorf("recursive proofs only support the groth16 backend")
+	}
+	settings := p.Settings
+	settings.Curve = CurveBW6761
+
+	n := len(p.Proofs)
+	circuit := &aggregationCircuit{
+		Proofs:        make([]innerProof, n),
+		Witnesses:     make([]innerWitness, n),
+		VerifyingKeys: make([]innerVerifyingKey, n),
+	}
+	assignment := &aggregationCircuit{
+		Proofs:    make([]innerProof, n),
+		Witnesses: make([]innerWitness, n),
+	}
+	aggregated := make([]AggregatedProof, n)
+
+	for i, proofPath := range p.Proofs {
+		fmt.Printf("Checking proof %s...\n", proofPath)
+		proof, vk, w, err := p.loadInnerProof(ctx, proofPath)
+		if err != nil {
+			return fmt.Errorf("proof %s: %w", proofPath, err)
+		}
+		meta, err := ReadMetadata(proofPath)
+		if err != nil {
+			return fmt.Errorf("proof %s: %w", proofPath, err)
+		}
+
+		circuit.Proofs[i].Commitments = make([]pedersen.Commitment[sw_bls12377.G1Affine], len(proof.Commitments))
+		circuit.Witnesses[i].Public = make([]emulated.Element[sw_bls12377.ScalarField], len(w.Public))
+		circuit.VerifyingKeys[i] = vk
+		assignment.Proofs[i], assignment.Witnesses[i] = proof, w
+		aggregated[i] = AggregatedProof{ProofType: meta.ProofType, Inputs: len(w.Public)}
+	}
+
+	settings.stamp()
+	if err := settings.prove(ctx, "recursive", circuit, assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	meta := newMetadata("recursive", settings)
+	meta.Aggregated = aggregated
+	if err := writeMetadata(outputPath, meta); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven that all %d proofs verify in a single proof.\n", n)
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+// Verify checks the recursive proof and prints each inner proof's public
+// inputs.
+func (p *RecursiveProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	meta, err := ReadMetadata(proofPath)
+	if err != nil {
+		return false, err
+	}
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+
+	want := 0
+	for _, inner := range meta.Aggregated {
+		want += inner.Inputs * innerLimbs
+	}
+	if len(public) != want {
+		return false, withKind(ErrVerification, fmt.Errorf("recursive proof has %d public inputs, its metadata declares %d", len(public), want))
+	}
+
+	for i, inner := range meta.Aggregated {
+		inputs := make([]*big.Int, inner.Inputs)
+		for j := range inputs {
+			inputs[j] = joinLimbs(public[:innerLimbs])
+			public = public[innerLimbs:]
+		}
+		fmt.Printf("Proof %d (%s) public inputs: %v\n", i+1, inner.ProofType, inputs)
+	}
+
+	return verified, nil
+}
+
+// joinLimbs recombines the 64-bit limbs of an emulated field element.
+func joinLimbs(limbs []*big.Int) *big.Int {
+	v := new(big.Int)
+	for i := len(limbs) - 1; i >= 0; i-- {
+		v.Lsh(v, 64)
+		v.Add(v, limbs[i])
+	}
+	return v
+}
@@ -0,0 +1,83 @@
+package proofs
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+// TestAggregationCircuit checks the BW6-761 circuit with test.IsSolved;
+// a full Aggregate run takes minutes.
+func TestAggregationCircuit(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+7	117199644	.	A	T	60	PASS	.	GT	0/0
+`)
+	dir := t.TempDir()
+	inner := Settings{Curve: CurveBLS12377}
+
+	zygosity := filepath.Join(dir, "zygosity_proof.bin")
+	if err := (&ZygosityProof{Settings: inner, Locus: "15:28365618"}).Generate(t.Context(), vcf, "", zygosity); err != nil {
+		t.Fatalf("Generate zygosity: %v", err)
+	}
+	wildtype := filepath.Join(dir, "wildtype_proof.bin")
+	if err := (&WildTypeProof{Settings: inner, Locus: "7:117199644"}).Generate(t.Context(), vcf, "", wildtype); err != nil {
+		t.Fatalf("Generate wildtype: %v", err)
+	}
+
+	circuit := &aggregationCircuit{}
+	assignment := &aggregationCircuit{}
+	for _, proofPath := range []string{zygosity, wildtype} {
+		proof, vk, w, err := Settings{}.loadInnerProof(t.Context(), proofPath)
+		if err != nil {
+			t.Fatalf("loadInnerProof: %v", err)
+		}
+		circuit.Proofs = append(circuit.Proofs, innerProof{})
+		circuit.Witnesses = append(circuit.Witnesses, innerWitness{Public: make([]emulated.Element[sw_bls12377.ScalarField], len(w.Public))})
+		circuit.VerifyingKeys = append(circuit.VerifyingKeys, vk)
+		assignment.Proofs = append(assignment.Proofs, proof)
+		assignment.Witnesses = append(assignment.Witnesses, w)
+	}
+
+	field := ecc.BW6_761.ScalarField()
+	if err := test.IsSolved(circuit, assignment, field); err != nil {
+		t.Fatalf("aggregation of valid proofs should satisfy the circuit: %v", err)
+	}
+
+	// Each proof only verifies under its own circuit's key.
+	swapped := *assignment
+	swapped.Proofs = []innerProof{assignment.Proofs[1], assignment.Proofs[0]}
+	if err := test.IsSolved(circuit, &swapped, field); err == nil {
+		t.Errorf("proofs paired with the wrong verifying keys should not satisfy the circuit")
+	}
+}
+
+func TestRecursiveProofRejectsOtherCurves(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	bn254 := filepath.Join(dir, "zygosity_proof.bin")
+	if err := (&ZygosityProof{Locus: "15:28365618"}).Generate(t.Context(), vcf, "", bn254); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if err := (&RecursiveProof{Proofs: []string{bn254}}).Aggregate(t.Context(), "", filepath.Join(dir, "recursive_proof.bin")); err == nil {
+		t.Errorf("a BN254 proof should not be aggregated")
+	}
+}
+
+func TestJoinLimbs(t *testing.T) {
+	limbs := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(0), big.NewInt(0)}
+	if got := joinLimbs(limbs); got.Cmp(new(big.Int).Add(new(big.Int).Lsh(big.NewInt(2), 64), big.NewInt(1))) != 0 {
+		t.Errorf("joinLimbs = %v", got)
+	}
+}
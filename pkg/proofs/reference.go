@@ -0,0 +1,184 @@
+package proofs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReferenceAlleles looks up reference alleles, for turning genotype calls
+// that only list bases into VCF records.
+type ReferenceAlleles interface {
+	// Base returns the reference base at the 1-based position of chrom, or
+	// "" when it is unknown.
+	Base(chrom string, pos uint64) (string, error)
+}
+
+// faiEntry is a FASTA index line: where a sequence's bases start and how
+// its lines are wrapped.
+type faiEntry struct {
+	length    uint64
+	offset    int64
+	lineBases int64
+	lineWidth int64
+}
+
+// FASTAReference reads reference bases from a FASTA file.
+type FASTAReference struct {
+	f     *os.File
+	index map[string]faiEntry
+}
+
+// OpenFASTA opens the reference genome at path, using its samtools faidx
+// index path+".fai" when there is one and otherwise indexing the file in
+// memory, which reads it once. The caller must close it.
+func OpenFASTA(path string) (*FASTAReference, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening reference: %w", err)
+	}
+
+	var index map[string]faiEntry
+	if fai, err := os.Open(path + ".fai"); err == nil {
+		index, err = readFAI(fai)
+		fai.Close()
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("reading %s.fai: %w", path, err)
+		}
+	} else {
+		if index, err = indexFASTA(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("indexing reference: %w", err)
+		}
+	}
+	return &FASTAReference{f: f, index: index}, nil
+}
+
+func (r *FASTAReference) Close() error {
+	return r.f.Close()
+}
+
+// Base returns the upper-cased reference base, or "" for positions outside
+// the reference and for N.
+func (r *FASTAReference) Base(chrom string, pos uint64) (string, error) {
+	entry, ok := r.index[normalizeChromosome(chrom)]
+	if !ok || pos == 0 || pos > entry.length {
+		return "", nil
+	}
+
+	i := int64(pos - 1)
+	b := make([]byte, 1)
+	if _, err := r.f.ReadAt(b, entry.offset+i/entry.lineBases*entry.lineWidth+i%entry.lineBases); err != nil {
+		return "", fmt.Errorf("reading reference at %s:%d: %w", chrom, pos, err)
+	}
+	base := strings.ToUpper(string(b))
+	if base == "N" {
+		return "", nil
+	}
+	return base, nil
+}
+
+func readFAI(r io.Reader) (map[string]faiEntry, error) {
+	index := map[string]faiEntry{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("invalid index line %q", scanner.Text())
+		}
+		var values [4]int64
+		for i := range values {
+			v, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("invalid index line %q", scanner.Text())
+			}
+			values[i] = v
+		}
+		if values[2] == 0 || values[3] < values[2] {
+			return nil, fmt.Errorf("invalid line lengths for %s", fields[0])
+		}
+		index[normalizeChromosome(fields[0])] = faiEntry{length: uint64(values[0]), offset: values[1], lineBases: values[2], lineWidth: values[3]}
+	}
+	return index, scanner.Err()
+}
+
+// indexFASTA builds the faidx index of the FASTA in f. Like samtools, it
+// requires every line of a sequence but the last to be the same length.
+func indexFASTA(f *os.File) (map[string]faiEntry, error) {
+	index := map[string]faiEntry{}
+	r := bufio.NewReaderSize(f, 1<<20)
+
+	var (
+		name    string
+		entry   faiEntry
+		short   bool // a line shorter than lineBases ended the sequence
+		offset  int64
+		lineNum int
+	)
+	finish := func() {
+		if name != "" {
+			index[normalizeChromosome(name)] = entry
+		}
+	}
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) == 0 && err == io.EOF {
+			break
+		} else if err != nil && err != io.EOF {
+			return nil, err
+		}
+		lineNum++
+		start := offset
+		offset += int64(len(line))
+
+		if line[0] == '>' {
+			finish()
+			fields := strings.Fields(string(line[1:]))
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("line %d: sequence without a name", lineNum)
+			}
+			name, entry, short = fields[0], faiEntry{offset: offset}, false
+			continue
+		}
+
+		bases := int64(len(bytes.TrimRight(line, "\r\n")))
+		if name == "" || bases == 0 {
+			continue
+		}
+		if short || (entry.lineBases > 0 && bases > entry.lineBases) {
+			return nil, fmt.Errorf("line %d: %s has lines of different lengths", lineNum, name)
+		}
+		if entry.lineBases == 0 {
+			entry.lineBases, entry.lineWidth = bases, offset-start
+		}
+		short = bases < entry.lineBases
+		entry.length += uint64(bases)
+	}
+	finish()
+	return index, nil
+}
+
+// PanelReference is the reference alleles of a panel's variants, for
+// converting genotype data without a reference genome. Only the panel's
+// positions are known.
+type PanelReference map[Locus]string
+
+// NewPanelReference collects the reference alleles of single-base variants.
+func NewPanelReference(variants []VariantSpec) PanelReference {
+	ref := PanelReference{}
+	for _, v := range variants {
+		if len(v.Ref) == 1 {
+			ref[Locus{normalizeChromosome(v.Chromosome), v.Position}] = strings.ToUpper(v.Ref)
+		}
+	}
+	return ref
+}
+
+func (p PanelReference) Base(chrom string, pos uint64) (string, error) {
+	return p[Locus{normalizeChromosome(chrom), pos}], nil
+}
@@ -0,0 +1,243 @@
+package proofs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// GenomicRegion is an inclusive [Start, End] interval on a chromosome.
+type GenomicRegion struct {
+	Chromosome string
+	Start      uint64
+	End        uint64
+}
+
+// ParseRegion parses "chrom:start-end", e.g. "15:28356859-28567298".
+func ParseRegion(s string) (GenomicRegion, error) {
+	chrom, span, ok := strings.Cut(s, ":")
+	if !ok {
+		return GenomicRegion{}, fmt.Errorf("invalid region %q: expected chrom:start-end", s)
+	}
+	startStr, endStr, ok := strings.Cut(span, "-")
+	if !ok {
+		return GenomicRegion{}, fmt.Errorf("invalid region %q: expected chrom:start-end", s)
+	}
+
+	start, err := strconv.ParseUint(startStr, 10, 32)
+	if err != nil {
+		return GenomicRegion{}, fmt.Errorf("invalid region start %q: %w", startStr, err)
+	}
+	end, err := strconv.ParseUint(endStr, 10, 32)
+	if err != nil {
+		return GenomicRegion{}, fmt.Errorf("invalid region end %q: %w", endStr, err)
+	}
+	if start > end {
+		return GenomicRegion{}, fmt.Errorf("invalid region %q: start is after end", s)
+	}
+
+	return GenomicRegion{Chromosome: normalizeChromosome(chrom), Start: start, End: end}, nil
+}
+
+func (r GenomicRegion) String() string {
+	return fmt.Sprintf("%s:%d-%d", r.Chromosome, r.Start, r.End)
+}
+
+// Contains reports whether l lies within the region.
+func (r GenomicRegion) Contains(l Locus) bool {
+	return normalizeChromosome(l.Chromosome) == r.Chromosome && l.Position >= r.Start && l.Position <= r.End
+}
+
+// overlapsLine reports whether the record on a VCF data line spans any of
+// the region, from POS to INFO/END or the end of REF, reading only the
+// columns it needs. Header lines and lines too short to tell are kept for
+// the parser to handle.
+func (r GenomicRegion) overlapsLine(line []byte) bool {
+	if len(line) == 0 || line[0] == '#' {
+		return true
+	}
+	fields := bytes.SplitN(bytes.TrimRight(line, "\r\n"), []byte{'\t'}, 9)
+	if len(fields) < 8 {
+		return true
+	}
+	if normalizeChromosome(string(fields[0])) != r.Chromosome {
+		return false
+	}
+	pos, err := strconv.ParseUint(string(fields[1]), 10, 64)
+	if err != nil {
+		return true
+	}
+	if pos > r.End {
+		return false
+	}
+
+	end := pos + uint64(max(len(fields[3]), 1)) - 1
+	for _, kv := range bytes.Split(fields[7], []byte{';'}) {
+		if value, ok := bytes.CutPrefix(kv, []byte("END=")); ok {
+			if e, err := strconv.ParseUint(string(value), 10, 64); err == nil && e >= pos {
+				end = e
+			}
+		}
+	}
+	return end >= r.Start
+}
+
+// RegionCircuit proves that a variant in the variant tree with a public root
+// lies within a public region, without revealing the variant or its exact
+// position.
+type RegionCircuit struct {
+	Root       frontend.Variable `gnark:",public"`
+	Chromosome frontend.Variable `gnark:",public"`
+	Start      frontend.Variable `gnark:",public"`
+	End        frontend.Variable `gnark:",public"`
+
+	Position  frontend.Variable
+	VariantID frontend.Variable
+	Index     frontend.Variable
+	Siblings  []frontend.Variable
+
+	Hash HashAlgorithm `gnark:"-"`
+}
+
+// NewRegionCircuit allocates a circuit for a tree of depth built with hash.
+func NewRegionCircuit(depth int, hash HashAlgorithm) *RegionCircuit {
+	return &RegionCircuit{Siblings: make([]frontend.Variable, depth), Hash: hash}
+}
+
+func (c *RegionCircuit) Define(api frontend.API) error {
+	// Positions occupy the low 32 bits of a locus key.
+	api.ToBinary(c.Position, 32)
+	api.AssertIsLessOrEqual(c.Start, c.Position)
+	api.AssertIsLessOrEqual(c.Position, c.End)
+
+	h, err := newFieldHasher(api, c.Hash)
+	if err != nil {
+		return err
+	}
+
+	key := api.Add(api.Mul(c.Chromosome, 1<<32), c.Position)
+	h.Write(key, c.VariantID)
+	leaf := h.Sum()
+
+	api.AssertIsEqual(merkleRoot(api, h, leaf, c.Index, c.Siblings), c.Root)
+	return nil
+}
+
+// findInRange returns the index of the first leaf whose locus key lies in
+// [low, high].
+func (t *VariantTree) findInRange(low, high uint64) (int, bool) {
+	index, _ := slices.BinarySearchFunc(t.leaves, low, func(l variantLeaf, key uint64) int {
+		switch {
+		case l.LocusKey < key:
+			return -1
+		case l.LocusKey > key:
+			return 1
+		default:
+			return 0
+		}
+	})
+	if index < len(t.leaves) && t.leaves[index].LocusKey <= high {
+		return index, true
+	}
+	return 0, false
+}
+
+func (p *RegionProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	region, err := ParseRegion(p.Region)
+	if err != nil {
+		return err
+	}
+	chromosome, ok := chromosomeIndex(region.Chromosome)
+	if !ok {
+		return fmt.Errorf("chromosome %s is not a primary assembly chromosome", region.Chromosome)
+	}
+
+	depth := p.TreeDepth
+	if depth == 0 {
+		depth = DefaultTreeDepth
+	}
+
+	fmt.Println("Building variant tree from VCF...")
+	tree, err := buildVariantTree(vcfPath, p.Sample, depth, p.Imputation, p.Hash, p.Limits)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	index, found := tree.findInRange(chromosome<<32|region.Start, chromosome<<32|region.End)
+	if !found {
+		return withKind(ErrNotFound, fmt.Errorf("no variant is carried in region %s", region))
+	}
+	leaf := tree.leaves[index]
+
+	assignment := NewRegionCircuit(tree.Depth, tree.Hash)
+	assignment.Root = tree.Root()
+	assignment.Chromosome = chromosome
+	assignment.Start = region.Start
+	assignment.End = region.End
+	assignment.Position = leaf.LocusKey & (1<<32 - 1)
+	assignment.VariantID = leaf.VariantID
+	assignment.Index = index
+	for i, sibling := range tree.path(index) {
+		assignment.Siblings[i] = sibling
+	}
+
+	if err := p.Settings.prove(ctx, "region", NewRegionCircuit(tree.Depth, tree.Hash), assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("region", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven that at least one variant is carried in region %s\n", region)
+	fmt.Println("without revealing which variant or where in the region it lies.")
+	fmt.Printf("Variant tree root: %s\n", tree.Root())
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *RegionProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 4 {
+		return false, withKind(ErrVerification, fmt.Errorf("region proof has %d public inputs, expected 4", len(public)))
+	}
+
+	chrom := locusFromKey(public[1].Uint64() << 32).Chromosome
+	proven := GenomicRegion{Chromosome: chrom, Start: public[2].Uint64(), End: public[3].Uint64()}
+	fmt.Printf("At least one variant is carried in region %s\n", proven)
+
+	if p.Region != "" {
+		region, err := ParseRegion(p.Region)
+		if err != nil {
+			return false, withKind(ErrInvalidInput, err)
+		}
+		if region != proven {
+			return false, withKind(ErrVerification, fmt.Errorf("proof is for region %s, not %s", proven, region))
+		}
+	}
+	if p.ExpectedCommitment != "" {
+		if err := checkCommitment(proofPath, 0, p.ExpectedCommitment); err != nil {
+			return false, err
+		}
+	}
+	return verified, nil
+}
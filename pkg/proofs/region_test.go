@@ -0,0 +1,156 @@
+package proofs
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestParseRegion(t *testing.T) {
+	r, err := ParseRegion("chr15:28356859-28567298")
+	if err != nil {
+		t.Fatalf("ParseRegion: %v", err)
+	}
+	if r != (GenomicRegion{Chromosome: "15", Start: 28356859, End: 28567298}) {
+		t.Errorf("ParseRegion = %+v", r)
+	}
+
+	for _, bad := range []string{"15", "15:100", "15:200-100", "15:a-b"} {
+		if _, err := ParseRegion(bad); err == nil {
+			t.Errorf("ParseRegion(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRegionFilter(t *testing.T) {
+	region := GenomicRegion{Chromosome: "22", Start: 100, End: 200}
+	for line, want := range map[string]bool{
+		"##fileformat=VCFv4.2\n":                            true,
+		"chr22\t150\t.\tA\tG\t60\tPASS\t.\n":                true,
+		"22\t98\t.\tACGT\tA\t60\tPASS\t.\n":                 true,
+		"22\t50\t.\tA\t<DEL>\t60\tPASS\tSVTYPE=DEL;END=120": true,
+		"22\t50\t.\tA\t<NON_REF>\t.\t.\tEND=99\n":           false,
+		"22\t201\t.\tA\tG\t60\tPASS\t.\n":                   false,
+		"chr1\t150\t.\tA\tG\t60\tPASS\t.\n":                 false,
+	} {
+		if got := region.overlapsLine([]byte(line)); got != want {
+			t.Errorf("overlapsLine(%q) = %v, want %v", line, got, want)
+		}
+	}
+
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+1	100	.	A	G	60	PASS	.	GT	0/1
+1	200	.	C	T	60	PASS	.	GT	0/1
+1	300	.	G	A	60	PASS	.	GT	1/1
+22	150	.	T	C	60	PASS	.	GT	0/1
+`)
+	// Records outside the region are skipped before the record limit
+	// counts them.
+	s := Settings{Region: &region, Limits: ReadLimits{MaxRecords: 1}}
+	got, err := extractGenotypes(vcf, s, locusSpecs(Locus{"chr22", 150}))
+	if err != nil || len(got) != 1 {
+		t.Errorf("extractGenotypes in region = %v, %v; want one call", got, err)
+	}
+	if _, err := extractGenotypes(vcf, s, locusSpecs(Locus{"1", 100})); err == nil || !strings.Contains(err.Error(), "outside region") {
+		t.Errorf("extractGenotypes outside region: err = %v", err)
+	}
+	if _, err := extractCoverage(vcf, s, Locus{"1", 100}); err == nil {
+		t.Errorf("extractCoverage outside region should fail")
+	}
+}
+
+func TestGeneRegion(t *testing.T) {
+	panel := []TraitVariant{
+		{Gene: "APOE", Chromosome: 19, Region: TraitRegion{Start: 45411900, End: 45412000}},
+		{Gene: "APOE", Chromosome: 19, Region: TraitRegion{Start: 45412000, End: 45412200}},
+		{Gene: "BRCA1", Chromosome: 17, Region: TraitRegion{Start: 41276000, End: 41277000}},
+	}
+
+	r, err := GeneRegion(panel, "apoe")
+	if err != nil {
+		t.Fatalf("GeneRegion: %v", err)
+	}
+	if r != (GenomicRegion{Chromosome: "19", Start: 45411900, End: 45412200}) {
+		t.Errorf("GeneRegion = %+v", r)
+	}
+
+	if _, err := GeneRegion(panel, "HERC2"); err == nil {
+		t.Errorf("GeneRegion should fail for a gene not in the panel")
+	}
+}
+
+func TestRegionCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	vcf := writeTempVCF(t, membershipTestVCF)
+	tree, err := BuildVariantTree(vcf, 4, ImputationPolicy{}, HashMiMC, ReadLimits{})
+	if err != nil {
+		t.Fatalf("BuildVariantTree: %v", err)
+	}
+
+	index, found, err := tree.find(mustParseVariant(t, "17:41276045:CT:C"))
+	if err != nil || !found {
+		t.Fatalf("variant not found: %v", err)
+	}
+	leaf := tree.leaves[index]
+
+	tests := []struct {
+		chromosome int
+		start, end int
+		want       bool
+	}{
+		{17, 41276000, 41277000, true},
+		{17, 41276045, 41276045, true},
+		{17, 41276046, 41277000, false},
+		{17, 41275000, 41276044, false},
+		{16, 41276000, 41277000, false},
+	}
+
+	for _, tt := range tests {
+		assignment := NewRegionCircuit(tree.Depth, tree.Hash)
+		assignment.Root = tree.Root()
+		assignment.Chromosome = tt.chromosome
+		assignment.Start = tt.start
+		assignment.End = tt.end
+		assignment.Position = leaf.LocusKey & (1<<32 - 1)
+		assignment.VariantID = leaf.VariantID
+		assignment.Index = index
+		for i, sibling := range tree.path(index) {
+			assignment.Siblings[i] = sibling
+		}
+
+		err := test.IsSolved(NewRegionCircuit(tree.Depth, tree.Hash), assignment, field)
+		if tt.want && err != nil {
+			t.Errorf("%d:%d-%d should satisfy the circuit: %v", tt.chromosome, tt.start, tt.end, err)
+		}
+		if !tt.want && err == nil {
+			t.Errorf("%d:%d-%d should not satisfy the circuit", tt.chromosome, tt.start, tt.end)
+		}
+	}
+
+	if _, found := tree.findInRange(17<<32|41276046, 17<<32|41277000); found {
+		t.Errorf("findInRange found a variant in an empty region")
+	}
+}
+
+func TestRegionProofBindsRegion(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "region_proof.bin")
+	proof := &RegionProof{Region: "17:41276000-41277000", TreeDepth: 4}
+	if err := proof.Generate(t.Context(), writeTempVCF(t, membershipTestVCF), "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); !ok || err != nil {
+		t.Errorf("Verify for the proof's region = %v, %v", ok, err)
+	}
+	for _, region := range []string{"17:41276000-41276100", "16:41276000-41277000"} {
+		other := &RegionProof{Region: region}
+		if ok, err := other.Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+			t.Errorf("Verify for region %s = %v, %v; want ErrVerification", region, ok, err)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+package proofs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProofConfig carries the options that parameterize individual proof
+// types; each reads the fields it needs and ignores the rest. Verification
+// only needs the expected commitment, position, gene, panel, region,
+// structural variant type and quality thresholds.
+type ProofConfig struct {
+	Settings           Settings
+	Commitment         *DatasetCommitment
+	ExpectedCommitment string
+	MaxVariants        int
+	Target             int
+	TreeDepth          int
+	WholeLocus         bool
+	Region             string
+	SVType             string
+	Panel              []VariantSpec
+	K                  int
+	Locus              string
+	RepeatThreshold    int
+	Gene               string
+	Variant            string
+	Position           string
+	Quality            QualityThresholds
+	SecondVariant      string
+	NormalVCF          string
+	TumorSample        string
+	NormalSample       string
+	Epsilon            float64
+	EyeColor           string
+}
+
+// PublicInput describes one public input of a proof circuit.
+type PublicInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ProofType describes a proof type and creates proofs of it.
+type ProofType struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Fields are the VCF columns and FORMAT or INFO fields the proof reads.
+	Fields []string `json:"vcf_fields,omitempty"`
+	// PublicInputs are the proof circuit's public inputs in order. The
+	// nullifier, nonce and dataset digest of the envelope follow them.
+	PublicInputs []PublicInput `json:"public_inputs"`
+	// Standalone is set for types proved from a VCF of SNV calls with no
+	// other options; these are the types "all" stands for.
+	Standalone bool `json:"standalone,omitempty"`
+	// New returns a proof of the type configured by cfg.
+	New func(cfg ProofConfig) Proof `json:"-"`
+}
+
+var (
+	locusKeyInput   = PublicInput{"locus_key", "Chromosome index and position, as chromosome<<32 | position"}
+	variantIDInput  = PublicInput{"variant_id", "Hash identifying the variant's chromosome, position and alleles"}
+	rootInput       = PublicInput{"root", "Merkle root over the VCF's carried variants"}
+	minQualityInput = PublicInput{"min_quality", "Minimum QUAL the call meets"}
+	minDepthInput   = PublicInput{"min_depth", "Minimum FORMAT/DP the call meets"}
+	panelIDInput    = PublicInput{"panel_id", "Hash identifying the SNP panel"}
+)
+
+var proofTypes = []ProofType{
+	{
+		Name:         "chromosome",
+		Standalone:   true,
+		Description:  "Chromosome-based genomic proof",
+		Fields:       []string{"CHROM"},
+		PublicInputs: []PublicInput{{"target_chromosome", "Chromosome shown to be present"}, {"commitment", "Commitment to the VCF's chromosome list"}},
+		New: func(cfg ProofConfig) Proof {
+			return &ChromosomeProof{
+				Settings:           cfg.Settings,
+				MaxVariants:        cfg.MaxVariants,
+				Target:             cfg.Target,
+				Commitment:         cfg.Commitment,
+				ExpectedCommitment: cfg.ExpectedCommitment,
+			}
+		},
+	},
+	{
+		Name:         "eyecolor",
+		Standalone:   true,
+		Description:  "Eye color from HERC2 and SLC45A2 genotypes",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: eyeColorDefinition.publicInputs(),
+		New:          func(cfg ProofConfig) Proof { return &EyeColorProof{Settings: cfg.Settings} },
+	},
+	{
+		Name:         "irisplex",
+		Standalone:   true,
+		Description:  "Multi-locus IrisPlex eye color prediction",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: []PublicInput{{"color", "Predicted eye color (1 brown, 2 intermediate, 3 blue)"}},
+		New:          func(cfg ProofConfig) Proof { return &IrisPlexProof{Settings: cfg.Settings, Color: cfg.EyeColor} },
+	},
+	{
+		Name:         "bloodtype",
+		Standalone:   true,
+		Description:  "ABO group and RhD status",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: bloodTypeDefinition.publicInputs(),
+		New:          func(cfg ProofConfig) Proof { return &BloodTypeProof{Settings: cfg.Settings} },
+	},
+	{
+		Name:         "brca1",
+		Standalone:   true,
+		Description:  "BRCA1 gene mutation proof",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: []PublicInput{{"carrier", "1 when a known pathogenic BRCA1 variant is carried"}},
+		New:          func(cfg ProofConfig) Proof { return &BRCA1Proof{Settings: cfg.Settings} },
+	},
+	{
+		Name:         "herc2",
+		Description:  "Eye color from the HERC2 genotype alone (not supported yet; use eyecolor)",
+		Fields:       []string{"CHROM", "POS", "GT"},
+		PublicInputs: []PublicInput{{"claimed_color", "Claimed eye color"}},
+		New:          func(cfg ProofConfig) Proof { return &HERC2Proof{Settings: cfg.Settings} },
+	},
+	{
+		Name:         "repeat",
+		Description:  "Repeat expansion below pathogenic threshold (e.g. HTT CAG)",
+		Fields:       []string{"CHROM", "POS", "ALT", "GT", "FORMAT/REPCN", "INFO/REF"},
+		PublicInputs: []PublicInput{{"locus_id", "Repeat locus"}, {"threshold", "Pathogenic repeat count both alleles stay below"}},
+		New: func(cfg ProofConfig) Proof {
+			return &RepeatExpansionProof{Settings: cfg.Settings, Locus: cfg.Locus, Threshold: cfg.RepeatThreshold}
+		},
+	},
+	{
+		Name:         "diplotype",
+		Description:  "Pharmacogene star-allele metabolizer phenotype",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT (phased)"},
+		PublicInputs: []PublicInput{{"gene_id", "Pharmacogene name"}, {"phenotype", "Metabolizer phenotype"}},
+		New:          func(cfg ProofConfig) Proof { return &StarAlleleProof{Settings: cfg.Settings, Gene: cfg.Gene} },
+	},
+	{
+		Name:         "compoundhet",
+		Description:  "Two pathogenic variants on different haplotypes",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT (phased)", "FORMAT/PS"},
+		PublicInputs: []PublicInput{{"gene_id", "Gene the variants lie in"}, {"compound_het", "1 when pathogenic variants are carried in trans"}},
+		New:          func(cfg ProofConfig) Proof { return &CompoundHetProof{Settings: cfg.Settings, Gene: cfg.Gene} },
+	},
+	{
+		Name:         "somatic",
+		Description:  "Variant present in tumor but absent from matched normal",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "QUAL", "GT", "FORMAT/DP"},
+		PublicInputs: []PublicInput{variantIDInput, {"classification", "Somatic classification"}, minQualityInput, minDepthInput},
+		New: func(cfg ProofConfig) Proof {
+			return &SomaticProof{
+				Settings:     cfg.Settings,
+				Variant:      cfg.Variant,
+				NormalVCF:    cfg.NormalVCF,
+				TumorSample:  cfg.TumorSample,
+				NormalSample: cfg.NormalSample,
+				Quality:      cfg.Quality,
+			}
+		},
+	},
+	{
+		Name:         "membership",
+		Description:  "Variant is in a Merkle-committed VCF",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: []PublicInput{rootInput, locusKeyInput, variantIDInput},
+		New: func(cfg ProofConfig) Proof {
+			return &VariantMembershipProof{
+				Settings:           cfg.Settings,
+				Variant:            cfg.Variant,
+				TreeDepth:          cfg.TreeDepth,
+				ExpectedCommitment: cfg.ExpectedCommitment,
+			}
+		},
+	},
+	{
+		Name:         "absence",
+		Description:  "Variant is not in a Merkle-committed VCF",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: []PublicInput{rootInput, locusKeyInput, variantIDInput, {"whole_locus", "1 when no variant at all is carried at the locus"}},
+		New: func(cfg ProofConfig) Proof {
+			return &VariantAbsenceProof{
+				Settings:           cfg.Settings,
+				Variant:            cfg.Variant,
+				WholeLocus:         cfg.WholeLocus,
+				TreeDepth:          cfg.TreeDepth,
+				ExpectedCommitment: cfg.ExpectedCommitment,
+			}
+		},
+	},
+	{
+		Name:         "region",
+		Description:  "A variant lies within a gene region",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: []PublicInput{rootInput, {"chromosome", "Region chromosome"}, {"start", "Region start"}, {"end", "Region end"}},
+		New: func(cfg ProofConfig) Proof {
+			return &RegionProof{
+				Settings:           cfg.Settings,
+				Region:             cfg.Region,
+				TreeDepth:          cfg.TreeDepth,
+				ExpectedCommitment: cfg.ExpectedCommitment,
+			}
+		},
+	},
+	{
+		Name:         "sv",
+		Description:  "A deletion or duplication overlaps a gene region",
+		Fields:       []string{"CHROM", "POS", "ALT", "GT", "INFO/SVTYPE", "INFO/END"},
+		PublicInputs: []PublicInput{{"chromosome", "Region chromosome"}, {"region_start", "Region start"}, {"region_end", "Region end"}, {"sv_type", "Structural variant type (DEL, DUP)"}},
+		New: func(cfg ProofConfig) Proof {
+			return &StructuralVariantProof{Settings: cfg.Settings, Region: cfg.Region, SVType: cfg.SVType}
+		},
+	},
+	{
+		Name:        "haplotype",
+		Description: "Two phased variants are in cis or trans",
+		Fields:      []string{"CHROM", "POS", "REF", "ALT", "GT (phased)", "FORMAT/PS"},
+		PublicInputs: []PublicInput{
+			rootInput,
+			{"locus_key_a", "First variant's locus key"}, {"variant_id_a", "First variant's ID"},
+			{"locus_key_b", "Second variant's locus key"}, {"variant_id_b", "Second variant's ID"},
+			{"cis", "1 when the variants are on the same haplotype"},
+		},
+		New: func(cfg ProofConfig) Proof {
+			return &HaplotypeProof{
+				Settings:           cfg.Settings,
+				Variants:           [2]string{cfg.Variant, cfg.SecondVariant},
+				TreeDepth:          cfg.TreeDepth,
+				ExpectedCommitment: cfg.ExpectedCommitment,
+			}
+		},
+	},
+	{
+		Name:         "zygosity",
+		Description:  "Heterozygous or homozygous at a locus",
+		Fields:       []string{"CHROM", "POS", "QUAL", "GT", "FORMAT/DP"},
+		PublicInputs: []PublicInput{locusKeyInput, {"zygosity", "1 heterozygous, 0 homozygous"}, minQualityInput, minDepthInput},
+		New: func(cfg ProofConfig) Proof {
+			return &ZygosityProof{Settings: cfg.Settings, Locus: cfg.Position, Quality: cfg.Quality}
+		},
+	},
+	{
+		Name:         "wildtype",
+		Description:  "Homozygous reference at a covered locus",
+		Fields:       []string{"CHROM", "POS", "QUAL", "GT", "FORMAT/DP", "FORMAT/GQ", "INFO/END"},
+		PublicInputs: []PublicInput{locusKeyInput, minQualityInput, minDepthInput},
+		New: func(cfg ProofConfig) Proof {
+			return &WildTypeProof{Settings: cfg.Settings, Locus: cfg.Position, Quality: cfg.Quality}
+		},
+	},
+	{
+		Name:         "dosage",
+		Description:  "Total ALT allele dosage across a SNP panel",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: []PublicInput{panelIDInput, {"total", "Total ALT allele count across the panel"}},
+		New:          func(cfg ProofConfig) Proof { return &DosageProof{Settings: cfg.Settings, Panel: cfg.Panel} },
+	},
+	{
+		Name:         "threshold",
+		Description:  "At least k variants of a panel are present",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: []PublicInput{panelIDInput, {"k", "Minimum number of panel variants"}, {"at_least", "1 when at least k panel variants are carried"}},
+		New: func(cfg ProofConfig) Proof {
+			return &ThresholdProof{Settings: cfg.Settings, Panel: cfg.Panel, K: cfg.K}
+		},
+	},
+	{
+		Name:         "panel",
+		Description:  "Carried variant count over a committed reference panel",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: []PublicInput{{"panel_hash", "Hash of the reference panel"}, rootInput, {"count", "Number of panel variants carried"}},
+		New: func(cfg ProofConfig) Proof {
+			return &PanelProof{
+				Settings:           cfg.Settings,
+				Panel:              cfg.Panel,
+				TreeDepth:          cfg.TreeDepth,
+				ExpectedCommitment: cfg.ExpectedCommitment,
+			}
+		},
+	},
+	{
+		Name:         "carrier",
+		Description:  "Committed carrier status for a cohort count",
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: []PublicInput{variantIDInput, {"commitment", "Commitment to the carrier status"}},
+		New:          func(cfg ProofConfig) Proof { return &CarrierProof{Settings: cfg.Settings, Variant: cfg.Variant} },
+	},
+	{
+		Name:        "cohort",
+		Description: "Differentially private carrier count (built with aggregate)",
+		PublicInputs: []PublicInput{
+			variantIDInput,
+			{"epsilon_milli", "Privacy budget in thousandths"},
+			{"seed_commitment", "Commitment to the noise seed"},
+			{"noisy_count", "Released carrier count with noise"},
+			{"commitments", "Each participant's carrier commitment, one input per participant"},
+		},
+		New: func(cfg ProofConfig) Proof {
+			return &CohortProof{Settings: cfg.Settings, Variant: cfg.Variant, Epsilon: cfg.Epsilon, SeedCommitment: cfg.ExpectedCommitment}
+		},
+	},
+	{
+		Name:         "recursive",
+		Description:  "Proof that a set of proofs verify (built with aggregate)",
+		PublicInputs: []PublicInput{{"witnesses", "Each inner proof's public inputs, in order"}},
+		New:          func(cfg ProofConfig) Proof { return &RecursiveProof{Settings: cfg.Settings} },
+	},
+}
+
+// compositeTraitInputs describes the public inputs of a composite trait
+// circuit: the phenotype followed by the decision table.
+func compositeTraitInputs(name, description string, trait CompositeTrait) []PublicInput {
+	return []PublicInput{
+		{name, description},
+		{"table", fmt.Sprintf("The %s decision table, one input per entry (%d)", trait.Name, len(trait.Table))},
+	}
+}
+
+// RegisterProofType makes t available to NewProof and LookupProofType. It
+// replaces any proof type already registered under t's name and is not safe
+// for concurrent use, so call it during initialization.
+func RegisterProofType(t ProofType) {
+	delete(traitDefinitions, t.Name)
+	for i := range proofTypes {
+		if proofTypes[i].Name == t.Name {
+			proofTypes[i] = t
+			return
+		}
+	}
+	proofTypes = append(proofTypes, t)
+}
+
+// Register registers a proof type named name whose proofs factory returns,
+// so a program importing the package that registers it, such as the CLI,
+// can generate, verify and list its proofs as it does the built-in types.
+// A proof embedding Settings gets the settings of the ProofConfig it is
+// created for; the rest of the configuration is the proof's own. Use
+// RegisterProofType to describe the type's VCF fields and public inputs
+// too. Like RegisterProofType, it replaces a type of the same name and
+// belongs in an init function.
+func Register(name string, factory func() Proof) {
+	if name == "" || factory == nil {
+		panic("proofs: Register needs a name and a factory")
+	}
+	RegisterProofType(ProofType{
+		Name: name,
+		New: func(cfg ProofConfig) Proof {
+			p := factory()
+			*proofSettings(p) = cfg.Settings
+			return p
+		},
+	})
+}
+
+// ProofTypes returns the registered proof types in registration order.
+func ProofTypes() []ProofType {
+	return append([]ProofType(nil), proofTypes...)
+}
+
+// StandaloneProofTypes returns the names of the Standalone proof types in
+// registration order.
+func StandaloneProofTypes() []string {
+	var names []string
+	for _, t := range proofTypes {
+		if t.Standalone {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+// LookupProofType returns the proof type registered as name, ignoring case.
+func LookupProofType(name string) (ProofType, bool) {
+	for _, t := range proofTypes {
+		if strings.EqualFold(t.Name, name) {
+			return t, true
+		}
+	}
+	return ProofType{}, false
+}
+
+// NewProof returns a proof of the type registered as name, configured by
+// cfg.
+func NewProof(name string, cfg ProofConfig) (Proof, error) {
+	t, ok := LookupProofType(name)
+	if !ok {
+		names := make([]string, len(proofTypes))
+		for i, t := range proofTypes {
+			names[i] = t.Name
+		}
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("unknown proof type: %s. Supported types: %s", name, strings.Join(names, ", ")))
+	}
+	return t.New(cfg), nil
+}
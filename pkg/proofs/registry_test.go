@@ -0,0 +1,138 @@
+package proofs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// publicInputCount counts the public variables of circuit, following nested
+// structs and slices as gnark does.
+func publicInputCount(v reflect.Value, public bool) int {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			if public {
+				return 1
+			}
+			return 0
+		}
+		return publicInputCount(v.Elem(), public)
+	case reflect.Struct:
+		n := 0
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if !field.IsExported() || field.Tag.Get("gnark") == "-" {
+				continue
+			}
+			n += publicInputCount(v.Field(i), public || strings.Contains(field.Tag.Get("gnark"), "public"))
+		}
+		return n
+	case reflect.Slice, reflect.Array:
+		n := 0
+		for i := range v.Len() {
+			n += publicInputCount(v.Index(i), public)
+		}
+		return n
+	default:
+		return 0
+	}
+}
+
+func TestProofRegistry(t *testing.T) {
+	cfg := ProofConfig{Gene: "CYP2C19", Panel: []VariantSpec{{Chromosome: "19", Position: 45411941, Ref: "T", Alt: "C"}}}
+	for _, pt := range ProofTypes() {
+		proof, err := NewProof(strings.ToUpper(pt.Name), cfg)
+		if err != nil {
+			t.Fatalf("NewProof(%s): %v", pt.Name, err)
+		}
+		_, circuit, err := proofCircuit(proof)
+		if err != nil {
+			continue // its circuit depends on the inputs it aggregates
+		}
+
+		// Each schema entry is one input, except a composite trait's table.
+		want := len(pt.PublicInputs)
+		if c, ok := circuit.(*CompositeTraitCircuit); ok {
+			want += len(c.Table) - 1
+		}
+		if got := publicInputCount(reflect.ValueOf(circuit), false); got != want {
+			t.Errorf("%s: circuit has %d public inputs, schema describes %d", pt.Name, got, want)
+		}
+	}
+
+	if _, err := NewProof("nope", cfg); err == nil || !strings.Contains(err.Error(), "zygosity") {
+		t.Errorf("NewProof(nope) = %v, want an error listing the supported types", err)
+	}
+}
+
+// hospitalPanelProof stands in for a proof type registered by another
+// package.
+type hospitalPanelProof struct {
+	Settings
+}
+
+func (p *hospitalPanelProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	return nil
+}
+
+func (p *hospitalPanelProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	return true, nil
+}
+
+// bareProof is a registered proof type without Settings.
+type bareProof struct{}
+
+func (p bareProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	return nil
+}
+
+func (p bareProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	return true, nil
+}
+
+func TestRegister(t *testing.T) {
+	registered := proofTypes
+	t.Cleanup(func() { proofTypes = registered })
+	proofTypes = append([]ProofType(nil), registered...)
+
+	Register("hospital", func() Proof { return &hospitalPanelProof{} })
+	Register("bare", func() Proof { return bareProof{} })
+
+	p, err := NewProof("HOSPITAL", ProofConfig{Settings: Settings{Nonce: "challenge"}})
+	if err != nil {
+		t.Fatalf("NewProof: %v", err)
+	}
+	if got := p.(*hospitalPanelProof).Nonce; got != "challenge" {
+		t.Errorf("registered proof has nonce %q, want the config's", got)
+	}
+	if _, err := NewProof("bare", ProofConfig{Settings: Settings{Nonce: "challenge"}}); err != nil {
+		t.Errorf("NewProof of a proof without settings: %v", err)
+	}
+	if _, ok := LookupProofType("hospital"); !ok {
+		t.Errorf("registered type is not listed")
+	}
+	if _, _, err := proofCircuit(p); !errors.Is(err, ErrNoCircuit) {
+		t.Errorf("proofCircuit of a registered type = %v, want ErrNoCircuit", err)
+	}
+}
+
+func TestHERC2Unsupported(t *testing.T) {
+	if typ, ok := LookupProofType("herc2"); !ok || typ.Standalone {
+		t.Errorf("herc2 = %+v, %v; want a registered type outside all", typ, ok)
+	}
+	p, err := NewProof("herc2", ProofConfig{})
+	if err != nil {
+		t.Fatalf("NewProof: %v", err)
+	}
+	out := filepath.Join(t.TempDir(), "herc2_proof.bin")
+	if err := p.Generate(t.Context(), writeTempVCF(t, "##fileformat=VCFv4.2\n"), "", out); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Generate = %v, want ErrUnsupported", err)
+	}
+	if ok, err := p.Verify(t.Context(), "/nonexistent.vk", "/nonexistent.bin"); ok || !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Verify = %v, %v; want ErrUnsupported", ok, err)
+	}
+}
@@ -0,0 +1,255 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/brentp/vcfgo"
+	"github.com/consensys/gnark/frontend"
+)
+
+// repeatCountBits bounds private repeat counts so the comparison below cannot
+// be satisfied by a value that wraps around the field.
+const repeatCountBits = 16
+
+// RepeatLocus describes a short tandem repeat and the repeat count at which
+// an allele is considered pathogenic.
+type RepeatLocus struct {
+	ID                  string
+	Motif               string
+	PathogenicThreshold int
+}
+
+// RepeatLoci lists the repeat-expansion loci with a built-in threshold,
+// keyed by the ExpansionHunter VARID.
+var RepeatLoci = map[string]RepeatLocus{
+	"HTT":  {ID: "HTT", Motif: "CAG", PathogenicThreshold: 36},
+	"FMR1": {ID: "FMR1", Motif: "CGG", PathogenicThreshold: 55},
+	"DMPK": {ID: "DMPK", Motif: "CTG", PathogenicThreshold: 50},
+}
+
+// RepeatExpansionCircuit proves that both alleles at a repeat locus are
+// shorter than a public pathogenic threshold without revealing the counts.
+type RepeatExpansionCircuit struct {
+	LocusID   frontend.Variable `gnark:",public"`
+	Threshold frontend.Variable `gnark:",public"`
+
+	Allele1 frontend.Variable
+	Allele2 frontend.Variable
+}
+
+func (c *RepeatExpansionCircuit) Define(api frontend.API) error {
+	for _, allele := range []frontend.Variable{c.Allele1, c.Allele2} {
+		api.ToBinary(allele, repeatCountBits)
+		api.AssertIsLessOrEqual(api.Add(allele, 1), c.Threshold)
+	}
+	bindPublic(api, c.LocusID)
+	return nil
+}
+
+// bindPublic constrains public inputs that label a claim but take part in
+// no other constraint. Groth16 does not bind such inputs, so without this
+// a proof could be relabeled after the fact.
+func bindPublic(api frontend.API, inputs ...frontend.Variable) {
+	for _, v := range inputs {
+		api.Mul(v, v)
+	}
+}
+
+// locusID encodes a locus name as a field element so the verifier can see
+// which locus the threshold claim refers to.
+func locusID(locus string) *big.Int {
+	return new(big.Int).SetBytes([]byte(locus))
+}
+
+// extractRepeatCounts finds the ExpansionHunter record for locus and returns
+// the repeat count of each allele of the sample named by s.Sample, or the
+// first sample.
+func extractRepeatCounts(vcfPath string, s Settings, locus string) ([2]int, error) {
+	rdr, err := openVCF(vcfPath, s.Limits, s.Region)
+	if err != nil {
+		return [2]int{}, err
+	}
+	defer rdr.Close()
+	sampleIndex, err := sampleColumn(rdr.Header, s.Sample)
+	if err != nil {
+		return [2]int{}, err
+	}
+
+	for {
+		variant, err := rdr.next()
+		if err != nil {
+			return [2]int{}, err
+		}
+		if variant == nil {
+			break
+		}
+
+		varID, _ := infoString(variant, "VARID")
+		repID, _ := infoString(variant, "REPID")
+		if !strings.EqualFold(varID, locus) && !strings.EqualFold(repID, locus) {
+			continue
+		}
+
+		fmt.Printf("Found %s repeat record at %s:%d\n", locus, variant.Chromosome, variant.Pos)
+		return repeatCountsFromVariant(variant, sampleIndex)
+	}
+
+	if s.Region != nil {
+		return [2]int{}, withKind(ErrNotFound, fmt.Errorf("repeat locus %s not found in region %s of the VCF", locus, s.Region))
+	}
+	return [2]int{}, withKind(ErrNotFound, fmt.Errorf("repeat locus %s not found in VCF", locus))
+}
+
+// repeatCountsFromVariant reads REPCN from the indexed sample, falling back
+// to resolving GT against the <STRn> ALT alleles and the INFO REF count.
+func repeatCountsFromVariant(variant *vcfgo.Variant, sampleIndex int) ([2]int, error) {
+	var counts [2]int
+	if len(variant.Samples) <= sampleIndex || variant.Samples[sampleIndex] == nil {
+		return counts, fmt.Errorf("repeat record has no sample genotype")
+	}
+	sample := variant.Samples[sampleIndex]
+
+	var values []int
+	if repcn, ok := sample.Fields["REPCN"]; ok && repcn != "." {
+		for _, part := range strings.Split(repcn, "/") {
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return counts, fmt.Errorf("parsing REPCN %q: %w", repcn, err)
+			}
+			values = append(values, n)
+		}
+	} else {
+		for _, allele := range sample.GT {
+			n, err := repeatAlleleCount(variant, allele)
+			if err != nil {
+				return counts, err
+			}
+			values = append(values, n)
+		}
+	}
+
+	switch len(values) {
+	case 1:
+		// Haploid call (e.g. chrX in males): both slots carry the one allele.
+		return [2]int{values[0], values[0]}, nil
+	case 2:
+		return [2]int{values[0], values[1]}, nil
+	default:
+		return counts, fmt.Errorf("expected 1 or 2 repeat alleles, got %d", len(values))
+	}
+}
+
+func repeatAlleleCount(variant *vcfgo.Variant, allele int) (int, error) {
+	if allele < 0 {
+		return 0, fmt.Errorf("repeat genotype is missing")
+	}
+	if allele == 0 {
+		ref, ok := infoString(variant, "REF")
+		if !ok {
+			return 0, fmt.Errorf("reference repeat count (INFO/REF) is missing")
+		}
+		return strconv.Atoi(ref)
+	}
+	if allele > len(variant.Alternate) {
+		return 0, fmt.Errorf("genotype allele %d has no matching ALT", allele)
+	}
+
+	alt := variant.Alternate[allele-1]
+	if !strings.HasPrefix(alt, "<STR") || !strings.HasSuffix(alt, ">") {
+		return 0, fmt.Errorf("ALT allele %s is not a repeat allele", alt)
+	}
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(alt, "<STR"), ">"))
+}
+
+func (p *RepeatExpansionProof) threshold() (int, error) {
+	if p.Threshold > 0 {
+		return p.Threshold, nil
+	}
+	locus, ok := RepeatLoci[strings.ToUpper(p.Locus)]
+	if !ok {
+		return 0, fmt.Errorf("no built-in threshold for repeat locus %s; supply one explicitly", p.Locus)
+	}
+	return locus.PathogenicThreshold, nil
+}
+
+func (p *RepeatExpansionProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+	if p.Locus == "" {
+		return fmt.Errorf("a repeat locus is required")
+	}
+	threshold, err := p.threshold()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Reading %s repeat counts from VCF...\n", p.Locus)
+	counts, err := extractRepeatCounts(vcfPath, p.Settings, p.Locus)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	if counts[0] >= threshold || counts[1] >= threshold {
+		return fmt.Errorf("repeat length at %s is not below the threshold of %d", p.Locus, threshold)
+	}
+
+	assignment := &RepeatExpansionCircuit{
+		LocusID:   locusID(strings.ToUpper(p.Locus)),
+		Threshold: threshold,
+		Allele1:   counts[0],
+		Allele2:   counts[1],
+	}
+
+	if err := p.Settings.prove(ctx, "repeat", &RepeatExpansionCircuit{}, assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("repeat", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven that both %s alleles are shorter than %d repeats\n", p.Locus, threshold)
+	fmt.Println("without revealing the actual repeat counts.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+// Verify checks the proof and that it is for p.Locus, when set, with a
+// threshold no higher than p's: a lower threshold proves more.
+func (p *RepeatExpansionProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 2 {
+		return false, withKind(ErrVerification, fmt.Errorf("repeat proof has %d public inputs, expected 2", len(public)))
+	}
+	locus := string(public[0].Bytes())
+	if p.Locus != "" {
+		if public[0].Cmp(locusID(strings.ToUpper(p.Locus))) != 0 {
+			return false, withKind(ErrVerification, fmt.Errorf("proof is for repeat locus %s, not %s", locus, p.Locus))
+		}
+		threshold, err := p.threshold()
+		if err != nil {
+			return false, withKind(ErrInvalidInput, err)
+		}
+		if public[1].Cmp(big.NewInt(int64(threshold))) > 0 {
+			return false, withKind(ErrVerification, fmt.Errorf("proof shows %s alleles below %s repeats, not below %d", locus, public[1], threshold))
+		}
+	}
+	fmt.Printf("Both %s alleles are below %s repeats\n", locus, public[1])
+
+	return verified, nil
+}
@@ -0,0 +1,136 @@
+package proofs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/test"
+)
+
+const expansionHunterVCF = `##fileformat=VCFv4.1
+##INFO=<ID=END,Number=1,Type=Integer,Description="End position of the variant">
+##INFO=<ID=REF,Number=1,Type=Integer,Description="Reference copy number">
+##INFO=<ID=RU,Number=1,Type=String,Description="Repeat unit in the reference orientation">
+##INFO=<ID=VARID,Number=1,Type=String,Description="Variant identifier">
+##INFO=<ID=REPID,Number=1,Type=String,Description="Repeat identifier">
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=REPCN,Number=1,Type=String,Description="Number of repeat units spanned by the allele">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	SAMPLE1
+chr4	3074876	.	C	<STR19>	.	PASS	END=3074939;REF=17;RU=CAG;VARID=HTT;REPID=HTT	GT:REPCN	0/1:17/19
+chrX	147912050	.	C	<STR31>	.	PASS	END=147912110;REF=20;RU=CGG;VARID=FMR1;REPID=FMR1	GT	1
+`
+
+func writeTempVCF(t *testing.T, vcfContent string) string {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "test*.vcf")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+
+	if _, err := tmpFile.WriteString(vcfContent); err != nil {
+		t.Fatalf("Failed to write to temp file: %v", err)
+	}
+	tmpFile.Close()
+
+	return tmpFile.Name()
+}
+
+func TestExtractRepeatCounts(t *testing.T) {
+	vcfPath := writeTempVCF(t, expansionHunterVCF)
+
+	counts, err := extractRepeatCounts(vcfPath, Settings{}, "HTT")
+	if err != nil {
+		t.Fatalf("Extracting HTT should not return error: %v", err)
+	}
+	if counts != [2]int{17, 19} {
+		t.Errorf("HTT counts = %v, want [17 19]", counts)
+	}
+
+	// FMR1 has no REPCN, so the count comes from the <STR31> ALT allele.
+	counts, err = extractRepeatCounts(vcfPath, Settings{}, "FMR1")
+	if err != nil {
+		t.Fatalf("Extracting FMR1 should not return error: %v", err)
+	}
+	if counts != [2]int{31, 31} {
+		t.Errorf("FMR1 counts = %v, want [31 31]", counts)
+	}
+
+	if _, err := extractRepeatCounts(vcfPath, Settings{}, "DMPK"); err == nil {
+		t.Errorf("Missing locus should return an error")
+	}
+}
+
+func TestRepeatExpansionCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+
+	below := &RepeatExpansionCircuit{LocusID: locusID("HTT"), Threshold: 36, Allele1: 17, Allele2: 35}
+	if err := test.IsSolved(&RepeatExpansionCircuit{}, below, field); err != nil {
+		t.Errorf("Alleles below the threshold should satisfy the circuit: %v", err)
+	}
+
+	expanded := &RepeatExpansionCircuit{LocusID: locusID("HTT"), Threshold: 36, Allele1: 17, Allele2: 36}
+	if err := test.IsSolved(&RepeatExpansionCircuit{}, expanded, field); err == nil {
+		t.Errorf("An allele at the threshold should not satisfy the circuit")
+	}
+}
+
+func TestRepeatExpansionProofBindsLocus(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "repeat_proof.bin")
+	if err := (&RepeatExpansionProof{Locus: "HTT"}).Generate(t.Context(), writeTempVCF(t, expansionHunterVCF), "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	assertInputBound(t, out, 0)
+}
+
+func TestRepeatExpansionProofChecksClaim(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "repeat_proof.bin")
+	if err := (&RepeatExpansionProof{Locus: "HTT", Threshold: 10000}).Generate(t.Context(), writeTempVCF(t, expansionHunterVCF), "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, tt := range []struct {
+		locus     string
+		threshold int
+		ok        bool
+	}{
+		{"HTT", 0, false},
+		{"HTT", 9999, false},
+		{"FMR1", 10000, false},
+		{"htt", 10000, true},
+		{"HTT", 20000, true},
+	} {
+		p := &RepeatExpansionProof{Locus: tt.locus, Threshold: tt.threshold}
+		ok, err := p.Verify(t.Context(), out+".vk", out)
+		if tt.ok && (!ok || err != nil) {
+			t.Errorf("Verify as %s below %d = %v, %v", tt.locus, tt.threshold, ok, err)
+		}
+		if !tt.ok && (ok || ErrorKind(err) != ErrVerification) {
+			t.Errorf("Verify as %s below %d = %v, %v; want a verification error", tt.locus, tt.threshold, ok, err)
+		}
+	}
+}
+
+// assertInputBound checks that the proof at proofPath stops verifying once
+// its public input at index is changed.
+func assertInputBound(t *testing.T, proofPath string, index int) {
+	t.Helper()
+	p, w, err := readProofFile(proofPath)
+	if err != nil {
+		t.Fatalf("readProofFile: %v", err)
+	}
+	vector := w.Vector().(fr.Vector)
+	one := fr.One()
+	vector[index].Add(&vector[index], &one)
+
+	tampered := filepath.Join(filepath.Dir(proofPath), "tampered.bin")
+	if err := writeProofFile(tampered, nil, p, w); err != nil {
+		t.Fatalf("writeProofFile: %v", err)
+	}
+	if _, err := (Settings{}).verifyProof(t.Context(), proofPath+".vk", tampered); err == nil {
+		t.Errorf("proof should not verify with public input %d changed", index)
+	}
+}
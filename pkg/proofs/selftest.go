@@ -0,0 +1,217 @@
+package proofs
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/consensys/gnark/backend/plonk"
+)
+
+// DefaultSelfTestSeed is the seed SelfTest is usually run with.
+const DefaultSelfTestSeed = 1
+
+// SelfTestResult is how one proof type fared in SelfTest.
+type SelfTestResult struct {
+	Type        string
+	Constraints int
+	Duration    time.Duration
+	// Error is why the circuit could not be compiled or set up, or the
+	// built-in witness proved or verified; empty when the type passed.
+	Error string
+}
+
+// selfTestVCF is the built-in witness: one sample with a call for every
+// proof type selfTestConfig configures.
+const selfTestVCF = `##fileformat=VCFv4.2
+##INFO=<ID=END,Number=1,Type=Integer,Description="End position of the variant or reference block">
+##INFO=<ID=SVTYPE,Number=1,Type=String,Description="Type of structural variant">
+##INFO=<ID=REF,Number=1,Type=Integer,Description="Reference copy number">
+##INFO=<ID=RU,Number=1,Type=String,Description="Repeat unit in the reference orientation">
+##INFO=<ID=VARID,Number=1,Type=String,Description="Variant identifier">
+##INFO=<ID=REPID,Number=1,Type=String,Description="Repeat identifier">
+##ALT=<ID=DEL,Description="Deletion">
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=DP,Number=1,Type=Integer,Description="Read depth">
+##FORMAT=<ID=GQ,Number=1,Type=Integer,Description="Genotype quality">
+##FORMAT=<ID=PS,Number=1,Type=Integer,Description="Phase set">
+##FORMAT=<ID=REPCN,Number=1,Type=String,Description="Number of repeat units spanned by the allele">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	SELFTEST
+1	25648478	rs590787	C	T	60	PASS	.	GT:DP	0/1:30
+4	3074876	.	C	<STR19>	.	PASS	END=3074939;REF=17;RU=CAG;VARID=HTT;REPID=HTT	GT:REPCN	0/1:17/19
+5	33951693	rs16891982	C	G	60	PASS	.	GT:DP	1/1:30
+7	117199600	.	A	<NON_REF>	.	.	END=117199700	GT:DP:GQ	0/0:30:45
+7	117227832	rs113993959	G	T	60	PASS	.	GT:DP:PS	0|1:30:100
+7	117227865	rs75527207	G	A	60	PASS	.	GT:DP:PS	1|0:30:100
+9	136131322	rs8176746	G	T	60	PASS	.	GT:DP	0/1:30
+9	136132908	rs8176719	T	TC	60	PASS	.	GT:DP	1/1:30
+10	96541616	rs4244285	G	A	60	PASS	.	GT:DP	0/1:30
+15	28365618	rs12913832	A	G	60	PASS	.	GT:DP	1|0:30
+17	7577120	.	C	T	60	PASS	.	GT:DP	0/1:40
+17	41196000	.	N	<DEL>	60	PASS	SVTYPE=DEL;END=41200000	GT	0/1
+17	41276045	.	C	G	60	PASS	.	GT:DP	0/1:30
+22	20765447	.	G	A	60	PASS	.	GT:DP	0/1:30
+`
+
+// selfTestRecords is the number of records in selfTestVCF, all of which a
+// chromosome proof commits to.
+const selfTestRecords = 15
+
+// selfTestNormalVCF is the matched normal of selfTestVCF for somatic
+// proofs.
+const selfTestNormalVCF = `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=DP,Number=1,Type=Integer,Description="Read depth">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	NORMAL
+17	7577120	.	C	T	60	PASS	.	GT:DP	0/0:35
+`
+
+// selfTestConfig configures a proof of type name to be proved over
+// selfTestVCF, with normalVCF holding selfTestNormalVCF.
+func selfTestConfig(name string, s Settings, normalVCF string) ProofConfig {
+	brca1 := VariantSpec{Chromosome: "17", Position: 41276045, Ref: "C", Alt: "G"}
+	cfg := ProofConfig{
+		Settings:      s,
+		MaxVariants:   selfTestRecords,
+		Variant:       brca1.String(),
+		SecondVariant: "7:117227865:G:A",
+		Position:      "15:28365618",
+		Region:        "17:41196000-41277500",
+		SVType:        "DEL",
+		Locus:         "HTT",
+		Gene:          "CYP2C19",
+		Panel:         []VariantSpec{brca1, {Chromosome: "15", Position: HERC2Pos, Ref: "A", Alt: "G"}},
+		K:             1,
+	}
+	switch name {
+	case "compoundhet":
+		cfg.Gene = "CFTR"
+	case "haplotype":
+		cfg.Variant = "7:117227832:G:T"
+	case "absence":
+		cfg.Variant = "17:41276045:C:T"
+	case "somatic":
+		cfg.Variant = "17:7577120:C:T"
+		cfg.NormalVCF = normalVCF
+	case "wildtype":
+		cfg.Position = "7:117199644"
+	}
+	return cfg
+}
+
+// SelfTest compiles the circuit of each of types, or of every registered
+// type with a circuit of its own when types is empty, runs its setup,
+// proves a built-in witness with the keys and verifies the proof, as
+// Benchmark does, writing the keys and proofs to dir. It is a health check
+// of the installed build rather than of any VCF.
+//
+// Setup is seeded where the backend allows it: a PLONK backend without an
+// SRS sets up every circuit from one development SRS whose secret is
+// derived from seed, so its keys are the same on every run. Groth16 setup
+// samples its toxic waste inside gnark, which takes no seed, so its keys
+// differ between runs.
+func SelfTest(ctx context.Context, s Settings, dir string, seed uint64, types []string) ([]SelfTestResult, error) {
+	vcf := filepath.Join(dir, "selftest.vcf")
+	normalVCF := filepath.Join(dir, "selftest_normal.vcf")
+	if err := os.WriteFile(vcf, []byte(selfTestVCF), 0644); err != nil {
+		return nil, fmt.Errorf("writing self-test VCF: %w", err)
+	}
+	if err := os.WriteFile(normalVCF, []byte(selfTestNormalVCF), 0644); err != nil {
+		return nil, fmt.Errorf("writing self-test VCF: %w", err)
+	}
+
+	named := len(types) > 0
+	if !named {
+		for _, t := range proofTypes {
+			types = append(types, t.Name)
+		}
+	}
+	var tested []Proof
+	var results []SelfTestResult
+	for _, name := range types {
+		p, err := NewProof(name, selfTestConfig(name, s, normalVCF))
+		if err != nil {
+			return nil, err
+		}
+		proofType, _, err := proofCircuit(p)
+		if err != nil {
+			// Types aggregating other proofs have no circuit of their own
+			// to test unless asked for by name.
+			if !named && errors.Is(err, ErrNoCircuit) {
+				continue
+			}
+			results = append(results, SelfTestResult{Type: name, Error: err.Error()})
+			tested = append(tested, nil)
+			continue
+		}
+		results = append(results, SelfTestResult{Type: proofType})
+		tested = append(tested, p)
+	}
+
+	if s.Backend == BackendPlonk && s.SRS == "" {
+		srs, err := selfTestSRS(tested, s.Curve, filepath.Join(dir, "selftest.srs"), seed)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range tested {
+			if p != nil {
+				proofSettings(p).SRS = srs
+			}
+		}
+	}
+
+	for i, p := range tested {
+		if p == nil {
+			continue
+		}
+		fmt.Printf("Testing %s...\n", results[i].Type)
+		start := time.Now()
+		bench, err := Benchmark(ctx, p, vcf, dir)
+		results[i].Duration = time.Since(start)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Constraints, results[i].Error = bench.Constraints, bench.ProveError
+	}
+	return results, nil
+}
+
+// selfTestSRS writes to path a development SRS over curve large enough for
+// every circuit of proofs, its secret derived from seed, and returns path.
+func selfTestSRS(proofs []Proof, curve Curve, path string, seed uint64) (string, error) {
+	backend, err := BackendPlonk.backend()
+	if err != nil {
+		return "", err
+	}
+	size := 0
+	for _, p := range proofs {
+		if p == nil {
+			continue
+		}
+		_, circuit, err := proofCircuit(p)
+		if err != nil {
+			return "", err
+		}
+		cs, err := backend.Compile(curve, circuit)
+		if err != nil {
+			// Benchmark reports the failure against the proof type.
+			continue
+		}
+		canonical, _ := plonk.SRSSize(cs)
+		size = max(size, canonical)
+	}
+
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	fmt.Printf("Generating a %d-point development SRS from seed %d...\n", size, seed)
+	if err := generateSRS(path, curve, uint64(size), mathrand.NewChaCha8(key)); err != nil {
+		return "", err
+	}
+	return path, nil
+}
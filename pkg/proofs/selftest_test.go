@@ -0,0 +1,46 @@
+package proofs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	types := []string{"chromosome", "bloodtype", "somatic", "sv", "wildtype"}
+	results, err := SelfTest(t.Context(), Settings{}, t.TempDir(), DefaultSelfTestSeed, types)
+	if err != nil {
+		t.Fatalf("SelfTest: %v", err)
+	}
+	if len(results) != len(types) {
+		t.Fatalf("SelfTest returned %d results, want %d", len(results), len(types))
+	}
+	for _, r := range results {
+		if r.Error != "" || r.Constraints == 0 {
+			t.Errorf("%s: %+v", r.Type, r)
+		}
+	}
+
+	// A PLONK self-test sets up the same keys from the same seed.
+	var keys [][]byte
+	for range 2 {
+		dir := t.TempDir()
+		results, err := SelfTest(t.Context(), Settings{Backend: BackendPlonk}, dir, DefaultSelfTestSeed, []string{"brca1"})
+		if err != nil || results[0].Error != "" {
+			t.Fatalf("PLONK SelfTest = %+v, %v", results, err)
+		}
+		vk, err := os.ReadFile(filepath.Join(dir, "brca1.vk"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, vk)
+	}
+	if !bytes.Equal(keys[0], keys[1]) {
+		t.Errorf("PLONK keys set up from the same seed differ")
+	}
+
+	if results, err := SelfTest(t.Context(), Settings{}, t.TempDir(), DefaultSelfTestSeed, []string{"cohort"}); err != nil || results[0].Error == "" {
+		t.Errorf("cohort has no circuit of its own and should fail when named: %+v, %v", results, err)
+	}
+}
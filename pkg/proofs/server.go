@@ -0,0 +1,823 @@
+package proofs
+
+import (
+	"cmp"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxUploadSize bounds the VCFs, jobs and proofs a proof server accepts.
+const maxUploadSize = 4 << 30
+
+// openAPISpec is the OpenAPI document of the proof server's API, served
+// as GET /openapi.json. The proofsclient tests check the client against it.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// defaultKeepFinished is how long a proof server keeps the records of
+// finished proofs unless ProofServer.KeepFinished says otherwise.
+const defaultKeepFinished = time.Hour
+
+// serverProofFile is the name of a proof in its directory under
+// ProofServer.Dir.
+const serverProofFile = "proof.bin"
+
+// Proof statuses reported by a proof server.
+const (
+	ProofPending = "pending"
+	ProofDone    = "done"
+	ProofFailed  = "failed"
+)
+
+// ProofServer generates and verifies proofs over HTTP, for applications
+// that would otherwise run the CLI:
+//
+//	POST   /proofs       start generating a proof; answers 202 with its record
+//	GET    /proofs/{id}  the proof's record, with the proof once it is done
+//	DELETE /proofs/{id}  stop generating a pending proof, which then fails
+//	POST   /verify       verify an uploaded or previously generated proof
+//	GET    /openapi.json the OpenAPI document of these endpoints
+//
+// Requests are multipart forms. POST /proofs takes the proof "type", a
+// "vcf" file, and the proof's options as fields named like the generate
+// flags (position, variant, gene, snps, region, nonce, valid-for, ...).
+// Instead of a VCF it takes a "job" file solved and sealed by the solve
+// command, with the "metadata" file solve wrote, when the server has a
+// prover key. POST /verify rejects expired proofs unless "ignore-expiry" is
+// true.
+// Uploaded VCFs are deleted once their proof is made, but the server reads
+// the genotypes in them and must be trusted with them.
+//
+// A server with Tenants is shared by several clients: every request must
+// bring a tenant's API key as a bearer token or X-API-Key header, and is
+// refused with 401 without one and with 429 over the tenant's rate limit
+// or pending proof quota. A tenant sees only its own proofs.
+type ProofServer struct {
+	// Dir holds each generated proof, with its metadata and verifying
+	// key, in a subdirectory named after the proof's ID.
+	Dir string
+	// KeyDir, when set, holds keys from setup named <type>.pk and
+	// <type>.vk. Proofs of a type with keys there are proved with them,
+	// and verified with them when a request brings no verifying key.
+	// Keys are read through Settings.KeyStore, a new KeyStore unless set,
+	// so concurrent requests share each key read.
+	KeyDir string
+	// Settings are the settings proofs start from; requests set their
+	// nonce, validity and genotype policies.
+	Settings Settings
+	// TraitPanel is the trait panel gene options are looked up in.
+	TraitPanel string
+	// ProverKey, when set, lets POST /proofs prove sealed jobs.
+	ProverKey *ecdh.PrivateKey
+	// SRS is the KZG SRS file for PLONK jobs that need a setup.
+	SRS string
+	// Workers bounds the proofs generated at once; zero means one.
+	Workers int
+	// Timeout, when set, bounds how long generating each proof may take
+	// once it starts; a proof still running then fails.
+	Timeout time.Duration
+	// KeepFinished is how long the record of a finished proof is kept in
+	// memory, with its error or progress; zero means an hour. A done proof
+	// is still served from Dir once its record is gone, a failed one is
+	// not.
+	KeepFinished time.Duration
+	// Tenants, when set, are the clients the server takes requests from.
+	// A tenant's proofs are stored under Dir/<tenant ID>, and its keys
+	// are read from KeyDir/<tenant ID> before the shared ones in KeyDir.
+	// Handler panics on tenants that ReadTenants would reject.
+	Tenants []Tenant
+
+	init    sync.Once
+	mu      sync.Mutex
+	records map[string]*ProofRecord
+	slots   chan struct{}
+	// tenants indexes Tenants by the hashes of their API keys.
+	tenants map[string]*tenantState
+}
+
+// ProofRecord is what a proof server reports about a proof.
+type ProofRecord struct {
+	ID          string     `json:"id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	ErrorKind   string     `json:"error_kind,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Progress is the latest progress of a proof being generated from a
+	// VCF.
+	Progress *ProgressEvent `json:"progress,omitempty"`
+	// The rest is set once the proof is done.
+	PublicInputs []string       `json:"public_inputs,omitempty"`
+	Claims       []string       `json:"claims,omitempty"`
+	Metadata     *ProofMetadata `json:"metadata,omitempty"`
+	Proof        []byte         `json:"proof,omitempty"`
+	VerifyingKey []byte         `json:"verifying_key,omitempty"`
+
+	verifyingKeyPath string
+	cancel           context.CancelCauseFunc
+	// tenant is the tenant the proof is for, on a server with tenants.
+	tenant *tenantState
+}
+
+// VerifyResult is a proof server's answer to POST /verify.
+type VerifyResult struct {
+	Type         string   `json:"type"`
+	Verified     bool     `json:"verified"`
+	Error        string   `json:"error,omitempty"`
+	ErrorKind    string   `json:"error_kind,omitempty"`
+	PublicInputs []string `json:"public_inputs,omitempty"`
+	Claims       []string `json:"claims,omitempty"`
+}
+
+// errorKindNames name the error kinds in server responses.
+var errorKindNames = map[error]string{
+	ErrInvalidInput: "invalid_input",
+	ErrVCF:          "vcf",
+	ErrNotFound:     "not_found",
+	ErrProving:      "proving",
+	ErrVerification: "verification",
+	ErrUnsupported:  "unsupported",
+}
+
+var proofIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// Handler returns the server's HTTP handler.
+func (s *ProofServer) Handler() http.Handler {
+	s.init.Do(func() {
+		s.records = make(map[string]*ProofRecord)
+		s.slots = make(chan struct{}, max(s.Workers, 1))
+		if s.Settings.KeyStore == nil {
+			s.Settings.KeyStore = NewKeyStore()
+		}
+		if len(s.Tenants) > 0 {
+			tenants, err := newTenantStates(s.Tenants)
+			if err != nil {
+				panic("proofs: ProofServer.Tenants: " + err.Error())
+			}
+			s.tenants = tenants
+		}
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /proofs", s.handleGenerate)
+	mux.HandleFunc("GET /proofs/{id}", s.handleGet)
+	mux.HandleFunc("DELETE /proofs/{id}", s.handleCancel)
+	mux.HandleFunc("POST /verify", s.handleVerify)
+	mux.HandleFunc("GET /openapi.json", handleOpenAPI)
+	return traceRequests(mux, s.authenticate(mux))
+}
+
+// traceRequests wraps h, serving the routes of mux, to serve each request
+// under a span continuing the trace its headers propagate, so the spans of
+// generating a proof join the trace of the client that asked for it.
+func traceRequests(mux *http.ServeMux, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		_, pattern := mux.Handler(r)
+		ctx, span := otel.Tracer(tracerName).Start(ctx, cmp.Or(pattern, r.Method+" "+r.URL.Path),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("http.request.method", r.Method), attribute.String("url.path", r.URL.Path)))
+		defer span.End()
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// handleOpenAPI serves the OpenAPI document of the server's API.
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+func (s *ProofServer) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	// The tenant's pending quota is taken before the upload is read, so a
+	// tenant over it cannot make the server read a VCF first.
+	tenant := requestTenant(r)
+	if !s.reservePending(tenant) {
+		writeError(w, http.StatusTooManyRequests, fmt.Errorf("tenant %s already has %d proofs pending", tenant.ID, tenant.MaxPending))
+		return
+	}
+	reserved := true
+	defer func() {
+		if reserved {
+			s.releasePending(tenant)
+		}
+	}()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("reading form: %w", err))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	id, err := newProofID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	dir := filepath.Join(s.proofsDir(tenant), id)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("creating proof directory: %w", err))
+		return
+	}
+	record := &ProofRecord{ID: id, Type: strings.ToLower(r.FormValue("type")), Status: ProofPending, CreatedAt: time.Now().UTC(), tenant: tenant}
+
+	var run func(ctx context.Context, outputPath string) (string, error)
+	if _, _, err := r.FormFile("job"); err == nil {
+		run, err = s.jobRun(r, dir, record)
+		if err != nil {
+			os.RemoveAll(dir)
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	} else {
+		run, err = s.vcfRun(r, dir, record)
+		if err != nil {
+			os.RemoveAll(dir)
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// The proof outlives the request, but stays in the request's trace.
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(r.Context()))
+	record.cancel = cancel
+	s.mu.Lock()
+	s.evictFinished()
+	s.records[id] = record
+	reserved = false
+	response := *record
+	s.mu.Unlock()
+
+	go func() {
+		defer cancel(nil)
+		ctx, span := startSpan(ctx, "proofs.job", attribute.String("proofs.id", id), attribute.String("proof.type", record.Type))
+		defer span.End()
+		select {
+		case s.slots <- struct{}{}:
+		case <-ctx.Done():
+			s.finish(record, "", context.Cause(ctx))
+			return
+		}
+		defer func() { <-s.slots }()
+
+		if s.Timeout > 0 {
+			var stop context.CancelFunc
+			ctx, stop = context.WithTimeoutCause(ctx, s.Timeout, fmt.Errorf("proof generation took longer than %s", s.Timeout))
+			defer stop()
+		}
+		vkPath, err := run(ctx, filepath.Join(dir, serverProofFile))
+		if ctx.Err() != nil {
+			err = context.Cause(ctx)
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		s.finish(record, vkPath, err)
+	}()
+
+	w.Header().Set("Location", "/proofs/"+id)
+	writeJSON(w, http.StatusAccepted, response)
+}
+
+// vcfRun saves the uploaded VCF and returns a function generating the
+// requested proof from it, which deletes the VCF once done.
+func (s *ProofServer) vcfRun(r *http.Request, dir string, record *ProofRecord) (func(context.Context, string) (string, error), error) {
+	cfg, err := s.requestConfig(r, record.Type)
+	if err != nil {
+		return nil, err
+	}
+	// Verify leaves k unchecked when none is given; generate defaults it
+	// to 1 as the CLI does.
+	if cfg.K == 0 {
+		cfg.K = 1
+	}
+	cfg.Settings.Progress = func(e ProgressEvent) {
+		s.mu.Lock()
+		record.Progress = &e
+		s.mu.Unlock()
+	}
+	proof, err := NewProof(record.Type, cfg)
+	if err != nil {
+		return nil, err
+	}
+	t, _ := LookupProofType(record.Type)
+	record.Type = t.Name
+
+	vcfPath := filepath.Join(dir, "input.vcf")
+	if err := saveFormFile(r, "vcf", vcfPath); err != nil {
+		return nil, err
+	}
+
+	provingKeyPath := s.keyFile(record.tenant, record.Type, ".pk")
+	return func(ctx context.Context, outputPath string) (string, error) {
+		defer os.Remove(vcfPath)
+		if err := proof.Generate(ctx, vcfPath, provingKeyPath, outputPath); err != nil {
+			return "", err
+		}
+		if provingKeyPath != "" {
+			return strings.TrimSuffix(provingKeyPath, ".pk") + ".vk", nil
+		}
+		// The proving key set up for this proof alone is of no further use.
+		os.Remove(outputPath + ".pk")
+		return outputPath + ".vk", nil
+	}, nil
+}
+
+// jobRun reads an uploaded job sealed by solve, with the metadata solve
+// wrote for its proof, and returns a function proving it.
+func (s *ProofServer) jobRun(r *http.Request, dir string, record *ProofRecord) (func(context.Context, string) (string, error), error) {
+	if s.ProverKey == nil {
+		return nil, withKind(ErrInvalidInput, errors.New("this server takes no proving jobs; upload a VCF instead"))
+	}
+	job, err := readFormFile(r, "job")
+	if err != nil {
+		return nil, err
+	}
+	metaPath := filepath.Join(dir, serverProofFile+MetadataSuffix)
+	if err := saveFormFile(r, "metadata", metaPath); err != nil {
+		return nil, err
+	}
+	meta, err := ReadMetadata(filepath.Join(dir, serverProofFile))
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, err)
+	}
+	if record.Type == "" {
+		record.Type = meta.ProofType
+	}
+
+	return func(ctx context.Context, outputPath string) (string, error) {
+		result, err := runJob(ctx, s.ProverKey, job, s.SRS, s.Settings.Accelerator)
+		if err != nil {
+			return "", withKind(ErrProving, err)
+		}
+		if err := writeResult(result, outputPath); err != nil {
+			return "", err
+		}
+		if result.VerifyingKey == nil {
+			// The job brought its own proving key, so its owner holds the
+			// verifying key.
+			return "", nil
+		}
+		os.Remove(outputPath + ".pk")
+		return outputPath + ".vk", nil
+	}, nil
+}
+
+// reservePending counts a proof of tenant, nil on a server without
+// tenants, as pending, and reports false without doing so when the tenant
+// already has as many pending as it may.
+func (s *ProofServer) reservePending(tenant *tenantState) bool {
+	if tenant == nil {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tenant.MaxPending > 0 && tenant.pending >= tenant.MaxPending {
+		return false
+	}
+	tenant.pending++
+	return true
+}
+
+// releasePending undoes reservePending for a proof that was not started.
+func (s *ProofServer) releasePending(tenant *tenantState) {
+	if tenant == nil {
+		return
+	}
+	s.mu.Lock()
+	tenant.pending--
+	s.mu.Unlock()
+}
+
+// evictFinished drops the records of proofs that finished more than
+// KeepFinished ago. It is called with s.mu held.
+func (s *ProofServer) evictFinished() {
+	keep := cmp.Or(s.KeepFinished, defaultKeepFinished)
+	cutoff := time.Now().Add(-keep)
+	for id, record := range s.records {
+		if record.CompletedAt != nil && record.CompletedAt.Before(cutoff) {
+			delete(s.records, id)
+		}
+	}
+}
+
+// finish records the outcome of generating record's proof.
+func (s *ProofServer) finish(record *ProofRecord, vkPath string, err error) {
+	now := time.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record.CompletedAt = &now
+	record.verifyingKeyPath = vkPath
+	if record.tenant != nil {
+		record.tenant.pending--
+	}
+	if err != nil {
+		record.Status = ProofFailed
+		record.Error = err.Error()
+		record.ErrorKind = errorKindNames[ErrorKind(err)]
+		return
+	}
+	record.Status = ProofDone
+}
+
+func (s *ProofServer) handleGet(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !proofIDPattern.MatchString(id) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no proof %s", id))
+		return
+	}
+	tenant := requestTenant(r)
+	proofPath := filepath.Join(s.proofsDir(tenant), id, serverProofFile)
+
+	s.mu.Lock()
+	s.evictFinished()
+	record, ok := s.records[id]
+	ok = ok && record.tenant == tenant
+	var response ProofRecord
+	if ok {
+		response = *record
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		// A proof generated before the server restarted is still on disk.
+		meta, err := ReadMetadata(proofPath)
+		if err != nil || !fileExists(proofPath) {
+			writeError(w, http.StatusNotFound, fmt.Errorf("no proof %s", id))
+			return
+		}
+		response = ProofRecord{ID: id, Type: meta.ProofType, Status: ProofDone, CreatedAt: meta.CreatedAt}
+		if fileExists(proofPath + ".vk") {
+			response.verifyingKeyPath = proofPath + ".vk"
+		} else {
+			response.verifyingKeyPath = s.keyFile(tenant, meta.ProofType, ".vk")
+		}
+	}
+
+	if response.Status == ProofDone {
+		if err := response.load(proofPath); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleCancel stops generating a pending proof, which then fails.
+func (s *ProofServer) handleCancel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	record, ok := s.records[id]
+	ok = ok && record.tenant == requestTenant(r)
+	var response ProofRecord
+	if ok {
+		response = *record
+	}
+	s.mu.Unlock()
+
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no pending proof %s", id))
+	case response.Status != ProofPending:
+		writeError(w, http.StatusConflict, fmt.Errorf("proof %s is already %s", id, response.Status))
+	default:
+		response.cancel(errors.New("proof generation was cancelled"))
+		writeJSON(w, http.StatusAccepted, response)
+	}
+}
+
+// load reads a done proof at proofPath into the record.
+func (p *ProofRecord) load(proofPath string) error {
+	summary, err := InspectProof(proofPath)
+	if err != nil {
+		return err
+	}
+	p.PublicInputs = bigStrings(summary.Inputs)
+	p.Claims = summary.Claims
+	p.Metadata = summary.Metadata
+	if p.Proof, err = os.ReadFile(proofPath); err != nil {
+		return fmt.Errorf("reading proof: %w", err)
+	}
+	if p.verifyingKeyPath != "" {
+		if p.VerifyingKey, err = os.ReadFile(p.verifyingKeyPath); err != nil {
+			return fmt.Errorf("reading verifying key: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *ProofServer) handleVerify(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("reading form: %w", err))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	dir, err := os.MkdirTemp("", "verify-")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	result, err := s.verify(r, dir)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if ErrorKind(err) == ErrInvalidInput {
+			status = http.StatusBadRequest
+		}
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// verify verifies the proof a POST /verify request names, uploading it to
+// dir unless it is one the server generated.
+func (s *ProofServer) verify(r *http.Request, dir string) (*VerifyResult, error) {
+	proofPath := filepath.Join(dir, serverProofFile)
+	vkPath := filepath.Join(dir, "proof.vk")
+	proofType := strings.ToLower(r.FormValue("type"))
+	tenant := requestTenant(r)
+
+	if id := r.FormValue("id"); id != "" {
+		if !proofIDPattern.MatchString(id) || !fileExists(filepath.Join(s.proofsDir(tenant), id, serverProofFile)) {
+			return nil, withKind(ErrInvalidInput, fmt.Errorf("no proof %s", id))
+		}
+		proofPath = filepath.Join(s.proofsDir(tenant), id, serverProofFile)
+		vkPath = proofPath + ".vk"
+	} else {
+		if err := saveFormFile(r, "proof", proofPath); err != nil {
+			return nil, err
+		}
+		if _, _, err := r.FormFile("metadata"); err == nil {
+			if err := saveFormFile(r, "metadata", proofPath+MetadataSuffix); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if proofType == "" {
+		detected, err := DetectProofType(proofPath)
+		if err != nil {
+			return nil, withKind(ErrInvalidInput, err)
+		}
+		proofType = detected
+	} else if err := CheckProofType(proofPath, proofType); err != nil {
+		return nil, err
+	}
+	// Only a registered type names a key file.
+	registered, ok := LookupProofType(proofType)
+	if !ok {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("unknown proof type %q", proofType))
+	}
+	proofType = registered.Name
+
+	if _, _, err := r.FormFile("verifying_key"); err == nil {
+		vkPath = filepath.Join(dir, "upload.vk")
+		if err := saveFormFile(r, "verifying_key", vkPath); err != nil {
+			return nil, err
+		}
+	} else if !fileExists(vkPath) {
+		if shared := s.keyFile(tenant, proofType, ".vk"); shared != "" {
+			vkPath = shared
+		}
+	}
+	if !fileExists(vkPath) {
+		return nil, withKind(ErrInvalidInput, errors.New("no verifying key: upload one as verifying_key"))
+	}
+
+	cfg, err := s.requestConfig(r, proofType)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := NewProof(proofType, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyResult{Type: proofType}
+	verified, err := proof.Verify(r.Context(), vkPath, proofPath)
+	if err == nil && verified && cfg.Settings.Nonce != "" {
+		err = CheckNonce(proofPath, cfg.Settings.Nonce)
+	}
+	if err == nil && verified && r.FormValue("ignore-expiry") != "true" {
+		err = CheckExpiry(proofPath, time.Now())
+	}
+	switch {
+	case err != nil && ErrorKind(err) == ErrInvalidInput:
+		return nil, err
+	case err != nil:
+		result.Error = err.Error()
+		result.ErrorKind = errorKindNames[ErrorKind(err)]
+	default:
+		result.Verified = verified
+	}
+	if summary, err := InspectProof(proofPath); err == nil {
+		result.PublicInputs = bigStrings(summary.Inputs)
+		result.Claims = summary.Claims
+	}
+	return result, nil
+}
+
+// requestConfig reads the options of a proof of proofType from the
+// request's form fields, named like the generate and verify flags.
+func (s *ProofServer) requestConfig(r *http.Request, proofType string) (ProofConfig, error) {
+	settings := s.Settings
+	settings.Nonce = r.FormValue("nonce")
+	settings.Sample = r.FormValue("sample")
+	settings.Timestamp = settings.Timestamp || r.FormValue("timestamp") == "true"
+	var err error
+	if v := r.FormValue("valid-for"); v != "" {
+		if settings.ValidFor, err = time.ParseDuration(v); err != nil {
+			return ProofConfig{}, withKind(ErrInvalidInput, fmt.Errorf("invalid valid-for: %w", err))
+		}
+	}
+	if v := r.FormValue("imputation"); v != "" {
+		if settings.Imputation.Mode, err = ParseImputationMode(v); err != nil {
+			return ProofConfig{}, withKind(ErrInvalidInput, err)
+		}
+	}
+	if v := r.FormValue("missing"); v != "" {
+		if settings.Missing, err = ParseMissingPolicy(v); err != nil {
+			return ProofConfig{}, withKind(ErrInvalidInput, err)
+		}
+	}
+	if v := r.FormValue("digest"); v != "" {
+		if settings.Digest, err = ParseDigestMode(v); err != nil {
+			return ProofConfig{}, withKind(ErrInvalidInput, err)
+		}
+	}
+
+	cfg := ProofConfig{
+		Settings:           settings,
+		ExpectedCommitment: r.FormValue("commitment"),
+		WholeLocus:         r.FormValue("whole-locus") == "true",
+		Region:             r.FormValue("region"),
+		SVType:             r.FormValue("sv-type"),
+		Locus:              r.FormValue("locus"),
+		Gene:               r.FormValue("gene"),
+		Variant:            r.FormValue("variant"),
+		Position:           r.FormValue("position"),
+		SecondVariant:      r.FormValue("second-variant"),
+		TumorSample:        r.FormValue("tumor-sample"),
+		NormalSample:       r.FormValue("normal-sample"),
+	}
+	if cfg.Locus == "" {
+		cfg.Locus = "HTT"
+	}
+	ints := []struct {
+		name string
+		dst  *int
+	}{
+		{"max-variants", &cfg.MaxVariants},
+		{"tree-depth", &cfg.TreeDepth},
+		{"target", &cfg.Target},
+		{"k", &cfg.K},
+		{"repeat-threshold", &cfg.RepeatThreshold},
+		{"min-qual", &cfg.Quality.MinQuality},
+		{"min-depth", &cfg.Quality.MinDepth},
+	}
+	for _, f := range ints {
+		if v := r.FormValue(f.name); v != "" {
+			if *f.dst, err = strconv.Atoi(v); err != nil {
+				return ProofConfig{}, withKind(ErrInvalidInput, fmt.Errorf("invalid %s %q", f.name, v))
+			}
+		}
+	}
+
+	if cfg.Region != "" {
+		region, err := ParseRegion(cfg.Region)
+		if err != nil {
+			return ProofConfig{}, withKind(ErrInvalidInput, err)
+		}
+		cfg.Settings.Region = &region
+	}
+	switch proofType {
+	case "region", "sv":
+		if cfg.Region == "" && cfg.Gene != "" {
+			panel, err := LoadTraitPanel(s.TraitPanel)
+			if err != nil {
+				return ProofConfig{}, err
+			}
+			region, err := GeneRegion(panel, cfg.Gene)
+			if err != nil {
+				return ProofConfig{}, withKind(ErrInvalidInput, err)
+			}
+			cfg.Region = region.String()
+		}
+	case "dosage", "threshold", "panel":
+		if snps := r.FormValue("snps"); snps != "" {
+			for _, snp := range strings.Split(snps, ",") {
+				v, err := ParseVariantSpec(strings.TrimSpace(snp))
+				if err != nil {
+					return ProofConfig{}, err
+				}
+				cfg.Panel = append(cfg.Panel, v)
+			}
+		} else if cfg.Gene != "" {
+			panel, err := LoadTraitPanel(s.TraitPanel)
+			if err != nil {
+				return ProofConfig{}, err
+			}
+			if cfg.Panel, err = PanelVariants(panel, cfg.Gene); err != nil {
+				return ProofConfig{}, withKind(ErrInvalidInput, err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+func newProofID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("generating proof ID: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// readFormFile reads the uploaded file in the request's field name.
+func readFormFile(r *http.Request, name string) ([]byte, error) {
+	f, _, err := r.FormFile(name)
+	if err != nil {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("no %s file uploaded", name))
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// saveFormFile writes the uploaded file in the request's field name to
+// path.
+func saveFormFile(r *http.Request, name string, path string) error {
+	f, _, err := r.FormFile(name)
+	if err != nil {
+		return withKind(ErrInvalidInput, fmt.Errorf("no %s file uploaded", name))
+	}
+	defer f.Close()
+	return writeUpload(f, path)
+}
+
+func writeUpload(f multipart.File, path string) error {
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("saving upload: %w", err)
+	}
+	if _, err := io.Copy(out, f); err != nil {
+		out.Close()
+		return fmt.Errorf("saving upload: %w", err)
+	}
+	return out.Close()
+}
+
+func fileExists(path string) bool {
+	_, err := statFile(path)
+	return err == nil
+}
+
+func bigStrings(values []*big.Int) []string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = v.String()
+	}
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error     string `json:"error"`
+		ErrorKind string `json:"error_kind,omitempty"`
+	}{err.Error(), errorKindNames[ErrorKind(err)]})
+}
@@ -0,0 +1,200 @@
+package proofs
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// postForm posts a multipart form of fields and files to url.
+func postForm(t *testing.T, url string, fields map[string]string, files map[string][]byte) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		form.WriteField(name, value)
+	}
+	for name, data := range files {
+		part, err := form.CreateFormFile(name, name)
+		if err != nil {
+			t.Fatalf("CreateFormFile: %v", err)
+		}
+		part.Write(data)
+	}
+	form.Close()
+	resp, err := http.Post(url, form.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	return resp
+}
+
+func decodeBody(t *testing.T, resp *http.Response, v any) {
+	t.Helper()
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+}
+
+func TestProofServer(t *testing.T) {
+	vcf := []byte(`##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	server := httptest.NewServer((&ProofServer{Dir: t.TempDir()}).Handler())
+	defer server.Close()
+
+	resp := postForm(t, server.URL+"/proofs",
+		map[string]string{"type": "zygosity", "position": "15:28365618", "nonce": "challenge"},
+		map[string][]byte{"vcf": vcf})
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST /proofs status = %d", resp.StatusCode)
+	}
+	var record ProofRecord
+	decodeBody(t, resp, &record)
+	if record.Status != ProofPending || resp.Header.Get("Location") != "/proofs/"+record.ID {
+		t.Fatalf("POST /proofs = %+v, Location %q", record, resp.Header.Get("Location"))
+	}
+
+	deadline := time.Now().Add(2 * time.Minute)
+	for record.Status == ProofPending {
+		if time.Now().After(deadline) {
+			t.Fatalf("proof %s still pending", record.ID)
+		}
+		time.Sleep(100 * time.Millisecond)
+		resp, err := http.Get(server.URL + "/proofs/" + record.ID)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		decodeBody(t, resp, &record)
+	}
+	if record.Status != ProofDone || len(record.Proof) == 0 || len(record.VerifyingKey) == 0 || record.Metadata == nil {
+		t.Fatalf("proof = %s %s", record.Status, record.Error)
+	}
+	if p := record.Progress; p == nil || p.Stage != StageProve || !p.Done {
+		t.Errorf("progress of the done proof = %+v", p)
+	}
+
+	// The stored proof verifies by ID, and an uploaded copy with its key.
+	var result VerifyResult
+	decodeBody(t, postForm(t, server.URL+"/verify",
+		map[string]string{"type": "zygosity", "position": "15:28365618", "nonce": "challenge", "id": record.ID}, nil), &result)
+	if !result.Verified || len(result.PublicInputs) == 0 {
+		t.Errorf("verify by ID = %+v", result)
+	}
+	decodeBody(t, postForm(t, server.URL+"/verify",
+		map[string]string{"type": "zygosity", "position": "15:28365618", "nonce": "stale"},
+		map[string][]byte{"proof": record.Proof, "verifying_key": record.VerifyingKey}), &result)
+	if result.Verified || result.ErrorKind != "verification" {
+		t.Errorf("verify with a stale nonce = %+v", result)
+	}
+
+	// A proof the VCF does not support fails with its error kind.
+	resp = postForm(t, server.URL+"/proofs",
+		map[string]string{"type": "chromosome", "target": "7"},
+		map[string][]byte{"vcf": vcf})
+	record = ProofRecord{}
+	decodeBody(t, resp, &record)
+	for record.Status == ProofPending {
+		time.Sleep(50 * time.Millisecond)
+		resp, err := http.Get(server.URL + "/proofs/" + record.ID)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		decodeBody(t, resp, &record)
+	}
+	if record.Status != ProofFailed || record.ErrorKind != "not_found" {
+		t.Errorf("proof of an absent chromosome = %s %s %s", record.Status, record.ErrorKind, record.Error)
+	}
+
+	if resp := postForm(t, server.URL+"/proofs", map[string]string{"type": "nope"}, map[string][]byte{"vcf": vcf}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown type status = %d", resp.StatusCode)
+	}
+	if resp, _ := http.Get(server.URL + "/proofs/../etc"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("bad ID status = %d", resp.StatusCode)
+	}
+}
+
+func TestProofServerCancel(t *testing.T) {
+	vcf := []byte(`##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	srv := &ProofServer{Dir: t.TempDir()}
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	// Hold the only worker slot so the proof stays queued.
+	srv.slots <- struct{}{}
+
+	var record ProofRecord
+	decodeBody(t, postForm(t, server.URL+"/proofs",
+		map[string]string{"type": "zygosity", "position": "15:28365618"},
+		map[string][]byte{"vcf": vcf}), &record)
+
+	del := func(id string) *http.Response {
+		req, _ := http.NewRequest(http.MethodDelete, server.URL+"/proofs/"+id, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("DELETE: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	if resp := del(record.ID); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("DELETE status = %d", resp.StatusCode)
+	}
+	for record.Status == ProofPending {
+		time.Sleep(20 * time.Millisecond)
+		resp, err := http.Get(server.URL + "/proofs/" + record.ID)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		decodeBody(t, resp, &record)
+	}
+	if record.Status != ProofFailed || !strings.Contains(record.Error, "cancelled") {
+		t.Errorf("cancelled proof = %s %s", record.Status, record.Error)
+	}
+	if resp := del(record.ID); resp.StatusCode != http.StatusConflict {
+		t.Errorf("DELETE of a failed proof status = %d", resp.StatusCode)
+	}
+	if resp := del("0123456789abcdef0123456789abcdef"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("DELETE of an unknown proof status = %d", resp.StatusCode)
+	}
+	<-srv.slots
+
+	// A proof running past the server's timeout fails.
+	srv.Timeout = time.Nanosecond
+	decodeBody(t, postForm(t, server.URL+"/proofs",
+		map[string]string{"type": "zygosity", "position": "15:28365618"},
+		map[string][]byte{"vcf": vcf}), &record)
+	for record.Status == ProofPending {
+		time.Sleep(20 * time.Millisecond)
+		resp, err := http.Get(server.URL + "/proofs/" + record.ID)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		decodeBody(t, resp, &record)
+	}
+	if record.Status != ProofFailed || !strings.Contains(record.Error, "longer than") {
+		t.Errorf("timed out proof = %s %s", record.Status, record.Error)
+	}
+
+	// The record of a finished proof is dropped once kept long enough.
+	srv.KeepFinished = time.Nanosecond
+	resp, err := http.Get(server.URL + "/proofs/" + record.ID)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET of an evicted failed proof status = %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,204 @@
+package proofs
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrNoCircuit is returned by SetupKeys and Benchmark for proofs without a
+// circuit of their own to set up ahead of proving, such as those built from
+// other proofs.
+var ErrNoCircuit = errors.New("no circuit to set up ahead of proving")
+
+// SetupOptions shapes the envelope of the circuit whose keys SetupKeys
+// sets up. Proofs generated with a holder secret, a nonce, a timestamp or
+// a validity period carry them as public inputs, so they need keys set up
+// with the matching option; the values themselves are only known when
+// proving. Expiry implies Timestamp. Force lets SetupKeys replace existing
+// keys, which invalidates the proofs already issued against them.
+type SetupOptions struct {
+	Nullifier bool
+	Nonce     bool
+	Timestamp bool
+	Expiry    bool
+	Force     bool
+}
+
+// SetupKeys compiles the circuit p proves, as its configuration and
+// settings shape it, and runs its backend's setup without reading a VCF.
+// The keys are written to keyPath with .pk and .vk appended; Generate then
+// proves with the .pk file as its proving key, and the .vk file verifies
+// the proofs. A description of the circuit is written beside them with
+// KeyInfoSuffix appended. The settings' key cache is used as Generate uses it.
+func SetupKeys(p Proof, keyPath string, opts SetupOptions) error {
+	proofType, circuit, err := proofCircuit(p)
+	if err != nil {
+		return err
+	}
+
+	// The envelope depends on whether a secret, a nonce and times are set,
+	// not on their values.
+	s := *proofSettings(p)
+	if opts.Nullifier {
+		s.HolderSecret = big.NewInt(1)
+	}
+	if opts.Nonce {
+		s.Nonce = "setup"
+	}
+	if opts.Timestamp {
+		s.Timestamp = true
+	}
+	if opts.Expiry {
+		s.ValidFor = time.Hour
+	}
+	if err := s.checkCurve(proofType); err != nil {
+		return err
+	}
+	if !opts.Force {
+		if err := refuseOverwrite(keyPath+".pk", keyPath+".vk"); err != nil {
+			return err
+		}
+	}
+	if s.enveloped() {
+		circuit = newEnvelopeCircuit(circuit, proofType, s)
+	}
+
+	backend, err := s.Backend.backend()
+	if err != nil {
+		return err
+	}
+	cs, err := s.compileCircuit(backend, circuit)
+	if err != nil {
+		return err
+	}
+	pk, vk, err := s.setupKeys(backend, cs)
+	if err != nil {
+		return err
+	}
+	if err := writeKeyPair(keyPath, pk, vk); err != nil {
+		return err
+	}
+
+	circuitID, err := circuitHash(cs)
+	if err != nil {
+		return err
+	}
+	info := KeyInfo{
+		ProofType:   proofType,
+		Backend:     s.Backend,
+		Curve:       s.Curve,
+		CircuitHash: hex.EncodeToString(circuitID),
+		Constraints: cs.GetNbConstraints(),
+		Nullifier:   opts.Nullifier,
+		Nonce:       opts.Nonce,
+		DigestBound: s.Digest == DigestPublic,
+		Timestamp:   s.timestamped(),
+		Expiry:      s.ValidFor > 0,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if info.Backend == "" {
+		info.Backend = BackendGroth16
+	}
+	if info.Curve == "" {
+		info.Curve = CurveBN254
+	}
+	return writeKeyInfo(keyPath, info)
+}
+
+// proofSettings returns the settings embedded in p, or settings of its
+// own for a registered proof type that embeds none.
+func proofSettings(p Proof) *Settings {
+	if s, ok := p.(interface{ settings() *Settings }); ok {
+		return s.settings()
+	}
+	return &Settings{}
+}
+
+func (s *Settings) settings() *Settings {
+	return s
+}
+
+// proofCircuit returns the proof type and circuit definition Generate
+// proves for p, which p's configuration fixes before any VCF is read.
+func proofCircuit(p Proof) (string, frontend.Circuit, error) {
+	switch p := p.(type) {
+	case *ChromosomeProof:
+		maxVariants := p.MaxVariants
+		if maxVariants == 0 {
+			maxVariants = DefaultMaxVariants
+		}
+		return "chromosome", NewChromosomeCircuit(maxVariants), nil
+	case *EyeColorProof:
+		return "eyecolor", NewCompositeTraitCircuit(len(EyeColorTrait.SNPs)), nil
+	case *BloodTypeProof:
+		return "bloodtype", NewCompositeTraitCircuit(len(BloodTypeTrait.SNPs)), nil
+	case *TraitProof:
+		return p.Definition.Name, NewCompositeTraitCircuit(len(p.Definition.Trait.SNPs)), nil
+	case *IrisPlexProof:
+		return "irisplex", &IrisPlexCircuit{}, nil
+	case *BRCA1Proof:
+		return "brca1", &BRCA1Circuit{}, nil
+	case *RepeatExpansionProof:
+		return "repeat", &RepeatExpansionCircuit{}, nil
+	case *StarAlleleProof:
+		gene, ok := Pharmacogenes[strings.ToUpper(p.Gene)]
+		if !ok {
+			return "", nil, fmt.Errorf("unsupported pharmacogene: %s. Supported genes: CYP2C19, CYP2C9, TPMT", p.Gene)
+		}
+		return "diplotype", &DiplotypeCircuit{Gene: gene.Name}, nil
+	case *CompoundHetProof:
+		gene := strings.ToUpper(p.Gene)
+		if _, ok := PathogenicVariants[gene]; !ok {
+			return "", nil, fmt.Errorf("no pathogenic variant list for gene: %s. Supported genes: CFTR", p.Gene)
+		}
+		return "compoundhet", &CompoundHetCircuit{Gene: gene}, nil
+	case *SomaticProof:
+		return "somatic", &SomaticCircuit{}, nil
+	case *CarrierProof:
+		return "carrier", &CarrierCircuit{}, nil
+	case *VariantMembershipProof:
+		return "membership", NewVariantMembershipCircuit(p.treeDepth(), p.Hash), nil
+	case *VariantAbsenceProof:
+		return "absence", NewVariantAbsenceCircuit(orDefaultDepth(p.TreeDepth), p.Hash), nil
+	case *RegionProof:
+		return "region", NewRegionCircuit(orDefaultDepth(p.TreeDepth), p.Hash), nil
+	case *HaplotypeProof:
+		return "haplotype", NewHaplotypeMembershipCircuit(p.treeDepth()), nil
+	case *StructuralVariantProof:
+		return "sv", &StructuralVariantCircuit{}, nil
+	case *ZygosityProof:
+		return "zygosity", &ZygosityCircuit{}, nil
+	case *WildTypeProof:
+		return "wildtype", &WildTypeCircuit{}, nil
+	case *DosageProof:
+		if len(p.Panel) == 0 {
+			return "", nil, fmt.Errorf("a SNP panel is required")
+		}
+		return "dosage", NewDosageCircuit(p.Panel), nil
+	case *ThresholdProof:
+		if len(p.Panel) == 0 {
+			return "", nil, fmt.Errorf("a variant panel is required")
+		}
+		return "threshold", NewThresholdCircuit(p.Panel), nil
+	case *PanelProof:
+		if len(p.Panel) == 0 {
+			return "", nil, fmt.Errorf("a reference panel is required")
+		}
+		return "panel", NewPanelConsistencyCircuit(len(p.Panel), p.treeDepth(), p.Hash), nil
+	default:
+		return "", nil, fmt.Errorf("%T has %w", p, ErrNoCircuit)
+	}
+}
+
+func orDefaultDepth(depth int) int {
+	if depth == 0 {
+		return DefaultTreeDepth
+	}
+	return depth
+}
@@ -0,0 +1,53 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSetupKeys(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+16	89919709	.	C	T	60	PASS	.	GT	0/1
+`)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name  string
+		proof Proof
+		opts  SetupOptions
+	}{
+		{"zygosity", &ZygosityProof{Locus: "15:28365618"}, SetupOptions{}},
+		{"zygosity with nonce", &ZygosityProof{Settings: Settings{Nonce: "challenge-1"}, Locus: "15:28365618"}, SetupOptions{Nonce: true}},
+		{"eyecolor", &EyeColorProof{}, SetupOptions{}},
+	}
+	for i, tt := range tests {
+		keys := filepath.Join(dir, tt.name)
+		if err := SetupKeys(tt.proof, keys, tt.opts); err != nil {
+			t.Fatalf("%s: SetupKeys: %v", tt.name, err)
+		}
+		out := filepath.Join(dir, filepath.Base(keys)+"_proof.bin")
+		if err := tt.proof.Generate(t.Context(), vcf, keys+".pk", out); err != nil {
+			t.Fatalf("%s: Generate: %v", tt.name, err)
+		}
+		if ok, err := tt.proof.Verify(t.Context(), keys+".vk", out); err != nil || !ok {
+			t.Errorf("%s: Verify with the setup key = %v, %v", tt.name, ok, err)
+		}
+		if i == 0 {
+			// Keys set up without a nonce do not fit a proof carrying one.
+			nonced := &ZygosityProof{Settings: Settings{Nonce: "challenge-2"}, Locus: "15:28365618"}
+			if err := nonced.Generate(t.Context(), vcf, keys+".pk", filepath.Join(dir, "nonced.bin")); err == nil {
+				t.Errorf("%s: proved a nonce with keys set up without one", tt.name)
+			}
+		}
+	}
+
+	if err := SetupKeys(&CohortProof{}, filepath.Join(dir, "cohort"), SetupOptions{}); err == nil {
+		t.Error("SetupKeys accepted a cohort proof, whose circuit depends on its inputs")
+	}
+	if err := SetupKeys(&DosageProof{}, filepath.Join(dir, "dosage"), SetupOptions{}); err == nil {
+		t.Error("SetupKeys accepted a dosage proof without a panel")
+	}
+}
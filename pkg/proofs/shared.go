@@ -0,0 +1,129 @@
+package proofs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ShareExtraction makes the proofs created from cfg read the genotypes
+// they need in a single pass over each VCF rather than one pass per proof.
+// The first proof to read a VCF reads the calls at every variant a proof
+// configured by cfg may ask for: those of the built-in proof types, the
+// panel, the position and the variants. Proofs asking for others, and
+// proofs reading records another way, such as variant trees, still read
+// the VCF themselves. A region being read turns sharing off, as the
+// built-in variants lie outside it.
+func (cfg *ProofConfig) ShareExtraction() {
+	if cfg.Settings.Region != nil {
+		return
+	}
+	specs := slices.Concat(BuiltinVariants(), cfg.Panel)
+	if locus, err := ParseLocus(cfg.Position); err == nil {
+		specs = append(specs, locusSpecs(locus)...)
+	}
+	for _, v := range []string{cfg.Variant, cfg.SecondVariant} {
+		if spec, err := ParseVariantSpec(v); err == nil {
+			specs = append(specs, spec)
+		}
+	}
+	cfg.Settings.shared = newSharedExtraction(specs)
+}
+
+// sharedExtraction holds the calls at a fixed set of variants, read once
+// per VCF.
+type sharedExtraction struct {
+	specs   []VariantSpec
+	indexes map[string]int
+
+	mu   sync.Mutex
+	vcfs map[string]*sharedCalls
+}
+
+// sharedCalls are the calls read from one VCF with the given read options.
+type sharedCalls struct {
+	options readOptions
+	once    sync.Once
+	calls   []*Genotype
+	err     error
+}
+
+// readOptions are the settings that change which call readCalls finds for
+// a variant.
+type readOptions struct {
+	sample     string
+	imputation ImputationPolicy
+	filter     CallFilter
+	index      string
+	limits     ReadLimits
+	liftover   *Liftover
+}
+
+func newSharedExtraction(specs []VariantSpec) *sharedExtraction {
+	x := &sharedExtraction{indexes: make(map[string]int), vcfs: make(map[string]*sharedCalls)}
+	for _, spec := range specs {
+		key := sharedKey(spec)
+		if _, ok := x.indexes[key]; !ok {
+			x.indexes[key] = len(x.specs)
+			x.specs = append(x.specs, spec)
+		}
+	}
+	return x
+}
+
+// sharedKey identifies a requested variant by everything readCalls finds
+// its record by.
+func sharedKey(spec VariantSpec) string {
+	spec.Chromosome = normalizeChromosome(spec.Chromosome)
+	return fmt.Sprintf("%s/%s", spec, strings.ToLower(spec.RSID))
+}
+
+// calls returns the shared calls at specs in the VCF at vcfPath, reading
+// the VCF if no proof has yet. It reports false, for the caller to read the
+// VCF itself, when x is nil, any of specs was not shared, the VCF was read
+// with other options or the shared read failed.
+func (x *sharedExtraction) calls(vcfPath string, s Settings, sampleName string, specs []VariantSpec) ([]*Genotype, bool) {
+	if x == nil || s.Region != nil {
+		return nil, false
+	}
+	indexes := make([]int, len(specs))
+	for i, spec := range specs {
+		index, ok := x.indexes[sharedKey(spec)]
+		if !ok {
+			return nil, false
+		}
+		indexes[i] = index
+	}
+
+	options := readOptions{
+		sample:     sampleName,
+		imputation: s.Imputation,
+		filter:     s.Filter,
+		index:      s.Index,
+		limits:     s.Limits,
+		liftover:   s.Liftover,
+	}
+	x.mu.Lock()
+	shared, ok := x.vcfs[vcfPath]
+	if !ok {
+		shared = &sharedCalls{options: options}
+		x.vcfs[vcfPath] = shared
+	}
+	x.mu.Unlock()
+	if shared.options != options {
+		return nil, false
+	}
+
+	shared.once.Do(func() {
+		shared.calls, shared.err = readCalls(vcfPath, s, sampleName, x.specs)
+	})
+	if shared.err != nil {
+		return nil, false
+	}
+	calls := make([]*Genotype, len(specs))
+	for i, index := range indexes {
+		calls[i] = shared.calls[index]
+	}
+	return calls, true
+}
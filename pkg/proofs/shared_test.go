@@ -0,0 +1,57 @@
+package proofs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestShareExtraction(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+1	25648478	rs590787	C	T	60	PASS	.	GT	0/1
+5	33951693	rs16891982	C	G	60	PASS	.	GT	1/1
+9	136131322	rs8176746	G	T	60	PASS	.	GT	0/0
+15	28365618	rs12913832	A	G	60	PASS	.	GT	1|0
+`)
+	cfg := ProofConfig{Position: "15:28365618"}
+	cfg.ShareExtraction()
+
+	eyes, err := extractGenotypes(vcf, cfg.Settings, EyeColorTrait.SNPs)
+	if err != nil {
+		t.Fatalf("extractGenotypes: %v", err)
+	}
+	if g := eyes[Locus{"5", 33951693}]; g.Carries("G") != 2 {
+		t.Errorf("rs16891982 = %+v, want G/G", g)
+	}
+
+	// Later proofs are served from the first read, so emptying the VCF does
+	// not change what they see.
+	if err := os.WriteFile(vcf, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	blood, err := extractGenotypes(vcf, cfg.Settings, BloodTypeTrait.SNPs)
+	if err != nil {
+		t.Fatalf("extractGenotypes from the shared read: %v", err)
+	}
+	if g, ok := blood[Locus{"1", 25648478}]; !ok || g.Carries("T") != 1 {
+		t.Errorf("rs590787 = %+v, %v; want C/T", g, ok)
+	}
+	if _, ok := blood[Locus{"9", 136132908}]; ok {
+		t.Errorf("rs8176719 has no record but was found")
+	}
+	zygosity, err := extractGenotypes(vcf, cfg.Settings, locusSpecs(Locus{"15", 28365618}))
+	if err != nil || len(zygosity) != 1 {
+		t.Errorf("extractGenotypes at the -position locus = %v, %v", zygosity, err)
+	}
+
+	// Variants outside the shared set, and other VCFs, are read as usual.
+	if _, err := extractGenotypes(vcf, cfg.Settings, locusSpecs(Locus{"2", 100})); err == nil {
+		t.Errorf("variant outside the shared set should be read from the emptied VCF and fail")
+	}
+	other := filepath.Join(t.TempDir(), "missing.vcf")
+	if _, err := extractGenotypes(other, cfg.Settings, EyeColorTrait.SNPs); err == nil {
+		t.Errorf("another VCF should be read on its own")
+	}
+}
@@ -0,0 +1,98 @@
+package proofs
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"io"
+)
+
+// holderSignatureDomain prefixes the message a holder signs, so a proof
+// signature cannot be passed off as a signature over anything else.
+const holderSignatureDomain = "vcf-proof holder signature v1\n"
+
+// HolderSignature is the holder's Ed25519 signature over a proof file,
+// binding the proof, its public inputs and circuit to the holder's key.
+// DID, when set, is the did:key or did:web identifier of the holder, whose
+// document lists the key; see DIDResolver.CheckSigner.
+type HolderSignature struct {
+	PublicKey []byte `json:"public_key"`
+	Signature []byte `json:"signature"`
+	DID       string `json:"did,omitempty"`
+}
+
+// holderSigningBytes returns the message a holder signs for the proof at
+// proofPath: the domain followed by the SHA-256 of the proof file.
+func holderSigningBytes(proofPath string) ([]byte, error) {
+	f, err := openFile(proofPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening proof file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, fmt.Errorf("hashing proof file: %w", err)
+	}
+	return h.Sum([]byte(holderSignatureDomain)), nil
+}
+
+// signProof signs the proof at proofPath with the holder's key, naming the
+// holder by did, or by the did:key of the key when did is empty.
+func signProof(proofPath string, key ed25519.PrivateKey, did string) (*HolderSignature, error) {
+	msg, err := holderSigningBytes(proofPath)
+	if err != nil {
+		return nil, err
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	if did == "" {
+		did = DIDKey(pub)
+	}
+	return &HolderSignature{
+		PublicKey: pub,
+		Signature: ed25519.Sign(key, msg),
+		DID:       did,
+	}, nil
+}
+
+// ProofSigner returns the holder key the proof at proofPath was signed
+// with, and false when its metadata carries no holder signature. The
+// signature is checked against the proof file.
+func ProofSigner(proofPath string) (ed25519.PublicKey, bool, error) {
+	meta, err := ReadMetadata(proofPath)
+	if err != nil {
+		return nil, false, err
+	}
+	sig := meta.HolderSignature
+	if sig == nil {
+		return nil, false, nil
+	}
+	if len(sig.PublicKey) != ed25519.PublicKeySize {
+		return nil, false, withKind(ErrVerification, fmt.Errorf("holder public key has invalid length %d", len(sig.PublicKey)))
+	}
+
+	msg, err := holderSigningBytes(proofPath)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(sig.PublicKey), msg, sig.Signature) {
+		return nil, false, withKind(ErrVerification, fmt.Errorf("holder signature does not match the proof"))
+	}
+	return ed25519.PublicKey(sig.PublicKey), true, nil
+}
+
+// CheckHolderSignature checks that the proof at proofPath was signed by
+// the holder whose public key is key.
+func CheckHolderSignature(proofPath string, key ed25519.PublicKey) error {
+	signer, signed, err := ProofSigner(proofPath)
+	if err != nil {
+		return err
+	}
+	if !signed {
+		return withKind(ErrVerification, fmt.Errorf("proof is not signed by its holder"))
+	}
+	if !signer.Equal(key) {
+		return withKind(ErrVerification, fmt.Errorf("proof was signed by another holder key"))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package proofs
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHolderSignature(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	dir := t.TempDir()
+
+	signed := filepath.Join(dir, "signed.bin")
+	if err := (&ZygosityProof{Settings: Settings{SignKey: key}, Locus: "15:28365618"}).Generate(t.Context(), vcf, "", signed); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	signer, ok, err := ProofSigner(signed)
+	if err != nil || !ok || !signer.Equal(pub) {
+		t.Fatalf("ProofSigner = %x, %v, %v; want %x", signer, ok, err, pub)
+	}
+	if err := CheckHolderSignature(signed, pub); err != nil {
+		t.Errorf("CheckHolderSignature with the holder's key: %v", err)
+	}
+	if err := CheckHolderSignature(signed, otherPub); err == nil {
+		t.Errorf("CheckHolderSignature accepted another holder's key")
+	}
+
+	unsigned := filepath.Join(dir, "unsigned.bin")
+	if err := (&ZygosityProof{Locus: "15:28365618"}).Generate(t.Context(), vcf, "", unsigned); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, ok, err := ProofSigner(unsigned); ok || err != nil {
+		t.Errorf("ProofSigner on an unsigned proof = %v, %v", ok, err)
+	}
+	if err := CheckHolderSignature(unsigned, pub); err == nil {
+		t.Errorf("CheckHolderSignature accepted an unsigned proof")
+	}
+
+	// The signature covers the proof file, so another proof cannot borrow
+	// it.
+	other, err := os.ReadFile(unsigned)
+	if err != nil {
+		t.Fatalf("reading proof: %v", err)
+	}
+	if err := os.WriteFile(signed, other, 0644); err != nil {
+		t.Fatalf("writing proof: %v", err)
+	}
+	if _, _, err := ProofSigner(signed); err == nil {
+		t.Errorf("ProofSigner accepted a signature over another proof")
+	}
+}
@@ -0,0 +1,209 @@
+package proofs
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/solidity"
+	"golang.org/x/crypto/sha3"
+)
+
+// CalldataSuffix is appended to a proof path to name its Solidity calldata
+// file.
+const CalldataSuffix = ".calldata.json"
+
+// SolidityCalldata is a proof formatted as arguments to the verifier
+// contract exported for its verifying key. Values are 0x-prefixed hex.
+type SolidityCalldata struct {
+	Backend BackendName `json:"backend"`
+	// Proof is verifyProof's uint256[8] proof argument for Groth16, or a
+	// single element holding Verify's bytes proof argument for PLONK.
+	Proof []string `json:"proof"`
+	// Inputs are the public inputs in circuit order, including any
+	// nullifier and nonce.
+	Inputs []string `json:"inputs"`
+}
+
+// solidityExporter is implemented by BN254 verifying keys of both backends.
+type solidityExporter interface {
+	ExportSolidity(w io.Writer, opts ...solidity.ExportOption) error
+}
+
+// ExportSolidityVerifier writes a Solidity contract that verifies proofs
+// made with the verifying key at verifyingKeyPath to contractPath, and
+// returns the proof at proofPath as calldata for it. The proof is verified
+// first. Only proofs over BN254, the curve with EVM pairing precompiles,
+// can be exported.
+func ExportSolidityVerifier(verifyingKeyPath string, proofPath string, contractPath string) (*SolidityCalldata, error) {
+	name, curve := proofSystem(proofPath)
+	if curve != CurveBN254 {
+		return nil, fmt.Errorf("proof is over %s; Solidity verifiers need proofs over %s", curve, CurveBN254)
+	}
+	if _, err := (Settings{}).verifyProof(context.Background(), verifyingKeyPath, proofPath); err != nil {
+		return nil, err
+	}
+
+	backend, err := name.backend()
+	if err != nil {
+		return nil, err
+	}
+	vk, err := readVerifyingKey(verifyingKeyPath, backend, curve)
+	if err != nil {
+		return nil, err
+	}
+	// The exported Groth16 verifier hashes commitments with Keccak-256,
+	// which proofs made with the default hash-to-field would not verify
+	// against.
+	if groth16Key, ok := vk.(*groth16_bn254.VerifyingKey); ok && len(groth16Key.CommitmentKeys) > 0 {
+		return nil, fmt.Errorf("circuits with commitments cannot be exported to Solidity")
+	}
+	exporter, ok := vk.(solidityExporter)
+	if !ok {
+		return nil, fmt.Errorf("%s verifying key cannot be exported to Solidity", name)
+	}
+
+	contractFile, err := os.Create(contractPath)
+	if err != nil {
+		return nil, fmt.Errorf("creating contract file: %w", err)
+	}
+	defer contractFile.Close()
+
+	if err := exporter.ExportSolidity(contractFile); err != nil {
+		return nil, fmt.Errorf("exporting Solidity verifier: %w", err)
+	}
+
+	return solidityCalldata(name, proofPath)
+}
+
+// solidityProof returns the proof at proofPath as backend's exported
+// verifier takes it, and its public witness values.
+func solidityProof(backend BackendName, proofPath string) ([]byte, []*big.Int, error) {
+	proof, _, err := readProofFile(proofPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	marshaler, ok := proof.(interface{ MarshalSolidity() []byte })
+	if !ok {
+		return nil, nil, fmt.Errorf("%s proof cannot be formatted for Solidity", backend)
+	}
+	inputs, err := readWitnessValues(proofPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return marshaler.MarshalSolidity(), inputs, nil
+}
+
+// solidityCalldata formats the proof at proofPath for backend's exported
+// verifier.
+func solidityCalldata(backend BackendName, proofPath string) (*SolidityCalldata, error) {
+	raw, inputs, err := solidityProof(backend, proofPath)
+	if err != nil {
+		return nil, err
+	}
+
+	calldata := &SolidityCalldata{Backend: backend}
+	if backend == BackendPlonk {
+		calldata.Proof = []string{"0x" + hex.EncodeToString(raw)}
+	} else {
+		for len(raw) > 0 {
+			calldata.Proof = append(calldata.Proof, "0x"+hex.EncodeToString(raw[:32]))
+			raw = raw[32:]
+		}
+	}
+
+	for _, v := range inputs {
+		calldata.Inputs = append(calldata.Inputs, fmt.Sprintf("0x%064x", v))
+	}
+	return calldata, nil
+}
+
+// ProofCalldata returns the proof at proofPath as calldata for the
+// verifier contract ExportSolidityVerifier exports for it, without its
+// verifying key and without verifying it. Only proofs over BN254 can be
+// exported.
+func ProofCalldata(proofPath string) (*SolidityCalldata, error) {
+	name, curve := proofSystem(proofPath)
+	if curve != CurveBN254 {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("proof is over %s; EVM verifiers need proofs over %s", curve, CurveBN254))
+	}
+	return solidityCalldata(name, proofPath)
+}
+
+// groth16ProofWords is the length in 32-byte words of a Groth16 proof
+// without commitments, as the exported verifier's uint256[8] takes it.
+const groth16ProofWords = 8
+
+// EVMCalldata returns the calldata of a call to the verifier contract
+// ExportSolidityVerifier exports for the proof at proofPath: the selector
+// of its verification function followed by the ABI-encoded proof and
+// public inputs, ready to send to the contract in a transaction or
+// eth_call. Unlike ExportSolidityVerifier, it needs no verifying key and
+// does not verify the proof. Only proofs over BN254 can be exported.
+func EVMCalldata(proofPath string) ([]byte, error) {
+	name, curve := proofSystem(proofPath)
+	if curve != CurveBN254 {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("proof is over %s; EVM verifiers need proofs over %s", curve, CurveBN254))
+	}
+	raw, inputs, err := solidityProof(name, proofPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var calldata []byte
+	if name == BackendPlonk {
+		// Verify(bytes proof, uint256[] public_inputs): both arguments are
+		// dynamic, so the head holds their offsets and the tail their
+		// lengths and contents, the proof padded to a whole word.
+		calldata = append(calldata, abiSelector("Verify(bytes,uint256[])")...)
+		padded := (len(raw) + 31) / 32 * 32
+		calldata = append(calldata, abiWord(big.NewInt(64))...)
+		calldata = append(calldata, abiWord(big.NewInt(int64(64+32+padded)))...)
+		calldata = append(calldata, abiWord(big.NewInt(int64(len(raw))))...)
+		calldata = append(calldata, raw...)
+		calldata = append(calldata, make([]byte, padded-len(raw))...)
+		calldata = append(calldata, abiWord(big.NewInt(int64(len(inputs))))...)
+	} else {
+		// verifyProof(uint256[8] proof, uint256[n] input): fixed-size
+		// arrays are encoded in place.
+		if len(raw) != groth16ProofWords*32 {
+			return nil, fmt.Errorf("circuits with commitments cannot be exported to Solidity")
+		}
+		calldata = append(calldata, abiSelector(fmt.Sprintf("verifyProof(uint256[%d],uint256[%d])", groth16ProofWords, len(inputs)))...)
+		calldata = append(calldata, raw...)
+	}
+	for _, v := range inputs {
+		calldata = append(calldata, abiWord(v)...)
+	}
+	return calldata, nil
+}
+
+// abiSelector returns the 4-byte selector of the Solidity function with
+// canonical signature sig.
+func abiSelector(sig string) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(sig))
+	return h.Sum(nil)[:4]
+}
+
+// abiWord encodes v as a big-endian 32-byte ABI word.
+func abiWord(v *big.Int) []byte {
+	return v.FillBytes(make([]byte, 32))
+}
+
+// WriteCalldata saves c to path as JSON.
+func WriteCalldata(path string, c *SolidityCalldata) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding calldata: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing calldata: %w", err)
+	}
+	return nil
+}
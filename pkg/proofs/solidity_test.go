@@ -0,0 +1,95 @@
+package proofs
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportSolidityVerifier(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`)
+	dir := t.TempDir()
+	srs := filepath.Join(dir, "bn254.srs")
+	if err := GenerateSRS(srs, CurveBN254, 1<<12); err != nil {
+		t.Fatalf("GenerateSRS: %v", err)
+	}
+
+	tests := []struct {
+		settings Settings
+		function string
+		words    int
+		selector string
+	}{
+		// keccak256("verifyProof(uint256[8],uint256[5])") and
+		// keccak256("Verify(bytes,uint256[])").
+		{Settings{Nonce: "challenge"}, "function verifyProof(", 8, "0x2a07d99a"},
+		{Settings{Backend: BackendPlonk, SRS: srs}, "function Verify(", 1, "0x7e4f7a8a"},
+	}
+	for _, tt := range tests {
+		out := filepath.Join(dir, tt.settings.Backend.String()+".bin")
+		if err := (&ZygosityProof{Settings: tt.settings, Locus: "15:28365618"}).Generate(t.Context(), vcf, "", out); err != nil {
+			t.Fatalf("%s: Generate: %v", tt.settings.Backend, err)
+		}
+
+		contract := out + ".sol"
+		calldata, err := ExportSolidityVerifier(out+".vk", out, contract)
+		if err != nil {
+			t.Fatalf("%s: ExportSolidityVerifier: %v", tt.settings.Backend, err)
+		}
+		source, err := os.ReadFile(contract)
+		if err != nil {
+			t.Fatalf("reading contract: %v", err)
+		}
+		if !strings.Contains(string(source), tt.function) {
+			t.Errorf("%s: contract has no %q", tt.settings.Backend, tt.function)
+		}
+		if len(calldata.Proof) != tt.words {
+			t.Errorf("%s: proof has %d calldata values, want %d", tt.settings.Backend, len(calldata.Proof), tt.words)
+		}
+
+		values, err := readWitnessValues(out)
+		if err != nil {
+			t.Fatalf("readWitnessValues: %v", err)
+		}
+		if len(calldata.Inputs) != len(values) || calldata.Inputs[0] != fmt.Sprintf("0x%064x", values[0]) {
+			t.Errorf("%s: calldata inputs %v do not match the public witness %v", tt.settings.Backend, calldata.Inputs, values)
+		}
+
+		encoded, err := EVMCalldata(out)
+		if err != nil {
+			t.Fatalf("%s: EVMCalldata: %v", tt.settings.Backend, err)
+		}
+		if got := "0x" + hex.EncodeToString(encoded[:4]); got != tt.selector {
+			t.Errorf("%s: selector %s, want %s", tt.settings.Backend, got, tt.selector)
+		}
+		inputs := hex.EncodeToString(encoded[len(encoded)-32*len(values):])
+		if want := strings.ReplaceAll(strings.Join(calldata.Inputs, ""), "0x", ""); inputs != want {
+			t.Errorf("%s: encoded inputs %s, want %s", tt.settings.Backend, inputs, want)
+		}
+		proof := strings.TrimPrefix(strings.Join(calldata.Proof, ""), "0x")
+		if tt.settings.Backend != BackendPlonk {
+			proof = strings.ReplaceAll(proof, "0x", "")
+		}
+		if !strings.Contains(hex.EncodeToString(encoded), proof) {
+			t.Errorf("%s: encoded calldata does not hold the proof", tt.settings.Backend)
+		}
+	}
+
+	out := filepath.Join(dir, "bls12-381.bin")
+	if err := (&ZygosityProof{Settings: Settings{Curve: CurveBLS12381}, Locus: "15:28365618"}).Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := ExportSolidityVerifier(out+".vk", out, out+".sol"); err == nil {
+		t.Errorf("proofs over bls12-381 should not export to Solidity")
+	}
+	if _, err := EVMCalldata(out); err == nil {
+		t.Errorf("proofs over bls12-381 should not export as EVM calldata")
+	}
+}
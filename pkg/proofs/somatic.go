@@ -0,0 +1,206 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// VariantOrigin is the public classification of a somatic proof.
+type VariantOrigin int
+
+const (
+	// OriginAbsent means the variant is not called in the tumor.
+	OriginAbsent VariantOrigin = iota
+	// OriginSomatic means the variant is called in the tumor only.
+	OriginSomatic
+	// OriginGermline means the variant is already called in the normal.
+	OriginGermline
+)
+
+func (o VariantOrigin) String() string {
+	switch o {
+	case OriginSomatic:
+		return "somatic"
+	case OriginGermline:
+		return "germline"
+	default:
+		return "absent"
+	}
+}
+
+// SomaticCircuit classifies a public variant as somatic or germline from the
+// private alt-allele dosages in a tumor and its matched normal. The tumor
+// call must meet public quality and depth minimums.
+type SomaticCircuit struct {
+	VariantID      frontend.Variable `gnark:",public"`
+	Classification frontend.Variable `gnark:",public"`
+	MinQuality     frontend.Variable `gnark:",public"`
+	MinDepth       frontend.Variable `gnark:",public"`
+
+	TumorDosage  frontend.Variable
+	NormalDosage frontend.Variable
+	TumorQuality frontend.Variable
+	TumorDepth   frontend.Variable
+}
+
+func (c *SomaticCircuit) Define(api frontend.API) error {
+	assertIsDosage(api, c.TumorDosage)
+	assertIsDosage(api, c.NormalDosage)
+	assertCallQuality(api, c.TumorQuality, c.TumorDepth, c.MinQuality, c.MinDepth)
+
+	inTumor := api.Sub(1, api.IsZero(c.TumorDosage))
+	inNormal := api.Sub(1, api.IsZero(c.NormalDosage))
+
+	// absent = 0, somatic = tumor only, germline = present in normal
+	somatic := api.Mul(inTumor, api.Sub(1, inNormal))
+	classification := api.Add(api.Mul(somatic, int(OriginSomatic)), api.Mul(inNormal, int(OriginGermline)))
+	api.AssertIsEqual(classification, c.Classification)
+	bindPublic(api, c.VariantID)
+
+	return nil
+}
+
+// assertIsDosage constrains v to a diploid alt-allele count in {0, 1, 2}.
+func assertIsDosage(api frontend.API, v frontend.Variable) {
+	api.AssertIsEqual(api.Mul(v, api.Sub(v, 1), api.Sub(v, 2)), 0)
+}
+
+// variantDosage returns how many copies of variant the sample carries, or 0
+// when the VCF has no call at its position.
+func variantDosage(genotypes map[Locus]Genotype, variant VariantSpec) (int, error) {
+	genotype, ok := genotypes[variant.Locus()]
+	if !ok {
+		return 0, nil
+	}
+	if genotype.Ref != variant.Ref {
+		return 0, fmt.Errorf("reference allele at %s is %s, expected %s", variant.Locus(), genotype.Ref, variant.Ref)
+	}
+
+	dosage := genotype.Carries(variant.Alt)
+	if dosage > 2 {
+		return 0, fmt.Errorf("genotype at %s is not diploid", variant.Locus())
+	}
+	return dosage, nil
+}
+
+func (p *SomaticProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+	if err := p.Quality.validate(); err != nil {
+		return err
+	}
+
+	variant, err := ParseVariantSpec(p.Variant)
+	if err != nil {
+		return err
+	}
+
+	// Without a separate normal VCF both samples come from one paired VCF,
+	// as written by tumor/normal callers such as Mutect2.
+	normalVCF := p.NormalVCF
+	if normalVCF == "" {
+		normalVCF = vcfPath
+		if p.TumorSample == "" || p.NormalSample == "" {
+			return fmt.Errorf("tumor and normal sample names are required when no normal VCF is given")
+		}
+	}
+
+	specs := []VariantSpec{variant}
+
+	fmt.Println("Reading tumor VCF...")
+	tumor, err := extractSampleGenotypes(vcfPath, p.Settings, p.TumorSample, specs)
+	if err != nil {
+		return fmt.Errorf("error reading tumor VCF: %w", err)
+	}
+
+	fmt.Println("Reading normal VCF...")
+	// The index, if any, is the tumor VCF's.
+	normalSettings := p.Settings
+	normalSettings.Index = ""
+	normal, err := extractSampleGenotypes(normalVCF, normalSettings, p.NormalSample, specs)
+	if err != nil {
+		return fmt.Errorf("error reading normal VCF: %w", err)
+	}
+
+	tumorDosage, err := variantDosage(tumor, variant)
+	if err != nil {
+		return fmt.Errorf("tumor: %w", err)
+	}
+	tumorCall := tumor[variant.Locus()]
+	if err := p.Quality.check(tumorCall); err != nil {
+		return fmt.Errorf("tumor call at %s: %w", variant.Locus(), err)
+	}
+	normalDosage, err := variantDosage(normal, variant)
+	if err != nil {
+		return fmt.Errorf("normal: %w", err)
+	}
+
+	origin := OriginAbsent
+	switch {
+	case normalDosage > 0:
+		origin = OriginGermline
+	case tumorDosage > 0:
+		origin = OriginSomatic
+	}
+
+	assignment := &SomaticCircuit{
+		VariantID:      variant.ID(),
+		Classification: int(origin),
+		MinQuality:     p.Quality.MinQuality,
+		MinDepth:       p.Quality.MinDepth,
+		TumorDosage:    tumorDosage,
+		NormalDosage:   normalDosage,
+		TumorQuality:   callQuality(tumorCall),
+		TumorDepth:     tumorCall.Depth,
+	}
+
+	if err := p.Settings.prove(ctx, "somatic", &SomaticCircuit{}, assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("somatic", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven that variant %s is %s\n", variant, origin)
+	fmt.Println("without revealing either the tumor or the normal genome.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *SomaticProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 4 {
+		return false, withKind(ErrVerification, fmt.Errorf("somatic proof has %d public inputs, expected 4", len(public)))
+	}
+	if p.Variant != "" {
+		variant, err := ParseVariantSpec(p.Variant)
+		if err != nil {
+			return false, withKind(ErrInvalidInput, err)
+		}
+		if public[0].Cmp(variant.ID()) != 0 {
+			return false, withKind(ErrVerification, fmt.Errorf("proof is for a different variant than %s", variant))
+		}
+		fmt.Printf("Variant: %s\n", variant)
+	}
+	fmt.Printf("Variant origin: %s\n", VariantOrigin(public[1].Int64()))
+	fmt.Printf("Tumor call quality at least %s, read depth at least %s\n", public[2], public[3])
+	if err := p.Quality.atLeast(public[2].Int64(), public[3].Int64()); err != nil {
+		return false, err
+	}
+
+	return verified, nil
+}
@@ -0,0 +1,31 @@
+package proofs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+const pairedSomaticVCF = `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=DP,Number=1,Type=Integer,Description="Read depth">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	TUMOR	NORMAL
+17	7577120	.	C	T	60	PASS	.	GT:DP	0/1:40	0/0:35
+`
+
+func TestSomaticProofBindsVariant(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "somatic_proof.bin")
+	proof := &SomaticProof{Variant: "17:7577120:C:T", TumorSample: "TUMOR", NormalSample: "NORMAL"}
+	if err := proof.Generate(t.Context(), writeTempVCF(t, pairedSomaticVCF), "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	assertInputBound(t, out, 0)
+
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); !ok || err != nil {
+		t.Errorf("Verify for the proof's variant = %v, %v", ok, err)
+	}
+	other := &SomaticProof{Variant: "17:7577121:C:T"}
+	if ok, err := other.Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+		t.Errorf("Verify for another variant = %v, %v; want ErrVerification", ok, err)
+	}
+}
@@ -0,0 +1,427 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// AlleleFunction is the CPIC functional status of a star allele.
+type AlleleFunction int
+
+const (
+	FunctionNone AlleleFunction = iota
+	FunctionDecreased
+	FunctionNormal
+	FunctionIncreased
+)
+
+// numAlleleFunctions is the number of AlleleFunction values, used to index the
+// diplotype phenotype table.
+const numAlleleFunctions = 4
+
+// MetabolizerPhenotype is the diplotype class exposed by a star-allele proof.
+// Zero is reserved so an unassigned public input never matches a class.
+type MetabolizerPhenotype int
+
+const (
+	PoorMetabolizer MetabolizerPhenotype = iota + 1
+	IntermediateMetabolizer
+	NormalMetabolizer
+	RapidMetabolizer
+	UltrarapidMetabolizer
+)
+
+func (m MetabolizerPhenotype) String() string {
+	switch m {
+	case PoorMetabolizer:
+		return "poor metabolizer"
+	case IntermediateMetabolizer:
+		return "intermediate metabolizer"
+	case NormalMetabolizer:
+		return "normal metabolizer"
+	case RapidMetabolizer:
+		return "rapid metabolizer"
+	case UltrarapidMetabolizer:
+		return "ultrarapid metabolizer"
+	default:
+		return "unknown"
+	}
+}
+
+// DefiningVariant is a SNP that defines one or more star alleles (GRCh37).
+type DefiningVariant struct {
+	RSID       string
+	Chromosome string
+	Position   uint64
+	Ref        string
+	Alt        string
+}
+
+// Spec returns the defining variant as a variant.
+func (v DefiningVariant) Spec() VariantSpec {
+	return VariantSpec{Chromosome: v.Chromosome, Position: v.Position, Ref: v.Ref, Alt: v.Alt, RSID: v.RSID}
+}
+
+// StarAllele is a haplotype defined by carrying exactly the listed defining
+// variants (indexes into Pharmacogene.Variants).
+type StarAllele struct {
+	Name     string
+	Variants []int
+	Function AlleleFunction
+}
+
+// Pharmacogene describes the star alleles of a gene and how pairs of allele
+// functions map to a metabolizer phenotype.
+type Pharmacogene struct {
+	Name     string
+	Variants []DefiningVariant
+	Alleles  []StarAllele
+	// Phenotype maps the functions of the two haplotypes to a phenotype.
+	Phenotype func(a, b AlleleFunction) MetabolizerPhenotype
+}
+
+// Pharmacogenes lists the genes supported by the star-allele caller. CYP2D6
+// is deliberately absent: its structural variants and hybrids cannot be
+// called from SNVs alone.
+var Pharmacogenes = map[string]Pharmacogene{
+	"CYP2C19": {
+		Name: "CYP2C19",
+		Variants: []DefiningVariant{
+			{RSID: "rs4244285", Chromosome: "10", Position: 96541616, Ref: "G", Alt: "A"},
+			{RSID: "rs4986893", Chromosome: "10", Position: 96540410, Ref: "G", Alt: "A"},
+			{RSID: "rs12248560", Chromosome: "10", Position: 96521657, Ref: "C", Alt: "T"},
+		},
+		Alleles: []StarAllele{
+			{Name: "*1", Function: FunctionNormal},
+			{Name: "*2", Variants: []int{0}, Function: FunctionNone},
+			{Name: "*3", Variants: []int{1}, Function: FunctionNone},
+			{Name: "*17", Variants: []int{2}, Function: FunctionIncreased},
+		},
+		Phenotype: cyp2c19Phenotype,
+	},
+	"CYP2C9": {
+		Name: "CYP2C9",
+		Variants: []DefiningVariant{
+			{RSID: "rs1799853", Chromosome: "10", Position: 96702047, Ref: "C", Alt: "T"},
+			{RSID: "rs1057910", Chromosome: "10", Position: 96741053, Ref: "A", Alt: "C"},
+		},
+		Alleles: []StarAllele{
+			{Name: "*1", Function: FunctionNormal},
+			{Name: "*2", Variants: []int{0}, Function: FunctionDecreased},
+			{Name: "*3", Variants: []int{1}, Function: FunctionNone},
+		},
+		Phenotype: activityScorePhenotype,
+	},
+	"TPMT": {
+		Name: "TPMT",
+		Variants: []DefiningVariant{
+			{RSID: "rs1800462", Chromosome: "6", Position: 18143955, Ref: "C", Alt: "G"},
+			{RSID: "rs1800460", Chromosome: "6", Position: 18139228, Ref: "C", Alt: "T"},
+			{RSID: "rs1142345", Chromosome: "6", Position: 18130918, Ref: "T", Alt: "C"},
+		},
+		Alleles: []StarAllele{
+			{Name: "*1", Function: FunctionNormal},
+			{Name: "*2", Variants: []int{0}, Function: FunctionNone},
+			{Name: "*3A", Variants: []int{1, 2}, Function: FunctionNone},
+			{Name: "*3B", Variants: []int{1}, Function: FunctionNone},
+			{Name: "*3C", Variants: []int{2}, Function: FunctionNone},
+		},
+		Phenotype: activityScorePhenotype,
+	},
+}
+
+// cyp2c19Phenotype follows the CPIC CYP2C19 diplotype-to-phenotype table.
+func cyp2c19Phenotype(a, b AlleleFunction) MetabolizerPhenotype {
+	switch {
+	case a == FunctionNone && b == FunctionNone:
+		return PoorMetabolizer
+	case a == FunctionNone || b == FunctionNone:
+		return IntermediateMetabolizer
+	case a == FunctionIncreased && b == FunctionIncreased:
+		return UltrarapidMetabolizer
+	case a == FunctionIncreased || b == FunctionIncreased:
+		return RapidMetabolizer
+	default:
+		return NormalMetabolizer
+	}
+}
+
+// activityScorePhenotype scores normal alleles 1, decreased 0.5 and no
+// function 0, as CPIC does for CYP2C9 and TPMT. Scores are doubled here to
+// stay in integers.
+func activityScorePhenotype(a, b AlleleFunction) MetabolizerPhenotype {
+	score := func(f AlleleFunction) int {
+		switch f {
+		case FunctionNormal, FunctionIncreased:
+			return 2
+		case FunctionDecreased:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	switch total := score(a) + score(b); {
+	case total >= 4:
+		return NormalMetabolizer
+	case total >= 2:
+		return IntermediateMetabolizer
+	default:
+		return PoorMetabolizer
+	}
+}
+
+// haplotypeIndex packs a haplotype's defining-variant bits into an integer.
+func haplotypeIndex(variants []int) int {
+	index := 0
+	for _, v := range variants {
+		index |= 1 << v
+	}
+	return index
+}
+
+// haplotypeTable returns, for every combination of defining variants, the
+// allele function and whether the combination is a known star allele.
+func (g Pharmacogene) haplotypeTable() (functions []int, known []int) {
+	size := 1 << len(g.Variants)
+	functions = make([]int, size)
+	known = make([]int, size)
+	for _, allele := range g.Alleles {
+		index := haplotypeIndex(allele.Variants)
+		functions[index] = int(allele.Function)
+		known[index] = 1
+	}
+	return functions, known
+}
+
+// phenotypeTable flattens Phenotype over every pair of allele functions.
+func (g Pharmacogene) phenotypeTable() []int {
+	table := make([]int, numAlleleFunctions*numAlleleFunctions)
+	for a := 0; a < numAlleleFunctions; a++ {
+		for b := 0; b < numAlleleFunctions; b++ {
+			table[a*numAlleleFunctions+b] = int(g.Phenotype(AlleleFunction(a), AlleleFunction(b)))
+		}
+	}
+	return table
+}
+
+// Diplotype is the pair of star alleles called for a gene.
+type Diplotype struct {
+	Gene       string
+	Haplotypes [2][]bool
+	Alleles    [2]StarAllele
+}
+
+func (d Diplotype) String() string {
+	return fmt.Sprintf("%s %s/%s", d.Gene, d.Alleles[0].Name, d.Alleles[1].Name)
+}
+
+// Phenotype returns the metabolizer class implied by the diplotype.
+func (d Diplotype) Phenotype() MetabolizerPhenotype {
+	return Pharmacogenes[d.Gene].Phenotype(d.Alleles[0].Function, d.Alleles[1].Function)
+}
+
+// CallStarAlleles assigns a star allele to each haplotype of gene from the
+// genotypes at its defining variants. Defining positions absent from the VCF
+// are treated as reference, as variant-only VCFs omit hom-ref sites. Unphased
+// calls are only accepted when at most one defining site is heterozygous.
+func CallStarAlleles(gene Pharmacogene, genotypes map[Locus]Genotype) (Diplotype, error) {
+	diplotype := Diplotype{Gene: gene.Name}
+
+	haplotypes, err := phaseHaplotypes(gene.Variants, genotypes)
+	if err != nil {
+		return diplotype, fmt.Errorf("cannot phase %s defining variants: %w", gene.Name, err)
+	}
+	diplotype.Haplotypes = haplotypes
+
+	for h, haplotype := range diplotype.Haplotypes {
+		allele, ok := gene.matchHaplotype(haplotype)
+		if !ok {
+			return diplotype, fmt.Errorf("%s haplotype %d does not match a known star allele", gene.Name, h+1)
+		}
+		diplotype.Alleles[h] = allele
+	}
+
+	// Report diplotypes in a canonical order, e.g. *1/*2 rather than *2/*1.
+	if diplotype.Alleles[0].Name > diplotype.Alleles[1].Name {
+		diplotype.Haplotypes[0], diplotype.Haplotypes[1] = diplotype.Haplotypes[1], diplotype.Haplotypes[0]
+		diplotype.Alleles[0], diplotype.Alleles[1] = diplotype.Alleles[1], diplotype.Alleles[0]
+	}
+
+	return diplotype, nil
+}
+
+func (g Pharmacogene) matchHaplotype(haplotype []bool) (StarAllele, bool) {
+	var carried []int
+	for i, has := range haplotype {
+		if has {
+			carried = append(carried, i)
+		}
+	}
+
+	for _, allele := range g.Alleles {
+		variants := slices.Sorted(slices.Values(allele.Variants))
+		if slices.Equal(variants, carried) {
+			return allele, true
+		}
+	}
+	return StarAllele{}, false
+}
+
+// maxDefiningVariants is the number of haplotype bits every DiplotypeCircuit
+// carries; genes with fewer defining variants leave the extra bits zero.
+const maxDefiningVariants = 3
+
+// DiplotypeCircuit proves that two private haplotypes are known star alleles
+// of a gene and together imply the public metabolizer phenotype.
+type DiplotypeCircuit struct {
+	GeneID    frontend.Variable `gnark:",public"`
+	Phenotype frontend.Variable `gnark:",public"`
+
+	Haplotype1 [maxDefiningVariants]frontend.Variable
+	Haplotype2 [maxDefiningVariants]frontend.Variable
+
+	// Gene selects the Pharmacogenes entry whose tables are compiled in.
+	Gene string `gnark:"-"`
+}
+
+func (c *DiplotypeCircuit) Define(api frontend.API) error {
+	gene, ok := Pharmacogenes[c.Gene]
+	if !ok {
+		return fmt.Errorf("unsupported pharmacogene: %s", c.Gene)
+	}
+
+	// The public gene ID must name the gene whose tables are compiled in.
+	api.AssertIsEqual(c.GeneID, geneID(gene.Name))
+
+	functions, known := gene.haplotypeTable()
+	functionTable := constants(functions)
+	knownTable := constants(known)
+
+	var haplotypeFunctions [2]frontend.Variable
+	for h, haplotype := range [][maxDefiningVariants]frontend.Variable{c.Haplotype1, c.Haplotype2} {
+		index := frontend.Variable(0)
+		for i, bit := range haplotype {
+			api.AssertIsBoolean(bit)
+			if i >= len(gene.Variants) {
+				api.AssertIsEqual(bit, 0)
+				continue
+			}
+			index = api.Add(index, api.Mul(bit, 1<<i))
+		}
+
+		api.AssertIsEqual(selector.Mux(api, index, knownTable...), 1)
+		haplotypeFunctions[h] = selector.Mux(api, index, functionTable...)
+	}
+
+	pair := api.Add(api.Mul(haplotypeFunctions[0], numAlleleFunctions), haplotypeFunctions[1])
+	phenotype := selector.Mux(api, pair, constants(gene.phenotypeTable())...)
+	api.AssertIsEqual(phenotype, c.Phenotype)
+
+	return nil
+}
+
+func constants(values []int) []frontend.Variable {
+	vars := make([]frontend.Variable, len(values))
+	for i, v := range values {
+		vars[i] = v
+	}
+	return vars
+}
+
+// geneID encodes a gene symbol as a field element for use as a public input.
+func geneID(gene string) *big.Int {
+	return new(big.Int).SetBytes([]byte(gene))
+}
+
+func (p *StarAlleleProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	gene, ok := Pharmacogenes[strings.ToUpper(p.Gene)]
+	if !ok {
+		return fmt.Errorf("unsupported pharmacogene: %s. Supported genes: CYP2C19, CYP2C9, TPMT", p.Gene)
+	}
+
+	specs := make([]VariantSpec, len(gene.Variants))
+	for i, v := range gene.Variants {
+		specs[i] = v.Spec()
+	}
+
+	fmt.Printf("Reading %s defining variants from VCF...\n", gene.Name)
+	genotypes, err := extractGenotypes(vcfPath, p.Settings, specs)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	diplotype, err := CallStarAlleles(gene, genotypes)
+	if err != nil {
+		return err
+	}
+	phenotype := diplotype.Phenotype()
+	fmt.Printf("Called diplotype %s (%s)\n", diplotype, phenotype)
+
+	assignment := &DiplotypeCircuit{
+		GeneID:    geneID(gene.Name),
+		Phenotype: int(phenotype),
+	}
+	for i := 0; i < maxDefiningVariants; i++ {
+		assignment.Haplotype1[i], assignment.Haplotype2[i] = 0, 0
+		if i < len(gene.Variants) {
+			assignment.Haplotype1[i] = boolToInt(diplotype.Haplotypes[0][i])
+			assignment.Haplotype2[i] = boolToInt(diplotype.Haplotypes[1][i])
+		}
+	}
+
+	if err := p.Settings.prove(ctx, "diplotype", &DiplotypeCircuit{Gene: gene.Name}, assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("diplotype", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven the subject is a %s %s\n", gene.Name, phenotype)
+	fmt.Println("without revealing the underlying star alleles or genotypes.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *StarAlleleProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 2 {
+		return false, withKind(ErrVerification, fmt.Errorf("diplotype proof has %d public inputs, expected 2", len(public)))
+	}
+
+	gene := string(public[0].Bytes())
+	if p.Gene != "" && !strings.EqualFold(p.Gene, gene) {
+		return false, withKind(ErrVerification, fmt.Errorf("proof is for %s, not %s", gene, p.Gene))
+	}
+	fmt.Printf("%s status: %s\n", gene, MetabolizerPhenotype(public[1].Int64()))
+
+	return verified, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,189 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestCallStarAlleles(t *testing.T) {
+	vcfContent := `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	SAMPLE1
+6	18130918	rs1142345	T	C	60	PASS	.	GT	1|0
+6	18139228	rs1800460	C	T	60	PASS	.	GT	1|0
+10	96541616	rs4244285	G	A	60	PASS	.	GT	0/1
+10	96521657	rs12248560	C	T	60	PASS	.	GT	1/0
+`
+	vcfPath := writeTempVCF(t, vcfContent)
+
+	tests := []struct {
+		gene      string
+		diplotype string
+		phenotype MetabolizerPhenotype
+		wantErr   bool
+	}{
+		{gene: "TPMT", diplotype: "TPMT *1/*3A", phenotype: IntermediateMetabolizer},
+		{gene: "CYP2C9", diplotype: "CYP2C9 *1/*1", phenotype: NormalMetabolizer},
+		// Two unphased heterozygous sites cannot be assigned to haplotypes.
+		{gene: "CYP2C19", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		gene := Pharmacogenes[tt.gene]
+		specs := make([]VariantSpec, len(gene.Variants))
+		for i, v := range gene.Variants {
+			specs[i] = v.Spec()
+		}
+
+		genotypes, err := extractGenotypes(vcfPath, Settings{}, specs)
+		if err != nil {
+			t.Fatalf("%s: extracting genotypes should not return error: %v", tt.gene, err)
+		}
+
+		diplotype, err := CallStarAlleles(gene, genotypes)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %s", tt.gene, diplotype)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: calling star alleles should not return error: %v", tt.gene, err)
+		}
+		if diplotype.String() != tt.diplotype {
+			t.Errorf("%s: diplotype = %s, want %s", tt.gene, diplotype, tt.diplotype)
+		}
+		if diplotype.Phenotype() != tt.phenotype {
+			t.Errorf("%s: phenotype = %s, want %s", tt.gene, diplotype.Phenotype(), tt.phenotype)
+		}
+	}
+}
+
+func TestDiplotypeCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	gene := Pharmacogenes["CYP2C19"]
+
+	// *2/*17: one no-function and one increased-function allele.
+	assignment := func(phenotype MetabolizerPhenotype) *DiplotypeCircuit {
+		return &DiplotypeCircuit{
+			GeneID:     geneID(gene.Name),
+			Phenotype:  int(phenotype),
+			Haplotype1: [maxDefiningVariants]frontend.Variable{1, 0, 0},
+			Haplotype2: [maxDefiningVariants]frontend.Variable{0, 0, 1},
+		}
+	}
+
+	if err := test.IsSolved(&DiplotypeCircuit{Gene: gene.Name}, assignment(IntermediateMetabolizer), field); err != nil {
+		t.Errorf("*2/*17 should prove intermediate metabolizer: %v", err)
+	}
+	if err := test.IsSolved(&DiplotypeCircuit{Gene: gene.Name}, assignment(RapidMetabolizer), field); err == nil {
+		t.Errorf("*2/*17 should not prove rapid metabolizer")
+	}
+
+	// *2 and *3 together on one haplotype is not a defined star allele.
+	unknown := assignment(PoorMetabolizer)
+	unknown.Haplotype1 = [maxDefiningVariants]frontend.Variable{1, 1, 0}
+	if err := test.IsSolved(&DiplotypeCircuit{Gene: gene.Name}, unknown, field); err == nil {
+		t.Errorf("An undefined haplotype should not satisfy the circuit")
+	}
+}
+
+func TestDiplotypeCircuitBindsGeneID(t *testing.T) {
+	assignment := &DiplotypeCircuit{
+		GeneID:     geneID("CYP2C9"),
+		Phenotype:  int(NormalMetabolizer),
+		Haplotype1: [maxDefiningVariants]frontend.Variable{0, 0, 0},
+		Haplotype2: [maxDefiningVariants]frontend.Variable{0, 0, 0},
+	}
+	if err := test.IsSolved(&DiplotypeCircuit{Gene: "CYP2C19"}, assignment, ecc.BN254.ScalarField()); err == nil {
+		t.Errorf("a CYP2C19 circuit should not prove a CYP2C9 gene ID")
+	}
+}
+
+func TestCYP2C19MetabolizerStatus(t *testing.T) {
+	gene := Pharmacogenes["CYP2C19"]
+
+	tests := []struct {
+		gts       [3]string // rs4244285 (*2), rs4986893 (*3), rs12248560 (*17)
+		diplotype string
+		phenotype MetabolizerPhenotype
+	}{
+		{[3]string{"0|0", "0|0", "0|0"}, "CYP2C19 *1/*1", NormalMetabolizer},
+		{[3]string{"1|0", "0|1", "0|0"}, "CYP2C19 *2/*3", PoorMetabolizer},
+		{[3]string{"0|1", "0|0", "0|0"}, "CYP2C19 *1/*2", IntermediateMetabolizer},
+		{[3]string{"0|0", "0|0", "1|0"}, "CYP2C19 *1/*17", RapidMetabolizer},
+		{[3]string{"0|0", "0|0", "1|1"}, "CYP2C19 *17/*17", UltrarapidMetabolizer},
+	}
+	for _, tt := range tests {
+		genotypes := make(map[Locus]Genotype)
+		for i, v := range gene.Variants {
+			alleles := []int{int(tt.gts[i][0] - '0'), int(tt.gts[i][2] - '0')}
+			genotypes[Locus{v.Chromosome, v.Position}] = Genotype{Ref: v.Ref, Alt: []string{v.Alt}, Alleles: alleles, Phased: true}
+		}
+
+		diplotype, err := CallStarAlleles(gene, genotypes)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.diplotype, err)
+		}
+		if diplotype.String() != tt.diplotype {
+			t.Errorf("diplotype = %s, want %s", diplotype, tt.diplotype)
+		}
+		if diplotype.Phenotype() != tt.phenotype {
+			t.Errorf("%s: phenotype = %s, want %s", diplotype, diplotype.Phenotype(), tt.phenotype)
+		}
+	}
+}
+
+func TestCYP2C19PanelEntries(t *testing.T) {
+	panel, err := LoadTraitPanel(filepath.Join("..", "..", "panels_traits.json"))
+	if err != nil {
+		t.Fatalf("LoadTraitPanel: %v", err)
+	}
+	variants, err := PanelVariants(panel, "CYP2C19")
+	if err != nil {
+		t.Fatalf("PanelVariants: %v", err)
+	}
+
+	for _, v := range Pharmacogenes["CYP2C19"].Variants {
+		want := v.Spec()
+		found := false
+		for _, got := range variants {
+			found = found || got == want
+		}
+		if !found {
+			t.Errorf("%s (%s) is missing from the trait panel", v.RSID, want)
+		}
+	}
+}
+
+func TestStarAlleleProofVerify(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+10	96541616	rs4244285	G	A	60	PASS	.	GT	0/1
+`)
+	out := filepath.Join(t.TempDir(), "diplotype_proof.bin")
+
+	if err := (&StarAlleleProof{Gene: "CYP2C19"}).Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	if verified, err := (&StarAlleleProof{Gene: "CYP2C19"}).Verify(t.Context(), out+".vk", out); err != nil || !verified {
+		t.Fatalf("Verify = %v, %v", verified, err)
+	}
+	public, err := readPublicInputs(out)
+	if err != nil {
+		t.Fatalf("readPublicInputs: %v", err)
+	}
+	if got := MetabolizerPhenotype(public[1].Int64()); got != IntermediateMetabolizer {
+		t.Errorf("proven status = %s, want intermediate metabolizer", got)
+	}
+
+	if _, err := (&StarAlleleProof{Gene: "TPMT"}).Verify(t.Context(), out+".vk", out); err == nil {
+		t.Errorf("CYP2C19 proof verified as a TPMT proof")
+	}
+}
@@ -0,0 +1,214 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// A Storage holds files by name, such as the objects of a bucket. Keys and
+// proofs at a path scheme://bucket/name, for a scheme registered with
+// RegisterStorage, are read and written through the Storage opened for the
+// bucket, so a proof can be generated with a proving key from a bucket and
+// written to one as it is to a local path. Names use forward slashes and
+// have no leading slash; a missing file is reported with an error wrapping
+// fs.ErrNotExist. A Storage must be safe for concurrent use.
+type Storage interface {
+	// Open opens the file name for reading. Files opened for proving and
+	// verifying are read from the start and seeked, so a reader that is
+	// not an io.Seeker is first copied to a temporary file.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// Create returns a writer of the file name, which replaces any file of
+	// that name once Close returns nil, and not before.
+	Create(ctx context.Context, name string) (io.WriteCloser, error)
+	// Stat describes the file name.
+	Stat(ctx context.Context, name string) (fs.FileInfo, error)
+	// Remove removes the file name.
+	Remove(ctx context.Context, name string) error
+}
+
+// A StorageOpener opens the Storage of a bucket.
+type StorageOpener func(ctx context.Context, bucket string) (Storage, error)
+
+var (
+	// storageOpeners holds the registered openers by scheme.
+	storageOpeners = map[string]StorageOpener{
+		"file": func(ctx context.Context, bucket string) (Storage, error) {
+			if bucket != "" {
+				return nil, fmt.Errorf("file URLs name no host, not %q", bucket)
+			}
+			return DirStorage("/"), nil
+		},
+	}
+	// storages holds the storages opened so far by scheme://bucket.
+	storages sync.Map
+)
+
+// RegisterStorage makes paths scheme://bucket/name name the file name in
+// the Storage open returns for bucket. Each bucket is opened once, when a
+// path in it is first used. Like RegisterProofType, it replaces an opener
+// of the same scheme and belongs in an init function.
+func RegisterStorage(scheme string, open StorageOpener) {
+	if scheme == "" || open == nil {
+		panic("proofs: RegisterStorage needs a scheme and an opener")
+	}
+	storageOpeners[scheme] = open
+}
+
+// IsStoragePath reports whether p names a file in a registered Storage
+// rather than on the local file system.
+func IsStoragePath(p string) bool {
+	_, _, _, ok := parseStoragePath(p)
+	return ok
+}
+
+// parseStoragePath splits a path scheme://bucket/name of a registered
+// scheme. Paths cleaned by filepath, which collapses the scheme's slashes
+// to scheme:/, are storage paths too.
+func parseStoragePath(p string) (scheme, bucket, name string, ok bool) {
+	scheme, rest, ok := strings.Cut(p, ":/")
+	if !ok || storageOpeners[scheme] == nil {
+		return "", "", "", false
+	}
+	rest = strings.TrimPrefix(rest, "/")
+	if scheme == "file" {
+		return scheme, "", strings.TrimPrefix(path.Clean("/"+rest), "/"), true
+	}
+	bucket, name, _ = strings.Cut(rest, "/")
+	return scheme, bucket, strings.TrimPrefix(path.Clean("/"+name), "/"), true
+}
+
+// storagePath returns the Storage holding the file at p and the file's
+// name in it, opening the Storage on first use, or a nil Storage when p is
+// not a storage path.
+func storagePath(p string) (Storage, string, error) {
+	scheme, bucket, name, ok := parseStoragePath(p)
+	if !ok {
+		return nil, "", nil
+	}
+	key := scheme + "://" + bucket
+	if s, ok := storages.Load(key); ok {
+		return s.(Storage), name, nil
+	}
+	s, err := storageOpeners[scheme](context.Background(), bucket)
+	if err != nil {
+		return nil, "", fmt.Errorf("opening %s: %w", key, err)
+	}
+	actual, _ := storages.LoadOrStore(key, s)
+	return actual.(Storage), name, nil
+}
+
+// openStorageFile opens the file name in s for reading, copying it to a
+// temporary file, removed once closed, when s's reader cannot seek.
+func openStorageFile(s Storage, name string) (io.ReadSeekCloser, error) {
+	r, err := s.Open(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+	if rs, ok := r.(io.ReadSeekCloser); ok {
+		return rs, nil
+	}
+	defer r.Close()
+	f, err := os.CreateTemp("", "storage-*")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return tempFile{f}, nil
+}
+
+// writeStorageFile writes the file name in s with write.
+func writeStorageFile(s Storage, name string, write func(io.Writer) error) error {
+	w, err := s.Create(context.Background(), name)
+	if err != nil {
+		return err
+	}
+	if err := write(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// tempFile is a temporary file removed once closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f tempFile) Close() error {
+	defer os.Remove(f.Name())
+	return f.File.Close()
+}
+
+// DirStorage is the Storage of the files under a local directory.
+type DirStorage string
+
+func (d DirStorage) path(name string) string {
+	return filepath.Join(string(d), filepath.FromSlash(path.Clean("/"+name)))
+}
+
+// Open opens the file name under d.
+func (d DirStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(d.path(name))
+}
+
+// Create writes the file name under d, creating its directory, through a
+// temporary file renamed over it on Close.
+func (d DirStorage) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	p := d.path(name)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	return &dirFile{File: f, path: p}, nil
+}
+
+// Stat describes the file name under d.
+func (d DirStorage) Stat(ctx context.Context, name string) (fs.FileInfo, error) {
+	return os.Stat(d.path(name))
+}
+
+// Remove removes the file name under d.
+func (d DirStorage) Remove(ctx context.Context, name string) error {
+	return os.Remove(d.path(name))
+}
+
+// dirFile is a file being written by DirStorage.Create.
+type dirFile struct {
+	*os.File
+	path string
+}
+
+func (f *dirFile) Close() error {
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0644); err != nil {
+		f.File.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.File.Close()
+		return err
+	}
+	if err := f.File.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), f.path)
+}
@@ -0,0 +1,84 @@
+package proofs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// streamStorage is a DirStorage whose readers cannot seek, as an object
+// store's cannot.
+type streamStorage struct {
+	DirStorage
+}
+
+func (s streamStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	f, err := s.DirStorage.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{f, f}, nil
+}
+
+func TestStorage(t *testing.T) {
+	dir := t.TempDir()
+	opened := 0
+	RegisterStorage("teststore", func(ctx context.Context, bucket string) (Storage, error) {
+		opened++
+		return streamStorage{DirStorage(filepath.Join(dir, bucket))}, nil
+	})
+	t.Cleanup(func() {
+		delete(storageOpeners, "teststore")
+		storages.Delete("teststore://bucket")
+	})
+
+	vcf := filepath.Join(t.TempDir(), "input.vcf")
+	if err := os.WriteFile(vcf, []byte(`##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := "teststore://bucket/proofs/zygosity.bin"
+	if err := Generate(t.Context(), "zygosity", vcf, output, WithPosition("15:28365618")); err != nil {
+		t.Fatalf("Generate into storage: %v", err)
+	}
+	for _, name := range []string{"zygosity.bin", "zygosity.bin" + MetadataSuffix, "zygosity.bin.pk", "zygosity.bin.vk"} {
+		if _, err := os.Stat(filepath.Join(dir, "bucket", "proofs", name)); err != nil {
+			t.Errorf("%s not in the bucket: %v", name, err)
+		}
+	}
+	if _, err := Verify(t.Context(), output, WithPosition("15:28365618")); err != nil {
+		t.Errorf("Verify from storage: %v", err)
+	}
+
+	// A proving key in storage proves a proof written locally.
+	local := filepath.Join(t.TempDir(), "zygosity.bin")
+	if err := Generate(t.Context(), "zygosity", vcf, local, WithPosition("15:28365618"), WithProvingKey(output+".pk")); err != nil {
+		t.Fatalf("Generate with a key in storage: %v", err)
+	}
+	if _, err := Verify(t.Context(), local, WithPosition("15:28365618"), WithVerifyingKey(output+".vk")); err != nil {
+		t.Errorf("Verify with a key in storage: %v", err)
+	}
+	if opened != 1 {
+		t.Errorf("bucket opened %d times, want once", opened)
+	}
+
+	if _, err := statFile("teststore://bucket/missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("statFile of a missing file: %v", err)
+	}
+	if err := removeFile(output); err != nil {
+		t.Errorf("removeFile: %v", err)
+	}
+	if !IsStoragePath("teststore:/bucket/x") || IsStoragePath("unknown://bucket/x") {
+		t.Errorf("storage paths misrecognised")
+	}
+}
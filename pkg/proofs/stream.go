@@ -0,0 +1,128 @@
+package proofs
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Files of a proof bundle, as GenerateTo writes them.
+const (
+	bundleProof    = "proof.bin"
+	bundleMetadata = bundleProof + MetadataSuffix
+	bundleKey      = bundleProof + ".vk"
+)
+
+// maxBundleEntry bounds the files VerifyFrom reads from a bundle.
+const maxBundleEntry = 1 << 30
+
+// GenerateTo is Generate for a VCF read from vcf, writing the proof to w
+// as a tar bundle of the proof file, its metadata and, unless
+// WithProvingKey is given, the verifying key of the setup it ran.
+// VerifyFrom reads the bundle back. Plain and gzip-compressed VCFs are
+// read; the VCF is spooled to a private temporary directory, removed with
+// the proving key before GenerateTo returns.
+func GenerateTo(ctx context.Context, proofType string, vcf io.Reader, w io.Writer, opts ...Option) error {
+	dir, err := os.MkdirTemp("", "vcf-proof-")
+	if err != nil {
+		return fmt.Errorf("creating working directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	vcfPath := filepath.Join(dir, "input.vcf")
+	f, err := os.OpenFile(vcfPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("spooling VCF: %w", err)
+	}
+	_, err = io.Copy(f, vcf)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return withKind(ErrVCF, fmt.Errorf("spooling VCF: %w", err))
+	}
+
+	proofPath := filepath.Join(dir, bundleProof)
+	if err := Generate(ctx, proofType, vcfPath, proofPath, opts...); err != nil {
+		return err
+	}
+
+	files := []string{bundleProof, bundleMetadata}
+	if fileExists(proofPath + ".vk") {
+		files = append(files, bundleKey)
+	}
+	tw := tar.NewWriter(w)
+	for _, name := range files {
+		if err := addBundleFile(tw, filepath.Join(dir, name), name); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("writing proof bundle: %w", err)
+	}
+	return nil
+}
+
+func addBundleFile(tw *tar.Writer, path, name string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("writing proof bundle: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("writing proof bundle: %w", err)
+	}
+	return nil
+}
+
+// VerifyFrom is Verify for a proof bundle written by GenerateTo, read from
+// r. The proof is verified against the verifying key in the bundle unless
+// WithVerifyingKey names another; a verifier that does not trust the
+// prover's setup passes its own key.
+func VerifyFrom(ctx context.Context, r io.Reader, opts ...Option) (*VerificationResult, error) {
+	dir, err := os.MkdirTemp("", "vcf-proof-")
+	if err != nil {
+		return nil, fmt.Errorf("creating working directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, withKind(ErrInvalidInput, fmt.Errorf("reading proof bundle: %w", err))
+		}
+		switch hdr.Name {
+		case bundleProof, bundleMetadata, bundleKey:
+		default:
+			return nil, withKind(ErrInvalidInput, fmt.Errorf("unexpected proof bundle entry %q", hdr.Name))
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > maxBundleEntry {
+			return nil, withKind(ErrInvalidInput, fmt.Errorf("proof bundle entry %s is not a file of at most %d bytes", hdr.Name, maxBundleEntry))
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, withKind(ErrInvalidInput, fmt.Errorf("reading proof bundle: %w", err))
+		}
+		if err := os.WriteFile(filepath.Join(dir, hdr.Name), data, 0600); err != nil {
+			return nil, fmt.Errorf("unpacking proof bundle: %w", err)
+		}
+	}
+
+	proofPath := filepath.Join(dir, bundleProof)
+	if !fileExists(proofPath) {
+		return nil, withKind(ErrInvalidInput, errors.New("proof bundle has no proof"))
+	}
+	return Verify(ctx, proofPath, opts...)
+}
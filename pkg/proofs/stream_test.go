@@ -0,0 +1,50 @@
+package proofs
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateToVerifyFrom(t *testing.T) {
+	vcf := `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`
+	var bundle bytes.Buffer
+	if err := GenerateTo(t.Context(), "zygosity", strings.NewReader(vcf), &bundle, WithPosition("15:28365618"), WithNonce("challenge")); err != nil {
+		t.Fatalf("GenerateTo: %v", err)
+	}
+
+	var names []string
+	tr := tar.NewReader(bytes.NewReader(bundle.Bytes()))
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, hdr.Name)
+	}
+	if strings.Join(names, " ") != "proof.bin proof.bin.meta.json proof.bin.vk" {
+		t.Errorf("bundle entries = %v", names)
+	}
+
+	if res, err := VerifyFrom(t.Context(), bytes.NewReader(bundle.Bytes()), WithPosition("15:28365618"), WithNonce("challenge")); err != nil || !res.Valid {
+		t.Fatalf("VerifyFrom = %+v, %v", res, err)
+	}
+	if _, err := VerifyFrom(t.Context(), bytes.NewReader(bundle.Bytes()), WithNonce("stale")); !errors.Is(ErrorKind(err), ErrVerification) {
+		t.Errorf("VerifyFrom with a stale nonce: %v", err)
+	}
+
+	var other bytes.Buffer
+	tw := tar.NewWriter(&other)
+	tw.WriteHeader(&tar.Header{Name: "../proof.bin", Mode: 0644, Size: 1})
+	tw.Write([]byte{0})
+	tw.Close()
+	if _, err := VerifyFrom(t.Context(), &other); !errors.Is(ErrorKind(err), ErrInvalidInput) {
+		t.Errorf("VerifyFrom of a bundle with a foreign entry: %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/brentp/vcfgo"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Structural variant types exposed by StructuralVariantCircuit.
+const (
+	SVDeletion    = 1
+	SVDuplication = 2
+)
+
+// SVTypeName returns the VCF SVTYPE of a structural variant type.
+func SVTypeName(svType int) string {
+	switch svType {
+	case SVDeletion:
+		return "DEL"
+	case SVDuplication:
+		return "DUP"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSVType parses a VCF SVTYPE or symbolic ALT such as DEL, <DUP> or
+// <DUP:TANDEM>.
+func ParseSVType(s string) (int, error) {
+	base, _, _ := strings.Cut(strings.ToUpper(strings.Trim(s, "<>")), ":")
+	switch base {
+	case "DEL":
+		return SVDeletion, nil
+	case "DUP":
+		return SVDuplication, nil
+	default:
+		return 0, fmt.Errorf("unsupported structural variant type %q (expected DEL or DUP)", s)
+	}
+}
+
+// StructuralVariant is a carried deletion or duplication spanning the
+// inclusive interval [Start, End].
+type StructuralVariant struct {
+	Type       int
+	Chromosome string
+	Start      uint64
+	End        uint64
+}
+
+func (sv StructuralVariant) String() string {
+	return fmt.Sprintf("%s %s:%d-%d", SVTypeName(sv.Type), sv.Chromosome, sv.Start, sv.End)
+}
+
+// overlaps reports whether the variant shares at least one base with r.
+func (sv StructuralVariant) overlaps(r GenomicRegion) bool {
+	return sv.Chromosome == r.Chromosome && sv.Start <= r.End && sv.End >= r.Start
+}
+
+// structuralVariant parses the record's ALT at index i as a symbolic
+// deletion or duplication. The type comes from the symbolic ALT, falling
+// back to INFO/SVTYPE, and the span from POS to INFO/END, skipping the
+// padding base that precedes a symbolic allele.
+func structuralVariant(variant *vcfgo.Variant, i int) (StructuralVariant, bool) {
+	alt := variant.Alternate[i]
+	var svType int
+	var err error
+	switch {
+	case strings.HasPrefix(alt, "<"):
+		svType, err = ParseSVType(alt)
+	default:
+		value, ok := infoString(variant, "SVTYPE")
+		if !ok {
+			return StructuralVariant{}, false
+		}
+		svType, err = ParseSVType(value)
+	}
+	if err != nil {
+		return StructuralVariant{}, false
+	}
+
+	end := recordEnd(variant)
+	start := variant.Pos + 1
+	if end < start {
+		return StructuralVariant{}, false
+	}
+	return StructuralVariant{
+		Type:       svType,
+		Chromosome: normalizeChromosome(variant.Chromosome),
+		Start:      start,
+		End:        end,
+	}, true
+}
+
+// extractStructuralVariants returns the deletions and duplications the
+// sample named by s.Sample, or the first sample, carries. Sites-only VCFs contribute every structural ALT.
+func extractStructuralVariants(vcfPath string, s Settings) ([]StructuralVariant, error) {
+	rdr, err := openVCF(vcfPath, s.Limits, s.Region)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	sampleIndex, err := sampleColumn(rdr.Header, s.Sample)
+	if err != nil {
+		return nil, err
+	}
+
+	var svs []StructuralVariant
+	for {
+		variant, err := rdr.next()
+		if err != nil {
+			return nil, err
+		}
+		if variant == nil {
+			break
+		}
+		if !s.Imputation.acceptsSample(variant, sampleIndex) || !s.Filter.accepts(variant, sampleIndex) {
+			continue
+		}
+
+		for i := range variant.Alternate {
+			if len(variant.Samples) > sampleIndex && variant.Samples[sampleIndex] != nil && !slices.Contains(variant.Samples[sampleIndex].GT, i+1) {
+				continue
+			}
+			if sv, ok := structuralVariant(variant, i); ok {
+				svs = append(svs, sv)
+			}
+		}
+	}
+	return svs, nil
+}
+
+// StructuralVariantCircuit proves that a deletion or duplication of a public
+// type overlaps a public region, without revealing its breakpoints. The
+// variant lies on the region's chromosome.
+type StructuralVariantCircuit struct {
+	Chromosome  frontend.Variable `gnark:",public"`
+	RegionStart frontend.Variable `gnark:",public"`
+	RegionEnd   frontend.Variable `gnark:",public"`
+	SVType      frontend.Variable `gnark:",public"`
+
+	Start frontend.Variable
+	End   frontend.Variable
+}
+
+func (c *StructuralVariantCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(api.Sub(c.SVType, SVDeletion), api.Sub(c.SVType, SVDuplication)), 0)
+
+	// Positions occupy the low 32 bits of a locus key.
+	for _, v := range []frontend.Variable{c.Start, c.End, c.RegionStart, c.RegionEnd} {
+		api.ToBinary(v, 32)
+	}
+	api.AssertIsLessOrEqual(c.Start, c.End)
+
+	// Two intervals overlap when each starts no later than the other ends.
+	api.AssertIsLessOrEqual(c.Start, c.RegionEnd)
+	api.AssertIsLessOrEqual(c.RegionStart, c.End)
+	bindPublic(api, c.Chromosome)
+
+	return nil
+}
+
+func (p *StructuralVariantProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+
+	region, err := ParseRegion(p.Region)
+	if err != nil {
+		return err
+	}
+	chromosome, ok := chromosomeIndex(region.Chromosome)
+	if !ok {
+		return fmt.Errorf("chromosome %s is not a primary assembly chromosome", region.Chromosome)
+	}
+	wantType := 0
+	if p.SVType != "" {
+		if wantType, err = ParseSVType(p.SVType); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Reading structural variants from VCF...")
+	svs, err := extractStructuralVariants(vcfPath, p.Settings)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	var sv StructuralVariant
+	found := false
+	for _, candidate := range svs {
+		if candidate.overlaps(region) && (wantType == 0 || candidate.Type == wantType) {
+			sv, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("no carried structural variant overlaps region %s", region)
+	}
+
+	assignment := &StructuralVariantCircuit{
+		Chromosome:  chromosome,
+		RegionStart: region.Start,
+		RegionEnd:   region.End,
+		SVType:      sv.Type,
+		Start:       sv.Start,
+		End:         sv.End,
+	}
+
+	if err := p.Settings.prove(ctx, "sv", &StructuralVariantCircuit{}, assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("sv", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven that a carried %s overlaps region %s\n", SVTypeName(sv.Type), region)
+	fmt.Println("without revealing its breakpoints.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *StructuralVariantProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 4 {
+		return false, withKind(ErrVerification, fmt.Errorf("structural variant proof has %d public inputs, expected 4", len(public)))
+	}
+
+	chrom := locusFromKey(public[0].Uint64() << 32).Chromosome
+	proven := GenomicRegion{Chromosome: chrom, Start: public[1].Uint64(), End: public[2].Uint64()}
+	svType := int(public[3].Int64())
+	fmt.Printf("A carried %s overlaps region %s\n", SVTypeName(svType), proven)
+
+	if p.Region != "" {
+		region, err := ParseRegion(p.Region)
+		if err != nil {
+			return false, err
+		}
+		if region != proven {
+			return false, withKind(ErrVerification, fmt.Errorf("proof is for region %s, not %s", proven, region))
+		}
+	}
+	if p.SVType != "" {
+		want, err := ParseSVType(p.SVType)
+		if err != nil {
+			return false, err
+		}
+		if svType != want {
+			return false, withKind(ErrVerification, fmt.Errorf("proof is for a %s, not a %s", SVTypeName(svType), SVTypeName(want)))
+		}
+	}
+	return verified, nil
+}
@@ -0,0 +1,117 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+const structuralTestVCF = `##fileformat=VCFv4.2
+##INFO=<ID=END,Number=1,Type=Integer,Description="End position of the variant">
+##INFO=<ID=SVTYPE,Number=1,Type=String,Description="Type of structural variant">
+##ALT=<ID=DEL,Description="Deletion">
+##ALT=<ID=DUP,Description="Duplication">
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+17	41196000	.	N	<DEL>	60	PASS	SVTYPE=DEL;END=41200000	GT	0/1
+17	41300000	.	N	<DUP:TANDEM>	60	PASS	SVTYPE=DUP;END=41310000	GT	0/0
+13	32890000	.	N	<DUP>	60	PASS	SVTYPE=DUP;END=32900000	GT	1/1
+2	1000	.	ACGTACGT	A	60	PASS	SVTYPE=DEL	GT	0/1
+2	5000	.	A	<INV>	60	PASS	SVTYPE=INV;END=6000	GT	0/1
+`
+
+func TestExtractStructuralVariants(t *testing.T) {
+	vcf := writeTempVCF(t, structuralTestVCF)
+	svs, err := extractStructuralVariants(vcf, Settings{})
+	if err != nil {
+		t.Fatalf("extractStructuralVariants: %v", err)
+	}
+
+	want := []StructuralVariant{
+		{SVDeletion, "17", 41196001, 41200000},
+		{SVDuplication, "13", 32890001, 32900000},
+		{SVDeletion, "2", 1001, 1007},
+	}
+	if len(svs) != len(want) {
+		t.Fatalf("got %v, want %v", svs, want)
+	}
+	for i := range want {
+		if svs[i] != want[i] {
+			t.Errorf("variant %d = %s, want %s", i, svs[i], want[i])
+		}
+	}
+}
+
+func TestStructuralVariantCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+
+	tests := []struct {
+		name       string
+		start, end uint64
+		svType     int
+		valid      bool
+	}{
+		{"spans region start", 900, 1100, SVDeletion, true},
+		{"spans region end", 1900, 2100, SVDuplication, true},
+		{"inside region", 1200, 1300, SVDeletion, true},
+		{"covers region", 500, 2500, SVDeletion, true},
+		{"touches region start", 800, 1000, SVDeletion, true},
+		{"before region", 500, 999, SVDeletion, false},
+		{"after region", 2001, 2500, SVDeletion, false},
+		{"reversed", 1300, 1200, SVDeletion, false},
+		{"unsupported type", 1200, 1300, 3, false},
+	}
+	for _, tt := range tests {
+		assignment := &StructuralVariantCircuit{
+			Chromosome:  17,
+			RegionStart: 1000,
+			RegionEnd:   2000,
+			SVType:      tt.svType,
+			Start:       tt.start,
+			End:         tt.end,
+		}
+		err := test.IsSolved(&StructuralVariantCircuit{}, assignment, field)
+		if tt.valid && err != nil {
+			t.Errorf("%s: rejected: %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+}
+
+func TestStructuralVariantProof(t *testing.T) {
+	vcf := writeTempVCF(t, structuralTestVCF)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "sv_proof.bin")
+
+	// BRCA1 exons 1-2 region
+	proof := &StructuralVariantProof{Region: "17:41197000-41277500", SVType: "DEL"}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	if _, err := (&StructuralVariantProof{SVType: "DUP"}).Verify(t.Context(), out+".vk", out); err == nil {
+		t.Errorf("deletion proof should not verify as a duplication")
+	}
+	if _, err := (&StructuralVariantProof{Region: "17:41197000-41277501"}).Verify(t.Context(), out+".vk", out); err == nil {
+		t.Errorf("proof should not verify for a different region")
+	}
+	assertInputBound(t, out, 0)
+
+	failures := []*StructuralVariantProof{
+		{Region: "17:41305000-41306000"},                // hom-ref duplication
+		{Region: "13:32889000-32895000", SVType: "DEL"}, // only a duplication overlaps
+		{Region: "2:5000-6000"},                         // inversions are not supported
+	}
+	for _, p := range failures {
+		if err := p.Generate(t.Context(), vcf, "", filepath.Join(dir, "p.bin")); err == nil {
+			t.Errorf("%s %s: expected an error", p.Region, p.SVType)
+		}
+	}
+}
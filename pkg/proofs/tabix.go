@@ -0,0 +1,377 @@
+package proofs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/brentp/vcfgo"
+)
+
+// Tabix indexes bin records by the 0-based region they span in a hierarchy
+// of depth levels below bins of 2^minShift bases.
+const (
+	tabixMinShift = 14
+	tabixDepth    = 5
+)
+
+// maxIndexEntries bounds the counts read from an index, so a corrupt file
+// fails instead of exhausting memory.
+const maxIndexEntries = 1 << 26
+
+// bgzfChunk is a range of virtual offsets in a BGZF file: the compressed
+// offset of a block shifted left 16 bits, plus an offset into the block's
+// decompressed data.
+type bgzfChunk struct {
+	begin, end uint64
+}
+
+// refIndex indexes one reference sequence.
+type refIndex struct {
+	bins map[uint32][]bgzfChunk
+	// intervals is the tabix linear index: the lowest offset of a record
+	// overlapping each 2^14-base window.
+	intervals []uint64
+}
+
+// vcfIndex is a tabix (.tbi) or CSI (.csi) index of a bgzip-compressed VCF.
+type vcfIndex struct {
+	minShift, depth int
+	refs            []refIndex
+	// byName maps normalized reference names to positions in refs.
+	byName map[string]int
+}
+
+// vcfIndexPath returns the index to read vcfPath through: index when set,
+// otherwise a .tbi or .csi file next to the VCF, or "" when there is none.
+func vcfIndexPath(vcfPath string, index string) string {
+	if index != "" {
+		return index
+	}
+	for _, ext := range []string{".tbi", ".csi"} {
+		if _, err := statFile(vcfPath + ext); err == nil {
+			return vcfPath + ext
+		}
+	}
+	return ""
+}
+
+// readVCFIndex reads the tabix or CSI index at path. CSI indexes written
+// without tabix metadata name their references by the VCF header's contig
+// lines, so header supplies the names for those.
+func readVCFIndex(path string, header *vcfgo.Header) (*vcfIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading index %s: %w", path, err)
+	}
+	defer zr.Close()
+	r := &indexReader{r: bufio.NewReader(zr)}
+
+	var idx *vcfIndex
+	switch magic := string(r.bytes(4)); magic {
+	case "TBI\x01":
+		idx = readTabix(r)
+	case "CSI\x01":
+		idx = readCSI(r, header)
+	default:
+		if r.err == nil {
+			r.err = fmt.Errorf("not a tabix or CSI index")
+		}
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("reading index %s: %w", path, r.err)
+	}
+	return idx, nil
+}
+
+func readTabix(r *indexReader) *vcfIndex {
+	nRef := r.count()
+	// Format, sequence, begin and end columns, comment character and
+	// skipped lines describe the indexed text, which is always a VCF here.
+	for range 6 {
+		r.int32()
+	}
+	names := splitNames(r.bytes(r.count()))
+	return newVCFIndex(tabixMinShift, tabixDepth, names, readRefs(r, nRef, false))
+}
+
+func readCSI(r *indexReader, header *vcfgo.Header) *vcfIndex {
+	minShift, depth := int(r.int32()), int(r.int32())
+	aux := r.bytes(r.count())
+	nRef := r.count()
+	if minShift <= 0 || depth <= 0 || minShift+3*depth > 62 {
+		r.fail(fmt.Errorf("invalid CSI binning (min_shift %d, depth %d)", minShift, depth))
+	}
+
+	var names []string
+	if len(aux) >= 28 {
+		// Tabix metadata as in a .tbi, ending with the names' length.
+		names = splitNames(aux[28:])
+	} else if header != nil {
+		for _, contig := range header.Contigs {
+			names = append(names, contig["ID"])
+		}
+	}
+	return newVCFIndex(minShift, depth, names, readRefs(r, nRef, true))
+}
+
+func readRefs(r *indexReader, nRef int, csi bool) []refIndex {
+	var refs []refIndex
+	for i := 0; i < nRef && r.err == nil; i++ {
+		ref := refIndex{bins: map[uint32][]bgzfChunk{}}
+		nBin := r.count()
+		for j := 0; j < nBin && r.err == nil; j++ {
+			bin := r.uint32()
+			if csi {
+				r.uint64() // loffset, superseded by the chunks themselves
+			}
+			nChunk := r.count()
+			for k := 0; k < nChunk && r.err == nil; k++ {
+				ref.bins[bin] = append(ref.bins[bin], bgzfChunk{begin: r.uint64(), end: r.uint64()})
+			}
+		}
+		if !csi {
+			nIntv := r.count()
+			for j := 0; j < nIntv && r.err == nil; j++ {
+				ref.intervals = append(ref.intervals, r.uint64())
+			}
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func newVCFIndex(minShift, depth int, names []string, refs []refIndex) *vcfIndex {
+	idx := &vcfIndex{minShift: minShift, depth: depth, refs: refs, byName: map[string]int{}}
+	for i, name := range names {
+		if i < len(refs) {
+			idx.byName[normalizeChromosome(name)] = i
+		}
+	}
+	return idx
+}
+
+// splitNames splits tabix's NUL-terminated reference names.
+func splitNames(b []byte) []string {
+	var names []string
+	for _, name := range bytes.Split(bytes.TrimRight(b, "\x00"), []byte{0}) {
+		names = append(names, string(name))
+	}
+	return names
+}
+
+// start returns the virtual offset to scan from for records overlapping the
+// 0-based, half-open region [beg, end) of chrom, and false when the index
+// has no such records.
+func (idx *vcfIndex) start(chrom string, beg, end int64) (uint64, bool) {
+	i, ok := idx.byName[normalizeChromosome(chrom)]
+	if !ok {
+		return 0, false
+	}
+	ref := idx.refs[i]
+
+	// Chunks ending before the first record of beg's window cannot
+	// overlap the region.
+	var minOffset uint64
+	if n := int64(len(ref.intervals)); n > 0 {
+		minOffset = ref.intervals[min(beg>>tabixMinShift, n-1)]
+	}
+
+	start, found := uint64(math.MaxUint64), false
+	for _, bin := range regionBins(beg, end, idx.minShift, idx.depth) {
+		for _, c := range ref.bins[bin] {
+			if c.end > minOffset && c.begin < start {
+				start, found = c.begin, true
+			}
+		}
+	}
+	return start, found
+}
+
+// regionBins lists the bins, at every level, that may hold records
+// overlapping [beg, end).
+func regionBins(beg, end int64, minShift, depth int) []uint32 {
+	maxPos := int64(1)<<(minShift+3*depth) - 1
+	beg, end = max(beg, 0), min(max(end, beg+1), maxPos+1)-1
+	if beg > end {
+		return nil
+	}
+
+	var bins []uint32
+	shift, offset := minShift+3*depth, 0
+	for level := 0; level <= depth; level++ {
+		for b := offset + int(beg>>shift); b <= offset+int(end>>shift); b++ {
+			bins = append(bins, uint32(b))
+		}
+		shift -= 3
+		offset += 1 << (3 * level)
+	}
+	return bins
+}
+
+// indexReader reads an index's little-endian fields, keeping the first
+// error.
+type indexReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *indexReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *indexReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		r.fail(err)
+		return nil
+	}
+	return b
+}
+
+func (r *indexReader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *indexReader) int32() int32 {
+	return int32(r.uint32())
+}
+
+func (r *indexReader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+// count reads a length, rejecting negative and implausibly large ones.
+func (r *indexReader) count() int {
+	n := r.int32()
+	if n < 0 || n > maxIndexEntries {
+		r.fail(fmt.Errorf("invalid count %d", n))
+		return 0
+	}
+	return int(n)
+}
+
+// indexedVCF reads the records at given loci of a bgzip-compressed VCF
+// through its index.
+type indexedVCF struct {
+	f      *os.File
+	index  *vcfIndex
+	header *vcfgo.Header
+	limits ReadLimits
+}
+
+// openIndexedVCF opens vcfPath for indexed reads with the index at
+// indexPath. The VCF must be compressed with bgzip, whose blocks the
+// index's offsets point into, and no newer than the index.
+func openIndexedVCF(vcfPath string, indexPath string, header *vcfgo.Header, limits ReadLimits) (*indexedVCF, error) {
+	vcfInfo, err := os.Stat(vcfPath)
+	if err != nil {
+		return nil, err
+	}
+	indexInfo, err := os.Stat(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	if indexInfo.ModTime().Before(vcfInfo.ModTime()) {
+		return nil, fmt.Errorf("index is older than the VCF")
+	}
+
+	index, err := readVCFIndex(indexPath, header)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(vcfPath)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil || !isBGZF(zr.Header.Extra) {
+		f.Close()
+		return nil, fmt.Errorf("%s is not compressed with bgzip, so it cannot be read through an index", vcfPath)
+	}
+	return &indexedVCF{f: f, index: index, header: header, limits: limits}, nil
+}
+
+// isBGZF reports whether a gzip member's extra field has the BC subfield
+// bgzip writes into every block.
+func isBGZF(extra []byte) bool {
+	for len(extra) >= 4 {
+		size := int(binary.LittleEndian.Uint16(extra[2:4]))
+		if extra[0] == 'B' && extra[1] == 'C' && size == 2 {
+			return true
+		}
+		if len(extra) < 4+size {
+			break
+		}
+		extra = extra[4+size:]
+	}
+	return false
+}
+
+func (v *indexedVCF) Close() error {
+	return v.f.Close()
+}
+
+// read calls visit with every record at locus, in file order.
+func (v *indexedVCF) read(locus Locus, visit func(*vcfgo.Variant) error) error {
+	pos := int64(locus.Position)
+	voffset, ok := v.index.start(locus.Chromosome, pos-1, pos)
+	if !ok {
+		return nil
+	}
+
+	if _, err := v.f.Seek(int64(voffset>>16), io.SeekStart); err != nil {
+		return fmt.Errorf("seeking in VCF: %w", err)
+	}
+	zr, err := gzip.NewReader(v.f)
+	if err != nil {
+		return fmt.Errorf("reading VCF block: %w", err)
+	}
+	defer zr.Close()
+	if _, err := io.CopyN(io.Discard, zr, int64(voffset&0xffff)); err != nil {
+		return fmt.Errorf("reading VCF block: %w", err)
+	}
+
+	rdr, err := newVCFReader(zr, v.header, v.limits, nil)
+	if err != nil {
+		return err
+	}
+	chrom := normalizeChromosome(locus.Chromosome)
+	for {
+		variant, err := rdr.next()
+		if err != nil {
+			return err
+		}
+		if variant == nil || normalizeChromosome(variant.Chromosome) != chrom || variant.Pos > locus.Position {
+			return nil
+		}
+		if variant.Pos == locus.Position {
+			if err := visit(variant); err != nil {
+				return err
+			}
+		}
+	}
+}
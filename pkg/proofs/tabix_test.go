@@ -0,0 +1,203 @@
+package proofs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const indexedVCFHeader = `##fileformat=VCFv4.2
+##contig=<ID=1>
+##contig=<ID=15>
+##contig=<ID=22>
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+`
+
+var indexedVCFRecords = []string{
+	"1	1000	.	A	G	60	PASS	.	GT	0/1",
+	"15	28000000	.	C	T	60	PASS	.	GT	1/1",
+	"15	28365618	.	A	G	60	PASS	.	GT	1|0",
+	"15	48000000	.	G	A	60	PASS	.	GT	0/1",
+	"22	100	.	T	C	60	PASS	.	GT	0/0",
+}
+
+// writeBGZF writes blocks as BGZF members and returns the virtual offset
+// each starts at.
+func writeBGZF(t *testing.T, path string, blocks []string) []uint64 {
+	t.Helper()
+	var buf bytes.Buffer
+	var offsets []uint64
+	for _, block := range append(blocks, "") {
+		offsets = append(offsets, uint64(buf.Len())<<16)
+		zw := gzip.NewWriter(&buf)
+		zw.Header.Extra = []byte{'B', 'C', 2, 0, 0, 0}
+		zw.Write([]byte(block))
+		zw.Close()
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return offsets
+}
+
+// tabixBin is the smallest bin holding the 0-based region [beg, end).
+func tabixBin(beg, end int64) uint32 {
+	end--
+	for level, shift := 5, 14; level > 0; level, shift = level-1, shift+3 {
+		if beg>>shift == end>>shift {
+			return uint32((1<<(3*level)-1)/7 + beg>>shift)
+		}
+	}
+	return 0
+}
+
+// writeTestIndex indexes one record per block the way tabix does, as a TBI
+// or as a CSI without tabix metadata.
+func writeTestIndex(t *testing.T, path string, records []string, offsets []uint64, csi bool) {
+	t.Helper()
+	var names []string
+	bins := map[string]map[uint32][]bgzfChunk{}
+	intervals := map[string][]uint64{}
+	for i, record := range records {
+		fields := strings.Split(record, "\t")
+		pos, _ := strconv.ParseInt(fields[1], 10, 64)
+		beg, end := pos-1, pos-1+int64(len(fields[3]))
+		if bins[fields[0]] == nil {
+			names = append(names, fields[0])
+			bins[fields[0]] = map[uint32][]bgzfChunk{}
+		}
+		bin := tabixBin(beg, end)
+		bins[fields[0]][bin] = append(bins[fields[0]][bin], bgzfChunk{offsets[i+1], offsets[i+2]})
+		for len(intervals[fields[0]]) <= int(beg>>14) {
+			intervals[fields[0]] = append(intervals[fields[0]], offsets[i+1])
+		}
+	}
+
+	var buf bytes.Buffer
+	put := func(v any) { binary.Write(&buf, binary.LittleEndian, v) }
+	if csi {
+		buf.WriteString("CSI\x01")
+		put([]int32{14, 5, 0, int32(len(names))})
+	} else {
+		nameBytes := strings.Join(names, "\x00") + "\x00"
+		buf.WriteString("TBI\x01")
+		put([]int32{int32(len(names)), 2, 1, 2, 0, '#', 0, int32(len(nameBytes))})
+		buf.WriteString(nameBytes)
+	}
+	for _, name := range names {
+		put(int32(len(bins[name])))
+		for bin, chunks := range bins[name] {
+			put(bin)
+			if csi {
+				put(chunks[0].begin)
+			}
+			put(int32(len(chunks)))
+			for _, c := range chunks {
+				put([]uint64{c.begin, c.end})
+			}
+		}
+		if !csi {
+			put(int32(len(intervals[name])))
+			put(intervals[name])
+		}
+	}
+
+	var zbuf bytes.Buffer
+	zw := gzip.NewWriter(&zbuf)
+	zw.Write(buf.Bytes())
+	zw.Close()
+	if err := os.WriteFile(path, zbuf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndexedGenotypes(t *testing.T) {
+	dir := t.TempDir()
+	vcf := filepath.Join(dir, "genome.vcf.gz")
+	blocks := []string{indexedVCFHeader}
+	for _, record := range indexedVCFRecords {
+		blocks = append(blocks, record+"\n")
+	}
+	offsets := writeBGZF(t, vcf, blocks)
+
+	loci := []Locus{{"chr15", 28365618}, {"1", 1000}, {"22", 100}, {"15", 28365619}, {"X", 5}}
+	want, err := extractGenotypes(vcf, Settings{}, locusSpecs(loci...))
+	if err != nil || len(want) != 3 {
+		t.Fatalf("scanning: genotypes = %v, %v; want 3", want, err)
+	}
+
+	rdr, err := openVCF(vcf, ReadLimits{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdr.Close()
+	for _, csi := range []bool{false, true} {
+		index := filepath.Join(dir, "genome.tbi")
+		if csi {
+			index = filepath.Join(dir, "genome.csi")
+		}
+		writeTestIndex(t, index, indexedVCFRecords, offsets, csi)
+
+		// The index points straight at the block holding the locus.
+		idx, err := readVCFIndex(index, rdr.Header)
+		if err != nil {
+			t.Fatalf("readVCFIndex(csi=%v): %v", csi, err)
+		}
+		if start, ok := idx.start("chr15", 28365617, 28365618); !ok || start != offsets[3] {
+			t.Errorf("csi=%v: start = %d, %v; want %d", csi, start, ok, offsets[3])
+		}
+
+		got, err := extractGenotypes(vcf, Settings{Index: index}, locusSpecs(loci...))
+		if err != nil {
+			t.Fatalf("csi=%v: extractGenotypes: %v", csi, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("csi=%v: indexed genotypes = %v, want %v", csi, got, want)
+		}
+	}
+
+	// An index next to the VCF is found on its own; a stale one is skipped
+	// unless it was named.
+	os.Rename(filepath.Join(dir, "genome.tbi"), vcf+".tbi")
+	if got, err := extractGenotypes(vcf, Settings{}, locusSpecs(loci...)); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("discovered index: genotypes = %v, %v", got, err)
+	}
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(vcf, later, later)
+	if got, err := extractGenotypes(vcf, Settings{}, locusSpecs(loci...)); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("stale discovered index: genotypes = %v, %v", got, err)
+	}
+	if _, err := extractGenotypes(vcf, Settings{Index: vcf + ".tbi"}, locusSpecs(loci...)); err == nil {
+		t.Errorf("a stale named index should be rejected")
+	}
+
+	// Indexes only work on bgzip-compressed VCFs.
+	plain := writeTempVCF(t, indexedVCFHeader+strings.Join(indexedVCFRecords, "\n")+"\n")
+	earlier := time.Now().Add(-time.Hour)
+	os.Chtimes(plain, earlier, earlier)
+	if _, err := extractGenotypes(plain, Settings{Index: filepath.Join(dir, "genome.csi")}, locusSpecs(loci...)); err == nil {
+		t.Errorf("an index over a plain VCF should be rejected")
+	} else if !strings.Contains(err.Error(), "bgzip") {
+		t.Errorf("plain VCF rejected for the wrong reason: %v", err)
+	}
+}
+
+func TestRegionBins(t *testing.T) {
+	if got, want := regionBins(0, 1, 14, 5), []uint32{0, 1, 9, 73, 585, 4681}; !slices.Equal(got, want) {
+		t.Errorf("regionBins(0, 1) = %v, want %v", got, want)
+	}
+	// A region across a 16 kbp boundary spans two leaf bins.
+	got := regionBins(1<<14-1, 1<<14+1, 14, 5)
+	if !slices.Contains(got, 4681) || !slices.Contains(got, 4682) {
+		t.Errorf("regionBins across a boundary = %v", got)
+	}
+}
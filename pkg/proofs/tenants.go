@@ -0,0 +1,213 @@
+package proofs
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// APIKeySuffix is appended to the path prefix given to GenerateAPIKey to
+// name the API key file.
+const APIKeySuffix = ".apikey"
+
+// apiKeyPrefix starts every API key, so a leaked key is recognizable.
+const apiKeyPrefix = "vcfp_"
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Tenant is a client of a ProofServer serving several, as read from a
+// tenants file by ReadTenants.
+type Tenant struct {
+	// ID names the tenant and the directories of its proofs and keys. It
+	// is lower case letters, digits, '-' and '_'.
+	ID string `json:"id"`
+	// APIKeys are the HashAPIKey hashes of the keys the tenant
+	// authenticates with, so the tenants file holds no secrets. A tenant
+	// rotates its key by having a new one added before the old is removed.
+	APIKeys []string `json:"api_keys"`
+	// RequestsPerMinute bounds the requests the tenant makes, allowing
+	// bursts of as many; zero means no limit.
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+	// MaxPending bounds the tenant's proofs being generated or waiting for
+	// a worker; zero means no limit.
+	MaxPending int `json:"max_pending,omitempty"`
+}
+
+// tenantState is a tenant of a running server.
+type tenantState struct {
+	Tenant
+	limiter *rate.Limiter
+	// pending counts the tenant's pending proofs, under ProofServer.mu.
+	pending int
+}
+
+type tenantKey struct{}
+
+// HashAPIKey returns the hash of an API key that a Tenant lists.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateAPIKey writes a new random API key to path+APIKeySuffix, readable
+// by its owner only, and returns its HashAPIKey hash for the tenants file.
+func GenerateAPIKey(path string) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generating API key: %w", err)
+	}
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+	if err := os.WriteFile(path+APIKeySuffix, []byte(key+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("writing API key: %w", err)
+	}
+	return HashAPIKey(key), nil
+}
+
+// ReadTenants reads a tenants file: a JSON array of Tenant.
+func ReadTenants(path string) ([]Tenant, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading tenants: %w", err)
+	}
+	var tenants []Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("parsing tenants %s: %w", path, err))
+	}
+	if err := checkTenants(tenants); err != nil {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("tenants %s: %w", path, err))
+	}
+	return tenants, nil
+}
+
+// checkTenants checks that tenants have valid, distinct IDs and API keys.
+func checkTenants(tenants []Tenant) error {
+	ids := map[string]bool{}
+	keys := map[string]string{}
+	for _, t := range tenants {
+		if !tenantIDPattern.MatchString(t.ID) {
+			return fmt.Errorf("invalid tenant ID %q: want lower case letters, digits, '-' and '_'", t.ID)
+		}
+		if ids[t.ID] {
+			return fmt.Errorf("tenant %s is listed twice", t.ID)
+		}
+		ids[t.ID] = true
+		if len(t.APIKeys) == 0 {
+			return fmt.Errorf("tenant %s has no API keys", t.ID)
+		}
+		for _, hash := range t.APIKeys {
+			if raw, err := hex.DecodeString(hash); err != nil || len(raw) != sha256.Size {
+				return fmt.Errorf("tenant %s: API key hash %q is not a hex SHA-256", t.ID, hash)
+			}
+			if other, ok := keys[strings.ToLower(hash)]; ok {
+				return fmt.Errorf("tenants %s and %s share an API key", other, t.ID)
+			}
+			keys[strings.ToLower(hash)] = t.ID
+		}
+		if t.RequestsPerMinute < 0 || t.MaxPending < 0 {
+			return fmt.Errorf("tenant %s has a negative limit", t.ID)
+		}
+	}
+	return nil
+}
+
+// newTenantStates indexes tenants by the hashes of their API keys.
+func newTenantStates(tenants []Tenant) (map[string]*tenantState, error) {
+	if err := checkTenants(tenants); err != nil {
+		return nil, err
+	}
+	states := make(map[string]*tenantState)
+	for _, t := range tenants {
+		state := &tenantState{Tenant: t, limiter: rate.NewLimiter(rate.Inf, 0)}
+		if t.RequestsPerMinute > 0 {
+			state.limiter = rate.NewLimiter(rate.Limit(float64(t.RequestsPerMinute)/60), t.RequestsPerMinute)
+		}
+		for _, hash := range t.APIKeys {
+			states[strings.ToLower(hash)] = state
+		}
+	}
+	return states, nil
+}
+
+// authenticate wraps h to serve only requests bearing a tenant's API key,
+// within the tenant's rate limit, with the tenant in their context, and
+// GET /openapi.json. A server without tenants serves every request.
+func (s *ProofServer) authenticate(h http.Handler) http.Handler {
+	if s.tenants == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Clients need the API description before they have a key.
+		if r.Method == http.MethodGet && r.URL.Path == "/openapi.json" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		key := r.Header.Get("X-API-Key")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			key = strings.TrimSpace(bearer)
+		}
+		tenant, ok := s.tenants[HashAPIKey(key)]
+		if key == "" || !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="proofs"`)
+			writeError(w, http.StatusUnauthorized, errors.New("a valid API key is required, as a bearer token or X-API-Key header"))
+			return
+		}
+		if reservation := tenant.limiter.Reserve(); reservation.Delay() > 0 {
+			wait := reservation.Delay()
+			reservation.Cancel()
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, fmt.Errorf("tenant %s is over its limit of %d requests a minute; retry in %s", tenant.ID, tenant.RequestsPerMinute, wait.Round(time.Second)))
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tenantKey{}, tenant)))
+	})
+}
+
+// requestTenant returns the tenant making r, or nil on a server without
+// tenants.
+func requestTenant(r *http.Request) *tenantState {
+	t, _ := r.Context().Value(tenantKey{}).(*tenantState)
+	return t
+}
+
+// proofsDir returns the directory of the proofs of tenant t.
+func (s *ProofServer) proofsDir(t *tenantState) string {
+	if t == nil {
+		return s.Dir
+	}
+	return filepath.Join(s.Dir, t.ID)
+}
+
+// keyFile returns the key file of tenant t for proofType with extension
+// ext (".pk" or ".vk"): the tenant's own, under KeyDir/<tenant ID>, or the
+// one shared by all tenants in KeyDir, or "" when there is neither or
+// proofType is not a registered type.
+func (s *ProofServer) keyFile(t *tenantState, proofType, ext string) string {
+	registered, ok := LookupProofType(proofType)
+	if s.KeyDir == "" || !ok {
+		return ""
+	}
+	proofType = registered.Name
+	if t != nil && tenantIDPattern.MatchString(t.ID) {
+		if path := filepath.Join(s.KeyDir, t.ID, proofType+ext); fileExists(path) {
+			return path
+		}
+	}
+	if path := filepath.Join(s.KeyDir, proofType+ext); fileExists(path) {
+		return path
+	}
+	return ""
+}
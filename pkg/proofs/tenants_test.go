@@ -0,0 +1,215 @@
+package proofs
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tenantRequest makes a request with the API key key and, for POST, a
+// multipart form of fields and a VCF upload.
+func tenantRequest(t *testing.T, method, url, key string, fields map[string]string) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		form.WriteField(name, value)
+	}
+	if method == http.MethodPost {
+		part, _ := form.CreateFormFile("vcf", "vcf")
+		part.Write([]byte("##fileformat=VCFv4.2\n#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO\tFORMAT\tS1\n15\t28365618\t.\tA\tG\t60\tPASS\t.\tGT\t1|0\n"))
+	}
+	form.Close()
+	req, err := http.NewRequest(method, url, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	return resp
+}
+
+func TestProofServerTenants(t *testing.T) {
+	dir := t.TempDir()
+	srv := &ProofServer{Dir: dir, Tenants: []Tenant{
+		{ID: "acme", APIKeys: []string{HashAPIKey("acme-key")}, MaxPending: 1},
+		{ID: "globex", APIKeys: []string{HashAPIKey("globex-key")}, RequestsPerMinute: 2},
+	}}
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	// Hold the only worker slot so proofs stay pending.
+	srv.slots <- struct{}{}
+
+	for _, key := range []string{"", "wrong-key"} {
+		resp := tenantRequest(t, http.MethodGet, server.URL+"/proofs/0123456789abcdef0123456789abcdef", key, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+			t.Errorf("GET with API key %q status = %d", key, resp.StatusCode)
+		}
+	}
+
+	fields := map[string]string{"type": "zygosity", "position": "15:28365618"}
+	resp := tenantRequest(t, http.MethodPost, server.URL+"/proofs", "acme-key", fields)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST /proofs status = %d", resp.StatusCode)
+	}
+	var record ProofRecord
+	decodeBody(t, resp, &record)
+	if _, err := os.Stat(filepath.Join(dir, "acme", record.ID)); err != nil {
+		t.Errorf("proof is not stored under its tenant's directory: %v", err)
+	}
+	resp = tenantRequest(t, http.MethodPost, server.URL+"/proofs", "acme-key", fields)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("POST over the pending quota status = %d", resp.StatusCode)
+	}
+	// The quota is checked before the upload is read.
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/proofs", strings.NewReader("not a form"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer acme-key")
+	if resp, err = http.DefaultClient.Do(req); err != nil {
+		t.Fatalf("POST /proofs: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("POST of an unreadable form over the pending quota status = %d", resp.StatusCode)
+	}
+
+	// Another tenant cannot see or cancel the proof.
+	resp = tenantRequest(t, http.MethodGet, server.URL+"/proofs/"+record.ID, "globex-key", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET of another tenant's proof status = %d", resp.StatusCode)
+	}
+	resp = tenantRequest(t, http.MethodDelete, server.URL+"/proofs/"+record.ID, "globex-key", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("DELETE of another tenant's proof status = %d", resp.StatusCode)
+	}
+	resp = tenantRequest(t, http.MethodGet, server.URL+"/proofs/"+record.ID, "acme-key", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET of the tenant's own proof status = %d", resp.StatusCode)
+	}
+
+	// globex has used two requests of its two a minute.
+	resp = tenantRequest(t, http.MethodGet, server.URL+"/proofs/"+record.ID, "globex-key", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("GET over the rate limit status = %d, Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+
+	// A cancelled proof frees its tenant's pending quota.
+	resp = tenantRequest(t, http.MethodDelete, server.URL+"/proofs/"+record.ID, "acme-key", nil)
+	resp.Body.Close()
+	for {
+		resp = tenantRequest(t, http.MethodGet, server.URL+"/proofs/"+record.ID, "acme-key", nil)
+		decodeBody(t, resp, &record)
+		if record.Status != ProofPending {
+			break
+		}
+	}
+	resp = tenantRequest(t, http.MethodPost, server.URL+"/proofs", "acme-key", fields)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST once the pending proof failed status = %d", resp.StatusCode)
+	}
+	decodeBody(t, resp, &record)
+	resp = tenantRequest(t, http.MethodDelete, server.URL+"/proofs/"+record.ID, "acme-key", nil)
+	resp.Body.Close()
+}
+
+func TestReadTenants(t *testing.T) {
+	hash := HashAPIKey("key")
+	tests := []struct {
+		name, json, err string
+	}{
+		{"valid", `[{"id": "acme", "api_keys": ["` + hash + `"], "requests_per_minute": 60}]`, ""},
+		{"bad ID", `[{"id": "../acme", "api_keys": ["` + hash + `"]}]`, "invalid tenant ID"},
+		{"no keys", `[{"id": "acme"}]`, "no API keys"},
+		{"raw key", `[{"id": "acme", "api_keys": ["vcfp_secret"]}]`, "not a hex SHA-256"},
+		{"shared key", `[{"id": "acme", "api_keys": ["` + hash + `"]}, {"id": "globex", "api_keys": ["` + hash + `"]}]`, "share an API key"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "tenants.json")
+		if err := os.WriteFile(path, []byte(tt.json), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ReadTenants(path)
+		if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%s: ReadTenants = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+
+	key := filepath.Join(t.TempDir(), "acme")
+	hash, err := GenerateAPIKey(key)
+	if err != nil {
+		t.Fatalf("GenerateAPIKey: %v", err)
+	}
+	data, err := os.ReadFile(key + APIKeySuffix)
+	if err != nil || HashAPIKey(strings.TrimSpace(string(data))) != hash {
+		t.Errorf("API key file does not hash to %s: %v", hash, err)
+	}
+}
+
+func TestProofServerKeyFile(t *testing.T) {
+	keyDir := t.TempDir()
+	for _, path := range []string{"membership.vk", "globex/membership.vk"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(keyDir, path)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(keyDir, path), []byte("key"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := &ProofServer{Dir: t.TempDir(), KeyDir: keyDir, Tenants: []Tenant{
+		{ID: "acme", APIKeys: []string{HashAPIKey("acme-key")}},
+		{ID: "globex", APIKeys: []string{HashAPIKey("globex-key")}},
+	}}
+	acme := &tenantState{Tenant: srv.Tenants[0]}
+
+	if got, want := srv.keyFile(acme, "Membership", ".vk"), filepath.Join(keyDir, "membership.vk"); got != want {
+		t.Errorf("keyFile(acme, Membership) = %q, want the shared %q", got, want)
+	}
+	for _, proofType := range []string{"../globex/membership", "globex/../membership", `..\globex\membership`} {
+		if got := srv.keyFile(acme, proofType, ".vk"); got != "" {
+			t.Errorf("keyFile(acme, %q) = %q, want none", proofType, got)
+		}
+	}
+
+	// POST /verify rejects an unregistered type before looking up a key.
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("type", "../globex/membership")
+	part, _ := form.CreateFormFile("proof", "proof")
+	part.Write([]byte("not a proof"))
+	form.Close()
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/verify", &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer acme-key")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /verify: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST /verify of a type naming another tenant's key status = %d", resp.StatusCode)
+	}
+}
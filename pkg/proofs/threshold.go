@@ -0,0 +1,135 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// thresholdCountBits bounds the panel size and K of a threshold proof.
+const thresholdCountBits = 16
+
+// ThresholdCircuit proves whether at least K variants of a public panel are
+// present, exposing only the outcome. The panel is fixed at compile time and
+// identified by the public PanelID.
+type ThresholdCircuit struct {
+	PanelID frontend.Variable `gnark:",public"`
+	K       frontend.Variable `gnark:",public"`
+	AtLeast frontend.Variable `gnark:",public"`
+
+	// Private inputs - 1 for each panel variant the subject carries
+	Present []frontend.Variable
+
+	Panel []VariantSpec `gnark:"-"`
+}
+
+// NewThresholdCircuit allocates a circuit for panel.
+func NewThresholdCircuit(panel []VariantSpec) *ThresholdCircuit {
+	return &ThresholdCircuit{Present: make([]frontend.Variable, len(panel)), Panel: panel}
+}
+
+func (c *ThresholdCircuit) Define(api frontend.API) error {
+	if len(c.Present) >= 1<<thresholdCountBits {
+		return fmt.Errorf("panel of %d variants is too large", len(c.Present))
+	}
+	api.AssertIsEqual(c.PanelID, panelID(c.Panel))
+
+	count := frontend.Variable(0)
+	for _, present := range c.Present {
+		api.AssertIsBoolean(present)
+		count = api.Add(count, present)
+	}
+
+	// K is public but must still be range checked for the comparison.
+	api.ToBinary(c.K, thresholdCountBits)
+	api.AssertIsEqual(isAtLeast(api, count, c.K, thresholdCountBits), c.AtLeast)
+
+	return nil
+}
+
+func (p *ThresholdProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+	if len(p.Panel) == 0 {
+		return fmt.Errorf("a variant panel is required")
+	}
+	if p.K < 1 || p.K > len(p.Panel) {
+		return fmt.Errorf("k must be between 1 and the panel size %d, got %d", len(p.Panel), p.K)
+	}
+
+	fmt.Printf("Reading %d panel variants from VCF...\n", len(p.Panel))
+	genotypes, err := extractGenotypes(vcfPath, p.Settings, p.Panel)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+
+	dosages, err := panelDosages(p.Panel, genotypes)
+	if err != nil {
+		return err
+	}
+
+	assignment := NewThresholdCircuit(p.Panel)
+	assignment.PanelID = panelID(p.Panel)
+	assignment.K = p.K
+	count := 0
+	for i, dosage := range dosages {
+		present := boolToInt(dosage > 0)
+		assignment.Present[i] = present
+		count += present
+	}
+	atLeast := count >= p.K
+	assignment.AtLeast = boolToInt(atLeast)
+
+	if err := p.Settings.prove(ctx, "threshold", NewThresholdCircuit(p.Panel), assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("threshold", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	if atLeast {
+		fmt.Printf("We have proven that at least %d of %d panel variants are present\n", p.K, len(p.Panel))
+	} else {
+		fmt.Printf("We have proven that fewer than %d of %d panel variants are present\n", p.K, len(p.Panel))
+	}
+	fmt.Println("without revealing which variants or how many.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *ThresholdProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 3 {
+		return false, withKind(ErrVerification, fmt.Errorf("threshold proof has %d public inputs, expected 3", len(public)))
+	}
+	if len(p.Panel) > 0 {
+		if public[0].Cmp(panelID(p.Panel)) != 0 {
+			return false, withKind(ErrVerification, fmt.Errorf("proof was made over a different variant panel"))
+		}
+		fmt.Println("Proof is bound to the given variant panel")
+	}
+	if p.K > 0 && public[1].Cmp(big.NewInt(int64(p.K))) != 0 {
+		return false, withKind(ErrVerification, fmt.Errorf("proof is for k = %s, not %d", public[1], p.K))
+	}
+	if public[2].Sign() != 0 {
+		fmt.Printf("At least %s panel variants are present\n", public[1])
+	} else {
+		fmt.Printf("Fewer than %s panel variants are present\n", public[1])
+	}
+
+	return verified, nil
+}
@@ -0,0 +1,101 @@
+package proofs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+func TestThresholdCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	panel := []VariantSpec{
+		mustParseVariant(t, "7:117199644:ATCT:A"),
+		mustParseVariant(t, "7:117227832:G:T"),
+		mustParseVariant(t, "17:41276044:ACT:A"),
+	}
+
+	assignment := func(k, atLeast int, present ...int) *ThresholdCircuit {
+		c := NewThresholdCircuit(panel)
+		c.PanelID = panelID(panel)
+		c.K = k
+		c.AtLeast = atLeast
+		for i, p := range present {
+			c.Present[i] = p
+		}
+		return c
+	}
+
+	tests := []struct {
+		name       string
+		assignment *ThresholdCircuit
+		valid      bool
+	}{
+		{"2 of 3 present, k=2", assignment(2, 1, 1, 0, 1), true},
+		{"1 of 3 present, k=2", assignment(2, 0, 0, 0, 1), true},
+		{"all present, k=3", assignment(3, 1, 1, 1, 1), true},
+		{"overclaim", assignment(2, 1, 0, 0, 1), false},
+		{"underclaim", assignment(2, 0, 1, 1, 0), false},
+		{"non-boolean presence", assignment(2, 1, 2, 0, 0), false},
+		{"wrapped k", assignment(-1, 1, 0, 0, 0), false},
+	}
+	for _, tt := range tests {
+		err := test.IsSolved(NewThresholdCircuit(panel), tt.assignment, field)
+		if tt.valid && err != nil {
+			t.Errorf("%s: rejected: %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+
+	other := assignment(2, 1, 1, 0, 1)
+	other.PanelID = panelID(panel[:2])
+	if err := test.IsSolved(NewThresholdCircuit(panel), other, field); err == nil {
+		t.Errorf("different panel ID accepted")
+	}
+}
+
+func TestThresholdProof(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+7	117199644	.	ATCT	A	60	PASS	.	GT	0/1
+17	41276044	.	ACT	A	60	PASS	.	GT	1/1
+`)
+	panel := []VariantSpec{
+		mustParseVariant(t, "7:117199644:ATCT:A"),
+		mustParseVariant(t, "7:117227832:G:T"),
+		mustParseVariant(t, "17:41276044:ACT:A"),
+	}
+
+	for k, want := range map[int]bool{2: true, 3: false} {
+		out := filepath.Join(t.TempDir(), "threshold_proof.bin")
+		proof := &ThresholdProof{Panel: panel, K: k}
+		if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+			t.Fatalf("k=%d: Generate: %v", k, err)
+		}
+		if verified, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !verified {
+			t.Fatalf("k=%d: Verify = %v, %v", k, verified, err)
+		}
+		public, err := readPublicInputs(out)
+		if err != nil {
+			t.Fatalf("readPublicInputs: %v", err)
+		}
+		if got := public[2].Sign() != 0; got != want {
+			t.Errorf("k=%d: at least = %v, want %v", k, got, want)
+		}
+
+		for _, other := range []*ThresholdProof{{Panel: panel[:2], K: k}, {Panel: panel, K: k - 1}} {
+			if ok, err := other.Verify(t.Context(), out+".vk", out); ok || !errors.Is(err, ErrVerification) {
+				t.Errorf("k=%d: Verify for %d panel variants and k=%d = %v, %v; want ErrVerification", k, len(other.Panel), other.K, ok, err)
+			}
+		}
+	}
+
+	if err := (&ThresholdProof{Panel: panel, K: 4}).Generate(t.Context(), vcf, "", filepath.Join(t.TempDir(), "p.bin")); err == nil {
+		t.Errorf("k larger than the panel should be rejected")
+	}
+}
@@ -0,0 +1,63 @@
+package proofs
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName names the OpenTelemetry tracer of the package's spans.
+const tracerName = "github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+
+// Span names of the stages of generating a proof, each a child of the
+// span of the context Generate was called with. Without a tracer provider
+// installed through otel.SetTracerProvider the spans are not recorded.
+const (
+	// SpanExtract covers validating the settings against the VCF and
+	// reading the genotypes the proof is over.
+	SpanExtract = "proofs.extract"
+	// SpanCompile covers compiling the circuit, or loading it from the
+	// circuit cache.
+	SpanCompile = "proofs.compile"
+	// SpanSetup covers running the setup or loading the keys, from a key
+	// file or the key cache.
+	SpanSetup = "proofs.setup"
+	// SpanProve covers proving the witness, on a prover daemon for a
+	// delegated proof.
+	SpanProve = "proofs.prove"
+	// SpanSerialize covers writing the proof file.
+	SpanSerialize = "proofs.serialize"
+)
+
+// startSpan starts a span named name as a child of ctx's span, through the
+// tracer provider installed when it is called.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, marking it failed when err is set.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceExtract records the span of reading the VCF for a proof of
+// proofType, from the time validate started to now. Proof types read the
+// VCF in their own ways between validate and prove, so the span is
+// recorded once the reading is over rather than threaded through them.
+func (s Settings) traceExtract(ctx context.Context, proofType string) {
+	if s.started.IsZero() {
+		return
+	}
+	_, span := otel.Tracer(tracerName).Start(ctx, SpanExtract,
+		trace.WithTimestamp(s.started),
+		trace.WithAttributes(attribute.String("proof.type", proofType)))
+	span.End(trace.WithTimestamp(time.Now()))
+}
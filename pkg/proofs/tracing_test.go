@@ -0,0 +1,93 @@
+package proofs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider recording the spans ended until
+// the test is over.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+	return recorder
+}
+
+func TestGenerateSpans(t *testing.T) {
+	recorder := recordSpans(t)
+	dir := t.TempDir()
+	vcf := filepath.Join(dir, "input.vcf")
+	if err := os.WriteFile(vcf, []byte(`##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, root := otel.Tracer("test").Start(t.Context(), "request")
+	err := Generate(ctx, "zygosity", vcf, filepath.Join(dir, "proof.bin"), WithPosition("15:28365618"))
+	root.End()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() == root.SpanContext().TraceID() {
+			spans[span.Name()] = span
+		}
+	}
+	generate, ok := spans["proofs.Generate"]
+	if !ok || generate.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Fatalf("no proofs.Generate span under the request's: %v", spans)
+	}
+	for _, name := range []string{SpanExtract, SpanCompile, SpanSetup, SpanProve, SpanSerialize} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("no %s span", name)
+			continue
+		}
+		if span.Parent().SpanID() != generate.SpanContext().SpanID() {
+			t.Errorf("%s span is not a child of proofs.Generate", name)
+		}
+		if span.EndTime().Before(span.StartTime()) {
+			t.Errorf("%s span ends before it starts", name)
+		}
+	}
+	if extract, prove := spans[SpanExtract], spans[SpanProve]; extract != nil && prove != nil && extract.EndTime().After(prove.StartTime()) {
+		t.Errorf("extraction ends after proving starts")
+	}
+}
+
+func TestProofServerContinuesTrace(t *testing.T) {
+	recorder := recordSpans(t)
+	req := httptest.NewRequest(http.MethodPost, "/verify", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	(&ProofServer{Dir: t.TempDir()}).Handler().ServeHTTP(rec, req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "POST /verify" || span.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || span.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("span %s in trace %s under %s does not continue the request's trace", span.Name(), span.SpanContext().TraceID(), span.Parent().SpanID())
+	}
+}
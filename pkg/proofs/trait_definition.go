@@ -0,0 +1,192 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TraitDefinition declares a trait predicted from a few SNPs as data: the
+// loci, the decision table mapping their genotypes to a phenotype and the
+// phenotypes' names. RegisterTrait makes it a proof type whose circuit, VCF
+// extraction and witness all follow from the definition, so a new simple
+// SNP trait needs no code of its own.
+type TraitDefinition struct {
+	// Name is the proof type name, such as "eyecolor".
+	Name        string
+	Description string
+	// Trait holds the loci and the decision table; its Name is used in
+	// messages.
+	Trait CompositeTrait
+	// Output describes the public phenotype output, such as "Predicted eye
+	// color".
+	Output string
+	// Phenotypes names each phenotype value the decision table holds.
+	Phenotypes map[int]string
+	// Standalone is set when the trait is proved from a VCF of SNV calls
+	// with no other options, as "all" proves.
+	Standalone bool
+}
+
+// traitDefinitions holds the definitions registered with RegisterTrait by
+// proof type name.
+var traitDefinitions = map[string]TraitDefinition{
+	"eyecolor":  eyeColorDefinition,
+	"bloodtype": bloodTypeDefinition,
+}
+
+// Definitions of the built-in composite traits.
+var (
+	eyeColorDefinition = TraitDefinition{
+		Name:        "eyecolor",
+		Description: "Eye color from HERC2 and SLC45A2 genotypes",
+		Trait:       EyeColorTrait,
+		Output:      "Predicted eye color",
+		Phenotypes: map[int]string{
+			EyeColorBrown:        EyeColorName(EyeColorBrown),
+			EyeColorIntermediate: EyeColorName(EyeColorIntermediate),
+			EyeColorBlue:         EyeColorName(EyeColorBlue),
+		},
+		Standalone: true,
+	}
+	bloodTypeDefinition = TraitDefinition{
+		Name:        "bloodtype",
+		Description: "ABO group and RhD status",
+		Trait:       BloodTypeTrait,
+		Output:      "Blood type",
+		Phenotypes:  bloodTypeNames(),
+		Standalone:  true,
+	}
+)
+
+func bloodTypeNames() map[int]string {
+	names := map[int]string{}
+	for group := BloodGroupO; group <= BloodGroupAB; group++ {
+		for _, rhPositive := range []bool{false, true} {
+			code := bloodTypeCode(group, rhPositive)
+			names[code] = BloodTypeName(code)
+		}
+	}
+	return names
+}
+
+// validate checks that the definition names a proof type, that its decision
+// table covers every genotype combination and that every phenotype it
+// holds has a name.
+func (d TraitDefinition) validate() error {
+	if strings.TrimSpace(d.Name) == "" {
+		return fmt.Errorf("trait definition has no name")
+	}
+	if err := d.Trait.validate(); err != nil {
+		return err
+	}
+	for _, phenotype := range d.Trait.Table {
+		if _, ok := d.Phenotypes[phenotype]; phenotype != 0 && !ok {
+			return fmt.Errorf("trait %s decision table holds phenotype %d, which has no name", d.Name, phenotype)
+		}
+	}
+	return nil
+}
+
+// PhenotypeName returns the name of a phenotype value of the trait.
+func (d TraitDefinition) PhenotypeName(phenotype int) string {
+	if name, ok := d.Phenotypes[phenotype]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// publicInputs describes the public inputs of the trait's circuit, naming
+// each phenotype value in the output's description.
+func (d TraitDefinition) publicInputs() []PublicInput {
+	values := make([]int, 0, len(d.Phenotypes))
+	for phenotype := range d.Phenotypes {
+		values = append(values, phenotype)
+	}
+	slices.Sort(values)
+	names := make([]string, len(values))
+	for i, phenotype := range values {
+		names[i] = fmt.Sprintf("%d %s", phenotype, d.Phenotypes[phenotype])
+	}
+	return compositeTraitInputs("phenotype", fmt.Sprintf("%s (%s)", d.Output, strings.Join(names, ", ")), d.Trait)
+}
+
+// ProofType returns the proof type proving the trait with a TraitProof.
+func (d TraitDefinition) ProofType() ProofType {
+	return ProofType{
+		Name:         d.Name,
+		Description:  d.Description,
+		Fields:       []string{"CHROM", "POS", "REF", "ALT", "GT"},
+		PublicInputs: d.publicInputs(),
+		Standalone:   d.Standalone,
+		New: func(cfg ProofConfig) Proof {
+			return &TraitProof{Settings: cfg.Settings, Definition: d}
+		},
+	}
+}
+
+// RegisterTrait checks d and registers it as a proof type, replacing any
+// proof type of the same name. Like RegisterProofType it is not safe for
+// concurrent use, so call it during initialization.
+func RegisterTrait(d TraitDefinition) error {
+	if err := d.validate(); err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+	RegisterProofType(d.ProofType())
+	traitDefinitions[d.Name] = d
+	return nil
+}
+
+// TraitProof proves the phenotype a TraitDefinition predicts from the
+// genotypes at its loci.
+type TraitProof struct {
+	Proof
+	Settings
+	Definition TraitDefinition
+}
+
+func (p *TraitProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	return generateTrait(ctx, p.Definition, p.Settings, vcfPath, provingKeyPath, outputPath)
+}
+
+func (p *TraitProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	return p.verifyTrait(ctx, p.Definition, verifyingKeyPath, proofPath)
+}
+
+// generateTrait proves the phenotype d predicts from the VCF and writes
+// the proof's metadata.
+func generateTrait(ctx context.Context, d TraitDefinition, settings Settings, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := settings.validate(vcfPath); err != nil {
+		return err
+	}
+	if err := d.validate(); err != nil {
+		return withKind(ErrInvalidInput, err)
+	}
+
+	phenotype, err := proveCompositeTrait(ctx, d.Trait, d.Name, vcfPath, settings, provingKeyPath, outputPath)
+	if err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata(d.Name, settings)); err != nil {
+		return err
+	}
+
+	settings.printGenerated()
+	fmt.Printf("We have proven the %s is %s\n", strings.ToLower(d.Output), d.PhenotypeName(phenotype))
+	fmt.Println("without revealing the genotype at any of the contributing SNPs.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+// verifyTrait verifies a proof of the trait d defines.
+func (s Settings) verifyTrait(ctx context.Context, d TraitDefinition, verifyingKeyPath string, proofPath string) (bool, error) {
+	phenotype, err := s.verifyCompositeTrait(ctx, d.Trait, verifyingKeyPath, proofPath)
+	if err != nil {
+		return false, err
+	}
+	fmt.Printf("%s: %s\n", d.Output, d.PhenotypeName(phenotype))
+	return true, nil
+}
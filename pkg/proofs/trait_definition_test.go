@@ -0,0 +1,65 @@
+package proofs
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegisterTrait(t *testing.T) {
+	registered, definitions := proofTypes, traitDefinitions
+	t.Cleanup(func() { proofTypes, traitDefinitions = registered, definitions })
+	proofTypes = append([]ProofType(nil), registered...)
+	traitDefinitions = map[string]TraitDefinition{}
+
+	lactase := TraitDefinition{
+		Name:   "lactase",
+		Trait:  CompositeTrait{Name: "lactase persistence", SNPs: []VariantSpec{{Chromosome: "2", Position: 136608646, Ref: "G", Alt: "A", RSID: "rs4988235"}}, Table: []int{1, 2, 2}},
+		Output: "Lactase persistence",
+		Phenotypes: map[int]string{
+			1: "non-persistent",
+			2: "persistent",
+		},
+	}
+	if err := RegisterTrait(lactase); err != nil {
+		t.Fatalf("RegisterTrait: %v", err)
+	}
+	pt, ok := LookupProofType("lactase")
+	if !ok || len(pt.PublicInputs) != 2 || !strings.Contains(pt.PublicInputs[0].Description, "2 persistent") {
+		t.Fatalf("registered proof type = %+v, %v", pt, ok)
+	}
+
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+2	136608646	.	G	A	60	PASS	.	GT	0/1
+`)
+	out := filepath.Join(t.TempDir(), "lactase_proof.bin")
+	if err := Generate(t.Context(), "lactase", vcf, out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	res, err := Verify(t.Context(), out, WithPublicInputs(big.NewInt(2)))
+	if err != nil || !res.Valid {
+		t.Fatalf("Verify = %+v, %v", res, err)
+	}
+	summary, err := InspectProof(out)
+	if err != nil {
+		t.Fatalf("InspectProof: %v", err)
+	}
+	if strings.Join(summary.Claims, "; ") != "Lactase persistence: persistent" {
+		t.Errorf("claims = %q", summary.Claims)
+	}
+
+	unnamed := lactase
+	unnamed.Phenotypes = map[int]string{1: "non-persistent"}
+	if err := RegisterTrait(unnamed); !errors.Is(ErrorKind(err), ErrInvalidInput) {
+		t.Errorf("RegisterTrait of a definition with an unnamed phenotype: %v", err)
+	}
+	short := lactase
+	short.Trait.Table = []int{1, 2}
+	if err := RegisterTrait(short); !errors.Is(ErrorKind(err), ErrInvalidInput) {
+		t.Errorf("RegisterTrait of a definition with a short table: %v", err)
+	}
+}
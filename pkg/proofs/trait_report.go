@@ -0,0 +1,142 @@
+package proofs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TraitReportName is the file ProveTraits writes its report to in the
+// output directory.
+const TraitReportName = "trait_report.json"
+
+// Statuses of a trait in a TraitReport.
+const (
+	TraitProved      = "proved"
+	TraitNotProvable = "not-provable"
+	TraitFailed      = "failed"
+)
+
+// TraitResult is a trait's entry in a TraitReport. It names the trait's
+// proof but, unlike a TraitCheck, never the genotype behind it.
+type TraitResult struct {
+	Trait     string `json:"trait"`
+	Gene      string `json:"gene"`
+	RSID      string `json:"rsid,omitempty"`
+	Variant   string `json:"variant"`
+	Status    string `json:"status"`
+	ProofType string `json:"proof_type,omitempty"`
+	Proof     string `json:"proof,omitempty"`
+	// Reason says why the trait was not provable or its proof failed.
+	Reason string `json:"reason,omitempty"`
+}
+
+// TraitReport lists a proof, or why there is none, for each trait of a
+// panel.
+type TraitReport struct {
+	ProofType string        `json:"proof_type"`
+	CreatedAt time.Time     `json:"created_at"`
+	Results   []TraitResult `json:"results"`
+}
+
+// TraitProofTypes are the proof types ProveTraits proves a trait with: a
+// threshold proof that at least one copy of the trait's alt is carried,
+// revealing only whether it is, or a dosage proof of how many copies are.
+var TraitProofTypes = []string{"threshold", "dosage"}
+
+// ProveTraits proves each trait of panel from the VCF at vcfPath with a
+// proof of proofType over the trait's variant alone, written to outputDir
+// as <gene>_<rsid or chrom_pos>_proof.bin beside its metadata and keys,
+// and writes a TraitReport of the outcome to TraitReportName in outputDir,
+// which, like the proofs, is only overwritten when cfg.Settings.Force is set.
+// The genotypes are read in one pass, as ProofConfig.ShareExtraction
+// describes, and traits CheckTraits finds unprovable are reported without
+// a proof. The proofs are generated on workers as GenerateAll does.
+func ProveTraits(ctx context.Context, vcfPath string, panel []TraitVariant, cfg ProofConfig, proofType string, outputDir string, workers int) (*TraitReport, error) {
+	proofType = strings.ToLower(proofType)
+	if !slices.Contains(TraitProofTypes, proofType) {
+		return nil, withKind(ErrInvalidInput, fmt.Errorf("traits are proved with %s proofs, not %q", strings.Join(TraitProofTypes, " or "), proofType))
+	}
+
+	reportPath := filepath.Join(outputDir, TraitReportName)
+	if !cfg.Settings.Force {
+		if err := refuseOverwrite(reportPath); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.Panel = nil
+	for _, t := range panel {
+		cfg.Panel = append(cfg.Panel, t.Spec())
+	}
+	cfg.ShareExtraction()
+	checks, err := CheckTraits(vcfPath, cfg.Settings, panel)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TraitReport{ProofType: proofType, CreatedAt: time.Now().UTC()}
+	var jobs []GenerateJob
+	var proved []int
+	for i, check := range checks {
+		result := TraitResult{Trait: check.Trait, Gene: check.Gene, RSID: check.RSID, Variant: check.Variant, Status: TraitNotProvable, Reason: check.Reason}
+		if check.Provable {
+			spec, err := ParseVariantSpec(check.Variant)
+			if err != nil {
+				return nil, err
+			}
+			spec.RSID = check.RSID
+			traitCfg := cfg
+			traitCfg.Panel, traitCfg.K = []VariantSpec{spec}, 1
+			proof, err := NewProof(proofType, traitCfg)
+			if err != nil {
+				return nil, err
+			}
+			result.ProofType = proofType
+			result.Proof = filepath.Join(outputDir, traitFileName(check)+"_proof.bin")
+			jobs = append(jobs, GenerateJob{Proof: proof, VCF: vcfPath, Output: result.Proof})
+			proved = append(proved, i)
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	if len(jobs) > 0 {
+		errs, err := GenerateAll(ctx, jobs, workers)
+		if err != nil {
+			return nil, err
+		}
+		for k, i := range proved {
+			if errs[k] != nil {
+				report.Results[i].Status, report.Results[i].Reason = TraitFailed, errs[k].Error()
+				continue
+			}
+			report.Results[i].Status = TraitProved
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding trait report: %w", err)
+	}
+	if err := writeFileAtomic(reportPath, 0644, writeBytes(append(data, '\n'))); err != nil {
+		return nil, fmt.Errorf("writing trait report: %w", err)
+	}
+	return report, nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// traitFileName names a trait's proof after its gene and its rsID, or its
+// locus when it has none, which the panel keeps unique.
+func traitFileName(check TraitCheck) string {
+	id := check.RSID
+	if id == "" {
+		id = check.Variant
+	}
+	return strings.Trim(unsafeNameChars.ReplaceAllString(strings.ToLower(check.Gene+"_"+id), "_"), "_")
+}
@@ -0,0 +1,64 @@
+package proofs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProveTraits(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	rs12913832	A	G	60	PASS	.	GT	1|0
+16	89919709	.	C	T	60	PASS	.	GT	./.
+`)
+	panel := []TraitVariant{
+		{Trait: "Eye color", Gene: "HERC2", RSID: "rs12913832", Chromosome: 15, Position: 28365618, Ref: "A", Alt: "G"},
+		{Trait: "Red hair", Gene: "MC1R", Chromosome: 16, Position: 89919709, Ref: "C", Alt: "T"},
+	}
+	dir := t.TempDir()
+
+	report, err := ProveTraits(t.Context(), vcf, panel, ProofConfig{}, "threshold", dir, 2)
+	if err != nil {
+		t.Fatalf("ProveTraits: %v", err)
+	}
+	if len(report.Results) != 2 {
+		t.Fatalf("report has %d results, want 2", len(report.Results))
+	}
+
+	eye := report.Results[0]
+	if eye.Status != TraitProved || eye.Proof != filepath.Join(dir, "herc2_rs12913832_proof.bin") {
+		t.Fatalf("called variant: %+v", eye)
+	}
+	spec := panel[0].Spec()
+	proof := &ThresholdProof{Panel: []VariantSpec{spec}, K: 1}
+	if ok, err := proof.Verify(t.Context(), eye.Proof+".vk", eye.Proof); err != nil || !ok {
+		t.Errorf("Verify(%s) = %v, %v", eye.Proof, ok, err)
+	}
+	if red := report.Results[1]; red.Status != TraitNotProvable || red.Proof != "" || red.Reason == "" {
+		t.Errorf("no-call under the fail policy: %+v", red)
+	}
+
+	// The report names proofs, never the genotypes behind them.
+	data, err := os.ReadFile(filepath.Join(dir, TraitReportName))
+	if err != nil {
+		t.Fatalf("reading the report: %v", err)
+	}
+	if strings.Contains(string(data), "G|A") {
+		t.Errorf("report reveals a genotype: %s", data)
+	}
+	var read TraitReport
+	if err := json.Unmarshal(data, &read); err != nil || len(read.Results) != 2 {
+		t.Errorf("report does not read back: %v", err)
+	}
+
+	if _, err := ProveTraits(t.Context(), vcf, panel, ProofConfig{}, "threshold", dir, 2); err == nil {
+		t.Errorf("ProveTraits should not overwrite an existing report without Force")
+	}
+	if _, err := ProveTraits(t.Context(), vcf, panel, ProofConfig{}, "eyecolor", t.TempDir(), 2); ErrorKind(err) != ErrInvalidInput {
+		t.Errorf("ProveTraits with an eyecolor proof = %v, want an invalid input error", err)
+	}
+}
@@ -0,0 +1,196 @@
+package proofs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type TraitRegion struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type TraitVariant struct {
+	Trait      string      `json:"trait"`
+	Gene       string      `json:"gene"`
+	RSID       string      `json:"rsid,omitempty"`
+	Chromosome int         `json:"chromosome"`
+	Position   int         `json:"position"`
+	Region     TraitRegion `json:"region"`
+	Ref        string      `json:"ref"`
+	Alt        string      `json:"alt"`
+}
+
+type TraitPanel struct{}
+
+// Spec returns the panel entry as a variant.
+func (t TraitVariant) Spec() VariantSpec {
+	return VariantSpec{
+		Chromosome: strconv.Itoa(t.Chromosome),
+		Position:   uint64(t.Position),
+		Ref:        strings.ToUpper(t.Ref),
+		Alt:        strings.ToUpper(t.Alt),
+		RSID:       t.RSID,
+	}
+}
+
+// LoadTraitPanel reads a trait panel such as panels_traits.json.
+func LoadTraitPanel(path string) ([]TraitVariant, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading trait panel: %w", err)
+	}
+
+	var variants []TraitVariant
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return nil, fmt.Errorf("parsing trait panel: %w", err)
+	}
+	return variants, nil
+}
+
+// WriteTraitPanel writes a trait panel in the layout of panels_traits.json,
+// after checking it with ValidateTraitPanel.
+func WriteTraitPanel(path string, variants []TraitVariant) error {
+	if err := ValidateTraitPanel(variants); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(variants); err != nil {
+		return fmt.Errorf("encoding trait panel: %w", err)
+	}
+	if err := writeFileAtomic(path, 0644, writeBytes(buf.Bytes())); err != nil {
+		return fmt.Errorf("writing trait panel: %w", err)
+	}
+	return nil
+}
+
+var (
+	rsidPattern   = regexp.MustCompile(`^rs[0-9]+$`)
+	allelePattern = regexp.MustCompile(`^[ACGTNacgtn]+$`)
+)
+
+// Validate checks the entry against the panel schema: a named trait and
+// gene, an autosomal position within its region, and distinct REF and ALT
+// bases.
+func (t TraitVariant) Validate() error {
+	var problems []string
+	if strings.TrimSpace(t.Trait) == "" {
+		problems = append(problems, "trait is empty")
+	}
+	if strings.TrimSpace(t.Gene) == "" {
+		problems = append(problems, "gene is empty")
+	}
+	if t.RSID != "" && !rsidPattern.MatchString(t.RSID) {
+		problems = append(problems, fmt.Sprintf("rsid %q is not of the form rs<number>", t.RSID))
+	}
+	if t.Chromosome < 1 || t.Chromosome > 22 {
+		problems = append(problems, fmt.Sprintf("chromosome %d is not an autosome (1-22)", t.Chromosome))
+	}
+	if t.Position < 1 {
+		problems = append(problems, fmt.Sprintf("position %d is not positive", t.Position))
+	}
+	if t.Region.Start < 1 || t.Region.Start > t.Region.End {
+		problems = append(problems, fmt.Sprintf("region %d-%d is empty", t.Region.Start, t.Region.End))
+	} else if t.Position < t.Region.Start || t.Position > t.Region.End {
+		problems = append(problems, fmt.Sprintf("position %d is outside region %d-%d", t.Position, t.Region.Start, t.Region.End))
+	}
+	for _, allele := range []struct{ name, bases string }{{"ref", t.Ref}, {"alt", t.Alt}} {
+		if !allelePattern.MatchString(allele.bases) {
+			problems = append(problems, fmt.Sprintf("%s %q is not a sequence of A, C, G, T and N", allele.name, allele.bases))
+		}
+	}
+	if t.Ref != "" && strings.EqualFold(t.Ref, t.Alt) {
+		problems = append(problems, "ref and alt are the same")
+	}
+	if len(problems) > 0 {
+		return withKind(ErrInvalidInput, fmt.Errorf("trait panel entry %q: %s", t.Trait, strings.Join(problems, "; ")))
+	}
+	return nil
+}
+
+// ValidateTraitPanel checks each entry with Validate, and that no variant
+// is listed twice.
+func ValidateTraitPanel(variants []TraitVariant) error {
+	var errs []error
+	seen := make(map[string]string)
+	for _, v := range variants {
+		if err := v.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		id := v.Spec().String()
+		if trait, ok := seen[id]; ok {
+			errs = append(errs, withKind(ErrInvalidInput, fmt.Errorf("trait panel lists %s for both %q and %q", id, trait, v.Trait)))
+		}
+		seen[id] = v.Trait
+	}
+	return errors.Join(errs...)
+}
+
+// Matches reports whether query names the entry: its trait, gene or rsID,
+// ignoring case, or its variant as chrom:pos or chrom:pos:ref:alt.
+func (t TraitVariant) Matches(query string) bool {
+	query = strings.TrimSpace(query)
+	if strings.EqualFold(query, t.Trait) || strings.EqualFold(query, t.Gene) || (t.RSID != "" && strings.EqualFold(query, t.RSID)) {
+		return true
+	}
+	spec := t.Spec()
+	if locus, err := ParseLocus(query); err == nil && locus == spec.Locus() {
+		return true
+	}
+	v, err := ParseVariantSpec(query)
+	return err == nil && v.String() == spec.normalized().String()
+}
+
+// PanelVariants returns the panel entries for gene as variants, or every
+// entry when gene is empty.
+func PanelVariants(variants []TraitVariant, gene string) ([]VariantSpec, error) {
+	var specs []VariantSpec
+	for _, v := range variants {
+		if gene == "" || strings.EqualFold(v.Gene, gene) {
+			specs = append(specs, v.Spec())
+		}
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("gene %s is not in the trait panel", gene)
+	}
+	return specs, nil
+}
+
+// GeneRegion returns the span covering every panel region listed for gene.
+func GeneRegion(variants []TraitVariant, gene string) (GenomicRegion, error) {
+	var region GenomicRegion
+	found := false
+	for _, v := range variants {
+		if !strings.EqualFold(v.Gene, gene) {
+			continue
+		}
+
+		chrom := strconv.Itoa(v.Chromosome)
+		if !found {
+			region = GenomicRegion{Chromosome: chrom, Start: uint64(v.Region.Start), End: uint64(v.Region.End)}
+			found = true
+			continue
+		}
+		if chrom != region.Chromosome {
+			return GenomicRegion{}, fmt.Errorf("gene %s spans chromosomes %s and %s in the panel", gene, region.Chromosome, chrom)
+		}
+		region.Start = min(region.Start, uint64(v.Region.Start))
+		region.End = max(region.End, uint64(v.Region.End))
+	}
+
+	if !found {
+		return GenomicRegion{}, fmt.Errorf("gene %s is not in the trait panel", gene)
+	}
+	return region, nil
+}
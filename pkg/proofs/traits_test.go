@@ -0,0 +1,61 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteTraitPanel(t *testing.T) {
+	panel, err := LoadTraitPanel("../../panels_traits.json")
+	if err != nil {
+		t.Fatalf("LoadTraitPanel: %v", err)
+	}
+	if err := ValidateTraitPanel(panel); err != nil {
+		t.Fatalf("panels_traits.json does not validate: %v", err)
+	}
+
+	lactase := TraitVariant{
+		Trait: "Lactase Persistence", Gene: "MCM6", RSID: "rs4988235",
+		Chromosome: 2, Position: 136608646, Region: TraitRegion{Start: 136608000, End: 136609000},
+		Ref: "G", Alt: "A",
+	}
+	path := filepath.Join(t.TempDir(), "panel.json")
+	if err := WriteTraitPanel(path, append(panel, lactase)); err != nil {
+		t.Fatalf("WriteTraitPanel: %v", err)
+	}
+	read, err := LoadTraitPanel(path)
+	if err != nil {
+		t.Fatalf("LoadTraitPanel: %v", err)
+	}
+	if len(read) != len(panel)+1 || read[len(panel)] != lactase {
+		t.Errorf("read back %d entries ending %+v", len(read), read[len(read)-1])
+	}
+
+	for _, query := range []string{"lactase persistence", "mcm6", "rs4988235", "2:136608646", "chr2:136608646:g:a"} {
+		if !lactase.Matches(query) {
+			t.Errorf("entry should match %q", query)
+		}
+	}
+	if lactase.Matches("2:136608646:G:T") {
+		t.Errorf("entry should not match another alt")
+	}
+
+	duplicate := lactase
+	duplicate.Trait = "Lactose Tolerance"
+	if err := WriteTraitPanel(path, append(panel, lactase, duplicate)); err == nil {
+		t.Errorf("panel listing a variant twice should not be written")
+	}
+
+	invalid := []TraitVariant{
+		{Gene: "MCM6", Chromosome: 2, Position: 10, Region: TraitRegion{Start: 1, End: 20}, Ref: "G", Alt: "A"},
+		{Trait: "X", Gene: "MCM6", Chromosome: 23, Position: 10, Region: TraitRegion{Start: 1, End: 20}, Ref: "G", Alt: "A"},
+		{Trait: "X", Gene: "MCM6", Chromosome: 2, Position: 30, Region: TraitRegion{Start: 1, End: 20}, Ref: "G", Alt: "A"},
+		{Trait: "X", Gene: "MCM6", Chromosome: 2, Position: 10, Region: TraitRegion{Start: 1, End: 20}, Ref: "G", Alt: "<DEL>"},
+		{Trait: "X", Gene: "MCM6", RSID: "4988235", Chromosome: 2, Position: 10, Region: TraitRegion{Start: 1, End: 20}, Ref: "G", Alt: "A"},
+	}
+	for _, v := range invalid {
+		if err := v.Validate(); err == nil || ErrorKind(err) != ErrInvalidInput {
+			t.Errorf("Validate(%+v) = %v, want an invalid input error", v, err)
+		}
+	}
+}
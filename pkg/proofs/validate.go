@@ -0,0 +1,287 @@
+package proofs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/brentp/vcfgo"
+)
+
+// Severities of validation issues. Errors make proof generation fail or
+// prove the wrong thing; warnings may be harmless depending on the proof.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ValidationIssue is one kind of problem found in a VCF. Problems found in
+// many records are reported once, with the first record's line and a count.
+type ValidationIssue struct {
+	// Check names the check that found the issue: header, samples, gt,
+	// genotype, contig, order, limits, build or coverage.
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// Line is the first line the issue was found on, zero for issues with
+	// the file as a whole.
+	Line int64 `json:"line,omitempty"`
+	// Example is the offending value on that line.
+	Example string `json:"example,omitempty"`
+	// Count is the number of records with the issue.
+	Count int `json:"count,omitempty"`
+}
+
+// Coverage statuses of a panel locus.
+const (
+	CoverageCalled      = "called"
+	CoverageNoCall      = "no-call"
+	CoverageMissing     = "missing"
+	CoverageRefMismatch = "ref-mismatch"
+)
+
+// LocusCoverage is whether the VCF has a usable call at a panel variant's
+// locus. It never includes the genotype itself.
+type LocusCoverage struct {
+	Variant string `json:"variant"`
+	Status  string `json:"status"`
+	// Ref is the VCF's reference allele when it differs from the panel's,
+	// which usually means the VCF is on a different genome build.
+	Ref string `json:"ref,omitempty"`
+}
+
+// ValidationReport is the machine-readable result of ValidateVCF.
+type ValidationReport struct {
+	VCF string `json:"vcf"`
+	// Valid is false when any issue is an error.
+	Valid   bool     `json:"valid"`
+	Samples []string `json:"samples"`
+	// Build is the genome build DetectBuild found in the header, if any.
+	Build   GenomeBuild       `json:"build,omitempty"`
+	Records int               `json:"records"`
+	Issues  []ValidationIssue `json:"issues"`
+	Panel   []LocusCoverage   `json:"panel,omitempty"`
+}
+
+// WriteValidationReport writes the report as indented JSON.
+func WriteValidationReport(path string, report *ValidationReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// validation collects a report's issues, merging repeats of the same kind.
+type validation struct {
+	report *ValidationReport
+	seen   map[string]int
+}
+
+func (v *validation) add(check, severity string, line int64, message, example string) {
+	key := check + "\x00" + message
+	if i, ok := v.seen[key]; ok {
+		v.report.Issues[i].Count++
+		return
+	}
+	v.seen[key] = len(v.report.Issues)
+	v.report.Issues = append(v.report.Issues, ValidationIssue{
+		Check: check, Severity: severity, Message: message, Line: line, Example: example, Count: 1,
+	})
+}
+
+// fileIssue records an issue with the file as a whole.
+func (v *validation) fileIssue(check, severity, message string) {
+	v.report.Issues = append(v.report.Issues, ValidationIssue{Check: check, Severity: severity, Message: message})
+}
+
+// vcfErrors records the errors vcfgo collected while parsing.
+func (v *validation) vcfErrors(rdr *vcfgo.Reader, check string, severity string) {
+	if verr, ok := rdr.Error().(*vcfgo.VCFError); ok {
+		for i, msg := range verr.Msgs {
+			v.add(check, severity, verr.Lines[i], "VCF parser error", msg)
+		}
+	}
+	rdr.Clear()
+}
+
+// ValidateVCF checks the VCF at vcfPath before proving: that its header
+// parses and declares GT, that every record parses with a GT call for each
+// sample, that contigs are consistently named primary chromosomes in
+// sorted order, and that the first sample has calls at each panel
+// variant, found by rsID or locus. Only an unreadable file is returned as
+// an error; every problem with its contents is an issue in the report.
+func ValidateVCF(vcfPath string, panel []VariantSpec, limits ReadLimits) (*ValidationReport, error) {
+	report := &ValidationReport{VCF: vcfPath, Samples: []string{}, Issues: []ValidationIssue{}}
+	v := &validation{report: report, seen: map[string]int{}}
+
+	stream, err := OpenVCFStream(vcfPath)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	limited := &lineLimiter{r: stream, maxLine: limits.MaxLineBytes}
+	rdr, err := vcfgo.NewReader(limited, false)
+	if rdr == nil {
+		if limited.err != nil {
+			err = limited.err
+		}
+		v.fileIssue("header", SeverityError, fmt.Sprintf("header does not parse: %v", err))
+		return v.finish(), nil
+	}
+	v.vcfErrors(rdr, "header", SeverityError)
+	records := &vcfReader{Reader: rdr, stream: limited, limits: limits}
+
+	header := rdr.Header
+	report.Samples = append(report.Samples, header.SampleNames...)
+	if header.FileFormat == "" {
+		v.fileIssue("header", SeverityWarning, "missing ##fileformat line")
+	}
+	if len(header.SampleNames) == 0 {
+		v.fileIssue("samples", SeverityWarning, "no sample columns; only membership, absence and region proofs read sites-only VCFs")
+	} else if _, ok := header.SampleFormats["GT"]; !ok {
+		v.fileIssue("gt", SeverityWarning, "header does not declare FORMAT/GT")
+	}
+	report.Build = DetectBuild(header)
+	if report.Build != "" && report.Build != PanelBuild {
+		v.fileIssue("build", SeverityWarning, fmt.Sprintf("VCF is on %s but panel positions are on %s; generate with a -liftover chain file", report.Build, PanelBuild))
+	}
+
+	declared := map[string]bool{}
+	for _, contig := range header.Contigs {
+		declared[contig["ID"]] = true
+	}
+
+	lookup := lookupSpecs(panel)
+	report.Panel = make([]LocusCoverage, len(panel))
+	for i, spec := range panel {
+		report.Panel[i] = LocusCoverage{Variant: spec.String(), Status: CoverageMissing}
+	}
+
+	prefixed, bare := 0, 0
+	var lastChrom string
+	var lastPos uint64
+	finished := map[string]bool{}
+	for {
+		variant, err := v.next(records)
+		if err != nil {
+			v.fileIssue("limits", SeverityError, err.Error())
+			break
+		}
+		if variant == nil {
+			break
+		}
+		report.Records++
+		line := variant.LineNumber
+		v.vcfErrors(rdr, "genotype", SeverityError)
+
+		chrom := variant.Chromosome
+		if strings.HasPrefix(chrom, "chr") {
+			prefixed++
+		} else {
+			bare++
+		}
+		if _, ok := chromosomeIndex(chrom); !ok {
+			v.add("contig", SeverityWarning, line, "record on a non-primary contig, which proofs skip", chrom)
+		}
+		if len(declared) > 0 && !declared[chrom] {
+			v.add("contig", SeverityWarning, line, "record on a contig the header does not declare", chrom)
+		}
+
+		if chrom != lastChrom {
+			if finished[chrom] {
+				v.add("order", SeverityWarning, line, "records of a contig are not contiguous, so the VCF cannot be indexed", chrom)
+			}
+			finished[lastChrom] = true
+			lastChrom = chrom
+		} else if variant.Pos < lastPos {
+			v.add("order", SeverityWarning, line, "records are not sorted by position, so the VCF cannot be indexed", fmt.Sprintf("%s:%d", chrom, variant.Pos))
+		}
+		lastPos = variant.Pos
+
+		if len(header.SampleNames) > 0 {
+			if !slices.Contains(variant.Format, "GT") {
+				v.add("gt", SeverityError, line, "record without a GT field", fmt.Sprintf("%s:%d", chrom, variant.Pos))
+			} else if len(variant.Samples) < len(header.SampleNames) || slices.Contains(variant.Samples, nil) {
+				v.add("genotype", SeverityError, line, "record with fewer sample columns than the header", fmt.Sprintf("%s:%d", chrom, variant.Pos))
+			}
+		}
+
+		for _, i := range lookup.match(variant) {
+			if rank, index := lookup.rank(i, variant, variant.Reference, variant.Alternate); rank != matchNone {
+				report.Panel[i] = panelCoverage(panel[i], variant, index, report.Panel[i])
+			}
+		}
+	}
+
+	if prefixed > 0 && bare > 0 {
+		v.fileIssue("contig", SeverityWarning, fmt.Sprintf("%d records name contigs with a chr prefix and %d without", prefixed, bare))
+	}
+	for _, c := range report.Panel {
+		switch c.Status {
+		case CoverageMissing:
+			v.add("coverage", SeverityWarning, 0, "panel variants without a record at their locus", c.Variant)
+		case CoverageNoCall:
+			v.add("coverage", SeverityWarning, 0, "panel variants without a genotype call", c.Variant)
+		case CoverageRefMismatch:
+			v.add("coverage", SeverityError, 0, "panel variants whose reference allele differs from the VCF's, which suggests a different genome build", c.Variant)
+		}
+	}
+	return v.finish(), nil
+}
+
+// next reads the next record, reporting records vcfgo cannot parse at all,
+// which make it panic, as issues rather than failing validation.
+func (v *validation) next(records *vcfReader) (variant *vcfgo.Variant, err error) {
+	for {
+		ok := func() (ok bool) {
+			defer func() {
+				if r := recover(); r != nil {
+					records.Clear()
+					v.add("genotype", SeverityError, records.LineNumber, "record does not parse", fmt.Sprint(r))
+				}
+			}()
+			variant, err = records.next()
+			return true
+		}()
+		if ok {
+			return variant, err
+		}
+	}
+}
+
+// panelCoverage updates a panel variant's coverage with a record at its
+// locus, whose ALT with GT index is the panel's alt, or -1 when none is.
+// A record with the panel's ref and alt wins over other records.
+func panelCoverage(spec VariantSpec, variant *vcfgo.Variant, index int, current LocusCoverage) LocusCoverage {
+	if index < 0 && !strings.EqualFold(variant.Reference, spec.Ref) {
+		if current.Status == CoverageMissing {
+			current.Status, current.Ref = CoverageRefMismatch, variant.Reference
+		}
+		return current
+	}
+	if current.Status == CoverageCalled {
+		return current
+	}
+
+	current.Status, current.Ref = CoverageNoCall, ""
+	if len(variant.Samples) == 0 {
+		// Sites-only records list the variant without a call.
+		if index > 0 {
+			current.Status = CoverageCalled
+		}
+		return current
+	}
+	if sample := variant.Samples[0]; sample != nil && len(sample.GT) > 0 && !slices.Contains(sample.GT, -1) {
+		current.Status = CoverageCalled
+	}
+	return current
+}
+
+func (v *validation) finish() *ValidationReport {
+	v.report.Valid = !slices.ContainsFunc(v.report.Issues, func(i ValidationIssue) bool { return i.Severity == SeverityError })
+	return v.report
+}
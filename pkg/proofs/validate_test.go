@@ -0,0 +1,110 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateVCF(t *testing.T) {
+	panel := []VariantSpec{
+		{Chromosome: "15", Position: 28365618, Ref: "A", Alt: "G"},
+		{Chromosome: "17", Position: 41276045, Ref: "C", Alt: "G"},
+		{Chromosome: "19", Position: 45411941, Ref: "T", Alt: "C"},
+		{Chromosome: "22", Position: 100, Ref: "T", Alt: "C"},
+	}
+
+	clean := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	0/1
+17	41276045	.	C	G	60	PASS	.	GT	0/0
+19	45411941	.	T	C	60	PASS	.	GT	1/1
+22	100	.	T	C	60	PASS	.	GT	0/1
+`)
+	report, err := ValidateVCF(clean, panel, ReadLimits{})
+	if err != nil {
+		t.Fatalf("ValidateVCF: %v", err)
+	}
+	if !report.Valid || len(report.Issues) != 0 || report.Records != 4 {
+		t.Errorf("clean VCF: report = %+v", report)
+	}
+	for _, c := range report.Panel {
+		if c.Status != CoverageCalled {
+			t.Errorf("clean VCF: %s is %s", c.Variant, c.Status)
+		}
+	}
+
+	messy := writeTempVCF(t, `##fileformat=VCFv4.2
+##contig=<ID=15>
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	./.
+15	1000	.	G	A	60	PASS	.	GT	0/1
+chr17	41276045	.	C	G	60	PASS	.	DP	12
+chrUn_gl000220	5	.	A	T	60	PASS	.	GT	0/1
+19	45411941	.	G	C	60	PASS	.	GT	0/1
+19	45411950	.	G	C	60	PASS	.	GT:DP	0/1:x
+15	2000	.	T	C	60	PASS	.	GT	0/1
+1	10
+`)
+	report, err = ValidateVCF(messy, panel, ReadLimits{})
+	if err != nil {
+		t.Fatalf("ValidateVCF: %v", err)
+	}
+	if report.Valid {
+		t.Errorf("messy VCF should not be valid")
+	}
+
+	found := map[string]bool{}
+	for _, issue := range report.Issues {
+		found[issue.Check+": "+issue.Message] = true
+	}
+	for _, want := range []string{
+		"order: records are not sorted by position, so the VCF cannot be indexed",
+		"order: records of a contig are not contiguous, so the VCF cannot be indexed",
+		"gt: record without a GT field",
+		"contig: record on a non-primary contig, which proofs skip",
+		"contig: record on a contig the header does not declare",
+		"contig: 2 records name contigs with a chr prefix and 5 without",
+		"genotype: VCF parser error",
+		"genotype: record does not parse",
+		"coverage: panel variants without a record at their locus",
+		"coverage: panel variants without a genotype call",
+		"coverage: panel variants whose reference allele differs from the VCF's, which suggests a different genome build",
+	} {
+		if !found[want] {
+			t.Errorf("missing issue %q in %+v", want, report.Issues)
+		}
+	}
+
+	// The chr17 record has no GT, so the panel variant there is uncalled.
+	want := []string{CoverageNoCall, CoverageNoCall, CoverageRefMismatch, CoverageMissing}
+	for i, c := range report.Panel {
+		if c.Status != want[i] {
+			t.Errorf("%s: status = %s, want %s", c.Variant, c.Status, want[i])
+		}
+	}
+	if report.Panel[2].Ref != "G" {
+		t.Errorf("ref mismatch should report the VCF's ref, got %q", report.Panel[2].Ref)
+	}
+
+	if err := WriteValidationReport(filepath.Join(t.TempDir(), "report.json"), report); err != nil {
+		t.Errorf("WriteValidationReport: %v", err)
+	}
+
+	grch38 := writeTempVCF(t, `##fileformat=VCFv4.2
+##contig=<ID=chr15,length=101991189>
+##contig=<ID=chr22,length=50818468>
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+chr22	100	.	T	C	60	PASS	.	GT	0/1
+`)
+	if report, err := ValidateVCF(grch38, nil, ReadLimits{}); err != nil || report.Build != GRCh38 || len(report.Issues) != 1 || report.Issues[0].Check != "build" {
+		t.Errorf("GRCh38 VCF: report = %+v, %v", report, err)
+	}
+
+	broken := writeTempVCF(t, "##fileformat=VCFv4.2\nnot a header\n")
+	if report, err := ValidateVCF(broken, nil, ReadLimits{}); err != nil || report.Valid || report.Issues[0].Check != "header" {
+		t.Errorf("broken header: report = %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,641 @@
+package proofs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/brentp/vcfgo"
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Locus identifies a single genomic position.
+type Locus struct {
+	Chromosome string
+	Position   uint64
+}
+
+func (l Locus) String() string {
+	return fmt.Sprintf("%s:%d", l.Chromosome, l.Position)
+}
+
+// ParseLocus parses "chrom:pos", e.g. "15:28365618".
+func ParseLocus(s string) (Locus, error) {
+	chrom, posStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return Locus{}, withKind(ErrInvalidInput, fmt.Errorf("invalid locus %q: expected chrom:pos", s))
+	}
+
+	pos, err := strconv.ParseUint(posStr, 10, 64)
+	if err != nil {
+		return Locus{}, withKind(ErrInvalidInput, fmt.Errorf("invalid locus position %q: %w", posStr, err))
+	}
+
+	return Locus{normalizeChromosome(chrom), pos}, nil
+}
+
+// Genotype is the first sample's call at a single VCF record.
+type Genotype struct {
+	Ref     string
+	Alt     []string
+	Alleles []int
+	Phased  bool
+	// PhaseSet is the FORMAT/PS value; phased calls are only comparable
+	// within the same phase set. Empty when the VCF has no PS field.
+	PhaseSet string
+	// Quality is the record's QUAL and Depth the sample's FORMAT/DP; both
+	// are zero when missing.
+	Quality float64
+	Depth   int
+}
+
+// altIndex returns the GT index of alt, or -1 when the record lacks it.
+func (g Genotype) altIndex(alt string) int {
+	for i, a := range g.Alt {
+		if strings.EqualFold(a, alt) {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// Allele returns the allele a GT index names: the REF for 0 and an ALT
+// otherwise, or "" for a missing or out-of-range index.
+func (g Genotype) Allele(index int) string {
+	switch {
+	case index == 0:
+		return g.Ref
+	case index > 0 && index <= len(g.Alt):
+		return g.Alt[index-1]
+	default:
+		return ""
+	}
+}
+
+// withAllele spells the ALT with GT index as spec's alt and the REF as
+// spec's ref, for a record that writes spec padded or within a
+// multiallelic record. The other ALTs keep the record's spelling.
+func (g Genotype) withAllele(index int, spec VariantSpec) Genotype {
+	if index < 1 || index > len(g.Alt) || spec.Ref == "" || spec.Alt == "" {
+		return g
+	}
+	g.Ref = spec.Ref
+	g.Alt = slices.Clone(g.Alt)
+	g.Alt[index-1] = spec.Alt
+	return g
+}
+
+// Carries reports how many copies of the alt allele the genotype carries.
+func (g Genotype) Carries(alt string) int {
+	index := g.altIndex(alt)
+	if index < 0 {
+		return 0
+	}
+
+	count := 0
+	for _, allele := range g.Alleles {
+		if allele == index {
+			count++
+		}
+	}
+	return count
+}
+
+// VariantSpec names a single variant as chrom:pos:ref:alt.
+type VariantSpec struct {
+	Chromosome string
+	Position   uint64
+	Ref        string
+	Alt        string
+	// RSID is the variant's dbSNP ID, if known, which finds its record in
+	// VCFs with rsIDs in the ID column regardless of position. It is not
+	// part of the variant's String or ID.
+	RSID string
+}
+
+// ParseVariantSpec parses "chrom:pos:ref:alt", e.g. "17:41276045:C:G".
+func ParseVariantSpec(s string) (VariantSpec, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return VariantSpec{}, withKind(ErrInvalidInput, fmt.Errorf("invalid variant %q: expected chrom:pos:ref:alt", s))
+	}
+
+	pos, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return VariantSpec{}, withKind(ErrInvalidInput, fmt.Errorf("invalid variant position %q: %w", parts[1], err))
+	}
+
+	return VariantSpec{
+		Chromosome: normalizeChromosome(parts[0]),
+		Position:   pos,
+		Ref:        parts[2],
+		Alt:        parts[3],
+	}.normalized(), nil
+}
+
+// recordSpec is the variant a VCF record's alt allele describes.
+func recordSpec(variant *vcfgo.Variant, alt string) VariantSpec {
+	return VariantSpec{
+		Chromosome: normalizeChromosome(variant.Chromosome),
+		Position:   variant.Pos,
+		Ref:        variant.Reference,
+		Alt:        alt,
+	}.normalized()
+}
+
+// normalized uppercases the alleles and trims the bases they share, first
+// from the end and then from the start, so an indel padded with extra
+// context or split from a multiallelic record compares equal to its
+// minimal form: 17:41276043:GACT:GA is 17:41276044:ACT:A. Both alleles
+// keep at least one base, and symbolic alleles such as <DEL> are left
+// as they are. Indels are not shifted left through repeats, which needs
+// the reference sequence, so VCFs should be left-aligned already.
+func (v VariantSpec) normalized() VariantSpec {
+	v.Ref, v.Alt = strings.ToUpper(v.Ref), strings.ToUpper(v.Alt)
+	if !isBases(v.Ref) || !isBases(v.Alt) {
+		return v
+	}
+	for len(v.Ref) > 1 && len(v.Alt) > 1 && v.Ref[len(v.Ref)-1] == v.Alt[len(v.Alt)-1] {
+		v.Ref, v.Alt = v.Ref[:len(v.Ref)-1], v.Alt[:len(v.Alt)-1]
+	}
+	for len(v.Ref) > 1 && len(v.Alt) > 1 && v.Ref[0] == v.Alt[0] {
+		v.Ref, v.Alt = v.Ref[1:], v.Alt[1:]
+		v.Position++
+	}
+	return v
+}
+
+// isBases reports whether an uppercase allele is spelled out in bases.
+func isBases(allele string) bool {
+	return allele != "" && strings.Trim(allele, "ACGTN") == ""
+}
+
+func (v VariantSpec) String() string {
+	return fmt.Sprintf("%s:%d:%s:%s", v.Chromosome, v.Position, v.Ref, v.Alt)
+}
+
+// Locus returns the position of the variant.
+func (v VariantSpec) Locus() Locus {
+	return Locus{v.Chromosome, v.Position}
+}
+
+// ID encodes the variant as a field element for use as a public input.
+func (v VariantSpec) ID() *big.Int {
+	digest := sha256.Sum256([]byte(v.String()))
+	return new(big.Int).Mod(new(big.Int).SetBytes(digest[:]), ecc.BN254.ScalarField())
+}
+
+// normalizeChromosome strips a leading "chr" so "chr10" and "10" compare
+// equal, and names the mitochondrial genome MT whether a VCF calls it chrM,
+// M or MT.
+func normalizeChromosome(chrom string) string {
+	if len(chrom) > 3 && strings.EqualFold(chrom[:3], "chr") {
+		chrom = chrom[3:]
+	}
+	if strings.EqualFold(chrom, "M") || strings.EqualFold(chrom, "MT") {
+		return "MT"
+	}
+	return chrom
+}
+
+// gzipMagic starts every gzip member, including the blocks of BGZF files
+// written by bgzip.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// vcfStream is a VCF file's text, decompressed if the file was compressed.
+type vcfStream struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (s *vcfStream) Close() error {
+	var first error
+	for _, c := range s.closers {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// OpenVCFStream opens the VCF at vcfPath for reading as text. Files starting
+// with the gzip magic bytes are decompressed, whatever their extension; this
+// covers both plain gzip and bgzip's BGZF, a series of gzip members read
+// back to back.
+func OpenVCFStream(vcfPath string) (io.ReadCloser, error) {
+	return openDecompressed(vcfPath)
+}
+
+// openDecompressed opens the file at path, decompressing it if it starts
+// with the gzip magic bytes.
+func openDecompressed(path string) (io.ReadCloser, error) {
+	f, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(f)
+	if magic, _ := br.Peek(len(gzipMagic)); !bytes.Equal(magic, gzipMagic) {
+		return &vcfStream{Reader: br, closers: []io.Closer{f}}, nil
+	}
+
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("reading compressed %s: %w", path, err)
+	}
+	return &vcfStream{Reader: zr, closers: []io.Closer{zr, f}}, nil
+}
+
+// FilterVCFRegion returns the VCF text of r without the data lines of
+// records that do not overlap region, so they are skipped unparsed.
+func FilterVCFRegion(r io.Reader, region GenomicRegion) io.Reader {
+	return &lineLimiter{r: r, keep: region.overlapsLine}
+}
+
+// ReadLimits bounds the reading of an input VCF, so an unexpectedly large
+// file fails instead of exhausting memory. The zero value is unlimited.
+type ReadLimits struct {
+	// MaxRecords is the number of records a scan of the VCF reads before
+	// giving up.
+	MaxRecords int
+	// MaxLineBytes is the length of the longest line accepted. Each line is
+	// held in memory whole while it is parsed, which can be large for VCFs
+	// with many samples.
+	MaxLineBytes int
+}
+
+// lineLimiter passes on the lines of r until one is longer than maxLine
+// bytes, then ends the stream, recording why in err. A line is held back
+// until its end is read, so readers never see part of the long line and
+// every line before it still parses. The end is reported as io.EOF because
+// vcfgo cannot handle other errors mid-line. When keep is set, lines it
+// rejects are dropped before anything parses them.
+type lineLimiter struct {
+	r       io.Reader
+	maxLine int
+	keep    func(line []byte) bool
+	chunk   []byte
+	line    []byte // the start of a line not yet passed on
+	out     []byte // lines passed on but not yet read
+	outBuf  []byte
+	rerr    error
+	err     error
+}
+
+func (l *lineLimiter) Read(p []byte) (int, error) {
+	if l.maxLine <= 0 && l.keep == nil {
+		return l.r.Read(p)
+	}
+	for len(l.out) == 0 {
+		if l.err != nil {
+			return 0, io.EOF
+		}
+		if l.rerr != nil {
+			if len(l.line) == 0 {
+				return 0, l.rerr
+			}
+			// The last line has no newline.
+			if l.keep != nil && !l.keep(l.line) {
+				l.line = nil
+				continue
+			}
+			l.out, l.line = l.line, nil
+			break
+		}
+		l.fill()
+	}
+	n := copy(p, l.out)
+	l.out = l.out[n:]
+	return n, nil
+}
+
+// fill reads the next chunk of r, passing on the lines it completes.
+func (l *lineLimiter) fill() {
+	if l.chunk == nil {
+		l.chunk = make([]byte, 64<<10)
+	}
+	n, err := l.r.Read(l.chunk)
+	l.rerr = err
+
+	out := l.outBuf[:0]
+	for data := l.chunk[:n]; len(data) > 0; {
+		end := len(data)
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			end = i + 1
+		}
+		l.line = append(l.line, data[:end]...)
+		data = data[end:]
+
+		if l.maxLine > 0 && len(bytes.TrimSuffix(l.line, []byte{'\n'})) > l.maxLine {
+			l.err = fmt.Errorf("VCF has a line longer than %d bytes", l.maxLine)
+			break
+		}
+		if l.line[len(l.line)-1] == '\n' {
+			if l.keep == nil || l.keep(l.line) {
+				out = append(out, l.line...)
+			}
+			l.line = l.line[:0]
+		}
+	}
+	l.outBuf, l.out = out, out
+}
+
+// vcfReader streams the records of a VCF within its ReadLimits.
+type vcfReader struct {
+	*vcfgo.Reader
+	stream  *lineLimiter
+	limits  ReadLimits
+	records int
+	closer  io.Closer
+}
+
+// newVCFReader reads records from r, parsing its header unless header is
+// given. When region is set, only records overlapping it are read.
+func newVCFReader(r io.Reader, header *vcfgo.Header, limits ReadLimits, region *GenomicRegion) (*vcfReader, error) {
+	stream := &lineLimiter{r: r, maxLine: limits.MaxLineBytes}
+	if region != nil {
+		stream.keep = region.overlapsLine
+	}
+	var rdr *vcfgo.Reader
+	var err error
+	if header != nil {
+		rdr, err = vcfgo.NewWithHeader(stream, header, false)
+	} else {
+		rdr, err = vcfgo.NewReader(stream, false)
+	}
+	if err != nil {
+		// A header cut short by the line limit fails to parse.
+		if stream.err != nil {
+			return nil, withKind(ErrVCF, stream.err)
+		}
+		return nil, withKind(ErrVCF, err)
+	}
+	return &vcfReader{Reader: rdr, stream: stream, limits: limits}, nil
+}
+
+// openVCF opens the VCF at vcfPath, decompressing gzip and BGZF files, to
+// read the records overlapping region, or every record when region is nil.
+// The caller must close the returned reader once done reading.
+func openVCF(vcfPath string, limits ReadLimits, region *GenomicRegion) (*vcfReader, error) {
+	stream, err := OpenVCFStream(vcfPath)
+	if err != nil {
+		return nil, withKind(ErrVCF, err)
+	}
+
+	rdr, err := newVCFReader(stream, nil, limits, region)
+	if err != nil {
+		stream.Close()
+		return nil, err
+	}
+	rdr.closer = stream
+
+	return rdr, nil
+}
+
+// sampleColumn returns the index of the sample named name in header, or 0,
+// the first sample, when name is empty.
+func sampleColumn(header *vcfgo.Header, name string) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+	index := slices.Index(header.SampleNames, name)
+	if index < 0 {
+		return 0, withKind(ErrNotFound, fmt.Errorf("sample %s not found in VCF", name))
+	}
+	return index, nil
+}
+
+// next returns the next record, or nil at the end of the VCF. Records are
+// read one at a time, so callers that stop early never read the rest.
+func (r *vcfReader) next() (*vcfgo.Variant, error) {
+	variant := r.Read()
+	if variant == nil {
+		return nil, withKind(ErrVCF, r.stream.err)
+	}
+	r.records++
+	if r.limits.MaxRecords > 0 && r.records > r.limits.MaxRecords {
+		return nil, withKind(ErrVCF, fmt.Errorf("VCF has more than %d records", r.limits.MaxRecords))
+	}
+	return variant, nil
+}
+
+func (r *vcfReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// infoHasKey reports whether the variant's INFO column contains key, either
+// as a flag or as a key=value pair.
+func infoHasKey(variant *vcfgo.Variant, key string) bool {
+	if variant.Info_ == nil {
+		return false
+	}
+	for _, k := range variant.Info_.Keys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// infoString returns the raw value of an INFO key=value pair, regardless of
+// whether the key is declared in the header.
+func infoString(variant *vcfgo.Variant, key string) (string, bool) {
+	info, ok := variant.Info_.(*vcfgo.InfoByte)
+	if !ok || !infoHasKey(variant, key) {
+		return "", false
+	}
+
+	value := string(info.SGet(key))
+	if value == "" || value == key {
+		return "", false
+	}
+	return value, true
+}
+
+// recordQuality returns the record's QUAL, or zero when it is missing.
+func recordQuality(variant *vcfgo.Variant) float64 {
+	if variant.Quality == vcfgo.MISSING_VAL || variant.Quality < 0 {
+		return 0
+	}
+	return float64(variant.Quality)
+}
+
+// extractGenotypes returns the first sample's call at each requested
+// variant that is present and accepted by the imputation policy and call
+// filter, keyed by the variant's locus and reading the VCF within s's
+// limits. Records are found through variantLookup: by rsID where the
+// variant and record carry one, otherwise by locus, preferring a record
+// with the variant's alleles.
+// The variants are read through the tabix or CSI index s.Index, or one
+// found next to the VCF, which finds them by locus only; otherwise the VCF
+// is scanned once, stopping as soon as every variant has a matching record.
+// With s.Liftover, the variants are on PanelBuild and are lifted onto the
+// VCF's build, and the genotypes have alleles on the requested strand.
+// A variant found padded or in a multiallelic record has its own ref and
+// alt in the genotype, at the GT index the record gives it.
+// No-calls and variants without a record are handled by s.Missing.
+func extractGenotypes(vcfPath string, s Settings, specs []VariantSpec) (map[Locus]Genotype, error) {
+	return extractSampleGenotypes(vcfPath, s, s.Sample, specs)
+}
+
+// extractSampleGenotypes is extractGenotypes for the named sample of a
+// multi-sample VCF. An empty sample name selects the first sample.
+func extractSampleGenotypes(vcfPath string, s Settings, sampleName string, specs []VariantSpec) (map[Locus]Genotype, error) {
+	genotypes, err := readSampleGenotypes(vcfPath, s, sampleName, specs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Missing.apply(specs, genotypes); err != nil {
+		return nil, err
+	}
+	return genotypes, nil
+}
+
+// readSampleGenotypes is extractSampleGenotypes before s.Missing is
+// applied, keeping no-calls as they are. Calls a shared extraction holds
+// for every spec are taken from it rather than read again.
+func readSampleGenotypes(vcfPath string, s Settings, sampleName string, specs []VariantSpec) (map[Locus]Genotype, error) {
+	calls, ok := s.shared.calls(vcfPath, s, sampleName, specs)
+	if !ok {
+		var err error
+		if calls, err = readCalls(vcfPath, s, sampleName, specs); err != nil {
+			return nil, err
+		}
+	}
+
+	genotypes := make(map[Locus]Genotype)
+	for i, call := range calls {
+		if call != nil {
+			genotypes[Locus{normalizeChromosome(specs[i].Chromosome), specs[i].Position}] = *call
+		}
+	}
+	return genotypes, nil
+}
+
+// readCalls reads the call at each of specs, nil where the VCF has none,
+// as readSampleGenotypes describes.
+func readCalls(vcfPath string, s Settings, sampleName string, specs []VariantSpec) ([]*Genotype, error) {
+	rdr, err := openVCF(vcfPath, s.Limits, s.Region)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	sampleIndex, err := sampleColumn(rdr.Header, sampleName)
+	if err != nil {
+		return nil, err
+	}
+
+	switch build := DetectBuild(rdr.Header); {
+	case s.Liftover == nil && build != "" && build != PanelBuild:
+		fmt.Printf("Warning: %s is on %s but panel positions are on %s; panel variants without an rsID in the VCF will be reported missing unless lifted over with a chain file\n", vcfPath, build, PanelBuild)
+	case s.Liftover != nil && build == PanelBuild:
+		fmt.Printf("Warning: %s is already on %s; lifting positions over anyway\n", vcfPath, build)
+	}
+
+	lookup := lookupSpecs(specs)
+	if s.Liftover != nil {
+		for i, t := range lookup.targets {
+			lifted, reverse, ok := s.Liftover.Lift(t.locus)
+			if !ok {
+				// Positions missing from the other build are only found by
+				// rsID.
+				lifted = Locus{}
+			}
+			lookup.targets[i].locus, lookup.targets[i].flipped = lifted, reverse
+		}
+		lookup = newVariantLookup(lookup.targets)
+	}
+	if s.Region != nil {
+		for _, t := range lookup.targets {
+			if !s.Region.Contains(t.locus) {
+				return nil, fmt.Errorf("%s is outside region %s being read", t.spec.Locus(), s.Region)
+			}
+		}
+	}
+
+	calls := make([]*Genotype, len(lookup.targets))
+	ranks := make([]int, len(lookup.targets))
+	found := 0
+	record := func(variant *vcfgo.Variant) error {
+		matched := lookup.match(variant)
+		if len(matched) == 0 || !s.Imputation.acceptsSample(variant, sampleIndex) || !s.Filter.accepts(variant, sampleIndex) {
+			return nil
+		}
+		if len(variant.Samples) <= sampleIndex || variant.Samples[sampleIndex] == nil {
+			return fmt.Errorf("no sample genotype at %s:%d", normalizeChromosome(variant.Chromosome), variant.Pos)
+		}
+
+		sample := variant.Samples[sampleIndex]
+		for _, i := range matched {
+			target := lookup.targets[i]
+			genotype := Genotype{
+				Ref:      variant.Reference,
+				Alt:      variant.Alternate,
+				Alleles:  sample.GT,
+				Phased:   sample.Phased,
+				PhaseSet: sample.Fields["PS"],
+				Quality:  recordQuality(variant),
+				Depth:    max(sample.DP, 0),
+			}
+			if target.flipped {
+				alleles := complementAlleles(append([]string{genotype.Ref}, genotype.Alt...)...)
+				genotype.Ref, genotype.Alt = alleles[0], alleles[1:]
+			}
+			rank, index := lookup.rank(i, variant, genotype.Ref, genotype.Alt)
+			if rank <= ranks[i] {
+				continue
+			}
+			if rank == matchAlleles {
+				found++
+			}
+			ranks[i] = rank
+			call := genotype.withAllele(index, target.spec)
+			calls[i] = &call
+		}
+		return nil
+	}
+
+	if indexPath := vcfIndexPath(vcfPath, s.Index); indexPath != "" {
+		indexed, err := openIndexedVCF(vcfPath, indexPath, rdr.Header, s.Limits)
+		if err == nil {
+			defer indexed.Close()
+			for _, l := range lookup.loci() {
+				if err := indexed.read(l, record); err != nil {
+					return nil, err
+				}
+			}
+			return calls, nil
+		}
+		// A named index must work; one found beside the VCF is optional.
+		if s.Index != "" {
+			return nil, err
+		}
+		fmt.Printf("Warning: not using index %s: %v; scanning the VCF\n", indexPath, err)
+	}
+
+	for found < len(lookup.targets) {
+		variant, err := rdr.next()
+		if err != nil {
+			return nil, err
+		}
+		if variant == nil {
+			break
+		}
+		if err := record(variant); err != nil {
+			return nil, err
+		}
+	}
+	return calls, nil
+}
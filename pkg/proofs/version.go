@@ -0,0 +1,124 @@
+package proofs
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Version is the release this binary was built as. Builds from a module
+// or a git checkout take it from the build info when it is not set with
+// -ldflags "-X github.com/zkgenomics/vcf-proof-mvp/pkg/proofs.Version=...".
+var Version string
+
+const (
+	gnarkModule       = "github.com/consensys/gnark"
+	gnarkCryptoModule = "github.com/consensys/gnark-crypto"
+)
+
+// SoftwareVersion identifies the build that generated a proof. Proofs from
+// builds with other gnark versions or circuit code may not verify against
+// keys set up by this one.
+type SoftwareVersion struct {
+	Version     string `json:"version"`
+	Commit      string `json:"commit,omitempty"`
+	Modified    bool   `json:"modified,omitempty"`
+	GoVersion   string `json:"go_version"`
+	Gnark       string `json:"gnark"`
+	GnarkCrypto string `json:"gnark_crypto"`
+}
+
+// BuildVersion returns the version of this binary and of the gnark modules
+// it was built with.
+func BuildVersion() SoftwareVersion {
+	v := SoftwareVersion{Version: Version, GoVersion: runtime.Version()}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		if v.Version == "" {
+			v.Version = "unknown"
+		}
+		return v
+	}
+	if v.Version == "" {
+		v.Version = info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		switch dep.Path {
+		case gnarkModule:
+			v.Gnark = dep.Version
+		case gnarkCryptoModule:
+			v.GnarkCrypto = dep.Version
+		}
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			v.Commit = setting.Value
+		case "vcs.modified":
+			v.Modified = setting.Value == "true"
+		}
+	}
+	return v
+}
+
+// String describes the build in one line.
+func (v SoftwareVersion) String() string {
+	s := v.Version
+	if v.Commit != "" {
+		s += fmt.Sprintf(" (%.12s", v.Commit)
+		if v.Modified {
+			s += ", modified"
+		}
+		s += ")"
+	}
+	return fmt.Sprintf("%s, gnark %s, gnark-crypto %s, %s", s, v.Gnark, v.GnarkCrypto, v.GoVersion)
+}
+
+// CircuitID identifies the circuit of a proof type.
+type CircuitID struct {
+	Type        string
+	Constraints int
+	// Hash is the hex SHA-256 of the compiled constraint system, as proof
+	// headers and setup's key descriptions record it.
+	Hash string
+}
+
+// CircuitIDs compiles the circuit of every registered proof type with one
+// of its own, for the backend and curve of s and configured as SelfTest
+// proves it, and returns their circuit hashes. Builds reporting the same
+// hashes compile the same circuits, so a prover and a verifier can compare
+// them to rule out a circuit change between their versions. The hash of
+// a given proof also depends on its options, such as its panel or
+// envelope, so compare that with the key description setup wrote instead.
+func CircuitIDs(s Settings) ([]CircuitID, error) {
+	backend, err := s.Backend.backend()
+	if err != nil {
+		return nil, err
+	}
+	var ids []CircuitID
+	for _, t := range proofTypes {
+		p := t.New(selfTestConfig(t.Name, s, ""))
+		proofType, circuit, err := proofCircuit(p)
+		if errors.Is(err, ErrNoCircuit) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		cs, err := backend.Compile(s.Curve, circuit)
+		if err != nil {
+			return nil, fmt.Errorf("compiling %s circuit: %w", proofType, err)
+		}
+		h, err := circuitHash(cs)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, CircuitID{Type: proofType, Constraints: cs.GetNbConstraints(), Hash: hex.EncodeToString(h)})
+	}
+	return ids, nil
+}
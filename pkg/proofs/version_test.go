@@ -0,0 +1,52 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCircuitIDs(t *testing.T) {
+	ids, err := CircuitIDs(Settings{})
+	if err != nil {
+		t.Fatalf("CircuitIDs: %v", err)
+	}
+	hashes := map[string]string{}
+	for _, id := range ids {
+		if id.Hash == "" || id.Constraints == 0 {
+			t.Errorf("%s: %+v", id.Type, id)
+		}
+		hashes[id.Type] = id.Hash
+	}
+	if _, ok := hashes["cohort"]; ok {
+		t.Errorf("cohort has no circuit of its own but was hashed")
+	}
+
+	// A proof generated with the default options is for the circuit
+	// version reports, and its metadata names this build.
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+17	41276045	.	C	G	60	PASS	.	GT	0/1
+`)
+	out := filepath.Join(t.TempDir(), "brca1_proof.bin")
+	if err := (&BRCA1Proof{}).Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	header, err := ReadProofHeader(out)
+	if err != nil {
+		t.Fatalf("ReadProofHeader: %v", err)
+	}
+	if header.CircuitHash != hashes["brca1"] {
+		t.Errorf("proof header circuit %s, CircuitIDs reports %s", header.CircuitHash, hashes["brca1"])
+	}
+	meta, err := ReadMetadata(out)
+	if err != nil {
+		t.Fatalf("ReadMetadata: %v", err)
+	}
+	if meta.Software == nil || *meta.Software != BuildVersion() {
+		t.Errorf("metadata software = %+v, want %+v", meta.Software, BuildVersion())
+	}
+	if hint := versionMismatch(out); hint != "" {
+		t.Errorf("proof from this build reported as from another: %s", hint)
+	}
+}
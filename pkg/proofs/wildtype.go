@@ -0,0 +1,256 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/brentp/vcfgo"
+	"github.com/consensys/gnark/frontend"
+)
+
+// WildTypeCircuit proves the subject is homozygous reference at a public
+// locus. Because a missing VCF record is not evidence of a reference call,
+// the circuit also requires a covering record: a call at the locus, or a
+// gVCF reference block whose span contains it, meeting public quality and
+// depth minimums.
+type WildTypeCircuit struct {
+	LocusKey   frontend.Variable `gnark:",public"`
+	MinQuality frontend.Variable `gnark:",public"`
+	MinDepth   frontend.Variable `gnark:",public"`
+
+	Alleles [2]frontend.Variable
+	// Start and End are the locus keys of the first and last position the
+	// covering record spans.
+	Start   frontend.Variable
+	End     frontend.Variable
+	Quality frontend.Variable
+	Depth   frontend.Variable
+}
+
+func (c *WildTypeCircuit) Define(api frontend.API) error {
+	for _, allele := range c.Alleles {
+		api.AssertIsEqual(allele, 0)
+	}
+	assertCallQuality(api, c.Quality, c.Depth, c.MinQuality, c.MinDepth)
+
+	// Both ends on the same chromosome, with the locus between them.
+	start := api.ToBinary(c.Start, 40)
+	end := api.ToBinary(c.End, 40)
+	api.AssertIsEqual(api.FromBinary(start[32:]...), api.FromBinary(end[32:]...))
+	api.ToBinary(c.LocusKey, 40)
+	api.AssertIsEqual(isAtLeast(api, c.LocusKey, c.Start, 40), 1)
+	api.AssertIsEqual(isAtLeast(api, c.End, c.LocusKey, 40), 1)
+
+	return nil
+}
+
+// coveringCall is a record whose span contains a queried locus.
+type coveringCall struct {
+	Genotype
+	Start, End uint64
+}
+
+// isHomRef reports whether the call is a diploid 0/0.
+func (c coveringCall) isHomRef() bool {
+	return len(c.Alleles) == 2 && c.Alleles[0] == 0 && c.Alleles[1] == 0
+}
+
+// recordEnd returns the last position a record spans: INFO/END for gVCF
+// reference blocks and symbolic alleles, the end of REF otherwise.
+func recordEnd(variant *vcfgo.Variant) uint64 {
+	if value, ok := infoString(variant, "END"); ok {
+		if end, err := strconv.ParseUint(value, 10, 64); err == nil && end >= variant.Pos {
+			return end
+		}
+	}
+	return variant.Pos + uint64(max(len(variant.Reference), 1)) - 1
+}
+
+// blockDepth returns the sample's read depth, preferring FORMAT/MIN_DP,
+// which gVCF reference blocks report as the lowest depth across the block.
+func blockDepth(sample *vcfgo.SampleGenotype) int {
+	if value, ok := sample.Fields["MIN_DP"]; ok {
+		if depth, err := strconv.Atoi(value); err == nil {
+			return max(depth, 0)
+		}
+	}
+	return max(sample.DP, 0)
+}
+
+// extractCoverage returns the first sample's calls from every record
+// accepted by the imputation policy and call filter whose span contains
+// locus, leaving out no-calls under MissingTreatAsMissing. Reference blocks
+// carry no QUAL, so their quality is the sample's FORMAT/GQ.
+func extractCoverage(vcfPath string, s Settings, locus Locus) ([]coveringCall, error) {
+	if s.Region != nil && !s.Region.Contains(locus) {
+		return nil, fmt.Errorf("%s is outside region %s being read", locus, s.Region)
+	}
+	rdr, err := openVCF(vcfPath, s.Limits, s.Region)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	sampleIndex, err := sampleColumn(rdr.Header, s.Sample)
+	if err != nil {
+		return nil, err
+	}
+
+	chrom := normalizeChromosome(locus.Chromosome)
+	var calls []coveringCall
+	for {
+		variant, err := rdr.next()
+		if err != nil {
+			return nil, err
+		}
+		if variant == nil {
+			break
+		}
+		if normalizeChromosome(variant.Chromosome) != chrom || variant.Pos > locus.Position {
+			continue
+		}
+		end := recordEnd(variant)
+		if end < locus.Position || !s.Imputation.acceptsSample(variant, sampleIndex) || !s.Filter.accepts(variant, sampleIndex) {
+			continue
+		}
+		if len(variant.Samples) <= sampleIndex || variant.Samples[sampleIndex] == nil {
+			return nil, fmt.Errorf("no sample genotype covering %s", locus)
+		}
+
+		sample := variant.Samples[sampleIndex]
+		if s.Missing == MissingTreatAsMissing && slices.Contains(sample.GT, -1) {
+			continue
+		}
+		quality := recordQuality(variant)
+		if variant.Quality == vcfgo.MISSING_VAL {
+			quality = float64(max(sample.GQ, 0))
+		}
+		calls = append(calls, coveringCall{
+			Genotype: Genotype{
+				Ref:     variant.Reference,
+				Alt:     variant.Alternate,
+				Alleles: sample.GT,
+				Phased:  sample.Phased,
+				Quality: quality,
+				Depth:   blockDepth(sample),
+			},
+			Start: variant.Pos,
+			End:   end,
+		})
+	}
+	return calls, nil
+}
+
+// wildTypeCall picks the call a wild-type proof at locus rests on. Every
+// covering record must be a 0/0 call; the first one is used.
+func wildTypeCall(locus Locus, calls []coveringCall) (coveringCall, error) {
+	if len(calls) == 0 {
+		return coveringCall{}, fmt.Errorf("%s is not covered by the VCF; a reference call or gVCF block is required", locus)
+	}
+	for _, call := range calls {
+		if len(call.Alleles) != 2 || slices.Contains(call.Alleles, -1) {
+			return coveringCall{}, fmt.Errorf("call covering %s is missing or not diploid", locus)
+		}
+		if !call.isHomRef() {
+			return coveringCall{}, withKind(ErrNotFound, fmt.Errorf("subject is not homozygous reference at %s", locus))
+		}
+	}
+	return calls[0], nil
+}
+
+func (p *WildTypeProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+	if err := p.Quality.validate(); err != nil {
+		return err
+	}
+
+	locus, err := ParseLocus(p.Locus)
+	if err != nil {
+		return err
+	}
+	key, err := locusKey(locus)
+	if err != nil {
+		return err
+	}
+
+	calls, err := extractCoverage(vcfPath, p.Settings, locus)
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+	call, err := wildTypeCall(locus, calls)
+	if err != nil {
+		return err
+	}
+	if err := p.Quality.check(call.Genotype); err != nil {
+		return fmt.Errorf("call at %s: %w", locus, err)
+	}
+
+	chromBits := key &^ (1<<32 - 1)
+	assignment := &WildTypeCircuit{
+		LocusKey:   key,
+		MinQuality: p.Quality.MinQuality,
+		MinDepth:   p.Quality.MinDepth,
+		Alleles:    [2]frontend.Variable{call.Alleles[0], call.Alleles[1]},
+		Start:      chromBits | call.Start,
+		End:        chromBits | call.End,
+		Quality:    callQuality(call.Genotype),
+		Depth:      call.Depth,
+	}
+
+	if err := p.Settings.prove(ctx, "wildtype", &WildTypeCircuit{}, assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("wildtype", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven the subject is homozygous reference at %s\n", locus)
+	if call.Start != call.End {
+		fmt.Printf("from a call covering %s:%d-%d.\n", locus.Chromosome, call.Start, call.End)
+	}
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *WildTypeProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 3 {
+		return false, withKind(ErrVerification, fmt.Errorf("wild-type proof has %d public inputs, expected 3", len(public)))
+	}
+
+	key := public[0].Uint64()
+	fmt.Printf("Homozygous reference at %s\n", locusFromKey(key))
+	fmt.Printf("Call quality at least %s, read depth at least %s\n", public[1], public[2])
+	if err := p.Quality.atLeast(public[1].Int64(), public[2].Int64()); err != nil {
+		return false, err
+	}
+
+	if p.Locus != "" {
+		locus, err := ParseLocus(p.Locus)
+		if err != nil {
+			return false, err
+		}
+		want, err := locusKey(locus)
+		if err != nil {
+			return false, err
+		}
+		if key != want {
+			return false, withKind(ErrVerification, fmt.Errorf("proof is for a different locus than %s", locus))
+		}
+	}
+	return verified, nil
+}
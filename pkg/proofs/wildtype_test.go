@@ -0,0 +1,139 @@
+package proofs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const wildTypeTestGVCF = `##fileformat=VCFv4.2
+##INFO=<ID=END,Number=1,Type=Integer,Description="End position of the reference block">
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+##FORMAT=<ID=DP,Number=1,Type=Integer,Description="Read depth">
+##FORMAT=<ID=GQ,Number=1,Type=Integer,Description="Genotype quality">
+##FORMAT=<ID=MIN_DP,Number=1,Type=Integer,Description="Minimum depth in the block">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+7	117199600	.	A	<NON_REF>	.	.	END=117199700	GT:DP:GQ:MIN_DP	0/0:30:45:18
+7	117199701	.	G	T,<NON_REF>	50	PASS	.	GT:DP:GQ	0/1:25:50
+7	117199702	.	C	<NON_REF>	.	.	END=117199800	GT:DP:GQ:MIN_DP	./.:0:0:0
+17	41276044	.	ACT	A,<NON_REF>	60	PASS	.	GT:DP:GQ	0/0:40:60
+`
+
+func TestWildTypeCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	chrom7 := uint64(7) << 32
+
+	tests := []struct {
+		name     string
+		alleles  [2]int
+		key      uint64
+		start    uint64
+		end      uint64
+		quality  int
+		minDepth int
+		valid    bool
+	}{
+		{"inside block", [2]int{0, 0}, chrom7 | 150, chrom7 | 100, chrom7 | 200, 40, 10, true},
+		{"block edge", [2]int{0, 0}, chrom7 | 200, chrom7 | 100, chrom7 | 200, 40, 10, true},
+		{"single record", [2]int{0, 0}, chrom7 | 100, chrom7 | 100, chrom7 | 100, 40, 10, true},
+		{"heterozygous", [2]int{0, 1}, chrom7 | 150, chrom7 | 100, chrom7 | 200, 40, 10, false},
+		{"before block", [2]int{0, 0}, chrom7 | 99, chrom7 | 100, chrom7 | 200, 40, 10, false},
+		{"after block", [2]int{0, 0}, chrom7 | 201, chrom7 | 100, chrom7 | 200, 40, 10, false},
+		{"other chromosome", [2]int{0, 0}, uint64(8)<<32 | 150, chrom7 | 100, uint64(9)<<32 | 200, 40, 10, false},
+		{"shallow", [2]int{0, 0}, chrom7 | 150, chrom7 | 100, chrom7 | 200, 40, 50, false},
+	}
+	for _, tt := range tests {
+		assignment := &WildTypeCircuit{
+			LocusKey:   tt.key,
+			MinQuality: 20,
+			MinDepth:   tt.minDepth,
+			Alleles:    [2]frontend.Variable{tt.alleles[0], tt.alleles[1]},
+			Start:      tt.start,
+			End:        tt.end,
+			Quality:    tt.quality,
+			Depth:      30,
+		}
+		err := test.IsSolved(&WildTypeCircuit{}, assignment, field)
+		if tt.valid && err != nil {
+			t.Errorf("%s: rejected: %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+}
+
+func TestWildTypeCall(t *testing.T) {
+	vcf := writeTempVCF(t, wildTypeTestGVCF)
+
+	tests := []struct {
+		locus   string
+		start   uint64
+		depth   int
+		quality float64
+		wantErr bool
+	}{
+		{"7:117199644", 117199600, 18, 45, false}, // inside a reference block
+		{"17:41276045", 41276044, 40, 60, false},  // within REF of a 0/0 record
+		{"7:117199701", 0, 0, 0, true},            // heterozygous call
+		{"7:117199750", 0, 0, 0, true},            // no-call block
+		{"7:117199900", 0, 0, 0, true},            // not covered
+		{"1:1000", 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		locus, err := ParseLocus(tt.locus)
+		if err != nil {
+			t.Fatalf("ParseLocus: %v", err)
+		}
+		calls, err := extractCoverage(vcf, Settings{}, locus)
+		if err != nil {
+			t.Fatalf("extractCoverage: %v", err)
+		}
+
+		call, err := wildTypeCall(locus, calls)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.locus)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.locus, err)
+			continue
+		}
+		if call.Start != tt.start || call.Depth != tt.depth || call.Quality != tt.quality {
+			t.Errorf("%s: start %d depth %d quality %g, want %d %d %g", tt.locus, call.Start, call.Depth, call.Quality, tt.start, tt.depth, tt.quality)
+		}
+	}
+}
+
+func TestWildTypeProof(t *testing.T) {
+	vcf := writeTempVCF(t, wildTypeTestGVCF)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "wildtype_proof.bin")
+
+	proof := &WildTypeProof{Locus: "7:117199644", Quality: QualityThresholds{MinQuality: 20, MinDepth: 10}}
+	if err := proof.Generate(t.Context(), vcf, "", out); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if ok, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !ok {
+		t.Fatalf("Verify = %v, %v", ok, err)
+	}
+
+	if _, err := (&WildTypeProof{Locus: "7:117199645"}).Verify(t.Context(), out+".vk", out); err == nil {
+		t.Errorf("proof should not verify for a different locus")
+	}
+	if _, err := (&WildTypeProof{Quality: QualityThresholds{MinDepth: 20}}).Verify(t.Context(), out+".vk", out); err == nil {
+		t.Errorf("proof should not satisfy a stricter depth minimum")
+	}
+
+	if err := (&WildTypeProof{Locus: "7:117199644", Quality: QualityThresholds{MinDepth: 20}}).Generate(t.Context(), vcf, "", filepath.Join(dir, "p.bin")); err == nil {
+		t.Errorf("block below the depth minimum should be rejected")
+	}
+	if err := (&WildTypeProof{Locus: "7:117199900"}).Generate(t.Context(), vcf, "", filepath.Join(dir, "p.bin")); err == nil {
+		t.Errorf("uncovered locus should be rejected")
+	}
+}
@@ -0,0 +1,178 @@
+package proofs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Zygosity classes exposed by ZygosityCircuit.
+const (
+	Homozygous   = 0
+	Heterozygous = 1
+)
+
+// ZygosityName returns the display name of a zygosity class.
+func ZygosityName(zygosity int) string {
+	switch zygosity {
+	case Homozygous:
+		return "homozygous"
+	case Heterozygous:
+		return "heterozygous"
+	default:
+		return "unknown"
+	}
+}
+
+// maxAlleleBits bounds the GT allele indices the zygosity circuit accepts.
+const maxAlleleBits = 8
+
+// ZygosityCircuit proves the zygosity class of a diploid call at a public
+// locus, and that the call meets public quality and depth minimums. The two
+// GT allele indices stay private, so a homozygous proof does not reveal
+// whether the call is reference or alternate.
+type ZygosityCircuit struct {
+	LocusKey   frontend.Variable `gnark:",public"`
+	Zygosity   frontend.Variable `gnark:",public"`
+	MinQuality frontend.Variable `gnark:",public"`
+	MinDepth   frontend.Variable `gnark:",public"`
+
+	Alleles [2]frontend.Variable
+	Quality frontend.Variable
+	Depth   frontend.Variable
+}
+
+func (c *ZygosityCircuit) Define(api frontend.API) error {
+	for _, allele := range c.Alleles {
+		api.ToBinary(allele, maxAlleleBits)
+	}
+	assertCallQuality(api, c.Quality, c.Depth, c.MinQuality, c.MinDepth)
+
+	heterozygous := api.Sub(1, api.IsZero(api.Sub(c.Alleles[0], c.Alleles[1])))
+	api.AssertIsEqual(heterozygous, c.Zygosity)
+	bindPublic(api, c.LocusKey)
+
+	return nil
+}
+
+// zygosityAlleles returns the GT allele indices of a diploid call. Loci
+// absent from the VCF are taken as homozygous reference.
+func zygosityAlleles(locus Locus, genotype Genotype, ok bool) ([2]int, error) {
+	if !ok {
+		fmt.Printf("%s not in VCF, assuming homozygous reference\n", locus)
+		return [2]int{0, 0}, nil
+	}
+
+	if len(genotype.Alleles) != 2 {
+		return [2]int{}, fmt.Errorf("genotype at %s is not diploid", locus)
+	}
+	alleles := [2]int{genotype.Alleles[0], genotype.Alleles[1]}
+	for _, allele := range alleles {
+		if allele < 0 {
+			return alleles, withKind(ErrNotFound, fmt.Errorf("genotype at %s is missing", locus))
+		}
+		if allele >= 1<<maxAlleleBits {
+			return alleles, fmt.Errorf("allele index %d at %s is out of range", allele, locus)
+		}
+	}
+	return alleles, nil
+}
+
+func (p *ZygosityProof) Generate(ctx context.Context, vcfPath string, provingKeyPath string, outputPath string) error {
+	if err := p.Settings.validate(vcfPath); err != nil {
+		return err
+	}
+	if err := p.Quality.validate(); err != nil {
+		return err
+	}
+
+	locus, err := ParseLocus(p.Locus)
+	if err != nil {
+		return err
+	}
+	key, err := locusKey(locus)
+	if err != nil {
+		return err
+	}
+
+	genotypes, err := extractGenotypes(vcfPath, p.Settings, locusSpecs(locus))
+	if err != nil {
+		return fmt.Errorf("error reading VCF: %w", err)
+	}
+	genotype, ok := genotypes[locus]
+	alleles, err := zygosityAlleles(locus, genotype, ok)
+	if err != nil {
+		return err
+	}
+	if err := p.Quality.check(genotype); err != nil {
+		return fmt.Errorf("call at %s: %w", locus, err)
+	}
+
+	zygosity := Homozygous
+	if alleles[0] != alleles[1] {
+		zygosity = Heterozygous
+	}
+
+	assignment := &ZygosityCircuit{
+		LocusKey:   key,
+		Zygosity:   zygosity,
+		MinQuality: p.Quality.MinQuality,
+		MinDepth:   p.Quality.MinDepth,
+		Alleles:    [2]frontend.Variable{alleles[0], alleles[1]},
+		Quality:    callQuality(genotype),
+		Depth:      genotype.Depth,
+	}
+
+	if err := p.Settings.prove(ctx, "zygosity", &ZygosityCircuit{}, assignment, provingKeyPath, outputPath); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(outputPath, newMetadata("zygosity", p.Settings)); err != nil {
+		return err
+	}
+
+	p.Settings.printGenerated()
+	fmt.Printf("We have proven the subject is %s at %s\n", ZygosityName(zygosity), locus)
+	fmt.Println("without revealing which alleles are carried.")
+	fmt.Printf("Proof saved to: %s\n", outputPath)
+
+	return nil
+}
+
+func (p *ZygosityProof) Verify(ctx context.Context, verifyingKeyPath string, proofPath string) (bool, error) {
+	verified, err := p.verifyProof(ctx, verifyingKeyPath, proofPath)
+	if err != nil {
+		return verified, err
+	}
+
+	public, err := readPublicInputs(proofPath)
+	if err != nil {
+		return false, err
+	}
+	if len(public) != 4 {
+		return false, withKind(ErrVerification, fmt.Errorf("zygosity proof has %d public inputs, expected 4", len(public)))
+	}
+
+	key := public[0].Uint64()
+	fmt.Printf("Zygosity at %s: %s\n", locusFromKey(key), ZygosityName(int(public[1].Int64())))
+	fmt.Printf("Call quality at least %s, read depth at least %s\n", public[2], public[3])
+	if err := p.Quality.atLeast(public[2].Int64(), public[3].Int64()); err != nil {
+		return false, err
+	}
+
+	if p.Locus != "" {
+		locus, err := ParseLocus(p.Locus)
+		if err != nil {
+			return false, err
+		}
+		want, err := locusKey(locus)
+		if err != nil {
+			return false, err
+		}
+		if key != want {
+			return false, withKind(ErrVerification, fmt.Errorf("proof is for a different locus than %s", locus))
+		}
+	}
+	return verified, nil
+}
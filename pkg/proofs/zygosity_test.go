@@ -0,0 +1,116 @@
+package proofs
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestZygosityCircuit(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+
+	tests := []struct {
+		alleles  [2]int
+		zygosity int
+		valid    bool
+	}{
+		{[2]int{0, 0}, Homozygous, true},
+		{[2]int{1, 1}, Homozygous, true},
+		{[2]int{0, 1}, Heterozygous, true},
+		{[2]int{1, 2}, Heterozygous, true},
+		{[2]int{0, 1}, Homozygous, false},
+		{[2]int{2, 2}, Heterozygous, false},
+		{[2]int{-1, -1}, Homozygous, false},
+		{[2]int{0, 1 << maxAlleleBits}, Heterozygous, false},
+	}
+	for _, tt := range tests {
+		assignment := &ZygosityCircuit{
+			LocusKey:   15<<32 | HERC2Pos,
+			Zygosity:   tt.zygosity,
+			MinQuality: 0,
+			MinDepth:   0,
+			Alleles:    [2]frontend.Variable{tt.alleles[0], tt.alleles[1]},
+			Quality:    0,
+			Depth:      0,
+		}
+		err := test.IsSolved(&ZygosityCircuit{}, assignment, field)
+		if tt.valid && err != nil {
+			t.Errorf("%v as %s rejected: %v", tt.alleles, ZygosityName(tt.zygosity), err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%v as %s accepted", tt.alleles, ZygosityName(tt.zygosity))
+		}
+	}
+}
+
+func TestZygosityProof(t *testing.T) {
+	vcf := writeTempVCF(t, `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+15	28365700	.	C	T	60	PASS	.	GT	./.
+`)
+	dir := t.TempDir()
+
+	tests := []struct {
+		locus   string
+		want    int
+		wantErr bool
+	}{
+		{"15:28365618", Heterozygous, false},
+		{"chr15:1000", Homozygous, false}, // absent, hom-ref
+		{"15:28365700", 0, true},          // missing call
+		{"chrUn:10", 0, true},
+	}
+	for i, tt := range tests {
+		out := filepath.Join(dir, fmt.Sprintf("zygosity_proof_%d.bin", i))
+		proof := &ZygosityProof{Locus: tt.locus}
+		err := proof.Generate(t.Context(), vcf, "", out)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: Generate should fail", tt.locus)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Generate: %v", tt.locus, err)
+		}
+
+		if verified, err := proof.Verify(t.Context(), out+".vk", out); err != nil || !verified {
+			t.Fatalf("%s: Verify = %v, %v", tt.locus, verified, err)
+		}
+		public, err := readPublicInputs(out)
+		if err != nil {
+			t.Fatalf("readPublicInputs: %v", err)
+		}
+		if got := int(public[1].Int64()); got != tt.want {
+			t.Errorf("%s: zygosity = %s, want %s", tt.locus, ZygosityName(got), ZygosityName(tt.want))
+		}
+
+		other := &ZygosityProof{Locus: "15:1"}
+		if _, err := other.Verify(t.Context(), out+".vk", out); err == nil {
+			t.Errorf("%s: proof verified for a different locus", tt.locus)
+		}
+		assertInputBound(t, out, 0)
+	}
+}
+
+func TestLocusFromKey(t *testing.T) {
+	for _, s := range []string{"1:1", "22:51304566", "X:2699520", "MT:16569"} {
+		locus, err := ParseLocus(s)
+		if err != nil {
+			t.Fatalf("ParseLocus(%q): %v", s, err)
+		}
+		key, err := locusKey(locus)
+		if err != nil {
+			t.Fatalf("locusKey(%s): %v", locus, err)
+		}
+		if got := locusFromKey(key); got != locus {
+			t.Errorf("locusFromKey(locusKey(%s)) = %s", locus, got)
+		}
+	}
+}
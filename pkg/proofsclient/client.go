@@ -0,0 +1,279 @@
+// Package proofsclient is a Go client of the HTTP API of a proof server,
+// as vcfproof serve and proofs.ProofServer serve it and the OpenAPI
+// document they serve as GET /openapi.json describes it. Its types mirror
+// the document's schemas, so the client does not depend on the proofs
+// package.
+package proofsclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Proof statuses a server reports.
+const (
+	StatusPending = "pending"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// ProgressEvent reports how far a stage of generating a proof has got.
+type ProgressEvent struct {
+	ProofType   string        `json:"proof_type"`
+	Stage       string        `json:"stage"`
+	Done        bool          `json:"done,omitempty"`
+	Fraction    float64       `json:"fraction"`
+	Elapsed     time.Duration `json:"elapsed"`
+	Constraints int           `json:"constraints,omitempty"`
+}
+
+// ProofRecord is what a server reports about a proof.
+type ProofRecord struct {
+	ID          string         `json:"id"`
+	Type        string         `json:"type"`
+	Status      string         `json:"status"`
+	Error       string         `json:"error,omitempty"`
+	ErrorKind   string         `json:"error_kind,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+	Progress    *ProgressEvent `json:"progress,omitempty"`
+	// The rest is set once the proof is done. Metadata is the proof's
+	// metadata file.
+	PublicInputs []string        `json:"public_inputs,omitempty"`
+	Claims       []string        `json:"claims,omitempty"`
+	Metadata     json.RawMessage `json:"metadata,omitempty"`
+	Proof        []byte          `json:"proof,omitempty"`
+	VerifyingKey []byte          `json:"verifying_key,omitempty"`
+}
+
+// VerifyResult is a server's answer to a verify request.
+type VerifyResult struct {
+	Type         string   `json:"type"`
+	Verified     bool     `json:"verified"`
+	Error        string   `json:"error,omitempty"`
+	ErrorKind    string   `json:"error_kind,omitempty"`
+	PublicInputs []string `json:"public_inputs,omitempty"`
+	Claims       []string `json:"claims,omitempty"`
+}
+
+// Error is an error response from a server.
+type Error struct {
+	StatusCode int
+	Message    string `json:"error"`
+	Kind       string `json:"error_kind,omitempty"`
+	// RetryAfter is how long to wait before retrying a request refused
+	// over the tenant's rate limit.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("proof server answered %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("proof server answered %d: %s", e.StatusCode, e.Message)
+}
+
+// GenerateRequest asks a server to generate a proof, from a VCF or from a
+// job sealed by solve with the metadata solve wrote.
+type GenerateRequest struct {
+	Type     string
+	VCF      io.Reader
+	Job      io.Reader
+	Metadata io.Reader
+	// Options are the proof's options, named like the generate flags:
+	// position, variant, gene, snps, region, nonce, valid-for, ...
+	Options map[string]string
+}
+
+// VerifyRequest asks a server to verify a proof, uploaded or one it
+// generated named by ID, against the options given.
+type VerifyRequest struct {
+	// Type is detected from the proof when empty.
+	Type         string
+	ID           string
+	Proof        io.Reader
+	Metadata     io.Reader
+	VerifyingKey io.Reader
+	IgnoreExpiry bool
+	// Options are what the proof must show, named like the verify flags:
+	// position, variant, gene, nonce, min-qual, ...
+	Options map[string]string
+}
+
+// Client calls the API of the proof server at BaseURL.
+type Client struct {
+	// BaseURL is the server's URL, such as http://localhost:8080.
+	BaseURL string
+	// APIKey, when set, is sent as a bearer token, as servers with tenants
+	// require.
+	APIKey string
+	// HTTPClient makes the requests; nil means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// New returns a client of the server at baseURL authenticating with
+// apiKey, which may be empty.
+func New(baseURL, apiKey string) *Client {
+	return &Client{BaseURL: baseURL, APIKey: apiKey}
+}
+
+// Generate starts generating a proof, and returns its pending record.
+func (c *Client) Generate(ctx context.Context, req GenerateRequest) (*ProofRecord, error) {
+	fields := withField(req.Options, "type", req.Type)
+	files := map[string]io.Reader{"vcf": req.VCF, "job": req.Job, "metadata": req.Metadata}
+	var record ProofRecord
+	if err := c.postForm(ctx, "/proofs", fields, files, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Get returns the record of the proof with id, with the proof once it is
+// done.
+func (c *Client) Get(ctx context.Context, id string) (*ProofRecord, error) {
+	var record ProofRecord
+	if err := c.do(ctx, http.MethodGet, "/proofs/"+url.PathEscape(id), "", nil, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Cancel stops generating the pending proof with id, which then fails.
+func (c *Client) Cancel(ctx context.Context, id string) (*ProofRecord, error) {
+	var record ProofRecord
+	if err := c.do(ctx, http.MethodDelete, "/proofs/"+url.PathEscape(id), "", nil, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Wait polls the proof with id every interval until it is no longer
+// pending, and returns its record.
+func (c *Client) Wait(ctx context.Context, id string, interval time.Duration) (*ProofRecord, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		record, err := c.Get(ctx, id)
+		if err != nil || record.Status != StatusPending {
+			return record, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Verify verifies a proof. A proof that fails to verify is reported in
+// the result, not as an error.
+func (c *Client) Verify(ctx context.Context, req VerifyRequest) (*VerifyResult, error) {
+	fields := withField(req.Options, "type", req.Type)
+	fields = withField(fields, "id", req.ID)
+	if req.IgnoreExpiry {
+		fields = withField(fields, "ignore-expiry", "true")
+	}
+	files := map[string]io.Reader{"proof": req.Proof, "metadata": req.Metadata, "verifying_key": req.VerifyingKey}
+	var result VerifyResult
+	if err := c.postForm(ctx, "/verify", fields, files, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// OpenAPI returns the OpenAPI document the server serves.
+func (c *Client) OpenAPI(ctx context.Context) ([]byte, error) {
+	var doc json.RawMessage
+	if err := c.do(ctx, http.MethodGet, "/openapi.json", "", nil, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// withField returns fields with name set to value, unless value is empty,
+// leaving fields itself unchanged.
+func withField(fields map[string]string, name, value string) map[string]string {
+	if value == "" {
+		return fields
+	}
+	out := make(map[string]string, len(fields)+1)
+	for k, v := range fields {
+		out[k] = v
+	}
+	out[name] = value
+	return out
+}
+
+// postForm posts a multipart form of fields and the non-nil files to path
+// and decodes the answer into v.
+func (c *Client) postForm(ctx context.Context, path string, fields map[string]string, files map[string]io.Reader, v any) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+	for name, f := range files {
+		if f == nil {
+			continue
+		}
+		part, err := form.CreateFormFile(name, name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, f); err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, path, form.FormDataContentType(), &body, v)
+}
+
+// do sends a request to path and decodes a successful answer into v, or
+// returns an *Error.
+func (c *Client) do(ctx context.Context, method, path, contentType string, body io.Reader, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		e := &Error{StatusCode: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(e)
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			e.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return e
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding %s %s answer: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package proofsclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+)
+
+const testVCF = `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer((&proofs.ProofServer{Dir: t.TempDir()}).Handler())
+	defer server.Close()
+	client := New(server.URL, "")
+
+	record, err := client.Generate(t.Context(), GenerateRequest{
+		Type:    "zygosity",
+		VCF:     strings.NewReader(testVCF),
+		Options: map[string]string{"position": "15:28365618"},
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if record.Status != StatusPending {
+		t.Errorf("new proof status = %s", record.Status)
+	}
+	record, err = client.Wait(t.Context(), record.ID, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if record.Status != StatusDone || len(record.Proof) == 0 || len(record.VerifyingKey) == 0 || len(record.Metadata) == 0 {
+		t.Fatalf("finished proof = %s %s, %d proof bytes", record.Status, record.Error, len(record.Proof))
+	}
+
+	result, err := client.Verify(t.Context(), VerifyRequest{ID: record.ID, Options: map[string]string{"position": "15:28365618"}})
+	if err != nil || !result.Verified {
+		t.Errorf("Verify by ID = %+v, %v", result, err)
+	}
+	result, err = client.Verify(t.Context(), VerifyRequest{
+		Proof:        strings.NewReader(string(record.Proof)),
+		VerifyingKey: strings.NewReader(string(record.VerifyingKey)),
+		Options:      map[string]string{"position": "15:28365619"},
+	})
+	if err != nil || result.Verified || result.ErrorKind != "verification" {
+		t.Errorf("Verify of an upload for another position = %+v, %v", result, err)
+	}
+
+	var apiErr *Error
+	if _, err := client.Cancel(t.Context(), record.ID); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("Cancel of a done proof = %v, want a 409 Error", err)
+	}
+	if _, err := client.Get(t.Context(), "0123456789abcdef0123456789abcdef"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Get of an unknown proof = %v, want a 404 Error", err)
+	}
+}
+
+func TestClientTenant(t *testing.T) {
+	srv := &proofs.ProofServer{Dir: t.TempDir(), Tenants: []proofs.Tenant{
+		{ID: "acme", APIKeys: []string{proofs.HashAPIKey("acme-key")}},
+	}}
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+
+	var apiErr *Error
+	if _, err := New(server.URL, "").Get(t.Context(), "0123456789abcdef0123456789abcdef"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Get without an API key = %v, want a 401 Error", err)
+	}
+	if _, err := New(server.URL, "acme-key").Get(t.Context(), "0123456789abcdef0123456789abcdef"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Get with the tenant's key = %v, want a 404 Error", err)
+	}
+}
+
+// TestOpenAPI checks that the document served lists the operations the
+// client calls.
+func TestOpenAPI(t *testing.T) {
+	server := httptest.NewServer((&proofs.ProofServer{Dir: t.TempDir(), Tenants: []proofs.Tenant{
+		{ID: "acme", APIKeys: []string{proofs.HashAPIKey("acme-key")}},
+	}}).Handler())
+	defer server.Close()
+
+	// The document is served without an API key.
+	data, err := New(server.URL, "").OpenAPI(t.Context())
+	if err != nil {
+		t.Fatalf("OpenAPI: %v", err)
+	}
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decoding the document: %v", err)
+	}
+	for path, methods := range map[string][]string{
+		"/proofs":       {"post"},
+		"/proofs/{id}":  {"get", "delete"},
+		"/verify":       {"post"},
+		"/openapi.json": {"get"},
+	} {
+		for _, method := range methods {
+			if _, ok := doc.Paths[path][method]; !ok {
+				t.Errorf("document does not describe %s %s", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// TestOpenAPISchemas checks that the client's types have the properties
+// of the document's schemas, and that the form fields the client sends are
+// ones the document describes.
+func TestOpenAPISchemas(t *testing.T) {
+	server := httptest.NewServer((&proofs.ProofServer{Dir: t.TempDir()}).Handler())
+	defer server.Close()
+	data, err := New(server.URL, "").OpenAPI(t.Context())
+	if err != nil {
+		t.Fatalf("OpenAPI: %v", err)
+	}
+	var doc struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decoding the document: %v", err)
+	}
+	properties := func(schema string) []string {
+		var names []string
+		for name := range doc.Components.Schemas[schema].Properties {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	for schema, v := range map[string]any{
+		"ProofRecord":   ProofRecord{},
+		"ProgressEvent": ProgressEvent{},
+		"VerifyResult":  VerifyResult{},
+		"Error":         Error{},
+	} {
+		var fields []string
+		typ := reflect.TypeOf(v)
+		for i := range typ.NumField() {
+			name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			if name != "" && name != "-" {
+				fields = append(fields, name)
+			}
+		}
+		slices.Sort(fields)
+		if want := properties(schema); !slices.Equal(fields, want) {
+			t.Errorf("%T has JSON fields %v, the %s schema %v", v, fields, schema, want)
+		}
+	}
+
+	// Record the form fields the client sends.
+	sent := map[string][]string{}
+	recorder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parsing the %s form: %v", r.URL.Path, err)
+		}
+		for name := range r.MultipartForm.Value {
+			sent[r.URL.Path] = append(sent[r.URL.Path], name)
+		}
+		for name := range r.MultipartForm.File {
+			sent[r.URL.Path] = append(sent[r.URL.Path], name)
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer recorder.Close()
+	client := New(recorder.URL, "")
+	file := func() *strings.Reader { return strings.NewReader("x") }
+	if _, err := client.Generate(t.Context(), GenerateRequest{Type: "zygosity", VCF: file(), Job: file(), Metadata: file()}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := client.Verify(t.Context(), VerifyRequest{
+		Type: "zygosity", ID: "0123456789abcdef0123456789abcdef", IgnoreExpiry: true,
+		Proof: file(), Metadata: file(), VerifyingKey: file(),
+	}); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	for path, schema := range map[string]string{"/proofs": "GenerateForm", "/verify": "VerifyForm"} {
+		if len(sent[path]) == 0 {
+			t.Errorf("client sent no form to %s", path)
+		}
+		want := properties(schema)
+		for _, field := range sent[path] {
+			if !slices.Contains(want, field) {
+				t.Errorf("the %s schema does not describe the %s field the client sends", schema, field)
+			}
+		}
+	}
+}
@@ -0,0 +1,6 @@
+// Package proofspb holds the gRPC service definition of proof generation
+// and verification, ProofService in proofs.proto, and the Go code
+// generated from it. The service is implemented by package proofsrpc.
+package proofspb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proofs.proto
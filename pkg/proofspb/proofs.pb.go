@@ -0,0 +1,1129 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: proofs.proto
+
+package proofspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ProofOptions are the options of a proof, named like the generate and
+// verify flags; each proof type reads the ones it needs.
+type ProofOptions struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Position string                 `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	Variant  string                 `protobuf:"bytes,2,opt,name=variant,proto3" json:"variant,omitempty"`
+	Gene     string                 `protobuf:"bytes,3,opt,name=gene,proto3" json:"gene,omitempty"`
+	Sample   string                 `protobuf:"bytes,4,opt,name=sample,proto3" json:"sample,omitempty"`
+	Target   int32                  `protobuf:"varint,5,opt,name=target,proto3" json:"target,omitempty"`
+	// panel lists the chrom:pos:ref:alt SNPs of dosage, threshold and panel
+	// proofs.
+	Panel []string `protobuf:"bytes,6,rep,name=panel,proto3" json:"panel,omitempty"`
+	// nonce is a verifier challenge bound into the proof.
+	Nonce string `protobuf:"bytes,7,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// valid_for_seconds binds an expiry this long after issuance into the
+	// proof.
+	ValidForSeconds int64 `protobuf:"varint,8,opt,name=valid_for_seconds,json=validForSeconds,proto3" json:"valid_for_seconds,omitempty"`
+	// public_inputs are the decimal values of the proof's leading public
+	// inputs; an empty value matches any.
+	PublicInputs []string `protobuf:"bytes,9,rep,name=public_inputs,json=publicInputs,proto3" json:"public_inputs,omitempty"`
+	// region is the chrom:start-end of region and sv proofs.
+	Region string `protobuf:"bytes,10,opt,name=region,proto3" json:"region,omitempty"`
+	// sv_type is the structural variant type, DEL or DUP, of sv proofs.
+	SvType string `protobuf:"bytes,11,opt,name=sv_type,json=svType,proto3" json:"sv_type,omitempty"`
+	// second_variant is the variant whose phase relative to variant a
+	// haplotype proof claims.
+	SecondVariant string `protobuf:"bytes,12,opt,name=second_variant,json=secondVariant,proto3" json:"second_variant,omitempty"`
+	// k is the minimum number of panel variants of threshold proofs; zero
+	// means 1 on generate and unchecked on verify.
+	K int32 `protobuf:"varint,13,opt,name=k,proto3" json:"k,omitempty"`
+	// locus is the repeat locus ID, HTT when empty, of repeat proofs.
+	Locus string `protobuf:"bytes,14,opt,name=locus,proto3" json:"locus,omitempty"`
+	// repeat_threshold is the pathogenic repeat count of repeat proofs; zero
+	// means the locus default.
+	RepeatThreshold int32 `protobuf:"varint,15,opt,name=repeat_threshold,json=repeatThreshold,proto3" json:"repeat_threshold,omitempty"`
+	// min_qual and min_depth are the QUAL and FORMAT/DP thresholds of
+	// zygosity, wildtype and somatic proofs.
+	MinQual       int32 `protobuf:"varint,16,opt,name=min_qual,json=minQual,proto3" json:"min_qual,omitempty"`
+	MinDepth      int32 `protobuf:"varint,17,opt,name=min_depth,json=minDepth,proto3" json:"min_depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProofOptions) Reset() {
+	*x = ProofOptions{}
+	mi := &file_proofs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProofOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProofOptions) ProtoMessage() {}
+
+func (x *ProofOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProofOptions.ProtoReflect.Descriptor instead.
+func (*ProofOptions) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProofOptions) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *ProofOptions) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *ProofOptions) GetGene() string {
+	if x != nil {
+		return x.Gene
+	}
+	return ""
+}
+
+func (x *ProofOptions) GetSample() string {
+	if x != nil {
+		return x.Sample
+	}
+	return ""
+}
+
+func (x *ProofOptions) GetTarget() int32 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *ProofOptions) GetPanel() []string {
+	if x != nil {
+		return x.Panel
+	}
+	return nil
+}
+
+func (x *ProofOptions) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *ProofOptions) GetValidForSeconds() int64 {
+	if x != nil {
+		return x.ValidForSeconds
+	}
+	return 0
+}
+
+func (x *ProofOptions) GetPublicInputs() []string {
+	if x != nil {
+		return x.PublicInputs
+	}
+	return nil
+}
+
+func (x *ProofOptions) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *ProofOptions) GetSvType() string {
+	if x != nil {
+		return x.SvType
+	}
+	return ""
+}
+
+func (x *ProofOptions) GetSecondVariant() string {
+	if x != nil {
+		return x.SecondVariant
+	}
+	return ""
+}
+
+func (x *ProofOptions) GetK() int32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *ProofOptions) GetLocus() string {
+	if x != nil {
+		return x.Locus
+	}
+	return ""
+}
+
+func (x *ProofOptions) GetRepeatThreshold() int32 {
+	if x != nil {
+		return x.RepeatThreshold
+	}
+	return 0
+}
+
+func (x *ProofOptions) GetMinQual() int32 {
+	if x != nil {
+		return x.MinQual
+	}
+	return 0
+}
+
+func (x *ProofOptions) GetMinDepth() int32 {
+	if x != nil {
+		return x.MinDepth
+	}
+	return 0
+}
+
+type GenerateProofRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Part:
+	//
+	//	*GenerateProofRequest_Header
+	//	*GenerateProofRequest_VcfChunk
+	Part          isGenerateProofRequest_Part `protobuf_oneof:"part"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateProofRequest) Reset() {
+	*x = GenerateProofRequest{}
+	mi := &file_proofs_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateProofRequest) ProtoMessage() {}
+
+func (x *GenerateProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateProofRequest.ProtoReflect.Descriptor instead.
+func (*GenerateProofRequest) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateProofRequest) GetPart() isGenerateProofRequest_Part {
+	if x != nil {
+		return x.Part
+	}
+	return nil
+}
+
+func (x *GenerateProofRequest) GetHeader() *GenerateProofHeader {
+	if x != nil {
+		if x, ok := x.Part.(*GenerateProofRequest_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *GenerateProofRequest) GetVcfChunk() []byte {
+	if x != nil {
+		if x, ok := x.Part.(*GenerateProofRequest_VcfChunk); ok {
+			return x.VcfChunk
+		}
+	}
+	return nil
+}
+
+type isGenerateProofRequest_Part interface {
+	isGenerateProofRequest_Part()
+}
+
+type GenerateProofRequest_Header struct {
+	Header *GenerateProofHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type GenerateProofRequest_VcfChunk struct {
+	VcfChunk []byte `protobuf:"bytes,2,opt,name=vcf_chunk,json=vcfChunk,proto3,oneof"`
+}
+
+func (*GenerateProofRequest_Header) isGenerateProofRequest_Part() {}
+
+func (*GenerateProofRequest_VcfChunk) isGenerateProofRequest_Part() {}
+
+type GenerateProofHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProofType     string                 `protobuf:"bytes,1,opt,name=proof_type,json=proofType,proto3" json:"proof_type,omitempty"`
+	Options       *ProofOptions          `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateProofHeader) Reset() {
+	*x = GenerateProofHeader{}
+	mi := &file_proofs_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateProofHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateProofHeader) ProtoMessage() {}
+
+func (x *GenerateProofHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateProofHeader.ProtoReflect.Descriptor instead.
+func (*GenerateProofHeader) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenerateProofHeader) GetProofType() string {
+	if x != nil {
+		return x.ProofType
+	}
+	return ""
+}
+
+func (x *GenerateProofHeader) GetOptions() *ProofOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type GenerateProofResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Proof    []byte                 `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
+	Metadata []byte                 `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// verifying_key is the key the proof verifies against: the server's key
+	// for the proof type, or that of the setup run for this proof.
+	VerifyingKey   []byte `protobuf:"bytes,3,opt,name=verifying_key,json=verifyingKey,proto3" json:"verifying_key,omitempty"`
+	KeyFingerprint string `protobuf:"bytes,4,opt,name=key_fingerprint,json=keyFingerprint,proto3" json:"key_fingerprint,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GenerateProofResponse) Reset() {
+	*x = GenerateProofResponse{}
+	mi := &file_proofs_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateProofResponse) ProtoMessage() {}
+
+func (x *GenerateProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateProofResponse.ProtoReflect.Descriptor instead.
+func (*GenerateProofResponse) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenerateProofResponse) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *GenerateProofResponse) GetMetadata() []byte {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *GenerateProofResponse) GetVerifyingKey() []byte {
+	if x != nil {
+		return x.VerifyingKey
+	}
+	return nil
+}
+
+func (x *GenerateProofResponse) GetKeyFingerprint() string {
+	if x != nil {
+		return x.KeyFingerprint
+	}
+	return ""
+}
+
+type VerifyProofRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// proof is a proof file or a proof envelope.
+	Proof    []byte `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
+	Metadata []byte `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// verifying_key, when empty, is the server's key for the proof type.
+	VerifyingKey []byte `protobuf:"bytes,3,opt,name=verifying_key,json=verifyingKey,proto3" json:"verifying_key,omitempty"`
+	// proof_type, when set, rejects proofs of other types.
+	ProofType     string        `protobuf:"bytes,4,opt,name=proof_type,json=proofType,proto3" json:"proof_type,omitempty"`
+	Options       *ProofOptions `protobuf:"bytes,5,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyProofRequest) Reset() {
+	*x = VerifyProofRequest{}
+	mi := &file_proofs_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyProofRequest) ProtoMessage() {}
+
+func (x *VerifyProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyProofRequest.ProtoReflect.Descriptor instead.
+func (*VerifyProofRequest) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VerifyProofRequest) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *VerifyProofRequest) GetMetadata() []byte {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *VerifyProofRequest) GetVerifyingKey() []byte {
+	if x != nil {
+		return x.VerifyingKey
+	}
+	return nil
+}
+
+func (x *VerifyProofRequest) GetProofType() string {
+	if x != nil {
+		return x.ProofType
+	}
+	return ""
+}
+
+func (x *VerifyProofRequest) GetOptions() *ProofOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type VerifyProofResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Valid          bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	ProofType      string                 `protobuf:"bytes,2,opt,name=proof_type,json=proofType,proto3" json:"proof_type,omitempty"`
+	CircuitId      string                 `protobuf:"bytes,3,opt,name=circuit_id,json=circuitId,proto3" json:"circuit_id,omitempty"`
+	PublicInputs   map[string]string      `protobuf:"bytes,4,rep,name=public_inputs,json=publicInputs,proto3" json:"public_inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	KeyFingerprint string                 `protobuf:"bytes,5,opt,name=key_fingerprint,json=keyFingerprint,proto3" json:"key_fingerprint,omitempty"`
+	// error says why a proof was rejected.
+	Error          string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	DurationMicros int64  `protobuf:"varint,7,opt,name=duration_micros,json=durationMicros,proto3" json:"duration_micros,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *VerifyProofResponse) Reset() {
+	*x = VerifyProofResponse{}
+	mi := &file_proofs_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyProofResponse) ProtoMessage() {}
+
+func (x *VerifyProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyProofResponse.ProtoReflect.Descriptor instead.
+func (*VerifyProofResponse) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VerifyProofResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyProofResponse) GetProofType() string {
+	if x != nil {
+		return x.ProofType
+	}
+	return ""
+}
+
+func (x *VerifyProofResponse) GetCircuitId() string {
+	if x != nil {
+		return x.CircuitId
+	}
+	return ""
+}
+
+func (x *VerifyProofResponse) GetPublicInputs() map[string]string {
+	if x != nil {
+		return x.PublicInputs
+	}
+	return nil
+}
+
+func (x *VerifyProofResponse) GetKeyFingerprint() string {
+	if x != nil {
+		return x.KeyFingerprint
+	}
+	return ""
+}
+
+func (x *VerifyProofResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *VerifyProofResponse) GetDurationMicros() int64 {
+	if x != nil {
+		return x.DurationMicros
+	}
+	return 0
+}
+
+type GetVerifyingKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProofType     string                 `protobuf:"bytes,1,opt,name=proof_type,json=proofType,proto3" json:"proof_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVerifyingKeyRequest) Reset() {
+	*x = GetVerifyingKeyRequest{}
+	mi := &file_proofs_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVerifyingKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVerifyingKeyRequest) ProtoMessage() {}
+
+func (x *GetVerifyingKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVerifyingKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetVerifyingKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetVerifyingKeyRequest) GetProofType() string {
+	if x != nil {
+		return x.ProofType
+	}
+	return ""
+}
+
+type GetVerifyingKeyResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	VerifyingKey   []byte                 `protobuf:"bytes,1,opt,name=verifying_key,json=verifyingKey,proto3" json:"verifying_key,omitempty"`
+	KeyFingerprint string                 `protobuf:"bytes,2,opt,name=key_fingerprint,json=keyFingerprint,proto3" json:"key_fingerprint,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetVerifyingKeyResponse) Reset() {
+	*x = GetVerifyingKeyResponse{}
+	mi := &file_proofs_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVerifyingKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVerifyingKeyResponse) ProtoMessage() {}
+
+func (x *GetVerifyingKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVerifyingKeyResponse.ProtoReflect.Descriptor instead.
+func (*GetVerifyingKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetVerifyingKeyResponse) GetVerifyingKey() []byte {
+	if x != nil {
+		return x.VerifyingKey
+	}
+	return nil
+}
+
+func (x *GetVerifyingKeyResponse) GetKeyFingerprint() string {
+	if x != nil {
+		return x.KeyFingerprint
+	}
+	return ""
+}
+
+type ListProofTypesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProofTypesRequest) Reset() {
+	*x = ListProofTypesRequest{}
+	mi := &file_proofs_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProofTypesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProofTypesRequest) ProtoMessage() {}
+
+func (x *ListProofTypesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProofTypesRequest.ProtoReflect.Descriptor instead.
+func (*ListProofTypesRequest) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{8}
+}
+
+type ListProofTypesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProofTypes    []*ProofType           `protobuf:"bytes,1,rep,name=proof_types,json=proofTypes,proto3" json:"proof_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProofTypesResponse) Reset() {
+	*x = ListProofTypesResponse{}
+	mi := &file_proofs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProofTypesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProofTypesResponse) ProtoMessage() {}
+
+func (x *ListProofTypesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProofTypesResponse.ProtoReflect.Descriptor instead.
+func (*ListProofTypesResponse) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListProofTypesResponse) GetProofTypes() []*ProofType {
+	if x != nil {
+		return x.ProofTypes
+	}
+	return nil
+}
+
+type ProofType struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	VcfFields     []string               `protobuf:"bytes,3,rep,name=vcf_fields,json=vcfFields,proto3" json:"vcf_fields,omitempty"`
+	PublicInputs  []*PublicInput         `protobuf:"bytes,4,rep,name=public_inputs,json=publicInputs,proto3" json:"public_inputs,omitempty"`
+	Standalone    bool                   `protobuf:"varint,5,opt,name=standalone,proto3" json:"standalone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProofType) Reset() {
+	*x = ProofType{}
+	mi := &file_proofs_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProofType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProofType) ProtoMessage() {}
+
+func (x *ProofType) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProofType.ProtoReflect.Descriptor instead.
+func (*ProofType) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProofType) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProofType) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ProofType) GetVcfFields() []string {
+	if x != nil {
+		return x.VcfFields
+	}
+	return nil
+}
+
+func (x *ProofType) GetPublicInputs() []*PublicInput {
+	if x != nil {
+		return x.PublicInputs
+	}
+	return nil
+}
+
+func (x *ProofType) GetStandalone() bool {
+	if x != nil {
+		return x.Standalone
+	}
+	return false
+}
+
+type PublicInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublicInput) Reset() {
+	*x = PublicInput{}
+	mi := &file_proofs_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublicInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicInput) ProtoMessage() {}
+
+func (x *PublicInput) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicInput.ProtoReflect.Descriptor instead.
+func (*PublicInput) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PublicInput) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PublicInput) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+var File_proofs_proto protoreflect.FileDescriptor
+
+var file_proofs_proto_rawDesc = string([]byte{
+	0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b,
+	0x76, 0x63, 0x66, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31, 0x22, 0xe4, 0x03, 0x0a, 0x0c,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x61, 0x72, 0x69,
+	0x61, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x65, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x67, 0x65, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x6e, 0x65, 0x6c, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x6e, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05,
+	0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x6e,
+	0x63, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x5f, 0x66, 0x6f, 0x72, 0x5f,
+	0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x46, 0x6f, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x23,
+	0x0a, 0x0d, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18,
+	0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x6e, 0x70,
+	0x75, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x73,
+	0x76, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x76,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x5f, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x6b,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x63,
+	0x75, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x63, 0x75, 0x73, 0x12,
+	0x29, 0x0a, 0x10, 0x72, 0x65, 0x70, 0x65, 0x61, 0x74, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68,
+	0x6f, 0x6c, 0x64, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x72, 0x65, 0x70, 0x65, 0x61,
+	0x74, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x69,
+	0x6e, 0x5f, 0x71, 0x75, 0x61, 0x6c, 0x18, 0x10, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6d, 0x69,
+	0x6e, 0x51, 0x75, 0x61, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x5f, 0x64, 0x65, 0x70,
+	0x74, 0x68, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x44, 0x65, 0x70,
+	0x74, 0x68, 0x22, 0x79, 0x0a, 0x14, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x06, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x76, 0x63, 0x66,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74,
+	0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x00, 0x52, 0x06,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x09, 0x76, 0x63, 0x66, 0x5f, 0x63, 0x68,
+	0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x08, 0x76, 0x63, 0x66,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x06, 0x0a, 0x04, 0x70, 0x61, 0x72, 0x74, 0x22, 0x69, 0x0a,
+	0x13, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x63, 0x66, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x97, 0x01, 0x0a, 0x15, 0x47, 0x65, 0x6e,
+	0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x69, 0x6e,
+	0x67, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x76, 0x65, 0x72,
+	0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x6b, 0x65, 0x79,
+	0x5f, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x6b, 0x65, 0x79, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x22, 0xbf, 0x01, 0x0a, 0x12, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12,
+	0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x23, 0x0a, 0x0d, 0x76,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0c, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79,
+	0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x33, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x76, 0x63, 0x66, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0xeb, 0x02, 0x0a, 0x13, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x49, 0x64,
+	0x12, 0x57, 0x0a, 0x0d, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x76, 0x63, 0x66, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x70, 0x75, 0x62,
+	0x6c, 0x69, 0x63, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x6b, 0x65, 0x79,
+	0x5f, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x6b, 0x65, 0x79, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0e, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x69, 0x63, 0x72, 0x6f,
+	0x73, 0x1a, 0x3f, 0x0a, 0x11, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x6e, 0x70, 0x75, 0x74,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0x37, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x69,
+	0x6e, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x79, 0x70, 0x65, 0x22, 0x67, 0x0a, 0x17, 0x47,
+	0x65, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79,
+	0x69, 0x6e, 0x67, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x76,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x6b,
+	0x65, 0x79, 0x5f, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6b, 0x65, 0x79, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70,
+	0x72, 0x69, 0x6e, 0x74, 0x22, 0x17, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x54, 0x79, 0x70, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x51, 0x0a,
+	0x16, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x79, 0x70, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76,
+	0x63, 0x66, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x79, 0x70, 0x65, 0x73,
+	0x22, 0xbf, 0x01, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x76, 0x63, 0x66, 0x5f, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x76, 0x63, 0x66, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x73, 0x12, 0x3d, 0x0a, 0x0d, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x69, 0x6e,
+	0x70, 0x75, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x66,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49,
+	0x6e, 0x70, 0x75, 0x74, 0x52, 0x0c, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x6e, 0x70, 0x75,
+	0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x6f, 0x6e, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x61, 0x6c, 0x6f,
+	0x6e, 0x65, 0x22, 0x43, 0x0a, 0x0b, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x6e, 0x70, 0x75,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x32, 0xf3, 0x02, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x58, 0x0a, 0x0d, 0x47, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x21, 0x2e, 0x76, 0x63, 0x66, 0x70,
+	0x72, 0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x76,
+	0x63, 0x66, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72,
+	0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x28, 0x01, 0x12, 0x50, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x12, 0x1f, 0x2e, 0x76, 0x63, 0x66, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x20, 0x2e, 0x76, 0x63, 0x66, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31,
+	0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66,
+	0x79, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x12, 0x23, 0x2e, 0x76, 0x63, 0x66, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x69,
+	0x6e, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x76,
+	0x63, 0x66, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x65,
+	0x72, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x59, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x54,
+	0x79, 0x70, 0x65, 0x73, 0x12, 0x22, 0x2e, 0x76, 0x63, 0x66, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x79, 0x70, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x76, 0x63, 0x66, 0x70, 0x72,
+	0x6f, 0x6f, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x54, 0x79, 0x70, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x32, 0x5a,
+	0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x6b, 0x67, 0x65,
+	0x6e, 0x6f, 0x6d, 0x69, 0x63, 0x73, 0x2f, 0x76, 0x63, 0x66, 0x2d, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x2d, 0x6d, 0x76, 0x70, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_proofs_proto_rawDescOnce sync.Once
+	file_proofs_proto_rawDescData []byte
+)
+
+func file_proofs_proto_rawDescGZIP() []byte {
+	file_proofs_proto_rawDescOnce.Do(func() {
+		file_proofs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proofs_proto_rawDesc), len(file_proofs_proto_rawDesc)))
+	})
+	return file_proofs_proto_rawDescData
+}
+
+var file_proofs_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proofs_proto_goTypes = []any{
+	(*ProofOptions)(nil),            // 0: vcfproof.v1.ProofOptions
+	(*GenerateProofRequest)(nil),    // 1: vcfproof.v1.GenerateProofRequest
+	(*GenerateProofHeader)(nil),     // 2: vcfproof.v1.GenerateProofHeader
+	(*GenerateProofResponse)(nil),   // 3: vcfproof.v1.GenerateProofResponse
+	(*VerifyProofRequest)(nil),      // 4: vcfproof.v1.VerifyProofRequest
+	(*VerifyProofResponse)(nil),     // 5: vcfproof.v1.VerifyProofResponse
+	(*GetVerifyingKeyRequest)(nil),  // 6: vcfproof.v1.GetVerifyingKeyRequest
+	(*GetVerifyingKeyResponse)(nil), // 7: vcfproof.v1.GetVerifyingKeyResponse
+	(*ListProofTypesRequest)(nil),   // 8: vcfproof.v1.ListProofTypesRequest
+	(*ListProofTypesResponse)(nil),  // 9: vcfproof.v1.ListProofTypesResponse
+	(*ProofType)(nil),               // 10: vcfproof.v1.ProofType
+	(*PublicInput)(nil),             // 11: vcfproof.v1.PublicInput
+	nil,                             // 12: vcfproof.v1.VerifyProofResponse.PublicInputsEntry
+}
+var file_proofs_proto_depIdxs = []int32{
+	2,  // 0: vcfproof.v1.GenerateProofRequest.header:type_name -> vcfproof.v1.GenerateProofHeader
+	0,  // 1: vcfproof.v1.GenerateProofHeader.options:type_name -> vcfproof.v1.ProofOptions
+	0,  // 2: vcfproof.v1.VerifyProofRequest.options:type_name -> vcfproof.v1.ProofOptions
+	12, // 3: vcfproof.v1.VerifyProofResponse.public_inputs:type_name -> vcfproof.v1.VerifyProofResponse.PublicInputsEntry
+	10, // 4: vcfproof.v1.ListProofTypesResponse.proof_types:type_name -> vcfproof.v1.ProofType
+	11, // 5: vcfproof.v1.ProofType.public_inputs:type_name -> vcfproof.v1.PublicInput
+	1,  // 6: vcfproof.v1.ProofService.GenerateProof:input_type -> vcfproof.v1.GenerateProofRequest
+	4,  // 7: vcfproof.v1.ProofService.VerifyProof:input_type -> vcfproof.v1.VerifyProofRequest
+	6,  // 8: vcfproof.v1.ProofService.GetVerifyingKey:input_type -> vcfproof.v1.GetVerifyingKeyRequest
+	8,  // 9: vcfproof.v1.ProofService.ListProofTypes:input_type -> vcfproof.v1.ListProofTypesRequest
+	3,  // 10: vcfproof.v1.ProofService.GenerateProof:output_type -> vcfproof.v1.GenerateProofResponse
+	5,  // 11: vcfproof.v1.ProofService.VerifyProof:output_type -> vcfproof.v1.VerifyProofResponse
+	7,  // 12: vcfproof.v1.ProofService.GetVerifyingKey:output_type -> vcfproof.v1.GetVerifyingKeyResponse
+	9,  // 13: vcfproof.v1.ProofService.ListProofTypes:output_type -> vcfproof.v1.ListProofTypesResponse
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_proofs_proto_init() }
+func file_proofs_proto_init() {
+	if File_proofs_proto != nil {
+		return
+	}
+	file_proofs_proto_msgTypes[1].OneofWrappers = []any{
+		(*GenerateProofRequest_Header)(nil),
+		(*GenerateProofRequest_VcfChunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proofs_proto_rawDesc), len(file_proofs_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proofs_proto_goTypes,
+		DependencyIndexes: file_proofs_proto_depIdxs,
+		MessageInfos:      file_proofs_proto_msgTypes,
+	}.Build()
+	File_proofs_proto = out.File
+	file_proofs_proto_goTypes = nil
+	file_proofs_proto_depIdxs = nil
+}
@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proofs.proto
+
+package proofspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ProofService_GenerateProof_FullMethodName   = "/vcfproof.v1.ProofService/GenerateProof"
+	ProofService_VerifyProof_FullMethodName     = "/vcfproof.v1.ProofService/VerifyProof"
+	ProofService_GetVerifyingKey_FullMethodName = "/vcfproof.v1.ProofService/GetVerifyingKey"
+	ProofService_ListProofTypes_FullMethodName  = "/vcfproof.v1.ProofService/ListProofTypes"
+)
+
+// ProofServiceClient is the client API for ProofService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ProofService generates and verifies proofs. The server reads the
+// genotypes in the VCFs it is sent and must be trusted with them; it keeps
+// them in memory and never writes them to disk.
+type ProofServiceClient interface {
+	// GenerateProof proves a claim about a VCF streamed in chunks: the first
+	// message carries the header, the rest the VCF's bytes in order.
+	GenerateProof(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[GenerateProofRequest, GenerateProofResponse], error)
+	// VerifyProof verifies a proof. A proof failing a check is answered with
+	// valid unset and the reason, not an error.
+	VerifyProof(ctx context.Context, in *VerifyProofRequest, opts ...grpc.CallOption) (*VerifyProofResponse, error)
+	// GetVerifyingKey returns the server's verifying key for a proof type.
+	GetVerifyingKey(ctx context.Context, in *GetVerifyingKeyRequest, opts ...grpc.CallOption) (*GetVerifyingKeyResponse, error)
+	// ListProofTypes lists the proof types the server generates and verifies.
+	ListProofTypes(ctx context.Context, in *ListProofTypesRequest, opts ...grpc.CallOption) (*ListProofTypesResponse, error)
+}
+
+type proofServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProofServiceClient(cc grpc.ClientConnInterface) ProofServiceClient {
+	return &proofServiceClient{cc}
+}
+
+func (c *proofServiceClient) GenerateProof(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[GenerateProofRequest, GenerateProofResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProofService_ServiceDesc.Streams[0], ProofService_GenerateProof_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GenerateProofRequest, GenerateProofResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProofService_GenerateProofClient = grpc.ClientStreamingClient[GenerateProofRequest, GenerateProofResponse]
+
+func (c *proofServiceClient) VerifyProof(ctx context.Context, in *VerifyProofRequest, opts ...grpc.CallOption) (*VerifyProofResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyProofResponse)
+	err := c.cc.Invoke(ctx, ProofService_VerifyProof_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proofServiceClient) GetVerifyingKey(ctx context.Context, in *GetVerifyingKeyRequest, opts ...grpc.CallOption) (*GetVerifyingKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVerifyingKeyResponse)
+	err := c.cc.Invoke(ctx, ProofService_GetVerifyingKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proofServiceClient) ListProofTypes(ctx context.Context, in *ListProofTypesRequest, opts ...grpc.CallOption) (*ListProofTypesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProofTypesResponse)
+	err := c.cc.Invoke(ctx, ProofService_ListProofTypes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProofServiceServer is the server API for ProofService service.
+// All implementations must embed UnimplementedProofServiceServer
+// for forward compatibility.
+//
+// ProofService generates and verifies proofs. The server reads the
+// genotypes in the VCFs it is sent and must be trusted with them; it keeps
+// them in memory and never writes them to disk.
+type ProofServiceServer interface {
+	// GenerateProof proves a claim about a VCF streamed in chunks: the first
+	// message carries the header, the rest the VCF's bytes in order.
+	GenerateProof(grpc.ClientStreamingServer[GenerateProofRequest, GenerateProofResponse]) error
+	// VerifyProof verifies a proof. A proof failing a check is answered with
+	// valid unset and the reason, not an error.
+	VerifyProof(context.Context, *VerifyProofRequest) (*VerifyProofResponse, error)
+	// GetVerifyingKey returns the server's verifying key for a proof type.
+	GetVerifyingKey(context.Context, *GetVerifyingKeyRequest) (*GetVerifyingKeyResponse, error)
+	// ListProofTypes lists the proof types the server generates and verifies.
+	ListProofTypes(context.Context, *ListProofTypesRequest) (*ListProofTypesResponse, error)
+	mustEmbedUnimplementedProofServiceServer()
+}
+
+// UnimplementedProofServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedProofServiceServer struct{}
+
+func (UnimplementedProofServiceServer) GenerateProof(grpc.ClientStreamingServer[GenerateProofRequest, GenerateProofResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method GenerateProof not implemented")
+}
+func (UnimplementedProofServiceServer) VerifyProof(context.Context, *VerifyProofRequest) (*VerifyProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyProof not implemented")
+}
+func (UnimplementedProofServiceServer) GetVerifyingKey(context.Context, *GetVerifyingKeyRequest) (*GetVerifyingKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVerifyingKey not implemented")
+}
+func (UnimplementedProofServiceServer) ListProofTypes(context.Context, *ListProofTypesRequest) (*ListProofTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProofTypes not implemented")
+}
+func (UnimplementedProofServiceServer) mustEmbedUnimplementedProofServiceServer() {}
+func (UnimplementedProofServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeProofServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProofServiceServer will
+// result in compilation errors.
+type UnsafeProofServiceServer interface {
+	mustEmbedUnimplementedProofServiceServer()
+}
+
+func RegisterProofServiceServer(s grpc.ServiceRegistrar, srv ProofServiceServer) {
+	// If the following call pancis, it indicates UnimplementedProofServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ProofService_ServiceDesc, srv)
+}
+
+func _ProofService_GenerateProof_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ProofServiceServer).GenerateProof(&grpc.GenericServerStream[GenerateProofRequest, GenerateProofResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProofService_GenerateProofServer = grpc.ClientStreamingServer[GenerateProofRequest, GenerateProofResponse]
+
+func _ProofService_VerifyProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProofServiceServer).VerifyProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProofService_VerifyProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProofServiceServer).VerifyProof(ctx, req.(*VerifyProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProofService_GetVerifyingKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVerifyingKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProofServiceServer).GetVerifyingKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProofService_GetVerifyingKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProofServiceServer).GetVerifyingKey(ctx, req.(*GetVerifyingKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProofService_ListProofTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProofTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProofServiceServer).ListProofTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProofService_ListProofTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProofServiceServer).ListProofTypes(ctx, req.(*ListProofTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ProofService_ServiceDesc is the grpc.ServiceDesc for ProofService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProofService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vcfproof.v1.ProofService",
+	HandlerType: (*ProofServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "VerifyProof",
+			Handler:    _ProofService_VerifyProof_Handler,
+		},
+		{
+			MethodName: "GetVerifyingKey",
+			Handler:    _ProofService_GetVerifyingKey_Handler,
+		},
+		{
+			MethodName: "ListProofTypes",
+			Handler:    _ProofService_ListProofTypes_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GenerateProof",
+			Handler:       _ProofService_GenerateProof_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proofs.proto",
+}
@@ -0,0 +1,296 @@
+// Package proofsrpc implements the gRPC ProofService of package proofspb
+// with the proofs package.
+package proofsrpc
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofspb"
+)
+
+// DefaultMaxVCFSize bounds the VCFs a Server accepts unless MaxVCFSize
+// says otherwise.
+const DefaultMaxVCFSize = 1 << 30
+
+// Server serves ProofService. VCFs, keys and proofs are held in memory,
+// so a VCF streamed to GenerateProof is never written to disk.
+type Server struct {
+	proofspb.UnimplementedProofServiceServer
+
+	// KeyDir, when set, holds keys from setup named <type>.pk and
+	// <type>.vk. Proofs of a type with keys there are proved with them,
+	// and verified with them when a request brings no verifying key.
+	KeyDir string
+	// Settings are the settings proofs start from; requests set their
+	// options. Keys are read through Settings.KeyStore, which NewServer
+	// sets.
+	Settings proofs.Settings
+	// MaxVCFSize bounds the VCFs GenerateProof accepts in bytes; zero
+	// means DefaultMaxVCFSize.
+	MaxVCFSize int64
+}
+
+// NewServer returns a Server proving and verifying with the keys in keyDir,
+// which may be empty, and reading them through a new KeyStore.
+func NewServer(keyDir string, settings proofs.Settings) *Server {
+	if settings.KeyStore == nil {
+		settings.KeyStore = proofs.NewKeyStore()
+	}
+	return &Server{KeyDir: keyDir, Settings: settings}
+}
+
+// GenerateProof reads the header and VCF chunks of a request stream and
+// proves the requested claim.
+func (s *Server) GenerateProof(stream proofspb.ProofService_GenerateProofServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := first.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the header")
+	}
+	t, ok := proofs.LookupProofType(header.ProofType)
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "unknown proof type %q", header.ProofType)
+	}
+
+	limit := s.MaxVCFSize
+	if limit <= 0 {
+		limit = DefaultMaxVCFSize
+	}
+	var vcf []byte
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		chunk := req.GetVcfChunk()
+		if req.GetHeader() != nil {
+			return status.Error(codes.InvalidArgument, "only the first message may carry the header")
+		}
+		if int64(len(vcf)+len(chunk)) > limit {
+			return status.Errorf(codes.ResourceExhausted, "the VCF is larger than %d bytes", limit)
+		}
+		vcf = append(vcf, chunk...)
+	}
+	if len(vcf) == 0 {
+		return status.Error(codes.InvalidArgument, "no VCF was sent")
+	}
+
+	opts, err := s.options(header.Options)
+	if err != nil {
+		return err
+	}
+	// Threshold proofs default to k = 1, as the generate flag does.
+	if header.Options.GetK() == 0 {
+		opts = append(opts, proofs.WithK(1))
+	}
+	keyPath := s.keyPath(t.Name, ".pk")
+	if keyPath != "" {
+		opts = append(opts, proofs.WithProvingKey(keyPath+".pk"))
+	}
+	data, err := proofs.GenerateBytes(stream.Context(), t.Name, vcf, opts...)
+	if err != nil {
+		return statusError(err)
+	}
+	resp := &proofspb.GenerateProofResponse{Proof: data.Proof, Metadata: data.Metadata, VerifyingKey: data.VerifyingKey}
+	if keyPath != "" {
+		if resp.VerifyingKey, err = os.ReadFile(keyPath + ".vk"); err != nil {
+			return status.Errorf(codes.Internal, "reading verifying key: %v", err)
+		}
+	}
+	resp.KeyFingerprint = fingerprint(resp.VerifyingKey)
+	return stream.SendAndClose(resp)
+}
+
+// VerifyProof verifies the proof in req.
+func (s *Server) VerifyProof(ctx context.Context, req *proofspb.VerifyProofRequest) (*proofspb.VerifyProofResponse, error) {
+	opts, err := s.options(req.Options)
+	if err != nil {
+		return nil, err
+	}
+	if req.ProofType != "" {
+		opts = append(opts, proofs.WithProofType(req.ProofType))
+	}
+	data := &proofs.ProofData{Proof: req.Proof, Metadata: req.Metadata, VerifyingKey: req.VerifyingKey}
+	if len(data.VerifyingKey) == 0 {
+		data.VerifyingKey = nil
+		proofType := req.ProofType
+		var meta proofs.ProofMetadata
+		if proofType == "" && json.Unmarshal(req.Metadata, &meta) == nil {
+			proofType = meta.ProofType
+		}
+		keyPath := ""
+		if t, ok := proofs.LookupProofType(proofType); ok {
+			keyPath = s.keyPath(t.Name, ".vk")
+		}
+		if keyPath == "" {
+			return nil, status.Error(codes.InvalidArgument, "no verifying key: send one as verifying_key")
+		}
+		opts = append(opts, proofs.WithVerifyingKey(keyPath+".vk"))
+	}
+
+	res, err := proofs.VerifyBytes(ctx, data, opts...)
+	if res == nil {
+		return nil, statusError(err)
+	}
+	resp := &proofspb.VerifyProofResponse{
+		Valid:          res.Valid,
+		ProofType:      res.ProofType,
+		CircuitId:      res.CircuitID,
+		PublicInputs:   make(map[string]string, len(res.PublicInputs)),
+		KeyFingerprint: res.KeyFingerprint,
+		DurationMicros: res.Duration.Microseconds(),
+	}
+	for name, v := range res.PublicInputs {
+		resp.PublicInputs[name] = fmt.Sprint(v)
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
+// GetVerifyingKey returns the verifying key in KeyDir for the requested
+// proof type.
+func (s *Server) GetVerifyingKey(ctx context.Context, req *proofspb.GetVerifyingKeyRequest) (*proofspb.GetVerifyingKeyResponse, error) {
+	t, ok := proofs.LookupProofType(req.ProofType)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown proof type %q", req.ProofType)
+	}
+	keyPath := s.keyPath(t.Name, ".vk")
+	if keyPath == "" {
+		return nil, status.Errorf(codes.NotFound, "the server has no keys for %s proofs", t.Name)
+	}
+	vk, err := os.ReadFile(keyPath + ".vk")
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "reading verifying key: %v", err)
+	}
+	return &proofspb.GetVerifyingKeyResponse{VerifyingKey: vk, KeyFingerprint: fingerprint(vk)}, nil
+}
+
+// ListProofTypes lists the registered proof types.
+func (s *Server) ListProofTypes(ctx context.Context, req *proofspb.ListProofTypesRequest) (*proofspb.ListProofTypesResponse, error) {
+	resp := &proofspb.ListProofTypesResponse{}
+	for _, t := range proofs.ProofTypes() {
+		pt := &proofspb.ProofType{Name: t.Name, Description: t.Description, VcfFields: t.Fields, Standalone: t.Standalone}
+		for _, in := range t.PublicInputs {
+			pt.PublicInputs = append(pt.PublicInputs, &proofspb.PublicInput{Name: in.Name, Description: in.Description})
+		}
+		resp.ProofTypes = append(resp.ProofTypes, pt)
+	}
+	return resp, nil
+}
+
+// keyPath returns the path in KeyDir, without extension, of the keys for
+// proofType, or "" when there is no key file with extension ext.
+func (s *Server) keyPath(proofType string, ext string) string {
+	if s.KeyDir == "" {
+		return ""
+	}
+	path := filepath.Join(s.KeyDir, proofType)
+	if _, err := os.Stat(path + ext); err != nil {
+		return ""
+	}
+	return path
+}
+
+// options returns the proofs Options of a request.
+func (s *Server) options(o *proofspb.ProofOptions) ([]proofs.Option, error) {
+	opts := []proofs.Option{proofs.WithSettings(s.Settings)}
+	if o == nil {
+		return opts, nil
+	}
+	opts = append(opts,
+		proofs.WithPosition(o.Position),
+		proofs.WithVariant(o.Variant),
+		proofs.WithGene(o.Gene),
+		proofs.WithSample(o.Sample),
+		proofs.WithTarget(int(o.Target)),
+		proofs.WithNonce(o.Nonce),
+		proofs.WithValidFor(time.Duration(o.ValidForSeconds)*time.Second),
+		proofs.WithSecondVariant(o.SecondVariant),
+		proofs.WithRegion(o.Region),
+		proofs.WithSVType(o.SvType),
+		proofs.WithK(int(o.K)),
+		proofs.WithLocus(cmp.Or(o.Locus, "HTT"), int(o.RepeatThreshold)),
+		proofs.WithQuality(proofs.QualityThresholds{MinQuality: int(o.MinQual), MinDepth: int(o.MinDepth)}),
+	)
+	if len(o.Panel) > 0 {
+		var panel []proofs.VariantSpec
+		for _, snp := range o.Panel {
+			spec, err := proofs.ParseVariantSpec(snp)
+			if err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			panel = append(panel, spec)
+		}
+		opts = append(opts, proofs.WithPanel(panel))
+	}
+	if len(o.PublicInputs) > 0 {
+		inputs := make([]*big.Int, len(o.PublicInputs))
+		for i, v := range o.PublicInputs {
+			if v == "" {
+				continue
+			}
+			n, ok := new(big.Int).SetString(v, 10)
+			if !ok {
+				return nil, status.Errorf(codes.InvalidArgument, "public input %d is not a decimal number: %q", i, v)
+			}
+			inputs[i] = n
+		}
+		opts = append(opts, proofs.WithPublicInputs(inputs...))
+	}
+	return opts, nil
+}
+
+// fingerprint is the KeyFingerprint of a key file holding key.
+func fingerprint(key []byte) string {
+	if key == nil {
+		return ""
+	}
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:])
+}
+
+// statusError converts an error of the proofs package to a gRPC status by
+// its kind.
+func statusError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	code := codes.Internal
+	switch proofs.ErrorKind(err) {
+	case proofs.ErrInvalidInput, proofs.ErrVCF:
+		code = codes.InvalidArgument
+	case proofs.ErrNotFound:
+		code = codes.FailedPrecondition
+	case proofs.ErrVerification:
+		code = codes.PermissionDenied
+	case proofs.ErrUnsupported:
+		code = codes.Unimplemented
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,186 @@
+package proofsrpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofs"
+	"github.com/zkgenomics/vcf-proof-mvp/pkg/proofspb"
+)
+
+const testVCF = `##fileformat=VCFv4.2
+##FORMAT=<ID=GT,Number=1,Type=String,Description="Genotype">
+#CHROM	POS	ID	REF	ALT	QUAL	FILTER	INFO	FORMAT	S1
+15	28365618	.	A	G	60	PASS	.	GT	1|0
+`
+
+func dial(t *testing.T, server *Server) proofspb.ProofServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	proofspb.RegisterProofServiceServer(s, server)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return proofspb.NewProofServiceClient(conn)
+}
+
+// generate streams testVCF to GenerateProof in chunks of chunkSize bytes.
+func generate(t *testing.T, client proofspb.ProofServiceClient, header *proofspb.GenerateProofHeader, chunkSize int) (*proofspb.GenerateProofResponse, error) {
+	t.Helper()
+	stream, err := client.GenerateProof(t.Context())
+	if err != nil {
+		t.Fatalf("GenerateProof: %v", err)
+	}
+	if err := stream.Send(&proofspb.GenerateProofRequest{Part: &proofspb.GenerateProofRequest_Header{Header: header}}); err != nil {
+		t.Fatalf("sending header: %v", err)
+	}
+	for vcf := []byte(testVCF); len(vcf) > 0; {
+		n := min(chunkSize, len(vcf))
+		if err := stream.Send(&proofspb.GenerateProofRequest{Part: &proofspb.GenerateProofRequest_VcfChunk{VcfChunk: vcf[:n]}}); err != nil {
+			break
+		}
+		vcf = vcf[n:]
+	}
+	return stream.CloseAndRecv()
+}
+
+func TestGenerateVerify(t *testing.T) {
+	client := dial(t, NewServer("", proofs.Settings{}))
+	header := &proofspb.GenerateProofHeader{
+		ProofType: "zygosity",
+		Options:   &proofspb.ProofOptions{Position: "15:28365618", Nonce: "challenge"},
+	}
+	resp, err := generate(t, client, header, 16)
+	if err != nil {
+		t.Fatalf("GenerateProof: %v", err)
+	}
+	if len(resp.Proof) == 0 || len(resp.VerifyingKey) == 0 || resp.KeyFingerprint == "" {
+		t.Fatalf("GenerateProof response lacks the proof or key: %v", resp)
+	}
+
+	verify := &proofspb.VerifyProofRequest{
+		Proof:        resp.Proof,
+		Metadata:     resp.Metadata,
+		VerifyingKey: resp.VerifyingKey,
+		Options:      &proofspb.ProofOptions{Position: "15:28365618", Nonce: "challenge"},
+	}
+	res, err := client.VerifyProof(t.Context(), verify)
+	if err != nil || !res.Valid {
+		t.Fatalf("VerifyProof = %v, %v", res, err)
+	}
+	if res.ProofType != "zygosity" || res.PublicInputs["nonce"] != "challenge" || res.KeyFingerprint != resp.KeyFingerprint {
+		t.Errorf("VerifyProof response = %v", res)
+	}
+
+	verify.Options.Nonce = "stale"
+	if res, err := client.VerifyProof(t.Context(), verify); err != nil || res.Valid || res.Error == "" {
+		t.Errorf("VerifyProof with a stale nonce = %v, %v", res, err)
+	}
+
+	verify.VerifyingKey = nil
+	if _, err := client.VerifyProof(t.Context(), verify); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("VerifyProof without a key: %v", err)
+	}
+	if _, err := generate(t, client, &proofspb.GenerateProofHeader{ProofType: "nope"}, 16); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GenerateProof of an unknown type: %v", err)
+	}
+}
+
+func TestGenerateProofLimitsVCFSize(t *testing.T) {
+	server := NewServer("", proofs.Settings{})
+	server.MaxVCFSize = 64
+	client := dial(t, server)
+	header := &proofspb.GenerateProofHeader{ProofType: "zygosity", Options: &proofspb.ProofOptions{Position: "15:28365618"}}
+	if _, err := generate(t, client, header, 32); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("GenerateProof of a VCF over the limit: %v", err)
+	}
+}
+
+func TestServerKeys(t *testing.T) {
+	dir := t.TempDir()
+	vcf := dir + "/input.vcf"
+	if err := os.WriteFile(vcf, []byte(testVCF), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := proofs.Generate(t.Context(), "zygosity", vcf, dir+"/zygosity", proofs.WithPosition("15:28365618")); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	client := dial(t, NewServer(dir, proofs.Settings{}))
+
+	key, err := client.GetVerifyingKey(t.Context(), &proofspb.GetVerifyingKeyRequest{ProofType: "zygosity"})
+	if err != nil {
+		t.Fatalf("GetVerifyingKey: %v", err)
+	}
+	if _, err := client.GetVerifyingKey(t.Context(), &proofspb.GetVerifyingKeyRequest{ProofType: "wildtype"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetVerifyingKey without keys: %v", err)
+	}
+
+	header := &proofspb.GenerateProofHeader{ProofType: "zygosity", Options: &proofspb.ProofOptions{Position: "15:28365618"}}
+	resp, err := generate(t, client, header, 1024)
+	if err != nil {
+		t.Fatalf("GenerateProof: %v", err)
+	}
+	if resp.KeyFingerprint != key.KeyFingerprint {
+		t.Errorf("proof made with key %s, not the server's %s", resp.KeyFingerprint, key.KeyFingerprint)
+	}
+	res, err := client.VerifyProof(t.Context(), &proofspb.VerifyProofRequest{
+		Proof:    resp.Proof,
+		Metadata: resp.Metadata,
+		Options:  &proofspb.ProofOptions{Position: "15:28365618"},
+	})
+	if err != nil || !res.Valid {
+		t.Errorf("VerifyProof with the server's key = %v, %v", res, err)
+	}
+
+	types, err := client.ListProofTypes(t.Context(), &proofspb.ListProofTypesRequest{})
+	if err != nil || len(types.ProofTypes) != len(proofs.ProofTypes()) {
+		t.Errorf("ListProofTypes = %v, %v", types, err)
+	}
+}
+
+func TestQualityOptions(t *testing.T) {
+	client := dial(t, NewServer("", proofs.Settings{}))
+	header := &proofspb.GenerateProofHeader{
+		ProofType: "zygosity",
+		Options:   &proofspb.ProofOptions{Position: "15:28365618", MinQual: 30},
+	}
+	resp, err := generate(t, client, header, 64)
+	if err != nil {
+		t.Fatalf("GenerateProof: %v", err)
+	}
+
+	verify := &proofspb.VerifyProofRequest{
+		Proof:        resp.Proof,
+		Metadata:     resp.Metadata,
+		VerifyingKey: resp.VerifyingKey,
+		Options:      &proofspb.ProofOptions{Position: "15:28365618", MinQual: 30},
+	}
+	if res, err := client.VerifyProof(t.Context(), verify); err != nil || !res.Valid {
+		t.Errorf("VerifyProof for the proof's min_qual = %v, %v", res, err)
+	}
+	verify.Options.MinQual = 50
+	if res, err := client.VerifyProof(t.Context(), verify); err != nil || res.Valid {
+		t.Errorf("VerifyProof for a higher min_qual = %v, %v", res, err)
+	}
+
+	header.Options.MinQual = 70
+	if _, err := generate(t, client, header, 64); err == nil {
+		t.Errorf("GenerateProof with min_qual above the call's QUAL should fail")
+	}
+}